@@ -0,0 +1,67 @@
+package api
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// publishInterval is the time between each state shared with clients.
+const publishInterval = 50 * time.Millisecond
+
+// Model plays a game while sharing its state with the server's clients. The moves they send are made by giving the
+// server to the game as one of its sources.
+type Model struct {
+	game   tea.Model
+	server *Server
+}
+
+func NewModel(game *marathon.Model, server *Server) *Model {
+	return &Model{
+		game:   *game,
+		server: server,
+	}
+}
+
+type publishTickMsg struct{}
+
+func publishTick() tea.Cmd {
+	return tea.Tick(publishInterval, func(_ time.Time) tea.Msg {
+		return publishTickMsg{}
+	})
+}
+
+// publish shares the current state of the game without blocking it. States which can't be taken are skipped, since
+// the game reports the error itself.
+func (m Model) publish() tea.Cmd {
+	state, err := m.game.(marathon.Model).State()
+	if err != nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = m.server.Publish(state)
+		return nil
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.game.Init(), publishTick())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(publishTickMsg); ok {
+		return m, tea.Batch(m.publish(), publishTick())
+	}
+
+	var cmd tea.Cmd
+	m.game, cmd = m.game.Update(msg)
+	if _, ok := msg.(marathon.GameOverMsg); ok {
+		return m, tea.Batch(cmd, m.publish())
+	}
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.game.View()
+}
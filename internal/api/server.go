@@ -0,0 +1,217 @@
+// Package api exposes a game over HTTP, so that other programs such as a browser frontend or stream overlay can mirror
+// it, and drive it if allowed.
+//
+// The state of the game is the JSON of tetris.State, served by GET /state and sent to WebSocket clients of /ws each
+// time it is published. Moves are made with POST /input, with a body such as {"move": "hard drop"} naming a
+// tetris.Move, if the server accepts control.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// inputBuffer is the number of moves which can wait to be made before more are refused.
+const inputBuffer = 64
+
+// Server shares the state of a game with HTTP and WebSocket clients, and takes moves from them.
+type Server struct {
+	control bool
+	inputs  chan tetris.Move
+	http    *http.Server
+	closed  chan struct{}
+	once    sync.Once
+
+	mu      sync.Mutex
+	state   []byte // the JSON of the latest state published (nil before the first)
+	clients map[*wsConn]struct{}
+}
+
+// InputRequest is the body of POST /input.
+type InputRequest struct {
+	Move string `json:"move"` // the name of a tetris.Move, such as "left"
+}
+
+// NewServer creates a server for a game. Moves are only accepted if control is true.
+func NewServer(control bool) *Server {
+	return &Server{
+		control: control,
+		inputs:  make(chan tetris.Move, inputBuffer),
+		closed:  make(chan struct{}),
+		clients: make(map[*wsConn]struct{}),
+	}
+}
+
+// Listen creates a server and starts serving it on the TCP address.
+func Listen(addr string, control bool) (*Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %q: %w", addr, err)
+	}
+	s := NewServer(control)
+	s.http = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = s.http.Serve(l)
+	}()
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", s.handleState)
+	mux.HandleFunc("/ws", s.handleWebSocket)
+	mux.HandleFunc("/input", s.handleInput)
+	return allowOrigins(mux)
+}
+
+// allowOrigins lets pages served from anywhere use the API, such as an overlay opened from a file.
+func allowOrigins(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.Lock()
+	state := s.state
+	s.mu.Unlock()
+	if state == nil {
+		http.Error(w, "the game has not started", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(state)
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.clients[conn] = struct{}{}
+	state := s.state
+	s.mu.Unlock()
+	if state != nil && conn.WriteText(state) != nil {
+		s.disconnect(conn)
+		return
+	}
+
+	go func() {
+		_ = conn.readLoop()
+		s.disconnect(conn)
+	}()
+}
+
+func (s *Server) handleInput(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.control {
+		http.Error(w, "the game is not accepting control", http.StatusForbidden)
+		return
+	}
+
+	var req InputRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&req)
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	move, err := tetris.ParseMove(req.Move)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case s.inputs <- move:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "too many moves waiting", http.StatusServiceUnavailable)
+	}
+}
+
+// Next waits for the next move sent by a client, so that the server can be a source of moves for the game. It only
+// returns false once the server is closed.
+func (s *Server) Next() (tetris.Move, bool) {
+	select {
+	case move := <-s.inputs:
+		return move, true
+	case <-s.closed:
+		return 0, false
+	}
+}
+
+// Publish shares the state with every client, disconnecting WebSocket clients which can no longer be reached.
+func (s *Server) Publish(state tetris.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = data
+	for conn := range s.clients {
+		if err := conn.WriteText(data); err != nil {
+			conn.Close()
+			delete(s.clients, conn)
+		}
+	}
+	return nil
+}
+
+// Clients returns the number of connected WebSocket clients.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+func (s *Server) disconnect(conn *wsConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn.Close()
+	delete(s.clients, conn)
+}
+
+// Close stops serving and disconnects every WebSocket client.
+func (s *Server) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	var err error
+	if s.http != nil {
+		err = s.http.Close()
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.clients {
+		conn.Close()
+		delete(s.clients, conn)
+	}
+	return err
+}
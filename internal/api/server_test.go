@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestServer_Input(t *testing.T) {
+	tt := []struct {
+		name     string
+		control  bool
+		method   string
+		body     string
+		wantCode int
+	}{
+		{"move", true, http.MethodPost, `{"move": "hard drop"}`, http.StatusNoContent},
+		{"not in control", false, http.MethodPost, `{"move": "left"}`, http.StatusForbidden},
+		{"unknown move", true, http.MethodPost, `{"move": "teleport"}`, http.StatusBadRequest},
+		{"invalid body", true, http.MethodPost, `left`, http.StatusBadRequest},
+		{"wrong method", true, http.MethodGet, ``, http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewServer(tc.control)
+			rec := httptest.NewRecorder()
+
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(tc.method, "/input", strings.NewReader(tc.body)))
+
+			if rec.Code != tc.wantCode {
+				t.Fatalf("want status %d, got %d", tc.wantCode, rec.Code)
+			}
+			if tc.wantCode != http.StatusNoContent {
+				return
+			}
+			move, ok := s.Next()
+			if !ok || move != tetris.MoveHardDrop {
+				t.Errorf("want %v, got %v", tetris.MoveHardDrop, move)
+			}
+		})
+	}
+}
+
+func TestServer_State(t *testing.T) {
+	s := NewServer(false)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Before publishing: want status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	err := s.Publish(tetris.State{Version: tetris.StateVersion, Queue: "TIO"})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("After publishing: want status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var state tetris.State
+	err = json.Unmarshal(rec.Body.Bytes(), &state)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if state.Queue != "TIO" {
+		t.Errorf("want queue %q, got %q", "TIO", state.Queue)
+	}
+}
+
+func TestServer_WebSocket(t *testing.T) {
+	s := NewServer(false)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// The key and accept value are the example given by RFC 6455.
+	_, err = io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: tetrigo\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("want status %d, got %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("want accept %q, got %q", "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+	}
+
+	for s.Clients() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	err = s.Publish(tetris.State{Version: tetris.StateVersion, Hold: "T"})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	var header [2]byte
+	_, err = io.ReadFull(r, header[:])
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if header[0] != 0x80|opText {
+		t.Errorf("want a final text frame, got %#x", header[0])
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, err = io.ReadFull(r, ext[:])
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	var state tetris.State
+	err = json.Unmarshal(payload, &state)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if state.Hold != "T" {
+		t.Errorf("want hold %q, got %q", "T", state.Hold)
+	}
+}
@@ -0,0 +1,80 @@
+// Package audio plays the game's sound effects and background music.
+//
+// Sounds are synthesised when the package is loaded, so there are no files to ship, and played through PulseAudio
+// (or PipeWire's PulseAudio server). When no audio server can be reached the game is silent.
+package audio
+
+import (
+	"github.com/jfreymuth/pulse"
+)
+
+type Sound int
+
+const (
+	Lock     Sound = iota // a tetrimino locked without clearing lines
+	Clear                 // one or more lines were cleared
+	LevelUp               // the level increased
+	GameOver              // the game finished, either by topping out or reaching its goal
+	Danger                // the stack rose close to the top of the matrix
+)
+
+// sounds are the samples played for each sound.
+var sounds = map[Sound][]float32{
+	Lock:     render(effectVolume, note{110, 0.05}),
+	Clear:    render(effectVolume, note{523.25, 0.06}, note{783.99, 0.09}),
+	LevelUp:  render(effectVolume, note{523.25, 0.07}, note{659.25, 0.07}, note{783.99, 0.07}, note{1046.5, 0.12}),
+	GameOver: render(effectVolume, note{392, 0.15}, note{329.63, 0.15}, note{261.63, 0.15}, note{196, 0.3}),
+	Danger:   render(effectVolume, note{880, 0.08}, note{698.46, 0.08}, note{880, 0.08}, note{698.46, 0.08}),
+}
+
+// Player plays sounds on the default audio device. A nil Player is silent, so games can play sounds without
+// checking whether audio is available.
+type Player struct {
+	sound  bool
+	mixer  *mixer
+	client *pulse.Client
+	stream *pulse.PlaybackStream
+}
+
+// Open connects to the audio server, starting the background music if music is set.
+// Nil is returned if neither sound nor music is set, or if there is no audio server to connect to.
+func Open(sound, music bool) *Player {
+	if !sound && !music {
+		return nil
+	}
+
+	client, err := pulse.NewClient(pulse.ClientApplicationName("tetrigo"))
+	if err != nil {
+		return nil
+	}
+	p := &Player{sound: sound, mixer: newMixer(), client: client}
+	if music {
+		p.mixer.loop(render(musicVolume, korobeiniki...))
+	}
+
+	p.stream, err = client.NewPlayback(pulse.Float32Reader(p.mixer.Read),
+		pulse.PlaybackSampleRate(sampleRate), pulse.PlaybackLatency(0.05))
+	if err != nil {
+		client.Close()
+		return nil
+	}
+	p.stream.Start()
+	return p
+}
+
+// Play starts the sound, mixing it with any that are already playing.
+func (p *Player) Play(s Sound) {
+	if p == nil || !p.sound {
+		return
+	}
+	p.mixer.play(sounds[s])
+}
+
+// Close stops all sounds and disconnects from the audio server.
+func (p *Player) Close() {
+	if p == nil {
+		return
+	}
+	p.stream.Close()
+	p.client.Close()
+}
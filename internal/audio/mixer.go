@@ -0,0 +1,61 @@
+package audio
+
+import "sync"
+
+// mixer combines the sounds being played into a single stream. It is read by the audio server's goroutine while
+// sounds are added by the game's.
+type mixer struct {
+	mu     sync.Mutex
+	voices [][]float32 // the remaining samples of each sound being played
+	music  []float32   // samples looped for as long as the stream is played (nil for no music)
+	pos    int         // position in the music
+}
+
+func newMixer() *mixer {
+	return &mixer{}
+}
+
+func (m *mixer) play(samples []float32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.voices = append(m.voices, samples)
+}
+
+func (m *mixer) loop(samples []float32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.music = samples
+	m.pos = 0
+}
+
+// Read fills out with the sum of the sounds being played, and silence where there are none.
+// It never runs out, so the stream plays until it is closed.
+func (m *mixer) Read(out []float32) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range out {
+		out[i] = 0
+		if len(m.music) > 0 {
+			out[i] = m.music[m.pos]
+			m.pos = (m.pos + 1) % len(m.music)
+		}
+	}
+
+	remaining := m.voices[:0]
+	for _, v := range m.voices {
+		n := min(len(v), len(out))
+		for i := 0; i < n; i++ {
+			out[i] += v[i]
+		}
+		if n < len(v) {
+			remaining = append(remaining, v[n:])
+		}
+	}
+	m.voices = remaining
+
+	for i := range out {
+		out[i] = max(-1, min(1, out[i]))
+	}
+	return len(out), nil
+}
@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMixer_Read(t *testing.T) {
+	tt := []struct {
+		name     string
+		voices   [][]float32
+		music    []float32
+		reads    int
+		expected [][]float32
+	}{
+		{
+			name:     "silence",
+			reads:    1,
+			expected: [][]float32{{0, 0, 0}},
+		},
+		{
+			name:     "sound ends",
+			voices:   [][]float32{{0.1, 0.2, 0.3, 0.4}},
+			reads:    2,
+			expected: [][]float32{{0.1, 0.2, 0.3}, {0.4, 0, 0}},
+		},
+		{
+			name:     "sounds are mixed",
+			voices:   [][]float32{{0.25, 0.25}, {0.5}},
+			reads:    1,
+			expected: [][]float32{{0.75, 0.25, 0}},
+		},
+		{
+			name:     "clipped",
+			voices:   [][]float32{{0.75, -0.75}, {0.75, -0.75}},
+			reads:    1,
+			expected: [][]float32{{1, -1, 0}},
+		},
+		{
+			name:     "music loops",
+			voices:   [][]float32{{0.5}},
+			music:    []float32{0.125, 0.25},
+			reads:    2,
+			expected: [][]float32{{0.625, 0.25, 0.125}, {0.25, 0.125, 0.25}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMixer()
+			for _, v := range tc.voices {
+				m.play(v)
+			}
+			if tc.music != nil {
+				m.loop(tc.music)
+			}
+
+			for i := 0; i < tc.reads; i++ {
+				out := make([]float32, 3)
+				n, err := m.Read(out)
+				if err != nil {
+					t.Fatalf("expected nil, got error: %v", err)
+				}
+				if n != len(out) {
+					t.Errorf("Read %d: want %d samples, got %d", i, len(out), n)
+				}
+				if !reflect.DeepEqual(out, tc.expected[i]) {
+					t.Errorf("Read %d: want %v, got %v", i, tc.expected[i], out)
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	samples := render(0.5, note{440, 0.1}, note{0, 0.05})
+	if want := int(0.1*sampleRate) + int(0.05*sampleRate); len(samples) != want {
+		t.Fatalf("want %d samples, got %d", want, len(samples))
+	}
+	if samples[0] != 0.5 {
+		t.Errorf("First sample: want 0.5, got %v", samples[0])
+	}
+	for i, s := range samples {
+		if s > 0.5 || s < -0.5 {
+			t.Fatalf("Sample %d: %v is louder than the volume", i, s)
+		}
+		if i >= int(0.1*sampleRate) && s != 0 {
+			t.Fatalf("Sample %d: want silence during the rest, got %v", i, s)
+		}
+	}
+}
+
+func TestPlayer_Nil(t *testing.T) {
+	var p *Player
+	p.Play(Clear)
+	p.Close()
+
+	if p := Open(false, false); p != nil {
+		t.Errorf("want nil player when sound and music are off, got %v", p)
+	}
+}
@@ -0,0 +1,55 @@
+package audio
+
+import "math"
+
+const sampleRate = 44100
+
+const (
+	effectVolume = 0.2
+	musicVolume  = 0.06
+)
+
+// note is a tone lasting for a number of seconds. A frequency of 0 is a rest.
+type note struct {
+	freq     float64
+	duration float64
+}
+
+// render synthesises the notes one after another as a square wave, like the sound chips of old consoles.
+// Each note fades out so that repeated notes can be told apart, and so that no clicks are heard between them.
+func render(volume float32, notes ...note) []float32 {
+	var samples []float32
+	for _, n := range notes {
+		count := int(n.duration * sampleRate)
+		for i := 0; i < count; i++ {
+			if n.freq == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			phase := math.Mod(float64(i)*n.freq/sampleRate, 1)
+			value := volume
+			if phase >= 0.5 {
+				value = -volume
+			}
+			envelope := 1 - float32(i)/float32(count)
+			samples = append(samples, value*envelope)
+		}
+	}
+	return samples
+}
+
+// beat is the length of a quarter note in the background music.
+const beat = 0.4
+
+// korobeiniki is the Russian folk song made famous by Tetris, played as the background music.
+var korobeiniki = []note{
+	{659.25, beat}, {493.88, beat / 2}, {523.25, beat / 2}, {587.33, beat}, {523.25, beat / 2}, {493.88, beat / 2},
+	{440, beat}, {440, beat / 2}, {523.25, beat / 2}, {659.25, beat}, {587.33, beat / 2}, {523.25, beat / 2},
+	{493.88, beat * 1.5}, {523.25, beat / 2}, {587.33, beat}, {659.25, beat},
+	{523.25, beat}, {440, beat}, {440, beat * 2},
+
+	{0, beat / 2}, {587.33, beat}, {698.46, beat / 2}, {880, beat}, {783.99, beat / 2}, {698.46, beat / 2},
+	{659.25, beat * 1.5}, {523.25, beat / 2}, {659.25, beat}, {587.33, beat / 2}, {523.25, beat / 2},
+	{493.88, beat}, {493.88, beat / 2}, {523.25, beat / 2}, {587.33, beat}, {659.25, beat},
+	{523.25, beat}, {440, beat}, {440, beat}, {0, beat},
+}
@@ -0,0 +1,178 @@
+// Package bench measures the speed of the engine by having the built-in bot play games without drawing them, timing
+// each part of the game it plays so that work on the engine can be compared over time.
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/bot"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// The names of the parts of the engine timed while the games are played.
+const (
+	PhaseBot  = "Bot"  // planning each placement
+	PhaseMove = "Move" // moving and rotating tetriminos
+	PhaseLock = "Lock" // dropping and locking tetriminos, clearing lines, scoring and dealing the next
+)
+
+// phases index the time spent in each part of the engine, in the same order as phaseNames.
+const (
+	phaseBot = iota
+	phaseMove
+	phaseLock
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{PhaseBot, PhaseMove, PhaseLock}
+
+// defaultPieces is the most tetriminos each game is played for unless another number is chosen.
+const defaultPieces = 1000
+
+// Options are the games played.
+type Options struct {
+	Games      int    // games played, one after another
+	Pieces     int    // most tetriminos each game is played for, if the bot doesn't top out first (0 for 1000)
+	Seed       int64  // seed of the first game, with each game after it using the next
+	Randomizer string // name of the randomizer tetriminos are dealt by (see tetris.RandomizerNames, empty for "bag")
+	Width      int    // columns of the matrix (0 for the default)
+	Height     int    // visible rows of the matrix (0 for the default)
+}
+
+// Phase is the time spent in a part of the engine, over every game.
+type Phase struct {
+	Name  string
+	Time  time.Duration
+	Calls int
+}
+
+// Result is how quickly the games were played.
+type Result struct {
+	Games   int
+	Pieces  int
+	Lines   uint
+	Elapsed time.Duration
+
+	// Allocs and Bytes are the heap allocations made while playing, and the bytes they allocated.
+	Allocs uint64
+	Bytes  uint64
+
+	// Phases are the parts of the engine, the slowest first. Time spent between them, such as on timing itself, isn't
+	// counted, so they add up to less than Elapsed.
+	Phases []Phase
+}
+
+// PiecesPerSecond returns the tetriminos placed for each second spent playing.
+func (r *Result) PiecesPerSecond() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Pieces) / r.Elapsed.Seconds()
+}
+
+// AllocsPerPiece returns the heap allocations made for each tetrimino placed.
+func (r *Result) AllocsPerPiece() float64 {
+	if r.Pieces == 0 {
+		return 0
+	}
+	return float64(r.Allocs) / float64(r.Pieces)
+}
+
+// Run plays the games with the bot, one after another, and returns how quickly they were played.
+func Run(opts Options) (*Result, error) {
+	if opts.Games < 1 {
+		return nil, fmt.Errorf("invalid games %d: must be at least 1", opts.Games)
+	}
+	if opts.Pieces == 0 {
+		opts.Pieces = defaultPieces
+	}
+	if opts.Randomizer == "" {
+		opts.Randomizer = "bag"
+	}
+	if opts.Width == 0 {
+		opts.Width = tetris.DefaultWidth
+	}
+	if opts.Height == 0 {
+		opts.Height = tetris.DefaultHeight
+	}
+
+	r := &Result{Games: opts.Games}
+	var t timer
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < opts.Games; i++ {
+		pieces, lines, err := play(&opts, opts.Seed+int64(i), &t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to play game %d: %w", i+1, err)
+		}
+		r.Pieces += pieces
+		r.Lines += lines
+	}
+	r.Elapsed = time.Since(start)
+	runtime.ReadMemStats(&after)
+	r.Allocs = after.Mallocs - before.Mallocs
+	r.Bytes = after.TotalAlloc - before.TotalAlloc
+
+	for i, name := range phaseNames {
+		r.Phases = append(r.Phases, Phase{Name: name, Time: t.total[i], Calls: t.calls[i]})
+	}
+	slices.SortStableFunc(r.Phases, func(a, b Phase) int { return cmp.Compare(b.Time, a.Time) })
+	return r, nil
+}
+
+// timer adds up the time spent in each phase.
+type timer struct {
+	total [phaseCount]time.Duration
+	calls [phaseCount]int
+}
+
+// add adds the time since start to the phase.
+func (t *timer) add(phase int, start time.Time) {
+	t.total[phase] += time.Since(start)
+	t.calls[phase]++
+}
+
+// play plays a game with the bot until it tops out or has placed the most tetriminos, returning how many it placed
+// and the lines they cleared. The game is played by the engine's simulation, without checking its invariants.
+func play(opts *Options, seed int64, t *timer) (int, uint, error) {
+	randomizer, err := tetris.NewRandomizer(opts.Randomizer, seed)
+	if err != nil {
+		return 0, 0, err
+	}
+	game := tetris.NewSimulation(tetris.SimulationOptions{
+		Matrix:     tetris.NewMatrix(opts.Width, opts.Height),
+		Randomizer: randomizer,
+		Unchecked:  true,
+	})
+	player := bot.New(bot.DefaultWeights)
+
+	var pieces int
+	for ; pieces < opts.Pieces && !game.GameOver(); pieces++ {
+		start := time.Now()
+		actions, err := player.Plan(game.Matrix(), game.Current())
+		t.add(phaseBot, start)
+		if err != nil {
+			return pieces, 0, fmt.Errorf("failed to plan placement: %w", err)
+		}
+
+		for _, a := range actions {
+			phase := phaseMove
+			if a == bot.ActionHardDrop {
+				phase = phaseLock
+			}
+			start = time.Now()
+			game.Apply(a.Move())
+			t.add(phase, start)
+		}
+		if report := game.Report(); !report.OK() {
+			return pieces, 0, fmt.Errorf("failed to place tetrimino: %v", report.Violations[0])
+		}
+	}
+	return pieces, game.State().Scoring.Lines, nil
+}
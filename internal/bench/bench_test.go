@@ -0,0 +1,54 @@
+package bench
+
+import (
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	r, err := Run(Options{Games: 2, Pieces: 50, Seed: 42})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if r.Games != 2 || r.Pieces == 0 || r.Pieces > 100 {
+		t.Errorf("want up to 100 tetriminos placed over 2 games, got %d over %d", r.Pieces, r.Games)
+	}
+	if r.PiecesPerSecond() <= 0 {
+		t.Errorf("want a positive rate, got %v", r.PiecesPerSecond())
+	}
+	if len(r.Phases) != phaseCount {
+		t.Fatalf("want %d phases, got %d", phaseCount, len(r.Phases))
+	}
+	for i, p := range r.Phases {
+		if p.Calls == 0 {
+			t.Errorf("Phase %s: want calls, got none", p.Name)
+		}
+		if i > 0 && p.Time > r.Phases[i-1].Time {
+			t.Errorf("want phases slowest first, got %s after %s", p.Name, r.Phases[i-1].Name)
+		}
+	}
+}
+
+func TestRun_Invalid(t *testing.T) {
+	tt := map[string]Options{
+		"no games":   {Games: 0},
+		"randomizer": {Games: 1, Randomizer: "shuffled"},
+	}
+
+	for name, opts := range tt {
+		t.Run(name, func(t *testing.T) {
+			_, err := Run(opts)
+			if err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, err := Run(Options{Games: 1, Pieces: 100, Seed: int64(i)})
+		if err != nil {
+			b.Fatalf("expected nil, got error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package bot provides a computer player which picks placements using a weighted board heuristic.
+package bot
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Action is a single input performed by the bot.
+type Action int
+
+const (
+	ActionLeft Action = iota
+	ActionRight
+	ActionClockwise
+	ActionHardDrop
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionLeft:
+		return "left"
+	case ActionRight:
+		return "right"
+	case ActionClockwise:
+		return "clockwise"
+	case ActionHardDrop:
+		return "hard drop"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// Move returns the gameplay move the action is made with.
+func (a Action) Move() tetris.Move {
+	switch a {
+	case ActionLeft:
+		return tetris.MoveLeft
+	case ActionRight:
+		return tetris.MoveRight
+	case ActionClockwise:
+		return tetris.MoveClockwise
+	}
+	return tetris.MoveHardDrop
+}
+
+// Weights for each feature of the board after a placement. Higher scores are preferred.
+type Weights struct {
+	AggregateHeight float64
+	CompleteLines   float64
+	Holes           float64
+	Bumpiness       float64
+}
+
+// DefaultWeights are tuned for steady survival rather than maximising score.
+var DefaultWeights = Weights{
+	AggregateHeight: -0.510066,
+	CompleteLines:   0.760666,
+	Holes:           -0.35663,
+	Bumpiness:       -0.184483,
+}
+
+type Bot struct {
+	weights Weights
+}
+
+func New(weights Weights) *Bot {
+	return &Bot{weights: weights}
+}
+
+// Plan returns the actions needed to place the tetrimino in the best position found.
+// The tetrimino is expected to already be in the matrix, as it is during play. Neither are modified.
+func (b *Bot) Plan(matrix tetris.Matrix, tet *tetris.Tetrimino) ([]Action, error) {
+	best, _, found, err := b.best(matrix, tet)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []Action{ActionHardDrop}, nil
+	}
+	return best, nil
+}
+
+// best returns the actions for the best placement found and its score, or false if the tetrimino cannot be placed.
+func (b *Bot) best(matrix tetris.Matrix, tet *tetris.Tetrimino) ([]Action, float64, bool, error) {
+	var best []Action
+	var bestScore float64
+	found := false
+
+	rotations := 4
+	if tet.Value == 'O' {
+		rotations = 1
+	}
+
+	for r := 0; r < rotations; r++ {
+		for col := 0; col < len(matrix[0]); col++ {
+			score, dx, ok, err := b.evaluate(matrix, tet, r, col)
+			if err != nil {
+				return nil, 0, false, fmt.Errorf("failed to evaluate placement (rotation %d, column %d): %w", r, col, err)
+			}
+			if !ok || (found && score <= bestScore) {
+				continue
+			}
+			best = actionsFor(r, dx)
+			bestScore = score
+			found = true
+		}
+	}
+	return best, bestScore, found, nil
+}
+
+// evaluate simulates rotating the tetrimino clockwise the given number of times, moving it to the given column and dropping it.
+// It returns the columns it moved once rotated, since rotating moves it too, or false if the rotation or the column
+// cannot be reached.
+func (b *Bot) evaluate(matrix tetris.Matrix, tet *tetris.Tetrimino, rotations, col int) (float64, int, bool, error) {
+	matrix = matrix.Clone()
+	tet = tet.Copy()
+
+	for i := 0; i < rotations; i++ {
+		rotated, err := tet.Rotate(&matrix, true)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if !rotated {
+			return 0, 0, false, nil
+		}
+	}
+	dx := col - tet.Pos.X
+
+	for tet.Pos.X != col {
+		var moved bool
+		var err error
+		if tet.Pos.X > col {
+			moved, err = tet.MoveLeft(&matrix)
+		} else {
+			moved, err = tet.MoveRight(&matrix)
+		}
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if !moved {
+			return 0, 0, false, nil
+		}
+	}
+
+	for tet.CanMoveDown(matrix) {
+		_, err := tet.MoveDown(&matrix)
+		if err != nil {
+			return 0, 0, false, err
+		}
+	}
+
+	rowsBefore := filledRows(matrix)
+	matrix.RemoveCompletedLines(tet)
+	lines := rowsBefore - filledRows(matrix)
+
+	return b.score(matrix, lines), dx, true, nil
+}
+
+func (b *Bot) score(matrix tetris.Matrix, lines int) float64 {
+	heights := columnHeights(matrix)
+
+	var aggregate, bumpiness int
+	for i, h := range heights {
+		aggregate += h
+		if i > 0 {
+			bumpiness += abs(h - heights[i-1])
+		}
+	}
+
+	return b.weights.AggregateHeight*float64(aggregate) +
+		b.weights.CompleteLines*float64(lines) +
+		b.weights.Holes*float64(holes(matrix, heights)) +
+		b.weights.Bumpiness*float64(bumpiness)
+}
+
+func actionsFor(rotations, dx int) []Action {
+	actions := make([]Action, 0, rotations+abs(dx)+1)
+	for i := 0; i < rotations; i++ {
+		actions = append(actions, ActionClockwise)
+	}
+	for ; dx < 0; dx++ {
+		actions = append(actions, ActionLeft)
+	}
+	for ; dx > 0; dx-- {
+		actions = append(actions, ActionRight)
+	}
+	return append(actions, ActionHardDrop)
+}
+
+// columnHeights returns the height of the highest filled cell in each column.
+func columnHeights(matrix tetris.Matrix) []int {
+	heights := make([]int, len(matrix[0]))
+	for col := range matrix[0] {
+		for row := range matrix {
+			if !matrix.IsCellEmpty(row, col) {
+				heights[col] = len(matrix) - row
+				break
+			}
+		}
+	}
+	return heights
+}
+
+// holes counts the empty cells which have a filled cell somewhere above them.
+func holes(matrix tetris.Matrix, heights []int) int {
+	var count int
+	for col, h := range heights {
+		for row := len(matrix) - h; row < len(matrix); row++ {
+			if matrix.IsCellEmpty(row, col) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func filledRows(matrix tetris.Matrix) int {
+	var count int
+	for row := range matrix {
+		for col := range matrix[row] {
+			if !matrix.IsCellEmpty(row, col) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestBot_Plan(t *testing.T) {
+	tt := []struct {
+		name     string
+		matrix   tetris.Matrix
+		tet      tetris.Tetrimino
+		expected []Action
+	}{
+		{
+			name: "I fills gap at right edge",
+			matrix: func() tetris.Matrix {
+				m := tetris.NewDefaultMatrix()
+				for row := 36; row < 40; row++ {
+					for col := 0; col < 9; col++ {
+						m[row][col] = 'X'
+					}
+				}
+				return m
+			}(),
+			tet: tetris.Tetrimino{
+				Value: 'I',
+				Cells: [][]bool{
+					{true, true, true, true},
+				},
+				Pos:            tetris.Coordinate{X: 3, Y: 19},
+				RotationCoords: tetris.RotationCoords['I'],
+			},
+			// Turning the I upright moves it two columns to the right, leaving four to the gap.
+			expected: []Action{ActionClockwise, ActionRight, ActionRight, ActionRight, ActionRight, ActionHardDrop},
+		},
+		{
+			name:   "O stays flat on empty matrix",
+			matrix: tetris.NewDefaultMatrix(),
+			tet: tetris.Tetrimino{
+				Value: 'O',
+				Cells: [][]bool{
+					{true, true},
+					{true, true},
+				},
+				Pos:            tetris.Coordinate{X: 4, Y: 18},
+				RotationCoords: tetris.RotationCoords['O'],
+			},
+			expected: []Action{ActionLeft, ActionLeft, ActionLeft, ActionLeft, ActionHardDrop},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.matrix.AddTetrimino(&tc.tet)
+			if err != nil {
+				t.Fatalf("failed to add tetrimino: %v", err)
+			}
+			before := tc.matrix.Clone()
+
+			actions, err := New(DefaultWeights).Plan(tc.matrix, &tc.tet)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+
+			if !reflect.DeepEqual(actions, tc.expected) {
+				t.Errorf("Actions: want %v, got %v", tc.expected, actions)
+			}
+			if !tc.matrix.Equal(before) {
+				t.Errorf("Matrix: expected to be unchanged")
+			}
+		})
+	}
+}
+
+func TestPerfectClear(t *testing.T) {
+	tt := []struct {
+		name     string
+		width    int
+		values   string
+		hold     bool
+		expected bool
+	}{
+		{"upright I", 10, "IIIIIIIIII", false, true},
+		{"opener", 10, "LJZSITOTZI", false, true},
+		{"opener with hold", 10, "ZTSIJLOSLZT", true, true},
+		{"opener needing hold", 10, "ZTSIJLOSLZ", false, false},
+		{"no opener", 10, "ZSJTOILOSZI", true, false},
+		{"held S", 10, "SIIIIIIIIII", true, true},
+		{"S without hold", 10, "SIIIIIIIIII", false, false},
+		{"too few", 10, "IIIIIIIII", false, false},
+		{"too wide", 40, "IIIIIIIIII", false, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := PerfectClear(tc.width, []byte(tc.values), tc.hold); actual != tc.expected {
+				t.Errorf("want %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
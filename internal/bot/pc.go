@@ -0,0 +1,211 @@
+package bot
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// pcLines are the lines an opener clears to make a perfect clear.
+const pcLines = 4
+
+// shape is a rotation of a tetrimino, as the columns of its cells counted from its left and their rows counted up
+// from its bottom.
+type shape struct {
+	cells  [4][2]int
+	width  int
+	height int
+	bottom []int // the lowest row of the shape in each of its columns
+}
+
+// shapes are the distinct rotations of each tetrimino, by value.
+var shapes = func() map[byte][]shape {
+	shapes := make(map[byte][]shape, len(tetris.Tetriminos))
+	for _, t := range tetris.Tetriminos {
+		cells := t.Cells
+		seen := make(map[[4][2]int]bool)
+		for r := 0; r < 4; r++ {
+			s := newShape(cells)
+			if !seen[s.cells] {
+				seen[s.cells] = true
+				shapes[t.Value] = append(shapes[t.Value], s)
+			}
+			cells = rotateCells(cells)
+		}
+	}
+	return shapes
+}()
+
+func newShape(cells [][]bool) shape {
+	s := shape{width: len(cells[0]), height: len(cells), bottom: make([]int, len(cells[0]))}
+	for col := range s.bottom {
+		s.bottom[col] = s.height
+	}
+	var i int
+	for row := range cells {
+		for col, filled := range cells[row] {
+			if !filled {
+				continue
+			}
+			up := len(cells) - 1 - row
+			s.cells[i] = [2]int{col, up}
+			s.bottom[col] = min(s.bottom[col], up)
+			i++
+		}
+	}
+	return s
+}
+
+// rotateCells returns the cells of a tetrimino turned clockwise.
+func rotateCells(cells [][]bool) [][]bool {
+	rotated := make([][]bool, len(cells[0]))
+	for row := range rotated {
+		rotated[row] = make([]bool, len(cells))
+		for col := range rotated[row] {
+			rotated[row][col] = cells[len(cells)-1-col][row]
+		}
+	}
+	return rotated
+}
+
+// pcState is a position in the search for a perfect clear: the rows of the board from the bottom, with a bit set for
+// each filled cell, the lines left to clear, the tetriminos of the queue dealt so far, and the one held.
+type pcState struct {
+	rows  [pcLines]uint32
+	lines int
+	dealt int
+	held  byte
+}
+
+// PerfectClear reports whether the tetriminos with the values, dealt in order, can clear the bottom four lines of an
+// empty matrix of the width without leaving a cell, as in a perfect-clear opener. Tetriminos are only hard dropped,
+// from any rotation and column, so placements which need soft drops or spins aren't found. With hold, the tetrimino
+// in play can be swapped for the one held, or the next if none is. Matrices wider than 32 columns are not searched.
+func PerfectClear(width int, values []byte, hold bool) bool {
+	if width < 1 || width > 32 {
+		return false
+	}
+	s := &pcSearch{width: width, values: values, hold: hold, failed: make(map[pcState]bool)}
+	return s.solve(pcState{lines: pcLines})
+}
+
+// pcSearch searches for a perfect clear, remembering the states it couldn't find one from.
+type pcSearch struct {
+	width  int
+	values []byte
+	hold   bool
+	failed map[pcState]bool
+}
+
+func (s *pcSearch) solve(state pcState) bool {
+	if state.lines == 0 {
+		return true
+	}
+	if s.failed[state] {
+		return false
+	}
+	if state.dealt < len(s.values) {
+		current := s.values[state.dealt]
+		next := state
+		next.dealt++
+		if s.place(next, current) {
+			return true
+		}
+		if s.hold {
+			switch {
+			case state.held != 0 && state.held != current:
+				next.held = current
+				if s.place(next, state.held) {
+					return true
+				}
+			case state.held == 0 && state.dealt+1 < len(s.values):
+				next.dealt++
+				next.held = current
+				if s.place(next, s.values[state.dealt+1]) {
+					return true
+				}
+			}
+		}
+	}
+	s.failed[state] = true
+	return false
+}
+
+// place tries each placement of the tetrimino with the value, searching on from each which leaves the board clearable.
+func (s *pcSearch) place(state pcState, value byte) bool {
+	var heights [32]int
+	for col := 0; col < s.width; col++ {
+		for row := state.lines - 1; row >= 0; row-- {
+			if state.rows[row]&(1<<col) != 0 {
+				heights[col] = row + 1
+				break
+			}
+		}
+	}
+
+	for _, sh := range shapes[value] {
+		for x := 0; x+sh.width <= s.width; x++ {
+			// The tetrimino lands on the first cell it meets in any of its columns.
+			var y int
+			for col := 0; col < sh.width; col++ {
+				y = max(y, heights[x+col]-sh.bottom[col])
+			}
+			if y+sh.height > state.lines {
+				continue
+			}
+			next := state
+			for _, c := range sh.cells {
+				next.rows[y+c[1]] |= 1 << (x + c[0])
+			}
+			next.clearLines(s.width)
+			if next.clearable(s.width) && s.solve(next) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clearLines removes the completed rows, moving those above down.
+func (state *pcState) clearLines(width int) {
+	full := uint32(1)<<width - 1
+	var rows [pcLines]uint32
+	var kept int
+	for row := 0; row < state.lines; row++ {
+		if state.rows[row] == full {
+			continue
+		}
+		rows[kept] = state.rows[row]
+		kept++
+	}
+	state.rows, state.lines = rows, kept
+}
+
+// clearable reports whether the empty cells could still be filled by hard dropping tetriminos: none are covered, and
+// each run of columns between full ones has a multiple of four cells left.
+func (state *pcState) clearable(width int) bool {
+	var empty int
+	for col := 0; col <= width; col++ {
+		var height int
+		if col < width {
+			for row := state.lines - 1; row >= 0; row-- {
+				if state.rows[row]&(1<<col) != 0 {
+					height = row + 1
+					break
+				}
+			}
+			for row := 0; row < height; row++ {
+				if state.rows[row]&(1<<col) == 0 {
+					return false
+				}
+			}
+		}
+		if col == width || height == state.lines {
+			if empty%4 != 0 {
+				return false
+			}
+			empty = 0
+			continue
+		}
+		empty += state.lines - height
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Review describes how a placement compares to the best one the bot could find, for coaching a player.
+type Review struct {
+	HolesCreated int     // empty cells newly covered by the placement (negative if holes were uncovered)
+	Score        float64 // score of the board after the placement
+	BestScore    float64 // score of the board after the best placement found
+
+	// Better are the actions for a placement which scores higher, or nil if no better placement was found.
+	Better []Action
+}
+
+// Review compares where a tetrimino was placed with where the bot would have placed it.
+// The matrix should contain the tetrimino as it was before being moved, as it is during play, and placed should be the
+// same tetrimino where it locked. Neither are modified.
+func (b *Bot) Review(matrix tetris.Matrix, tet *tetris.Tetrimino, placed *tetris.Tetrimino) (*Review, error) {
+	best, bestScore, found, err := b.best(matrix, tet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find best placement: %w", err)
+	}
+
+	matrix = matrix.Clone()
+	err = matrix.RemoveTetrimino(tet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove tetrimino: %w", err)
+	}
+	holesBefore := holes(matrix, columnHeights(matrix))
+
+	placed = placed.Copy()
+	err = matrix.AddTetrimino(placed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add placed tetrimino: %w", err)
+	}
+	rowsBefore := filledRows(matrix)
+	matrix.RemoveCompletedLines(placed)
+	lines := rowsBefore - filledRows(matrix)
+
+	r := &Review{
+		HolesCreated: holes(matrix, columnHeights(matrix)) - holesBefore,
+		Score:        b.score(matrix, lines),
+		BestScore:    bestScore,
+	}
+	if found && bestScore > r.Score {
+		r.Better = best
+	}
+	return r, nil
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestBot_Review(t *testing.T) {
+	rightWell := func() tetris.Matrix {
+		m := tetris.NewDefaultMatrix()
+		for row := 36; row < 40; row++ {
+			for col := 0; col < 9; col++ {
+				m[row][col] = 'X'
+			}
+		}
+		return m
+	}
+	spawnI := tetris.Tetrimino{
+		Value:          'I',
+		Cells:          [][]bool{{true, true, true, true}},
+		Pos:            tetris.Coordinate{X: 3, Y: 19},
+		RotationCoords: tetris.RotationCoords['I'],
+	}
+
+	tt := []struct {
+		name                 string
+		matrix               tetris.Matrix
+		tet                  tetris.Tetrimino
+		placed               tetris.Tetrimino
+		expectedHolesCreated int
+		expectsBetter        bool
+	}{
+		{
+			name:   "best placement",
+			matrix: rightWell(),
+			tet:    spawnI,
+			placed: tetris.Tetrimino{
+				Value: 'I',
+				Cells: [][]bool{{true}, {true}, {true}, {true}},
+				Pos:   tetris.Coordinate{X: 9, Y: 36},
+			},
+			expectedHolesCreated: 0,
+			expectsBetter:        false,
+		},
+		{
+			name:   "worse placement",
+			matrix: rightWell(),
+			tet:    spawnI,
+			placed: tetris.Tetrimino{
+				Value: 'I',
+				Cells: [][]bool{{true, true, true, true}},
+				Pos:   tetris.Coordinate{X: 0, Y: 35},
+			},
+			expectedHolesCreated: 0,
+			expectsBetter:        true,
+		},
+		{
+			name: "covers a gap",
+			matrix: func() tetris.Matrix {
+				m := tetris.NewDefaultMatrix()
+				copy(m[39], []byte{0, 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'})
+				return m
+			}(),
+			tet: tetris.Tetrimino{
+				Value:          'O',
+				Cells:          [][]bool{{true, true}, {true, true}},
+				Pos:            tetris.Coordinate{X: 4, Y: 18},
+				RotationCoords: tetris.RotationCoords['O'],
+			},
+			placed: tetris.Tetrimino{
+				Value: 'O',
+				Cells: [][]bool{{true, true}, {true, true}},
+				Pos:   tetris.Coordinate{X: 0, Y: 37},
+			},
+			expectedHolesCreated: 1,
+			expectsBetter:        true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.matrix.AddTetrimino(&tc.tet)
+			if err != nil {
+				t.Fatalf("failed to add tetrimino: %v", err)
+			}
+			before := tc.matrix.Clone()
+
+			r, err := New(DefaultWeights).Review(tc.matrix, &tc.tet, &tc.placed)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+
+			if r.HolesCreated != tc.expectedHolesCreated {
+				t.Errorf("HolesCreated: want %d, got %d", tc.expectedHolesCreated, r.HolesCreated)
+			}
+			if (r.Better != nil) != tc.expectsBetter {
+				t.Errorf("Better: want %t, got %v", tc.expectsBetter, r.Better)
+			}
+			if !tc.matrix.Equal(before) {
+				t.Errorf("Matrix: expected to be unchanged")
+			}
+		})
+	}
+}
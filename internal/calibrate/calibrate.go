@@ -0,0 +1,165 @@
+// Package calibrate implements a wizard which measures how the player's terminal repeats held keys and how quickly
+// the player reacts, and recommends handling settings to suit them.
+package calibrate
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Measurements are the results of the wizard's tests.
+type Measurements struct {
+	// Presses are the times of each press the terminal sent while a key was held, from the first.
+	Presses []time.Time
+	// Reactions are how long the player took to press a key after each prompt.
+	Reactions []time.Duration
+}
+
+// RepeatDelay returns how long the terminal waited before it started repeating the held key, and false if it never did.
+func (m Measurements) RepeatDelay() (time.Duration, bool) {
+	if len(m.Presses) < 2 {
+		return 0, false
+	}
+	return m.Presses[1].Sub(m.Presses[0]), true
+}
+
+// RepeatInterval returns the typical time between the presses the terminal repeated, and false if it repeated too
+// few to tell.
+func (m Measurements) RepeatInterval() (time.Duration, bool) {
+	if len(m.Presses) < 3 {
+		return 0, false
+	}
+	gaps := make([]time.Duration, 0, len(m.Presses)-2)
+	for i := 2; i < len(m.Presses); i++ {
+		gaps = append(gaps, m.Presses[i].Sub(m.Presses[i-1]))
+	}
+	return median(gaps), true
+}
+
+// Reaction returns the player's typical reaction time, and false if it was not measured.
+func (m Measurements) Reaction() (time.Duration, bool) {
+	if len(m.Reactions) == 0 {
+		return 0, false
+	}
+	return median(m.Reactions), true
+}
+
+// median returns the middle value of the durations, which mustn't be empty. It is less affected than the mean by the
+// odd slow reaction or delayed repeat.
+func median(ds []time.Duration) time.Duration {
+	sorted := slices.Clone(ds)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Recommendation is the handling recommended for a player, and the reasons for it.
+type Recommendation struct {
+	Handling tetris.Handling
+	Reasons  []string
+}
+
+const (
+	// fastReaction and slowReaction divide players into those who react quickly, typically, and slowly.
+	fastReaction = 250 * time.Millisecond
+	slowReaction = 350 * time.Millisecond
+
+	// maxWindow is the longest repeat window recommended. Two taps of the same key closer together than the window
+	// are taken to be the key held, so a longer one would swallow quick taps.
+	maxWindow = 250 * time.Millisecond
+	// windowMargin allows for repeats arriving a little late.
+	windowMargin = 20 * time.Millisecond
+)
+
+// Recommend returns the handling which suits the measurements.
+//
+// Terminals only report presses, so a held key is recognised by the terminal repeating it. The repeat window has to
+// be long enough to span the gaps between repeats, and DAS can be no shorter than the terminal takes to start
+// repeating. Players who react quickly are given a shorter DAS, a faster ARR and a faster soft drop.
+func Recommend(m Measurements) Recommendation {
+	var r Recommendation
+
+	reaction, ok := m.Reaction()
+	switch {
+	case !ok:
+		reaction = (fastReaction + slowReaction) / 2
+		r.Reasons = append(r.Reasons, "Your reaction time was not measured, so typical settings are recommended.")
+	default:
+		r.Reasons = append(r.Reasons, fmt.Sprintf("You reacted in %s on average.", roundMs(reaction)))
+	}
+
+	var das time.Duration
+	switch {
+	case reaction < fastReaction:
+		das, r.Handling.ARR, r.Handling.SoftDrop = 100*time.Millisecond, 0, 20
+		r.Reasons = append(r.Reasons, "That is quick, so held keys start moving sooner and go straight to the wall, "+
+			"and soft drops are twice as fast.")
+	case reaction < slowReaction:
+		das, r.Handling.ARR, r.Handling.SoftDrop = 133*time.Millisecond, 33*time.Millisecond, tetris.DefaultSoftDropFactor
+		r.Reasons = append(r.Reasons, "Held keys start moving a little sooner than usual, a cell at a time.")
+	default:
+		das, r.Handling.ARR, r.Handling.SoftDrop = 167*time.Millisecond, 50*time.Millisecond, 6
+		r.Reasons = append(r.Reasons, "Held keys wait a little longer before moving, so taps are never mistaken for "+
+			"holds, and soft drops are gentler.")
+	}
+
+	delay, ok := m.RepeatDelay()
+	if !ok {
+		r.Handling = tetris.Handling{SoftDrop: r.Handling.SoftDrop}
+		r.Reasons = append(r.Reasons, "Your terminal did not repeat the held key, so handling is left off and every "+
+			"press moves the tetrimino once.")
+		return r
+	}
+	interval, ok := m.RepeatInterval()
+	if !ok {
+		interval = delay
+	}
+
+	if delay+windowMargin <= maxWindow {
+		r.Handling.Window = max(delay, 2*interval) + windowMargin
+		if das < delay {
+			das = delay
+			r.Reasons = append(r.Reasons, fmt.Sprintf("Your terminal waits %s before repeating a held key, so DAS "+
+				"can be no shorter.", roundMs(delay)))
+		}
+	} else {
+		// The first repeat comes too late to be told apart from a second tap, so the hold is only recognised
+		// from then on, and DAS is counted from it.
+		r.Handling.Window = min(2*interval+windowMargin, maxWindow)
+		das = max(das-delay, interval)
+		r.Reasons = append(r.Reasons, fmt.Sprintf("Your terminal waits %s before repeating a held key, which is "+
+			"longer than DAS, so DAS is shortened to make up for it. Lowering the key repeat delay in your system's "+
+			"keyboard settings would make held keys more responsive.", roundMs(delay)))
+	}
+	r.Handling.DAS = roundUp(das)
+	r.Handling.Window = roundUp(r.Handling.Window)
+	r.Reasons = append(r.Reasons, fmt.Sprintf("Your terminal repeats held keys every %s, so presses of the same key "+
+		"within %s are taken to mean that it is still held.", roundMs(interval), r.Handling.Window))
+	return r
+}
+
+// roundUp rounds the duration up to the nearest 10ms, so the settings saved are round numbers.
+func roundUp(d time.Duration) time.Duration {
+	step := 10 * time.Millisecond
+	return (d + step - 1) / step * step
+}
+
+// roundMs rounds the duration to the nearest millisecond for display.
+func roundMs(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+// Apply writes the recommended handling to the config.
+func (r Recommendation) Apply(cfg *config.Config) {
+	cfg.DAS = uint(r.Handling.DAS / time.Millisecond)
+	cfg.ARR = uint(r.Handling.ARR / time.Millisecond)
+	cfg.Window = uint(r.Handling.Window / time.Millisecond)
+	cfg.SoftDrop = r.Handling.SoftDrop
+}
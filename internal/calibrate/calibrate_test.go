@@ -0,0 +1,92 @@
+package calibrate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestRecommend(t *testing.T) {
+	ms := time.Millisecond
+	// held returns the presses a terminal sends for a held key, repeating it after the delay and then every interval.
+	held := func(delay, interval time.Duration, repeats int) []time.Time {
+		at := time.Unix(0, 0)
+		presses := []time.Time{at}
+		for i := 0; i < repeats; i++ {
+			if i == 0 {
+				at = at.Add(delay)
+			} else {
+				at = at.Add(interval)
+			}
+			presses = append(presses, at)
+		}
+		return presses
+	}
+
+	tt := []struct {
+		name         string
+		measurements Measurements
+		expected     tetris.Handling
+	}{
+		{
+			name:         "no repeats",
+			measurements: Measurements{Presses: held(0, 0, 0), Reactions: []time.Duration{200 * ms, 220 * ms}},
+			expected:     tetris.Handling{SoftDrop: 20},
+		},
+		{
+			name:         "not measured",
+			measurements: Measurements{Presses: held(50*ms, 30*ms, 20)},
+			expected:     tetris.Handling{DAS: 140 * ms, ARR: 33 * ms, Window: 80 * ms, SoftDrop: 10},
+		},
+		{
+			name:         "fast reactions",
+			measurements: Measurements{Presses: held(50*ms, 30*ms, 20), Reactions: []time.Duration{180 * ms, 900 * ms, 200 * ms}},
+			expected:     tetris.Handling{DAS: 100 * ms, Window: 80 * ms, SoftDrop: 20},
+		},
+		{
+			name:         "slow reactions",
+			measurements: Measurements{Presses: held(50*ms, 30*ms, 20), Reactions: []time.Duration{400 * ms, 380 * ms}},
+			expected:     tetris.Handling{DAS: 170 * ms, ARR: 50 * ms, Window: 80 * ms, SoftDrop: 6},
+		},
+		{
+			name:         "DAS no shorter than the repeat delay",
+			measurements: Measurements{Presses: held(200*ms, 30*ms, 20), Reactions: []time.Duration{200 * ms}},
+			expected:     tetris.Handling{DAS: 200 * ms, Window: 220 * ms, SoftDrop: 20},
+		},
+		{
+			name:         "long repeat delay",
+			measurements: Measurements{Presses: held(500*ms, 33*ms, 20), Reactions: []time.Duration{300 * ms}},
+			expected:     tetris.Handling{DAS: 40 * ms, ARR: 33 * ms, Window: 90 * ms, SoftDrop: 10},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Recommend(tc.measurements)
+			if r.Handling != tc.expected {
+				t.Errorf("Handling: want %+v, got %+v", tc.expected, r.Handling)
+			}
+			if len(r.Reasons) == 0 {
+				t.Errorf("Reasons: want at least one, got none")
+			}
+		})
+	}
+}
+
+func TestRecommendation_Apply(t *testing.T) {
+	r := Recommendation{Handling: tetris.Handling{
+		DAS:      140 * time.Millisecond,
+		ARR:      33 * time.Millisecond,
+		Window:   80 * time.Millisecond,
+		SoftDrop: 20,
+	}}
+	cfg := config.Default()
+	r.Apply(cfg)
+
+	if !reflect.DeepEqual(cfg.Handling(), r.Handling) {
+		t.Errorf("Handling: want %+v, got %+v", r.Handling, cfg.Handling())
+	}
+}
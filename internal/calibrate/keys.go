@@ -0,0 +1,47 @@
+package calibrate
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit  key.Binding
+	Skip  key.Binding
+	Next  key.Binding
+	Hold  key.Binding
+	React key.Binding
+	Retry key.Binding
+}
+
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit:  key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+		Skip:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "skip")),
+		Next:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "continue")),
+		Hold:  key.NewBinding(key.WithKeys("d", "right"), key.WithHelp("d, right", "hold")),
+		React: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "react")),
+		Retry: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry")),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Quit,
+		k.Skip,
+		k.Next,
+		k.Hold,
+		k.React,
+		k.Retry,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Quit,
+			k.Skip,
+			k.Next,
+			k.Hold,
+			k.React,
+			k.Retry,
+		},
+	}
+}
@@ -0,0 +1,278 @@
+package calibrate
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/teautil"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type stage int
+
+const (
+	stageIntro stage = iota
+	stageHold
+	stageReaction
+	stageResults
+	stageDone // the wizard has finished, and there is no model to continue to
+)
+
+const (
+	// holdQuiet is how long after the last press the held key is taken to have been let go.
+	holdQuiet = 500 * time.Millisecond
+	// reactionRounds is the number of reactions measured.
+	reactionRounds = 5
+	// The wait before each prompt is random, between minWait and maxWait, so that it can't be anticipated.
+	minWait = time.Second
+	maxWait = 3 * time.Second
+)
+
+// Input configures the wizard.
+type Input struct {
+	Config *config.Config
+	Path   string       // the config file the recommended settings are saved to
+	Theme  *theme.Theme // colour scheme the wizard is drawn with (nil for the default)
+
+	// FirstRun saves the config even if the wizard is skipped, so that it is only shown the first time the game is run.
+	FirstRun bool
+
+	// Then creates the model shown once the wizard finishes, given the config as saved (nil to quit).
+	Then func(*config.Config) tea.Model
+}
+
+type Model struct {
+	in    *Input
+	stage stage
+
+	measurements   Measurements
+	recommendation Recommendation
+
+	// Reaction test.
+	attempt  int       // incremented each time a prompt is scheduled, so that earlier prompts are ignored
+	prompted time.Time // when the current prompt was shown (zero while waiting for it)
+	early    bool      // whether the key was pressed before the last prompt
+
+	saved bool
+	err   error // the error saving the config, if any
+
+	next tea.Model // the model continued to once the wizard finishes (nil until then)
+
+	// windowSize is the last size of the terminal, passed on to the next model when it starts.
+	windowSize *tea.WindowSizeMsg
+
+	keys   *KeyMap
+	styles *Styles
+	help   help.Model
+}
+
+// holdEndMsg is sent after each press of the held key. If no other press has been received since, the key has been let go.
+type holdEndMsg struct {
+	presses int
+}
+
+// promptMsg is sent to prompt the player to react.
+type promptMsg struct {
+	attempt int
+}
+
+func NewModel(in *Input) *Model {
+	m := &Model{
+		in:     in,
+		keys:   DefaultKeyMap(),
+		styles: NewStyles(in.Theme),
+		help:   theme.NewHelp(in.Theme),
+	}
+	m.setStage(stageIntro)
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowSize = &msg
+	}
+	if m.next != nil {
+		var cmd tea.Cmd
+		m.next, cmd = m.next.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case holdEndMsg:
+		if m.stage == stageHold && msg.presses == len(m.measurements.Presses) {
+			m.setStage(stageReaction)
+			return m, m.schedulePrompt()
+		}
+	case promptMsg:
+		if m.stage == stageReaction && msg.attempt == m.attempt {
+			m.prompted = time.Now()
+		}
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Skip):
+			return m, m.finish(false)
+		case key.Matches(msg, m.keys.Next) && m.stage == stageIntro:
+			m.setStage(stageHold)
+		case key.Matches(msg, m.keys.Next) && m.stage == stageResults:
+			return m, m.finish(true)
+		case key.Matches(msg, m.keys.Retry):
+			m.measurements = Measurements{}
+			m.err = nil
+			m.setStage(stageHold)
+		case key.Matches(msg, m.keys.Hold) && m.stage == stageHold:
+			m.measurements.Presses = append(m.measurements.Presses, time.Now())
+			presses := len(m.measurements.Presses)
+			return m, tea.Tick(holdQuiet, func(_ time.Time) tea.Msg { return holdEndMsg{presses: presses} })
+		case key.Matches(msg, m.keys.React) && m.stage == stageReaction:
+			return m, m.react()
+		}
+	}
+	return m, nil
+}
+
+// setStage moves the wizard on to the stage, enabling only the keys it uses.
+func (m *Model) setStage(s stage) {
+	m.stage = s
+	m.keys.Next.SetEnabled(s == stageIntro || s == stageResults)
+	m.keys.Hold.SetEnabled(s == stageHold)
+	m.keys.React.SetEnabled(s == stageReaction)
+	m.keys.Retry.SetEnabled(s == stageResults)
+	if s == stageResults {
+		m.keys.Next.SetHelp("enter", "save")
+	}
+}
+
+// schedulePrompt waits a random time before prompting the player to react.
+func (m *Model) schedulePrompt() tea.Cmd {
+	m.attempt++
+	m.prompted = time.Time{}
+	attempt := m.attempt
+	wait := minWait + time.Duration(rand.Int63n(int64(maxWait-minWait)))
+	return tea.Tick(wait, func(_ time.Time) tea.Msg { return promptMsg{attempt: attempt} })
+}
+
+// react records a press of the reaction key, starting the round again if it came before the prompt.
+func (m *Model) react() tea.Cmd {
+	if m.prompted.IsZero() {
+		m.early = true
+		return m.schedulePrompt()
+	}
+	m.early = false
+	m.measurements.Reactions = append(m.measurements.Reactions, time.Since(m.prompted))
+	if len(m.measurements.Reactions) < reactionRounds {
+		return m.schedulePrompt()
+	}
+	m.recommendation = Recommend(m.measurements)
+	m.setStage(stageResults)
+	return nil
+}
+
+// finish saves the config, with the recommended handling if apply is set, and continues to the next model.
+// Nothing is saved when the wizard is skipped, unless it is the first run.
+func (m *Model) finish(apply bool) tea.Cmd {
+	cfg := *m.in.Config
+	if apply {
+		m.recommendation.Apply(&cfg)
+	}
+	if apply || m.in.FirstRun {
+		err := config.Save(m.in.Path, &cfg)
+		if err != nil {
+			m.err = err
+			return nil
+		}
+		m.saved = apply
+	}
+
+	if m.in.Then == nil {
+		m.setStage(stageDone)
+		return tea.Quit
+	}
+	m.next = m.in.Then(&cfg)
+	return tea.Batch(m.next.Init(), teautil.Resize(m.windowSize))
+}
+
+func (m Model) View() string {
+	if m.next != nil {
+		return m.next.View()
+	}
+	if m.stage == stageDone {
+		if m.saved {
+			return fmt.Sprintf("Saved handling settings to %s.\n", m.in.Path)
+		}
+		return "Calibration skipped, so no settings were changed.\n"
+	}
+
+	var title, body string
+	switch m.stage {
+	case stageIntro:
+		title = "Calibration"
+		body = "The game can tune how it handles held keys to suit your terminal and how quickly you react. " +
+			"There are two short tests, and the recommended settings are shown before anything is saved.\n\n" +
+			"You can run this again at any time with tetrigo calibrate."
+	case stageHold:
+		title = "Test 1 of 2: Key repeat"
+		body = "Hold down d or the right arrow for about two seconds, then let go.\n\n" +
+			fmt.Sprintf("Presses received: %d", len(m.measurements.Presses))
+	case stageReaction:
+		title = "Test 2 of 2: Reaction time"
+		body = fmt.Sprintf("Press space as soon as NOW appears. Round %d of %d.\n\n",
+			len(m.measurements.Reactions)+1, reactionRounds)
+		switch {
+		case !m.prompted.IsZero():
+			body += m.styles.Prompt.Render("NOW!")
+		case m.early:
+			body += "Too soon! Wait for it..."
+		default:
+			body += "Wait for it..."
+		}
+	case stageResults:
+		title = "Recommended settings"
+		body = m.resultsView()
+	}
+	if m.err != nil {
+		body += "\n\n" + m.styles.Error.Render(m.err.Error())
+	}
+
+	return m.styles.Title.Render(title) + "\n" +
+		m.styles.Body.Render(body) + "\n" +
+		m.help.View(m.keys)
+}
+
+// resultsView shows the recommended handling and the reasons for it.
+func (m Model) resultsView() string {
+	h := m.recommendation.Handling
+	rows := [][2]string{
+		{"DAS", fmt.Sprint(h.DAS)},
+		{"ARR", fmt.Sprint(h.ARR)},
+		{"Repeat window", fmt.Sprint(h.Window)},
+		{"Soft drop", fmt.Sprintf("%dx", h.SoftDrop)},
+	}
+	if h.DAS == 0 {
+		rows = rows[3:]
+	} else if h.ARR == 0 {
+		rows[1][1] = "instant"
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(m.styles.Setting.Render(row[0]) + m.styles.Value.Render(row[1]) + "\n")
+	}
+	b.WriteString("\n")
+	for _, reason := range m.recommendation.Reasons {
+		b.WriteString(m.styles.Reason.Render(reason) + "\n")
+	}
+	b.WriteString("\nThese are saved to " + m.in.Path + ".")
+	return b.String()
+}
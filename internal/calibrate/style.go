@@ -0,0 +1,36 @@
+package calibrate
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Title   lipgloss.Style
+	Body    lipgloss.Style
+	Prompt  lipgloss.Style
+	Setting lipgloss.Style
+	Value   lipgloss.Style
+	Reason  lipgloss.Style
+	Error   lipgloss.Style
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for the calibration wizard with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
+	return &Styles{
+		Title:   lipgloss.NewStyle().Bold(true).Foreground(t.Text).Padding(1, 2, 0),
+		Body:    lipgloss.NewStyle().Foreground(t.Text).Padding(1, 2).Width(76),
+		Prompt:  lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		Setting: lipgloss.NewStyle().Foreground(t.Subtle).Width(16),
+		Value:   lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		Reason:  lipgloss.NewStyle().Foreground(t.Muted),
+		Error:   lipgloss.NewStyle().Foreground(t.Danger),
+	}
+}
@@ -0,0 +1,489 @@
+// Package config loads user settings from a TOML file.
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/controls"
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/BurntSushi/toml"
+)
+
+type Config struct {
+	Level       uint   `toml:"level"`
+	HoldPreview bool   `toml:"hold_preview"`
+	Countdown   uint   `toml:"countdown"`
+	Interludes  bool   `toml:"interludes"`
+	ThemeName   string `toml:"theme"`
+	// Skin is the name of the characters cells are drawn with (see theme.SkinNames), or empty for the default.
+	Skin      string `toml:"skin"`
+	ASCII     bool   `toml:"ascii"`
+	Letters   bool   `toml:"letters"`
+	LowVision bool   `toml:"low_vision"`
+	// Frame is what is drawn around the playfield (see theme.FrameNames), or empty for a box.
+	Frame string `toml:"frame"`
+	// Queue is where the next tetriminos are shown (see theme.QueueNames), or empty beside the playfield.
+	Queue string `toml:"queue"`
+	// GridLines draws faint lines between the cells of the playfield, and Indicators numbers its rows and marks its
+	// columns.
+	GridLines  bool `toml:"grid_lines"`
+	Indicators bool `toml:"indicators"`
+	// LevelColours shifts the colours of tetriminos and the grid every few levels, as classic games do.
+	LevelColours bool `toml:"level_colours"`
+	// ScreenReader describes games in text as they change, for screen readers, rather than drawing them.
+	ScreenReader bool    `toml:"screen_reader"`
+	Speed        float64 `toml:"speed"`
+	Sound        bool    `toml:"sound"`
+	Music        bool    `toml:"music"`
+
+	// Handling of held keys, in milliseconds (see tetris.Handling). DAS is 0 unless calibrated, turning handling off.
+	DAS      uint `toml:"das"`
+	ARR      uint `toml:"arr"`
+	Window   uint `toml:"repeat_window"`
+	SoftDrop uint `toml:"soft_drop"` // soft drop factor (0 for the default)
+	// SoftDropToggle keeps soft drop on until the key is pressed again, rather than only while it is held.
+	SoftDropToggle bool `toml:"soft_drop_toggle"`
+	// SonicDrop makes soft drops drop all the way to the stack at once without locking, in place of the SoftDrop
+	// factor.
+	SonicDrop bool `toml:"sonic_drop"`
+	// LockDown is the name of the lock-down rule, for what restarts the lock delay of a landed tetrimino.
+	LockDown string `toml:"lock_down"`
+	// Keys is the name of the preset of keys gameplay is bound to (see controls.Names), or empty for the
+	// default.
+	Keys string `toml:"keys"`
+
+	// Width and Height are the size of the matrix, in columns and visible rows, for modes played on an empty board.
+	Width  uint `toml:"width"`
+	Height uint `toml:"height"`
+	// Randomizer is the name of the randomizer dealing tetriminos in the modes without one of their own, such as
+	// marathon (see tetris.RandomizerNames).
+	Randomizer string `toml:"randomizer"`
+
+	// DangerRow is the row of the playfield, numbered from the top, which the stack reaching turns the border red and
+	// plays a warning (0 to never warn).
+	DangerRow uint `toml:"danger_row"`
+
+	// Language is the language the interface is shown in (see locale.Names), or empty for the one set by the
+	// environment, such as by LANG.
+	Language string `toml:"language"`
+
+	// IdleTimeout is how many seconds games over SSH and crowd play wait without any input before pausing, rather than
+	// letting the stack top out unattended (0 to never pause).
+	IdleTimeout uint `toml:"idle_timeout"`
+
+	// LeaderboardURL is where the results of finished games are submitted, if SubmitScores opts in to it, under
+	// LeaderboardName (empty for the name logged in with).
+	LeaderboardURL  string `toml:"leaderboard_url"`
+	LeaderboardName string `toml:"leaderboard_name"`
+	SubmitScores    bool   `toml:"submit_scores"`
+
+	// Attack changes the lines sent in versus for each kind of line clear, keyed by the names given by attackFields.
+	// Clears left out send as many lines as in the guideline's table.
+	Attack map[string]uint `toml:"attack,omitempty"`
+}
+
+func Default() *Config {
+	return &Config{
+		Level:       1,
+		HoldPreview: false,
+		Countdown:   3,
+		ThemeName:   theme.DefaultName,
+		Speed:       1,
+		Sound:       true,
+		Indicators:  true,
+		LockDown:    tetris.LockDownExtended.String(),
+		Width:       tetris.DefaultWidth,
+		Height:      tetris.DefaultHeight,
+		Randomizer:  "bag",
+		DangerRow:   4,
+		IdleTimeout: 60,
+	}
+}
+
+// Theme returns the chosen theme, or the default theme if the name is invalid.
+// The theme is drawn with only ASCII characters if ASCII is set, with the cells of the Skin in the Frame and the next
+// tetriminos where Queue says, with grid lines if GridLines is set and without row numbers or column markers unless
+// Indicators is set, with colours shifting every few levels if LevelColours is set, with letters in each cell if
+// Letters is set, and at a larger size with more contrast if LowVision is set, or described in text if ScreenReader is
+// set. It warns once the stack reaches DangerRow, and shows text in the Language.
+func (c *Config) Theme() *theme.Theme {
+	t, err := theme.Get(c.ThemeName)
+	if err != nil {
+		t = theme.Default()
+	}
+	if c.ASCII {
+		t = t.WithASCII()
+	}
+	if skin, err := theme.GetSkin(c.Skin); err == nil {
+		t = t.WithSkin(skin)
+	}
+	if c.Frame != "" {
+		t = t.WithFrame(c.Frame)
+	}
+	if c.Queue != "" {
+		t = t.WithQueue(c.Queue)
+	}
+	if c.GridLines {
+		t = t.WithGridLines()
+	}
+	if !c.Indicators {
+		t = t.WithoutIndicators()
+	}
+	if c.LevelColours {
+		t = t.WithLevelColours()
+	}
+	if c.Letters {
+		t = t.WithLetters()
+	}
+	if c.LowVision {
+		t = t.WithLowVision()
+	}
+	if c.ScreenReader {
+		t = t.WithScreenReader()
+	}
+	if c.DangerRow > 0 {
+		t = t.WithDangerRow(int(c.DangerRow))
+	}
+	language := c.Language
+	if language == "" {
+		language = locale.FromEnv()
+	}
+	if l, err := locale.Get(language); err == nil {
+		t = t.WithLocale(l)
+	}
+	return t
+}
+
+// Idle returns how long games over SSH and crowd play wait without input before pausing (0 to never pause).
+func (c *Config) Idle() time.Duration {
+	return time.Duration(c.IdleTimeout) * time.Second
+}
+
+// Handling returns how held keys move the tetrimino, and the lock-down rule (the guideline's default if the name is
+// invalid).
+func (c *Config) Handling() tetris.Handling {
+	lockDown, _ := tetris.ParseLockDown(c.LockDown)
+	return tetris.Handling{
+		DAS:            time.Duration(c.DAS) * time.Millisecond,
+		ARR:            time.Duration(c.ARR) * time.Millisecond,
+		Window:         time.Duration(c.Window) * time.Millisecond,
+		SoftDrop:       c.SoftDrop,
+		ToggleSoftDrop: c.SoftDropToggle,
+		SonicDrop:      c.SonicDrop,
+		LockDown:       lockDown,
+	}
+}
+
+// AttackTable returns the lines sent in versus for each kind of line clear, or nil if the guideline's table is used.
+func (c *Config) AttackTable() *tetris.AttackTable {
+	if len(c.Attack) == 0 {
+		return nil
+	}
+	table := tetris.DefaultAttackTable()
+	fields := attackFields(&table)
+	for name, lines := range c.Attack {
+		if ptr, ok := fields[name]; ok {
+			*ptr = lines
+		}
+	}
+	return &table
+}
+
+// attackFields returns a pointer to each entry of the attack table, keyed by its name in the config file.
+func attackFields(t *tetris.AttackTable) map[string]*uint {
+	return map[string]*uint{
+		"single":            &t.Single,
+		"double":            &t.Double,
+		"triple":            &t.Triple,
+		"tetris":            &t.Tetris,
+		"mini_tspin_single": &t.MiniTSpinSingle,
+		"tspin_single":      &t.TSpinSingle,
+		"tspin_double":      &t.TSpinDouble,
+		"tspin_triple":      &t.TSpinTriple,
+		"back_to_back":      &t.BackToBack,
+	}
+}
+
+// FieldError describes a problem with the config file, and the field and line at fault where known.
+type FieldError struct {
+	Path   string
+	Field  string
+	Line   int
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	var location string
+	if e.Line > 0 {
+		location = fmt.Sprintf(" line %d", e.Line)
+	}
+	var field string
+	if e.Field != "" {
+		field = fmt.Sprintf(" (%s)", e.Field)
+	}
+	return fmt.Sprintf("%s%s%s: %s", e.Path, location, field, e.Reason)
+}
+
+// DefaultPath returns the location of the config file in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "config.toml"), nil
+}
+
+// Load reads the config file at the given path. A missing file is not an error.
+// If the file cannot be parsed or contains invalid values, the default config is returned along with the error,
+// so the game can still be played.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	} else if err != nil {
+		return Default(), fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var raw map[string]toml.Primitive
+	md, err := toml.Decode(string(data), &raw)
+	if err != nil {
+		var pe toml.ParseError
+		if errors.As(err, &pe) {
+			return Default(), &FieldError{Path: path, Field: pe.LastKey, Line: pe.Position.Line, Reason: pe.Message}
+		}
+		return Default(), &FieldError{Path: path, Reason: err.Error()}
+	}
+
+	// Decode each field separately so that type errors can be attributed to the field at fault.
+	cfg := Default()
+	fields := cfg.fields()
+	for _, k := range md.Keys() {
+		if len(k) != 1 {
+			continue
+		}
+		field := k.String()
+		ptr, ok := fields[field]
+		if !ok {
+			return Default(), &FieldError{Path: path, Field: field, Line: lineOf(data, field), Reason: "unknown field"}
+		}
+		err = md.PrimitiveDecode(raw[field], ptr)
+		if err != nil {
+			reason := strings.TrimPrefix(err.Error(), "toml: ")
+			return Default(), &FieldError{Path: path, Field: field, Line: lineOf(data, field), Reason: reason}
+		}
+	}
+
+	if violations := cfg.validate(); len(violations) > 0 {
+		v := violations[0]
+		return Default(), &FieldError{Path: path, Field: v.field, Line: lineOf(data, v.field), Reason: v.reason}
+	}
+
+	return cfg, nil
+}
+
+// Save writes the config to the file at the given path, creating its directory if needed. Any comments in an existing
+// file are lost.
+func Save(path string, c *Config) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = toml.NewEncoder(&buf).Encode(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	err = os.WriteFile(path, buf.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// fields returns a pointer to each config field, keyed by its name in the config file.
+func (c *Config) fields() map[string]any {
+	return map[string]any{
+		"level":            &c.Level,
+		"hold_preview":     &c.HoldPreview,
+		"countdown":        &c.Countdown,
+		"interludes":       &c.Interludes,
+		"theme":            &c.ThemeName,
+		"skin":             &c.Skin,
+		"frame":            &c.Frame,
+		"queue":            &c.Queue,
+		"grid_lines":       &c.GridLines,
+		"indicators":       &c.Indicators,
+		"level_colours":    &c.LevelColours,
+		"ascii":            &c.ASCII,
+		"letters":          &c.Letters,
+		"low_vision":       &c.LowVision,
+		"screen_reader":    &c.ScreenReader,
+		"speed":            &c.Speed,
+		"sound":            &c.Sound,
+		"music":            &c.Music,
+		"das":              &c.DAS,
+		"arr":              &c.ARR,
+		"repeat_window":    &c.Window,
+		"soft_drop":        &c.SoftDrop,
+		"soft_drop_toggle": &c.SoftDropToggle,
+		"sonic_drop":       &c.SonicDrop,
+		"lock_down":        &c.LockDown,
+		"keys":             &c.Keys,
+		"width":            &c.Width,
+		"height":           &c.Height,
+		"randomizer":       &c.Randomizer,
+		"danger_row":       &c.DangerRow,
+		"language":         &c.Language,
+		"idle_timeout":     &c.IdleTimeout,
+		"leaderboard_url":  &c.LeaderboardURL,
+		"leaderboard_name": &c.LeaderboardName,
+		"submit_scores":    &c.SubmitScores,
+		"attack":           &c.Attack,
+	}
+}
+
+// The limits of the size of the matrix. The narrowest and shortest still leave room for tetriminos to spawn and be
+// moved around.
+const (
+	minWidth  = 6
+	minHeight = 10
+)
+
+// maxIdleTimeout is the longest games can wait without input before pausing, in seconds.
+const maxIdleTimeout = 3600
+
+// maxAttack is the most lines a single line clear can send, which is enough to fill the tallest matrix.
+const maxAttack = tetris.MaxHeight
+
+type violation struct {
+	field  string
+	reason string
+}
+
+func (c *Config) validate() []violation {
+	var violations []violation
+	if c.Level < 1 || c.Level > 15 {
+		violations = append(violations, violation{"level", "must be between 1 and 15"})
+	}
+	if _, err := theme.Get(c.ThemeName); err != nil {
+		violations = append(violations, violation{"theme", fmt.Sprintf("must be one of %s", strings.Join(theme.Names(), ", "))})
+	}
+	if c.Skin != "" && !slices.Contains(theme.SkinNames(), c.Skin) {
+		violations = append(violations, violation{"skin", fmt.Sprintf("must be one of %s", strings.Join(theme.SkinNames(), ", "))})
+	}
+	if c.Frame != "" && !slices.Contains(theme.FrameNames, c.Frame) {
+		violations = append(violations, violation{"frame", fmt.Sprintf("must be one of %s", strings.Join(theme.FrameNames, ", "))})
+	}
+	if c.Queue != "" && !slices.Contains(theme.QueueNames, c.Queue) {
+		violations = append(violations, violation{"queue", fmt.Sprintf("must be one of %s", strings.Join(theme.QueueNames, ", "))})
+	}
+	if c.Speed < 0.5 || c.Speed > 2 {
+		violations = append(violations, violation{"speed", "must be between 0.5 and 2"})
+	}
+	if c.Countdown > 10 {
+		violations = append(violations, violation{"countdown", "must be at most 10"})
+	}
+	if c.DAS > 1000 {
+		violations = append(violations, violation{"das", "must be at most 1000"})
+	}
+	if c.ARR > 500 {
+		violations = append(violations, violation{"arr", "must be at most 500"})
+	}
+	if c.Window > 500 {
+		violations = append(violations, violation{"repeat_window", "must be at most 500"})
+	}
+	if c.SoftDrop > 100 {
+		violations = append(violations, violation{"soft_drop", "must be at most 100"})
+	}
+	if _, err := tetris.ParseLockDown(c.LockDown); err != nil {
+		violations = append(violations, violation{"lock_down", "must be one of extended, infinite, classic"})
+	}
+	if c.Width < minWidth || c.Width > tetris.MaxWidth {
+		violations = append(violations, violation{"width", fmt.Sprintf("must be between %d and %d", minWidth, tetris.MaxWidth)})
+	}
+	if c.Height < minHeight || c.Height > tetris.MaxHeight {
+		violations = append(violations, violation{"height", fmt.Sprintf("must be between %d and %d", minHeight, tetris.MaxHeight)})
+	}
+	if c.Keys != "" && !slices.Contains(controls.Names(), c.Keys) {
+		violations = append(violations, violation{"keys", fmt.Sprintf("must be one of %s", strings.Join(controls.Names(), ", "))})
+	}
+	if !slices.Contains(tetris.RandomizerNames, c.Randomizer) {
+		violations = append(violations, violation{"randomizer", fmt.Sprintf("must be one of %s", strings.Join(tetris.RandomizerNames, ", "))})
+	}
+	if c.DangerRow > tetris.MaxHeight {
+		violations = append(violations, violation{"danger_row", fmt.Sprintf("must be at most %d", tetris.MaxHeight)})
+	}
+	if c.IdleTimeout > maxIdleTimeout {
+		violations = append(violations, violation{"idle_timeout", fmt.Sprintf("must be at most %d", maxIdleTimeout)})
+	}
+	if c.Language != "" && !slices.Contains(locale.Names(), c.Language) {
+		violations = append(violations, violation{"language", fmt.Sprintf("must be one of %s", strings.Join(locale.Names(), ", "))})
+	}
+	if c.LeaderboardURL != "" {
+		u, err := url.Parse(c.LeaderboardURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			violations = append(violations, violation{"leaderboard_url", "must be an http or https URL"})
+		}
+	}
+	if c.SubmitScores && c.LeaderboardURL == "" {
+		violations = append(violations, violation{"submit_scores", "requires leaderboard_url to be set"})
+	}
+	names := sortedKeys(attackFields(&tetris.AttackTable{}))
+	for _, name := range sortedKeys(c.Attack) {
+		field := "attack." + name
+		if !slices.Contains(names, name) {
+			violations = append(violations, violation{field, fmt.Sprintf("must be one of %s", strings.Join(names, ", "))})
+		} else if c.Attack[name] > maxAttack {
+			violations = append(violations, violation{field, fmt.Sprintf("must be at most %d", maxAttack)})
+		}
+	}
+	return violations
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// lineOf returns the line number the field is defined on, or 0 if it cannot be found.
+// Fields within tables are given as dotted keys, such as "table.field".
+func lineOf(data []byte, field string) int {
+	var table string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "[") {
+			table = strings.Trim(text, "[] ")
+			continue
+		}
+
+		key, _, found := strings.Cut(text, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if table != "" {
+			key = table + "." + key
+		}
+		if key == field {
+			return line
+		}
+	}
+	return 0
+}
@@ -0,0 +1,451 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// withDefaults returns the default config with the fields under test set.
+func withDefaults(set func(c *Config)) *Config {
+	c := Default()
+	set(c)
+	return c
+}
+
+func TestLoad(t *testing.T) {
+	tt := []struct {
+		name          string
+		contents      string
+		expected      *Config
+		expectedField string
+		expectedLine  int
+		expectsErr    bool
+	}{
+		{
+			name:     "valid",
+			contents: "level = 5\nhold_preview = true\ncountdown = 0\ninterludes = true\n",
+			expected: withDefaults(func(c *Config) {
+				c.Level = 5
+				c.HoldPreview = true
+				c.Countdown = 0
+				c.Interludes = true
+			}),
+		},
+		{
+			name:     "partial",
+			contents: "hold_preview = true\n",
+			expected: withDefaults(func(c *Config) { c.HoldPreview = true }),
+		},
+		{
+			name:          "syntax error",
+			contents:      "level = 5\nhold_preview = tru\n",
+			expected:      Default(),
+			expectedLine:  2,
+			expectsErr:    true,
+			expectedField: "hold_preview",
+		},
+		{
+			name:          "wrong type",
+			contents:      "level = 5\nhold_preview = \"yes\"\n",
+			expected:      Default(),
+			expectedField: "hold_preview",
+			expectedLine:  2,
+			expectsErr:    true,
+		},
+		{
+			name:          "unknown field",
+			contents:      "level = 5\n\nghost = true\n",
+			expected:      Default(),
+			expectedField: "ghost",
+			expectedLine:  3,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid value",
+			contents:      "# comment\nlevel = 20\n",
+			expected:      Default(),
+			expectedField: "level",
+			expectedLine:  2,
+			expectsErr:    true,
+		},
+		{
+			name:     "theme",
+			contents: "theme = \"nes\"\n",
+			expected: withDefaults(func(c *Config) { c.ThemeName = "nes" }),
+		},
+		{
+			name:     "ascii",
+			contents: "ascii = true\n",
+			expected: withDefaults(func(c *Config) { c.ASCII = true }),
+		},
+		{
+			name:     "colourblind letters",
+			contents: "theme = \"colourblind\"\nletters = true\n",
+			expected: withDefaults(func(c *Config) {
+				c.ThemeName = "colourblind"
+				c.Letters = true
+			}),
+		},
+		{
+			name:     "low vision",
+			contents: "low_vision = true\n",
+			expected: withDefaults(func(c *Config) { c.LowVision = true }),
+		},
+		{
+			name:     "screen reader",
+			contents: "screen_reader = true\n",
+			expected: withDefaults(func(c *Config) { c.ScreenReader = true }),
+		},
+		{
+			name:     "idle timeout",
+			contents: "idle_timeout = 0\n",
+			expected: withDefaults(func(c *Config) { c.IdleTimeout = 0 }),
+		},
+		{
+			name:          "invalid idle timeout",
+			contents:      "idle_timeout = 7200\n",
+			expected:      Default(),
+			expectedField: "idle_timeout",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid theme",
+			contents:      "theme = \"neon\"\n",
+			expected:      Default(),
+			expectedField: "theme",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "speed",
+			contents: "speed = 0.5\n",
+			expected: withDefaults(func(c *Config) { c.Speed = 0.5 }),
+		},
+		{
+			name:     "audio",
+			contents: "sound = false\nmusic = true\n",
+			expected: withDefaults(func(c *Config) {
+				c.Music = true
+				c.Sound = false
+			}),
+		},
+		{
+			name:          "invalid speed",
+			contents:      "speed = 3.0\n",
+			expected:      Default(),
+			expectedField: "speed",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "handling",
+			contents: "das = 167\narr = 33\nrepeat_window = 60\nsoft_drop = 20\nsoft_drop_toggle = true\n",
+			expected: withDefaults(func(c *Config) {
+				c.DAS = 167
+				c.ARR = 33
+				c.Window = 60
+				c.SoftDrop = 20
+				c.SoftDropToggle = true
+			}),
+		},
+		{
+			name:     "sonic drop",
+			contents: "sonic_drop = true\n",
+			expected: withDefaults(func(c *Config) { c.SonicDrop = true }),
+		},
+		{
+			name:     "lock down",
+			contents: "lock_down = \"infinite\"\n",
+			expected: withDefaults(func(c *Config) { c.LockDown = "infinite" }),
+		},
+		{
+			name:          "invalid lock down",
+			contents:      "lock_down = \"step\"\n",
+			expected:      Default(),
+			expectedField: "lock_down",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "leaderboard",
+			contents: "leaderboard_url = \"https://scores.example.com/submit\"\nleaderboard_name = \"alice\"\nsubmit_scores = true\n",
+			expected: withDefaults(func(c *Config) {
+				c.LeaderboardURL = "https://scores.example.com/submit"
+				c.LeaderboardName = "alice"
+				c.SubmitScores = true
+			}),
+		},
+		{
+			name:          "invalid leaderboard url",
+			contents:      "leaderboard_url = \"scores.example.com\"\n",
+			expected:      Default(),
+			expectedField: "leaderboard_url",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:          "submit scores without leaderboard",
+			contents:      "submit_scores = true\n",
+			expected:      Default(),
+			expectedField: "submit_scores",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "playfield",
+			contents: "frame = \"well\"\ngrid_lines = true\nindicators = false\n",
+			expected: withDefaults(func(c *Config) {
+				c.Frame = "well"
+				c.GridLines = true
+				c.Indicators = false
+			}),
+		},
+		{
+			name:          "invalid frame",
+			contents:      "frame = \"double\"\n",
+			expected:      Default(),
+			expectedField: "frame",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "queue",
+			contents: "queue = \"below\"\n",
+			expected: withDefaults(func(c *Config) { c.Queue = "below" }),
+		},
+		{
+			name:          "invalid queue",
+			contents:      "queue = \"left\"\n",
+			expected:      Default(),
+			expectedField: "queue",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "skin",
+			contents: "skin = \"braille\"\n",
+			expected: withDefaults(func(c *Config) { c.Skin = "braille" }),
+		},
+		{
+			name:          "invalid skin",
+			contents:      "skin = \"emoji\"\n",
+			expected:      Default(),
+			expectedField: "skin",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "keys",
+			contents: "keys = \"vim\"\n",
+			expected: withDefaults(func(c *Config) { c.Keys = "vim" }),
+		},
+		{
+			name:          "invalid keys",
+			contents:      "keys = \"emacs\"\n",
+			expected:      Default(),
+			expectedField: "keys",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "size",
+			contents: "width = 12\nheight = 24\n",
+			expected: withDefaults(func(c *Config) {
+				c.Width = 12
+				c.Height = 24
+			}),
+		},
+		{
+			name:          "invalid width",
+			contents:      "width = 4\n",
+			expected:      Default(),
+			expectedField: "width",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "randomizer",
+			contents: "randomizer = \"tgm\"\n",
+			expected: withDefaults(func(c *Config) { c.Randomizer = "tgm" }),
+		},
+		{
+			name:     "danger row",
+			contents: "danger_row = 0\n",
+			expected: withDefaults(func(c *Config) { c.DangerRow = 0 }),
+		},
+		{
+			name:          "invalid danger row",
+			contents:      "danger_row = 41\n",
+			expected:      Default(),
+			expectedField: "danger_row",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:     "attack table",
+			contents: "level = 1\n\n[attack]\ntetris = 5\nback_to_back = 2\n",
+			expected: withDefaults(func(c *Config) { c.Attack = map[string]uint{"tetris": 5, "back_to_back": 2} }),
+		},
+		{
+			name:          "unknown attack",
+			contents:      "[attack]\ntetris = 5\nquad = 4\n",
+			expected:      Default(),
+			expectedField: "attack.quad",
+			expectedLine:  3,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid attack",
+			contents:      "[attack]\ntetris = 50\n",
+			expected:      Default(),
+			expectedField: "attack.tetris",
+			expectedLine:  2,
+			expectsErr:    true,
+		},
+		{
+			name:     "language",
+			contents: "language = \"es\"\n",
+			expected: withDefaults(func(c *Config) { c.Language = "es" }),
+		},
+		{
+			name:          "invalid language",
+			contents:      "language = \"klingon\"\n",
+			expected:      Default(),
+			expectedField: "language",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid randomizer",
+			contents:      "randomizer = \"bag7\"\n",
+			expected:      Default(),
+			expectedField: "randomizer",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid height",
+			contents:      "level = 2\nheight = 50\n",
+			expected:      Default(),
+			expectedField: "height",
+			expectedLine:  2,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid DAS",
+			contents:      "das = 5000\n",
+			expected:      Default(),
+			expectedField: "das",
+			expectedLine:  1,
+			expectsErr:    true,
+		},
+		{
+			name:          "invalid countdown",
+			contents:      "level = 5\ncountdown = 60\n",
+			expected:      Default(),
+			expectedField: "countdown",
+			expectedLine:  2,
+			expectsErr:    true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			err := os.WriteFile(path, []byte(tc.contents), 0o600)
+			if err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			cfg, err := Load(path)
+
+			if !reflect.DeepEqual(cfg, tc.expected) {
+				t.Errorf("Config: want %+v, got %+v", tc.expected, cfg)
+			}
+			if !tc.expectsErr {
+				if err != nil {
+					t.Errorf("expected nil, got error: %v", err)
+				}
+				return
+			}
+
+			var fe *FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("expected FieldError, got %v", err)
+			}
+			if fe.Field != tc.expectedField {
+				t.Errorf("Field: want %q, got %q", tc.expectedField, fe.Field)
+			}
+			if fe.Line != tc.expectedLine {
+				t.Errorf("Line: want %d, got %d", tc.expectedLine, fe.Line)
+			}
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tetrigo", "config.toml")
+	cfg := Default()
+	cfg.ThemeName = "nes"
+	cfg.DAS = 167
+	cfg.ARR = 33
+
+	err := Save(path, cfg)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("Config: want %+v, got %+v", cfg, loaded)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Errorf("expected nil, got error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Config: want %+v, got %+v", Default(), cfg)
+	}
+}
+
+func TestConfig_Handling(t *testing.T) {
+	cfg := &Config{DAS: 167, ARR: 33, Window: 60, SoftDrop: 20, SoftDropToggle: true, SonicDrop: true, LockDown: "classic"}
+	expected := tetris.Handling{
+		DAS:            167 * time.Millisecond,
+		ARR:            33 * time.Millisecond,
+		Window:         60 * time.Millisecond,
+		SoftDrop:       20,
+		ToggleSoftDrop: true,
+		SonicDrop:      true,
+		LockDown:       tetris.LockDownClassic,
+	}
+	if h := cfg.Handling(); h != expected {
+		t.Errorf("want %+v, got %+v", expected, h)
+	}
+}
+
+func TestConfig_AttackTable(t *testing.T) {
+	if table := Default().AttackTable(); table != nil {
+		t.Errorf("want nil, got %+v", *table)
+	}
+
+	cfg := &Config{Attack: map[string]uint{"tetris": 5, "tspin_double": 3}}
+	expected := tetris.DefaultAttackTable()
+	expected.Tetris = 5
+	expected.TSpinDouble = 3
+	if table := cfg.AttackTable(); table == nil || *table != expected {
+		t.Errorf("want %+v, got %+v", expected, table)
+	}
+}
@@ -0,0 +1,78 @@
+// Package controls has the presets of keys gameplay can be bound to, shared by the games which bind them and the
+// config and menu which choose one by name.
+package controls
+
+import "sort"
+
+// Default is the name of the preset used when none is chosen.
+const Default = "default"
+
+// Preset is the keys bound to each gameplay action, so that they can be switched all at once.
+type Preset struct {
+	Left, Right, Clockwise, CounterClockwise, SoftDrop, HardDrop, Hold []string
+	Suspend                                                            []string // moved off the keys played with
+}
+
+var presets = map[string]Preset{
+	Default: {
+		Left:             []string{"a", "j"},
+		Right:            []string{"d", "l"},
+		Clockwise:        []string{"e", "o"},
+		CounterClockwise: []string{"q", "u"},
+		SoftDrop:         []string{"s", "k"},
+		HardDrop:         []string{"w", "i"},
+		Hold:             []string{" "},
+		Suspend:          []string{"z"},
+	},
+	"arrows": {
+		Left:             []string{"left"},
+		Right:            []string{"right"},
+		Clockwise:        []string{"up", "x"},
+		CounterClockwise: []string{"z"},
+		SoftDrop:         []string{"down"},
+		HardDrop:         []string{" "},
+		Hold:             []string{"c"},
+		Suspend:          []string{"ctrl+z"},
+	},
+	"vim": {
+		Left:             []string{"h"},
+		Right:            []string{"l"},
+		Clockwise:        []string{"k"},
+		CounterClockwise: []string{"K"},
+		SoftDrop:         []string{"j"},
+		HardDrop:         []string{" "},
+		Hold:             []string{"c"},
+		Suspend:          []string{"z"},
+	},
+	"wasd": {
+		Left:             []string{"a"},
+		Right:            []string{"d"},
+		Clockwise:        []string{"w"},
+		CounterClockwise: []string{"q"},
+		SoftDrop:         []string{"s"},
+		HardDrop:         []string{" "},
+		Hold:             []string{"e"},
+		Suspend:          []string{"z"},
+	},
+}
+
+// Names returns the names of the presets, with the default first and the rest sorted alphabetically.
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		if name != Default {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{Default}, names...)
+}
+
+// Lookup returns the named preset, or the default preset and false if there is no such preset.
+func Lookup(name string) (Preset, bool) {
+	p, ok := presets[name]
+	if !ok {
+		return presets[Default], false
+	}
+	return p, true
+}
@@ -0,0 +1,370 @@
+// Package drill generates T-spin drills: boards with a slot for a T-spin double or triple, and the queue of
+// tetriminos to fill it with.
+//
+// Each board is the stack as it is just before the T-spin: the rows the T clears are filled but for the cells it
+// takes, and the stack beside the slot overhangs it, so that a T can only get in by rotating, kicked as in SRS. The
+// tetrimino which completes the overhang (the roof) is taken out of the board and dealt first, and the T after it.
+package drill
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Kind is the T-spin a drill's slot is for.
+type Kind int
+
+const (
+	// Double is a T-spin double: a T pointing down into a notch, turned in from beside it under the roof.
+	Double Kind = iota
+	// Triple is a T-spin triple: a T on its side at the bottom of a well, kicked in from above it under the roof.
+	Triple
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Double:
+		return "T-spin double"
+	case Triple:
+		return "T-spin triple"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Lines returns the number of lines the T-spin clears.
+func (k Kind) Lines() int {
+	if k == Triple {
+		return 3
+	}
+	return 2
+}
+
+// Drill is a board with a slot to spin a T into.
+type Drill struct {
+	Kind   Kind
+	Matrix tetris.Matrix
+	Queue  []byte              // values of the tetriminos dealt, in order: the roof, then the T
+	Slot   []tetris.Coordinate // the cells of the matrix the T fills, from the top left
+}
+
+// Executed reports whether the tetrimino was spun into the drill's slot: a T which locked there, making a T-spin.
+func (d *Drill) Executed(t *tetris.Tetrimino, spin tetris.Spin) bool {
+	if t.Value != 'T' || spin != tetris.SpinFull {
+		return false
+	}
+	return slices.Equal(sorted(tetris.NewPiece(t).Cells), d.Slot)
+}
+
+// maxAttempts is how many boards are tried for each drill before giving up, should none have a slot the T can spin
+// into or a roof which can be placed.
+const maxAttempts = 100
+
+// maxGarbage is the most rows of garbage generated below a slot.
+const maxGarbage = 4
+
+// maxWall is the most rows the stack overhanging a slot is built up by.
+const maxWall = 3
+
+// Generator deals drills for a matrix of the given size, in an order decided by its seed.
+type Generator struct {
+	rng           *rand.Rand
+	width, height int
+}
+
+// NewGenerator creates a generator of drills for a matrix with the given columns and visible rows. The kind of each
+// drill, where its slot is and the garbage below it are decided by the seed.
+func NewGenerator(width, height int, seed int64) *Generator {
+	return &Generator{
+		rng:    rand.New(rand.NewSource(seed)),
+		width:  width,
+		height: height,
+	}
+}
+
+// Next generates the next drill.
+func (g *Generator) Next() (*Drill, error) {
+	for i := 0; i < maxAttempts; i++ {
+		d, roof := g.generate()
+		if !reachable(d.Matrix, 'T', d.Slot) {
+			continue
+		}
+		if g.takeRoof(d, roof) {
+			return d, nil
+		}
+	}
+	return nil, errors.New("failed to generate a drill the T can spin into")
+}
+
+// generate builds a drill with its roof in place, returning it with the cell of the roof overhanging the slot. The
+// slot is in the rows above any garbage, with the stack left of it for a double and right of it for a triple, and
+// half of the drills are mirrored so that it is on the other side.
+func (g *Generator) generate() (*Drill, tetris.Coordinate) {
+	matrix := tetris.NewMatrix(g.width, g.height)
+	garbage := g.rng.Intn(maxGarbage + 1)
+	bottom := len(matrix) - 1 - garbage
+	for i := 0; i < garbage; i++ {
+		fill(matrix[len(matrix)-1-i], g.rng.Intn(g.width))
+	}
+
+	d := &Drill{Kind: Kind(g.rng.Intn(2)), Matrix: matrix}
+	wall := 1 + g.rng.Intn(maxWall)
+	var roof tetris.Coordinate
+	switch d.Kind {
+	case Double:
+		// The T points down into columns x to x+2, turned in from the right under the corner of the stack on its left.
+		x, y := g.rng.Intn(g.width-2), bottom-1
+		d.Slot = []tetris.Coordinate{{X: x, Y: y}, {X: x + 1, Y: y}, {X: x + 2, Y: y}, {X: x + 1, Y: y + 1}}
+		fill(matrix[y+1], x+1)
+		fill(matrix[y], x, x+1, x+2)
+		for row := y - 1; row > y-1-wall; row-- {
+			build(matrix[row], 0, x)
+		}
+		roof = tetris.Coordinate{X: x, Y: y - 1}
+	case Triple:
+		// The T stands in column x+1 pointing left into column x, kicked down from the left under the stack on its
+		// right, which overhangs the top of the slot.
+		x, y := 1+g.rng.Intn(g.width-3), bottom-2
+		d.Slot = []tetris.Coordinate{{X: x + 1, Y: y}, {X: x, Y: y + 1}, {X: x + 1, Y: y + 1}, {X: x + 1, Y: y + 2}}
+		fill(matrix[y+2], x+1)
+		fill(matrix[y+1], x, x+1)
+		fill(matrix[y], x+1)
+		build(matrix[y-1], x+2, g.width-1)
+		for row := y - 2; row > y-2-wall; row-- {
+			build(matrix[row], x+1, g.width-1)
+		}
+		roof = tetris.Coordinate{X: x + 1, Y: y - 2}
+	}
+
+	if g.rng.Intn(2) == 0 {
+		mirror(matrix, d.Slot)
+		roof.X = g.width - 1 - roof.X
+	}
+	d.Slot = sorted(d.Slot)
+	return d, roof
+}
+
+// takeRoof picks a tetrimino in the stack covering the roof cell, above the rows the T clears, which can be placed
+// there again once it is taken out, and takes it out of the drill's board to be dealt before the T. It reports false
+// if there is none.
+func (g *Generator) takeRoof(d *Drill, roof tetris.Coordinate) bool {
+	type placement struct {
+		value byte
+		cells []tetris.Coordinate
+	}
+	var roofs []placement
+	for _, t := range tetris.Tetriminos {
+		if t.Value == 'T' {
+			continue
+		}
+		for _, shape := range shapes(t.Value) {
+			for _, c := range shape {
+				cells := make([]tetris.Coordinate, len(shape))
+				above := true
+				for i, s := range shape {
+					cells[i] = tetris.Coordinate{X: s.X - c.X + roof.X, Y: s.Y - c.Y + roof.Y}
+					above = above && cells[i].Y < d.Slot[0].Y
+				}
+				if !above {
+					continue
+				}
+				matrix, ok := without(d.Matrix, cells)
+				if ok && resting(matrix, cells) && reachable(matrix, t.Value, sorted(cells)) {
+					roofs = append(roofs, placement{value: t.Value, cells: cells})
+				}
+			}
+		}
+	}
+	if len(roofs) == 0 {
+		return false
+	}
+
+	r := roofs[g.rng.Intn(len(roofs))]
+	d.Matrix, _ = without(d.Matrix, r.cells)
+	d.Queue = []byte{r.value, 'T'}
+	return true
+}
+
+// fill fills every cell of the row except those in the given columns.
+func fill(row []byte, except ...int) {
+	for col := range row {
+		if !slices.Contains(except, col) {
+			row[col] = tetris.GarbageValue
+		}
+	}
+}
+
+// build fills the cells of the row from one column to another, inclusive.
+func build(row []byte, from, to int) {
+	for col := from; col <= to; col++ {
+		row[col] = tetris.GarbageValue
+	}
+}
+
+// mirror flips the matrix and the slot's cells from left to right.
+func mirror(matrix tetris.Matrix, slot []tetris.Coordinate) {
+	for _, row := range matrix {
+		slices.Reverse(row)
+	}
+	for i := range slot {
+		slot[i].X = len(matrix[0]) - 1 - slot[i].X
+	}
+}
+
+// without returns a copy of the matrix with the cells emptied, or false if any of them isn't filled.
+func without(matrix tetris.Matrix, cells []tetris.Coordinate) (tetris.Matrix, bool) {
+	matrix = matrix.Clone()
+	for _, c := range cells {
+		if c.Y < 0 || c.Y >= len(matrix) || c.X < 0 || c.X >= len(matrix[0]) || matrix.IsCellEmpty(c.Y, c.X) {
+			return nil, false
+		}
+		matrix[c.Y][c.X] = 0
+	}
+	return matrix, true
+}
+
+// resting reports whether a tetrimino in the cells would rest on the stack or the floor.
+func resting(matrix tetris.Matrix, cells []tetris.Coordinate) bool {
+	for _, c := range cells {
+		below := tetris.Coordinate{X: c.X, Y: c.Y + 1}
+		if below.Y == len(matrix) || (!matrix.IsCellEmpty(below.Y, below.X) && !slices.Contains(cells, below)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sorted returns the cells in order from the top left, row by row.
+func sorted(cells []tetris.Coordinate) []tetris.Coordinate {
+	cells = slices.Clone(cells)
+	slices.SortFunc(cells, func(a, b tetris.Coordinate) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return cells
+}
+
+// spawn returns the tetrimino with the value where it spawns in the matrix.
+func spawn(matrix tetris.Matrix, value byte) (*tetris.Tetrimino, error) {
+	bag, err := tetris.NewFixedBag(matrix, []byte{value})
+	if err != nil {
+		return nil, err
+	}
+	return bag.Next(), nil
+}
+
+// shapes returns the cells of the tetrimino with the value in each of its distinct rotations, from the top left.
+func shapes(value byte) [][]tetris.Coordinate {
+	matrix := tetris.NewMatrix(tetris.DefaultWidth, tetris.DefaultHeight)
+	t, err := spawn(matrix, value)
+	if err != nil || matrix.AddTetrimino(t) != nil {
+		return nil
+	}
+	var all [][]tetris.Coordinate
+	for i := 0; i < 4; i++ {
+		cells := tetris.NewPiece(t).Cells
+		for j := range cells {
+			cells[j].X -= t.Pos.X
+			cells[j].Y -= t.Pos.Y
+		}
+		cells = sorted(cells)
+		if !slices.ContainsFunc(all, func(s []tetris.Coordinate) bool { return slices.Equal(s, cells) }) {
+			all = append(all, cells)
+		}
+		if _, err := t.Rotate(&matrix, true); err != nil {
+			break
+		}
+	}
+	return all
+}
+
+// moves are the ways a tetrimino can be moved, each returning the kick it used if it rotated (-1 if not) and whether
+// it moved.
+var moves = []func(t *tetris.Tetrimino, m *tetris.Matrix) (int, bool, error){
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (int, bool, error) {
+		moved, err := t.MoveLeft(m)
+		return -1, moved, err
+	},
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (int, bool, error) {
+		moved, err := t.MoveRight(m)
+		return -1, moved, err
+	},
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (int, bool, error) {
+		moved, err := t.MoveDown(m)
+		return -1, moved, err
+	},
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (int, bool, error) {
+		kick, err := t.RotateWithKicks(m, true)
+		return kick, kick >= 0, err
+	},
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (int, bool, error) {
+		kick, err := t.RotateWithKicks(m, false)
+		return kick, kick >= 0, err
+	},
+}
+
+// position is where a tetrimino is in the matrix, which way it faces, and the kick its last move used if it rotated
+// (-1 if not), which decides whether a T makes a T-spin.
+type position struct {
+	x, y, rotation, kick int
+}
+
+// reachable reports whether the tetrimino with the value, moving and rotating from where it spawns, can come to rest
+// in the cells. A T must make a T-spin getting there.
+func reachable(matrix tetris.Matrix, value byte, cells []tetris.Coordinate) bool {
+	start, err := spawn(matrix, value)
+	if err != nil || !matrix.CanAddTetrimino(start) {
+		return false
+	}
+
+	type step struct {
+		t    *tetris.Tetrimino
+		kick int
+	}
+	seen := map[position]bool{{start.Pos.X, start.Pos.Y, start.CurrentRotation, -1}: true}
+	queue := []step{{start, -1}}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		if landed(matrix, s.t, s.kick, cells) {
+			return true
+		}
+
+		for _, move := range moves {
+			m := matrix.Clone()
+			next := s.t.Copy()
+			if m.AddTetrimino(next) != nil {
+				continue
+			}
+			kick, moved, err := move(next, &m)
+			p := position{next.Pos.X, next.Pos.Y, next.CurrentRotation, kick}
+			if err != nil || !moved || seen[p] {
+				continue
+			}
+			seen[p] = true
+			queue = append(queue, step{next, kick})
+		}
+	}
+	return false
+}
+
+// landed reports whether the tetrimino has come to rest in the cells, having been moved there by the kick of its last
+// rotation (-1 if it wasn't rotated there). A T must make a T-spin.
+func landed(matrix tetris.Matrix, t *tetris.Tetrimino, kick int, cells []tetris.Coordinate) bool {
+	if t.CanMoveDown(matrix) || !slices.Equal(sorted(tetris.NewPiece(t).Cells), cells) {
+		return false
+	}
+	if t.Value != 'T' {
+		return true
+	}
+	matrix = matrix.Clone()
+	if matrix.AddTetrimino(t) != nil {
+		return false
+	}
+	return matrix.TSpin(t, kick) == tetris.SpinFull
+}
@@ -0,0 +1,159 @@
+package drill
+
+import (
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestGenerator_Next(t *testing.T) {
+	tt := map[string]struct {
+		width, height int
+	}{
+		"default": {
+			width:  tetris.DefaultWidth,
+			height: tetris.DefaultHeight,
+		},
+		"narrow": {
+			width:  4,
+			height: 8,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			g := NewGenerator(tc.width, tc.height, 42)
+			kinds := make(map[Kind]bool)
+			for i := 0; i < 20; i++ {
+				d, err := g.Next()
+				if err != nil {
+					t.Fatalf("expected nil, got error: %v", err)
+				}
+				kinds[d.Kind] = true
+				if len(d.Queue) != 2 || d.Queue[0] == 'T' || d.Queue[1] != 'T' {
+					t.Errorf("Drill %d: want the roof and then a T dealt, got %q", i, d.Queue)
+				}
+
+				// Locking a T in the slot clears the lines the drill is for.
+				matrix := d.Matrix.Clone()
+				for _, c := range d.Slot {
+					if !matrix.IsCellEmpty(c.Y, c.X) {
+						t.Fatalf("Drill %d: want slot cell %v empty, got filled", i, c)
+					}
+					matrix[c.Y][c.X] = 'T'
+				}
+				rows := make([][]bool, d.Slot[len(d.Slot)-1].Y-d.Slot[0].Y+1)
+				placed := &tetris.Tetrimino{Value: 'T', Pos: d.Slot[0], Cells: rows}
+				if lines := len(matrix.CompletedLines(placed)); lines != d.Kind.Lines() {
+					t.Errorf("Drill %d: want %d lines cleared by a %v, got %d\n%s", i, d.Kind.Lines(), d.Kind, lines, d.Matrix)
+				}
+			}
+			if !kinds[Double] || !kinds[Triple] {
+				t.Errorf("want drills of both kinds, got %v", kinds)
+			}
+		})
+	}
+}
+
+func TestReachable(t *testing.T) {
+	tt := map[string]struct {
+		board string
+		slot  []tetris.Coordinate
+		want  bool
+	}{
+		"double under a roof": {
+			board: `
+XXXX......
+XXX...XXXX
+XXXX.XXXXX
+`,
+			slot: []tetris.Coordinate{{X: 3, Y: 38}, {X: 4, Y: 38}, {X: 5, Y: 38}, {X: 4, Y: 39}},
+			want: true,
+		},
+		"double open from above": {
+			board: `
+XXX.......
+XXX...XXXX
+XXXX.XXXXX
+`,
+			slot: []tetris.Coordinate{{X: 3, Y: 38}, {X: 4, Y: 38}, {X: 5, Y: 38}, {X: 4, Y: 39}},
+		},
+		"triple under a roof": {
+			board: `
+..XXXXXXXX
+...XXXXXXX
+XX.XXXXXXX
+X..XXXXXXX
+XX.XXXXXXX
+`,
+			slot: []tetris.Coordinate{{X: 2, Y: 37}, {X: 1, Y: 38}, {X: 2, Y: 38}, {X: 2, Y: 39}},
+			want: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			matrix, err := tetris.ParseMatrix(tc.board)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if got := reachable(matrix, 'T', sorted(tc.slot)); got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDrill_Executed(t *testing.T) {
+	d, err := NewGenerator(tetris.DefaultWidth, tetris.DefaultHeight, 7).Next()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	tt := map[string]struct {
+		value  byte
+		offset int
+		spin   tetris.Spin
+		want   bool
+	}{
+		"spun into the slot": {
+			value: 'T',
+			spin:  tetris.SpinFull,
+			want:  true,
+		},
+		"dropped into the slot": {
+			value: 'T',
+		},
+		"a mini T-spin": {
+			value: 'T',
+			spin:  tetris.SpinMini,
+		},
+		"beside the slot": {
+			value:  'T',
+			offset: 1,
+			spin:   tetris.SpinFull,
+		},
+		"not a T": {
+			value: 'L',
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			top, left := d.Slot[0], d.Slot[0].X
+			for _, c := range d.Slot {
+				left = min(left, c.X)
+			}
+			cells := [][]bool{{false, false, false}, {false, false, false}, {false, false, false}}
+			for _, c := range d.Slot {
+				cells[c.Y-top.Y][c.X-left] = true
+			}
+			placed := &tetris.Tetrimino{Value: tc.value, Pos: tetris.Coordinate{X: left + tc.offset, Y: top.Y}, Cells: cells}
+
+			got := d.Executed(placed, tc.spin)
+			if got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package dual
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit   key.Binding
+	Switch key.Binding
+}
+
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit:   key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+		Switch: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch board")),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Quit,
+		k.Switch,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Quit,
+			k.Switch,
+		},
+	}
+}
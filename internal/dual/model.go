@@ -0,0 +1,163 @@
+// Package dual implements a single-player mode played on two boards at once. Gravity runs on both boards, but only
+// the active one is controlled, and the player switches between them with a key. Topping out on either ends the game.
+package dual
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Input struct {
+	Level     uint
+	Countdown uint          // seconds counted down before the game starts
+	Theme     *theme.Theme  // colour scheme the boards are drawn with (nil for the default)
+	Speed     float64       // gravity multiplier for both boards (0 for normal speed)
+	Audio     *audio.Player // plays the sounds of both boards (nil for silence)
+	Keys      string        // the name of the preset of keys both boards are bound to (empty for the default)
+
+	// Handling is how held keys move the tetrimino on both boards (see marathon.Input).
+	Handling tetris.Handling
+}
+
+// Model runs a game on each of two boards. Results are not recorded, since the boards are scored separately.
+type Model struct {
+	boards [2]tea.Model
+	ids    [2]int
+	active int // index of the board that gameplay keys are sent to
+
+	// Results of each board, set once the game is over.
+	results [2]*marathon.Results
+	// toppedOut is the index of the board that ended the game.
+	toppedOut int
+
+	keys   *KeyMap
+	styles *Styles
+	help   help.Model
+}
+
+func NewModel(in *Input) *Model {
+	m := &Model{
+		keys:   DefaultKeyMap(),
+		styles: NewStyles(in.Theme),
+		help:   theme.NewHelp(in.Theme),
+	}
+	for i := range m.boards {
+		board := marathon.NewModel(&marathon.Input{
+			Level:     in.Level,
+			Countdown: in.Countdown,
+			Theme:     in.Theme,
+			Speed:     in.Speed,
+			Audio:     in.Audio,
+			Keys:      in.Keys,
+			Handling:  in.Handling,
+		})
+		m.boards[i] = *board
+		m.ids[i] = board.ID()
+	}
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.boards[0].Init(), m.boards[1].Init())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.isOver() {
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Quit) {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// The boards are laid out side by side, so neither is centred in the terminal on its own.
+		return m, nil
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Switch) {
+			m.active = 1 - m.active
+			return m, nil
+		}
+		// Only the active board is controlled, so it alone receives key presses.
+		var cmd tea.Cmd
+		m.boards[m.active], cmd = m.boards[m.active].Update(msg)
+		return m, cmd
+	case marathon.AttackMsg:
+		// Lines cleared on one board are not sent anywhere.
+		return m, nil
+	case marathon.GameOverMsg:
+		for i, id := range m.ids {
+			results := m.boards[i].(marathon.Model).Results()
+			m.results[i] = &results
+			if id == msg.ID {
+				m.toppedOut = i
+			}
+		}
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	for i := range m.boards {
+		var cmd tea.Cmd
+		m.boards[i], cmd = m.boards[i].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) isOver() bool {
+	return m.results[0] != nil
+}
+
+func (m Model) View() string {
+	if m.isOver() {
+		return m.resultsView()
+	}
+
+	views := make([]string, len(m.boards))
+	for i := range m.boards {
+		label := m.styles.Inactive.Render(fmt.Sprintf("Board %d", i+1))
+		if i == m.active {
+			label = m.styles.Active.Render(fmt.Sprintf("Board %d (active)", i+1))
+		}
+		views[i] = label + "\n" + m.boards[i].View()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views[0], m.styles.Gap.Render(""), views[1]) + "\n" +
+		m.help.View(m.keys)
+}
+
+func (m Model) resultsView() string {
+	title := fmt.Sprintf("Board %d topped out", m.toppedOut+1)
+
+	a, b := m.results[0], m.results[1]
+	rows := []struct {
+		name        string
+		a, b, total string
+	}{
+		{"Score", fmt.Sprint(a.Score), fmt.Sprint(b.Score), fmt.Sprint(a.Score + b.Score)},
+		{"Lines", fmt.Sprint(a.Lines), fmt.Sprint(b.Lines), fmt.Sprint(a.Lines + b.Lines)},
+		{"Pieces", fmt.Sprint(a.Pieces), fmt.Sprint(b.Pieces), fmt.Sprint(a.Pieces + b.Pieces)},
+		{"Level", fmt.Sprint(a.Level), fmt.Sprint(b.Level), ""},
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%-10s%12s%12s%12s\n", "", "Board 1", "Board 2", "Total"))
+	for _, r := range rows {
+		output.WriteString(fmt.Sprintf("%-10s%12s%12s%12s\n", r.name, r.a, r.b, r.total))
+	}
+	output.WriteString(fmt.Sprintf("\nTime: %s\n", a.Time.Round(time.Millisecond)))
+
+	return m.styles.Title.Render(title) + "\n" +
+		m.styles.Results.Render(output.String()) + "\n" +
+		m.help.View(m.keys)
+}
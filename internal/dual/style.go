@@ -0,0 +1,33 @@
+package dual
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Gap      lipgloss.Style
+	Active   lipgloss.Style // the label above the board being controlled
+	Inactive lipgloss.Style // the label above the other board
+	Title    lipgloss.Style
+	Results  lipgloss.Style
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for the boards and results with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
+	s := Styles{
+		Gap:      lipgloss.NewStyle().Width(4),
+		Active:   lipgloss.NewStyle().Bold(true).Foreground(t.Accent).Padding(0, 2),
+		Inactive: lipgloss.NewStyle().Foreground(t.Muted).Padding(0, 2),
+		Title:    lipgloss.NewStyle().Bold(true).Foreground(t.Text).Padding(1, 2, 0),
+		Results:  lipgloss.NewStyle().Foreground(t.Text).Padding(1, 2),
+	}
+	return &s
+}
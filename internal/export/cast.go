@@ -0,0 +1,56 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// castHeader is the first line of an asciinema cast (version 2), describing the recording.
+type castHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// WriteCast writes the frames as an asciinema cast, to play back with asciinema or share on asciinema.org. Each frame
+// redraws the screen from the top left corner.
+func WriteCast(w io.Writer, frames []Frame, r *Renderer, title string, date time.Time) error {
+	if len(frames) == 0 {
+		return errors.New("no frames to write")
+	}
+	header := castHeader{Version: 2, Title: title}
+	if !date.IsZero() {
+		header.Timestamp = date.Unix()
+	}
+	views := make([]string, len(frames))
+	for i, f := range frames {
+		views[i] = r.Render(f)
+		header.Width = max(header.Width, lipgloss.Width(views[i]))
+		header.Height = max(header.Height, lipgloss.Height(views[i]))
+	}
+
+	enc := json.NewEncoder(w)
+	err := enc.Encode(header)
+	if err != nil {
+		return fmt.Errorf("failed to write cast header: %w", err)
+	}
+	for i, view := range views {
+		output := "\x1b[H" + strings.ReplaceAll(view, "\n", "\x1b[K\r\n")
+		if i == 0 {
+			output = "\x1b[2J" + output
+		}
+		err = enc.Encode([]any{frames[i].Time.Seconds(), "o", output})
+		if err != nil {
+			return fmt.Errorf("failed to write cast frame: %w", err)
+		}
+	}
+	return nil
+}
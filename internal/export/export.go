@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+)
+
+// Write exports the replay to the file at the path, as an animated GIF if its extension is ".gif" or as an asciinema
+// cast otherwise (usually ".cast"). Any file there already is replaced.
+func Write(path string, r *replay.Replay, t *theme.Theme) error {
+	frames := Frames(r)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if strings.EqualFold(filepath.Ext(path), ".gif") {
+		err = WriteGIF(w, frames, t)
+	} else {
+		err = WriteCast(w, frames, NewRenderer(t), "tetrigo: "+r.Mode, r.Date)
+	}
+	if err != nil {
+		return err
+	}
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,151 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// testReplay returns a replay of two I tetriminos laid flat along the bottom of a matrix 4 columns wide, each clearing
+// a line, then an O.
+func testReplay() *replay.Replay {
+	row := func(y int) []tetris.Coordinate {
+		return []tetris.Coordinate{{X: 0, Y: y}, {X: 1, Y: y}, {X: 2, Y: y}, {X: 3, Y: y}}
+	}
+	return &replay.Replay{
+		Mode:   "Marathon",
+		Width:  4,
+		Height: 6,
+		Placements: []replay.Placement{
+			{Piece: "I", Cells: row(11), Time: time.Second},
+			{Piece: "I", Cells: row(10), Time: 2 * time.Second},
+			{Piece: "O", Cells: []tetris.Coordinate{{X: 0, Y: 10}, {X: 1, Y: 10}, {X: 0, Y: 11}, {X: 1, Y: 11}}, Time: 4 * time.Second},
+		},
+	}
+}
+
+func TestFrames(t *testing.T) {
+	r := testReplay()
+	r.Placements[0].Cells = r.Placements[0].Cells[:3]
+
+	frames := Frames(r)
+
+	// The start, each placement, and one after the second placement's line is cleared.
+	wantTimes := []time.Duration{0, time.Second, 2 * time.Second, 2*time.Second + clearPause, 4 * time.Second}
+	if len(frames) != len(wantTimes) {
+		t.Fatalf("want %d frames, got %d", len(wantTimes), len(frames))
+	}
+	for i, f := range frames {
+		if f.Time != wantTimes[i] {
+			t.Errorf("Frame %d: want time %v, got %v", i, wantTimes[i], f.Time)
+		}
+	}
+	if frames[2].Matrix[11][0] != 'I' || frames[2].Matrix[10][0] != 'I' || frames[2].Lines != 0 {
+		t.Errorf("want both I tetriminos before the line is cleared, got %v", frames[2].Matrix)
+	}
+	if frames[3].Matrix[10][0] != 0 || frames[3].Matrix[11][3] != 0 || frames[3].Lines != 1 {
+		t.Errorf("want the completed line removed, got %v", frames[3].Matrix)
+	}
+	if frames[4].Pieces != 3 {
+		t.Errorf("want 3 pieces, got %d", frames[4].Pieces)
+	}
+}
+
+func TestFrames_Board(t *testing.T) {
+	r := testReplay()
+	r.Board = tetris.NewMatrix(4, 6)
+	r.Board[11][3] = tetris.GarbageValue
+
+	frames := Frames(r)
+
+	if frames[0].Matrix[11][3] != tetris.GarbageValue {
+		t.Errorf("want the first frame to start from the board, got %v", frames[0].Matrix)
+	}
+	if r.Board[11][0] != 0 {
+		t.Errorf("want the replay's board unchanged, got %v", r.Board)
+	}
+}
+
+func TestFrames_Garbage(t *testing.T) {
+	r := testReplay()
+	r.Garbage = []replay.Garbage{{After: 1, Time: 1500 * time.Millisecond, Holes: []int{2}}}
+
+	frames := Frames(r)
+
+	// The start, the first placement and its line cleared, then the garbage rising before the second placement.
+	f := frames[3]
+	if f.Time != 1500*time.Millisecond || f.Pieces != 1 {
+		t.Fatalf("want the garbage shown at 1.5s after 1 piece, got %v after %d", f.Time, f.Pieces)
+	}
+	if f.Matrix[11][0] != tetris.GarbageValue || f.Matrix[11][2] != 0 {
+		t.Errorf("want a line of garbage with a hole in column 2, got %v", f.Matrix)
+	}
+}
+
+func TestWriteCast(t *testing.T) {
+	var buf bytes.Buffer
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	err := WriteCast(&buf, Frames(testReplay()), NewRenderer(nil), "tetrigo", date)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(nil, 1<<20)
+	scanner.Scan()
+	var header castHeader
+	err = json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if header.Version != 2 || header.Timestamp != date.Unix() || header.Width == 0 || header.Height != 6+2+1 {
+		t.Errorf("want a version 2 header for the board, got %+v", header)
+	}
+
+	var events int
+	for scanner.Scan() {
+		var event []any
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		if len(event) != 3 || event[1] != "o" {
+			t.Errorf("want an output event, got %v", event)
+		}
+		events++
+	}
+	if events != 6 {
+		t.Errorf("want 6 events, got %d", events)
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteGIF(&buf, Frames(testReplay()), nil)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if len(anim.Image) != 6 {
+		t.Fatalf("want 6 frames, got %d", len(anim.Image))
+	}
+	if anim.Delay[0] != 100 || anim.Delay[5] != 300 {
+		t.Errorf("want delays of 100 and 300, got %v", anim.Delay)
+	}
+	bounds := anim.Image[0].Bounds()
+	if bounds.Dx() != 4*cellPixels+1 || bounds.Dy() != 6*cellPixels+1 {
+		t.Errorf("want a cell for each visible cell of the board, got %v", bounds)
+	}
+}
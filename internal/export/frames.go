@@ -0,0 +1,92 @@
+// Package export turns replays into recordings which can be shared, such as asciinema casts and animated GIFs. Each
+// placement of the replay is drawn as a frame, without needing a terminal.
+package export
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// clearPause is how long a placement which clears lines is shown before the lines are removed, unless the next
+// placement comes sooner.
+const clearPause = 200 * time.Millisecond
+
+// Frame is the board at a point in a game.
+type Frame struct {
+	Time   time.Duration // time played when the frame is shown
+	Matrix tetris.Matrix
+	Pieces int  // tetriminos placed so far
+	Lines  uint // lines cleared so far
+}
+
+// Frames plays the replay back as a frame for the start of the game and for each placement, with another after each
+// placement which clears lines to show them removed, and another each time garbage rises.
+func Frames(r *replay.Replay) []Frame {
+	matrix := tetris.NewMatrix(r.Width, r.Height)
+	if r.Board != nil {
+		matrix = r.Board.Clone()
+	}
+	frames := []Frame{{Matrix: matrix.Clone()}}
+
+	var lines uint
+	garbage := r.Garbage
+	// raise adds the garbage which rose after the given number of placements, shown no sooner than the frame before.
+	raise := func(placed int) {
+		for len(garbage) > 0 && garbage[0].After <= placed {
+			garbage[0].Raise(matrix)
+			at := max(garbage[0].Time, frames[len(frames)-1].Time)
+			frames = append(frames, Frame{Time: at, Matrix: matrix.Clone(), Pieces: placed, Lines: lines})
+			garbage = garbage[1:]
+		}
+	}
+	for i, p := range r.Placements {
+		raise(i)
+		for _, c := range p.Cells {
+			if c.Y >= 0 && c.Y < len(matrix) && c.X >= 0 && c.X < len(matrix[c.Y]) && len(p.Piece) == 1 {
+				matrix[c.Y][c.X] = p.Piece[0]
+			}
+		}
+		frames = append(frames, Frame{Time: p.Time, Matrix: matrix.Clone(), Pieces: i + 1, Lines: lines})
+
+		cleared := clearLines(matrix)
+		if cleared == 0 {
+			continue
+		}
+		lines += uint(cleared)
+		at := p.Time + clearPause
+		if i+1 < len(r.Placements) {
+			at = min(at, r.Placements[i+1].Time)
+		}
+		frames = append(frames, Frame{Time: at, Matrix: matrix.Clone(), Pieces: i + 1, Lines: lines})
+	}
+	raise(len(r.Placements))
+	return frames
+}
+
+// clearLines removes the complete rows of the matrix, moving the rows above them down. It returns the number removed.
+func clearLines(matrix tetris.Matrix) int {
+	var cleared int
+	for row := range matrix {
+		if !isComplete(matrix[row]) {
+			continue
+		}
+		removed := matrix[row]
+		copy(matrix[1:row+1], matrix[:row])
+		clear(removed)
+		matrix[0] = removed
+		cleared++
+	}
+	return cleared
+}
+
+// isComplete reports whether every cell of the row is filled.
+func isComplete(row []byte) bool {
+	for _, cell := range row {
+		if cell == 0 {
+			return false
+		}
+	}
+	return true
+}
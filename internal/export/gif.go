@@ -0,0 +1,104 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const (
+	// cellPixels is the width and height of each cell of the board in GIFs.
+	cellPixels = 12
+
+	// lastFrameDelay is how long the last frame of a GIF is shown before it loops.
+	lastFrameDelay = 3 * time.Second
+)
+
+// Colours of the background and grid lines in GIFs, and of cells whose colour the theme leaves to the terminal.
+var (
+	backgroundColour = color.RGBA{0x10, 0x10, 0x18, 0xFF}
+	gridColour       = color.RGBA{0x30, 0x30, 0x40, 0xFF}
+	cellColour       = color.RGBA{0xC0, 0xC0, 0xC0, 0xFF}
+)
+
+// WriteGIF writes the frames as an animated GIF of the visible rows of the board, coloured with the given theme (nil
+// for the default). Each frame is shown until the time of the next.
+func WriteGIF(w io.Writer, frames []Frame, t *theme.Theme) error {
+	if len(frames) == 0 {
+		return errors.New("no frames to write")
+	}
+	if t == nil {
+		t = theme.Default()
+	}
+
+	palette := color.Palette{backgroundColour, toRGBA(t.Grid, gridColour), cellColour}
+	indexes := make(map[byte]uint8, len(t.Tetriminos))
+	for value, colour := range t.Tetriminos {
+		indexes[value] = uint8(len(palette))
+		palette = append(palette, toRGBA(colour, cellColour))
+	}
+
+	anim := &gif.GIF{}
+	for i, f := range frames {
+		anim.Image = append(anim.Image, drawFrame(f, palette, indexes))
+		delay := lastFrameDelay
+		if i+1 < len(frames) {
+			delay = frames[i+1].Time - f.Time
+		}
+		// Delays are in hundredths of a second.
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+	err := gif.EncodeAll(w, anim)
+	if err != nil {
+		return fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return nil
+}
+
+// drawFrame draws the visible rows of the frame's board, with each cell outlined by the grid.
+func drawFrame(f Frame, palette color.Palette, indexes map[byte]uint8) *image.Paletted {
+	rows := f.Matrix.Visible()
+	img := image.NewPaletted(image.Rect(0, 0, len(rows[0])*cellPixels+1, len(rows)*cellPixels+1), palette)
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			if x%cellPixels == 0 || y%cellPixels == 0 {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	for row := range rows {
+		for col, cell := range rows[row] {
+			if cell == 0 {
+				continue
+			}
+			index, ok := indexes[cell]
+			if !ok {
+				index = 2
+			}
+			for y := row*cellPixels + 1; y < (row+1)*cellPixels; y++ {
+				for x := col*cellPixels + 1; x < (col+1)*cellPixels; x++ {
+					img.SetColorIndex(x, y, index)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// toRGBA converts a colour of the theme, or returns the fallback if it is left to the terminal.
+func toRGBA(c lipgloss.Color, fallback color.RGBA) color.RGBA {
+	tc := termenv.TrueColor.Color(string(c))
+	if tc == nil {
+		return fallback
+	}
+	r, g, b := termenv.ConvertToRGB(tc).RGB255()
+	return color.RGBA{r, g, b, 0xFF}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Renderer draws frames as text coloured with ANSI escape codes, the same for every output rather than depending on
+// the terminal the export is run in.
+type Renderer struct {
+	cells  map[byte]lipgloss.Style
+	empty  lipgloss.Style
+	board  lipgloss.Style
+	filled string
+	blank  string
+}
+
+// NewRenderer creates a renderer which draws frames with the given theme (nil for the default).
+func NewRenderer(t *theme.Theme) *Renderer {
+	if t == nil {
+		t = theme.Default()
+	}
+	lg := lipgloss.NewRenderer(io.Discard)
+	lg.SetColorProfile(termenv.TrueColor)
+
+	r := &Renderer{
+		cells:  make(map[byte]lipgloss.Style, len(t.Tetriminos)),
+		empty:  lg.NewStyle().Foreground(t.Grid),
+		board:  lg.NewStyle().Border(t.Border),
+		filled: "██",
+		blank:  "▕ ",
+	}
+	if t.ASCII {
+		r.filled, r.blank = "[]", "| "
+	}
+	for value, colour := range t.Tetriminos {
+		r.cells[value] = lg.NewStyle().Foreground(colour)
+	}
+	return r
+}
+
+// Render draws the visible rows of the frame's board, with the pieces, lines and time played beneath it.
+func (r *Renderer) Render(f Frame) string {
+	var rows []string
+	for _, row := range f.Matrix.Visible() {
+		var line strings.Builder
+		for _, cell := range row {
+			style, ok := r.cells[cell]
+			switch {
+			case cell == 0:
+				line.WriteString(r.empty.Render(r.blank))
+			case ok:
+				line.WriteString(style.Render(r.filled))
+			default:
+				line.WriteString(r.filled)
+			}
+		}
+		rows = append(rows, line.String())
+	}
+	caption := fmt.Sprintf("Pieces %d  Lines %d  %s", f.Pieces, f.Lines, formatTime(f.Time))
+	return r.board.Render(strings.Join(rows, "\n")) + "\n" + caption
+}
+
+// formatTime formats the time played as minutes, seconds and hundredths, such as "01:23.45".
+func formatTime(d time.Duration) string {
+	d = d.Round(10 * time.Millisecond)
+	return fmt.Sprintf("%02d:%05.2f", int(d.Minutes()), (d % time.Minute).Seconds())
+}
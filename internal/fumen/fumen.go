@@ -0,0 +1,364 @@
+// Package fumen encodes and decodes boards in the fumen format (https://fumen.zui.jp), which the community uses to
+// share setups, so that they can be exchanged with other tools.
+//
+// Only the first page of a fumen is read or written. Its field is the visible part of the board, 23 rows tall, and
+// the queue of a quiz is kept in its comment:
+//
+//	#Q=[hold](current)next
+//
+// Pieces drawn on a page rather than into its field, and the garbage row below the field, are ignored.
+package fumen
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Page is the first page of a fumen.
+type Page struct {
+	Matrix  tetris.Matrix
+	Comment string
+}
+
+const (
+	prefix = "v115@"
+
+	width      = 10
+	height     = 23                   // rows of the field, excluding the garbage row
+	fieldCells = (height + 1) * width // including the garbage row
+)
+
+// digits are the characters each base 64 digit of the encoding is written with, from 0 to 63.
+const digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// commentChars are the characters of escaped comments, written four to a value in base 96.
+const commentChars = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// pieces are the values of the tetriminos for each piece number used by fumen, where 0 is empty and 8 is garbage.
+var pieces = []byte{0, 'I', 'L', 'O', 'Z', 'T', 'J', 'S', tetris.GarbageValue}
+
+// Flags of a page, stored with its piece.
+const (
+	flagColour  = 4 // the guideline colours are used (only read on the first page)
+	flagComment = 8 // a comment follows
+)
+
+// Encode returns the fumen of the page, where a matrix with no rows is an empty field. The matrix must be 10 cells
+// wide, and the stack must fit in the 23 rows of the field.
+func Encode(p Page) (string, error) {
+	if len(p.Matrix) > 0 && len(p.Matrix[0]) != width {
+		return "", fmt.Errorf("matrix must be %d cells wide to fit a fumen", width)
+	}
+	top := len(p.Matrix) - height
+	for row := 0; row < top; row++ {
+		for col := range p.Matrix[row] {
+			if !p.Matrix.IsCellEmpty(row, col) {
+				return "", fmt.Errorf("stack is higher than the %d rows of a fumen", height)
+			}
+		}
+	}
+
+	var v values
+
+	// Cells are written from the top row down, as the difference from the previous page (here, an empty field) offset by
+	// 8, with each run of the same difference packed into two digits.
+	cells := make([]int, fieldCells)
+	for i := 0; i < height*width; i++ {
+		// The rows above a short matrix are left empty.
+		if top+i/width < 0 {
+			continue
+		}
+		cell, err := pieceNumber(p.Matrix[top+i/width][i%width])
+		if err != nil {
+			return "", err
+		}
+		cells[i] = cell
+	}
+	for i := 0; i < len(cells); {
+		run := 1
+		for i+run < len(cells) && cells[i+run] == cells[i] {
+			run++
+		}
+		v.push((cells[i]+8)*fieldCells+run-1, 2)
+		i += run
+	}
+	if cells[0] == 0 && len(v) == 2 {
+		// An unchanged field is followed by the number of the pages after it which are also unchanged.
+		v.push(0, 1)
+	}
+
+	// No piece is drawn on the page, so only its flags are set.
+	flags := flagColour
+	if p.Comment != "" {
+		flags |= flagComment
+	}
+	v.push(flags*fieldCells*32, 3)
+
+	if p.Comment != "" {
+		escaped := escape(p.Comment)
+		if len(escaped) >= 64*64 {
+			return "", errors.New("comment is too long")
+		}
+		v.push(len(escaped), 2)
+		for i := 0; i < len(escaped); i += 4 {
+			value, scale := 0, 1
+			for _, c := range escaped[i:min(i+4, len(escaped))] {
+				value += strings.IndexRune(commentChars, c) * scale
+				scale *= 96
+			}
+			v.push(value, 5)
+		}
+	}
+
+	// The encoding is broken up by question marks, as the fumen editor writes it.
+	data := v.String()
+	var b strings.Builder
+	b.WriteString(prefix)
+	for first := true; data != ""; first = false {
+		n := 47
+		if first {
+			n = 42
+		} else {
+			b.WriteByte('?')
+		}
+		n = min(n, len(data))
+		b.WriteString(data[:n])
+		data = data[n:]
+	}
+	return b.String(), nil
+}
+
+// Decode reads the first page of the fumen, which may be given as a link to the fumen editor.
+func Decode(s string) (Page, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "?"); strings.Contains(s, "://") && i >= 0 {
+		s = s[i+1:]
+	}
+	if len(s) < len(prefix) || s[1:len(prefix)] != prefix[1:] || !strings.ContainsRune("vmd", rune(s[0])) {
+		return Page{}, fmt.Errorf("invalid fumen: must start with %q", prefix)
+	}
+
+	r := reader{data: strings.ReplaceAll(s[len(prefix):], "?", "")}
+	p := Page{Matrix: tetris.NewDefaultMatrix()}
+
+	top := len(p.Matrix) - height
+	unchanged := false
+	for i := 0; i < fieldCells; {
+		value, err := r.next(2)
+		if err != nil {
+			return Page{}, err
+		}
+		diff, run := value/fieldCells-8, value%fieldCells+1
+		if diff < 0 || diff >= len(pieces) || i+run > fieldCells {
+			return Page{}, errors.New("invalid fumen: invalid field")
+		}
+		unchanged = diff == 0 && run == fieldCells
+		for ; run > 0; run-- {
+			if i < height*width {
+				p.Matrix[top+i/width][i%width] = pieces[diff]
+			}
+			i++
+		}
+	}
+	if unchanged {
+		_, err := r.next(1)
+		if err != nil {
+			return Page{}, err
+		}
+	}
+
+	action, err := r.next(3)
+	if err != nil {
+		return Page{}, err
+	}
+	if action/(fieldCells*32)&flagComment == 0 {
+		return p, nil
+	}
+
+	length, err := r.next(2)
+	if err != nil {
+		return Page{}, err
+	}
+	var escaped strings.Builder
+	for n := 0; n < length; n += 4 {
+		value, err := r.next(5)
+		if err != nil {
+			return Page{}, err
+		}
+		for i := n; i < min(n+4, length); i++ {
+			escaped.WriteByte(commentChars[value%96])
+			value /= 96
+		}
+	}
+	p.Comment, err = unescape(escaped.String())
+	if err != nil {
+		return Page{}, fmt.Errorf("invalid fumen: invalid comment: %w", err)
+	}
+	return p, nil
+}
+
+// pieceNumber returns the number fumen uses for the value of a cell.
+func pieceNumber(value byte) (int, error) {
+	if value == 'G' {
+		return 0, nil // ghost cells are empty
+	}
+	for i, v := range pieces {
+		if v == value {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid cell value %q", value)
+}
+
+// values are the base 64 digits of an encoding.
+type values []byte
+
+// push appends the value as n digits, least significant first.
+func (v *values) push(value, n int) {
+	for ; n > 0; n-- {
+		*v = append(*v, digits[value%64])
+		value /= 64
+	}
+}
+
+func (v values) String() string {
+	return string(v)
+}
+
+// reader reads the base 64 digits of an encoding.
+type reader struct {
+	data string
+}
+
+// next reads a value written as n digits, least significant first.
+func (r *reader) next(n int) (int, error) {
+	if len(r.data) < n {
+		return 0, errors.New("invalid fumen: unexpected end of data")
+	}
+	value, scale := 0, 1
+	for _, c := range r.data[:n] {
+		d := strings.IndexRune(digits, c)
+		if d < 0 {
+			return 0, fmt.Errorf("invalid fumen: invalid character %q", c)
+		}
+		value += d * scale
+		scale *= 64
+	}
+	r.data = r.data[n:]
+	return value, nil
+}
+
+// escape escapes the comment as JavaScript's escape function does, which fumen uses before encoding comments.
+func escape(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c < utf8.RuneSelf && (isAlphanumeric(byte(c)) || strings.ContainsRune("@*_+-./", c)):
+			b.WriteRune(c)
+		case c < 256:
+			fmt.Fprintf(&b, "%%%02X", c)
+		default:
+			fmt.Fprintf(&b, "%%u%04X", c)
+		}
+	}
+	return b.String()
+}
+
+// unescape reverses escape.
+func unescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+		n := 2
+		if strings.HasPrefix(s[i+1:], "u") {
+			i++
+			n = 4
+		}
+		if i+n >= len(s) {
+			return "", errors.New("incomplete escape sequence")
+		}
+		c, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid escape sequence %q", s[i:i+1+n])
+		}
+		b.WriteRune(rune(c))
+		i += n
+	}
+	return b.String(), nil
+}
+
+func isAlphanumeric(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// Quiz is the queue of tetriminos of a quiz, where the player places them in order, swapping with the held one if
+// they choose. Hold and Current are 0 for none.
+type Quiz struct {
+	Hold    byte
+	Current byte
+	Next    []byte
+}
+
+// ParseQuiz reads the quiz from a page's comment, and reports false if the page isn't a quiz.
+func ParseQuiz(comment string) (Quiz, bool, error) {
+	rest, ok := strings.CutPrefix(comment, "#Q=")
+	if !ok {
+		return Quiz{}, false, nil
+	}
+
+	var q Quiz
+	var err error
+	if q.Hold, rest, err = cutSlot(rest, '[', ']'); err != nil {
+		return Quiz{}, true, fmt.Errorf("invalid quiz: hold: %w", err)
+	}
+	if q.Current, rest, err = cutSlot(rest, '(', ')'); err != nil {
+		return Quiz{}, true, fmt.Errorf("invalid quiz: current: %w", err)
+	}
+	// Anything after the queue, such as a description after a semicolon, is not part of it.
+	rest, _, _ = strings.Cut(rest, ";")
+	for _, c := range []byte(strings.TrimSpace(rest)) {
+		if !isTetrimino(c) {
+			return Quiz{}, true, fmt.Errorf("invalid quiz: queue has invalid tetrimino %q", c)
+		}
+		q.Next = append(q.Next, c)
+	}
+	return q, true, nil
+}
+
+// cutSlot reads a slot of the quiz holding at most one tetrimino, such as "[I]" or "()", and returns the rest.
+func cutSlot(s string, open, close byte) (byte, string, error) {
+	end := strings.IndexByte(s, close)
+	if len(s) == 0 || s[0] != open || end < 0 || end > 2 {
+		return 0, "", fmt.Errorf("must be a tetrimino between %c and %c", open, close)
+	}
+	if end == 1 {
+		return 0, s[end+1:], nil
+	}
+	if !isTetrimino(s[1]) {
+		return 0, "", fmt.Errorf("invalid tetrimino %q", s[1])
+	}
+	return s[1], s[end+1:], nil
+}
+
+func isTetrimino(value byte) bool {
+	return value != 0 && value != tetris.GarbageValue && strings.IndexByte(string(pieces), value) >= 0
+}
+
+// String returns the quiz as a page's comment.
+func (q Quiz) String() string {
+	slot := func(v byte) string {
+		if v == 0 {
+			return ""
+		}
+		return string(v)
+	}
+	return fmt.Sprintf("#Q=[%s](%s)%s", slot(q.Hold), slot(q.Current), q.Next)
+}
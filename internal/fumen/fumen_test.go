@@ -0,0 +1,232 @@
+package fumen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestEncode(t *testing.T) {
+	tt := map[string]struct {
+		page    Page
+		want    string
+		wantErr bool
+	}{
+		"empty": {
+			want: "v115@vhAAgH",
+		},
+		"garbage": {
+			page: Page{Matrix: mustParseMatrix(t, "XXXXXX....\nXXXXXX....\nXXXXXX....\nXXXXXX....")},
+			want: "v115@9gF8DeF8DeF8DeF8NeAgH",
+		},
+		"ghost cells are empty": {
+			page: Page{Matrix: ghost(mustParseMatrix(t, "XXXXXX....\nXXXXXX....\nXXXXXX....\nXXXXXX...."))},
+			want: "v115@9gF8DeF8DeF8DeF8NeAgH",
+		},
+		"quiz": {
+			page: Page{Comment: "#Q=[](T)ZSOJLI"},
+			want: "v115@vhAAgWaAFLDmClcJSAVDEHBEooRBUoAVBa9aPCM+AA?A",
+		},
+		"too high": {
+			page:    Page{Matrix: fill(16)},
+			wantErr: true,
+		},
+		"invalid cell": {
+			page: Page{Matrix: func() tetris.Matrix {
+				m := tetris.NewDefaultMatrix()
+				m[39][0] = '?'
+				return m
+			}()},
+			wantErr: true,
+		},
+		"wrong width": {
+			page:    Page{Matrix: tetris.NewMatrix(12, tetris.DefaultHeight)},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := Encode(tc.page)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tt := map[string]struct {
+		fumen   string
+		want    Page
+		wantErr bool
+	}{
+		"empty": {
+			fumen: "v115@vhAAgH",
+			want:  Page{Matrix: tetris.NewDefaultMatrix()},
+		},
+		"garbage": {
+			fumen: "v115@9gF8DeF8DeF8DeF8NeAgH",
+			want:  Page{Matrix: mustParseMatrix(t, "XXXXXX....\nXXXXXX....\nXXXXXX....\nXXXXXX....")},
+		},
+		"link": {
+			fumen: "https://fumen.zui.jp/?v115@9gF8DeF8DeF8DeF8NeAgH",
+			want:  Page{Matrix: mustParseMatrix(t, "XXXXXX....\nXXXXXX....\nXXXXXX....\nXXXXXX....")},
+		},
+		"quiz": {
+			fumen: "v115@vhAAgWaAFLDmClcJSAVDEHBEooRBUoAVBa9aPCM+AAA",
+			want:  Page{Matrix: tetris.NewDefaultMatrix(), Comment: "#Q=[](T)ZSOJLI"},
+		},
+		"no prefix": {
+			fumen:   "vhAAgH",
+			wantErr: true,
+		},
+		"invalid character": {
+			fumen:   "v115@vh!AgH",
+			wantErr: true,
+		},
+		"truncated": {
+			fumen:   "v115@9gF8DeF8",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := Decode(tc.fumen)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	page := Page{
+		Matrix:  mustParseMatrix(t, "I.........\nIO.T.....J\nIOOTTS..JJ\nIZZTSSLXXX"),
+		Comment: "#Q=[I](T)SZ; a comment with ~ and é",
+	}
+	fumen, err := Encode(page)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	got, err := Decode(fumen)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, page) {
+		t.Errorf("want %+v, got %+v", page, got)
+	}
+}
+
+func TestParseQuiz(t *testing.T) {
+	tt := map[string]struct {
+		comment  string
+		want     Quiz
+		wantQuiz bool
+		wantErr  bool
+	}{
+		"not a quiz": {
+			comment: "just a comment",
+		},
+		"no hold": {
+			comment:  "#Q=[](L)ZSOIJT",
+			want:     Quiz{Current: 'L', Next: []byte("ZSOIJT")},
+			wantQuiz: true,
+		},
+		"hold": {
+			comment:  "#Q=[I](T)SZ",
+			want:     Quiz{Hold: 'I', Current: 'T', Next: []byte("SZ")},
+			wantQuiz: true,
+		},
+		"description": {
+			comment:  "#Q=[](O)JL; build a TSD",
+			want:     Quiz{Current: 'O', Next: []byte("JL")},
+			wantQuiz: true,
+		},
+		"invalid hold": {
+			comment:  "#Q=[IO](T)",
+			wantQuiz: true,
+			wantErr:  true,
+		},
+		"invalid queue": {
+			comment:  "#Q=[](T)SX",
+			wantQuiz: true,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, ok, err := ParseQuiz(tc.comment)
+			if ok != tc.wantQuiz {
+				t.Errorf("quiz: want %t, got %t", tc.wantQuiz, ok)
+			}
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want %+v, got %+v", tc.want, got)
+			}
+			if ok && got.String() != tc.comment && name != "description" {
+				t.Errorf("String: want %q, got %q", tc.comment, got.String())
+			}
+		})
+	}
+}
+
+func mustParseMatrix(t *testing.T, text string) tetris.Matrix {
+	t.Helper()
+	matrix, err := tetris.ParseMatrix(text)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	return matrix
+}
+
+// fill returns a matrix with the given row, and every row below it, full of garbage.
+func fill(row int) tetris.Matrix {
+	matrix := tetris.NewDefaultMatrix()
+	for ; row < len(matrix); row++ {
+		for col := range matrix[row] {
+			matrix[row][col] = tetris.GarbageValue
+		}
+	}
+	return matrix
+}
+
+// ghost fills the empty cells of the bottom row of the matrix with a ghost tetrimino.
+func ghost(matrix tetris.Matrix) tetris.Matrix {
+	row := len(matrix) - 1
+	for col := range matrix[row] {
+		if matrix[row][col] == 0 {
+			matrix[row][col] = 'G'
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,64 @@
+// Package importer converts replays saved by other clients into tetrigo's replays, so that games played elsewhere can
+// be watched, analysed and exported like those played in tetrigo.
+//
+// Other clients record the keys pressed rather than where each tetrimino locked, so their games are played again by
+// their own rules: the order tetriminos are dealt in, how they rotate and kick, and the handling and gravity the game
+// was played with. Games played by rules which can't be followed, such as other randomizers, or which received garbage
+// from other players, are refused with ErrUnsupported rather than imported wrongly.
+//
+// TETR.IO replays (.ttr and .ttrm) are imported, following SRS for both of its kick sets. Half turns are only made in
+// place, and the I kicks of SRS+ are those of SRS, so games relying on the kicks SRS+ adds diverge; single player
+// games are checked against the number of tetriminos they placed to catch this. Jstris replays are recognised, but
+// refused with ErrUnsupported, since how Jstris packs the inputs it records and deals its tetriminos isn't followed.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+)
+
+// ErrUnsupported is returned, wrapped, when a replay was played by rules tetrigo can't follow to import it.
+var ErrUnsupported = errors.New("replay is not supported")
+
+// Options choose which game is imported from a replay of several, such as a multiplayer match.
+type Options struct {
+	Round  int // round of the match, from 1 (0 for the first)
+	Player int // player in the round, from 1 (0 for the first)
+}
+
+// Read imports the replay in the file at the path, recognising which client saved it from its contents.
+func Read(path string, opts Options) (*replay.Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %w", err)
+	}
+	return Import(data, opts)
+}
+
+// Import converts the replay, recognising which client saved it from its contents.
+func Import(data []byte, opts Options) (*replay.Replay, error) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %w", err)
+	}
+
+	_, hasData := fields["data"]
+	_, hasEnd := fields["endcontext"]
+	_, hasRounds := fields["replay"]
+	_, hasConfig := fields["c"]
+	_, hasActions := fields["d"]
+	switch {
+	case hasData && hasEnd:
+		return importTETRIO(data, opts)
+	case hasRounds:
+		return nil, fmt.Errorf("%w: replays saved by newer versions of TETR.IO can't be imported yet", ErrUnsupported)
+	case hasConfig && hasActions:
+		return nil, fmt.Errorf("%w: Jstris replays can't be imported, since their inputs and randomizer aren't followed", ErrUnsupported)
+	}
+	return nil, errors.New("replay was not saved by a client which can be imported from (TETR.IO or Jstris)")
+}
@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// ttrFile returns a single player TETR.IO replay of the events, each a type, frame and key, played with the options.
+func ttrFile(t *testing.T, options string, end string, events ...[3]string) []byte {
+	t.Helper()
+	lines := []string{fmt.Sprintf(`{"frame":0,"type":"full","data":{"options":%s}}`, options)}
+	for _, e := range events {
+		lines = append(lines, fmt.Sprintf(`{"frame":%s,"type":%q,"data":{"key":%q}}`, e[1], e[0], e[2]))
+	}
+	data := fmt.Sprintf(`{"ismulti":false,"ts":"2024-01-02T03:04:05Z","endcontext":%s,"data":{"frames":100,"events":[%s]}}`,
+		end, strings.Join(lines, ","))
+	if !json.Valid([]byte(data)) {
+		t.Fatalf("invalid replay: %s", data)
+	}
+	return []byte(data)
+}
+
+// bag returns the tetriminos TETR.IO deals from the seed, in order.
+func bag(seed int64, n int) []byte {
+	b := tetris.NewRandomizedBag(tetris.NewDefaultMatrix(), newTETRIORNG(seed))
+	dealt := make([]byte, n)
+	for i := range dealt {
+		dealt[i] = b.Next().Value
+	}
+	return dealt
+}
+
+// columns returns the leftmost and rightmost columns of the cells, and their lowest row.
+func columns(cells []tetris.Coordinate) (int, int, int) {
+	left, right, bottom := cells[0].X, cells[0].X, cells[0].Y
+	for _, c := range cells {
+		left, right, bottom = min(left, c.X), max(right, c.X), max(bottom, c.Y)
+	}
+	return left, right, bottom
+}
+
+func TestImport_TETRIO(t *testing.T) {
+	data := ttrFile(t, `{"seed":12345,"bagtype":"7-bag","kickset":"SRS+","handling":{"das":6,"arr":0,"sdf":41}}`,
+		`{"gametype":"40l","piecesplaced":4,"score":0}`,
+		[3]string{"keydown", "10", "hardDrop"},
+		[3]string{"keydown", "20", "moveLeft"},
+		[3]string{"keyup", "21", "moveLeft"},
+		[3]string{"keydown", "22", "hardDrop"},
+		[3]string{"keydown", "30", "moveRight"},
+		[3]string{"keyup", "40", "moveRight"},
+		[3]string{"keydown", "41", "hardDrop"},
+		[3]string{"keydown", "50", "hold"},
+		[3]string{"keydown", "51", "hardDrop"},
+	)
+
+	r, err := Import(data, Options{})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if r.Mode != "TETR.IO (40l)" || r.Seed != 12345 {
+		t.Errorf("want a 40l game with seed 12345, got %q with seed %d", r.Mode, r.Seed)
+	}
+	if len(r.Placements) != 4 {
+		t.Fatalf("want 4 placements, got %d", len(r.Placements))
+	}
+
+	dealt := bag(12345, 5)
+	for i, want := range []byte{dealt[0], dealt[1], dealt[2], dealt[4]} {
+		if r.Placements[i].Piece != string(want) {
+			t.Errorf("Placement %d: want %c, got %s", i+1, want, r.Placements[i].Piece)
+		}
+	}
+	if _, _, bottom := columns(r.Placements[0].Cells); bottom != 2*r.Height-1 {
+		t.Errorf("want the first tetrimino on the floor, got lowest row %d", bottom)
+	}
+	spawned, _ := newBoard(r.Width, r.Height).spawn(dealt[1])
+	spawnLeft, _, _ := columns(spawned.cells())
+	if left, _, _ := columns(r.Placements[1].Cells); left != spawnLeft-1 {
+		t.Errorf("want the second tetrimino tapped left to column %d, got %d", spawnLeft-1, left)
+	}
+	if _, right, _ := columns(r.Placements[2].Cells); right != r.Width-1 {
+		t.Errorf("want the third tetrimino shifted to the right wall, got column %d", right)
+	}
+	if got := r.Placements[3].Inputs; len(got) != 2 || got[0] != tetris.MoveHold.String() {
+		t.Errorf("want a hold then a hard drop, got %v", got)
+	}
+	if r.Placements[0].Drop == 0 {
+		t.Errorf("want points for hard dropping, got 0")
+	}
+}
+
+func TestImport_Unsupported(t *testing.T) {
+	tt := map[string][]byte{
+		"other randomizer": ttrFile(t, `{"seed":1,"bagtype":"14-bag"}`, `{}`),
+		"other kicks":      ttrFile(t, `{"seed":1,"kickset":"ARS"}`, `{}`),
+		"too tall":         ttrFile(t, `{"seed":1,"boardheight":1000000000}`, `{}`),
+		"diverged": ttrFile(t, `{"seed":1}`, `{"gametype":"40l","piecesplaced":3}`,
+			[3]string{"keydown", "10", "hardDrop"}),
+		"garbage": []byte(`{"ismulti":true,"endcontext":[],"data":[{"replays":[{"frames":10,"events":[
+			{"frame":0,"type":"full","data":{"options":{"seed":1}}},
+			{"frame":5,"type":"ige","data":{"type":"ige","data":{"type":"garbage","amt":4,"column":3}}}]}]}]}`),
+		"jstris":       []byte(`{"c":{"v":3.3,"seed":"abc"},"d":"AAAA"}`),
+		"newer tetrio": []byte(`{"id":"1","replay":{"rounds":[]}}`),
+	}
+
+	for name, data := range tt {
+		t.Run(name, func(t *testing.T) {
+			_, err := Import(data, Options{})
+			if !errors.Is(err, ErrUnsupported) {
+				t.Errorf("want %v, got %v", ErrUnsupported, err)
+			}
+		})
+	}
+}
+
+func TestImport_TETRIO_InvalidFrames(t *testing.T) {
+	tt := map[string][]byte{
+		"backwards": ttrFile(t, `{"seed":1}`, `{}`,
+			[3]string{"keydown", "20", "moveLeft"},
+			[3]string{"keyup", "10", "moveLeft"}),
+		"too far apart": ttrFile(t, `{"seed":1}`, `{}`,
+			[3]string{"keydown", "9223372036854775807", "hardDrop"}),
+	}
+
+	for name, data := range tt {
+		t.Run(name, func(t *testing.T) {
+			_, err := Import(data, Options{})
+			if err == nil || errors.Is(err, ErrUnsupported) {
+				t.Errorf("want an invalid replay error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestImport_Unrecognised(t *testing.T) {
+	_, err := Import([]byte(`{"mode":"Marathon"}`), Options{})
+	if err == nil || errors.Is(err, ErrUnsupported) {
+		t.Errorf("want an unrecognised format error, got %v", err)
+	}
+}
+
+func TestImport_TETRIO_Player(t *testing.T) {
+	data := []byte(`{"ismulti":true,"endcontext":[],"data":[{"replays":[
+		{"frames":10,"events":[{"frame":0,"type":"full","data":{"options":{"seed":1}}}]},
+		{"frames":10,"events":[{"frame":0,"type":"full","data":{"options":{"seed":2}}},
+			{"frame":3,"type":"keydown","data":{"key":"hardDrop"}}]}]}]}`)
+
+	r, err := Import(data, Options{Round: 1, Player: 2})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if r.Seed != 2 || len(r.Placements) != 1 {
+		t.Errorf("want the second player's game of one placement, got seed %d with %d", r.Seed, len(r.Placements))
+	}
+
+	_, err = Import(data, Options{Round: 2})
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestPiece_Tetrimino_TSpinDouble(t *testing.T) {
+	b := newBoard(10, 20)
+	bottom := len(b.cells) - 1
+	for col := 0; col < b.width; col++ {
+		if col != 1 {
+			b.cells[bottom][col] = tetris.GarbageValue
+		}
+		if col > 2 {
+			b.cells[bottom-1][col] = tetris.GarbageValue
+		}
+	}
+	b.cells[bottom-2][0] = tetris.GarbageValue
+
+	// The T points down into the slot, having been rotated into it.
+	p := &piece{value: 'T', state: stateTwice, x: 0, y: bottom - 2, rotated: true}
+	if !b.fits(p.cells()) {
+		t.Fatalf("want the T to fit the slot")
+	}
+	tet := p.tetrimino()
+	spin := b.cells.TSpin(tet, p.kick())
+	err := b.cells.AddTetrimino(tet)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	for _, c := range p.cells() {
+		if b.cells[c.Y][c.X] != 'T' {
+			t.Errorf("want the T in the cell at row %d, col %d, got %q", c.Y, c.X, b.cells[c.Y][c.X])
+		}
+	}
+	if action := b.cells.ClearLines(tet, spin); action.String() != "T-SPIN DOUBLE" {
+		t.Errorf("want a T-SPIN DOUBLE, got %q", action)
+	}
+}
+
+func TestBoard_Rotate_Kick(t *testing.T) {
+	b := newBoard(10, 20)
+	p, ok := b.spawn('I')
+	if !ok {
+		t.Fatalf("want the I to spawn")
+	}
+	// Upright against the left wall, the I can only turn back by kicking away from it.
+	if !b.rotate(p, 3) || b.shiftAll(p, -1, 0) == 0 {
+		t.Fatalf("want the I turned upright and moved to the wall")
+	}
+	if !b.rotate(p, 1) {
+		t.Fatalf("want the I to kick off the wall")
+	}
+	if left, _, _ := columns(p.cells()); left != 0 {
+		t.Errorf("want the I lying against the wall, got column %d", left)
+	}
+	if p.lastKick == 0 {
+		t.Errorf("want a kick used, got none")
+	}
+}
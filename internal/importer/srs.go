@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// The rotation states of a tetrimino in SRS: as it spawns, turned clockwise (R), turned twice, and turned
+// counter-clockwise (L).
+const (
+	stateSpawn = iota
+	stateRight
+	stateTwice
+	stateLeft
+)
+
+// shapes are the cells of each tetrimino as it spawns, within the box it rotates in, with rows counted down from the
+// top as in tetrigo's matrix. The I rotates in a box of 4 and the others in a box of 3, where the O fills the middle
+// and right columns so that it spawns in the middle of the matrix with the others.
+var shapes = map[byte]struct {
+	size  int
+	cells []tetris.Coordinate
+}{
+	'I': {4, []tetris.Coordinate{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}},
+	'O': {3, []tetris.Coordinate{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+	'T': {3, []tetris.Coordinate{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+	'S': {3, []tetris.Coordinate{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}},
+	'Z': {3, []tetris.Coordinate{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+	'J': {3, []tetris.Coordinate{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+	'L': {3, []tetris.Coordinate{{X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+}
+
+// piece is the tetrimino being played: its value, rotation state and the top left of the box it rotates in.
+type piece struct {
+	value    byte
+	state    int
+	x, y     int
+	rotated  bool // whether the last thing to move it was a rotation, for T-spins
+	lastKick int  // index of the kick used by the last rotation
+}
+
+// cells returns the cells of the matrix the piece is in.
+func (p *piece) cells() []tetris.Coordinate {
+	return p.cellsAt(p.state, p.x, p.y)
+}
+
+// cellsAt returns the cells of the matrix the piece would be in with the state and position.
+func (p *piece) cellsAt(state, x, y int) []tetris.Coordinate {
+	shape := shapes[p.value]
+	cells := make([]tetris.Coordinate, len(shape.cells))
+	for i, c := range shape.cells {
+		// The O looks the same in every state, so it is never turned.
+		for turn := 0; turn < state && p.value != 'O'; turn++ {
+			c = tetris.Coordinate{X: shape.size - 1 - c.Y, Y: c.X}
+		}
+		cells[i] = tetris.Coordinate{X: x + c.X, Y: y + c.Y}
+	}
+	return cells
+}
+
+// tetrimino returns the piece as one of tetrigo's tetriminos, where it is in the matrix, for the engine to lock.
+func (p *piece) tetrimino() *tetris.Tetrimino {
+	cells := p.cells()
+	left, top, right, bottom := cells[0].X, cells[0].Y, cells[0].X, cells[0].Y
+	for _, c := range cells {
+		left, top, right, bottom = min(left, c.X), min(top, c.Y), max(right, c.X), max(bottom, c.Y)
+	}
+	shape := make([][]bool, bottom-top+1)
+	for row := range shape {
+		shape[row] = make([]bool, right-left+1)
+	}
+	for _, c := range cells {
+		shape[c.Y-top][c.X-left] = true
+	}
+	return &tetris.Tetrimino{
+		Value:           p.value,
+		Cells:           shape,
+		Pos:             tetris.Coordinate{X: left, Y: top},
+		CurrentRotation: p.state,
+	}
+}
+
+// kick returns the index of the kick used by the piece's last rotation, or -1 if it has moved since, as
+// tetris.Matrix.TSpin takes it.
+func (p *piece) kick() int {
+	if !p.rotated {
+		return -1
+	}
+	return p.lastKick
+}
+
+// board is a matrix played on by the rules of another client. Row 0 is the top of the buffer above the visible rows.
+type board struct {
+	cells  tetris.Matrix // the stack, without the tetrimino being moved
+	width  int
+	height int // visible rows, with as many again above them
+}
+
+func newBoard(width, height int) *board {
+	return &board{cells: tetris.NewMatrix(width, height), width: width, height: height}
+}
+
+// fits reports whether each of the cells is inside the matrix and empty.
+func (b *board) fits(cells []tetris.Coordinate) bool {
+	for _, c := range cells {
+		if c.X < 0 || c.X >= b.width || c.Y < 0 || c.Y >= len(b.cells) || !b.cells.IsCellEmpty(c.Y, c.X) {
+			return false
+		}
+	}
+	return true
+}
+
+// spawn returns the tetrimino with the value in the middle of the matrix, just above the visible rows, or false if it
+// is blocked.
+func (b *board) spawn(value byte) (*piece, bool) {
+	p := &piece{value: value, x: (b.width - 3) / 2, y: len(b.cells) - b.height - 2}
+	if value == 'I' {
+		p.y--
+	}
+	return p, b.fits(p.cells())
+}
+
+// shift moves the piece by the offset, reporting whether it fit there.
+func (b *board) shift(p *piece, dx, dy int) bool {
+	if !b.fits(p.cellsAt(p.state, p.x+dx, p.y+dy)) {
+		return false
+	}
+	p.x, p.y = p.x+dx, p.y+dy
+	p.rotated = false
+	return true
+}
+
+// shiftAll moves the piece by the offset for as long as it fits, returning how many times it moved.
+func (b *board) shiftAll(p *piece, dx, dy int) int {
+	var n int
+	for b.shift(p, dx, dy) {
+		n++
+	}
+	return n
+}
+
+// grounded reports whether the piece is resting on the stack or the floor.
+func (b *board) grounded(p *piece) bool {
+	return !b.fits(p.cellsAt(p.state, p.x, p.y+1))
+}
+
+// rotate turns the piece by the quarter turns, clockwise, trying each kick for the turn in order. Half turns are only
+// made in place, since the kicks for them differ between clients.
+func (b *board) rotate(p *piece, turns int) bool {
+	to := (p.state + turns) % 4
+	offsets := []tetris.Coordinate{{}}
+	if turns != 2 {
+		offsets = tetris.Kicks(p.value, p.state, to)
+	}
+	for i, o := range offsets {
+		if b.fits(p.cellsAt(to, p.x+o.X, p.y+o.Y)) {
+			p.state, p.x, p.y = to, p.x+o.X, p.y+o.Y
+			p.rotated, p.lastKick = true, i
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,548 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// TETR.IO's defaults for the options a replay leaves out.
+const (
+	tetrioGravity    = 0.02 // rows fallen each frame
+	tetrioLockTime   = 30   // frames a landed tetrimino waits before locking
+	tetrioLockResets = 15   // moves and rotations which restart the lock delay
+	tetrioDAS        = 10   // frames a movement key is held before it repeats
+	tetrioARR        = 2    // frames between repeats
+	tetrioSDF        = 6    // how many times faster tetriminos fall during a soft drop
+	tetrioSDFMax     = 41   // the soft drop factor TETR.IO treats as dropping straight to the stack
+)
+
+// tetrioMaxFrameGap is the most frames played between one event and the next, ten minutes at 60 frames a second.
+// Replays which jump further are taken to be corrupt rather than played out frame by frame.
+const tetrioMaxFrameGap = 10 * 60 * 60
+
+// tetrioBag is the order tetriminos are put in each bag before it is shuffled.
+const tetrioBag = "ZLOSIJT"
+
+// ttr is a TETR.IO replay, of a single player game (.ttr) or of the rounds of a multiplayer one (.ttrm).
+type ttr struct {
+	IsMulti    bool            `json:"ismulti"`
+	Data       json.RawMessage `json:"data"`
+	EndContext json.RawMessage `json:"endcontext"`
+	Date       time.Time       `json:"ts"`
+}
+
+// ttrGame is the recording of a single player's game: every event, by the frame it happened on.
+type ttrGame struct {
+	Frames int        `json:"frames"`
+	Events []ttrEvent `json:"events"`
+}
+
+type ttrEvent struct {
+	Frame int             `json:"frame"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// ttrRound is a round of a multiplayer replay, with a game for each player.
+type ttrRound struct {
+	Replays []ttrGame `json:"replays"`
+}
+
+// ttrEnd is how a single player game ended, to check the import against.
+type ttrEnd struct {
+	GameType     string  `json:"gametype"`
+	Score        float64 `json:"score"`
+	Lines        uint    `json:"lines"`
+	PiecesPlaced int     `json:"piecesplaced"`
+}
+
+// ttrOptions are the rules a game was played with, given by its "full" event. Older replays give the handling at the
+// top level, and newer ones in Handling.
+type ttrOptions struct {
+	Seed            int64    `json:"seed"`
+	BagType         string   `json:"bagtype"`
+	KickSet         string   `json:"kickset"`
+	BoardWidth      int      `json:"boardwidth"`
+	BoardHeight     int      `json:"boardheight"`
+	Gravity         *float64 `json:"g"`
+	GravityIncrease float64  `json:"gincrease"`
+	GravityMargin   int      `json:"gmargin"`
+	LockTime        int      `json:"locktime"`
+	LockResets      *int     `json:"lockresets"`
+	DAS             *float64 `json:"das"`
+	ARR             *float64 `json:"arr"`
+	SDF             *float64 `json:"sdf"`
+	Handling        *struct {
+		DAS float64 `json:"das"`
+		ARR float64 `json:"arr"`
+		SDF float64 `json:"sdf"`
+	} `json:"handling"`
+}
+
+// importTETRIO converts a TETR.IO replay, choosing the round and player of a multiplayer one.
+func importTETRIO(data []byte, opts Options) (*replay.Replay, error) {
+	var t ttr
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode TETR.IO replay: %w", err)
+	}
+
+	var game ttrGame
+	var end *ttrEnd
+	mode := "TETR.IO"
+	if t.IsMulti {
+		var rounds []ttrRound
+		err = json.Unmarshal(t.Data, &rounds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode TETR.IO rounds: %w", err)
+		}
+		round, player := max(opts.Round, 1), max(opts.Player, 1)
+		if round > len(rounds) {
+			return nil, fmt.Errorf("replay has %d rounds, so there is no round %d", len(rounds), round)
+		}
+		if player > len(rounds[round-1].Replays) {
+			return nil, fmt.Errorf("round %d has %d players, so there is no player %d", round,
+				len(rounds[round-1].Replays), player)
+		}
+		game = rounds[round-1].Replays[player-1]
+		mode = fmt.Sprintf("TETR.IO (round %d)", round)
+	} else {
+		err = json.Unmarshal(t.Data, &game)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode TETR.IO game: %w", err)
+		}
+		// The results are only checked for single player games, which give them in the same form.
+		if json.Unmarshal(t.EndContext, &end) == nil && end != nil && end.GameType != "" {
+			mode = fmt.Sprintf("TETR.IO (%s)", end.GameType)
+		}
+	}
+	if len(game.Events) == 0 {
+		return nil, errors.New("TETR.IO replay has no events")
+	}
+
+	r, err := playTETRIO(game, mode)
+	if err != nil {
+		return nil, err
+	}
+	r.Date = t.Date
+	if end != nil && end.PiecesPlaced > 0 {
+		if end.PiecesPlaced != len(r.Placements) {
+			return nil, fmt.Errorf("%w: the game placed %d tetriminos, but %d were placed importing it, so it relies on "+
+				"rules which can't be imported", ErrUnsupported, end.PiecesPlaced, len(r.Placements))
+		}
+		r.Score = uint(end.Score)
+	}
+	return r, nil
+}
+
+// playTETRIO plays the game's events again by TETR.IO's rules, recording where each tetrimino locked.
+func playTETRIO(game ttrGame, mode string) (*replay.Replay, error) {
+	var p *tetrioPlayer
+	for _, e := range game.Events {
+		switch e.Type {
+		case "full":
+			if p != nil {
+				continue
+			}
+			var full struct {
+				Options ttrOptions `json:"options"`
+			}
+			err := json.Unmarshal(e.Data, &full)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode TETR.IO options: %w", err)
+			}
+			p, err = newTETRIOPlayer(&full.Options)
+			if err != nil {
+				return nil, err
+			}
+			p.replay.Mode = mode
+		case "keydown", "keyup":
+			if p == nil {
+				return nil, errors.New("TETR.IO replay has inputs before its options")
+			}
+			var key struct {
+				Key string `json:"key"`
+			}
+			err := json.Unmarshal(e.Data, &key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode TETR.IO input: %w", err)
+			}
+			p.advance(e.Frame)
+			p.press(key.Key, e.Type == "keydown")
+			if p.err != nil {
+				return nil, p.err
+			}
+		case "ige":
+			// Interactions with other players are how garbage arrives, which replays can't record.
+			var interaction any
+			if json.Unmarshal(e.Data, &interaction) == nil && garbage(interaction) > 0 {
+				return nil, fmt.Errorf("%w: the game received garbage", ErrUnsupported)
+			}
+		case "end":
+			if p != nil {
+				p.advance(e.Frame)
+				if p.err != nil {
+					return nil, p.err
+				}
+			}
+		}
+		if p != nil && p.over {
+			break
+		}
+	}
+	if p == nil {
+		return nil, errors.New("TETR.IO replay has no options")
+	}
+	if report := p.game.Report(); !report.OK() {
+		return nil, fmt.Errorf("failed to play TETR.IO replay: %v", report.Violations[0])
+	}
+	p.replay.Lines = p.lines
+	return p.replay, nil
+}
+
+// garbage returns the lines of garbage sent by an interaction, found anywhere within it.
+func garbage(v any) int {
+	var lines int
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if amount, ok := value.(float64); ok && key == "amt" {
+				lines += int(amount)
+			} else {
+				lines += garbage(value)
+			}
+		}
+	case []any:
+		for _, value := range v {
+			lines += garbage(value)
+		}
+	}
+	return lines
+}
+
+// tetrioPlayer plays a TETR.IO game from its inputs, frame by frame. Tetriminos are moved by TETR.IO's rules, and
+// dealt, held and locked by the engine's simulation, which the board is kept in step with.
+type tetrioPlayer struct {
+	game    *tetris.Simulation
+	board   *board
+	current *piece
+	over    bool
+	err     error // why the game couldn't be played on, if it ended before it should have
+
+	das, arr, sdf       float64
+	gravity, increase   float64
+	margin              int
+	lockTime, maxResets int
+
+	frame       int
+	left, right bool    // whether each movement key is held
+	direction   int     // the direction being auto-shifted in, the last movement key pressed which is still held
+	charge      float64 // frames the direction has been held
+	repeat      float64 // frames since the last repeat
+	softDrop    bool
+	fall        float64 // rows of gravity accumulated
+	landed      int     // frames the current tetrimino has been on the stack
+	resets      int
+
+	inputs []tetris.Move
+	drop   uint // points scored dropping the current tetrimino, as tetrigo scores them
+	combo  int
+	lines  uint
+	replay *replay.Replay
+}
+
+// newTETRIOPlayer starts a game with the options, or returns an error if tetrigo can't play by them.
+func newTETRIOPlayer(o *ttrOptions) (*tetrioPlayer, error) {
+	if o.BagType != "" && o.BagType != "7-bag" {
+		return nil, fmt.Errorf("%w: tetriminos are dealt by %q rather than in bags of seven", ErrUnsupported, o.BagType)
+	}
+	if o.KickSet != "" && o.KickSet != "SRS" && o.KickSet != "SRS+" {
+		return nil, fmt.Errorf("%w: tetriminos are rotated by %q rather than SRS", ErrUnsupported, o.KickSet)
+	}
+	width, height := o.BoardWidth, o.BoardHeight
+	if width == 0 {
+		width = tetris.DefaultWidth
+	}
+	if height == 0 {
+		height = tetris.DefaultHeight
+	}
+	if width < 4 || width > tetris.MaxWidth || height < 4 || height > tetris.MaxHeight {
+		return nil, fmt.Errorf("%w: the board is %dx%d", ErrUnsupported, width, height)
+	}
+
+	p := &tetrioPlayer{
+		game: tetris.NewSimulation(tetris.SimulationOptions{
+			Randomizer: newTETRIORNG(o.Seed),
+			Matrix:     tetris.NewMatrix(width, height),
+		}),
+		board:     newBoard(width, height),
+		das:       tetrioDAS,
+		arr:       tetrioARR,
+		sdf:       tetrioSDF,
+		gravity:   tetrioGravity,
+		increase:  o.GravityIncrease,
+		margin:    o.GravityMargin,
+		lockTime:  tetrioLockTime,
+		maxResets: tetrioLockResets,
+		replay:    replay.New("TETR.IO", o.Seed, 1, tetris.NewMatrix(width, height)),
+	}
+	switch {
+	case o.Handling != nil:
+		p.das, p.arr, p.sdf = o.Handling.DAS, o.Handling.ARR, o.Handling.SDF
+	case o.DAS != nil && o.ARR != nil && o.SDF != nil:
+		p.das, p.arr, p.sdf = *o.DAS, *o.ARR, *o.SDF
+	}
+	if o.Gravity != nil {
+		p.gravity = *o.Gravity
+	}
+	if o.LockTime > 0 {
+		p.lockTime = o.LockTime
+	}
+	if o.LockResets != nil {
+		p.maxResets = *o.LockResets
+	}
+	p.spawn()
+	return p, nil
+}
+
+// spawn puts the tetrimino the game has dealt or swapped in at the top of the matrix, ending the game if it is
+// blocked.
+func (p *tetrioPlayer) spawn() {
+	p.fall, p.landed, p.resets = 0, 0, 0
+	if p.game.GameOver() {
+		p.over = true
+		return
+	}
+	current, ok := p.board.spawn(p.game.Current().Value)
+	p.current = current
+	if !ok {
+		p.over = true
+	}
+}
+
+// advance plays the frames up to the one given, with the keys held as they are. Events must be in order and not too
+// far apart.
+func (p *tetrioPlayer) advance(frame int) {
+	switch {
+	case frame < p.frame:
+		p.over, p.err = true, fmt.Errorf("TETR.IO replay goes back from frame %d to %d", p.frame, frame)
+		return
+	case frame-p.frame > tetrioMaxFrameGap:
+		p.over, p.err = true, fmt.Errorf("TETR.IO replay jumps from frame %d to %d", p.frame, frame)
+		return
+	}
+	for p.frame < frame && !p.over {
+		p.frame++
+		p.step()
+	}
+}
+
+// step plays a frame: the held movement key repeating once DAS has passed, gravity and the lock delay.
+func (p *tetrioPlayer) step() {
+	if p.direction != 0 {
+		p.charge++
+		if p.charge > p.das {
+			if p.arr == 0 {
+				p.moved(p.board.shiftAll(p.current, p.direction, 0) > 0)
+			} else {
+				p.repeat++
+				for ; p.repeat >= p.arr; p.repeat -= p.arr {
+					p.moved(p.board.shift(p.current, p.direction, 0))
+				}
+			}
+		}
+	}
+
+	gravity := p.gravity
+	if p.frame > p.margin {
+		gravity += p.increase * float64(p.frame-p.margin)
+	}
+	if p.softDrop {
+		if p.sdf >= tetrioSDFMax {
+			p.drop += uint(p.board.shiftAll(p.current, 0, 1))
+		}
+		gravity *= p.sdf
+	}
+	for p.fall += gravity; p.fall >= 1; p.fall-- {
+		if !p.board.shift(p.current, 0, 1) {
+			p.fall = 0
+			break
+		}
+		if p.softDrop {
+			p.drop++
+		}
+	}
+
+	if !p.board.grounded(p.current) {
+		p.landed = 0
+		return
+	}
+	p.landed++
+	if p.landed >= p.lockTime {
+		p.lock()
+	}
+}
+
+// moved restarts the lock delay of a landed tetrimino which was moved or rotated, until it has been restarted too
+// many times.
+func (p *tetrioPlayer) moved(ok bool) {
+	if ok && p.landed > 0 && p.resets < p.maxResets {
+		p.landed = 0
+		p.resets++
+	}
+}
+
+// press makes the input of a key being pressed or released, recording the moves made with tetrigo's names. Half turns
+// are recorded as two clockwise rotations, since tetrigo has no key for them.
+func (p *tetrioPlayer) press(key string, down bool) {
+	if p.over {
+		return
+	}
+	switch key {
+	case "moveLeft", "moveRight":
+		direction, move := -1, tetris.MoveLeft
+		if key == "moveRight" {
+			direction, move = 1, tetris.MoveRight
+		}
+		if direction < 0 {
+			p.left = down
+		} else {
+			p.right = down
+		}
+		if down {
+			p.direction, p.charge, p.repeat = direction, 0, 0
+			p.inputs = append(p.inputs, move)
+			p.moved(p.board.shift(p.current, direction, 0))
+		} else if p.direction == direction {
+			// Releasing the key hands back to the other one if it is still held.
+			p.direction, p.charge, p.repeat = 0, 0, 0
+			if p.left {
+				p.direction = -1
+			} else if p.right {
+				p.direction = 1
+			}
+		}
+	case "softDrop":
+		p.softDrop = down
+		if down {
+			p.inputs = append(p.inputs, tetris.MoveSoftDrop)
+		}
+	case "hardDrop":
+		if down {
+			p.inputs = append(p.inputs, tetris.MoveHardDrop)
+			p.drop += 2 * uint(p.board.shiftAll(p.current, 0, 1))
+			p.lock()
+		}
+	case "rotateCW":
+		if down {
+			p.inputs = append(p.inputs, tetris.MoveClockwise)
+			p.moved(p.board.rotate(p.current, 1))
+		}
+	case "rotateCCW":
+		if down {
+			p.inputs = append(p.inputs, tetris.MoveCounterClockwise)
+			p.moved(p.board.rotate(p.current, 3))
+		}
+	case "rotate180":
+		if down {
+			p.inputs = append(p.inputs, tetris.MoveClockwise, tetris.MoveClockwise)
+			p.moved(p.board.rotate(p.current, 2))
+		}
+	case "hold":
+		if down {
+			// The game only swaps in another tetrimino once for each one dealt.
+			current := p.game.Current()
+			p.game.Apply(tetris.MoveHold)
+			if p.game.Current() != current || p.game.GameOver() {
+				p.inputs = append(p.inputs, tetris.MoveHold)
+				p.spawn()
+			}
+		}
+	}
+}
+
+// lock locks the current tetrimino, recording the placement, and spawns the next.
+func (p *tetrioPlayer) lock() {
+	cells := p.current.cells()
+	t := p.current.tetrimino()
+	lines, clearName, err := p.game.Place(t, p.board.cells.TSpin(t, p.current.kick()))
+	if err != nil {
+		p.over, p.err = true, fmt.Errorf("failed to lock %c: %w", t.Value, err)
+		return
+	}
+	p.board.cells = p.game.Stack()
+	if lines > 0 {
+		p.combo++
+	} else {
+		p.combo = 0
+	}
+	p.lines += uint(lines)
+
+	placement := replay.Placement{
+		Piece:  string(p.current.value),
+		Cells:  cells,
+		Time:   time.Duration(p.frame) * tetris.FrameDuration,
+		Inputs: make([]string, len(p.inputs)),
+		Clear:  clearName,
+		Lines:  uint(lines),
+		Combo:  p.combo,
+		Drop:   p.drop,
+	}
+	for i, m := range p.inputs {
+		placement.Inputs[i] = m.String()
+	}
+	p.replay.Placements = append(p.replay.Placements, placement)
+	p.inputs, p.drop = nil, 0
+	p.spawn()
+}
+
+// tetrioRNG is the random number generator TETR.IO shuffles its bags with, a Park-Miller generator.
+type tetrioRNG struct {
+	t int64
+}
+
+func newTETRIORNG(seed int64) *tetrioRNG {
+	t := seed % 2147483647
+	if t <= 0 {
+		t += 2147483646
+	}
+	return &tetrioRNG{t: t}
+}
+
+func (r *tetrioRNG) next() int64 {
+	r.t = 16807 * r.t % 2147483647
+	return r.t
+}
+
+// float returns a number from 0 up to, but not including, 1.
+func (r *tetrioRNG) float() float64 {
+	return float64(r.next()-1) / 2147483646
+}
+
+// Deal shuffles a bag of the tetriminos as TETR.IO does, so that the engine deals them in the same order.
+func (r *tetrioRNG) Deal(choices []tetris.Tetrimino) []tetris.Tetrimino {
+	values := []byte(tetrioBag)
+	r.shuffle(values)
+	dealt := make([]tetris.Tetrimino, 0, len(values))
+	for _, value := range values {
+		for _, t := range choices {
+			if t.Value == value {
+				dealt = append(dealt, t)
+			}
+		}
+	}
+	return dealt
+}
+
+// shuffle shuffles the values in place, in the same way as TETR.IO.
+func (r *tetrioRNG) shuffle(values []byte) {
+	for i := len(values) - 1; i > 0; i-- {
+		j := int(r.float() * float64(i+1))
+		values[i], values[j] = values[j], values[i]
+	}
+}
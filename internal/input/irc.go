@@ -0,0 +1,90 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ircNick is the nickname used to read chat. Twitch lets anyone read chat anonymously with a nickname like this.
+const ircNick = "justinfan31415"
+
+// ircTimeout is how long connecting to the server can take.
+const ircTimeout = 10 * time.Second
+
+// Chat reads votes from an IRC channel, such as a Twitch channel's chat.
+type Chat struct {
+	conn  net.Conn
+	votes *Votes
+}
+
+// DialChat connects to the IRC server at addr and joins the channel (with or without its leading "#"), casting each
+// message in it which names a move as a vote. Twitch chat is at irc.chat.twitch.tv:6667, where the channel is the
+// streamer's username.
+func DialChat(addr, channel string, votes *Votes) (*Chat, error) {
+	conn, err := net.DialTimeout("tcp", addr, ircTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to chat: %w", err)
+	}
+	c := &Chat{conn: conn, votes: votes}
+	_, err = fmt.Fprintf(conn, "NICK %s\r\nJOIN #%s\r\n", ircNick, strings.ToLower(strings.TrimPrefix(channel, "#")))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to join chat: %w", err)
+	}
+	go func() {
+		_ = readChat(conn, conn, votes)
+	}()
+	return c, nil
+}
+
+// readChat casts votes from the messages read until the connection closes, answering the server's pings to stay
+// connected.
+func readChat(r io.Reader, w io.Writer, votes *Votes) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if token, ok := strings.CutPrefix(line, "PING "); ok {
+			_, err := fmt.Fprintf(w, "PONG %s\r\n", token)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if voter, text, ok := parsePrivmsg(line); ok {
+			votes.VoteText(voter, text)
+		}
+	}
+	return scanner.Err()
+}
+
+// parsePrivmsg returns the sender and text of a message sent to a channel, such as
+// ":nick!user@host PRIVMSG #channel :text", ignoring any IRCv3 tags before it.
+func parsePrivmsg(line string) (string, string, bool) {
+	if strings.HasPrefix(line, "@") {
+		_, line, _ = strings.Cut(line, " ")
+	}
+	prefix, rest, ok := strings.Cut(line, " ")
+	if !ok || !strings.HasPrefix(prefix, ":") {
+		return "", "", false
+	}
+	command, rest, ok := strings.Cut(rest, " ")
+	if !ok || command != "PRIVMSG" {
+		return "", "", false
+	}
+	_, text, ok := strings.Cut(rest, " :")
+	if !ok {
+		return "", "", false
+	}
+	nick, _, _ := strings.Cut(strings.TrimPrefix(prefix, ":"), "!")
+	return nick, text, true
+}
+
+// Close leaves the chat and stops voting.
+func (c *Chat) Close() error {
+	c.votes.Close()
+	return c.conn.Close()
+}
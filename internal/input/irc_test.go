@@ -0,0 +1,59 @@
+package input
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestParsePrivmsg(t *testing.T) {
+	tt := []struct {
+		name      string
+		line      string
+		wantVoter string
+		wantText  string
+		wantOk    bool
+	}{
+		{"message", ":ada!ada@ada.tmi.twitch.tv PRIVMSG #tetrigo :hard drop", "ada", "hard drop", true},
+		{"with tags", "@badges=;color= :bob!bob@host PRIVMSG #tetrigo :!left", "bob", "!left", true},
+		{"text with colon", ":ada!ada@host PRIVMSG #tetrigo :hi :)", "ada", "hi :)", true},
+		{"other command", ":tmi.twitch.tv 001 justinfan31415 :Welcome", "", "", false},
+		{"join", ":ada!ada@host JOIN #tetrigo", "", "", false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			voter, text, ok := parsePrivmsg(tc.line)
+			if ok != tc.wantOk || voter != tc.wantVoter || text != tc.wantText {
+				t.Errorf("want (%q, %q, %t), got (%q, %q, %t)", tc.wantVoter, tc.wantText, tc.wantOk, voter, text, ok)
+			}
+		})
+	}
+}
+
+func TestReadChat(t *testing.T) {
+	chat := strings.Join([]string{
+		":tmi.twitch.tv 001 justinfan31415 :Welcome, GLHF!",
+		"PING :tmi.twitch.tv",
+		":ada!ada@host PRIVMSG #tetrigo :cw",
+		":bob!bob@host PRIVMSG #tetrigo :nice",
+	}, "\r\n") + "\r\n"
+	var sent bytes.Buffer
+	votes := NewVotes(time.Millisecond)
+
+	err := readChat(strings.NewReader(chat), &sent, votes)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	if sent.String() != "PONG :tmi.twitch.tv\r\n" {
+		t.Errorf("want a pong, got %q", sent.String())
+	}
+	move, ok := votes.Next()
+	if !ok || move != tetris.MoveClockwise {
+		t.Errorf("want %v, got %v", tetris.MoveClockwise, move)
+	}
+}
@@ -0,0 +1,71 @@
+// Package input provides sources of moves other than the keyboard, such as a pipe or votes from a chat, for games to
+// be played by other programs or by a crowd.
+package input
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Source is where moves come from other than the keyboard. Games wait for each move in turn until the source runs
+// out, so Next may block for as long as it needs.
+type Source interface {
+	// Next returns the next move, or false once there are no more.
+	Next() (tetris.Move, bool)
+}
+
+// Channel is a source of the moves sent on the channel, until it is closed.
+type Channel <-chan tetris.Move
+
+func (c Channel) Next() (tetris.Move, bool) {
+	move, ok := <-c
+	return move, ok
+}
+
+// Reader is a source of moves read from text, one per line, such as a pipe or standard input. Each line names a move
+// as tetris.Move does, such as "hard drop", or gives one of the short names ParseMove accepts. Lines which don't
+// name a move are skipped.
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(r)}
+}
+
+func (r *Reader) Next() (tetris.Move, bool) {
+	for r.scanner.Scan() {
+		move, ok := ParseMove(r.scanner.Text())
+		if ok {
+			return move, true
+		}
+	}
+	return 0, false
+}
+
+// shortNames are the moves which can be given more quickly than by their full names, such as in chat.
+var shortNames = map[string]tetris.Move{
+	"l":    tetris.MoveLeft,
+	"r":    tetris.MoveRight,
+	"cw":   tetris.MoveClockwise,
+	"ccw":  tetris.MoveCounterClockwise,
+	"sd":   tetris.MoveSoftDrop,
+	"down": tetris.MoveSoftDrop,
+	"hd":   tetris.MoveHardDrop,
+	"drop": tetris.MoveHardDrop,
+}
+
+// ParseMove returns the move named by the text, ignoring case, surrounding space and a leading "!" as used for chat
+// commands. Both the names given by tetris.Move and the short names "l", "r", "cw", "ccw", "sd" (or "down") and "hd"
+// (or "drop") are accepted.
+func ParseMove(text string) (tetris.Move, bool) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(text), "!"))
+	if move, ok := shortNames[name]; ok {
+		return move, true
+	}
+	move, err := tetris.ParseMove(name)
+	return move, err == nil
+}
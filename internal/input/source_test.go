@@ -0,0 +1,68 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestParseMove(t *testing.T) {
+	tt := []struct {
+		text   string
+		want   tetris.Move
+		wantOk bool
+	}{
+		{"left", tetris.MoveLeft, true},
+		{"  Hard Drop ", tetris.MoveHardDrop, true},
+		{"!ccw", tetris.MoveCounterClockwise, true},
+		{"drop", tetris.MoveHardDrop, true},
+		{"down", tetris.MoveSoftDrop, true},
+		{"hello chat", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.text, func(t *testing.T) {
+			got, ok := ParseMove(tc.text)
+			if ok != tc.wantOk {
+				t.Fatalf("want ok %t, got %t", tc.wantOk, ok)
+			}
+			if ok && got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	r := NewReader(strings.NewReader("left\nnot a move\n\nhard drop\n"))
+
+	var got []tetris.Move
+	for {
+		move, ok := r.Next()
+		if !ok {
+			break
+		}
+		got = append(got, move)
+	}
+
+	if len(got) != 2 || got[0] != tetris.MoveLeft || got[1] != tetris.MoveHardDrop {
+		t.Errorf("want [left hard drop], got %v", got)
+	}
+}
+
+func TestChannel(t *testing.T) {
+	c := make(chan tetris.Move, 1)
+	c <- tetris.MoveHold
+	close(c)
+
+	move, ok := Channel(c).Next()
+	if !ok || move != tetris.MoveHold {
+		t.Errorf("want %v, got %v", tetris.MoveHold, move)
+	}
+	_, ok = Channel(c).Next()
+	if ok {
+		t.Errorf("want no more moves once closed")
+	}
+}
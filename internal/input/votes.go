@@ -0,0 +1,97 @@
+package input
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Votes is a source of moves chosen by a crowd. Votes are counted in rounds, starting with the first vote cast and
+// lasting for the window, and the move with the most votes is made when the round ends. Each voter has one vote per
+// round, which is changed by voting again, and ties go to the move voted for first.
+type Votes struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	votes   map[string]tetris.Move // the move each voter has voted for this round
+	order   []tetris.Move          // moves in the order they were first voted for this round
+	started chan struct{}          // signalled when the first vote of a round is cast
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func NewVotes(window time.Duration) *Votes {
+	return &Votes{
+		window:  window,
+		votes:   make(map[string]tetris.Move),
+		started: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Vote casts the voter's vote for the move in the current round, starting a round if none is running.
+func (v *Votes) Vote(voter string, move tetris.Move) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.votes) == 0 {
+		select {
+		case v.started <- struct{}{}:
+		default:
+		}
+	}
+	v.votes[voter] = move
+	for _, m := range v.order {
+		if m == move {
+			return
+		}
+	}
+	v.order = append(v.order, move)
+}
+
+// VoteText casts the voter's vote for the move named by a chat message, as ParseMove reads it. It reports whether the
+// message was a vote.
+func (v *Votes) VoteText(voter, text string) bool {
+	move, ok := ParseMove(text)
+	if ok {
+		v.Vote(voter, move)
+	}
+	return ok
+}
+
+// Next waits for a round to be voted on, and returns the move with the most votes. It returns false once the votes
+// are closed.
+func (v *Votes) Next() (tetris.Move, bool) {
+	select {
+	case <-v.started:
+	case <-v.closed:
+		return 0, false
+	}
+	select {
+	case <-time.After(v.window):
+	case <-v.closed:
+		return 0, false
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	counts := make(map[tetris.Move]int, len(v.order))
+	for _, move := range v.votes {
+		counts[move]++
+	}
+	winner := v.order[0]
+	for _, move := range v.order[1:] {
+		if counts[move] > counts[winner] {
+			winner = move
+		}
+	}
+	clear(v.votes)
+	v.order = v.order[:0]
+	return winner, true
+}
+
+// Close ends voting, so that Next returns false.
+func (v *Votes) Close() error {
+	v.once.Do(func() { close(v.closed) })
+	return nil
+}
@@ -0,0 +1,62 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestVotes_Next(t *testing.T) {
+	type vote struct {
+		voter string
+		move  tetris.Move
+	}
+	tt := []struct {
+		name  string
+		votes []vote
+		want  tetris.Move
+	}{
+		{"most votes", []vote{{"a", tetris.MoveLeft}, {"b", tetris.MoveRight}, {"c", tetris.MoveRight}}, tetris.MoveRight},
+		{"tie goes to first", []vote{{"a", tetris.MoveHold}, {"b", tetris.MoveLeft}}, tetris.MoveHold},
+		{"one vote each", []vote{{"a", tetris.MoveLeft}, {"a", tetris.MoveLeft}, {"b", tetris.MoveRight}}, tetris.MoveLeft},
+		{"changed vote", []vote{{"a", tetris.MoveLeft}, {"b", tetris.MoveRight}, {"a", tetris.MoveRight}}, tetris.MoveRight},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewVotes(10 * time.Millisecond)
+			for _, vt := range tc.votes {
+				v.Vote(vt.voter, vt.move)
+			}
+
+			got, ok := v.Next()
+			if !ok || got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestVotes_Rounds(t *testing.T) {
+	v := NewVotes(10 * time.Millisecond)
+	v.Vote("a", tetris.MoveLeft)
+	_, _ = v.Next()
+
+	v.Vote("b", tetris.MoveClockwise)
+	got, ok := v.Next()
+	if !ok || got != tetris.MoveClockwise {
+		t.Errorf("want %v, got %v", tetris.MoveClockwise, got)
+	}
+}
+
+func TestVotes_Close(t *testing.T) {
+	v := NewVotes(time.Hour)
+	v.Vote("a", tetris.MoveLeft)
+	go v.Close()
+
+	_, ok := v.Next()
+	if ok {
+		t.Errorf("want no move once closed")
+	}
+}
@@ -0,0 +1,185 @@
+// Package leaderboard submits the results of finished games to an online leaderboard. Results which can't be sent,
+// such as while offline, are queued in a file and sent along with the next.
+//
+// Each result is the JSON of an Entry, sent with POST to the server's URL. A 2xx status accepts it. Other 4xx statuses
+// reject it, so it is dropped rather than sent again, and anything else, such as the server being unreachable, leaves
+// it queued.
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// timeout is how long the server has to accept each result before it is queued to be sent later.
+const timeout = 10 * time.Second
+
+// Entry is the result of a finished game, as submitted.
+type Entry struct {
+	Player     string        `json:"player"`
+	Mode       string        `json:"mode"` // name of the mode, such as "Sprint (40 lines)"
+	Score      uint          `json:"score"`
+	Lines      uint          `json:"lines"`
+	Time       time.Duration `json:"time"`
+	Grade      string        `json:"grade,omitempty"` // grade earned, in modes which grade the player, such as "S4"
+	Seed       int64         `json:"seed"`
+	ReplayHash string        `json:"replay_hash"` // see replay.Replay.Hash
+	Date       time.Time     `json:"date"`        // when the game ended
+}
+
+// Client submits results to a leaderboard server. It is safe to use from multiple goroutines.
+type Client struct {
+	url       string
+	player    string
+	queuePath string
+	http      *http.Client
+	mu        sync.Mutex
+}
+
+// DefaultQueuePath returns the location of the file results waiting to be sent are kept in, in the user's config
+// directory.
+func DefaultQueuePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "leaderboard-queue.json"), nil
+}
+
+// NewClient creates a client which submits results to the URL under the player's name, queueing those which can't be
+// sent in the file at queuePath.
+func NewClient(url, player, queuePath string) *Client {
+	return &Client{
+		url:       url,
+		player:    player,
+		queuePath: queuePath,
+		http:      &http.Client{Timeout: timeout},
+	}
+}
+
+// Submit sends the entry, after any queued before it, and reports whether it was sent. If the server can't be reached
+// it is queued instead, and false is returned without an error. Entries without a player are sent under the client's.
+func (c *Client) Submit(e Entry) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e.Player == "" {
+		e.Player = c.player
+	}
+	queue, err := c.read()
+	if err != nil {
+		return false, err
+	}
+	queue = append(queue, e)
+
+	// Entries are sent in the order they were played, stopping at the first which can't be sent so that it is kept.
+	// Queued entries which are rejected are dropped, since sending them again would be rejected too.
+	var rejected error
+	for len(queue) > 0 {
+		retry, err := c.send(queue[0])
+		if retry {
+			break
+		}
+		if err != nil && len(queue) == 1 {
+			rejected = err
+		}
+		queue = queue[1:]
+	}
+
+	err = c.write(queue)
+	if err != nil {
+		return false, err
+	}
+	if rejected != nil {
+		return false, rejected
+	}
+	return len(queue) == 0, nil
+}
+
+// Queued returns the number of entries waiting to be sent.
+func (c *Client) Queued() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.read()
+	if err != nil {
+		return 0, err
+	}
+	return len(queue), nil
+}
+
+// send posts the entry to the server, reporting whether it should be sent again later, such as when the server can't
+// be reached, or the error if it was rejected.
+func (c *Client) send(e Entry) (bool, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode result: %w", err)
+	}
+	resp, err := c.http.Post(c.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return true, nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return false, fmt.Errorf("leaderboard rejected result: %s", resp.Status)
+	}
+	return true, nil
+}
+
+func (c *Client) read() ([]Entry, error) {
+	var queue []Entry
+	data, err := os.ReadFile(c.queuePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard queue: %w", err)
+	}
+
+	err = json.Unmarshal(data, &queue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode leaderboard queue: %w", err)
+	}
+	return queue, nil
+}
+
+// write keeps the entries waiting to be sent, removing the file once there are none.
+func (c *Client) write(queue []Entry) error {
+	if len(queue) == 0 {
+		err := os.Remove(c.queuePath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove leaderboard queue: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode leaderboard queue: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(c.queuePath), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create leaderboard queue directory: %w", err)
+	}
+	// Write to a temporary file first so that the queue is not lost if writing is interrupted.
+	tmp := c.queuePath + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write leaderboard queue: %w", err)
+	}
+	err = os.Rename(tmp, c.queuePath)
+	if err != nil {
+		return fmt.Errorf("failed to replace leaderboard queue: %w", err)
+	}
+	return nil
+}
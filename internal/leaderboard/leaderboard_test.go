@@ -0,0 +1,127 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// server is a leaderboard server which responds to every result with the status, keeping those it accepts.
+type server struct {
+	status  int
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var e Entry
+	err := json.NewDecoder(r.Body).Decode(&e)
+	if err != nil {
+		http.Error(w, "invalid result", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status < 300 {
+		s.entries = append(s.entries, e)
+	}
+	w.WriteHeader(s.status)
+}
+
+func TestClient_Submit(t *testing.T) {
+	tt := map[string]struct {
+		status     int
+		wantSent   bool
+		wantErr    bool
+		wantQueued int
+	}{
+		"accepted": {
+			status:   http.StatusCreated,
+			wantSent: true,
+		},
+		"rejected": {
+			status:  http.StatusUnprocessableEntity,
+			wantErr: true,
+		},
+		"server error": {
+			status:     http.StatusServiceUnavailable,
+			wantQueued: 1,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			s := &server{status: tc.status}
+			ts := httptest.NewServer(s)
+			defer ts.Close()
+			c := NewClient(ts.URL, "alice", filepath.Join(t.TempDir(), "queue.json"))
+
+			sent, err := c.Submit(Entry{Mode: "Marathon", Score: 1200, Seed: 42, Date: time.Now()})
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if sent != tc.wantSent {
+				t.Errorf("Sent: want %v, got %v", tc.wantSent, sent)
+			}
+			queued, err := c.Queued()
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if queued != tc.wantQueued {
+				t.Errorf("Queued: want %d, got %d", tc.wantQueued, queued)
+			}
+			if tc.wantSent && (len(s.entries) != 1 || s.entries[0].Player != "alice") {
+				t.Errorf("want one entry from alice, got %v", s.entries)
+			}
+		})
+	}
+}
+
+func TestClient_Submit_Offline(t *testing.T) {
+	s := &server{status: http.StatusOK}
+	ts := httptest.NewServer(s)
+	url := ts.URL
+	ts.Close()
+	path := filepath.Join(t.TempDir(), "queue.json")
+
+	offline := NewClient(url, "alice", path)
+	for _, score := range []uint{100, 200} {
+		sent, err := offline.Submit(Entry{Mode: "Marathon", Score: score})
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		if sent {
+			t.Errorf("want the result queued while offline, got sent")
+		}
+	}
+
+	ts = httptest.NewServer(s)
+	defer ts.Close()
+	online := NewClient(ts.URL, "alice", path)
+	sent, err := online.Submit(Entry{Mode: "Marathon", Score: 300})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !sent {
+		t.Errorf("want the result sent once online, got queued")
+	}
+	if len(s.entries) != 3 {
+		t.Fatalf("want 3 entries sent, got %d", len(s.entries))
+	}
+	for i, want := range []uint{100, 200, 300} {
+		if s.entries[i].Score != want {
+			t.Errorf("Entry %d: want score %d, got %d", i, want, s.entries[i].Score)
+		}
+	}
+	queued, err := online.Queued()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if queued != 0 {
+		t.Errorf("Queued: want 0, got %d", queued)
+	}
+}
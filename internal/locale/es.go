@@ -0,0 +1,212 @@
+package locale
+
+var spanish = map[string]string{
+	// Information panels
+	"Score":    "Puntos",
+	"Level":    "Nivel",
+	"Cleared":  "Líneas",
+	"Goal":     "Meta",
+	"Cheese":   "Queso",
+	"Dug":      "Cavadas",
+	"Grade":    "Grado",
+	"Roll":     "Créditos",
+	"Zone":     "Zona",
+	"Pace":     "Ritmo",
+	"Splits":   "Parciales",
+	"Time":     "Tiempo",
+	"Speed":    "Velocidad",
+	"Combo":    "Combo",
+	"Drill":    "Ejercicio",
+	"Clean":    "Limpios",
+	"Opener":   "Apertura",
+	"Budget":   "Restantes",
+	"PCs":      "PCs",
+	"Best":     "Mejor",
+	"Attack":   "Ataque",
+	"Received": "Recibidas",
+	"Incoming": "Entrantes",
+	"Hold":     "Reserva",
+	"Next":     "Siguiente",
+	"Pieces":   "Piezas",
+	"Tetris":   "Tetris",
+	"Seed":     "Semilla",
+	"Coach":    "Entrenador",
+
+	"T-spin double": "T-spin doble",
+	"T-spin triple": "T-spin triple",
+
+	// Results
+	"GAME OVER":                            "FIN DE LA PARTIDA",
+	"FINISHED":                             "TERMINADA",
+	"SOLVED":                               "RESUELTO",
+	"Lines":                                "Líneas",
+	"Drops":                                "Caídas",
+	"Drills":                               "Ejercicios",
+	"Success":                              "Éxito",
+	"Max combo":                            "Combo máx.",
+	"Left":                                 "Izquierda",
+	"Right":                                "Derecha",
+	"Rotate":                               "Girar",
+	"Soft drop":                            "Caída suave",
+	"Hard drop":                            "Caída dura",
+	"Survival":                             "Aguante",
+	"New personal best!":                   "¡Nuevo récord personal!",
+	"Personal best":                        "Récord personal",
+	"Share text copied":                    "Texto copiado",
+	"Submitted to the leaderboard":         "Enviado a la clasificación",
+	"Failed to submit to the leaderboard:": "No se pudo enviar a la clasificación:",
+	"Leaderboard unreachable, queued to submit later": "Clasificación inaccesible, se enviará más tarde",
+	"Terminal too small":                              "Terminal demasiado pequeña",
+	"Resize to at least":                              "Amplíala al menos a",
+	"KEYS":                                            "TECLAS",
+	"Movement":                                        "Movimiento",
+	"Rotation":                                        "Rotación",
+	"Practice":                                        "Práctica",
+	"System":                                          "Sistema",
+	"ARE YOU STILL THERE?":                            "¿SIGUES AHÍ?",
+	"The game is paused. Press any key to resume": "La partida está en pausa. Pulsa cualquier tecla para continuar",
+	"Press any key to resume":                     "Pulsa cualquier tecla para seguir",
+	"The game ended because of an error: ":        "La partida terminó por un error: ",
+
+	"PB":      "Récord",
+	"Last":    "Última",
+	"Session": "Sesión",
+
+	"Singles":  "Simples",
+	"Doubles":  "Dobles",
+	"Triples":  "Triples",
+	"Tetrises": "Tetris",
+	"T-spins":  "T-spins",
+
+	"Failed to save record:":      "No se pudo guardar el récord:",
+	"Failed to save replay:":      "No se pudo guardar la repetición:",
+	"Replay saved to":             "Repetición guardada en",
+	"Failed to log events:":       "No se pudieron registrar los eventos:",
+	"Exporting recording...":      "Exportando grabación...",
+	"Failed to export recording:": "No se pudo exportar la grabación:",
+	"Recording saved to":          "Grabación guardada en",
+
+	// Hints
+	"Failed to suspend:":    "No se pudo suspender:",
+	"Failed to autosave:":   "No se pudo guardar automáticamente:",
+	"Failed to copy fumen:": "No se pudo copiar el fumen:",
+	"Fumen copied":          "Fumen copiado",
+	"Gravity paused":        "Gravedad en pausa",
+	"In the zone":           "En la zona",
+	"time has stopped":      "el tiempo se ha detenido",
+	"Zone ready":            "Zona lista",
+	"press":                 "pulsa",
+	"to enter":              "para entrar",
+	"left":                  "restantes",
+	"Hold unavailable":      "Reserva no disponible",
+	"Soft drop active":      "Caída suave activa",
+	"keep it going!":        "¡sigue así!",
+	"lines incoming":        "líneas entrantes",
+	"clear lines to cancel": "limpia líneas para cancelarlas",
+	"Editing: arrows move the cursor, enter fills or clears, b to play": "Editando: las flechas mueven el cursor, enter llena o vacía, b para jugar",
+
+	// Versus and spectating
+	"You win!":              "¡Has ganado!",
+	"You lose":              "Has perdido",
+	"You win the match!":    "¡Has ganado el enfrentamiento!",
+	"You lose the match":    "Has perdido el enfrentamiento",
+	"Opponent disconnected": "El rival se ha desconectado",
+	"You":                   "Tú",
+	"Opponent":              "Rival",
+	"Bot":                   "Bot",
+	"Match":                 "Marcador",
+	"Broadcast ended":       "Emisión terminada",
+
+	// Menu
+	"Playing as":   "Jugando como",
+	"Players":      "Jugadores",
+	"Mode":         "Modo",
+	"Theme":        "Tema",
+	"Skin":         "Aspecto",
+	"Hold Preview": "Vista de reserva",
+	"Board":        "Tablero",
+	"Soft Drop":    "Caída suave",
+	"Lock Down":    "Bloqueo",
+	"Profile":      "Perfil",
+
+	// Modes
+	"Restore":      "Restaurar",
+	"Continue":     "Continuar",
+	"Marathon":     "Maratón",
+	"Classic":      "Clásico",
+	"Master":       "Maestro",
+	"Dig":          "Excavar",
+	"Daily":        "Diario",
+	"Invisible":    "Invisible",
+	"Dual":         "Dual",
+	"Versus":       "Versus",
+	"Warm-up":      "Calentamiento",
+	"T-spin drill": "Ejercicio de T-spin",
+	"PC opener":    "Apertura PC",
+	"Sandbox":      "Libre",
+	"Zen":          "Zen",
+	"Big":          "Grande",
+	"Adaptive":     "Adaptativo",
+	"Demo":         "Demo",
+	"Restore the game in progress when tetrigo last exited, from its last autosave.": "Restaura la partida en curso cuando tetrigo se cerró, desde su último guardado automático.",
+
+	// Popups
+	"SINGLE":             "SIMPLE",
+	"DOUBLE":             "DOBLE",
+	"TRIPLE":             "TRIPLE",
+	"TETRIS":             "TETRIS",
+	"T-SPIN MINI":        "T-SPIN MINI",
+	"T-SPIN MINI SINGLE": "T-SPIN MINI SIMPLE",
+	"T-SPIN":             "T-SPIN",
+	"T-SPIN SINGLE":      "T-SPIN SIMPLE",
+	"T-SPIN DOUBLE":      "T-SPIN DOBLE",
+	"T-SPIN TRIPLE":      "T-SPIN TRIPLE",
+	"LEVEL":              "NIVEL",
+	"LINES":              "LÍNEAS",
+	"ZONE":               "ZONA",
+	"CLEAN":              "LIMPIO",
+	"MISSED":             "FALLADO",
+	"PERFECT CLEAR":      "TABLERO LIMPIO",
+
+	// Overlays
+	"GO":           "YA",
+	"CREDIT ROLL":  "CRÉDITOS",
+	"the stack":    "la pila",
+	"is invisible": "es invisible",
+	"per row":      "por fila",
+
+	// Keys
+	"quit":                     "salir",
+	"help":                     "ayuda",
+	"all keys":                 "todas las teclas",
+	"stream layout":            "vista de directo",
+	"coach":                    "entrenador",
+	"move left":                "mover a la izquierda",
+	"move right":               "mover a la derecha",
+	"move up":                  "mover arriba",
+	"move down":                "mover abajo",
+	"rotate clockwise":         "girar a la derecha",
+	"rotate counter-clockwise": "girar a la izquierda",
+	"toggle soft drop":         "alternar caída suave",
+	"soft drop":                "caída suave",
+	"lock":                     "fijar",
+	"hard drop":                "caída dura",
+	"hold":                     "reservar",
+	"copy share text":          "copiar texto",
+	"retry same seed":          "repetir con la misma semilla",
+	"suspend":                  "suspender",
+	"copy fumen":               "copiar fumen",
+	"undo placement":           "deshacer colocación",
+	"rewind 10s":               "retroceder 10s",
+	"enter zone":               "entrar en la zona",
+	"pause gravity":            "pausar gravedad",
+	"edit board":               "editar tablero",
+	"pick next":                "elegir siguiente",
+	"cursor left":              "cursor a la izquierda",
+	"cursor right":             "cursor a la derecha",
+	"cursor up":                "cursor arriba",
+	"cursor down":              "cursor abajo",
+	"fill or clear cell":       "llenar o vaciar celda",
+	"start game":               "empezar partida",
+	"lifetime stats":           "estadísticas",
+}
@@ -0,0 +1,41 @@
+package locale
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap is a key map whose help is shown in the language of a locale.
+type keyMap struct {
+	keys   help.KeyMap
+	locale *Locale
+}
+
+// Keys returns the key map with the help of its bindings in the locale's language, to be shown by a help.Model.
+func (l *Locale) Keys(keys help.KeyMap) help.KeyMap {
+	return keyMap{keys: keys, locale: l}
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return k.locale.Bindings(k.keys.ShortHelp())
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	columns := k.keys.FullHelp()
+	translated := make([][]key.Binding, len(columns))
+	for i, column := range columns {
+		translated[i] = k.locale.Bindings(column)
+	}
+	return translated
+}
+
+// Bindings returns copies of the bindings with the description in their help in the locale's language. The bindings
+// themselves are left as they are.
+func (l *Locale) Bindings(bindings []key.Binding) []key.Binding {
+	translated := make([]key.Binding, len(bindings))
+	for i, b := range bindings {
+		b.SetHelp(b.Help().Key, l.T(b.Help().Desc))
+		translated[i] = b
+	}
+	return translated
+}
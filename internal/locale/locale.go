@@ -0,0 +1,90 @@
+// Package locale translates the text of the interface into the player's language. Text is looked up by its English
+// wording, so anything missing from a catalog is shown in English.
+package locale
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Default is the language the interface is written in.
+const Default = "en"
+
+// catalogs hold the translations of each language other than English, keyed by the English text.
+var catalogs = map[string]map[string]string{
+	"es": spanish,
+}
+
+// Locale is a language the interface can be shown in. The nil locale shows it in English.
+type Locale struct {
+	name     string
+	messages map[string]string
+}
+
+// Names returns the names of the languages the interface can be shown in, such as "es".
+func Names() []string {
+	names := []string{Default}
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	slices.Sort(names[1:])
+	return names
+}
+
+// Get returns the locale for the language with the given name, such as "es".
+func Get(name string) (*Locale, error) {
+	if name == Default {
+		return &Locale{name: Default}, nil
+	}
+	messages, ok := catalogs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown language %q: must be one of %s", name, strings.Join(Names(), ", "))
+	}
+	return &Locale{name: name, messages: messages}, nil
+}
+
+// FromEnv returns the name of the language set by the environment, in the variables LC_ALL, LC_MESSAGES and LANG, in
+// that order, or Default if none of them name a language there is a catalog for.
+func FromEnv() string {
+	for _, variable := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(variable)
+		if value == "" {
+			continue
+		}
+		return Parse(value)
+	}
+	return Default
+}
+
+// Parse returns the name of the language of a POSIX locale, such as "es" for "es_ES.UTF-8", or Default if there is no
+// catalog for it.
+func Parse(posix string) string {
+	name, _, _ := strings.Cut(posix, "_")
+	name, _, _ = strings.Cut(name, ".")
+	name = strings.ToLower(name)
+	if _, ok := catalogs[name]; ok {
+		return name
+	}
+	return Default
+}
+
+// Name returns the name of the locale's language, such as "es".
+func (l *Locale) Name() string {
+	if l == nil {
+		return Default
+	}
+	return l.name
+}
+
+// T returns the translation of the English text, or the text itself if it has not been translated.
+func (l *Locale) T(text string) string {
+	if l == nil {
+		return text
+	}
+	if translated, ok := l.messages[text]; ok {
+		return translated
+	}
+	return text
+}
@@ -0,0 +1,114 @@
+package locale
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestNames(t *testing.T) {
+	want := []string{"en", "es"}
+	if got := Names(); !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestLocale_T(t *testing.T) {
+	es, err := Get("es")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	en, err := Get("en")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	var none *Locale
+
+	tt := map[string]struct {
+		locale *Locale
+		text   string
+		want   string
+	}{
+		"translated":     {es, "Score", "Puntos"},
+		"not translated": {es, "PPS", "PPS"},
+		"english":        {en, "Score", "Score"},
+		"nil":            {none, "Score", "Score"},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.locale.T(tc.text); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGet_Unknown(t *testing.T) {
+	if _, err := Get("tlh"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tt := map[string]string{
+		"es_ES.UTF-8": "es",
+		"es":          "es",
+		"ES_MX":       "es",
+		"en_GB.UTF-8": "en",
+		"fr_FR.UTF-8": "en",
+		"C":           "en",
+	}
+
+	for posix, want := range tt {
+		t.Run(posix, func(t *testing.T) {
+			if got := Parse(posix); got != want {
+				t.Errorf("want %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_AR.UTF-8")
+	if got := FromEnv(); got != "es" {
+		t.Errorf("want %q, got %q", "es", got)
+	}
+
+	t.Setenv("LC_ALL", "C")
+	if got := FromEnv(); got != "en" {
+		t.Errorf("want %q, got %q", "en", got)
+	}
+}
+
+// keys is a key map of a single binding, shown in both the short and full help.
+type keys struct {
+	binding key.Binding
+}
+
+func (k keys) ShortHelp() []key.Binding  { return []key.Binding{k.binding} }
+func (k keys) FullHelp() [][]key.Binding { return [][]key.Binding{{k.binding}} }
+
+func TestLocale_Keys(t *testing.T) {
+	es, err := Get("es")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	k := keys{key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit"), key.WithDisabled())}
+
+	translated := es.Keys(k)
+	for name, b := range map[string]key.Binding{"short": translated.ShortHelp()[0], "full": translated.FullHelp()[0][0]} {
+		if got := b.Help(); got.Key != "esc" || got.Desc != "salir" {
+			t.Errorf("%s: want esc to be described as %q, got %q for %q", name, "salir", got.Desc, got.Key)
+		}
+		if b.Enabled() {
+			t.Errorf("%s: want the binding still disabled", name)
+		}
+	}
+	if got := k.binding.Help().Desc; got != "quit" {
+		t.Errorf("Original: want %q left as it was, got %q", "quit", got)
+	}
+}
@@ -0,0 +1,29 @@
+package marathon
+
+import "fmt"
+
+// adaptiveStart is the difficulty adaptive games start at, where gravity is at normal speed and no garbage is sent.
+const adaptiveStart = 0.5
+
+// adapt adjusts the difficulty of an adaptive game after a placement which cleared the lines. Gravity speeds up or
+// slows down straight away, and any garbage sent waits to rise until a placement doesn't clear lines, as it does when
+// received from an opponent, so it can still be cancelled.
+func (m *Model) adapt(lines int) {
+	if m.difficulty == nil {
+		return
+	}
+	m.difficulty.Place(m.matrix, lines)
+	m.gravity.SetSpeed(m.speed * m.difficulty.Speed())
+	if garbage := m.difficulty.Garbage(); garbage > 0 {
+		m.receiveGarbage(garbage)
+	}
+}
+
+// difficultyView shows the difficulty of an adaptive game as a percentage, and the speed of gravity it gives.
+func (m *Model) difficultyView() string {
+	if m.difficulty == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s: %.0f%%\n%s: %.2f%s\n", m.styles.text("Pressure"), m.difficulty.Level()*100,
+		m.styles.text("Gravity"), m.difficulty.Speed(), m.styles.glyphs.times)
+}
@@ -0,0 +1,82 @@
+package marathon
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/save"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autosaveInterval is the time played between each snapshot of a game in progress.
+const autosaveInterval = 5 * time.Second
+
+// autosaver writes the snapshots of a game to its autosave file. Snapshots are written by commands, so one may still be
+// being written when the game ends; the lock makes sure it can't be written after the file has been removed.
+type autosaver struct {
+	path    string
+	mu      sync.Mutex
+	removed bool
+}
+
+// write writes the snapshot to the file, unless it has already been removed.
+func (a *autosaver) write(g *save.Game) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.removed {
+		return nil
+	}
+	return save.Write(a.path, g)
+}
+
+// remove deletes the file, and stops any later snapshots being written to it.
+func (a *autosaver) remove() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removed = true
+	return save.Remove(a.path)
+}
+
+// autosaveMsg is sent when a snapshot of the game has been written.
+type autosaveMsg struct {
+	id  int
+	err error
+}
+
+// autosave snapshots the game every autosaveInterval of play, so that it can be restored if tetrigo exits without the
+// game ending, such as when it crashes or an SSH session drops. Snapshots are only taken while a tetrimino is falling,
+// since one is saved along with the stack. The snapshot is taken now, but written to the file by the returned command.
+func (m *Model) autosave() tea.Cmd {
+	if m.autosaver == nil || m.gameOver || m.entering() || m.timer.Elapsed()-m.autosavedAt < autosaveInterval {
+		return nil
+	}
+	m.autosavedAt = m.timer.Elapsed()
+	g, err := m.save()
+	if err != nil {
+		m.autosaveErr = err
+		return nil
+	}
+	id, a := m.id, m.autosaver
+	return func() tea.Msg {
+		return autosaveMsg{id: id, err: a.write(g)}
+	}
+}
+
+// removeAutosave deletes the snapshot of the game once there is nothing to restore, because the game has ended, been
+// quit, or been suspended to the save file instead.
+func (m *Model) removeAutosave() {
+	if m.autosaver != nil {
+		m.autosaveErr = m.autosaver.remove()
+	}
+}
+
+// Abandon removes the snapshot of the game when it is left before it ends, such as by a menu it was started from.
+func (m *Model) Abandon() {
+	m.removeAutosave()
+}
+
+// quit leaves the game, which can't be restored afterwards.
+func (m *Model) quit() tea.Cmd {
+	m.removeAutosave()
+	return tea.Quit
+}
@@ -0,0 +1,100 @@
+package marathon
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boardCache keeps the board as last drawn, so that it is only drawn again once something on it changes, and then only
+// the rows which have changed. Frames are drawn far more often than the board changes.
+type boardCache struct {
+	styles  *Styles
+	matrix  tetris.Matrix // the matrix last drawn, including the ghost and any other marks
+	overlay []string
+	danger  bool
+	rows    []string // each visible row of the matrix as drawn
+	output  string
+}
+
+// BoardView renders the visible part of the matrix. It is used for both local games and remote opponents. A matrix
+// with no rows, such as that of an opponent who has yet to send their board, is drawn as an empty board.
+func BoardView(styles *Styles, matrix *tetris.Matrix) string {
+	if len(*matrix) == 0 {
+		return boardView(styles, tetris.NewDefaultMatrix(), nil, false, nil)
+	}
+	return boardView(styles, *matrix, nil, false, nil)
+}
+
+// boardView renders the visible part of the matrix, with each line of the overlay (if any) in place of a row from the
+// middle, and the border in the danger colour if danger is set. Rows which haven't changed since the board was last
+// drawn are taken from the cache (nil to draw every row). The matrix must not be changed afterwards, since it is kept
+// by the cache.
+func boardView(styles *Styles, matrix tetris.Matrix, overlay []string, danger bool, cache *boardCache) string {
+	valid := cache != nil && cache.styles == styles && len(cache.matrix) == len(matrix) &&
+		len(cache.matrix[0]) == len(matrix[0]) && slices.Equal(cache.overlay, overlay)
+	if valid && cache.danger == danger && slices.EqualFunc(cache.matrix, matrix, bytes.Equal) {
+		return cache.output
+	}
+
+	visible := matrix.VisibleRows()
+	top := matrix.BufferRows()
+	overlayRow := len(matrix) - visible/2 - len(overlay)/2
+	width := len(matrix[0]) * styles.cellWidth()
+	rows := make([]string, visible)
+	var line strings.Builder
+	for row := top; row < len(matrix); row++ {
+		switch {
+		case valid && bytes.Equal(cache.matrix[row], matrix[row]):
+			rows[row-top] = cache.rows[row-top]
+		case row-overlayRow >= 0 && row-overlayRow < len(overlay):
+			rows[row-top] = lipgloss.PlaceHorizontal(width, lipgloss.Center, styles.Overlay.Render(overlay[row-overlayRow]))
+		default:
+			line.Reset()
+			for _, cell := range matrix[row] {
+				line.WriteString(styles.renderCell(cell))
+			}
+			rows[row-top] = line.String()
+		}
+	}
+
+	var output strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			output.WriteByte('\n')
+		}
+		output.WriteString(row)
+		// Taller cells repeat each row of the matrix, except for overlay text, which is only shown once.
+		for j := 1; j < styles.cellHeight; j++ {
+			output.WriteByte('\n')
+			if i+top-overlayRow >= 0 && i+top-overlayRow < len(overlay) {
+				output.WriteString(strings.Repeat(" ", width))
+			} else {
+				output.WriteString(row)
+			}
+		}
+	}
+
+	playfield := styles.Playfield
+	if danger {
+		playfield = styles.PlayfieldDanger
+	}
+	board := playfield.Render(output.String())
+	if styles.rowNumbers {
+		var rowIndicator strings.Builder
+		for i := 1; i <= visible; i++ {
+			rowIndicator.WriteString(strconv.Itoa(i))
+			rowIndicator.WriteString(strings.Repeat("\n", styles.cellHeight))
+		}
+		board = lipgloss.JoinHorizontal(lipgloss.Center, board, styles.RowIndicator.Render(rowIndicator.String()))
+	}
+
+	if cache != nil {
+		*cache = boardCache{styles: styles, matrix: matrix, overlay: overlay, danger: danger, rows: rows, output: board}
+	}
+	return board
+}
@@ -0,0 +1,76 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateCheatSheet handles messages while the cheat sheet is shown. Any key other than quit closes it and resumes the
+// game.
+func (m Model) updateCheatSheet(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit) {
+			return m, m.quit()
+		}
+		m.cheatSheet = false
+		m.timer.Start()
+	case frameMsg:
+		// Frames keep coming while the timer is stopped, so that the game resumes as soon as the cheat sheet is closed.
+		if msg.id == m.id {
+			return m, frame(m.id)
+		}
+	}
+	return m, nil
+}
+
+// cheatSheetView lists every enabled binding by category, with all the keys bound to each, two categories to a row. It
+// is built from the key map, so it shows the keys the game actually responds to.
+func (m *Model) cheatSheetView() string {
+	var groups []string
+	for _, group := range m.keys.Groups() {
+		var keys, descs []string
+		width := 0
+		for _, b := range group.Bindings {
+			if !b.Enabled() {
+				continue
+			}
+			names := make([]string, len(b.Keys()))
+			for i, k := range b.Keys() {
+				names[i] = keyName(k)
+			}
+			keys = append(keys, strings.Join(names, ", "))
+			descs = append(descs, m.styles.text(b.Help().Desc))
+			width = max(width, len(keys[len(keys)-1]))
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		var output strings.Builder
+		output.WriteString(m.styles.NewBest.Render(m.styles.text(group.Name)))
+		for i := range keys {
+			output.WriteString(fmt.Sprintf("\n%-*s  %s", width, keys[i], descs[i]))
+		}
+		groups = append(groups, m.styles.CheatSheet.Render(output.String()))
+	}
+
+	rows := []string{m.styles.Overlay.Render(m.styles.text("KEYS"))}
+	for i := 0; i < len(groups); i += 2 {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, groups[i:min(i+2, len(groups))]...))
+	}
+	rows = append(rows, m.styles.Hint.Render(m.styles.text("Press any key to resume")))
+	return lipgloss.JoinVertical(lipgloss.Center, rows...)
+}
+
+// keyName returns the name of a key as shown to the player.
+func keyName(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
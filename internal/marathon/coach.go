@@ -0,0 +1,81 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/bot"
+)
+
+// reviewPlacement has the coach review where the current tetrimino is locking, against where the bot would have placed
+// it from where it spawned, while the coach panel is shown. Big games aren't reviewed, since the bot plays on a board
+// of single cells.
+func (m *Model) reviewPlacement() {
+	if !m.coaching || m.currentTet.Scale > 1 {
+		return
+	}
+	spawned, err := m.bag.Spawned(m.currentTet.Value)
+	if err != nil {
+		m.review, m.reviewErr = nil, err
+		return
+	}
+	// The review starts from the stack as it was, with the tetrimino back where it spawned.
+	matrix := m.matrix.Clone()
+	err = matrix.RemoveTetrimino(m.currentTet)
+	if err == nil {
+		err = matrix.AddTetrimino(spawned)
+	}
+	if err != nil {
+		m.review, m.reviewErr = nil, fmt.Errorf("failed to find where %c spawned: %w", m.currentTet.Value, err)
+		return
+	}
+	m.review, m.reviewErr = m.coach.Review(matrix, spawned, m.currentTet)
+	m.reviewed = m.currentTet.Value
+}
+
+// coachView shows the review of the last placement: the holes it created and, if the bot found a better placement,
+// the moves to make it from where the tetrimino spawned.
+func (m *Model) coachView() string {
+	output := m.styles.text("Coach") + ":\n"
+	switch {
+	case m.reviewErr != nil:
+		output += fmt.Sprintf("Failed to review: %v\n", m.reviewErr)
+	case m.review == nil:
+		output += "Place a tetrimino to have it reviewed\n"
+	default:
+		r := m.review
+		output += fmt.Sprintf("%c placed\n", m.reviewed)
+		switch {
+		case r.HolesCreated > 0:
+			output += fmt.Sprintf("%d holes created\n", r.HolesCreated)
+		case r.HolesCreated < 0:
+			output += fmt.Sprintf("%d holes uncovered\n", -r.HolesCreated)
+		default:
+			output += "No holes created\n"
+		}
+		if r.Better == nil {
+			output += "The bot agrees\n"
+		} else {
+			output += "The bot would play:\n" + actionsLabel(r.Better) + "\n"
+		}
+	}
+	return m.styles.renderPanel(m.styles.History, output)
+}
+
+// actionsLabel describes the bot's actions, counting runs of the same action, such as "left x2, hard drop".
+func actionsLabel(actions []bot.Action) string {
+	var parts []string
+	for i := 0; i < len(actions); {
+		n := 1
+		for i+n < len(actions) && actions[i+n] == actions[i] {
+			n++
+		}
+		if n > 1 {
+			parts = append(parts, fmt.Sprintf("%v x%d", actions[i], n))
+		} else {
+			parts = append(parts, actions[i].String())
+		}
+		i += n
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,44 @@
+package marathon
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// dailyCheese is the number of rows of cheese dug out in the daily challenge.
+const dailyCheese = 10
+
+// DailyDate returns the date of the daily challenge being played at the given time. Days start at midnight UTC, so
+// everyone plays the same challenge at once wherever they are.
+func DailyDate(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// dailySeed returns the seed the tetriminos and cheese of the daily challenge for the date are dealt from.
+func dailySeed(date string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("tetrigo daily " + date))
+	seed := int64(h.Sum64() >> 1)
+	if seed == 0 {
+		// A seed of 0 would be replaced with a random one.
+		seed = 1
+	}
+	return seed
+}
+
+// dailyInput returns the input for the daily challenge of in.Daily: a cheese race at level 1 on the guideline's board,
+// dealt from the day's seed. Settings which would change the board or the tetriminos dealt are ignored.
+func dailyInput(in *Input) *Input {
+	daily := *in
+	daily.Level = 1
+	daily.Seed = dailySeed(in.Daily)
+	daily.Cheese, daily.CheeseTotal = dailyCheese, 0
+	daily.Matrix = nil
+	daily.Width, daily.Height = 0, 0
+	daily.Randomizer = ""
+	daily.Classic, daily.Master, daily.Invisible, daily.ComboPractice = false, false, false, false
+	daily.LineGoal, daily.TimeLimit, daily.Dig = 0, 0, 0
+	daily.TSpinDrills = 0
+	daily.Puzzle = nil
+	return &daily
+}
@@ -0,0 +1,30 @@
+package marathon
+
+import "github.com/Broderick-Westrope/tetrigo/internal/audio"
+
+// updateDanger warns once the stack reaches the danger row, playing a sound as the warning starts, and stops warning
+// once it drops back below. Invisible games don't warn, since it would give away the height of the stack.
+func (m *Model) updateDanger() {
+	if m.dangerRow <= 0 || m.fade != nil {
+		return
+	}
+	limit := m.matrix.BufferRows() + m.dangerRow - 1
+	danger := m.stackTop() <= limit
+	if danger && !m.danger && !m.gameOver {
+		m.audio.Play(audio.Danger)
+	}
+	m.danger = danger
+}
+
+// stackTop returns the index of the highest row of the matrix with a cell of the stack in it, not counting the
+// current tetrimino, or the number of rows if the stack is empty.
+func (m *Model) stackTop() int {
+	for row := range m.matrix {
+		for col, cell := range m.matrix[row] {
+			if cell != 0 && (m.entering() || !m.occupies(row, col)) {
+				return row
+			}
+		}
+	}
+	return len(m.matrix)
+}
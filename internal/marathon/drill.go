@@ -0,0 +1,63 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// DefaultTSpinDrills is how many T-spin drills are played in a game of the mode.
+const DefaultTSpinDrills = 10
+
+// nextDrill replaces the board with the next T-spin drill's, and deals its queue.
+func (m *Model) nextDrill() error {
+	d, err := m.drills.Next()
+	if err != nil {
+		return err
+	}
+	bag, err := tetris.NewFixedBag(d.Matrix, d.Queue)
+	if err != nil {
+		return fmt.Errorf("failed to create bag: %w", err)
+	}
+	m.drill, m.matrix, m.bag = d, d.Matrix, bag
+	return nil
+}
+
+// finishDrill counts the drill which was just played, then finishes the game if it was the last, or moves on to the
+// next drill.
+func (m *Model) finishDrill(executed bool) {
+	m.drillsPlayed++
+	outcome := "missed"
+	if executed {
+		m.drillsExecuted++
+		outcome = "clean"
+	}
+	m.addPopup(strings.ToUpper(outcome))
+	m.logEvent("Drill %d %s", m.drillsPlayed, outcome)
+	m.narrate("drill %s, %d of %d clean", outcome, m.drillsExecuted, m.drillsPlayed)
+
+	if m.drillsPlayed >= m.drillCount {
+		m.gameOver = true
+		m.completed = true
+		return
+	}
+	err := m.nextDrill()
+	if err != nil {
+		m.fail(fmt.Errorf("failed to start drill: %w", err))
+	}
+}
+
+// drillView returns the lines the drills add to the information panel: the drill being played, the T-spin it is for
+// and how many have been executed so far.
+func (m *Model) drillView() string {
+	if m.drills == nil {
+		return ""
+	}
+	output := fmt.Sprintf("%s: %d/%d\n", m.styles.text("Drill"), min(m.drillsPlayed+1, m.drillCount), m.drillCount)
+	if m.drill != nil {
+		output += m.styles.text(m.drill.Kind.String()) + "\n"
+	}
+	output += fmt.Sprintln(m.styles.text("Clean")+": ", m.drillsExecuted)
+	return output
+}
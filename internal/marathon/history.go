@@ -0,0 +1,34 @@
+package marathon
+
+import (
+	"fmt"
+	"time"
+)
+
+// historyLength is the number of events listed in the history panel. Older events scroll off the top.
+const historyLength = 16
+
+// event is something which happened during the game, listed in the history panel.
+type event struct {
+	at   time.Duration // the time played when it happened
+	text string
+}
+
+// logEvent adds an event to the history, dropping the oldest once the panel is full. Events are kept even while the
+// panel is hidden, since the stream layout can be switched to at any time.
+func (m *Model) logEvent(format string, args ...any) {
+	m.history = append(m.history, event{at: m.timer.Elapsed(), text: fmt.Sprintf(format, args...)})
+	if len(m.history) > historyLength {
+		m.history = m.history[len(m.history)-historyLength:]
+	}
+}
+
+// historyView lists the recent events with the time played when each happened, oldest first.
+func (m *Model) historyView() string {
+	output := "History:\n"
+	for _, e := range m.history {
+		minutes, seconds := int(e.at/time.Minute), (e.at % time.Minute).Seconds()
+		output += fmt.Sprintf("%02d:%04.1f %s\n", minutes, seconds, e.text)
+	}
+	return m.styles.renderPanel(m.styles.History, output)
+}
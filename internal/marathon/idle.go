@@ -0,0 +1,69 @@
+package marathon
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// checkIdle pauses the game once it has gone without input for the idle timeout, so that the stack doesn't top out
+// while nobody is playing. Games played by the bot never pause.
+func (m *Model) checkIdle() {
+	if m.idleTimeout <= 0 || m.bot != nil || m.gameOver || m.timer.Elapsed()-m.lastInput < m.idleTimeout {
+		return
+	}
+	m.idle = true
+	m.timer.Stop()
+	m.narrate("paused, are you still there? press any key to resume")
+}
+
+// updateIdle handles messages while the game is paused for being idle. Any key other than quit, or a MoveMsg, resumes
+// it. The move itself isn't played, since the board was hidden when it was made.
+func (m Model) updateIdle(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit) {
+			return m, m.quit()
+		}
+		m.resumeIdle()
+	case MoveMsg:
+		if msg.ID != m.id || msg.ended {
+			break
+		}
+		m.resumeIdle()
+		if msg.source != nil {
+			return m, m.waitForSource(msg.source)
+		}
+	case GarbageMsg:
+		if msg.ID == m.id {
+			m.receiveGarbage(msg.Lines)
+		}
+	case frameMsg:
+		// Frames keep coming while the timer is stopped, so that the game resumes as soon as someone is back.
+		if msg.id == m.id {
+			return m, frame(m.id)
+		}
+	}
+	return m, nil
+}
+
+// resumeIdle resumes a game paused for being idle, counting the idle timeout again from now.
+func (m *Model) resumeIdle() {
+	m.idle = false
+	m.active()
+	m.timer.Start()
+}
+
+// active counts the idle timeout again from now, as someone has just pressed a key or sent a move.
+func (m *Model) active() {
+	m.lastInput = m.timer.Elapsed()
+}
+
+// idleView asks whether anyone is still playing, in place of the paused game.
+func (m *Model) idleView() string {
+	return lipgloss.JoinVertical(lipgloss.Center,
+		m.styles.Overlay.Render(m.styles.text("ARE YOU STILL THERE?")),
+		"",
+		m.styles.Hint.Render(m.styles.text("The game is paused. Press any key to resume")),
+	)
+}
@@ -1,10 +1,20 @@
 package marathon
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/controls"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 type KeyMap struct {
 	Quit             key.Binding
 	Help             key.Binding
+	CheatSheet       key.Binding // shows every binding, pausing the game
+	Stream           key.Binding // switches between the normal and stream layouts
+	Coach            key.Binding // shows or hides the coach's reviews of each placement
 	Left             key.Binding
 	Right            key.Binding
 	Clockwise        key.Binding
@@ -12,19 +22,77 @@ type KeyMap struct {
 	SoftDrop         key.Binding
 	HardDrop         key.Binding
 	Hold             key.Binding
+	Share            key.Binding // only enabled once the game is over
+	Retry            key.Binding // only enabled once the game is over, in games which can be played again
+	Suspend          key.Binding // only enabled in games which can be suspended
+	Fumen            key.Binding // disabled whenever the stack is invisible
+	Undo             key.Binding // only enabled in games whose placements can be undone
+	Rewind           key.Binding // only enabled in zen games, until they are over
+	Zone             key.Binding // only enabled in zone games
+
+	// Sandbox games can pause gravity, edit the stack and pick the next tetrimino. The cursor and paint keys are only
+	// enabled while editing.
+	Pause       key.Binding
+	Edit        key.Binding
+	Pick        key.Binding
+	CursorLeft  key.Binding
+	CursorRight key.Binding
+	CursorUp    key.Binding
+	CursorDown  key.Binding
+	Paint       key.Binding
 }
 
 func DefaultKeyMap() *KeyMap {
+	p, _ := controls.Lookup(controls.Default)
+	return keyMap(p)
+}
+
+// newKeyMap creates the key map with the named preset's keys bound to gameplay, or the default preset's if there is
+// no such preset.
+func newKeyMap(preset string) *KeyMap {
+	p, _ := controls.Lookup(preset)
+	return keyMap(p)
+}
+
+// bind creates a binding of the keys, with every key listed in its help.
+func bind(keys []string, desc string, opts ...key.BindingOpt) key.Binding {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = keyName(k)
+	}
+	opts = append([]key.BindingOpt{key.WithKeys(keys...), key.WithHelp(strings.Join(names, ", "), desc)}, opts...)
+	return key.NewBinding(opts...)
+}
+
+func keyMap(p controls.Preset) *KeyMap {
 	return &KeyMap{
 		Quit:             key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
 		Help:             key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-		Left:             key.NewBinding(key.WithKeys("j", "a"), key.WithHelp("a, j", "move left")),
-		Right:            key.NewBinding(key.WithKeys("l", "d"), key.WithHelp("d, l", "move right")),
-		Clockwise:        key.NewBinding(key.WithKeys("e", "o"), key.WithHelp("e, o", "rotate clockwise")),
-		CounterClockwise: key.NewBinding(key.WithKeys("q", "u"), key.WithHelp("q, u", "rotate counter-clockwise")),
-		SoftDrop:         key.NewBinding(key.WithKeys("s", "k"), key.WithHelp("s, k", "toggle soft drop")),
-		HardDrop:         key.NewBinding(key.WithKeys("w", "i"), key.WithHelp("w, i", "hard drop")),
-		Hold:             key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "hold")),
+		CheatSheet:       key.NewBinding(key.WithKeys("f1"), key.WithHelp("f1", "all keys")),
+		Stream:           key.NewBinding(key.WithKeys("f2"), key.WithHelp("f2", "stream layout")),
+		Coach:            key.NewBinding(key.WithKeys("f3"), key.WithHelp("f3", "coach")),
+		Left:             bind(p.Left, "move left"),
+		Right:            bind(p.Right, "move right"),
+		Clockwise:        bind(p.Clockwise, "rotate clockwise"),
+		CounterClockwise: bind(p.CounterClockwise, "rotate counter-clockwise"),
+		SoftDrop:         bind(p.SoftDrop, "toggle soft drop"),
+		HardDrop:         bind(p.HardDrop, "hard drop"),
+		Hold:             bind(p.Hold, "hold"),
+		Share:            key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy share text"), key.WithDisabled()),
+		Retry:            key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry same seed"), key.WithDisabled()),
+		Suspend:          bind(p.Suspend, "suspend", key.WithDisabled()),
+		Fumen:            key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "copy fumen")),
+		Undo:             key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "undo placement"), key.WithDisabled()),
+		Rewind:           key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rewind 10s"), key.WithDisabled()),
+		Zone:             key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "enter zone"), key.WithDisabled()),
+		Pause:            key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause gravity"), key.WithDisabled()),
+		Edit:             key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "edit board"), key.WithDisabled()),
+		Pick:             key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "pick next"), key.WithDisabled()),
+		CursorLeft:       key.NewBinding(key.WithKeys("left"), key.WithHelp("left", "cursor left"), key.WithDisabled()),
+		CursorRight:      key.NewBinding(key.WithKeys("right"), key.WithHelp("right", "cursor right"), key.WithDisabled()),
+		CursorUp:         key.NewBinding(key.WithKeys("up"), key.WithHelp("up", "cursor up"), key.WithDisabled()),
+		CursorDown:       key.NewBinding(key.WithKeys("down"), key.WithHelp("down", "cursor down"), key.WithDisabled()),
+		Paint:            key.NewBinding(key.WithKeys("enter", "x"), key.WithHelp("enter, x", "fill or clear cell"), key.WithDisabled()),
 	}
 }
 
@@ -32,6 +100,15 @@ func (k *KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		k.Quit,
 		k.Help,
+		k.CheatSheet,
+		k.Share,
+		k.Retry,
+		k.Suspend,
+		k.Undo,
+		k.Rewind,
+		k.Zone,
+		k.Pause,
+		k.Edit,
 	}
 }
 
@@ -40,6 +117,12 @@ func (k *KeyMap) FullHelp() [][]key.Binding {
 		{
 			k.Quit,
 			k.Help,
+			k.CheatSheet,
+			k.Stream,
+			k.Coach,
+			k.Share,
+			k.Retry,
+			k.Suspend,
 			k.Left,
 		},
 		{
@@ -51,6 +134,63 @@ func (k *KeyMap) FullHelp() [][]key.Binding {
 			k.SoftDrop,
 			k.HardDrop,
 			k.Hold,
+			k.Fumen,
+		},
+		{
+			k.Undo,
+			k.Rewind,
+			k.Zone,
+			k.Pause,
+			k.Edit,
+			k.Pick,
+			k.Paint,
 		},
+		{
+			k.CursorLeft,
+			k.CursorRight,
+			k.CursorUp,
+			k.CursorDown,
+		},
+	}
+}
+
+// KeyGroup is a category of bindings, such as those which move the tetrimino.
+type KeyGroup struct {
+	Name     string
+	Bindings []key.Binding
+}
+
+// Groups returns every binding by category, for the cheat sheet. Disabled bindings are included, so callers should
+// skip them.
+func (k *KeyMap) Groups() []KeyGroup {
+	return []KeyGroup{
+		{Name: "Movement", Bindings: []key.Binding{k.Left, k.Right, k.SoftDrop, k.HardDrop}},
+		{Name: "Rotation", Bindings: []key.Binding{k.Clockwise, k.CounterClockwise, k.Hold}},
+		{Name: "Practice", Bindings: []key.Binding{
+			k.Coach, k.Undo, k.Rewind, k.Zone, k.Pause, k.Edit, k.Pick,
+			k.CursorLeft, k.CursorRight, k.CursorUp, k.CursorDown, k.Paint,
+		}},
+		{Name: "System", Bindings: []key.Binding{k.Quit, k.Help, k.CheatSheet, k.Stream, k.Share, k.Retry, k.Suspend, k.Fumen}},
+	}
+}
+
+// move returns the gameplay move bound to the key, if any.
+func (k *KeyMap) move(msg tea.KeyMsg) (tetris.Move, bool) {
+	switch {
+	case key.Matches(msg, k.Left):
+		return tetris.MoveLeft, true
+	case key.Matches(msg, k.Right):
+		return tetris.MoveRight, true
+	case key.Matches(msg, k.Clockwise):
+		return tetris.MoveClockwise, true
+	case key.Matches(msg, k.CounterClockwise):
+		return tetris.MoveCounterClockwise, true
+	case key.Matches(msg, k.SoftDrop):
+		return tetris.MoveSoftDrop, true
+	case key.Matches(msg, k.HardDrop):
+		return tetris.MoveHardDrop, true
+	case key.Matches(msg, k.Hold):
+		return tetris.MoveHold, true
 	}
+	return 0, false
 }
@@ -0,0 +1,72 @@
+package marathon
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/leaderboard"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// submissionMsg is sent once the results of a finished game have been submitted to the leaderboard, or queued to be.
+type submissionMsg struct {
+	id   int
+	sent bool // whether the results were sent, rather than queued because the leaderboard couldn't be reached
+	err  error
+}
+
+// submitScore returns a command which submits the results to the leaderboard, with the hash of the game's replay so
+// that they can be checked against it. Every finished game of a mode which keeps personal bests is submitted, whether
+// or not it set one, but only once the replay has been played again to check it, where it can be.
+func (m *Model) submitScore(results Results) tea.Cmd {
+	if m.leaderboard == nil || results.Flagged || (m.race && !results.Completed) || m.err != nil {
+		return nil
+	}
+
+	id, client := m.id, m.leaderboard
+	entry := leaderboard.Entry{
+		Player: m.player,
+		Mode:   m.mode,
+		Score:  results.Score,
+		Lines:  results.Lines,
+		Time:   results.Time,
+		Grade:  results.Grade,
+		Seed:   m.seed,
+		Date:   m.replay.Date,
+	}
+	hash, err := m.replay.Hash()
+	if err != nil {
+		return func() tea.Msg { return submissionMsg{id: id, err: err} }
+	}
+	entry.ReplayHash = hash
+	r, verify := m.replay, m.verifiable()
+	return func() tea.Msg {
+		if verify {
+			err := replay.Verify(r, entry.Score, entry.Lines)
+			if err != nil {
+				return submissionMsg{id: id, err: fmt.Errorf("failed to verify replay: %w", err)}
+			}
+		}
+		sent, err := client.Submit(entry)
+		return submissionMsg{id: id, sent: sent, err: err}
+	}
+}
+
+// verifiable reports whether the game's replay can be played again by replay.Verify. Puzzles don't deal their
+// tetriminos from a randomizer, and neither the minos of big games nor the lines stacked in the zone are followed.
+func (m *Model) verifiable() bool {
+	return m.replay.Randomizer != "" && !m.in.Big && !m.in.Zone
+}
+
+// submissionView reports whether the results were submitted to the leaderboard, once they have been.
+func (m *Model) submissionView() string {
+	switch {
+	case m.submission == nil:
+		return ""
+	case m.submission.err != nil:
+		return m.styles.Hint.Render(m.styles.text("Failed to submit to the leaderboard:") + "\n" + m.submission.err.Error())
+	case m.submission.sent:
+		return m.styles.Hint.Render(m.styles.text("Submitted to the leaderboard"))
+	}
+	return m.styles.Hint.Render(m.styles.text("Leaderboard unreachable, queued to submit later"))
+}
@@ -0,0 +1,54 @@
+package marathon
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// garbageMeter draws a bar beside the matrix which rises with the garbage about to be added to the stack. In versus it
+// shows a row for each line received, which rises when the next tetrimino locks unless cancelled by clearing lines. In
+// dig races it fills up as the next line of garbage is about to rise.
+func (m *Model) garbageMeter() string {
+	visible := m.matrix.VisibleRows()
+	style := m.styles.GarbageMeter
+	var filled int
+	if m.rise != nil {
+		style = m.styles.GarbagePreview
+		if length := m.rise.Length(); length > 0 {
+			progress := 1 - m.rise.Remaining().Seconds()/length.Seconds()
+			filled = int(math.Ceil(progress * float64(visible)))
+		}
+	} else {
+		filled = len(m.pendingHoles)
+	}
+	return m.meterView(style, filled)
+}
+
+// meterView draws a bar beside the matrix, filled from the bottom up to the given number of visible rows.
+func (m *Model) meterView(style lipgloss.Style, filled int) string {
+	visible := m.matrix.VisibleRows()
+	filled = min(max(filled, 0), visible)
+
+	meter := style.Render(m.styles.glyphs.meter)
+	empty := strings.Repeat(" ", len([]rune(m.styles.glyphs.meter)))
+	// Lines beside the top and bottom borders of the playfield, if it has them, are left empty.
+	var lines []string
+	for i := 0; i < m.styles.Playfield.GetBorderTopSize(); i++ {
+		lines = append(lines, empty)
+	}
+	for row := 0; row < visible; row++ {
+		cell := empty
+		if visible-row <= filled {
+			cell = meter
+		}
+		for i := 0; i < m.styles.cellHeight; i++ {
+			lines = append(lines, cell)
+		}
+	}
+	for i := 0; i < m.styles.Playfield.GetBorderBottomSize(); i++ {
+		lines = append(lines, empty)
+	}
+	return strings.Join(lines, "\n")
+}
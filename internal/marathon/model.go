@@ -1,283 +1,3152 @@
 package marathon
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/bot"
+	"github.com/Broderick-Westrope/tetrigo/internal/drill"
+	"github.com/Broderick-Westrope/tetrigo/internal/export"
+	"github.com/Broderick-Westrope/tetrigo/internal/fumen"
+	"github.com/Broderick-Westrope/tetrigo/internal/input"
+	"github.com/Broderick-Westrope/tetrigo/internal/leaderboard"
+	"github.com/Broderick-Westrope/tetrigo/internal/preset"
+	"github.com/Broderick-Westrope/tetrigo/internal/puzzle"
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/save"
+	"github.com/Broderick-Westrope/tetrigo/internal/share"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/netplay"
 	"github.com/Broderick-Westrope/tetrigo/tetris"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/stopwatch"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
+type Input struct {
+	Level     uint
+	LineGoal  uint          // the game finishes once this many lines are cleared (0 for endless)
+	TimeLimit time.Duration // the game finishes once this much time has elapsed (0 for no limit)
+	Bot       bool          // whether the game is played by the built-in bot instead of the keyboard
+	Versus    bool          // whether the game is played against an opponent, showing attack statistics
+	Seed      int64         // seed for the order of tetriminos (0 for a random seed)
+	Strict    bool          // whether inputs that are physically impossible are rejected and the game flagged
+
+	// HardDropOnly disables soft drop, so that every tetrimino is hard dropped, as in hard drop drills.
+	HardDropOnly bool
+
+	// GarbageMessiness is the probability (0 to 1) that the hole in received garbage changes column on each line.
+	GarbageMessiness float64
+	// GarbageHoles is how the hole in received and rising garbage moves from line to line (tetris.HolesMessy, the
+	// default, moves it with GarbageMessiness), and GarbageHoleWidth the columns each hole spans (0 for 1).
+	GarbageHoles     tetris.HolePattern
+	GarbageHoleWidth int
+	// AttackTable is the lines sent to opponents for each kind of line clear (nil for the guideline's table).
+	AttackTable *tetris.AttackTable
+	// GarbageMultiplier multiplies the lines sent to opponents, such as 0.5 to handicap a stronger player (0 for 1).
+	GarbageMultiplier float64
+	// StartingGarbage is the rows of garbage the board starts with, as a handicap for a stronger player.
+	StartingGarbage uint
+
+	// HoldPreview shows where the held tetrimino would land if it was swapped in now.
+	HoldPreview bool
+	// Hold is how often the current tetrimino can be held. Classic games and puzzles always play without hold. Games
+	// with unlimited hold are not recorded.
+	Hold tetris.HoldRule
+	// HoldSlots is how many tetriminos can be held at once (0 for 1). With more than one, each hold deals the next
+	// tetrimino until every slot is filled, then swaps in the one held longest. Games with more than one are not
+	// recorded.
+	HoldSlots int
+
+	// Matrix is the board the game starts from, such as a preset (nil for an empty board). The game is played on a
+	// matrix of its size.
+	Matrix *tetris.Matrix
+	// Width and Height are the size of the empty board the game starts from otherwise, in columns and visible rows (0
+	// for the size given by the guideline, 10 by 20). Games on boards of other sizes are not recorded.
+	Width, Height int
+	// Randomizer is the name of the randomizer dealing the tetriminos (see tetris.RandomizerNames), or empty for the
+	// mode's own (see defaultRandomizer). Combo practice and puzzles deal their own tetriminos. Games dealt by another
+	// randomizer are not recorded.
+	Randomizer string
+
+	// Classic plays by the rules of the NES version: tetriminos are dealt by its randomizer instead of in bags, fall
+	// at its speeds and score its points, and there is no hold, ghost or hard drop, and only the next tetrimino is shown.
+	Classic bool
+
+	// Master plays with instant gravity (20G), so tetriminos land as soon as they spawn and the challenge is to move
+	// them into place within the lock delay. The next tetrimino spawns after an entry delay, and both delays shorten
+	// as the level increases. Soft drop locks the tetrimino straight away.
+	Master bool
+	// Grading is the name of the grader master mode games are graded by (see tetris.GraderNames), or empty for
+	// "lines".
+	Grading string
+
+	// Cheese starts the game with this many rows of garbage beneath the stack, each with a single hole in a different
+	// column to the row above, and the game finishes once they have all been dug out.
+	Cheese uint
+	// CheeseTotal is the number of rows of cheese to dig out in total. New rows rise from the bottom as they are cleared,
+	// keeping Cheese rows on the board until this many have been added (0 or at most Cheese for no new rows).
+	CheeseTotal uint
+	// CheeseHoles and CheeseHoleWidth place the holes in cheese as GarbageHoles and GarbageHoleWidth do for garbage.
+	// With tetris.HolesMessy, the default, the hole moves on every line.
+	CheeseHoles     tetris.HolePattern
+	CheeseHoleWidth int
+
+	// Daily plays the daily challenge for the date, such as "2026-10-14" (see DailyDate), instead of the mode given by
+	// the rest of the input: a cheese race whose tetriminos and cheese are dealt from the same seed for everyone that
+	// day. The best time is kept for each day.
+	Daily string
+
+	// Dig rises a line of garbage from the bottom of the board at this interval, and the game lasts until the player
+	// tops out (0 for no rising garbage). Dig races are scored by the seconds survived plus the lines of garbage dug.
+	Dig time.Duration
+
+	// Invisible hides locked tetriminos once they have been on the stack for the fade delay, so the player has to
+	// remember the stack. There is no ghost or hold preview, and the whole stack is revealed when the game ends.
+	Invisible bool
+	// FadeDelay is how long locked tetriminos stay visible in invisible games (0 to hide them as soon as they lock).
+	FadeDelay time.Duration
+
+	// Puzzle replaces the board with the puzzle's, and the bag with its queue of tetriminos. The game finishes once
+	// the puzzle is solved, or ends once the queue runs out. There is no hold, since the queue is part of the puzzle.
+	Puzzle *puzzle.Puzzle
+
+	// Interludes pause the game briefly to show the new level and speed whenever the level increases.
+	// They are never shown in versus, puzzles, or games with a line goal or time limit, since they would affect the result.
+	Interludes bool
+
+	// History shows a panel beside the board listing recent events, such as tetriminos locking and garbage being
+	// received, with the time played when each happened.
+	History bool
+
+	// Stream starts the game in the stream layout, which draws the score, level and lines large and always shows the
+	// history, for people watching. It can be switched to and from at any time.
+	Stream bool
+	// Webcam is the size of a region the stream layout leaves blank for a webcam overlay (zero for none).
+	Webcam Region
+
+	// Theme is the colour scheme the game is drawn with (nil for the default).
+	Theme *theme.Theme
+
+	// Countdown is the number of seconds counted down before the game starts (0 to start immediately).
+	Countdown uint
+
+	// Speed scales every timer of the game, from 0.5 for half speed to 2 for double speed (0 for normal speed): how
+	// quickly tetriminos fall, the lock, entry and line clear delays, DAS and ARR, and how often garbage rises in dig
+	// races. The timer still measures real time, so results are marked as speed-adjusted.
+	Speed float64
+	// EntryDelay is how long the next tetrimino takes to spawn after one locks (ARE), and LineClearDelay how much longer
+	// it takes when the lock clears lines (0 for none). Master mode has its own, which shorten as the level increases.
+	// Games with either are not recorded.
+	EntryDelay     time.Duration
+	LineClearDelay time.Duration
+
+	// Curve is the name of the curve the fall speed follows from level to level, such as "tgm" (empty for the mode's
+	// own). See tetris.CurveByName. Classic and master mode always use their own.
+	Curve string
+	// Gravity is the time a tetrimino takes to fall one row at each level, overriding Curve (nil for the curve).
+	Gravity tetris.Table
+
+	// Records keeps the player's best results, so the summary can show whether a personal best was set (nil to not
+	// keep records). Games played by the bot, at an adjusted speed, in versus, or from a preset are not recorded.
+	Records *records.Store
+	// Player is the name records are kept under (empty for local play).
+	Player string
+	// Leaderboard submits the results of games which are recorded to an online leaderboard (nil to not submit them).
+	Leaderboard *leaderboard.Client
+
+	// Sources are where moves are taken from as well as the keyboard, such as a pipe or votes from a chat. Each is
+	// waited on until it runs out or the game ends.
+	Sources []input.Source
+
+	// Audio plays sounds as tetriminos lock, lines are cleared, the level increases and the game ends (nil for silence).
+	Audio *audio.Player
+
+	// Clipboard is the terminal the share text of the results is copied to, using the OSC 52 escape sequence, which
+	// most terminals pass on to the system clipboard (nil for standard output).
+	Clipboard io.Writer
+
+	// Clock is the source of time for the game (nil for the system time).
+	Clock tetris.Clock
+
+	// Handling is how held keys move the tetrimino (the zero value moves it once for every press the terminal sends),
+	// and the lock-down rule outside of classic and master mode.
+	Handling tetris.Handling
+	// IdleTimeout pauses the game once it has gone this long without input, such as in games over SSH where nobody
+	// may be watching (0 to never pause).
+	IdleTimeout time.Duration
+
+	// Keys is the name of the preset of keys gameplay is bound to, such as "vim" (see controls.Names, empty for the
+	// default).
+	Keys string
+
+	// ReplayPath is the file a replay of the game is written to when it ends, recording every placement so that the
+	// game can be analysed afterwards (empty to not record one).
+	ReplayPath string
+
+	// Events is where every input, spawn, lock and clear is logged as the game is played, for analysis tools and bots
+	// (nil to not log them).
+	Events *replay.EventLog
+
+	// RecordingPath is the file a recording of the game is exported to when it ends, to share: an animated GIF if it
+	// ends in ".gif", or an asciinema cast otherwise (empty to not export one).
+	RecordingPath string
+
+	// SavePath is the file marathon games are saved to when suspended, to be continued later with Resume (empty to not
+	// allow suspending). Games of other modes can't be suspended.
+	SavePath string
+	// AutosavePath is the file marathon games are snapshotted to while they are played, to be restored with Resume if
+	// tetrigo exits before the game ends (empty to not take snapshots). It is removed once the game ends or is quit.
+	AutosavePath string
+
+	// Sandbox lets the player pause gravity, edit the stack with a cursor and pick the tetrimino dealt next, to try out
+	// setups. Sandbox games are not recorded. Placements can be undone in sandbox games and combo practice.
+	Sandbox bool
+
+	// Zen is played at your own pace: the last rewindLength of play can be rewound, taking back every placement since,
+	// to try again. Zen games are not recorded.
+	Zen bool
+
+	// Zone fills a meter beside the board as lines are cleared, which can be spent once a quarter is full to enter the
+	// zone: time stops for 5 seconds for each quarter, and lines cleared in it are stacked at the bottom of the board
+	// rather than removed, until the zone ends and clears them all at once for a bonus growing with their square.
+	Zone bool
+
+	// Big scales every tetrimino up so that each mino is bigScale cells wide and tall, on an empty board bigScale times
+	// the default width (ignoring Width). Tetriminos move and fall a whole mino at a time, and each row of minos
+	// cleared counts as a single line.
+	Big bool
+
+	// Adaptive adjusts the speed of gravity and sends garbage to match how the player has been doing (see
+	// tetris.Difficulty), easing off when the stack is high and pressing harder when it is low.
+	Adaptive bool
+
+	// ComboPractice starts from a 4-wide well (unless Matrix is set), skips tetriminos which are awkward to combo with,
+	// and restores the starting board whenever a tetrimino locks without clearing lines.
+	ComboPractice bool
+
+	// TSpinDrills is how many T-spin drills are played, one after another, before the game finishes (0 for a normal
+	// game). Each drill replaces the board with one generated with a slot for a T-spin double or triple, and deals the
+	// tetrimino roofing it over and then a T to spin into it. There is no hold, and the drill is over once the T locks,
+	// whether or not it was spun into the slot.
+	TSpinDrills uint
+
+	// PCOpeners is how many perfect-clear openers are attempted, one after another, before the game finishes (0 for a
+	// normal game). Each attempt starts from an empty board with nothing held and a fresh bag, and succeeds if the board
+	// is cleared within as many tetriminos as it is wide: the 10 of a perfect clear of four lines on the guideline's
+	// board.
+	PCOpeners uint
+	// PCSession counts the perfect-clear openers attempted and cleared over the session the game is played in, such
+	// as by retrying it, which the game's are added to (nil for a session of its own).
+	PCSession *PCSession
+
+	// Rules add the win and lose conditions and information of a mode defined outside this package (nil for none).
+	// Games played with rules can't be suspended.
+	Rules *Rules
+}
+
+// Results describes how a game went. It is sent in a GameOverMsg when the game ends.
+type Results struct {
+	Score     uint
+	Lines     uint
+	Level     uint
+	Time      time.Duration
+	Completed bool    // whether the goal was reached, rather than the player topping out
+	Attack    uint    // lines of garbage sent to opponents
+	Received  uint    // lines of garbage received from opponents
+	Flagged   bool    // whether illegal inputs were rejected in strict mode
+	MaxCombo  int     // longest run of consecutive tetriminos that cleared lines
+	Pieces    uint    // tetriminos placed
+	Speed     float64 // speed multiplier the game was played at, where 1 is normal speed
+	Dug       uint    // lines of garbage cleared in dig races
+	Grade     string  // grade earned in master mode (empty otherwise)
+
+	// Clears are the number of clears of each kind and the points they were awarded.
+	Clears []tetris.ClearCount
+	// Inputs are the number of times the player made each move.
+	Inputs map[tetris.Move]uint
+}
+
+// SpeedAdjusted reports whether the game was played at other than normal speed.
+func (r Results) SpeedAdjusted() bool {
+	return r.Speed != 1
+}
+
+// SurvivalScore returns the score of a dig race: the whole seconds survived plus the lines of garbage dug.
+func (r Results) SurvivalScore() uint {
+	return uint(r.Time/time.Second) + r.Dug
+}
+
+// Presses returns the number of times the player made any of the given moves.
+func (r Results) Presses(moves ...tetris.Move) uint {
+	var n uint
+	for _, move := range moves {
+		n += r.Inputs[move]
+	}
+	return n
+}
+
+// KPP returns the keys pressed per piece placed, counting every move made.
+func (r Results) KPP() float64 {
+	if r.Pieces == 0 {
+		return 0
+	}
+	var keys uint
+	for _, n := range r.Inputs {
+		keys += n
+	}
+	return float64(keys) / float64(r.Pieces)
+}
+
+// PPS returns the pieces placed per second.
+func (r Results) PPS() float64 {
+	if r.Time <= 0 {
+		return 0
+	}
+	return float64(r.Pieces) / r.Time.Seconds()
+}
+
+// APM returns the attack per minute.
+func (r Results) APM() float64 {
+	if r.Time <= 0 {
+		return 0
+	}
+	return float64(r.Attack) / r.Time.Minutes()
+}
+
+// SuspendedMsg is sent once the game has been saved to be continued later. The game quits when it receives it, unless
+// it is intercepted by the model the game is part of, such as the menu.
+type SuspendedMsg struct {
+	ID int
+}
+
+// GameOverMsg is sent when the game ends, either by reaching the goal or by topping out.
+type GameOverMsg struct {
+	ID      int
+	Results Results
+}
+
+// AttackMsg is sent when a line clear sends garbage to opponents.
+type AttackMsg struct {
+	ID    int
+	Lines uint
+}
+
+// GarbageMsg delivers garbage sent by an opponent to the game with the given ID.
+type GarbageMsg struct {
+	ID    int
+	Lines uint
+}
+
+// MoveMsg makes a gameplay move in the game with the given ID, as if its key had been pressed, such as one taken from
+// the game's sources.
+type MoveMsg struct {
+	ID   int
+	Move tetris.Move
+
+	source input.Source // the source the move was taken from, waited on again once it is made (nil for none)
+	ended  bool         // whether the source ran out instead
+}
+
+var (
+	lastID int
+	idMtx  sync.Mutex
+)
+
+func nextID() int {
+	idMtx.Lock()
+	defer idMtx.Unlock()
+	lastID++
+	return lastID
+}
+
 type Model struct {
+	id         int
 	matrix     tetris.Matrix
 	styles     *Styles
 	help       help.Model
 	keys       *KeyMap
 	currentTet *tetris.Tetrimino
-	holdTet    *tetris.Tetrimino
-	canHold    bool
-	fall       *Fall
+	held       tetris.HoldQueue
+	hold       tetris.HoldRule
+	canHold    bool // whether the current tetrimino can be held
+	gravity    *tetris.Gravity
 	scoring    *tetris.Scoring
 	bag        *tetris.Bag
-	timer      stopwatch.Model
+	clock      tetris.Clock
+	timer      *tetris.Stopwatch // time spent playing, which gravity is based on
+	lineGoal   uint
+	timeLimit  time.Duration
+	gameOver   bool
+	err        error // the fault that ended the game, if the engine failed (nil otherwise)
+	completed  bool
+	rules      *Rules // conditions and information added by the mode (nil for none)
+	dangerRow  int    // the row of the playfield the stack reaching warns of topping out (0 to never warn)
+	levelUpAt  time.Duration
+	levelledUp bool // whether the level has increased, last at levelUpAt in the time played
+	heldAt     time.Duration
+	swapped    bool // whether a tetrimino has been held, last at heldAt in the time played
+	danger     bool // whether the stack has reached the danger row
+	bot        *bot.Bot
+	botActions []bot.Action
+	sources    []input.Source
+	attack     *tetris.Attack
+	stats      *tetris.Statistics
+	isVersus   bool
+	speed      float64
+	classic    bool
+	queueLen   int // number of upcoming tetriminos shown
+
+	// entryDelay runs from when a tetrimino locks until the next spawns, for entryTime, plus lineClearTime if the lock
+	// cleared lines. It is never started if both are 0.
+	entryDelay    *tetris.Delay
+	entryTime     time.Duration
+	lineClearTime time.Duration
+
+	// lockDelay decides when a landed tetrimino locks, under the chosen lock-down rule. It is nil in classic mode,
+	// where tetriminos lock as soon as gravity next lowers them after landing.
+	lockDelay *tetris.LockDelay
+	master    bool
+	// initialInputs are the hold and rotation pressed during the entry delay, applied once the next tetrimino spawns.
+	initialInputs tetris.InitialInputs
+
+	// Master mode ends with a credit roll after the final level, played with an invisible stack. Both are nil
+	// outside of master mode.
+	grade tetris.Grader // attached to the scoring
+	roll  *tetris.Delay // running once the credit roll has started
+
+	// popups announce recent actions beside the matrix, oldest first.
+	popups []popup
+	// showHistory shows the history panel, listing the recent events in history, oldest first.
+	showHistory bool
+	history     []event
+	// stream shows the stream layout in place of the normal one, leaving webcam blank for an overlay.
+	stream bool
+	webcam Region
+	// coaching shows the coach panel, where coach reviews each placement as it locks. review is that of the last
+	// placement, of a tetrimino with the value reviewed (nil until one is reviewed, or if reviewErr is set).
+	coaching  bool
+	coach     *bot.Bot
+	review    *bot.Review
+	reviewed  byte
+	reviewErr error
+	// screenReader describes the game in text in place of drawing it, listing the recent announcements in narration,
+	// oldest first.
+	screenReader bool
+	narration    []string
+	// cheatSheet shows every key binding in place of the game, which is paused until it is closed.
+	cheatSheet bool
+	// idle pauses the game once it has gone without input for idleTimeout since lastInput, in the time played, until
+	// someone is back (never if idleTimeout is 0).
+	idle        bool
+	idleTimeout time.Duration
+	lastInput   time.Duration
+
+	// pendingAttack is the number of lines sent since the last AttackMsg.
+	pendingAttack uint
+	// pendingHoles are the hole columns of received garbage lines, from top to bottom, added when the next tetrimino locks.
+	// They are chosen as the garbage is received so that they can be previewed.
+	pendingHoles []int
+	garbage      *tetris.GarbageGenerator
+
+	// Cheese is dug out in cheese races, which are nil otherwise.
+	cheese       *tetris.GarbageGenerator
+	cheeseHeight int  // rows of cheese kept on the board
+	cheeseLeft   uint // rows of cheese still to be added
+
+	zone *zone // nil outside zone games
+	// difficulty adjusts gravity and sends garbage in adaptive games (nil otherwise).
+	difficulty *tetris.Difficulty
+
+	// Garbage rises on a timer in dig races, which is nil otherwise.
+	rise *tetris.Delay
+	dug  uint // lines of garbage cleared
+
+	fade *tetris.Fade // when each cell of the stack fades from view in invisible games (nil otherwise)
+
+	puzzle *puzzle.Puzzle // the puzzle being solved (nil otherwise)
+
+	inputChecker *tetris.InputChecker // nil unless in strict mode
+	startTime    time.Time
+
+	shift *tetris.Shift // applies the handling to presses of the left and right keys
+
+	// Soft drop lasts while the key is held, ending once the terminal stops repeating it for the repeat window, unless
+	// toggleSoftDrop is set.
+	toggleSoftDrop bool
+	repeatWindow   time.Duration
+
+	// replay records each placement, with the moves made since the last (pieceInputs), to be written to replayPath
+	// when the game ends. It is nil unless a replay or recording was asked for.
+	replay      *replay.Replay
+	replayPath  string
+	replayErr   error
+	events      *replay.EventLog
+	pieceInputs []tetris.Move
+	lockedScore uint // the score once the last tetrimino locked, so the points scored dropping the next are known
+
+	// lastRotation is where the current tetrimino was last rotated to, for T-spins. It is nil once it has been moved
+	// sideways since.
+	lastRotation *rotation
+
+	// recordingPath is where the replay is exported as a recording, drawn with theme, when the game ends. recording
+	// is nil until it has been.
+	recordingPath string
+	recording     *recordingMsg
+	theme         *theme.Theme
+
+	startLevel uint
+	savePath   string // where the game is saved when suspended (empty if it can't be)
+	suspendErr error  // the error saving the game when it was last suspended, if any
+
+	autosaver   *autosaver    // writes the snapshots of the game while it is played (nil if it isn't snapshotted)
+	autosavedAt time.Duration // the time played when the last snapshot was taken
+	autosaveErr error         // the error taking, writing or removing the last snapshot, if any
+
+	// In sandbox games gravity can be paused, and the stack edited at the cursor, given in matrix coordinates.
+	sandbox bool
+	paused  bool // whether the player has paused gravity
+	editing bool
+	cursor  tetris.Coordinate
+
+	// undo keeps the state of the game before each recent placement, so that they can be undone in sandbox games and
+	// combo practice, or rewound in zen games (nil otherwise). spawned is the state as the current tetrimino spawned,
+	// kept once it is placed.
+	undo    *tetris.Undo
+	spawned *tetris.Snapshot
+
+	comboSetup *tetris.Matrix // the board restored when a combo breaks (nil unless in combo practice)
+	maxCombo   int
+
+	// T-spin drills are dealt by drills, and counted as they are played and executed, until drillCount have been.
+	drills         *drill.Generator // nil unless playing T-spin drills
+	drill          *drill.Drill     // the drill being played
+	drillCount     uint
+	drillsPlayed   uint
+	drillsExecuted uint
+
+	// Perfect-clear openers are dealt bags seeded by pcRandom, and counted as they are attempted and cleared, until
+	// pcCount have been. pcPieces is the number of tetriminos placed in the current attempt.
+	pcRandom   *rand.Rand // nil unless practising perfect-clear openers
+	pcCount    uint
+	pcAttempts uint
+	pcCleared  uint
+	pcPieces   uint
+	pcSession  *PCSession
+
+	countdown uint // seconds left before the game starts (0 once it has started)
+	showGo    bool // whether "GO" is shown, briefly after the countdown finishes
+
+	showInterludes   bool
+	interlude        *interlude // the interlude being shown (nil while playing)
+	pendingInterlude *interlude // an interlude which will be shown at the end of the current update
+	interludeCount   int        // number of interludes shown, used to ignore the end of one that was skipped
+
+	records   *records.Store
+	recordKey string // the key results are recorded under (empty if they are not recorded)
+	race      bool   // whether the game is to clear a number of lines, so records are for the fastest time
+	record    *recordMsg
+
+	leaderboard *leaderboard.Client // nil unless results are submitted to a leaderboard
+	player      string
+	submission  *submissionMsg
+
+	// checkpoints are the progress of the game each time another records.CheckpointLines lines were cleared, and
+	// pace are those of the personal best, which they are compared with (nil until they have been read).
+	checkpoints []records.Checkpoint
+	pace        []records.Checkpoint
+	bestSplits  []time.Duration // the fastest time for each section in any game of the mode before this one
+	lastSplits  []time.Duration // the splits of the last game of the mode to pass a checkpoint before this one
+
+	audio *audio.Player
+
+	mode      string // name of the mode, shown in the share text
+	seed      int64
+	in        Input // the input the game was created with, to play it again with the same seed
+	clipboard *termenv.Output
+	shared    bool // whether the share text has been copied
+
+	// The result of copying the board as a fumen, shown until the current tetrimino locks.
+	fumenCopied bool
+	fumenErr    error
+
+	// shown is the theme the game is drawn with. levelTheme is the theme if its colours shift every few levels (nil
+	// otherwise), and palette its colours after the last of paletteShift shifts. They fade in from fadeFrom (nil once
+	// faded) from fadeAt in the time played, with fadeStep of the steps of the fade drawn so far.
+	shown        *theme.Theme
+	levelTheme   *theme.Theme
+	palette      *theme.Theme
+	paletteShift int
+	fadeFrom     *theme.Theme
+	fadeAt       time.Duration
+	fadeStep     int
+	// compactStyles draw cells at the normal size, used in low-vision mode when the terminal is too small for the
+	// larger cells (nil otherwise).
+	compactStyles *Styles
+	board         *boardCache // the board as last drawn, shared by copies of the model
+
+	// Size of the terminal, or 0 if it is not known yet.
+	width, height int
+
+	showHoldPreview bool
+}
+
+// queueLength is the number of upcoming tetriminos shown, except in classic games.
+const queueLength = 6
+
+// undoLength is the number of placements which can be undone in practice, or rewound in zen games.
+const undoLength = 30
+
+// rewindLength is the length of play taken back by rewinding in zen games.
+const rewindLength = 10 * time.Second
+
+// bigScale is the number of cells each mino spans across and down in big games.
+const bigScale = 2
+
+// comboTetriminos are the tetriminos dealt in combo practice. The O is left out since it only fits some residues.
+var comboTetriminos = []byte{'I', 'J', 'L', 'S', 'T', 'Z'}
+
+// interludeDuration is how long an interlude is shown for, unless it is skipped.
+const interludeDuration = 2 * time.Second
+
+// interlude describes a level increase, or the start of the credit roll, shown while the game is paused.
+type interlude struct {
+	level    uint
+	from, to time.Duration // time for a tetrimino to fall one row before and after the level increase
+	roll     bool          // whether the credit roll is starting, rather than the level increasing
+}
+
+// masterFinalLevel is the last level of master mode. Clearing it starts the credit roll, which lasts for rollDuration.
+const (
+	masterFinalLevel = 15
+	rollDuration     = 60 * time.Second
+)
+
+type interludeEndMsg struct {
+	id    int
+	count int
+}
+
+// goDuration is how long "GO" is shown for once the countdown finishes.
+const goDuration = 500 * time.Millisecond
+
+type countdownTickMsg struct {
+	id int
+}
+
+func countdownTick(id int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(_ time.Time) tea.Msg {
+		return countdownTickMsg{id: id}
+	})
+}
+
+// recordMsg is sent once the results of a finished game have been compared with the player's records.
+type recordMsg struct {
+	id       int
+	isBest   bool
+	previous *records.Record // the best record before this game (nil if there was none)
+	err      error
 }
 
-func InitialModel(level uint) *Model {
+// recordingMsg is sent once the replay of a finished game has been exported as a recording.
+type recordingMsg struct {
+	id  int
+	err error
+}
+
+// frameMsg is sent every frame while the game is running, to apply gravity and update the timer.
+type frameMsg struct {
+	id int
+}
+
+func frame(id int) tea.Cmd {
+	return tea.Tick(tetris.FrameDuration, func(_ time.Time) tea.Msg {
+		return frameMsg{id: id}
+	})
+}
+
+// botInterval is the time between each action performed by the bot.
+const botInterval = 100 * time.Millisecond
+
+type botTickMsg struct {
+	id int
+}
+
+func botTick(id int) tea.Cmd {
+	return tea.Tick(botInterval, func(_ time.Time) tea.Msg {
+		return botTickMsg{id: id}
+	})
+}
+
+func NewModel(in *Input) *Model {
+	if in.Daily != "" {
+		in = dailyInput(in)
+	}
+	clock := in.Clock
+	if clock == nil {
+		clock = tetris.RealClock{}
+	}
+	timer := tetris.NewStopwatch(clock)
+	speed := in.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	width, height := in.Width, in.Height
+	if width <= 0 {
+		width = tetris.DefaultWidth
+	}
+	if in.Big {
+		width = tetris.DefaultWidth * bigScale
+	}
+	if height <= 0 {
+		height = tetris.DefaultHeight
+	}
+
 	m := &Model{
-		matrix:  tetris.Matrix{},
-		styles:  DefaultStyles(),
-		help:    help.New(),
-		keys:    DefaultKeyMap(),
-		scoring: tetris.NewScoring(level),
-		holdTet: &tetris.Tetrimino{
-			Cells: [][]bool{
-				{false, false, false},
-				{false, false, false},
-				{false, false, false},
-			},
-			Value: 0,
-		},
-		canHold: true,
-		timer:   stopwatch.NewWithInterval(time.Millisecond),
+		id:        nextID(),
+		matrix:    tetris.NewMatrix(width, height),
+		board:     &boardCache{},
+		help:      theme.NewHelp(in.Theme),
+		keys:      newKeyMap(in.Keys),
+		scoring:   tetris.NewScoring(in.Level),
+		lineGoal:  in.LineGoal,
+		timeLimit: in.TimeLimit,
+		rules:     in.Rules,
+		attack:    newAttack(in),
+		stats:     tetris.NewStatistics(),
+		isVersus:  in.Versus,
+
+		showHoldPreview: in.HoldPreview,
+		startTime:       clock.Now(),
+		countdown:       in.Countdown,
+		showInterludes:  in.Interludes && !in.Versus && in.LineGoal == 0 && in.TimeLimit == 0 && in.Cheese == 0 && in.Dig == 0,
+		held:            tetris.NewHoldQueue(in.HoldSlots),
+		hold:            in.Hold,
+		canHold:         true,
+		clock:           clock,
+		timer:           timer,
+		gravity:         tetris.NewGravity(timer, in.Level),
+		speed:           speed,
+		classic:         in.Classic,
+		records:         in.Records,
+		audio:           in.Audio,
+		race:            in.LineGoal > 0 || in.Cheese > 0 || in.Puzzle != nil,
+		mode:            modeName(in),
+
+		entryDelay:    tetris.NewDelay(timer, 0),
+		entryTime:     scaled(in.EntryDelay, speed),
+		lineClearTime: scaled(in.LineClearDelay, speed),
+		showHistory:   in.History,
+		stream:        in.Stream,
+		webcam:        in.Webcam,
+		idleTimeout:   in.IdleTimeout,
+	}
+	if in.Clipboard != nil {
+		m.clipboard = termenv.NewOutput(in.Clipboard)
+	} else {
+		m.clipboard = termenv.DefaultOutput()
+	}
+	if in.Theme != nil {
+		m.dangerRow = in.Theme.DangerRow
+		m.screenReader = in.Theme.ScreenReader
+	}
+	m.setTheme(in.Theme)
+	if in.Theme != nil && in.Theme.LevelColours {
+		m.levelTheme = in.Theme
+		m.setPalette(in.Level)
+	}
+	m.gravity.SetSpeed(speed)
+	m.gravity.SetSoftDropFactor(in.Handling.SoftDrop)
+	m.gravity.SetSonicDrop(in.Handling.SonicDrop)
+	m.startLevel = in.Level
+	if suspendable(in) {
+		m.savePath = in.SavePath
+		m.keys.Suspend.SetEnabled(true)
+	}
+	if in.AutosavePath != "" && resumable(in) {
+		m.autosaver = &autosaver{path: in.AutosavePath}
+	}
+	m.shift = tetris.NewShift(in.Handling.Scaled(speed))
+	m.toggleSoftDrop, m.repeatWindow = in.Handling.ToggleSoftDrop, in.Handling.Window
+	if !m.toggleSoftDrop {
+		m.keys.SoftDrop.SetHelp(m.keys.SoftDrop.Help().Key, "soft drop")
+	}
+	m.queueLen = queueLength
+	if in.Curve != "" {
+		curve, err := tetris.CurveByName(in.Curve)
+		if err != nil {
+			m.fail(err)
+		} else {
+			m.gravity.SetCurve(curve)
+		}
+	}
+	if in.Gravity != nil {
+		m.gravity.SetCurve(in.Gravity.Curve)
+	}
+	m.lockDelay = tetris.NewLockDelay(timer, in.Handling.LockDown, scaled(tetris.DefaultLockDelay, speed))
+	if in.Classic {
+		m.scoring = tetris.NewClassicScoring(in.Level)
+		m.lockDelay = nil
+		m.gravity.SetCurve(tetris.NESCurve)
+		m.queueLen = 1
+		m.hold = tetris.HoldOff
+		m.keys.HardDrop.SetEnabled(false)
+	}
+	if in.Master {
+		m.master = true
+		m.gravity.SetCurve(tetris.MasterCurve)
+		m.lockDelay = tetris.NewLockDelay(timer, tetris.LockDownClassic, scaled(tetris.MasterLockDelay(in.Level), speed))
+		m.entryTime = scaled(tetris.MasterEntryDelay(in.Level), speed)
+		m.lineClearTime = scaled(tetris.MasterLineClearDelay(in.Level), speed)
+		grader := in.Grading
+		if grader == "" {
+			grader = "lines"
+		}
+		var err error
+		m.grade, err = tetris.NewGrader(grader, timer, masterFinalLevel)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to create grader: %w", err))
+			m.grade = tetris.NewGrade()
+		}
+		m.scoring.SetGrader(m.grade)
+		m.roll = tetris.NewDelay(timer, rollDuration)
+		m.keys.SoftDrop.SetHelp(m.keys.SoftDrop.Help().Key, "lock")
+	}
+	if in.Sandbox {
+		m.sandbox = true
+		m.showInterludes = false
+		m.cursor = tetris.Coordinate{X: width / 2, Y: height*2 - 1}
+		m.keys.Pause.SetEnabled(true)
+		m.keys.Edit.SetEnabled(true)
+		m.keys.Pick.SetEnabled(true)
+	}
+	if in.Dig > 0 {
+		m.rise = tetris.NewDelay(timer, scaled(in.Dig, speed))
+		m.rise.Start()
+	}
+	if in.Records != nil {
+		if mode := recordMode(in, speed); mode != "" {
+			m.recordKey = records.Key(in.Player, mode)
+		}
+	}
+	if m.recordKey != "" {
+		m.leaderboard = in.Leaderboard
+		m.player = in.Player
+	}
+	start := in.Matrix
+	if in.ComboPractice && start == nil {
+		var err error
+		start, err = preset.Load("four-wide")
+		if err != nil {
+			m.fail(fmt.Errorf("failed to load combo setup: %w", err))
+		}
+	}
+	if in.Puzzle != nil {
+		start = &in.Puzzle.Matrix
+		m.puzzle = in.Puzzle
+		m.hold = tetris.HoldOff
+		m.showInterludes = false
+	}
+	if start != nil {
+		m.matrix = start.Clone()
+	}
+	if in.ComboPractice {
+		setup := m.matrix.Clone()
+		m.comboSetup = &setup
+	}
+	if in.Bot {
+		m.bot = bot.New(bot.DefaultWeights)
+	}
+	if in.Bot || in.Big {
+		// There is nothing to coach when the bot is playing, and it can't review the placements of big tetriminos.
+		m.keys.Coach.SetEnabled(false)
+	} else {
+		m.coach = bot.New(bot.DefaultWeights)
+	}
+	if in.Strict {
+		m.inputChecker = tetris.NewInputChecker()
+	}
+	if in.HardDropOnly {
+		m.keys.SoftDrop.SetEnabled(false)
+	}
+	m.sources = in.Sources
+	seed := in.Seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	m.seed = seed
+	m.in = *in
+	m.in.Seed = seed
+	var randomizer string // the name of the randomizer dealing tetriminos (empty if they are dealt by a fixed bag)
+	if in.ComboPractice {
+		var err error
+		m.bag, err = tetris.NewSeededBagOf(m.matrix, seed, comboTetriminos)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to create bag: %w", err))
+			m.bag = tetris.NewSeededBag(m.matrix, seed)
+		}
+	} else if in.Puzzle != nil {
+		var err error
+		m.bag, err = tetris.NewFixedBag(m.matrix, in.Puzzle.Queue)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to create bag: %w", err))
+			m.bag = tetris.NewSeededBag(m.matrix, seed)
+		}
+	} else if in.TSpinDrills > 0 {
+		m.drills = drill.NewGenerator(len(m.matrix[0]), m.matrix.VisibleRows(), seed)
+		m.drillCount = in.TSpinDrills
+		m.hold = tetris.HoldOff
+		m.showInterludes = false
+		err := m.nextDrill()
+		if err != nil {
+			m.fail(fmt.Errorf("failed to start drill: %w", err))
+			m.bag = tetris.NewSeededBag(m.matrix, seed)
+		}
+	} else if in.PCOpeners > 0 {
+		m.pcRandom = rand.New(rand.NewSource(seed))
+		m.pcCount = in.PCOpeners
+		m.pcSession = in.PCSession
+		if m.pcSession == nil {
+			m.pcSession = &PCSession{}
+		}
+		// Retries carry on the same session.
+		m.in.PCSession = m.pcSession
+		m.showInterludes = false
+		m.nextPC()
+	} else {
+		name := in.Randomizer
+		if name == "" {
+			name = defaultRandomizer(in)
+		}
+		r, err := tetris.NewRandomizer(name, seed)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to create randomizer: %w", err))
+			r = tetris.NewBagRandomizer(seed)
+		}
+		randomizer = name
+		m.bag = tetris.NewRandomizedBag(m.matrix, r)
+	}
+	if in.Big {
+		m.bag.Scale(m.matrix, bigScale)
+	}
+	m.garbage = tetris.NewPatternedGarbageGenerator(len(m.matrix[0]), tetris.GarbagePattern{
+		Holes:     in.GarbageHoles,
+		Messiness: in.GarbageMessiness,
+		HoleWidth: in.GarbageHoleWidth,
+	}, seed)
+	if in.StartingGarbage > 0 {
+		m.addGarbage(m.garbage, m.garbage.Holes(int(in.StartingGarbage), len(m.matrix[0])))
+	}
+	if in.Cheese > 0 {
+		m.cheese = tetris.NewPatternedGarbageGenerator(len(m.matrix[0]), tetris.GarbagePattern{
+			Holes:     in.CheeseHoles,
+			Messiness: 1,
+			HoleWidth: in.CheeseHoleWidth,
+		}, seed)
+		m.cheeseHeight = int(in.Cheese)
+		m.cheeseLeft = max(in.Cheese, in.CheeseTotal)
+		m.addCheese()
+	}
+	if in.Invisible {
+		m.fade = tetris.NewFade(timer, m.matrix, in.FadeDelay)
+		m.showHoldPreview = false
+		m.keys.Fumen.SetEnabled(false)
+	}
+	if m.hold == tetris.HoldOff {
+		m.showHoldPreview = false
+		m.keys.Hold.SetEnabled(false)
+	}
+	if in.Versus {
+		// The opponent carries on playing, so the game can't be paused to show the cheat sheet.
+		m.keys.CheatSheet.SetEnabled(false)
+	}
+	// Results submitted to a leaderboard are sent with the hash of the game's replay, so one is kept even if it isn't
+	// written.
+	if in.ReplayPath != "" || in.RecordingPath != "" || m.leaderboard != nil {
+		m.replay = replay.New(m.mode, seed, in.Level, m.matrix)
+		m.replay.Randomizer, m.replay.Hold, m.replay.Classic = randomizer, m.hold.String(), m.classic
+		m.replayPath = in.ReplayPath
+		m.recordingPath = in.RecordingPath
+		m.theme = in.Theme
+	}
+	m.events = in.Events
+	if in.Sandbox || in.ComboPractice {
+		m.undo = tetris.NewUndo(undoLength)
+		m.keys.Undo.SetEnabled(true)
+	}
+	if in.Zen {
+		m.undo = tetris.NewUndo(undoLength)
+		m.keys.Rewind.SetEnabled(true)
+	}
+	if in.Zone {
+		m.zone = newZone(clock)
+		m.keys.Zone.SetEnabled(true)
+	}
+	if in.Adaptive {
+		m.difficulty = tetris.NewDifficulty(adaptiveStart)
+		m.gravity.SetSpeed(speed * m.difficulty.Speed())
 	}
-	m.bag = tetris.NewBag(len(m.matrix))
-	m.fall = defaultFall(level)
 	m.currentTet = m.bag.Next()
 	err := m.matrix.AddTetrimino(m.currentTet)
 	if err != nil {
-		panic(fmt.Errorf("failed to add tetrimino to matrix: %w", err))
+		// The starting board blocks the first tetrimino from spawning (block out).
+		m.gameOver = true
+	}
+	m.narrate("%c piece spawned", m.currentTet.Value)
+	m.events.Spawn(m.timer.Elapsed(), m.currentTet)
+	m.resetLockDelay()
+	m.takeSnapshot()
+	return m
+}
+
+// defaultRandomizer returns the name of the randomizer the mode deals tetriminos with unless another is chosen: the
+// NES version's for classic, Tetris The Grand Master 2's for master, and bags of seven as in the guideline otherwise.
+func defaultRandomizer(in *Input) string {
+	switch {
+	case in.Classic:
+		return "nes"
+	case in.Master:
+		return "tgm"
+	}
+	return "bag"
+}
+
+// suspendable reports whether the game can be suspended, which it can if it can be resumed and there is somewhere to
+// save it.
+func suspendable(in *Input) bool {
+	return in.SavePath != "" && resumable(in)
+}
+
+// resumable reports whether the game can be resumed from a save. Only marathon games can be, since the other modes are
+// races, or depend on more than the stack, the queue and the score.
+func resumable(in *Input) bool {
+	switch {
+	case in.Bot, in.Versus, in.Strict, in.Classic, in.Master, in.Invisible, in.ComboPractice, in.Sandbox,
+		in.Zen, in.Zone, in.Big, in.Adaptive, in.Rules != nil, in.TSpinDrills > 0, in.PCOpeners > 0:
+		return false
+	case in.Matrix != nil, in.Puzzle != nil, in.EntryDelay > 0, in.LineClearDelay > 0, in.Hold != tetris.HoldOnce,
+		in.HoldSlots > 1:
+		return false
+	}
+	return in.LineGoal == 0 && in.TimeLimit == 0 && in.Cheese == 0 && in.Dig == 0
+}
+
+// Resume continues a game suspended to the save where it left off. The game is played with the settings it was
+// started with, and the rest of the input, such as the theme, as given.
+func Resume(in *Input, g *save.Game) (*Model, error) {
+	resumed := *in
+	resumed.Level = g.Level
+	resumed.HoldPreview = g.HoldPreview
+	resumed.Interludes = g.Interludes
+	resumed.Speed = g.Speed
+	resumed.Seed = g.Seed
+	if len(g.Matrix) == 0 || len(g.Matrix)%2 != 0 || len(g.Matrix[0]) < 4 || len(g.Matrix[0]) > tetris.MaxWidth {
+		return nil, errors.New("saved matrix has an invalid size")
+	}
+	resumed.Width, resumed.Height = len(g.Matrix[0]), g.Matrix.VisibleRows()
+	m := NewModel(&resumed)
+
+	bag, err := tetris.RestoreBag(g.Matrix, g.Bag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore bag: %w", err)
+	}
+	stats, err := tetris.RestoreStatistics(g.Statistics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore statistics: %w", err)
+	}
+
+	m.matrix = g.Matrix
+	current := g.Current
+	m.currentTet = &current
+	// Saves from before the whole hold queue was kept only have the tetrimino held longest.
+	switch {
+	case g.Held != "":
+		for i := range g.Held {
+			held, err := bag.Spawned(g.Held[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore hold: %w", err)
+			}
+			m.held.Held = append(m.held.Held, *held)
+		}
+	case g.Hold.Value != 0:
+		m.held.Held = []tetris.Tetrimino{g.Hold}
+	}
+	err = m.matrix.AddTetrimino(m.currentTet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add tetrimino to matrix: %w", err)
+	}
+	m.canHold = g.CanHold
+	m.bag = bag
+	m.scoring = tetris.RestoreScoring(g.Scoring)
+	m.attack = tetris.RestoreAttack(g.Attack)
+	m.stats = stats
+	m.maxCombo = g.MaxCombo
+	m.checkpoints = g.Checkpoints
+	m.lockedScore = m.scoring.Total()
+	// The replay only starts from where the game was suspended, so the results can't be checked against it.
+	m.leaderboard = nil
+
+	m.timer.Restore(g.Time)
+	m.lastInput = g.Time
+	m.gravity.SetLevel(m.scoring.Level())
+	m.gravity.Reset()
+	m.setPalette(m.scoring.Level())
+	return m, nil
+}
+
+// suspend saves the game to be continued later and stops it. The results are not recorded, since the game isn't over.
+func (m *Model) suspend() tea.Cmd {
+	g, err := m.save()
+	if err == nil {
+		err = save.Write(m.savePath, g)
+	}
+	if err != nil {
+		m.suspendErr = err
+		return nil
+	}
+
+	m.timer.Stop()
+	m.removeAutosave()
+	id := m.id
+	return func() tea.Msg { return SuspendedMsg{ID: id} }
+}
+
+// save returns the state of the game, from which it can be resumed.
+func (m *Model) save() (*save.Game, error) {
+	// The current tetrimino is saved separately, since it is not part of the stack.
+	matrix := m.matrix.Clone()
+	err := matrix.RemoveTetrimino(m.currentTet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove tetrimino from matrix: %w", err)
+	}
+	bag, err := m.bag.State()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save bag: %w", err)
+	}
+
+	return &save.Game{
+		Level:       m.startLevel,
+		HoldPreview: m.showHoldPreview,
+		Interludes:  m.showInterludes,
+		Speed:       m.speed,
+		Seed:        m.seed,
+		Matrix:      matrix,
+		Current:     *m.currentTet,
+		Hold:        m.heldFront(),
+		Held:        m.held.Values(),
+		CanHold:     m.canHold,
+		Bag:         bag,
+		Scoring:     m.scoring.State(),
+		Attack:      m.attack.State(),
+		Statistics:  m.stats.State(),
+		MaxCombo:    m.maxCombo,
+		Time:        m.timer.Elapsed(),
+		Checkpoints: m.checkpoints,
+		Date:        m.clock.Now(),
+	}, nil
+}
+
+// scaled returns how long a delay lasts in a game played at the given speed.
+func scaled(d time.Duration, speed float64) time.Duration {
+	return time.Duration(float64(d) / speed)
+}
+
+// recordMode returns the name of the mode records for the game are kept under, or an empty string if its results are
+// not comparable with other games and should not be recorded.
+func recordMode(in *Input, speed float64) string {
+	switch {
+	case in.Bot, in.Versus, in.ComboPractice, in.TSpinDrills > 0, in.PCOpeners > 0, in.Sandbox, in.Zen, in.Matrix != nil,
+		in.StartingGarbage > 0, speed != 1, in.Hold == tetris.HoldUnlimited, in.HoldSlots > 1, in.HardDropOnly:
+		return ""
+	case !in.Master && (in.EntryDelay > 0 || in.LineClearDelay > 0):
+		return ""
+	case !in.Classic && !in.Master && in.Handling.LockDown != tetris.LockDownExtended:
+		// Classic mode has no lock delay and master mode its own, but elsewhere the rule changes how long a
+		// tetrimino can be kept from locking.
+		return ""
+	case in.GarbageHoles != tetris.HolesMessy, in.GarbageHoleWidth > 1, in.CheeseHoles != tetris.HolesMessy,
+		in.CheeseHoleWidth > 1:
+		return ""
+	case in.Puzzle != nil:
+		return fmt.Sprintf("puzzle-%s", in.Puzzle.Name)
+	case in.Daily != "":
+		return fmt.Sprintf("daily-%s", in.Daily)
+	case !in.Big && in.Width > 0 && in.Width != tetris.DefaultWidth, in.Height > 0 && in.Height != tetris.DefaultHeight:
+		return ""
+	case in.Randomizer != "" && in.Randomizer != defaultRandomizer(in):
+		return ""
+	case in.Rules != nil:
+		return rulesRecordMode(in.Rules, in.Level)
+	case in.LineGoal > 0:
+		return fmt.Sprintf("lines-%d", in.LineGoal)
+	case in.Cheese > 0:
+		return fmt.Sprintf("cheese-%d", max(in.Cheese, in.CheeseTotal))
+	case in.Dig > 0:
+		return fmt.Sprintf("dig-%s-level-%d", in.Dig, in.Level)
+	case in.Invisible:
+		return fmt.Sprintf("invisible-%s-level-%d", in.FadeDelay, in.Level)
+	case in.TimeLimit > 0:
+		return fmt.Sprintf("time-%s", in.TimeLimit)
+	case in.Classic:
+		return fmt.Sprintf("classic-level-%d", in.Level)
+	case in.Master && in.Grading != "" && in.Grading != "lines":
+		// Grades from different graders aren't comparable, so each has its own records. Games graded by lines keep
+		// the key they had before graders could be chosen.
+		return fmt.Sprintf("master-%s-level-%d", in.Grading, in.Level)
+	case in.Master:
+		return fmt.Sprintf("master-level-%d", in.Level)
+	case in.Zone:
+		return fmt.Sprintf("zone-level-%d", in.Level)
+	case in.Big:
+		return fmt.Sprintf("big-level-%d", in.Level)
+	case in.Adaptive:
+		return fmt.Sprintf("adaptive-level-%d", in.Level)
+	case in.Curve != "" && in.Curve != "guideline":
+		return fmt.Sprintf("marathon-%s-level-%d", in.Curve, in.Level)
+	}
+	return fmt.Sprintf("marathon-level-%d", in.Level)
+}
+
+// modeName returns the name of the mode the game is played in, such as "Sprint (40 lines) from level 1".
+func modeName(in *Input) string {
+	if in.Puzzle != nil {
+		return fmt.Sprintf("Puzzle: %s", in.Puzzle.Title)
+	}
+	if in.Daily != "" {
+		return fmt.Sprintf("Daily challenge: %s", in.Daily)
+	}
+	return fmt.Sprintf("%s from level %d", modeTitle(in), in.Level)
+}
+
+// modeTitle returns the name of the mode the game is played in, whatever the level, such as "Sprint (40 lines)".
+func modeTitle(in *Input) string {
+	var name string
+	switch {
+	case in.Puzzle != nil:
+		name = "Puzzle"
+	case in.Daily != "":
+		name = "Daily challenge"
+	case in.Bot:
+		name = "Demo"
+	case in.Versus:
+		name = "Versus"
+	case in.ComboPractice:
+		name = "Combo practice"
+	case in.TSpinDrills > 0:
+		name = "T-spin drill"
+	case in.PCOpeners > 0:
+		name = "PC opener"
+	case in.Sandbox:
+		name = "Sandbox"
+	case in.Zen:
+		name = "Zen"
+	case in.Zone:
+		name = "Zone"
+	case in.Big:
+		name = "Big"
+	case in.Adaptive:
+		name = "Adaptive"
+	case in.Rules != nil:
+		name = in.Rules.Name
+	case in.Cheese > 0:
+		name = fmt.Sprintf("Cheese race (%d rows)", max(in.Cheese, in.CheeseTotal))
+	case in.Dig > 0:
+		name = fmt.Sprintf("Dig race (every %s)", in.Dig)
+	case in.Invisible:
+		name = "Invisible"
+	case in.LineGoal > 0:
+		name = fmt.Sprintf("Sprint (%d lines)", in.LineGoal)
+	case in.TimeLimit > 0:
+		name = fmt.Sprintf("Ultra (%s)", in.TimeLimit)
+	case in.Classic:
+		name = "Classic"
+	case in.Master:
+		name = "Master"
+	default:
+		name = "Marathon"
+	}
+	return name
+}
+
+// ID returns the unique ID of the game, used to identify the messages it sends and receives.
+func (m Model) ID() int {
+	return m.id
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.gameOver {
+		// The game ended before it started, such as from a board the first tetrimino can't spawn on.
+		return m.endGame()
+	}
+	if m.countdown > 0 {
+		return tea.Batch(countdownTick(m.id, time.Second), m.listen(), m.loadPace())
+	}
+	return tea.Batch(m.start(), m.listen(), m.loadPace())
+}
+
+// listen waits for the next move from each of the game's sources.
+func (m Model) listen() tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.sources))
+	for i, source := range m.sources {
+		cmds[i] = m.waitForSource(source)
+	}
+	return tea.Batch(cmds...)
+}
+
+// waitForSource waits for the next move from the source.
+func (m Model) waitForSource(source input.Source) tea.Cmd {
+	id := m.id
+	return func() tea.Msg {
+		move, ok := source.Next()
+		return MoveMsg{ID: id, Move: move, source: source, ended: !ok}
+	}
+}
+
+// start begins gravity and the game timer.
+func (m Model) start() tea.Cmd {
+	m.timer.Start()
+	cmds := []tea.Cmd{frame(m.id)}
+	if m.bot != nil {
+		cmds = append(cmds, botTick(m.id))
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateCountdown handles messages before the game has started. Gameplay keys are ignored until then.
+func (m Model) updateCountdown(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, m.quit()
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		}
+	case GarbageMsg:
+		if msg.ID == m.id {
+			m.receiveGarbage(msg.Lines)
+		}
+	case countdownTickMsg:
+		if msg.id != m.id {
+			break
+		}
+		m.countdown--
+		if m.countdown > 0 {
+			return m, countdownTick(m.id, time.Second)
+		}
+		m.showGo = true
+		m.startTime = m.clock.Now()
+		return m, tea.Batch(m.start(), countdownTick(m.id, goDuration))
+	}
+	return m, nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width, m.height = msg.Width, msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+	}
+
+	if msg, ok := msg.(paceMsg); ok {
+		if msg.id == m.id {
+			m.pace, m.bestSplits, m.lastSplits = msg.best, msg.splits, msg.last
+		}
+		return m, nil
+	}
+
+	if msg, ok := msg.(MoveMsg); ok && msg.ID == m.id && (m.gameOver || m.countdown > 0 || m.interlude != nil || m.editing) {
+		// Moves can't be made now, but later ones are still waited for until the game ends.
+		if msg.source == nil || msg.ended || m.gameOver {
+			return m, nil
+		}
+		return m, m.waitForSource(msg.source)
+	}
+
+	if m.gameOver {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.Quit):
+				return m, m.quit()
+			case key.Matches(msg, m.keys.Help):
+				m.help.ShowAll = !m.help.ShowAll
+			case key.Matches(msg, m.keys.Share):
+				m.shared = true
+				return m, m.copyShareText()
+			case key.Matches(msg, m.keys.Retry):
+				return m.retry()
+			}
+		case recordMsg:
+			if msg.id == m.id {
+				m.record = &msg
+			}
+		case submissionMsg:
+			if msg.id == m.id {
+				m.submission = &msg
+			}
+		case recordingMsg:
+			if msg.id == m.id {
+				m.recording = &msg
+			}
+		}
+		return m, nil
+	}
+	if m.countdown > 0 {
+		return m.updateCountdown(msg)
+	}
+	if m.interlude != nil {
+		return m.updateInterlude(msg)
+	}
+	if m.cheatSheet {
+		return m.updateCheatSheet(msg)
+	}
+	if m.idle {
+		return m.updateIdle(msg)
+	}
+	if m.editing {
+		return m.updateEditing(msg)
+	}
+
+	switch msg := msg.(type) {
+	case countdownTickMsg:
+		if msg.id == m.id {
+			m.showGo = false
+		}
+	case SuspendedMsg:
+		if msg.ID == m.id {
+			return m, tea.Quit
+		}
+	case autosaveMsg:
+		if msg.id == m.id {
+			m.autosaveErr = msg.err
+		}
+	case tea.KeyMsg:
+		m.active()
+		if move, ok := m.keys.move(msg); ok {
+			if m.bot == nil {
+				// The bot is in control otherwise, so gameplay keys are ignored.
+				m.input(move)
+			}
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, m.quit()
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		case key.Matches(msg, m.keys.CheatSheet):
+			m.cheatSheet = true
+			m.timer.Stop()
+		case key.Matches(msg, m.keys.Stream):
+			m.stream = !m.stream
+		case key.Matches(msg, m.keys.Coach):
+			m.coaching = !m.coaching
+		case key.Matches(msg, m.keys.Suspend):
+			return m, m.suspend()
+		case key.Matches(msg, m.keys.Fumen):
+			return m, m.copyFumen()
+		case key.Matches(msg, m.keys.Undo):
+			err := m.undoPlacement()
+			if err != nil {
+				m.fail(fmt.Errorf("failed to undo placement: %w", err))
+			}
+		case key.Matches(msg, m.keys.Rewind):
+			err := m.rewind()
+			if err != nil {
+				m.fail(fmt.Errorf("failed to rewind: %w", err))
+			}
+		case key.Matches(msg, m.keys.Zone):
+			m.enterZone()
+		case key.Matches(msg, m.keys.Pause):
+			m.togglePause()
+		case key.Matches(msg, m.keys.Edit):
+			m.startEditing()
+		case key.Matches(msg, m.keys.Pick):
+			err := m.pickNext(msg)
+			if err != nil {
+				m.fail(fmt.Errorf("failed to pick next tetrimino: %w", err))
+				return m, m.endGame()
+			}
+		}
+		err := m.settle()
+		if err != nil {
+			m.fail(fmt.Errorf("failed to settle tetrimino: %w", err))
+		}
+	case MoveMsg:
+		if msg.ID != m.id || msg.ended {
+			break
+		}
+		m.active()
+		if m.bot == nil {
+			m.input(msg.Move)
+		}
+		if msg.source != nil {
+			cmds = append(cmds, m.waitForSource(msg.source))
+		}
+	case botTickMsg:
+		if msg.id != m.id {
+			break
+		}
+		if m.entering() {
+			cmds = append(cmds, botTick(m.id))
+			break
+		}
+		err := m.playBotAction()
+		if err != nil {
+			m.fail(fmt.Errorf("failed to play bot action: %w", err))
+			break
+		}
+		cmds = append(cmds, botTick(m.id))
+	case GarbageMsg:
+		if msg.ID != m.id {
+			break
+		}
+		m.receiveGarbage(msg.Lines)
+	case frameMsg:
+		if msg.id != m.id {
+			break
+		}
+		m.updateEntry()
+		m.expirePopups()
+		m.fadePalette()
+		var err error
+		if !m.master && !m.entering() {
+			err = m.applyGravity()
+		}
+		if err == nil {
+			err = m.updateDelays()
+		}
+		if err != nil {
+			m.fail(fmt.Errorf("failed to lower tetrimino (gravity): %w", err))
+		}
+		if m.master && m.roll.Expired() && !m.gameOver {
+			m.grade.CompleteRoll()
+			m.gameOver = true
+			m.completed = true
+		}
+		if m.rise != nil && m.rise.Expired() && !m.gameOver {
+			m.rise.Start()
+			err = m.raiseGarbage()
+			if err != nil {
+				m.fail(fmt.Errorf("failed to raise garbage: %w", err))
+			}
+		}
+		m.updateZone()
+		m.checkIdle()
+		cmds = append(cmds, m.autosave(), frame(m.id))
+	}
+
+	if m.pendingAttack > 0 {
+		attack := AttackMsg{ID: m.id, Lines: m.pendingAttack}
+		cmds = append(cmds, func() tea.Msg { return attack })
+		m.pendingAttack = 0
+	}
+
+	if m.pendingInterlude != nil && !m.gameOver {
+		m.interlude, m.pendingInterlude = m.pendingInterlude, nil
+		m.interludeCount++
+		count := m.interludeCount
+		m.timer.Stop()
+		cmds = append(cmds, tea.Tick(interludeDuration, func(_ time.Time) tea.Msg {
+			return interludeEndMsg{id: m.id, count: count}
+		}))
+	}
+
+	if m.timeLimit > 0 && m.timer.Elapsed() >= m.timeLimit {
+		m.gameOver = true
+		m.completed = true
+	}
+	m.checkRules()
+	m.updateDanger()
+	if m.gameOver {
+		cmds = append(cmds, m.endGame())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// updateInterlude handles messages while an interlude is shown. Any key other than quit or help skips it.
+func (m Model) updateInterlude(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, m.quit()
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		default:
+			m.interlude = nil
+			m.timer.Start()
+		}
+	case interludeEndMsg:
+		if msg.id != m.id || msg.count != m.interludeCount {
+			break
+		}
+		m.interlude = nil
+		m.timer.Start()
+	case frameMsg:
+		// Frames keep coming while the timer is stopped, so that the game resumes as soon as the interlude ends.
+		if msg.id == m.id {
+			return m, frame(m.id)
+		}
+	}
+	return m, nil
+}
+
+// Matrix returns a copy of the matrix, including the current tetrimino.
+func (m Model) Matrix() tetris.Matrix {
+	return m.matrix.Clone()
+}
+
+// Snapshot returns the current state of the game, as shared with opponents and spectators.
+func (m Model) Snapshot() *netplay.Board {
+	results := m.Results()
+	board := &netplay.Board{
+		Matrix:   m.matrix.Clone(),
+		Score:    results.Score,
+		Lines:    results.Lines,
+		Level:    results.Level,
+		Attack:   results.Attack,
+		Received: results.Received,
+		Time:     results.Time,
+	}
+	board.Hold = m.held.Values()
+	for _, t := range m.bag.Elements[:min(m.queueLen, len(m.bag.Elements))] {
+		board.Queue += string(t.Value)
+	}
+	return board
+}
+
+// State returns the full state of the game, in the schema shared with tools outside the game.
+func (m Model) State() (tetris.State, error) {
+	state := tetris.State{
+		Version:    tetris.StateVersion,
+		Matrix:     m.matrix.Clone(),
+		CanHold:    m.canHold,
+		Scoring:    m.scoring.State(),
+		Attack:     m.attack.State(),
+		Statistics: m.stats.State(),
+		Timers: tetris.Timers{
+			Time:       m.timer.Elapsed(),
+			Gravity:    m.gravity.Interval(),
+			EntryDelay: m.entryDelay.Remaining(),
+		},
+		GameOver: m.gameOver,
+	}
+	// Between tetriminos, and once the game is over, the current tetrimino is part of the stack or was never added.
+	if !m.entering() && !m.gameOver {
+		err := state.Matrix.RemoveTetrimino(m.currentTet)
+		if err != nil {
+			return tetris.State{}, fmt.Errorf("failed to remove tetrimino from matrix: %w", err)
+		}
+		state.Current = tetris.NewPiece(m.currentTet)
+		if m.lockDelay != nil {
+			state.Timers.LockDelay = m.lockDelay.Remaining()
+		}
+	}
+	state.Hold = m.held.Values()
+	for _, t := range m.bag.Elements[:min(m.queueLen, len(m.bag.Elements))] {
+		state.Queue += string(t.Value)
+	}
+	return state, nil
+}
+
+// Results returns the results of the game so far.
+func (m Model) Results() Results {
+	return Results{
+		Score:     m.scoring.Total(),
+		Lines:     m.scoring.Lines(),
+		Level:     m.scoring.Level(),
+		Time:      m.timer.Elapsed(),
+		Completed: m.completed,
+		Attack:    m.attack.Sent(),
+		Received:  m.attack.Received(),
+		Flagged:   m.inputChecker != nil && m.inputChecker.Rejected() > 0,
+		MaxCombo:  m.maxCombo,
+		Pieces:    m.stats.Pieces(),
+		Speed:     m.speed,
+		Dug:       m.dug,
+		Grade:     m.gradeName(),
+		Clears:    m.stats.Breakdown(),
+		Inputs:    m.stats.Inputs(),
+	}
+}
+
+// fail ends the game because the engine failed, such as finding the matrix in a state it can't continue from. The
+// error is shown with the results rather than crashing the program.
+func (m *Model) fail(err error) {
+	m.err = err
+	m.gameOver = true
+}
+
+// endGame stops the timer and reports the results of the game.
+func (m *Model) endGame() tea.Cmd {
+	m.timer.Stop()
+	m.removeAutosave()
+	m.keys.Share.SetEnabled(true)
+	// Versus games and those played from other sources are part of something bigger, which ends with the game.
+	m.keys.Retry.SetEnabled(!m.isVersus && len(m.sources) == 0)
+	// Retry takes over r from rewinding, which has nothing left to rewind once the game is over.
+	m.keys.Rewind.SetEnabled(false)
+	m.audio.Play(audio.GameOver)
+	if m.completed {
+		m.narrate("finished, score %d", m.scoring.Total())
+	} else {
+		m.narrate("game over, score %d", m.scoring.Total())
+	}
+	if m.replay != nil {
+		m.replay.Date = m.clock.Now()
+		m.replay.Score, m.replay.Lines = m.scoring.Total(), m.scoring.Lines()
+		if m.replayPath != "" {
+			m.replayErr = replay.Write(m.replayPath, m.replay)
+		}
+	}
+	results := m.Results()
+	return tea.Batch(
+		func() tea.Msg { return GameOverMsg{ID: m.id, Results: results} },
+		m.exportRecording(),
+		m.submitRecord(results),
+		m.submitScore(results),
+		m.addLifetime(results),
+	)
+}
+
+// exportRecording returns a command which exports the game's replay as a recording, if one was asked for. Encoding an
+// animated GIF can take a while, so it isn't done while the game waits.
+func (m *Model) exportRecording() tea.Cmd {
+	if m.replay == nil || m.recordingPath == "" {
+		return nil
+	}
+	id, path, r, th := m.id, m.recordingPath, m.replay, m.theme
+	return func() tea.Msg {
+		return recordingMsg{id: id, err: export.Write(path, r, th)}
+	}
+}
+
+// addLifetime adds the game to the player's lifetime stats. Games played by the bot aren't the player's, so they are
+// left out. Lifetime stats are only kept for interest, so failing to keep them doesn't interrupt the game.
+func (m *Model) addLifetime(results Results) tea.Cmd {
+	if m.records == nil || m.in.Bot {
+		return nil
+	}
+
+	store, player := m.records, m.in.Player
+	g := records.Game{
+		Mode:     modeTitle(&m.in),
+		Lines:    results.Lines,
+		Tetrises: m.stats.Tetrises(),
+		Pieces:   results.Pieces,
+		Time:     results.Time,
+	}
+	return func() tea.Msg {
+		_ = store.AddGame(player, g)
+		return nil
+	}
+}
+
+// retry starts the game again from the beginning, dealing the same tetriminos from the same seed. As with a seed
+// chosen to play, the new game isn't recorded, since what comes next is known, unless it is the daily challenge, whose
+// seed is the same for everyone anyway.
+func (m Model) retry() (tea.Model, tea.Cmd) {
+	in := m.in
+	if in.Daily == "" {
+		in.Records = nil
+	}
+	game := NewModel(&in)
+	// The new game only learns the size of the terminal from a resize, so it is given the last one.
+	game.width, game.height = m.width, m.height
+	game.help.Width = m.help.Width
+	return *game, game.Init()
+}
+
+// ShareCard returns a summary of the results of the game, to be shared with others.
+func (m Model) ShareCard() *share.Card {
+	results := m.Results()
+	return &share.Card{
+		Mode:   m.mode,
+		Score:  results.Score,
+		Lines:  results.Lines,
+		Time:   results.Time,
+		Pieces: results.Pieces,
+		PPS:    results.PPS(),
+		Seed:   m.seed,
+		Matrix: m.matrix.Clone(),
+		Race:   m.race && results.Completed,
+	}
+}
+
+// copyShareText returns a command which copies the share card to the clipboard.
+func (m *Model) copyShareText() tea.Cmd {
+	clipboard, text := m.clipboard, m.ShareCard().String()
+	return func() tea.Msg {
+		clipboard.Copy(text)
+		return nil
+	}
+}
+
+// Fumen returns the stack as a fumen, with the held, current and upcoming tetriminos as its quiz, so that the board
+// can be opened in other tools.
+func (m *Model) Fumen() (string, error) {
+	matrix := m.matrix.Clone()
+	queue := m.bag.Elements[:min(m.queueLen, len(m.bag.Elements))]
+	var quiz fumen.Quiz
+	if m.entering() {
+		// The last tetrimino has locked, so the current one is the next to spawn.
+		quiz = fumen.Quiz{Hold: m.held.Front()}
+		if len(queue) > 0 {
+			quiz.Current, queue = queue[0].Value, queue[1:]
+		}
+	} else {
+		err := matrix.RemoveTetrimino(m.currentTet)
+		if err != nil {
+			return "", fmt.Errorf("failed to remove tetrimino from matrix: %w", err)
+		}
+		quiz = fumen.Quiz{Hold: m.held.Front(), Current: m.currentTet.Value}
+	}
+	for _, t := range queue {
+		quiz.Next = append(quiz.Next, t.Value)
+	}
+	return fumen.Encode(fumen.Page{Matrix: matrix, Comment: quiz.String()})
+}
+
+// copyFumen returns a command which copies the board to the clipboard as a fumen.
+func (m *Model) copyFumen() tea.Cmd {
+	text, err := m.Fumen()
+	m.fumenCopied, m.fumenErr = err == nil, err
+	if err != nil {
+		return nil
+	}
+	clipboard := m.clipboard
+	return func() tea.Msg {
+		clipboard.Copy(text)
+		return nil
+	}
+}
+
+// submitRecord compares the results with the player's records, keeping them if they are a new personal best.
+// Games which topped out before reaching their line goal, were flagged in strict mode or ended with an error are not
+// recorded.
+func (m *Model) submitRecord(results Results) tea.Cmd {
+	if m.recordKey == "" || results.Flagged || (m.race && !results.Completed) || m.err != nil {
+		return nil
+	}
+
+	id, store, key := m.id, m.records, m.recordKey
+	score := results.Score
+	if m.rise != nil {
+		score = results.SurvivalScore()
+	}
+	record := records.Record{
+		Score: score,
+		Lines: results.Lines,
+		Time:  results.Time,
+		Date:  m.clock.Now(),
+		Race:  m.race,
+
+		Checkpoints: m.checkpoints,
+	}
+	return func() tea.Msg {
+		msg := recordMsg{id: id}
+		previous, ok, err := store.Best(key)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		if ok {
+			msg.previous = &previous
+		}
+		msg.isBest, msg.err = store.Submit(key, record)
+		return msg
+	}
+}
+
+func (m Model) View() string {
+	if m.cheatSheet {
+		return m.fit(m.cheatSheetView())
+	}
+	if m.screenReader {
+		return m.narrationView()
+	}
+	if m.idle {
+		return m.fit(m.idleView())
+	}
+	view := m.view()
+	if m.compactStyles != nil && !m.fits(view) {
+		// The larger cells of low-vision mode do not fit, so fall back to the normal size rather than hiding the game.
+		m.styles = m.compactStyles
+		view = m.view()
+	}
+	return m.fit(view)
+}
+
+func (m Model) view() string {
+	if m.stream && !m.gameOver {
+		// The stream layout is wider than the normal one, so it falls back to the normal one when it doesn't fit.
+		if view := m.streamView(); m.fits(view) {
+			return view
+		}
+	}
+	board := m.matrixView()
+	if !m.gameOver {
+		board = m.queueRowView(board)
+	}
+	left := lipgloss.JoinVertical(lipgloss.Right, m.holdView(), m.informationView())
+	if m.hold == tetris.HoldOff {
+		left = m.informationView()
+	}
+	if popups := m.popupsView(); popups != "" && !m.gameOver {
+		left = lipgloss.JoinVertical(lipgloss.Right, left, popups)
+	}
+	var output = lipgloss.JoinHorizontal(lipgloss.Top, left, board, m.bagView(), m.statisticsView())
+	if m.showHistory {
+		output = lipgloss.JoinHorizontal(lipgloss.Top, output, m.historyView())
+	}
+	if m.coaching && !m.gameOver {
+		output = lipgloss.JoinHorizontal(lipgloss.Top, output, m.coachView())
+	}
+	if m.width > 0 && lipgloss.Width(output) > m.width {
+		// Hide the side panels rather than letting the terminal wrap them, starting with the least important.
+		output = lipgloss.JoinHorizontal(lipgloss.Top, left, board, m.bagView(), m.statisticsView())
+	}
+	if m.width > 0 && lipgloss.Width(output) > m.width {
+		output = lipgloss.JoinHorizontal(lipgloss.Top, left, board, m.bagView())
+		if lipgloss.Width(output) > m.width {
+			output = board
+		}
+	}
+
+	if m.gameOver {
+		output = lipgloss.JoinHorizontal(lipgloss.Top, board, m.summaryView(), m.splitsView())
+
+		status := m.styles.text("GAME OVER")
+		if m.completed {
+			status = m.styles.text("FINISHED")
+		}
+		if m.puzzle != nil && m.completed {
+			status = m.styles.text("SOLVED")
+		}
+		if m.Results().Flagged {
+			status += " (flagged for illegal input)"
+		}
+		if m.Results().SpeedAdjusted() {
+			status += fmt.Sprintf(" (at %s speed)", m.speedLabel())
+		}
+		output += "\n" + m.styles.GameOver.Render(status)
+		if m.err != nil {
+			output += "\n" + m.styles.Hint.Render(m.styles.text("The game ended because of an error: ")+m.err.Error())
+		}
+	}
+
+	return output + "\n" + m.helpView()
+}
+
+// fits reports whether the view fits in the terminal, assuming it does if the size is not known yet.
+func (m Model) fits(view string) bool {
+	if m.width == 0 || m.height == 0 {
+		return true
+	}
+	return lipgloss.Width(view) <= m.width && lipgloss.Height(view) <= m.height
+}
+
+// fit centres the view in the terminal, or explains that the terminal is too small to show it without wrapping.
+func (m Model) fit(view string) string {
+	if m.width == 0 || m.height == 0 {
+		return view
+	}
+	if !m.fits(view) {
+		width, height := lipgloss.Width(view), lipgloss.Height(view)
+		view = m.styles.TooSmall.Render(fmt.Sprintf("%s\n%s %dx%d", m.styles.text("Terminal too small"),
+			m.styles.text("Resize to at least"), width, height))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
+}
+
+// helpView shows hints about the state of the game while playing, falling back to the key bindings when there are none.
+func (m *Model) helpView() string {
+	if m.gameOver || m.help.ShowAll {
+		return m.help.View(m.styles.locale.Keys(m.keys))
+	}
+	hints := m.hints()
+	if len(hints) == 0 {
+		return m.help.View(m.styles.locale.Keys(m.keys))
+	}
+	return m.styles.Hint.Render(strings.Join(hints, m.styles.glyphs.separator))
+}
+
+// hints returns short descriptions of the state of the game that affect what the player can do next.
+func (m *Model) hints() []string {
+	if m.countdown > 0 {
+		return nil
+	}
+
+	g := m.styles.glyphs
+	var hints []string
+	if m.suspendErr != nil {
+		hints = append(hints, fmt.Sprintf("%s %v", m.styles.text("Failed to suspend:"), m.suspendErr))
+	}
+	if m.autosaveErr != nil {
+		hints = append(hints, fmt.Sprintf("%s %v", m.styles.text("Failed to autosave:"), m.autosaveErr))
+	}
+	switch {
+	case m.fumenErr != nil:
+		hints = append(hints, fmt.Sprintf("%s %v", m.styles.text("Failed to copy fumen:"), m.fumenErr))
+	case m.fumenCopied:
+		hints = append(hints, m.styles.text("Fumen copied"))
+	}
+	switch {
+	case m.editing:
+		hints = append(hints, m.styles.text("Editing: arrows move the cursor, enter fills or clears, b to play"))
+	case m.paused:
+		hints = append(hints, m.styles.text("Gravity paused"))
+	case m.zone.active():
+		hints = append(hints, fmt.Sprintf("%s %s %s", m.styles.text("In the zone"), g.dash,
+			m.styles.text("time has stopped")))
+	case m.zone.ready():
+		hints = append(hints, fmt.Sprintf("%s %s %s %s %s", m.styles.text("Zone ready"), g.dash, m.styles.text("press"),
+			m.keys.Zone.Help().Key, m.styles.text("to enter")))
+	}
+	if m.puzzle != nil {
+		hints = append(hints, fmt.Sprintf("%s (%d %s)", m.puzzle.Objective(), len(m.bag.Elements)+1, m.styles.text("left")))
+	}
+	if !m.canHold && m.hold == tetris.HoldOnce {
+		hints = append(hints, m.styles.text("Hold unavailable"))
+	}
+	if m.gravity.IsSoftDrop() {
+		hints = append(hints, m.styles.text("Soft drop active"))
+	}
+	if combo := m.attack.Combo(); combo > 1 {
+		hints = append(hints, fmt.Sprintf("%s %s%d", m.styles.text("Combo"), g.times, combo))
+	}
+	if b2b := m.attack.BackToBack(); b2b > 0 {
+		hints = append(hints, fmt.Sprintf("B2B %s%d %s %s", g.times, b2b, g.dash, m.styles.text("keep it going!")))
+	}
+	if len(m.pendingHoles) > 0 {
+		hints = append(hints, fmt.Sprintf("%d %s %s %s", len(m.pendingHoles), m.styles.text("lines incoming"), g.dash,
+			m.styles.text("clear lines to cancel")))
+	}
+	return hints
+}
+
+func (m *Model) matrixView() string {
+	matrix := m.matrix.Clone()
+	if m.editing {
+		m.addCursor(matrix)
+	}
+	if m.showHoldPreview {
+		m.addHoldPreview(matrix)
+	}
+	if m.fade != nil && !m.gameOver {
+		m.hideFaded(matrix)
+	}
+	// The ghost is not shown in master mode, where tetriminos are always on the stack already, or in invisible games,
+	// where it would give away the stack.
+	if !m.classic && !m.master && m.fade == nil && !m.entering() {
+		addProjection(matrix, m.currentTet, m.currentTet.DropPosition(m.matrix), 'G')
+	}
+
+	var overlay []string
+	if m.countdown > 0 {
+		overlay = []string{fmt.Sprint(m.countdown)}
+	} else if m.showGo {
+		overlay = []string{m.styles.text("GO")}
+	} else if m.interlude != nil && m.interlude.roll {
+		overlay = []string{m.styles.text("CREDIT ROLL"), "", m.styles.text("the stack"), m.styles.text("is invisible")}
+	} else if m.interlude != nil {
+		overlay = []string{
+			fmt.Sprintf("%s %d", m.styles.text("LEVEL"), m.interlude.level),
+			"",
+			fmt.Sprintf("%.2fs %s %.2fs", m.interlude.from.Seconds(), m.styles.glyphs.arrow, m.interlude.to.Seconds()),
+			m.styles.text("per row"),
+		}
+	}
+	board := boardView(m.styles, matrix, overlay, m.danger, m.board)
+	if m.isVersus {
+		board = lipgloss.JoinVertical(lipgloss.Left, board, m.garbagePreview())
+	}
+	if m.isVersus || m.rise != nil || m.difficulty != nil {
+		board = lipgloss.JoinHorizontal(lipgloss.Top, m.garbageMeter(), board)
+	}
+	if m.zone != nil {
+		board = lipgloss.JoinHorizontal(lipgloss.Top, m.zoneMeter(), board)
+	}
+	return board
+}
+
+// garbagePreview marks the columns beneath the matrix where pending garbage will have holes when it rises.
+func (m *Model) garbagePreview() string {
+	holes := make([]bool, len(m.matrix[0]))
+	for _, col := range m.pendingHoles {
+		for i := 0; i < m.garbage.HoleWidth(); i++ {
+			holes[col+i] = true
+		}
+	}
+
+	// Skip the left border of the playfield so the marks line up with the columns.
+	var output strings.Builder
+	output.WriteString(strings.Repeat(" ", m.styles.Playfield.GetBorderLeftSize()))
+	for _, isHole := range holes {
+		if isHole {
+			output.WriteString(m.styles.GarbagePreview.Render(m.styles.glyphs.hole))
+		} else {
+			output.WriteString(m.styles.glyphs.blank)
+		}
+	}
+	return output.String()
+}
+
+// hideFaded marks the cells of the stack which have faded from view in invisible games. The current tetrimino is
+// always shown, since it is not locked yet.
+func (m *Model) hideFaded(matrix tetris.Matrix) {
+	for row := range matrix {
+		for col, cell := range matrix[row] {
+			if cell == 0 || m.occupies(row, col) || !m.fade.Hidden(row, col) {
+				continue
+			}
+			matrix[row][col] = hiddenCell
+		}
+	}
+}
+
+// occupies reports whether the cell is filled by the current tetrimino.
+func (m *Model) occupies(row, col int) bool {
+	t := m.currentTet
+	y, x := row-t.Pos.Y, col-t.Pos.X
+	return y >= 0 && y < len(t.Cells) && x >= 0 && x < len(t.Cells[y]) && t.Cells[y][x]
+}
+
+// addHoldPreview marks where the held tetrimino would land if it was swapped with the current tetrimino.
+func (m *Model) addHoldPreview(matrix tetris.Matrix) {
+	next, ok := m.held.Next()
+	if !ok || !m.canHold || m.entering() {
+		return
+	}
+
+	// The current tetrimino would be removed from the matrix by the swap.
+	swapped := m.matrix.Clone()
+	err := swapped.RemoveTetrimino(m.currentTet)
+	if err != nil || !swapped.CanAddTetrimino(&next) {
+		return
+	}
+
+	addProjection(matrix, &next, next.DropPosition(swapped), 'H')
+}
+
+// addProjection marks the empty cells the tetrimino would occupy at the given position with the given value.
+func addProjection(matrix tetris.Matrix, t *tetris.Tetrimino, pos tetris.Coordinate, value byte) {
+	for row := range t.Cells {
+		for col := range t.Cells[row] {
+			if t.Cells[row][col] && matrix.IsCellEmpty(pos.Y+row, pos.X+col) {
+				matrix[pos.Y+row][pos.X+col] = value
+			}
+		}
+	}
+}
+
+func (m *Model) informationView() string {
+	var output string
+	output += fmt.Sprintln(m.styles.text("Score")+": ", m.scoring.Total())
+	if m.celebrating() {
+		output += m.styles.NewBest.Render(fmt.Sprint(m.styles.text("Level")+":  ", m.scoring.Level())) + "\n"
+	} else {
+		output += fmt.Sprintln(m.styles.text("Level")+": ", m.scoring.Level())
+	}
+	output += fmt.Sprintln(m.styles.text("Cleared")+": ", m.scoring.Lines())
+	if m.lineGoal > 0 {
+		output += fmt.Sprintln(m.styles.text("Goal")+": ", m.lineGoal)
+	}
+	if m.cheese != nil {
+		output += fmt.Sprintln(m.styles.text("Cheese")+": ", m.cheeseRemaining())
+	}
+	if m.rise != nil {
+		output += fmt.Sprintln(m.styles.text("Dug")+": ", m.dug)
+	}
+	if m.master {
+		output += fmt.Sprintln(m.styles.text("Grade")+": ", m.grade)
+	}
+	if m.rolling() {
+		output += fmt.Sprintf("%s: %.0fs\n", m.styles.text("Roll"), m.roll.Remaining().Seconds())
+	}
+	if m.zone != nil {
+		output += m.zoneView()
+	}
+	output += m.difficultyView()
+	output += m.rulesView()
+
+	output += m.styles.text("Time") + ": " + m.timeLabel() + "\n"
+	output += m.paceView()
+
+	if m.speed != 1 {
+		output += fmt.Sprintln(m.styles.text("Speed")+": ", m.speedLabel())
+	}
+	if m.puzzle == nil {
+		// Seeds are too long to fit beside the label, so they are shown below it.
+		output += m.styles.text("Seed") + ":\n" + fmt.Sprintln(m.seed)
+	}
+
+	output += m.drillView()
+	output += m.pcView()
+
+	if m.comboSetup != nil {
+		output += fmt.Sprintln(m.styles.text("Combo")+": ", m.attack.Combo())
+		output += fmt.Sprintln(m.styles.text("Best")+": ", m.maxCombo)
+	}
+
+	if m.isVersus {
+		output += fmt.Sprintln(m.styles.text("Attack")+": ", m.attack.Sent())
+		output += fmt.Sprintln(m.styles.text("Received")+": ", m.attack.Received())
+		output += fmt.Sprintln(m.styles.text("Incoming")+": ", len(m.pendingHoles))
+	}
+
+	return m.styles.renderPanel(m.styles.Information, output)
+}
+
+// timeLabel returns the time played, or the time left in games with a time limit.
+func (m *Model) timeLabel() string {
+	elapsed := m.timer.Elapsed().Seconds()
+	if m.timeLimit > 0 {
+		elapsed = max(m.timeLimit.Seconds()-elapsed, 0)
+	}
+	minutes := int(elapsed) / 60
+	if minutes > 0 {
+		seconds := int(elapsed) % 60
+		return fmt.Sprintf("%02d:%02d", minutes, seconds)
+	}
+	return fmt.Sprintf("%06.3f", elapsed)
+}
+
+// summaryView breaks down the results of a finished game, and shows how they compare with the player's records.
+func (m *Model) summaryView() string {
+	results := m.Results()
+
+	var output strings.Builder
+	row := func(name string, value any) {
+		output.WriteString(fmt.Sprintf("%-12s%10v\n", name, value))
+	}
+
+	row(m.styles.text("Score"), results.Score)
+	drops := results.Score
+	for _, c := range results.Clears {
+		output.WriteString(fmt.Sprintf("  %-10s%4d%6d\n", m.styles.text(c.Kind.String()), c.Count, c.Points))
+		drops -= c.Points
+	}
+	output.WriteString(fmt.Sprintf("  %-10s%10d\n", m.styles.text("Drops"), drops))
+	output.WriteString("\n")
+	row(m.styles.text("Lines"), results.Lines)
+	row(m.styles.text("Level"), results.Level)
+	row(m.styles.text("Time"), results.Time.Round(time.Millisecond))
+	row(m.styles.text("Pieces"), results.Pieces)
+	row(m.styles.text("PPS"), fmt.Sprintf("%.2f", results.PPS()))
+	row(m.styles.text("Max combo"), results.MaxCombo)
+	if m.puzzle == nil {
+		row(m.styles.text("Seed"), m.seed)
+	}
+	if m.bot == nil {
+		output.WriteString("\n")
+		row(m.styles.text("KPP"), fmt.Sprintf("%.2f", results.KPP()))
+		inputs := []struct {
+			name  string
+			moves []tetris.Move
+		}{
+			{"Left", []tetris.Move{tetris.MoveLeft}},
+			{"Right", []tetris.Move{tetris.MoveRight}},
+			{"Rotate", []tetris.Move{tetris.MoveClockwise, tetris.MoveCounterClockwise}},
+			{"Soft drop", []tetris.Move{tetris.MoveSoftDrop}},
+			{"Hard drop", []tetris.Move{tetris.MoveHardDrop}},
+			{"Hold", []tetris.Move{tetris.MoveHold}},
+		}
+		for _, input := range inputs {
+			output.WriteString(fmt.Sprintf("  %-10s%10d\n", m.styles.text(input.name), results.Presses(input.moves...)))
+		}
+	}
+	if m.rise != nil {
+		row(m.styles.text("Dug"), results.Dug)
+		row(m.styles.text("Survival"), results.SurvivalScore())
+	}
+	if m.master {
+		row(m.styles.text("Grade"), results.Grade)
+	}
+	if m.drills != nil {
+		row(m.styles.text("Drills"), fmt.Sprintf("%d/%d", m.drillsExecuted, m.drillsPlayed))
+	}
+	if m.pcRandom != nil {
+		row(m.styles.text("PCs"), fmt.Sprintf("%d/%d", m.pcCleared, m.pcAttempts))
+		row(m.styles.text("Session"), fmt.Sprintf("%d/%d", m.pcSession.Cleared, m.pcSession.Attempts))
+		row(m.styles.text("Success"), fmt.Sprintf("%.0f%%", m.pcSession.Rate()))
+	}
+
+	if m.record != nil {
+		output.WriteString("\n")
+		switch {
+		case m.record.err != nil:
+			output.WriteString(m.styles.Hint.Render(m.styles.text("Failed to save record:") + "\n" + m.record.err.Error()))
+		case m.record.isBest:
+			output.WriteString(m.styles.NewBest.Render(m.styles.text("New personal best!")))
+		case m.race:
+			output.WriteString(fmt.Sprintf("%s: %s", m.styles.text("Personal best"), m.record.previous.Time.Round(time.Millisecond)))
+		default:
+			output.WriteString(fmt.Sprintf("%s: %d", m.styles.text("Personal best"), m.record.previous.Score))
+		}
+	}
+	if submission := m.submissionView(); submission != "" {
+		output.WriteString("\n\n" + submission)
+	}
+	if m.shared {
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Share text copied")))
+	}
+	if m.replayErr != nil {
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Failed to save replay:")+"\n"+m.replayErr.Error()))
+	} else if m.replayPath != "" {
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Replay saved to")+"\n"+m.replayPath))
+	}
+	if err := m.events.Err(); err != nil {
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Failed to log events:")+"\n"+err.Error()))
+	}
+	switch {
+	case m.recordingPath == "":
+	case m.recording == nil:
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Exporting recording...")))
+	case m.recording.err != nil:
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Failed to export recording:")+"\n"+
+			m.recording.err.Error()))
+	default:
+		output.WriteString("\n\n" + m.styles.Hint.Render(m.styles.text("Recording saved to")+"\n"+m.recordingPath))
+	}
+
+	return m.styles.renderPanel(m.styles.Summary, output.String())
+}
+
+// celebrateLevelUp announces the new level and how fast tetriminos now fall, and highlights the level for as long as
+// the announcement is shown, so that the change in speed doesn't go unnoticed.
+func (m *Model) celebrateLevelUp(level uint) {
+	m.addPopup(fmt.Sprintf("%s %d", m.styles.text("LEVEL"), level))
+	m.addPopup(gravityLabel(m.gravity.Interval()))
+	m.levelUpAt, m.levelledUp = m.timer.Elapsed(), true
+	m.shiftPalette(level)
+	m.logEvent("Level %d %s %s", level, m.styles.glyphs.dash, gravityLabel(m.gravity.Interval()))
+	m.narrate("level %d", level)
+}
+
+// celebrating reports whether the level increased recently enough that it is still highlighted.
+func (m *Model) celebrating() bool {
+	return m.levelledUp && m.timer.Elapsed()-m.levelUpAt < popupDuration
+}
+
+// gravityFrame is the length of the frame that fast fall speeds are given in rows per frame (G) for.
+const gravityFrame = time.Second / 60
+
+// gravityLabel describes the time taken to fall one row, such as "0.22s/row", or the rows fallen each frame once
+// that is more than one, such as "3.5G".
+func gravityLabel(interval time.Duration) string {
+	switch {
+	case interval <= 0:
+		return "20G"
+	case interval < gravityFrame:
+		return fmt.Sprintf("%.1fG", min(float64(gravityFrame)/float64(interval), 20))
 	}
-	return m
+	return fmt.Sprintf("%.2fs/row", interval.Seconds())
 }
 
-func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.fall.stopwatch.Init(), m.timer.Init())
+// speedLabel describes the gravity multiplier, such as "0.5x".
+func (m *Model) speedLabel() string {
+	return strconv.FormatFloat(m.speed, 'f', -1, 64) + m.styles.glyphs.times
 }
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, m.keys.Quit):
-			return m, tea.Quit
-		case key.Matches(msg, m.keys.Help):
-			m.help.ShowAll = !m.help.ShowAll
-		case key.Matches(msg, m.keys.Left):
-			err := m.currentTet.MoveLeft(&m.matrix)
-			if err != nil {
-				panic(fmt.Errorf("failed to move tetrimino left: %w", err))
-			}
-		case key.Matches(msg, m.keys.Right):
-			err := m.currentTet.MoveRight(&m.matrix)
-			if err != nil {
-				panic(fmt.Errorf("failed to move tetrimino right: %w", err))
-			}
-		case key.Matches(msg, m.keys.Clockwise):
-			err := m.currentTet.Rotate(&m.matrix, true)
-			if err != nil {
-				panic(fmt.Errorf("failed to rotate tetrimino clockwise: %w", err))
-			}
-		case key.Matches(msg, m.keys.CounterClockwise):
-			err := m.currentTet.Rotate(&m.matrix, false)
-			if err != nil {
-				panic(fmt.Errorf("failed to rotate tetrimino counter-clockwise: %w", err))
-			}
-		case key.Matches(msg, m.keys.HardDrop):
-			for {
-				finished, err := m.lowerTetrimino()
-				if err != nil {
-					panic(fmt.Errorf("failed to lower tetrimino (hard drop): %w", err))
-				}
-				if finished {
-					break
-				}
-			}
-		case key.Matches(msg, m.keys.SoftDrop):
-			m.fall.toggleSoftDrop()
-		case key.Matches(msg, m.keys.Hold):
-			err := m.holdTetrimino()
-			if err != nil {
-				panic(fmt.Errorf("failed to hold tetrimino: %w", err))
-			}
+// statisticsView shows the rates of play so far and how often each tetrimino has been placed.
+func (m *Model) statisticsView() string {
+	results := m.Results()
+	var output string
+	output += fmt.Sprintln(m.styles.text("Pieces")+": ", results.Pieces)
+	output += fmt.Sprintf("PPS: %.2f\n", results.PPS())
+	output += fmt.Sprintf("APM: %.1f\n", results.APM())
+	output += fmt.Sprintf("%s: %.0f%%\n", m.styles.text("Tetris"), m.stats.TetrisRate()*100)
+	output += "\n"
+	for _, t := range tetris.Tetriminos {
+		output += fmt.Sprintf("%s %c %d\n", m.styles.renderCell(t.Value), t.Value, m.stats.PieceCount(t.Value))
+	}
+	return m.styles.renderPanel(m.styles.Statistics, output)
+}
+
+func (m *Model) holdView() string {
+	slide := m.holdSlide()
+	if m.held.Slots <= 1 {
+		var value byte
+		if len(m.held.Held) > 0 {
+			value = m.held.Held[0].Value
 		}
-	case stopwatch.TickMsg:
-		if m.fall.stopwatch.ID() != msg.ID {
-			break
+		return m.styles.Hold.Render(m.styles.text("Hold") + ":\n" + m.styles.renderShiftedPreview(value, slide))
+	}
+
+	// Each slot has a preview, with the tetrimino swapped in next at the top and the one held last at the bottom.
+	output := m.styles.text("Hold") + ":"
+	for i := 0; i < m.held.Slots; i++ {
+		var value byte
+		if i < len(m.held.Held) {
+			value = m.held.Held[i].Value
 		}
-		_, err := m.lowerTetrimino()
-		if err != nil {
-			panic(fmt.Errorf("failed to lower tetrimino (tick): %w", err))
+		shift := 0
+		if i == len(m.held.Held)-1 {
+			shift = slide
 		}
+		output += "\n" + m.styles.renderShiftedPreview(value, shift)
 	}
+	return m.styles.Hold.UnsetHeight().Render(output)
+}
 
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
-
-	m.timer, cmd = m.timer.Update(msg)
-	cmds = append(cmds, cmd)
+// holdSwapDuration is how long the tetrimino put on hold takes to slide into the hold from the side of the board.
+const holdSwapDuration = 150 * time.Millisecond
 
-	m.fall.stopwatch, cmd = m.fall.stopwatch.Update(msg)
-	cmds = append(cmds, cmd)
+// holdSlide returns how many columns the tetrimino held last has still to slide into the hold, as it swaps places with
+// the one taken out.
+func (m *Model) holdSlide() int {
+	if !m.swapped {
+		return 0
+	}
+	progress := float64(m.timer.Elapsed()-m.heldAt) / float64(holdSwapDuration)
+	if progress >= 1 || progress < 0 {
+		return 0
+	}
+	return int(float64(previewColumns*m.styles.cellWidth()) * (1 - progress))
+}
 
-	return m, tea.Batch(cmds...)
+func (m *Model) bagView() string {
+	if m.styles.queue != theme.QueueSide {
+		// The queue is drawn in a row with the board instead, leaving only the sandbox picker in this column.
+		if m.sandbox {
+			return m.pickerView()
+		}
+		return ""
+	}
+	queue := QueueView(m.styles, m.queued())
+	if m.sandbox {
+		return lipgloss.JoinVertical(lipgloss.Left, queue, m.pickerView())
+	}
+	return queue
 }
 
-func (m Model) View() string {
-	var output = lipgloss.JoinHorizontal(lipgloss.Top,
-		lipgloss.JoinVertical(lipgloss.Right, m.holdView(), m.informationView()),
-		m.matrixView(),
-		m.bagView(),
-	)
+// queueRowView adds the queue in a row above or below the board, if it is drawn there, with as many of the upcoming
+// tetriminos as fit in the board's width.
+func (m *Model) queueRowView(board string) string {
+	switch m.styles.queue {
+	case theme.QueueAbove:
+		return lipgloss.JoinVertical(lipgloss.Left, QueueRowView(m.styles, m.queued(), lipgloss.Width(board)), board)
+	case theme.QueueBelow:
+		return lipgloss.JoinVertical(lipgloss.Left, board, QueueRowView(m.styles, m.queued(), lipgloss.Width(board)))
+	}
+	return board
+}
 
-	return output + "\n" + m.help.View(m.keys)
+// queued returns the upcoming tetriminos shown in the queue.
+func (m *Model) queued() []tetris.Tetrimino {
+	return m.bag.Elements[:min(m.queueLen, len(m.bag.Elements))]
 }
 
-func (m *Model) matrixView() string {
-	var output string
-	for row := (len(m.matrix) - 20); row < len(m.matrix); row++ {
-		for col := range m.matrix[row] {
-			output += m.renderCell(m.matrix[row][col])
+// HoldView renders the held tetriminos with the values, each in its own preview frame with the one swapped in next at
+// the top, or an empty frame until something is held. It is used for spectated games, since local ones slide the
+// tetrimino put on hold into place (see holdView).
+func HoldView(styles *Styles, values string) string {
+	if len(values) <= 1 {
+		var value byte
+		if values != "" {
+			value = values[0]
 		}
-		if row < len(m.matrix)-1 {
-			output += "\n"
+		return styles.Hold.Render(styles.text("Hold") + ":\n" + styles.renderPreview(value))
+	}
+	output := styles.text("Hold") + ":"
+	for i := range values {
+		output += "\n" + styles.renderPreview(values[i])
+	}
+	return styles.Hold.UnsetHeight().Render(output)
+}
+
+// QueueView renders the upcoming tetriminos, each in a preview frame of the same size. It is used for both local games
+// and spectated ones.
+func QueueView(styles *Styles, tetriminos []tetris.Tetrimino) string {
+	output := styles.text("Next") + ":\n"
+	for i := range tetriminos {
+		output += "\n" + styles.renderPreview(tetriminos[i].Value)
+	}
+	return styles.Bag.Render(output)
+}
+
+// QueueRowView renders the upcoming tetriminos side by side, leaving out those after the first which don't fit in the
+// width.
+func QueueRowView(styles *Styles, tetriminos []tetris.Tetrimino, width int) string {
+	output := styles.text("Next") + ":"
+	for i := range tetriminos {
+		row := lipgloss.JoinHorizontal(lipgloss.Top, output, " ",
+			strings.TrimSuffix(styles.renderPreview(tetriminos[i].Value), "\n"))
+		if i > 0 && lipgloss.Width(styles.QueueRow.Render(row)) > width {
+			break
 		}
+		output = row
 	}
+	return styles.QueueRow.Render(output)
+}
 
-	var rowIndicator string
-	for i := 1; i <= 20; i++ {
-		rowIndicator += fmt.Sprintf("%d\n", i)
+// heldFront returns the tetrimino which has been held longest, or the empty hold if none has been.
+func (m *Model) heldFront() tetris.Tetrimino {
+	if len(m.held.Held) == 0 {
+		return *emptyHold()
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Center, m.styles.Playfield.Render(output), m.styles.RowIndicator.Render(rowIndicator))
+	return m.held.Held[0]
 }
 
-func (m *Model) informationView() string {
-	var output string
-	output += fmt.Sprintln("Score: ", m.scoring.Total())
-	output += fmt.Sprintln("Level: ", m.scoring.Level())
-	output += fmt.Sprintln("Cleared: ", m.scoring.Lines())
+// emptyHold returns the hold as it is before anything has been held.
+func emptyHold() *tetris.Tetrimino {
+	return &tetris.Tetrimino{
+		Cells: [][]bool{
+			{false, false, false},
+			{false, false, false},
+			{false, false, false},
+		},
+	}
+}
 
-	elapsed := m.timer.Elapsed().Seconds()
-	minutes := int(elapsed) / 60
+func (m *Model) holdTetrimino() error {
+	if !m.canHold || m.hold == tetris.HoldOff {
+		return nil
+	}
 
-	output += "Time: "
-	if minutes > 0 {
-		seconds := int(elapsed) % 60
-		output += fmt.Sprintf("%02d:%02d\n", minutes, seconds)
-	} else {
-		output += fmt.Sprintf("%06.3f\n", elapsed)
+	// Swap the current tetrimino with the held one at the front of the queue, or the next if a slot is free.
+	held := m.currentTet
+	next, err := m.held.Hold(m.matrix, held, m.bag)
+	switch {
+	case errors.Is(err, tetris.ErrBlockOut):
+		// The tetrimino swapped in is blocked from spawning (block out).
+		m.gameOver = true
+		return nil
+	case errors.Is(err, tetris.ErrEmptyBag):
+		// There is nothing left to swap in, so the tetrimino stays in play.
+		return nil
+	case err != nil:
+		return err
 	}
+	m.currentTet = next
+	// The tetrimino swapped in wasn't rotated into place, so can't make a T-spin until it is.
+	m.lastRotation = nil
+	m.heldAt, m.swapped = m.timer.Elapsed(), true
 
-	return m.styles.Information.Render(output)
+	m.narrate("%c held, %c piece spawned", held.Value, m.currentTet.Value)
+	m.canHold = m.hold == tetris.HoldUnlimited
+	m.resetLockDelay()
+	return nil
 }
 
-func (m *Model) holdView() string {
-	output := "Hold:\n" + m.renderTetrimino(m.holdTet, 1)
-	return m.styles.Hold.Render(output)
+// isLegal reports whether the move should be applied. Moves are always legal unless in strict mode.
+func (m *Model) isLegal(move tetris.Move) bool {
+	if m.inputChecker == nil {
+		return true
+	}
+	return m.inputChecker.Check(move, m.clock.Now().Sub(m.startTime)) == nil
 }
 
-func (m *Model) bagView() string {
-	output := "Next:\n"
-	for i, t := range m.bag.Elements {
-		if i > 5 {
-			break
+// moveSideways moves the current tetrimino left or right for a press of the key, by as many cells as the handling
+// allows. Presses the terminal repeats while the key is held may move it by none, or all the way to the wall.
+func (m *Model) moveSideways(move tetris.Move) error {
+	cells := m.shift.Press(move, m.clock.Now().Sub(m.startTime))
+	for i := 0; i < cells; i++ {
+		var moved bool
+		var err error
+		if move == tetris.MoveLeft {
+			moved, err = m.currentTet.MoveLeft(&m.matrix)
+		} else {
+			moved, err = m.currentTet.MoveRight(&m.matrix)
+		}
+		if err != nil || !moved {
+			if i > 0 {
+				m.narratePosition()
+			}
+			return err
 		}
-		output += "\n" + m.renderTetrimino(&t, 1)
+		m.moved()
 	}
-	return m.styles.Bag.Render(output)
+	if cells > 0 {
+		m.narratePosition()
+	}
+	return nil
 }
 
-func (m *Model) renderTetrimino(t *tetris.Tetrimino, background byte) string {
-	var output string
-	for row := range t.Cells {
-		for col := range t.Cells[row] {
-			if t.Cells[row][col] {
-				output += m.renderCell(t.Value)
-			} else {
-				output += m.renderCell(background)
-			}
-		}
-		output += "\n"
+// rotation is where a tetrimino was rotated to, and the kick which moved it there (see tetris.Kicks).
+type rotation struct {
+	pos   tetris.Coordinate
+	state int
+	kick  int
+}
+
+// rotate rotates the current tetrimino, restarting the lock delay if it turned.
+func (m *Model) rotate(clockwise bool) error {
+	kick, err := m.currentTet.RotateWithKicks(&m.matrix, clockwise)
+	if err != nil {
+		return err
 	}
-	return output
+	if kick >= 0 {
+		m.moved()
+		m.lastRotation = &rotation{pos: m.currentTet.Pos, state: m.currentTet.CurrentRotation, kick: kick}
+		m.narratePosition()
+	}
+	return nil
 }
 
-func (m *Model) renderCell(cell byte) string {
-	switch cell {
-	case 0:
-		return m.styles.ColIndicator.Render("▕ ")
-	case 1:
-		return m.styles.TetriminoStyles[cell].Render("  ")
-	case 'G':
-		return "░░"
-	default:
-		cellStyle, ok := m.styles.TetriminoStyles[cell]
-		if ok {
-			return cellStyle.Render("██")
-		}
+// moved tells the lock delay that the current tetrimino was moved or rotated, which restarts it if the lock-down rule
+// allows.
+func (m *Model) moved() {
+	m.lastRotation = nil
+	if m.lockDelay != nil {
+		m.lockDelay.Moved()
 	}
-	return "??"
 }
 
-func (m *Model) holdTetrimino() error {
-	if !m.canHold {
-		return nil
+// tSpin returns the T-spin the current tetrimino makes where it is. Only a T which was rotated there, and hasn't
+// moved since, makes one.
+func (m *Model) tSpin() tetris.Spin {
+	r := m.lastRotation
+	if r == nil || r.pos != m.currentTet.Pos || r.state != m.currentTet.CurrentRotation {
+		return tetris.SpinNone
 	}
+	return m.matrix.TSpin(m.currentTet, r.kick)
+}
 
-	// Swap the current tetrimino with the hold tetrimino
-	if m.holdTet.Value == 0 {
-		m.holdTet = m.currentTet
-		m.currentTet = m.bag.Next()
-	} else {
-		m.holdTet, m.currentTet = m.currentTet, m.holdTet
+// input makes a gameplay move, from a key press or a MoveMsg. Between tetriminos only hold, rotation and sideways moves
+// are kept, to be applied to the next as it spawns.
+func (m *Model) input(move tetris.Move) {
+	if !m.isLegal(move) {
+		return
+	}
+	m.events.Input(m.timer.Elapsed(), move)
+	if !m.entering() || m.initialInputs.Buffer(move) {
+		m.stats.ProcessInput(move)
+		m.pieceInputs = append(m.pieceInputs, move)
 	}
+	if !m.entering() {
+		m.play(move)
+	}
+	err := m.settle()
+	if err != nil {
+		m.fail(fmt.Errorf("failed to settle tetrimino: %w", err))
+	}
+}
 
-	m.matrix.RemoveTetrimino(m.holdTet)
+// play moves the current tetrimino as the player asked.
+func (m *Model) play(move tetris.Move) {
+	switch move {
+	case tetris.MoveLeft:
+		err := m.moveSideways(tetris.MoveLeft)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to move tetrimino left: %w", err))
+		}
+	case tetris.MoveRight:
+		err := m.moveSideways(tetris.MoveRight)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to move tetrimino right: %w", err))
+		}
+	case tetris.MoveClockwise:
+		err := m.rotate(true)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to rotate tetrimino clockwise: %w", err))
+		}
+	case tetris.MoveCounterClockwise:
+		err := m.rotate(false)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to rotate tetrimino counter-clockwise: %w", err))
+		}
+	case tetris.MoveHardDrop:
+		err := m.hardDrop()
+		if err != nil {
+			m.fail(fmt.Errorf("failed to hard drop: %w", err))
+		}
+	case tetris.MoveSoftDrop:
+		switch {
+		case m.master:
+			if !m.currentTet.CanMoveDown(m.matrix) {
+				m.lockTetrimino()
+			}
+		case m.toggleSoftDrop:
+			m.gravity.ToggleSoftDrop()
+		default:
+			m.gravity.HoldSoftDrop(m.repeatWindow)
+		}
+	case tetris.MoveHold:
+		err := m.holdTetrimino()
+		if err != nil {
+			m.fail(fmt.Errorf("failed to hold tetrimino: %w", err))
+		}
+	}
+}
 
-	// Reset the position of the hold tetrimino
-	var found bool
-	for _, t := range tetris.Tetriminos {
-		if t.Value == m.holdTet.Value {
-			m.holdTet.Pos = t.Pos
-			m.holdTet.Pos.Y += (len(m.matrix) - 20)
-			found = true
-			break
+// playBotAction performs the next action planned by the bot, planning the placement of the current tetrimino if needed.
+func (m *Model) playBotAction() error {
+	if len(m.botActions) == 0 {
+		actions, err := m.bot.Plan(m.matrix, m.currentTet)
+		if err != nil {
+			return fmt.Errorf("failed to plan placement: %w", err)
 		}
+		m.botActions = actions
+	}
+
+	action := m.botActions[0]
+	m.botActions = m.botActions[1:]
+
+	var moved bool
+	var err error
+	switch action {
+	case bot.ActionLeft:
+		moved, err = m.currentTet.MoveLeft(&m.matrix)
+	case bot.ActionRight:
+		moved, err = m.currentTet.MoveRight(&m.matrix)
+	case bot.ActionClockwise:
+		return m.rotate(true)
+	case bot.ActionHardDrop:
+		m.botActions = nil
+		return m.hardDrop()
+	default:
+		return fmt.Errorf("invalid bot action: %v", action)
 	}
-	if !found {
-		return fmt.Errorf("failed to find tetrimino with value '%v'", m.currentTet.Value)
+	if moved {
+		m.moved()
 	}
+	return err
+}
 
-	// Add the current tetrimino to the matrix
-	err := m.matrix.AddTetrimino(m.currentTet)
+// hardDrop drops the current tetrimino onto the stack and locks it, scoring points for each row it dropped.
+func (m *Model) hardDrop() error {
+	rows, err := m.currentTet.Drop(&m.matrix)
 	if err != nil {
-		return fmt.Errorf("failed to add tetrimino to matrix: %w", err)
+		return fmt.Errorf("failed to move tetrimino down: %w", err)
+	}
+	m.scoring.AddHardDrop(uint(rows))
+	m.lockTetrimino()
+	return nil
+}
+
+// newAttack creates the attack sending lines by the input's table and multiplier.
+func newAttack(in *Input) *tetris.Attack {
+	if in.AttackTable == nil && in.GarbageMultiplier == 0 {
+		return tetris.NewAttack()
+	}
+	table := tetris.DefaultAttackTable()
+	if in.AttackTable != nil {
+		table = *in.AttackTable
+	}
+	multiplier := in.GarbageMultiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	return tetris.NewCustomAttack(table, multiplier)
+}
+
+// receiveGarbage queues lines of garbage from an opponent, to be added when the next tetrimino locks.
+func (m *Model) receiveGarbage(lines uint) {
+	m.logEvent("Received %d lines", lines)
+	m.narrate("%d lines of garbage incoming", lines)
+	m.attack.Receive(lines)
+	m.pendingHoles = append(m.pendingHoles, m.garbage.Holes(int(lines), len(m.matrix[0]))...)
+}
+
+// applyGravity lowers the current tetrimino by the rows it has fallen since the last frame, scoring a point for each
+// row it is soft dropped. Any remaining rows are dropped once it lands, so that it doesn't carry on falling if it is
+// moved off the stack, and the next tetrimino starts from the top.
+func (m *Model) applyGravity() error {
+	if m.gravity.IsSonicDrop() {
+		rows, err := m.currentTet.Drop(&m.matrix)
+		if err != nil {
+			return fmt.Errorf("failed to sonic drop tetrimino: %w", err)
+		}
+		m.scoring.AddSoftDrop(uint(rows))
 	}
 
-	m.canHold = false
+	softDrop := m.gravity.IsSoftDrop()
+	for rows := m.gravity.Rows(); rows > 0 && !m.gameOver; rows-- {
+		landed, err := m.lowerTetrimino()
+		if err != nil {
+			return err
+		}
+		if landed {
+			break
+		}
+		if softDrop {
+			m.scoring.AddSoftDrop(1)
+		}
+	}
 	return nil
 }
 
+// lowerTetrimino moves the current tetrimino down a row, reporting whether it had already landed. A landed tetrimino
+// is locked straight away if there is no lock delay, and otherwise locks once the lock delay has passed.
 func (m *Model) lowerTetrimino() (bool, error) {
 	if !m.currentTet.CanMoveDown(m.matrix) {
-		action := m.matrix.RemoveCompletedLines(m.currentTet)
-		m.scoring.ProcessAction(action)
-		m.currentTet = m.bag.Next()
-		err := m.matrix.AddTetrimino(m.currentTet)
-		if err != nil {
-			return false, fmt.Errorf("failed to add tetrimino to matrix: %w", err)
+		if m.lockDelay == nil {
+			m.lockTetrimino()
 		}
-		m.canHold = true
 		return true, nil
 	}
 
-	err := m.currentTet.MoveDown(&m.matrix)
+	_, err := m.currentTet.MoveDown(&m.matrix)
 	if err != nil {
 		return false, fmt.Errorf("failed to move tetrimino down: %w", err)
 	}
 
 	return false, nil
 }
+
+// lockTetrimino locks the current tetrimino where it is, clearing any completed lines, and deals the next one.
+// In master mode the next tetrimino spawns once the entry delay has passed.
+func (m *Model) lockTetrimino() {
+	m.fumenCopied, m.fumenErr = false, nil
+	if m.spawned != nil {
+		m.undo.Push(*m.spawned)
+		m.spawned = nil
+	}
+	garbage := m.matrix.GarbageLines()
+	lockOut := m.matrix.IsLockOut(m.currentTet)
+	// Each row of minos cleared counts as a line, however many rows of cells it spans in big games.
+	lines := len(m.matrix.CompletedLines(m.currentTet)) / max(m.currentTet.Scale, 1)
+	if m.replay != nil {
+		m.replay.Add(m.currentTet, m.timer.Elapsed(), m.pieceInputs, m.scoring.Total()-m.lockedScore)
+	}
+	m.pieceInputs = nil
+	m.events.Lock(m.timer.Elapsed(), m.currentTet)
+	if m.fade != nil {
+		m.fade.Lock(m.currentTet)
+		m.fade.RemoveLines(m.matrix.CompletedLines(m.currentTet))
+	}
+	spin := m.tSpin()
+	m.lastRotation = nil
+	// A drill is over once its T locks, after the roof has been placed.
+	drilled := m.drill != nil && m.currentTet.Value == 'T'
+	executed := drilled && m.drill.Executed(m.currentTet, spin)
+	m.reviewPlacement()
+	cleared := m.currentTet
+	if m.zone.active() {
+		// Lines completed in the zone are stacked at the bottom rather than cleared, so there are none to remove.
+		m.stackZoneLines(m.currentTet)
+		cleared, lines, spin = &tetris.Tetrimino{}, 0, tetris.SpinNone
+	}
+	m.zone.charge(lines)
+	action := m.matrix.ClearLines(cleared, spin)
+	if m.rise != nil {
+		m.dug += uint(garbage - m.matrix.GarbageLines())
+	}
+	chain := m.attack.BackToBack()
+	points := m.scoring.ProcessAction(action)
+	m.lockedScore = m.scoring.Total()
+	m.stats.ProcessLock(m.currentTet.Value, action, points)
+	m.passCheckpoints()
+	change, levelUp := m.scoring.LevelChanged()
+	if levelUp {
+		from := m.gravity.Interval()
+		m.gravity.SetLevel(change.To)
+		if m.showInterludes {
+			m.pendingInterlude = &interlude{level: change.To, from: from, to: m.gravity.Interval()}
+		}
+		if m.master {
+			m.lockDelay.SetLength(scaled(tetris.MasterLockDelay(change.To), m.speed))
+			m.entryTime = scaled(tetris.MasterEntryDelay(change.To), m.speed)
+			m.lineClearTime = scaled(tetris.MasterLineClearDelay(change.To), m.speed)
+		}
+		m.audio.Play(audio.LevelUp)
+	} else if action.ClearsLines() {
+		m.audio.Play(audio.Clear)
+	} else {
+		m.audio.Play(audio.Lock)
+	}
+	if m.master {
+		if !m.rolling() && m.scoring.Level() > masterFinalLevel {
+			m.startRoll()
+		}
+	}
+
+	// Lines sent cancel out pending garbage before any remainder is sent to opponents.
+	sent := m.attack.ProcessAction(action)
+	cancelled := min(sent, uint(len(m.pendingHoles)))
+	m.pendingHoles = m.pendingHoles[cancelled:]
+	m.pendingAttack += sent - cancelled
+	m.maxCombo = max(m.maxCombo, m.attack.Combo())
+	if m.replay != nil {
+		m.replay.Cleared(action.String(), uint(lines), m.attack.Combo())
+	}
+	if lines > 0 || action.String() != "" {
+		m.events.Clear(m.timer.Elapsed(), action.String(), uint(lines), m.scoring.Total())
+	}
+
+	m.logEvent("%c locked", m.currentTet.Value)
+	if name := action.String(); name != "" {
+		m.addPopup(name)
+		m.logEvent("%s", name)
+	}
+	switch {
+	case action.ClearsLines():
+		m.narrate("%s clear, score %d", strings.ToLower(action.String()), m.scoring.Total())
+	case action.String() != "":
+		m.narrate("%s, score %d", strings.ToLower(action.String()), m.scoring.Total())
+	}
+	if m.attack.BackToBack() > chain && !m.classic {
+		m.addPopup("+B2B")
+	}
+	if levelUp {
+		m.celebrateLevelUp(change.To)
+	}
+
+	if m.comboSetup != nil && !action.ClearsLines() {
+		// The combo is broken, so start again from the setup.
+		m.matrix = m.comboSetup.Clone()
+		if m.fade != nil {
+			m.fade.Reset()
+		}
+	} else if lockOut {
+		// The tetrimino locked entirely above the visible rows (lock out).
+		m.gameOver = true
+		return
+	}
+	if drilled {
+		m.finishDrill(executed)
+		if m.gameOver {
+			return
+		}
+	}
+	if m.pcRandom != nil {
+		m.placedPC()
+		if m.gameOver {
+			return
+		}
+	}
+
+	if !action.ClearsLines() && len(m.pendingHoles) > 0 {
+		holes := m.pendingHoles
+		m.pendingHoles = nil
+		if m.addGarbage(m.garbage, holes) {
+			m.gameOver = true
+			return
+		}
+	}
+	m.adapt(lines)
+	m.narrateStack()
+
+	if m.lineGoal > 0 && m.scoring.Lines() >= m.lineGoal {
+		m.gameOver = true
+		m.completed = true
+		return
+	}
+	if m.puzzle != nil {
+		if m.puzzle.Solved(m.matrix, m.stats.Lines(), spin, uint(lines)) {
+			m.gameOver = true
+			m.completed = true
+			return
+		}
+		if m.bag.Empty() {
+			// The queue has run out before the puzzle was solved.
+			m.gameOver = true
+			return
+		}
+	}
+	if m.cheese != nil {
+		if m.addCheese() {
+			m.gameOver = true
+			return
+		}
+		if m.cheeseRemaining() == 0 {
+			m.gameOver = true
+			m.completed = true
+			return
+		}
+	}
+	entry := m.entryTime
+	if action.ClearsLines() {
+		entry += m.lineClearTime
+	}
+	if entry > 0 {
+		m.entryDelay.SetLength(entry)
+		m.entryDelay.Start()
+		return
+	}
+	m.spawnTetrimino()
+}
+
+// startRoll clears the stack and starts the credit roll, once the final level of master mode has been cleared.
+// The stack is invisible for the rest of the game.
+func (m *Model) startRoll() {
+	m.matrix = tetris.NewMatrix(len(m.matrix[0]), m.matrix.VisibleRows())
+	m.fade = tetris.NewFade(m.timer, m.matrix, 0)
+	m.showHoldPreview = false
+	m.keys.Fumen.SetEnabled(false)
+	m.roll.Start()
+	m.grade.StartRoll()
+	m.pendingInterlude = &interlude{roll: true}
+}
+
+// rolling reports whether the credit roll has started, in master mode.
+func (m *Model) rolling() bool {
+	return m.master && m.roll.Running()
+}
+
+// gradeName returns the grade earned in master mode, or an empty string otherwise.
+func (m *Model) gradeName() string {
+	if m.grade == nil {
+		return ""
+	}
+	return m.grade.String()
+}
+
+// addGarbage pushes the stack up with a line of garbage for each hole column given, as Matrix.AddGarbage does, with
+// holes as wide as those of the generator which chose them. Garbage rising during play is kept in the replay.
+func (m *Model) addGarbage(g *tetris.GarbageGenerator, holes []int) bool {
+	if m.fade != nil {
+		m.fade.Raise(len(holes))
+	}
+	if m.replay != nil {
+		m.replay.AddGarbage(m.timer.Elapsed(), holes, g.HoleWidth())
+	}
+	return m.matrix.AddWideGarbage(holes, g.HoleWidth())
+}
+
+// addCheese tops the cheese on the board back up to its height, while there are rows left to add.
+// It returns true if the stack was pushed out of the top of the matrix (top out).
+func (m *Model) addCheese() bool {
+	lines := min(uint(max(m.cheeseHeight-m.matrix.GarbageLines(), 0)), m.cheeseLeft)
+	if lines == 0 {
+		return false
+	}
+	m.cheeseLeft -= lines
+	return m.addGarbage(m.cheese, m.cheese.Holes(int(lines), len(m.matrix[0])))
+}
+
+// cheeseRemaining returns the number of rows of cheese left to dig out, including those not yet added.
+func (m *Model) cheeseRemaining() uint {
+	return uint(m.matrix.GarbageLines()) + m.cheeseLeft
+}
+
+// raiseGarbage pushes the stack up with a line of garbage from the bottom, in dig races. The current tetrimino stays
+// where it is unless the stack rises into it, in which case it is pushed up too.
+func (m *Model) raiseGarbage() error {
+	if m.entering() {
+		// The last tetrimino has locked, and the next has yet to spawn.
+		m.gameOver = m.addGarbage(m.garbage, m.garbage.Holes(1, len(m.matrix[0])))
+		return nil
+	}
+	err := m.matrix.RemoveTetrimino(m.currentTet)
+	if err != nil {
+		return fmt.Errorf("failed to remove tetrimino: %w", err)
+	}
+	if m.addGarbage(m.garbage, m.garbage.Holes(1, len(m.matrix[0]))) {
+		m.gameOver = true
+		return nil
+	}
+	if m.matrix.AddTetrimino(m.currentTet) == nil {
+		return nil
+	}
+	m.currentTet.Pos.Y--
+	if m.matrix.AddTetrimino(m.currentTet) != nil {
+		// There is no room above the stack for the tetrimino (top out).
+		m.gameOver = true
+	}
+	return nil
+}
+
+// spawnTetrimino deals the next tetrimino at the top of the matrix.
+func (m *Model) spawnTetrimino() {
+	m.currentTet = m.bag.Next()
+	err := m.matrix.AddTetrimino(m.currentTet)
+	if err != nil {
+		// The new tetrimino is blocked from spawning (block out).
+		m.gameOver = true
+		return
+	}
+	m.narrate("%c piece spawned", m.currentTet.Value)
+	m.events.Spawn(m.timer.Elapsed(), m.currentTet)
+	m.canHold = true
+	m.resetLockDelay()
+	m.takeSnapshot()
+	m.applyInitialInputs()
+}
+
+// applyInitialInputs applies the hold, rotation and sideways moves pressed during the entry delay to the tetrimino
+// which has just spawned (IHS and IRS). Each sideways move shifts it by one column, whatever the handling.
+func (m *Model) applyInitialInputs() {
+	for _, move := range m.initialInputs.Take() {
+		var err error
+		switch move {
+		case tetris.MoveHold:
+			err = m.holdTetrimino()
+		case tetris.MoveClockwise, tetris.MoveCounterClockwise:
+			err = m.rotate(move == tetris.MoveClockwise)
+		case tetris.MoveLeft:
+			_, err = m.currentTet.MoveLeft(&m.matrix)
+		case tetris.MoveRight:
+			_, err = m.currentTet.MoveRight(&m.matrix)
+		}
+		if err != nil {
+			m.fail(fmt.Errorf("failed to apply %v as the tetrimino spawned: %w", move, err))
+			return
+		}
+		if m.gameOver {
+			return
+		}
+	}
+}
+
+// entering reports whether the game is waiting for the entry delay to pass before the next tetrimino spawns.
+func (m *Model) entering() bool {
+	return m.entryDelay.Running()
+}
+
+// settle drops the current tetrimino onto the stack when gravity is instant, as in master mode, and tells the lock
+// delay where it is so that it starts once the tetrimino has landed.
+func (m *Model) settle() error {
+	if m.lockDelay == nil || m.entering() || m.gameOver {
+		return nil
+	}
+	if m.gravity.Instant() {
+		_, err := m.currentTet.Drop(&m.matrix)
+		if err != nil {
+			return fmt.Errorf("failed to move tetrimino down: %w", err)
+		}
+	}
+	m.lockDelay.Update(m.currentTet.Pos.Y, !m.currentTet.CanMoveDown(m.matrix))
+	return nil
+}
+
+// resetLockDelay starts the lock delay afresh for a new current tetrimino.
+func (m *Model) resetLockDelay() {
+	if m.lockDelay != nil {
+		m.lockDelay.Reset(m.currentTet.Pos.Y)
+	}
+}
+
+// updateEntry spawns the next tetrimino once the entry delay has passed. Gravity starts afresh for it, rather than
+// counting the time spent waiting.
+func (m *Model) updateEntry() {
+	if !m.entering() || !m.entryDelay.Expired() {
+		return
+	}
+	m.entryDelay.Stop()
+	m.spawnTetrimino()
+	m.gravity.Reset()
+}
+
+// updateDelays locks the current tetrimino once the lock delay has passed.
+func (m *Model) updateDelays() error {
+	if m.lockDelay == nil || m.entering() || m.gameOver {
+		return nil
+	}
+	err := m.settle()
+	if err != nil {
+		return err
+	}
+	if m.lockDelay.Expired() {
+		m.lockTetrimino()
+	}
+	return nil
+}
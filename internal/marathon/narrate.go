@@ -0,0 +1,83 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// narrationLength is the number of announcements shown in screen reader mode. Older announcements scroll off the top,
+// so that screen readers only have the latest lines to read out.
+const narrationLength = 6
+
+// narrate announces a change to the game in screen reader mode, dropping the oldest announcement once there are
+// narrationLength.
+func (m *Model) narrate(format string, args ...any) {
+	if !m.screenReader {
+		return
+	}
+	m.narration = append(m.narration, fmt.Sprintf(format, args...))
+	if len(m.narration) > narrationLength {
+		m.narration = m.narration[len(m.narration)-narrationLength:]
+	}
+}
+
+// narratePosition announces the columns the current tetrimino covers, numbered from 1 on the left, after it is moved
+// or rotated.
+func (m *Model) narratePosition() {
+	if !m.screenReader {
+		return
+	}
+	left, right := len(m.matrix[0]), 0
+	for _, row := range m.currentTet.Cells {
+		for col, filled := range row {
+			if filled {
+				left = min(left, m.currentTet.Pos.X+col+1)
+				right = max(right, m.currentTet.Pos.X+col+1)
+			}
+		}
+	}
+	if left == right {
+		m.narrate("%c in column %d", m.currentTet.Value, left)
+		return
+	}
+	m.narrate("%c in columns %d to %d", m.currentTet.Value, left, right)
+}
+
+// narrateStack announces the height of the stack and the columns with holes in them, once a tetrimino has locked. It
+// is left out while the stack fades from sight, as in invisible games, where it would give away what can't be seen.
+func (m *Model) narrateStack() {
+	if !m.screenReader || m.fade != nil {
+		return
+	}
+	height := 0
+	var holes []string
+	for col := range m.matrix[0] {
+		covered := false
+		for row := range m.matrix {
+			if !m.matrix.IsCellEmpty(row, col) {
+				height = max(height, len(m.matrix)-row)
+				covered = true
+			} else if covered {
+				holes = append(holes, fmt.Sprint(col+1))
+				break
+			}
+		}
+	}
+	switch len(holes) {
+	case 0:
+		m.narrate("stack height %d", height)
+	case 1:
+		m.narrate("stack height %d, hole in column %s", height, holes[0])
+	default:
+		m.narrate("stack height %d, holes in columns %s", height, strings.Join(holes, ", "))
+	}
+}
+
+// narrationView lists the recent announcements, oldest first, in place of the game in screen reader mode.
+func (m *Model) narrationView() string {
+	output := strings.Join(m.narration, "\n")
+	if m.gameOver {
+		output += "\n" + m.summaryView()
+	}
+	return output + "\n" + m.helpView()
+}
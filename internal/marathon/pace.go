@@ -0,0 +1,116 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paceMsg carries the checkpoints of the personal best, the best splits and those of the last game, read once the
+// game starts.
+type paceMsg struct {
+	id     int
+	best   []records.Checkpoint
+	splits []time.Duration
+	last   []time.Duration
+}
+
+// splitRows is the number of splits listed once the game ends. Earlier splits are left out of long games.
+const splitRows = 16
+
+// loadPace reads the checkpoints of the personal best the game is compared with as it is played. It does nothing for
+// games which aren't recorded. Failing to read them is not reported, since the records are read again, and any error
+// shown, once the game ends.
+func (m *Model) loadPace() tea.Cmd {
+	if m.recordKey == "" {
+		return nil
+	}
+	id, store, key := m.id, m.records, m.recordKey
+	return func() tea.Msg {
+		best, ok, err := store.Best(key)
+		if err != nil || !ok {
+			return nil
+		}
+		msg := paceMsg{id: id, best: best.Checkpoints, splits: best.BestSplits}
+		if n := len(best.History); n > 0 {
+			msg.last = best.History[n-1].Splits
+		}
+		return msg
+	}
+}
+
+// passCheckpoints adds a checkpoint for every records.CheckpointLines lines cleared since the last one.
+func (m *Model) passCheckpoints() {
+	for {
+		lines := uint(len(m.checkpoints)+1) * records.CheckpointLines
+		if lines > m.scoring.Lines() {
+			return
+		}
+		m.checkpoints = append(m.checkpoints, records.Checkpoint{
+			Lines: lines,
+			Time:  m.timer.Elapsed(),
+			Score: m.scoring.Total(),
+		})
+	}
+}
+
+// paceView compares the last checkpoint passed with the personal best's at the same number of lines, as a split timer
+// does: by the time taken in races, and by score otherwise. It is empty until the first checkpoint both have passed.
+func (m *Model) paceView() string {
+	i := min(len(m.checkpoints), len(m.pace)) - 1
+	if i < 0 {
+		return ""
+	}
+	current, best := m.checkpoints[i], m.pace[i]
+
+	var delta string
+	var ahead bool
+	if m.race {
+		ahead = current.Time <= best.Time
+		delta = fmt.Sprintf("%+.1fs", (current.Time - best.Time).Seconds())
+	} else {
+		ahead = current.Score >= best.Score
+		delta = fmt.Sprintf("%+d", int(current.Score)-int(best.Score))
+	}
+	style := m.styles.PaceBehind
+	if ahead {
+		style = m.styles.PaceAhead
+	}
+	return fmt.Sprintln(m.styles.text("Pace")+": ", style.Render(delta))
+}
+
+// splitsView lists the time taken for each section of the game between checkpoints, once it has ended, with the
+// differences from the personal best's and the last game's, and splits faster than any before highlighted in recorded
+// games. It is empty if no checkpoints were passed.
+func (m *Model) splitsView() string {
+	splits := records.Splits(m.checkpoints)
+	if len(splits) == 0 {
+		return ""
+	}
+	paceSplits := records.Splits(m.pace)
+
+	var output strings.Builder
+	output.WriteString(m.styles.text("Splits") + "\n")
+	if len(paceSplits) > 0 || len(m.lastSplits) > 0 {
+		output.WriteString(fmt.Sprintf("%13s %7s %7s\n", "", m.styles.text("PB"), m.styles.text("Last")))
+	}
+	for i := max(len(splits)-splitRows, 0); i < len(splits); i++ {
+		line := fmt.Sprintf("%4d %7.1fs", m.checkpoints[i].Lines, splits[i].Seconds())
+		for _, compared := range [][]time.Duration{paceSplits, m.lastSplits} {
+			if i < len(compared) {
+				line += fmt.Sprintf(" %+6.1fs", (splits[i] - compared[i]).Seconds())
+			} else {
+				line += strings.Repeat(" ", 8)
+			}
+		}
+		line = strings.TrimRight(line, " ")
+		if m.recordKey != "" && (i >= len(m.bestSplits) || splits[i] < m.bestSplits[i]) {
+			line = m.styles.NewBest.Render(line)
+		}
+		output.WriteString(line + "\n")
+	}
+	return m.styles.renderPanel(m.styles.Summary, output.String())
+}
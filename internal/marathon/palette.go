@@ -0,0 +1,67 @@
+package marathon
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+)
+
+const (
+	// paletteFade is how long the colours take to fade into those of the next level, once they shift.
+	paletteFade = time.Second
+	// paletteFadeSteps is how many times the styles are made again as the colours fade, rather than on every frame.
+	paletteFadeSteps = 10
+)
+
+// setTheme draws the game with the theme from now on, and at the normal size with it too if it is drawn larger for
+// low vision.
+func (m *Model) setTheme(t *theme.Theme) {
+	m.shown = t
+	m.styles = NewStyles(t)
+	m.compactStyles = nil
+	if t != nil && t.LowVision {
+		compact := *t
+		compact.LowVision = false
+		m.compactStyles = NewStyles(&compact)
+	}
+}
+
+// setPalette draws the game straight away in the colours of the level, if its theme shifts them every few levels.
+func (m *Model) setPalette(level uint) {
+	if m.levelTheme == nil {
+		return
+	}
+	m.paletteShift = theme.LevelShift(level)
+	m.palette, m.fadeFrom = m.levelTheme.AtLevel(level), nil
+	m.setTheme(m.palette)
+}
+
+// shiftPalette starts the colours fading into those of the level, if its theme shifts them every few levels and they
+// have yet to shift for it. It is called as the level increases.
+func (m *Model) shiftPalette(level uint) {
+	if m.levelTheme == nil || theme.LevelShift(level) == m.paletteShift {
+		return
+	}
+	m.paletteShift = theme.LevelShift(level)
+	// A fade already underway carries on from the colours drawn now.
+	m.fadeFrom, m.palette = m.shown, m.levelTheme.AtLevel(level)
+	m.fadeAt, m.fadeStep = m.timer.Elapsed(), 0
+}
+
+// fadePalette moves the colours on towards those of the last shift, while they fade.
+func (m *Model) fadePalette() {
+	if m.fadeFrom == nil {
+		return
+	}
+	step := min(int(float64(m.timer.Elapsed()-m.fadeAt)/float64(paletteFade)*paletteFadeSteps), paletteFadeSteps)
+	if step == m.fadeStep {
+		return
+	}
+	m.fadeStep = step
+	if step == paletteFadeSteps {
+		m.setTheme(m.palette)
+		m.fadeFrom = nil
+		return
+	}
+	m.setTheme(m.fadeFrom.Blend(m.palette, float64(step)/paletteFadeSteps))
+}
@@ -0,0 +1,104 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/bot"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// DefaultPCOpeners is how many perfect-clear openers are attempted in a game of the mode.
+const DefaultPCOpeners = 10
+
+// pcDeals is how many bags are dealt looking for an opener which can be cleared, before one that can't is played.
+const pcDeals = 20
+
+// PCSession counts the perfect-clear openers attempted and cleared over a session, across every game played in it.
+type PCSession struct {
+	Attempts uint
+	Cleared  uint
+}
+
+// Rate returns the percentage of the openers attempted in the session which were cleared.
+func (s *PCSession) Rate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Cleared) / float64(s.Attempts) * 100
+}
+
+// nextPC starts the next perfect-clear opener from an empty board, with nothing held and a fresh bag.
+func (m *Model) nextPC() {
+	m.matrix = tetris.NewMatrix(len(m.matrix[0]), m.matrix.VisibleRows())
+	m.bag = tetris.NewSeededBag(m.matrix, m.pcSeed())
+	m.held = tetris.NewHoldQueue(m.held.Slots)
+	m.canHold = true
+	m.pcPieces = 0
+}
+
+// pcBudget returns how many tetriminos an attempt has to clear the board with: enough to clear four lines.
+func (m *Model) pcBudget() uint {
+	return uint(len(m.matrix[0]))
+}
+
+// pcSeed returns the seed of a bag whose opener can be cleared by hard drops within the budget, as the bot finds by
+// searching every placement. Most can, so it gives up and takes the last seed tried after pcDeals bags.
+func (m *Model) pcSeed() int64 {
+	var seed int64
+	for i := 0; i < pcDeals; i++ {
+		seed = m.pcRandom.Int63()
+		bag := tetris.NewSeededBag(m.matrix, seed)
+		// A tetrimino more than the budget can be seen when one is held.
+		values := make([]byte, m.pcBudget()+1)
+		for j := range values {
+			values[j] = bag.Next().Value
+		}
+		if bot.PerfectClear(len(m.matrix[0]), values, m.hold != tetris.HoldOff) {
+			break
+		}
+	}
+	return seed
+}
+
+// placedPC counts the tetrimino which was just locked towards the perfect-clear opener being attempted. The attempt
+// is over once the board is clear or the budget is spent, and the game finishes if it was the last.
+func (m *Model) placedPC() {
+	m.pcPieces++
+	cleared := m.matrix.IsEmpty()
+	if !cleared && m.pcPieces < m.pcBudget() {
+		return
+	}
+
+	m.pcAttempts++
+	m.pcSession.Attempts++
+	outcome := "missed"
+	if cleared {
+		m.pcCleared++
+		m.pcSession.Cleared++
+		outcome = "perfect clear"
+	}
+	m.addPopup(strings.ToUpper(outcome))
+	m.logEvent("Opener %d %s", m.pcAttempts, outcome)
+	m.narrate("opener %s, %d of %d cleared", outcome, m.pcSession.Cleared, m.pcSession.Attempts)
+
+	if m.pcAttempts >= m.pcCount {
+		m.gameOver = true
+		m.completed = true
+		return
+	}
+	m.nextPC()
+}
+
+// pcView returns the lines the perfect-clear openers add to the information panel: the attempt being played, the
+// tetriminos left to clear the board with, and how many have been cleared so far in the game and the session.
+func (m *Model) pcView() string {
+	if m.pcRandom == nil {
+		return ""
+	}
+	output := fmt.Sprintf("%s: %d/%d\n", m.styles.text("Opener"), min(m.pcAttempts+1, m.pcCount), m.pcCount)
+	output += fmt.Sprintln(m.styles.text("Budget")+": ", m.pcBudget()-m.pcPieces)
+	output += fmt.Sprintln(m.styles.text("PCs")+": ", m.pcCleared)
+	output += fmt.Sprintf("%s: %.0f%%\n", m.styles.text("Success"), m.pcSession.Rate())
+	return output
+}
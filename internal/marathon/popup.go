@@ -0,0 +1,54 @@
+package marathon
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// popupDuration is how long each popup is shown.
+	popupDuration = time.Second
+	// popupFadeAfter is how long each popup is shown in full before it fades for the rest of popupDuration.
+	popupFadeAfter = 600 * time.Millisecond
+)
+
+// popup is text announcing a notable action, such as clearing a tetris or levelling up, shown beside the matrix for a
+// moment afterwards.
+type popup struct {
+	text  string
+	shown time.Duration // the time played when the action happened
+}
+
+// addPopup announces the action with the given text, in the language of the theme, beneath any popups still being
+// shown for earlier actions.
+func (m *Model) addPopup(text string) {
+	m.popups = append(m.popups, popup{text: m.styles.text(text), shown: m.timer.Elapsed()})
+}
+
+// expirePopups removes the popups which have been shown for popupDuration.
+func (m *Model) expirePopups() {
+	now := m.timer.Elapsed()
+	m.popups = slices.DeleteFunc(m.popups, func(p popup) bool {
+		return now-p.shown >= popupDuration
+	})
+}
+
+// popupsView shows the popups for recent actions in the order they happened, fading each once it has been shown for
+// popupFadeAfter. Time only passes while the game is being played, so the popups wait during interludes.
+func (m *Model) popupsView() string {
+	now := m.timer.Elapsed()
+	var lines []string
+	for _, p := range m.popups {
+		age := now - p.shown
+		switch {
+		case age >= popupDuration:
+			continue
+		case age >= popupFadeAfter:
+			lines = append(lines, m.styles.PopupFading.Render(p.text))
+		default:
+			lines = append(lines, m.styles.Popup.Render(p.text))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
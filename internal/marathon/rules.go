@@ -0,0 +1,55 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rules add the conditions and information of a mode defined outside this package, such as one registered with the
+// mode package, to a marathon game. The game is otherwise played as set by the rest of the input.
+type Rules struct {
+	// Name is the name of the mode, such as "Survival". Records for games played with the rules are kept under it.
+	Name string
+
+	// Won reports whether the game has been won, ending it as completed (nil to never win).
+	Won func(Results) bool
+	// Lost reports whether the game has been lost, ending it as if the player had topped out (nil to only lose by
+	// topping out).
+	Lost func(Results) bool
+	// HUD returns lines shown below the score while playing, such as progress towards the goal (nil for none).
+	HUD func(Results) string
+}
+
+// checkRules ends the game if the results so far meet the rules' condition for winning or losing.
+func (m *Model) checkRules() {
+	if m.rules == nil || m.gameOver {
+		return
+	}
+	results := m.Results()
+	switch {
+	case m.rules.Won != nil && m.rules.Won(results):
+		m.gameOver = true
+		m.completed = true
+	case m.rules.Lost != nil && m.rules.Lost(results):
+		m.gameOver = true
+	}
+}
+
+// rulesView returns the lines the rules add to the information panel, each ending in a newline.
+func (m *Model) rulesView() string {
+	if m.rules == nil || m.rules.HUD == nil {
+		return ""
+	}
+	output := m.rules.HUD(m.Results())
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return output
+}
+
+// rulesRecordMode returns the name of the mode records for games played with the rules are kept under, such as
+// "rules-survival-level-1".
+func rulesRecordMode(rules *Rules, level uint) string {
+	slug := strings.Join(strings.Fields(strings.ToLower(rules.Name)), "-")
+	return fmt.Sprintf("rules-%s-level-%d", slug, level)
+}
@@ -0,0 +1,141 @@
+package marathon
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Cursor cells mark the cell being edited in sandbox games, drawn differently depending on whether it is filled.
+const (
+	cursorEmpty  byte = '+'
+	cursorFilled byte = '#'
+)
+
+// togglePause stops or restarts gravity in sandbox games. The timer stops with it, so the tetrimino doesn't lock and
+// the next doesn't spawn until gravity is restarted.
+func (m *Model) togglePause() {
+	m.paused = !m.paused
+	if m.paused {
+		m.timer.Stop()
+	} else {
+		m.timer.Start()
+	}
+}
+
+// startEditing pauses gravity and shows the cursor, so that cells of the stack can be filled and cleared.
+func (m *Model) startEditing() {
+	m.editing = true
+	m.timer.Stop()
+	m.setEditKeys(true)
+}
+
+// stopEditing hides the cursor, restarting gravity unless the player had paused it.
+func (m *Model) stopEditing() {
+	m.editing = false
+	if !m.paused {
+		m.timer.Start()
+	}
+	m.setEditKeys(false)
+}
+
+// setEditKeys enables the keys that move the cursor and edit cells, only used while editing.
+func (m *Model) setEditKeys(enabled bool) {
+	for _, k := range []*key.Binding{&m.keys.CursorLeft, &m.keys.CursorRight, &m.keys.CursorUp, &m.keys.CursorDown, &m.keys.Paint} {
+		k.SetEnabled(enabled)
+	}
+}
+
+// updateEditing handles messages while the stack is being edited in sandbox games. Gameplay keys are ignored until
+// editing stops.
+func (m Model) updateEditing(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.active()
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, m.quit()
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		case key.Matches(msg, m.keys.Edit):
+			m.stopEditing()
+		case key.Matches(msg, m.keys.CursorLeft):
+			m.moveCursor(-1, 0)
+		case key.Matches(msg, m.keys.CursorRight):
+			m.moveCursor(1, 0)
+		case key.Matches(msg, m.keys.CursorUp):
+			m.moveCursor(0, -1)
+		case key.Matches(msg, m.keys.CursorDown):
+			m.moveCursor(0, 1)
+		case key.Matches(msg, m.keys.Paint):
+			err := m.paint()
+			if err != nil {
+				m.fail(fmt.Errorf("failed to edit cell: %w", err))
+				return m, m.endGame()
+			}
+		case key.Matches(msg, m.keys.Pick):
+			err := m.pickNext(msg)
+			if err != nil {
+				m.fail(fmt.Errorf("failed to pick next tetrimino: %w", err))
+				return m, m.endGame()
+			}
+		}
+	case frameMsg:
+		// Frames keep coming while editing, so that the game resumes as soon as editing stops.
+		if msg.id == m.id {
+			return m, frame(m.id)
+		}
+	}
+	return m, nil
+}
+
+// moveCursor moves the cursor by the given number of columns and rows, staying within the visible rows.
+func (m *Model) moveCursor(cols, rows int) {
+	top := m.matrix.BufferRows()
+	m.cursor.X = min(max(m.cursor.X+cols, 0), len(m.matrix[0])-1)
+	m.cursor.Y = min(max(m.cursor.Y+rows, top), len(m.matrix)-1)
+}
+
+// paint fills the cell under the cursor with garbage, or clears it if it is already filled. The current tetrimino
+// can't be painted over, since it is not part of the stack.
+func (m *Model) paint() error {
+	row, col := m.cursor.Y, m.cursor.X
+	if !m.entering() && m.occupies(row, col) {
+		return nil
+	}
+	value := tetris.GarbageValue
+	if !m.matrix.IsCellEmpty(row, col) {
+		value = 0
+	}
+	return m.matrix.SetCell(row, col, value)
+}
+
+// pickNext deals the tetrimino chosen with the number keys next, in the order of tetris.Tetriminos.
+func (m *Model) pickNext(msg tea.KeyMsg) error {
+	i := int(msg.String()[0] - '1')
+	if i < 0 || i >= len(tetris.Tetriminos) {
+		return nil
+	}
+	return m.bag.DealNext(tetris.Tetriminos[i].Value)
+}
+
+// addCursor marks the cell being edited.
+func (m *Model) addCursor(matrix tetris.Matrix) {
+	row, col := m.cursor.Y, m.cursor.X
+	if matrix.IsCellEmpty(row, col) {
+		matrix[row][col] = cursorEmpty
+	} else {
+		matrix[row][col] = cursorFilled
+	}
+}
+
+// pickerView lists the tetriminos which can be chosen to be dealt next, by their number keys.
+func (m *Model) pickerView() string {
+	output := "Pick next:\n"
+	for i, t := range tetris.Tetriminos {
+		output += fmt.Sprintf("%d %s %c\n", i+1, m.styles.renderCell(t.Value), t.Value)
+	}
+	return m.styles.renderPanel(m.styles.Picker, output)
+}
@@ -0,0 +1,93 @@
+package marathon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Region is the size of an area of the terminal, in columns and rows.
+type Region struct {
+	Width, Height int
+}
+
+// UnmarshalText parses the region from its width and height, such as "40x12".
+func (r *Region) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%dx%d", &r.Width, &r.Height)
+	if err != nil || r.Width <= 0 || r.Height <= 0 {
+		return fmt.Errorf("invalid region %q: must be a width and height, such as 40x12", text)
+	}
+	return nil
+}
+
+func (r Region) String() string {
+	return fmt.Sprintf("%dx%d", r.Width, r.Height)
+}
+
+// bigDigits are the digits of the stream layout's readouts, drawn three rows tall so they can be read on a stream.
+var bigDigits = [10][3]string{
+	{"█▀█", "█ █", "▀▀▀"},
+	{" █ ", " █ ", " ▀ "},
+	{"▀▀█", "█▀▀", "▀▀▀"},
+	{"▀▀█", " ▀█", "▀▀▀"},
+	{"█ █", "▀▀█", "  ▀"},
+	{"█▀▀", "▀▀█", "▀▀▀"},
+	{"█▀▀", "█▀█", "▀▀▀"},
+	{"▀▀█", "  █", "  ▀"},
+	{"█▀█", "█▀█", "▀▀▀"},
+	{"█▀█", "▀▀█", "▀▀▀"},
+}
+
+// bigNumber draws the number with bigDigits, or as it is if the glyphs are ASCII, which has no block characters.
+func (s *Styles) bigNumber(n uint) string {
+	digits := fmt.Sprint(n)
+	if !s.bigDigits {
+		return digits
+	}
+	var rows [3]strings.Builder
+	for i, d := range digits {
+		for row := range rows {
+			if i > 0 {
+				rows[row].WriteString(" ")
+			}
+			rows[row].WriteString(bigDigits[d-'0'][row])
+		}
+	}
+	return rows[0].String() + "\n" + rows[1].String() + "\n" + rows[2].String()
+}
+
+// streamView is the stream layout, which is easier to follow for people watching than it is to play with: the score,
+// level and lines are drawn large, and the history of the game is always listed beside the board, below the region
+// left blank for a webcam.
+func (m *Model) streamView() string {
+	readout := func(label string, value uint, style lipgloss.Style) string {
+		return m.styles.text(label) + "\n" + style.Render(m.styles.bigNumber(value)) + "\n"
+	}
+	level := m.styles.Readout
+	if m.celebrating() {
+		level = m.styles.NewBest
+	}
+	readouts := readout("Score", m.scoring.Total(), m.styles.Readout) +
+		readout("Level", m.scoring.Level(), level) +
+		readout("Cleared", m.scoring.Lines(), m.styles.Readout)
+	readouts += m.styles.text("Time") + ": " + m.timeLabel() + "\n"
+	if m.puzzle == nil {
+		readouts += m.styles.text("Seed") + ":\n" + fmt.Sprintln(m.seed)
+	}
+
+	// The rows of the digits must stay together, so the readouts aren't spaced out in low-vision mode.
+	left := m.styles.Readouts.Render(readouts)
+	if m.hold != tetris.HoldOff {
+		left = lipgloss.JoinVertical(lipgloss.Right, m.holdView(), left)
+	}
+	right := m.historyView()
+	if m.webcam.Width > 0 {
+		// The border is part of the region, so that an overlay can be sized to match it exactly.
+		webcam := m.styles.Webcam.Width(max(m.webcam.Width-2, 0)).Height(max(m.webcam.Height-2, 0)).Render("")
+		right = lipgloss.JoinVertical(lipgloss.Left, webcam, right)
+	}
+	board := m.queueRowView(m.matrixView())
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, board, m.bagView(), right) + "\n" + m.helpView()
+}
@@ -1,34 +1,355 @@
 package marathon
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/lipgloss"
+)
 
 type Styles struct {
 	Playfield       lipgloss.Style
+	PlayfieldDanger lipgloss.Style // the playfield once the stack is close to topping out
 	ColIndicator    lipgloss.Style
 	TetriminoStyles map[byte]lipgloss.Style
 	Hold            lipgloss.Style
 	Information     lipgloss.Style
 	RowIndicator    lipgloss.Style
 	Bag             lipgloss.Style
+	QueueRow        lipgloss.Style // the queue when it is drawn in a row above or below the playfield
+	GameOver        lipgloss.Style
+	Ghost           lipgloss.Style
+	HoldPreview     lipgloss.Style
+	Overlay         lipgloss.Style
+	Hint            lipgloss.Style
+	TooSmall        lipgloss.Style
+	GarbagePreview  lipgloss.Style
+	GarbageMeter    lipgloss.Style // the garbage meter beside the matrix, filled with incoming lines
+	ZoneMeter       lipgloss.Style // the zone meter beside the matrix, filled by clearing lines
+	Statistics      lipgloss.Style
+	Summary         lipgloss.Style
+	NewBest         lipgloss.Style
+	Popup           lipgloss.Style
+	PopupFading     lipgloss.Style
+	History         lipgloss.Style
+	Picker          lipgloss.Style
+	Cursor          lipgloss.Style
+	CheatSheet      lipgloss.Style // each group of bindings on the cheat sheet
+	PaceAhead       lipgloss.Style // the difference from the personal best, when ahead of it
+	PaceBehind      lipgloss.Style // the difference from the personal best, when behind it
+	Readouts        lipgloss.Style // the panel of large readouts in the stream layout
+	Readout         lipgloss.Style // each large score, level and lines in the stream layout
+	Webcam          lipgloss.Style // the region left blank for a webcam in the stream layout
+
+	glyphs     *glyphs
+	letters    bool            // whether filled cells show the value of their tetrimino
+	patterns   map[byte]string // the characters filled cells of each tetrimino are drawn with (nil for the glyphs)
+	lowVision  bool            // whether text panels are spaced out
+	bigDigits  bool            // whether the stream layout's readouts are drawn with block characters
+	rowNumbers bool            // whether rows are numbered beside the playfield
+	queue      string          // where the queue is drawn (see theme.QueueNames)
+	cellHeight int             // rows of text each cell is drawn with
+	locale     *locale.Locale
+
+	// cells are each kind of cell as drawn, styled once when the styles are created rather than every time a cell is
+	// drawn.
+	cells map[byte]string
+}
+
+// glyphs are the characters the game is drawn with. Cells are two characters wide, or four in low-vision mode.
+type glyphs struct {
+	empty     string // an empty cell of the playfield
+	grid      string // an empty cell of the playfield drawn with grid lines, along its bottom and right edges
+	filled    string // a cell occupied by a tetrimino or garbage
+	shadow    string // a cell of the ghost or hold preview
+	hole      string // a column where incoming garbage will have a hole
+	meter     string // a row of the garbage meter with incoming garbage
+	blank     string // a cell drawn without any marker, such as around tetriminos in previews
+	separator string // between hints in the help bar
+	times     string // a multiplier, such as for combos
+	dash      string // between a hint and its explanation
+	arrow     string // a change from one value to another
+}
+
+var (
+	unicodeGlyphs = glyphs{
+		empty:     "▕ ",
+		grid:      "▁▕",
+		filled:    "██",
+		shadow:    "░░",
+		hole:      "▀▀",
+		meter:     "█",
+		blank:     "  ",
+		separator: " • ",
+		times:     "×",
+		dash:      "—",
+		arrow:     "→",
+	}
+	asciiGlyphs = glyphs{
+		empty:     "| ",
+		grid:      "_|",
+		filled:    "[]",
+		shadow:    "..",
+		hole:      "^^",
+		meter:     "#",
+		blank:     "  ",
+		separator: " | ",
+		times:     "x",
+		dash:      "-",
+		arrow:     "->",
+	}
+)
+
+// skinned returns a copy of the glyphs with cells drawn with the skin's characters.
+func (g glyphs) skinned(skin *theme.Skin) *glyphs {
+	g.filled = skin.Filled
+	g.shadow = skin.Ghost
+	g.empty = skin.Empty
+	if skin.Grid != "" {
+		g.grid = skin.Grid
+	}
+	return &g
+}
+
+// withEmpty returns a copy of the glyphs with empty cells of the playfield drawn as the given characters.
+func (g glyphs) withEmpty(empty string) *glyphs {
+	g.empty = empty
+	return &g
 }
 
+// large returns a copy of the glyphs with cells four characters wide, for low-vision mode.
+func (g glyphs) large() *glyphs {
+	g.empty += strings.Repeat(" ", 2)
+	line, cross := []rune(g.grid)[0], []rune(g.grid)[1]
+	g.grid = strings.Repeat(string(line), 3) + string(cross)
+	g.filled += g.filled
+	g.shadow += g.shadow
+	g.hole += g.hole
+	g.meter += g.meter
+	g.blank += g.blank
+	return &g
+}
+
+// hiddenCell marks a locked cell which has faded from view in an invisible game, so it is drawn as if it was empty.
+const hiddenCell byte = '-'
+
 func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for drawing the game with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
 	s := Styles{
-		Playfield:    lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0),
-		ColIndicator: lipgloss.NewStyle().Foreground(lipgloss.Color("#303040")),
-		TetriminoStyles: map[byte]lipgloss.Style{
-			'I': lipgloss.NewStyle().Foreground(lipgloss.Color("#64C4EB")),
-			'O': lipgloss.NewStyle().Foreground(lipgloss.Color("#F1D448")),
-			'T': lipgloss.NewStyle().Foreground(lipgloss.Color("#A15398")),
-			'S': lipgloss.NewStyle().Foreground(lipgloss.Color("#64B452")),
-			'Z': lipgloss.NewStyle().Foreground(lipgloss.Color("#DC3A35")),
-			'J': lipgloss.NewStyle().Foreground(lipgloss.Color("#5C65A8")),
-			'L': lipgloss.NewStyle().Foreground(lipgloss.Color("#E07F3A")),
-		},
-		Hold:         lipgloss.NewStyle().Width(10).Height(5).Border(lipgloss.RoundedBorder(), true, false, true, true).Align(lipgloss.Center, lipgloss.Center),
-		Information:  lipgloss.NewStyle().Width(13).Align(lipgloss.Left, lipgloss.Top),
-		RowIndicator: lipgloss.NewStyle().Foreground(lipgloss.Color("#444049")).Align(lipgloss.Left).Padding(0, 1, 0),
-		Bag:          lipgloss.NewStyle().PaddingTop(1),
+		Playfield:       lipgloss.NewStyle().Border(t.Border).Padding(0),
+		PlayfieldDanger: lipgloss.NewStyle().Border(t.Border).BorderForeground(t.Danger).Padding(0),
+		ColIndicator:    lipgloss.NewStyle().Foreground(t.Grid),
+		TetriminoStyles: make(map[byte]lipgloss.Style, len(t.Tetriminos)),
+		Hold:            lipgloss.NewStyle().Width(10).Height(5).Border(t.Border, true, false, true, true).Align(lipgloss.Center, lipgloss.Center),
+		Information:     lipgloss.NewStyle().Width(13).Align(lipgloss.Left, lipgloss.Top),
+		RowIndicator:    lipgloss.NewStyle().Foreground(t.Muted).Align(lipgloss.Left).Padding(0, 1, 0),
+		Bag:             lipgloss.NewStyle().PaddingTop(1),
+		QueueRow:        lipgloss.NewStyle().Padding(0, 1),
+		GameOver:        lipgloss.NewStyle().Bold(true).Foreground(t.Danger).Padding(0, 2),
+		Ghost:           lipgloss.NewStyle().Foreground(t.Ghost),
+		HoldPreview:     lipgloss.NewStyle().Foreground(t.Muted),
+		Overlay:         lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		Hint:            lipgloss.NewStyle().Foreground(t.Subtle),
+		TooSmall:        lipgloss.NewStyle().Bold(true).Foreground(t.Text).Align(lipgloss.Center),
+		GarbagePreview:  lipgloss.NewStyle().Foreground(t.Tetriminos[tetris.GarbageValue]).Faint(true),
+		GarbageMeter:    lipgloss.NewStyle().Foreground(t.Danger),
+		ZoneMeter:       lipgloss.NewStyle().Foreground(t.Accent),
+		Statistics:      lipgloss.NewStyle().Width(14).PaddingTop(1).PaddingLeft(2),
+		Summary:         lipgloss.NewStyle().PaddingTop(1).PaddingLeft(2),
+		NewBest:         lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		Popup:           lipgloss.NewStyle().Width(13).Bold(true).Foreground(t.Accent),
+		PopupFading:     lipgloss.NewStyle().Width(13).Foreground(t.Subtle),
+		History:         lipgloss.NewStyle().Width(28).PaddingTop(1).PaddingLeft(2),
+		Picker:          lipgloss.NewStyle().PaddingTop(1),
+		Cursor:          lipgloss.NewStyle().Foreground(t.Accent),
+		CheatSheet:      lipgloss.NewStyle().Padding(1, 2),
+		PaceAhead:       lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		PaceBehind:      lipgloss.NewStyle().Foreground(t.Danger),
+		Readouts:        lipgloss.NewStyle().PaddingRight(2).Align(lipgloss.Left, lipgloss.Top),
+		Readout:         lipgloss.NewStyle().Bold(true).Foreground(t.Text),
+		Webcam:          lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Muted).MarginLeft(2).MarginTop(1),
+		glyphs:          &unicodeGlyphs,
+		bigDigits:       !t.ASCII,
+		cellHeight:      1,
+	}
+	if t.ASCII {
+		s.glyphs = &asciiGlyphs
+	}
+	if t.Skin != nil && (!t.ASCII || t.Skin.ASCII()) {
+		s.glyphs = s.glyphs.skinned(t.Skin)
+	}
+	switch t.Frame {
+	case theme.FrameWell:
+		s.Playfield = s.Playfield.Border(t.Border, false, true, true)
+		s.PlayfieldDanger = s.PlayfieldDanger.Border(t.Border, false, true, true)
+	case theme.FrameNone:
+		// Without a frame to turn red, a line above the playfield warns of danger instead, in the row left for it.
+		s.Playfield = lipgloss.NewStyle().PaddingTop(1)
+		s.PlayfieldDanger = lipgloss.NewStyle().Border(t.Border, true, false, false).BorderForeground(t.Danger)
+	}
+	s.queue = t.Queue
+	if s.queue == "" {
+		s.queue = theme.QueueSide
+	}
+	s.letters = t.Letters
+	s.rowNumbers = !t.HideIndicators
+	s.patterns = t.Patterns
+	s.locale = t.Locale
+	if t.LowVision {
+		s.lowVision = true
+		s.cellHeight = 2
+		s.glyphs = s.glyphs.large()
+		s.Hold = s.Hold.Width(18).Height(9)
+		s.Information = s.Information.Width(20).Bold(true)
+		s.Statistics = s.Statistics.Width(22).Bold(true)
+		s.Summary = s.Summary.Bold(true)
+		s.Popup = s.Popup.Width(20)
+		s.PopupFading = s.PopupFading.Width(20).Bold(true)
+		s.History = s.History.Width(36).Bold(true)
+		s.Hint = s.Hint.Bold(true)
+	}
+	// Grid lines take the place of the column markers, or the skin's empty cells, so they are drawn whether or not the
+	// indicators are shown.
+	if t.GridLines {
+		s.glyphs = s.glyphs.withEmpty(s.glyphs.grid)
+		s.ColIndicator = s.ColIndicator.Faint(true)
+	} else if t.HideIndicators {
+		s.glyphs = s.glyphs.withEmpty(s.glyphs.blank)
+	}
+	for value, colour := range t.Tetriminos {
+		// With letters the colours are reversed, so cells are still filled with the tetrimino's colour behind them.
+		s.TetriminoStyles[value] = lipgloss.NewStyle().Foreground(colour).Reverse(t.Letters)
+	}
+
+	s.cells = make(map[byte]string, len(s.TetriminoStyles)+6)
+	for _, cell := range []byte{0, hiddenCell, 'G', 'H', cursorEmpty, cursorFilled} {
+		s.cells[cell] = s.styleCell(cell)
+	}
+	for value := range s.TetriminoStyles {
+		s.cells[value] = s.styleCell(value)
 	}
 	return &s
 }
+
+// previewColumns and previewRows are the size, in cells, of the frames tetriminos are previewed in, which fits each of
+// them in the orientation it spawns in.
+const (
+	previewColumns = 4
+	previewRows    = 2
+)
+
+// renderPreview renders the tetrimino with the value in the orientation it spawns in, centred in a frame of the same
+// size whatever its shape, so that the hold and queue don't shift as the tetriminos in them change. Values which
+// aren't a tetrimino, such as that of an empty hold, render as an empty frame.
+func (s *Styles) renderPreview(value byte) string {
+	return s.renderShiftedPreview(value, 0)
+}
+
+// renderShiftedPreview renders the preview with the tetrimino moved right by the given number of columns, leaving out
+// the cells moved past the right edge, so that it can be slid into place.
+func (s *Styles) renderShiftedPreview(value byte, shift int) string {
+	var cells [][]bool
+	for _, t := range tetris.Tetriminos {
+		if t.Value == value {
+			cells = t.Cells
+			break
+		}
+	}
+
+	width := s.cellWidth()
+	top := (previewRows - len(cells)) / 2
+	var output, line strings.Builder
+	for row := 0; row < previewRows; row++ {
+		line.Reset()
+		if row < top || row-top >= len(cells) {
+			line.WriteString(strings.Repeat(" ", previewColumns*width))
+		} else {
+			// Tetriminos an odd number of cells wide are centred by half a cell on each side.
+			padding := (previewColumns - len(cells[row-top])) * width
+			used := min(padding/2+shift, previewColumns*width)
+			line.WriteString(strings.Repeat(" ", used))
+			for _, filled := range cells[row-top] {
+				if used+width > previewColumns*width {
+					break
+				}
+				if filled {
+					line.WriteString(s.renderCell(value))
+				} else {
+					line.WriteString(s.renderCell(1))
+				}
+				used += width
+			}
+			line.WriteString(strings.Repeat(" ", previewColumns*width-used))
+		}
+		line.WriteByte('\n')
+		for i := 0; i < s.cellHeight; i++ {
+			output.WriteString(line.String())
+		}
+	}
+	return output.String()
+}
+
+// text returns the English text in the language of the styles' theme.
+func (s *Styles) text(english string) string {
+	return s.locale.T(english)
+}
+
+// cellWidth returns the number of columns each cell is drawn with.
+func (s *Styles) cellWidth() int {
+	return len([]rune(s.glyphs.blank))
+}
+
+// renderPanel renders the text of a panel, such as the score, with a blank line between each line in low-vision mode.
+func (s *Styles) renderPanel(style lipgloss.Style, text string) string {
+	if s.lowVision {
+		text = strings.ReplaceAll(strings.TrimSuffix(text, "\n"), "\n", "\n\n")
+	}
+	return style.Render(text)
+}
+
+// renderCell returns the cell as drawn, such as a filled cell in the colour of its tetrimino.
+func (s *Styles) renderCell(cell byte) string {
+	if output, ok := s.cells[cell]; ok {
+		return output
+	}
+	return s.styleCell(cell)
+}
+
+// styleCell styles the glyphs of the cell.
+func (s *Styles) styleCell(cell byte) string {
+	switch cell {
+	case 0, hiddenCell:
+		return s.ColIndicator.Render(s.glyphs.empty)
+	case 1:
+		return s.TetriminoStyles[cell].Render(s.glyphs.blank)
+	case 'G':
+		return s.Ghost.Render(s.glyphs.shadow)
+	case 'H':
+		return s.HoldPreview.Render(s.glyphs.shadow)
+	case cursorEmpty:
+		return s.Cursor.Render(s.glyphs.shadow)
+	case cursorFilled:
+		return s.Cursor.Render(s.glyphs.filled)
+	default:
+		cellStyle, ok := s.TetriminoStyles[cell]
+		if ok && s.letters {
+			return cellStyle.Render(string(cell) + s.glyphs.blank[1:])
+		}
+		if pattern, found := s.patterns[cell]; ok && found {
+			return cellStyle.Render(strings.Repeat(pattern, s.cellWidth()/2))
+		}
+		if ok {
+			return cellStyle.Render(s.glyphs.filled)
+		}
+	}
+	return "??"
+}
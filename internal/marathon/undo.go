@@ -0,0 +1,107 @@
+package marathon
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// takeSnapshot keeps the state of the game as the current tetrimino spawns, to be undone to once it is placed. It
+// does nothing in games whose placements can't be undone.
+func (m *Model) takeSnapshot() {
+	if m.undo == nil || m.gameOver {
+		return
+	}
+
+	// The current tetrimino is kept separately, since it is not part of the stack.
+	matrix := m.matrix.Clone()
+	err := matrix.RemoveTetrimino(m.currentTet)
+	if err != nil {
+		m.fail(fmt.Errorf("failed to remove tetrimino from matrix: %w", err))
+		return
+	}
+	bag, err := m.bag.State()
+	if err != nil {
+		m.fail(fmt.Errorf("failed to save bag: %w", err))
+		return
+	}
+	m.spawned = &tetris.Snapshot{
+		Matrix:     matrix,
+		Current:    *m.currentTet.Copy(),
+		Hold:       m.held.Copy(),
+		CanHold:    m.canHold,
+		Bag:        bag,
+		Scoring:    m.scoring.State(),
+		Attack:     m.attack.State(),
+		Statistics: m.stats.State(),
+		Time:       m.timer.Elapsed(),
+	}
+}
+
+// undoPlacement restores the game to when the last tetrimino placed spawned, taking back its placement and any lines
+// it cleared. The time played carries on, and nothing happens if there is no placement left to undo.
+func (m *Model) undoPlacement() error {
+	s, ok := m.undo.Pop()
+	if !ok {
+		return nil
+	}
+	err := m.restoreSnapshot(s)
+	if err != nil {
+		return err
+	}
+	m.logEvent("Undid %c", s.Current.Value)
+	return nil
+}
+
+// rewind restores the game to how it was rewindLength of play ago, as the tetrimino then in play spawned, taking back
+// every placement since. At least the last placement is taken back. The time played carries on, and nothing happens if
+// there is no placement left to take back.
+func (m *Model) rewind() error {
+	s, ok := m.undo.Rewind(m.timer.Elapsed() - rewindLength)
+	if !ok {
+		return nil
+	}
+	err := m.restoreSnapshot(s)
+	if err != nil {
+		return err
+	}
+	m.logEvent("Rewound to %c", s.Current.Value)
+	return nil
+}
+
+// restoreSnapshot restores the game to the snapshot taken as a tetrimino spawned.
+func (m *Model) restoreSnapshot(s tetris.Snapshot) error {
+	bag, err := tetris.RestoreBag(s.Matrix, s.Bag)
+	if err != nil {
+		return fmt.Errorf("failed to restore bag: %w", err)
+	}
+	stats, err := tetris.RestoreStatistics(s.Statistics)
+	if err != nil {
+		return fmt.Errorf("failed to restore statistics: %w", err)
+	}
+
+	m.matrix = s.Matrix.Clone()
+	m.currentTet, m.held = s.Current.Copy(), s.Hold.Copy()
+	err = m.matrix.AddTetrimino(m.currentTet)
+	if err != nil {
+		return fmt.Errorf("failed to add tetrimino to matrix: %w", err)
+	}
+	m.canHold = s.CanHold
+	m.bag = bag
+	m.scoring = tetris.RestoreScoring(s.Scoring)
+	m.lockedScore = m.scoring.Total()
+	m.attack = tetris.RestoreAttack(s.Attack)
+	m.stats = stats
+	m.spawned = &s
+	m.checkpoints = m.checkpoints[:min(len(m.checkpoints), int(m.scoring.Lines()/records.CheckpointLines))]
+
+	// The tetrimino is back where it spawned, so anything waiting for the last one to lock or spawn is forgotten.
+	m.entryDelay.Stop()
+	m.initialInputs.Take()
+	m.gravity.SetLevel(m.scoring.Level())
+	m.setPalette(m.scoring.Level())
+	m.gravity.Reset()
+	m.resetLockDelay()
+	return nil
+}
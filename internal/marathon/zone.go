@@ -0,0 +1,192 @@
+package marathon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+const (
+	// zoneQuarterLines is the lines cleared to fill a quarter of the zone meter, the least which can be spent.
+	zoneQuarterLines = 8
+	// zoneMeterLines is the lines cleared to fill the zone meter.
+	zoneMeterLines = 4 * zoneQuarterLines
+	// zoneQuarterTime is how long the zone lasts for each quarter of the meter spent.
+	zoneQuarterTime = 5 * time.Second
+)
+
+// zone is the meter of a zone game, and the zone once it is entered. Time stops in the zone, so it is timed on a
+// stopwatch of its own while the game's is stopped.
+type zone struct {
+	meter int           // lines charged, up to zoneMeterLines
+	time  *tetris.Delay // running while in the zone
+	lines int           // lines cleared in the zone, stacked at the bottom of the matrix
+}
+
+func newZone(clock tetris.Clock) *zone {
+	stopwatch := tetris.NewStopwatch(clock)
+	stopwatch.Start()
+	return &zone{time: tetris.NewDelay(stopwatch, 0)}
+}
+
+// active reports whether the zone has been entered, and not yet ended.
+func (z *zone) active() bool {
+	return z != nil && z.time.Running()
+}
+
+// ready reports whether enough of the meter is charged to enter the zone.
+func (z *zone) ready() bool {
+	return z != nil && !z.active() && z.meter >= zoneQuarterLines
+}
+
+// charge fills the meter with the lines cleared outside the zone.
+func (z *zone) charge(lines int) {
+	if z != nil && !z.active() {
+		z.meter = min(z.meter+lines, zoneMeterLines)
+	}
+}
+
+// enterZone spends every full quarter of the meter to enter the zone, stopping time for as long as they last.
+func (m *Model) enterZone() {
+	if !m.zone.ready() || m.entering() || m.gameOver {
+		return
+	}
+	quarters := m.zone.meter / zoneQuarterLines
+	m.zone.meter = 0
+	m.zone.time.SetLength(time.Duration(quarters) * zoneQuarterTime)
+	m.zone.time.Start()
+	m.timer.Stop()
+	m.addPopup("ZONE")
+	m.logEvent("Entered the zone for %s", m.zone.time.Length())
+	m.narrate("zone entered for %d seconds, time stops", int(m.zone.time.Length().Seconds()))
+}
+
+// updateZone ends the zone once its time is up. Until then, it keeps the game's time stopped, even if something such
+// as closing the cheat sheet started it again.
+func (m *Model) updateZone() {
+	if !m.zone.active() {
+		return
+	}
+	if !m.zone.time.Expired() {
+		m.timer.Stop()
+		return
+	}
+	m.zone.time.Stop()
+	m.endZone()
+	if !m.gameOver {
+		m.timer.Start()
+	}
+}
+
+// stackZoneLines moves the rows completed by the tetrimino in the zone to the bottom of the matrix, on top of those
+// stacked before them, rather than removing them. It returns how many were stacked.
+func (m *Model) stackZoneLines(t *tetris.Tetrimino) int {
+	stacked := len(m.matrix) - m.zone.lines
+	var completed, rest tetris.Matrix
+	for _, row := range m.matrix.CompletedLines(t) {
+		// Rows already stacked are complete too, and may be spanned by the tetrimino's cells.
+		if row < stacked {
+			completed = append(completed, m.matrix[row])
+		}
+	}
+	if len(completed) == 0 {
+		return 0
+	}
+	for row := 0; row < stacked; row++ {
+		if !containsRow(completed, m.matrix[row]) {
+			rest = append(rest, m.matrix[row])
+		}
+	}
+	copy(m.matrix, append(append(rest, completed...), m.matrix[stacked:]...))
+	m.zone.lines += len(completed)
+	return len(completed)
+}
+
+// containsRow reports whether the row is one of the rows, as the same slice rather than equal cells.
+func containsRow(rows tetris.Matrix, row []byte) bool {
+	for _, r := range rows {
+		if &r[0] == &row[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// endZone clears the lines stacked in the zone all at once, scoring them together.
+func (m *Model) endZone() {
+	lines := m.zone.lines
+	m.zone.lines = 0
+	if lines == 0 {
+		m.logEvent("Left the zone")
+		return
+	}
+	// The current tetrimino falls with the stack, so that it keeps clear of it.
+	falling := !m.entering() && !m.gameOver
+	if falling {
+		err := m.matrix.RemoveTetrimino(m.currentTet)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to remove tetrimino: %w", err))
+			return
+		}
+	}
+	// The stacked rows are reused as empty rows at the top, as Matrix.RemoveCompletedLines does.
+	stacked := append(tetris.Matrix{}, m.matrix[len(m.matrix)-lines:]...)
+	copy(m.matrix[lines:], m.matrix[:len(m.matrix)-lines])
+	for i, row := range stacked {
+		clear(row)
+		m.matrix[i] = row
+	}
+	if m.fade != nil {
+		m.fade.Reset()
+	}
+	if falling {
+		m.currentTet.Pos.Y += lines
+		err := m.matrix.AddTetrimino(m.currentTet)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to add tetrimino: %w", err))
+			return
+		}
+	}
+
+	points := m.scoring.AddZone(uint(lines))
+	m.lockedScore = m.scoring.Total()
+	m.addPopup(fmt.Sprintf("%d %s", lines, m.styles.text("LINES")))
+	m.logEvent("Left the zone with %d lines for %d points", lines, points)
+	m.narrate("zone ended, %d lines cleared for %d points, score %d", lines, points, m.scoring.Total())
+	m.events.Clear(m.timer.Elapsed(), "ZONE", uint(lines), m.scoring.Total())
+	if change, levelUp := m.scoring.LevelChanged(); levelUp {
+		m.gravity.SetLevel(change.To)
+		m.audio.Play(audio.LevelUp)
+		m.celebrateLevelUp(change.To)
+	} else {
+		m.audio.Play(audio.Clear)
+	}
+	if m.lineGoal > 0 && m.scoring.Lines() >= m.lineGoal {
+		m.gameOver = true
+		m.completed = true
+	}
+}
+
+// zoneMeter draws a bar beside the matrix which fills as the zone meter is charged, and empties as the zone runs out.
+func (m *Model) zoneMeter() string {
+	visible := m.matrix.VisibleRows()
+	var filled int
+	if m.zone.active() {
+		if length := m.zone.time.Length(); length > 0 {
+			filled = int(float64(visible) * m.zone.time.Remaining().Seconds() / length.Seconds())
+		}
+	} else {
+		filled = visible * m.zone.meter / zoneMeterLines
+	}
+	return m.meterView(m.styles.ZoneMeter, filled)
+}
+
+// zoneView describes the zone meter in the information panel, or the zone while it lasts.
+func (m *Model) zoneView() string {
+	if m.zone.active() {
+		return fmt.Sprintf("%s: %.0fs, %d\n", m.styles.text("Zone"), m.zone.time.Remaining().Seconds(), m.zone.lines)
+	}
+	return fmt.Sprintf("%s: %d%%\n", m.styles.text("Zone"), 100*m.zone.meter/zoneMeterLines)
+}
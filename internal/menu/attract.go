@@ -0,0 +1,104 @@
+package menu
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// attractDelay is how long the menu waits without input before playing a demo, like an arcade's attract screen.
+const attractDelay = 30 * time.Second
+
+// promptHeight is the number of lines the prompt above the demo takes up.
+const promptHeight = 2
+
+// idleMsg is sent attractDelay after each input, when the menu may have gone idle.
+type idleMsg struct{}
+
+// ansiCodes matches the escape codes styling text in the terminal.
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// waitForIdle restarts the wait for the menu to go idle.
+func (m *Model) waitForIdle() tea.Cmd {
+	m.lastInput = time.Now()
+	return checkIdle()
+}
+
+// checkIdle checks whether the menu has gone idle once attractDelay has passed.
+func checkIdle() tea.Cmd {
+	return tea.Tick(attractDelay, func(time.Time) tea.Msg { return idleMsg{} })
+}
+
+// idle reports whether the menu has gone attractDelay without input. Input since an idleMsg was scheduled means
+// another is on the way.
+func (m *Model) idle() bool {
+	return time.Since(m.lastInput) >= attractDelay
+}
+
+// startDemo starts a game played by the bot in place of the menu, with a fresh seed each time.
+func (m *Model) startDemo() tea.Cmd {
+	demo := marathon.NewModel(&marathon.Input{
+		Level:  1,
+		Bot:    true,
+		Theme:  m.theme(),
+		Width:  m.width,
+		Height: m.height,
+	})
+	m.mode = modeAttract
+	m.demo, m.demoID = demo, demo.ID()
+	cmd := demo.Init()
+	// As with games, the demo only learns the size of the terminal from a resize, so pass on the last one.
+	if m.windowSize != nil {
+		size := m.demoSize(*m.windowSize)
+		cmd = tea.Batch(cmd, func() tea.Msg { return size })
+	}
+	return cmd
+}
+
+// demoSize returns the size of the terminal left for the demo beneath the prompt.
+func (m *Model) demoSize(size tea.WindowSizeMsg) tea.WindowSizeMsg {
+	size.Height = max(size.Height-promptHeight, 0)
+	return size
+}
+
+// updateAttract passes messages on to the demo until a key is pressed or the mouse clicked, which goes back to the
+// menu. A demo which tops out is followed by another.
+func (m Model) updateAttract(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		msg = m.demoSize(size)
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.stopDemo()
+	case tea.MouseMsg:
+		if msg.Type != tea.MouseMotion {
+			return m.stopDemo()
+		}
+		return m, nil
+	case marathon.GameOverMsg:
+		if msg.ID == m.demoID {
+			return m, m.startDemo()
+		}
+	}
+	var cmd tea.Cmd
+	m.demo, cmd = m.demo.Update(msg)
+	return m, cmd
+}
+
+// stopDemo goes back to the menu from the demo.
+func (m Model) stopDemo() (tea.Model, tea.Cmd) {
+	m.mode = modeMenu
+	m.demo = nil
+	return m, m.waitForIdle()
+}
+
+// attractView draws the demo dimmed, with its colours taken out, beneath a prompt to press any key.
+func (m *Model) attractView() string {
+	demo := ansiCodes.ReplaceAllString(m.demo.View(), "")
+	prompt := m.styles.heading.Render(strings.ToUpper(m.styles.text("Press any key")))
+	return lipgloss.JoinVertical(lipgloss.Center, prompt, "", m.styles.dimmed.Render(demo))
+}
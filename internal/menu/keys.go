@@ -10,6 +10,7 @@ type KeyMap struct {
 	Up    key.Binding
 	Down  key.Binding
 	Start key.Binding
+	Stats key.Binding
 }
 
 func DefaultKeyMap() *KeyMap {
@@ -21,6 +22,7 @@ func DefaultKeyMap() *KeyMap {
 		Up:    key.NewBinding(key.WithKeys("i", "w", "up"), key.WithHelp("w, i, right", "move up")),
 		Down:  key.NewBinding(key.WithKeys("k", "s", "down"), key.WithHelp("s, k, down", "move down")),
 		Start: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "start game")),
+		Stats: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "lifetime stats")),
 	}
 }
 
@@ -43,6 +45,7 @@ func (k *KeyMap) FullHelp() [][]key.Binding {
 			k.Up,
 			k.Down,
 			k.Start,
+			k.Stats,
 		},
 	}
 }
@@ -2,8 +2,22 @@ package menu
 
 import (
 	"fmt"
+	"io"
+	"slices"
+	"time"
 
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/controls"
+	"github.com/Broderick-Westrope/tetrigo/internal/leaderboard"
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
 	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/mode"
+	"github.com/Broderick-Westrope/tetrigo/internal/preset"
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/internal/save"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,6 +27,8 @@ import (
 const (
 	modeMenu = iota
 	modeGame
+	modeStats
+	modeAttract
 )
 
 type option interface{}
@@ -28,53 +44,464 @@ type Model struct {
 	settingIndex int
 	game         tea.Model
 	mode         int
+	player       string
+	windowSize   *tea.WindowSizeMsg // the last size of the terminal, or nil if it is not known yet
+	countdown    uint
+	interludes   bool
+	ascii        bool   // whether games are drawn using only ASCII characters
+	letters      bool   // whether cells are marked with the letter of their tetrimino
+	frame        string // what is drawn around the playfield, or empty for a box
+	queue        string // where the next tetriminos are shown, or empty beside the playfield
+	gridLines    bool   // whether lines are drawn between the cells of the playfield
+	indicators   bool   // whether rows are numbered and columns marked
+	levelColours bool   // whether the colours of games shift every few levels
+	lowVision    bool   // whether games are drawn at a larger size with more contrast
+	screenReader bool   // whether games are described in text for screen readers
+	dangerRow    int    // the row the stack reaching warns of topping out (0 to never warn)
+	locale       *locale.Locale
+	speed        float64
+	handling     tetris.Handling
+	keyPreset    string // the name of the preset of keys gameplay is bound to
+	idleTimeout  time.Duration
+	width        int // the size of the matrix for modes played on an empty board, in columns and visible rows
+	height       int
+	randomizer   string // the name of the randomizer dealing tetriminos in modes without one of their own
+	attack       *tetris.AttackTable
+	savePath     string
+	autosavePath string
+	profile      string                         // the profile being played, or empty for the default profile
+	switchTo     func(profile string) tea.Model // creates the menu of another profile (nil when profiles can't be switched)
+	records      *records.Store
+	leaderboard  *leaderboard.Client
+	audio        *audio.Player
+	clipboard    io.Writer
+	pcSession    *marathon.PCSession // the perfect-clear openers attempted in every game started from the menu
+
+	// err is why the last game failed to start, shown until the next key is pressed (nil otherwise).
+	err error
+
+	// lifetime is the player's lifetime stats as they were when the stats screen was opened, or statsErr why they
+	// couldn't be read.
+	lifetime records.Lifetime
+	statsErr error
+
+	// demo is the game the bot plays once the menu is idle (nil otherwise), and demoID its ID. lastInput is when
+	// the menu last had input, or was opened.
+	demo      tea.Model
+	demoID    int
+	lastInput time.Time
 
 	keys   *KeyMap
 	styles *Styles
 	help   help.Model
 }
 
-func InitialModel() *Model {
+type Input struct {
+	Config  *config.Config
+	Player  string         // name the player's records are kept under (empty for local play)
+	Records *records.Store // the player's best results (nil to not keep records)
+	Audio   *audio.Player  // plays the sounds of each game (nil for silence)
+
+	// Clipboard is the terminal share text is copied to at the end of games (nil for standard output).
+	Clipboard io.Writer
+
+	// SavePath is the file marathon games are suspended to, and continued from (empty to not allow suspending).
+	SavePath string
+	// AutosavePath is the file marathon games are snapshotted to while they are played, and restored from if tetrigo
+	// exited before the game ended (empty to not take snapshots).
+	AutosavePath string
+
+	// Leaderboard is where the results of recorded games are submitted (nil to not submit them).
+	Leaderboard *leaderboard.Client
+
+	// IdleTimeout pauses games once they have gone this long without input, such as for SSH sessions (0 to never
+	// pause).
+	IdleTimeout time.Duration
+
+	// Profile is the profile being played (empty for the default profile), and Profiles are the others that can be
+	// switched to from the menu, which creates the menu for the chosen profile with SwitchProfile.
+	Profile       string
+	Profiles      []string
+	SwitchProfile func(profile string) tea.Model
+}
+
+func NewModel(in *Input) *Model {
+	levels, levelIndex := levelOptions(in.Config.Level)
+	themes, themeIndex := themeOptions(in.Config.ThemeName)
+	skins, skinIndex := skinOptions(in.Config.Skin)
+	softDrops, softDropIndex := softDropOptions(in.Config.Handling())
+	lockDowns := []option{tetris.LockDownExtended, tetris.LockDownInfinite, tetris.LockDownClassic}
+	lockDownIndex := slices.Index(lockDowns, option(in.Config.Handling().LockDown))
+	keyPresets, keyPresetIndex := keyPresetOptions(in.Config.Keys)
+	holdPreviewIndex := 0
+	if in.Config.HoldPreview {
+		holdPreviewIndex = 1
+	}
+
 	m := Model{
 		settings: []setting{
 			{
 				name:    "Level",
-				options: []option{1, 5, 10, 15},
-				index:   0,
+				options: levels,
+				index:   levelIndex,
 			},
 			{
 				name:    "Players",
 				options: []option{uint(1)},
 				index:   0,
 			},
+			{
+				name:    "Hold Preview",
+				options: []option{"Off", "On"},
+				index:   holdPreviewIndex,
+			},
+			{
+				name:    "Hold",
+				options: []option{tetris.HoldOnce, tetris.HoldOff, tetris.HoldUnlimited},
+				index:   0,
+			},
+			{
+				name:    "Board",
+				options: boardOptions(),
+				index:   0,
+			},
 			{
 				name:    "Mode",
-				options: []option{"Marathon"},
+				options: modeOptions(in.SavePath, in.AutosavePath),
 				index:   0,
 			},
+			{
+				name:    "Theme",
+				options: themes,
+				index:   themeIndex,
+			},
+			{
+				name:    "Skin",
+				options: skins,
+				index:   skinIndex,
+			},
+			{
+				name:    "Soft Drop",
+				options: softDrops,
+				index:   softDropIndex,
+			},
+			{
+				name:    "Lock Down",
+				options: lockDowns,
+				index:   max(lockDownIndex, 0),
+			},
+			{
+				name:    "Keys",
+				options: keyPresets,
+				index:   keyPresetIndex,
+			},
 		},
 		settingIndex: 0,
 		keys:         DefaultKeyMap(),
-		styles:       DefaultStyles(),
+		styles:       NewStyles(in.Config.Theme()),
 		mode:         modeMenu,
-		help:         help.New(),
+		player:       in.Player,
+		countdown:    in.Config.Countdown,
+		interludes:   in.Config.Interludes,
+		ascii:        in.Config.ASCII,
+		letters:      in.Config.Letters,
+		frame:        in.Config.Frame,
+		queue:        in.Config.Queue,
+		gridLines:    in.Config.GridLines,
+		indicators:   in.Config.Indicators,
+		levelColours: in.Config.LevelColours,
+		lowVision:    in.Config.LowVision,
+		screenReader: in.Config.ScreenReader,
+		dangerRow:    int(in.Config.DangerRow),
+		locale:       in.Config.Theme().Locale,
+		speed:        in.Config.Speed,
+		handling:     in.Config.Handling(),
+		width:        int(in.Config.Width),
+		height:       int(in.Config.Height),
+		randomizer:   in.Config.Randomizer,
+		attack:       in.Config.AttackTable(),
+		savePath:     in.SavePath,
+		autosavePath: in.AutosavePath,
+		idleTimeout:  in.IdleTimeout,
+		profile:      in.Profile,
+		records:      in.Records,
+		leaderboard:  in.Leaderboard,
+		audio:        in.Audio,
+		clipboard:    in.Clipboard,
+		pcSession:    &marathon.PCSession{},
+		help:         theme.NewHelp(in.Config.Theme()),
+		lastInput:    time.Now(),
+	}
+	if in.SwitchProfile != nil {
+		profiles, profileIndex := profileOptions(in.Profile, in.Profiles)
+		m.settings = append(m.settings, setting{name: "Profile", options: profiles, index: profileIndex})
+		m.switchTo = in.SwitchProfile
 	}
 	return &m
 }
 
+// defaultProfile is the option for the default profile, which has no name.
+const defaultProfile = "Default"
+
+// profileOptions returns the profiles that can be switched to, starting with the default profile and including the
+// current profile, and the index of the current profile.
+func profileOptions(current string, names []string) ([]option, int) {
+	if current != "" && !slices.Contains(names, current) {
+		names = append(slices.Clone(names), current)
+		slices.Sort(names)
+	}
+
+	options := []option{defaultProfile}
+	for _, n := range names {
+		options = append(options, n)
+	}
+	if current == "" {
+		return options, 0
+	}
+	return options, slices.Index(options, option(current))
+}
+
+// switchProfile creates the menu of the selected profile if it isn't the one being played.
+func (m *Model) switchProfile() (tea.Model, tea.Cmd, bool) {
+	s := m.settings[m.settingIndex]
+	if s.name != "Profile" || m.switchTo == nil {
+		return nil, nil, false
+	}
+	name := s.options[s.index].(string)
+	if name == defaultProfile {
+		name = ""
+	}
+	if name == m.profile {
+		return nil, nil, false
+	}
+
+	next := m.switchTo(name)
+	cmd := next.Init()
+	// As with games, the new menu only learns the size of the terminal from a resize, so pass on the last one.
+	if m.windowSize != nil {
+		size := *m.windowSize
+		cmd = tea.Batch(cmd, func() tea.Msg { return size })
+	}
+	return next, cmd, true
+}
+
+// levelOptions returns the levels that can be chosen, including the default level, and the index of the default level.
+func levelOptions(defaultLevel uint) ([]option, int) {
+	levels := []int{1, 5, 10, 15}
+	if !slices.Contains(levels, int(defaultLevel)) {
+		levels = append(levels, int(defaultLevel))
+		slices.Sort(levels)
+	}
+
+	options := make([]option, len(levels))
+	for i, l := range levels {
+		options[i] = l
+	}
+	return options, slices.Index(levels, int(defaultLevel))
+}
+
+// softDrop is a soft drop speed which can be chosen.
+type softDrop struct {
+	factor uint // how many times faster tetriminos fall
+	sonic  bool // whether tetriminos drop all the way at once instead
+}
+
+func (s softDrop) String() string {
+	if s.sonic {
+		return "Sonic"
+	}
+	return fmt.Sprintf("%dx", s.factor)
+}
+
+// softDropOptions returns the soft drop speeds that can be chosen, including the configured speed, and the index of
+// the configured speed.
+func softDropOptions(h tetris.Handling) ([]option, int) {
+	factors := []uint{6, 10, 20, 40}
+	configured := h.SoftDrop
+	if configured == 0 {
+		configured = tetris.DefaultSoftDropFactor
+	}
+	if !slices.Contains(factors, configured) {
+		factors = append(factors, configured)
+		slices.Sort(factors)
+	}
+
+	options := make([]option, 0, len(factors)+1)
+	for _, f := range factors {
+		options = append(options, softDrop{factor: f})
+	}
+	options = append(options, softDrop{sonic: true})
+	if h.SonicDrop {
+		return options, len(options) - 1
+	}
+	return options, slices.Index(factors, configured)
+}
+
+// modeOptions returns the modes that can be chosen, starting with restoring the game tetrigo exited during and
+// continuing the suspended game, if there are any.
+func modeOptions(savePath, autosavePath string) []option {
+	var options []option
+	if autosavePath != "" && save.Exists(autosavePath) {
+		options = append(options, "Restore")
+	}
+	if savePath != "" && save.Exists(savePath) {
+		options = append(options, "Continue")
+	}
+	for _, name := range mode.Names() {
+		options = append(options, name)
+	}
+	return options
+}
+
+// modeDescription returns the description of the selected mode, or an empty string if it has none.
+func (m *Model) modeDescription() string {
+	for _, s := range m.settings {
+		if s.name != "Mode" {
+			continue
+		}
+		if s.options[s.index] == "Restore" {
+			return m.styles.text("Restore the game in progress when tetrigo last exited, from its last autosave.")
+		}
+		if gameMode := mode.Get(fmt.Sprint(s.options[s.index])); gameMode != nil {
+			return gameMode.Description()
+		}
+	}
+	return ""
+}
+
+// refreshModes updates the modes that can be chosen once a game has been suspended or continued, keeping the same
+// mode selected if it is still there.
+func (m *Model) refreshModes() {
+	for i := range m.settings {
+		s := &m.settings[i]
+		if s.name != "Mode" {
+			continue
+		}
+		selected := s.options[s.index]
+		s.options = modeOptions(m.savePath, m.autosavePath)
+		s.index = max(slices.Index(s.options, selected), 0)
+	}
+}
+
+// boardOptions returns the boards a game can start from: an empty board followed by each preset.
+func boardOptions() []option {
+	options := []option{"Empty"}
+	for _, name := range preset.Names() {
+		options = append(options, name)
+	}
+	return options
+}
+
+// themeOptions returns the names of the themes that can be chosen and the index of the named theme.
+func themeOptions(name string) ([]option, int) {
+	names := theme.Names()
+	options := make([]option, len(names))
+	for i, n := range names {
+		options[i] = n
+	}
+	return options, max(slices.Index(names, name), 0)
+}
+
+// skinOptions returns the names of the skins that can be chosen and the index of the named skin (the default if there
+// is no such skin).
+func skinOptions(name string) ([]option, int) {
+	names := theme.SkinNames()
+	options := make([]option, len(names))
+	for i, n := range names {
+		options[i] = n
+	}
+	return options, max(slices.Index(names, name), 0)
+}
+
+// keyPresetOptions returns the names of the key presets that can be chosen and the index of the named preset (the
+// default if there is no such preset).
+func keyPresetOptions(name string) ([]option, int) {
+	names := controls.Names()
+	options := make([]option, len(names))
+	for i, n := range names {
+		options[i] = n
+	}
+	return options, max(slices.Index(names, name), 0)
+}
+
+// theme returns the currently selected theme.
+func (m *Model) theme() *theme.Theme {
+	t := theme.Default()
+	var skin *theme.Skin
+	for _, setting := range m.settings {
+		switch setting.name {
+		case "Theme":
+			selected, err := theme.Get(setting.options[setting.index].(string))
+			if err == nil {
+				t = selected
+			}
+		case "Skin":
+			selected, err := theme.GetSkin(setting.options[setting.index].(string))
+			if err == nil {
+				skin = selected
+			}
+		}
+	}
+	if m.ascii {
+		t = t.WithASCII()
+	}
+	if skin != nil {
+		t = t.WithSkin(skin)
+	}
+	if m.frame != "" {
+		t = t.WithFrame(m.frame)
+	}
+	if m.queue != "" {
+		t = t.WithQueue(m.queue)
+	}
+	if m.gridLines {
+		t = t.WithGridLines()
+	}
+	if !m.indicators {
+		t = t.WithoutIndicators()
+	}
+	if m.levelColours {
+		t = t.WithLevelColours()
+	}
+	if m.letters {
+		t = t.WithLetters()
+	}
+	if m.lowVision {
+		t = t.WithLowVision()
+	}
+	if m.screenReader {
+		t = t.WithScreenReader()
+	}
+	if m.dangerRow > 0 {
+		t = t.WithDangerRow(m.dangerRow)
+	}
+	return t.WithLocale(m.locale)
+}
+
 func (m Model) Init() tea.Cmd {
-	return nil
+	return checkIdle()
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowSize = &msg
+	}
+
 	if m.mode == modeGame {
 		switch msg := msg.(type) {
+		case marathon.SuspendedMsg:
+			m.mode = modeMenu
+			m.game = nil
+			m.refreshModes()
+			return m, m.waitForIdle()
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, m.keys.Quit):
+				m.abandonGame()
 				m.mode = modeMenu
 				m.game = nil
-				return m, nil
+				m.refreshModes()
+				return m, m.waitForIdle()
 			}
 		}
 		var cmd tea.Cmd
@@ -82,58 +509,143 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.mode == modeAttract {
+		return m.updateAttract(msg)
+	}
+	if m.mode == modeStats {
+		// Any key goes back to the menu, which quitting would otherwise leave.
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.mode = modeMenu
+			return m, m.waitForIdle()
+		}
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, m.keys.Quit):
-			return m, tea.Quit
-		case key.Matches(msg, m.keys.Left):
-			m.settingIndex--
-			if m.settingIndex < 0 {
-				m.settingIndex = len(m.settings) - 1
-			}
-		case key.Matches(msg, m.keys.Right):
-			m.settingIndex++
-			if m.settingIndex >= len(m.settings) {
-				m.settingIndex = 0
-			}
-		case key.Matches(msg, m.keys.Up):
-			m.settings[m.settingIndex].index--
-			if m.settings[m.settingIndex].index < 0 {
-				m.settings[m.settingIndex].index = len(m.settings[m.settingIndex].options) - 1
-			}
-		case key.Matches(msg, m.keys.Down):
-			m.settings[m.settingIndex].index++
-			if m.settings[m.settingIndex].index >= len(m.settings[m.settingIndex].options) {
-				m.settings[m.settingIndex].index = 0
-			}
-		case key.Matches(msg, m.keys.Start):
-			cmd, err := m.startGame()
-			if err != nil {
-				panic(fmt.Errorf("failed to start game: %w", err))
-			}
-			return m, cmd
-		case key.Matches(msg, m.keys.Help):
-			m.help.ShowAll = !m.help.ShowAll
+	case idleMsg:
+		if m.idle() {
+			return m, m.startDemo()
 		}
+	case tea.KeyMsg:
+		m.err = nil
+		idle := m.waitForIdle()
+		next, cmd := m.updateKey(msg)
+		return next, tea.Batch(idle, cmd)
+	case tea.MouseMsg:
+		idle := m.waitForIdle()
+		next, cmd := m.updateMouse(msg)
+		return next, tea.Batch(idle, cmd)
 	}
 
 	return m, nil
 }
 
+// updateKey handles a key pressed in the menu.
+func (m Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Stats):
+		m.openStats()
+	case key.Matches(msg, m.keys.Left):
+		m.settingIndex--
+		if m.settingIndex < 0 {
+			m.settingIndex = len(m.settings) - 1
+		}
+	case key.Matches(msg, m.keys.Right):
+		m.settingIndex++
+		if m.settingIndex >= len(m.settings) {
+			m.settingIndex = 0
+		}
+	case key.Matches(msg, m.keys.Up):
+		if next, cmd, ok := m.selectOption(m.settings[m.settingIndex].index - 1); ok {
+			return next, cmd
+		}
+	case key.Matches(msg, m.keys.Down):
+		if next, cmd, ok := m.selectOption(m.settings[m.settingIndex].index + 1); ok {
+			return next, cmd
+		}
+	case key.Matches(msg, m.keys.Start):
+		return m, m.start()
+	case key.Matches(msg, m.keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	}
+	return m, nil
+}
+
+// selectOption selects the option at the index of the selected setting, wrapping around past either end. If the
+// option is another profile, it returns the menu of that profile to switch to.
+func (m *Model) selectOption(index int) (tea.Model, tea.Cmd, bool) {
+	s := &m.settings[m.settingIndex]
+	s.index = (index%len(s.options) + len(s.options)) % len(s.options)
+	if next, cmd, ok := m.switchProfile(); ok {
+		return next, cmd, true
+	}
+	m.styles = NewStyles(m.theme())
+	return nil, nil, false
+}
+
+// start starts a game with the selected settings, or shows why it couldn't be started.
+func (m *Model) start() tea.Cmd {
+	cmd, err := m.startGame()
+	if err != nil {
+		m.err = fmt.Errorf("failed to start game: %w", err)
+		return nil
+	}
+	// Games only learn the size of the terminal from a resize, so pass on the last one.
+	if m.windowSize != nil {
+		size := *m.windowSize
+		cmd = tea.Batch(cmd, func() tea.Msg { return size })
+	}
+	return cmd
+}
+
+// openStats shows the stats screen with the player's lifetime stats.
+func (m *Model) openStats() {
+	m.mode = modeStats
+	m.lifetime, m.statsErr = records.Lifetime{}, nil
+	if m.records != nil {
+		m.lifetime, m.statsErr = m.records.Lifetime(m.player)
+	}
+}
+
 func (m Model) View() string {
-	if m.mode == modeGame {
+	switch m.mode {
+	case modeGame:
 		return m.game.View()
+	case modeAttract:
+		return m.attractView()
+	case modeStats:
+		return m.statsView() + "\n\n" + m.styles.description.Render(m.styles.text("Press any key to go back"))
 	}
 
+	sections, _ := m.sections()
+	return lipgloss.JoinVertical(lipgloss.Center, sections...) + "\n" + m.help.View(m.styles.locale.Keys(m.keys))
+}
+
+// sections returns the sections of the menu, from top to bottom, and the index of the section with the settings.
+func (m *Model) sections() ([]string, int) {
+	sections := []string{renderTitle()}
+	if m.player != "" {
+		sections = append(sections, m.styles.player.Render(m.styles.text("Playing as")+" "+m.player))
+	}
+	row := len(sections)
+	sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, m.renderSettings()...))
+	if d := m.modeDescription(); d != "" {
+		sections = append(sections, m.styles.description.Render(d))
+	}
+	if m.err != nil {
+		sections = append(sections, m.styles.err.Render(m.err.Error()))
+	}
+	return sections, row
+}
+
+func (m *Model) renderSettings() []string {
 	settings := make([]string, len(m.settings))
 	for i := range m.settings {
 		settings[i] = m.renderSetting(i, i == m.settingIndex)
 	}
-	return lipgloss.JoinVertical(lipgloss.Center,
-		renderTitle(),
-		lipgloss.JoinHorizontal(lipgloss.Top, settings...),
-	) + "\n" + m.help.View(m.keys)
+	return settings
 }
 
 func renderTitle() string {
@@ -147,14 +659,14 @@ func renderTitle() string {
 }
 
 func (m *Model) renderSetting(index int, isSelected bool) string {
-	output := fmt.Sprintf("%v:\n", m.settings[index].name)
+	output := fmt.Sprintf("%v:\n", m.styles.text(m.settings[index].name))
 	for i, option := range m.settings[index].options {
 		if i == m.settings[index].index {
 			output += " > "
 		} else {
 			output += "   "
 		}
-		output += fmt.Sprintf("%v", option)
+		output += m.styles.text(fmt.Sprint(option))
 		if i < len(m.settings[index].options)-1 {
 			output += "\n"
 		}
@@ -167,7 +679,10 @@ func (m *Model) renderSetting(index int, isSelected bool) string {
 
 func (m *Model) startGame() (tea.Cmd, error) {
 	var level uint
-	var mode string
+	var modeName string
+	var holdPreview bool
+	var hold tetris.HoldRule
+	var board string
 	// var players uint
 	for _, setting := range m.settings {
 		switch setting.name {
@@ -176,16 +691,114 @@ func (m *Model) startGame() (tea.Cmd, error) {
 			level = uint(intLevel)
 		// case "Players":
 		// 	players = setting.options[setting.index].(uint)
+		case "Hold Preview":
+			holdPreview = setting.options[setting.index].(string) == "On"
+		case "Hold":
+			hold = setting.options[setting.index].(tetris.HoldRule)
+		case "Board":
+			board = setting.options[setting.index].(string)
 		case "Mode":
-			mode = setting.options[setting.index].(string)
+			modeName = setting.options[setting.index].(string)
+		case "Soft Drop":
+			sd := setting.options[setting.index].(softDrop)
+			m.handling.SoftDrop, m.handling.SonicDrop = sd.factor, sd.sonic
+		case "Lock Down":
+			m.handling.LockDown = setting.options[setting.index].(tetris.LockDown)
+		case "Keys":
+			m.keyPreset = setting.options[setting.index].(string)
+		}
+	}
+
+	t := m.theme()
+	var matrix *tetris.Matrix
+	if board != "Empty" {
+		var err error
+		matrix, err = preset.Load(board)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	switch mode {
-	case "Marathon":
+	if modeName == "Continue" || modeName == "Restore" {
+		path := m.savePath
+		if modeName == "Restore" {
+			path = m.autosavePath
+		}
+		g, err := save.Read(path)
+		if err != nil {
+			return nil, err
+		}
+		game, err := marathon.Resume(&marathon.Input{
+			Countdown:    m.countdown,
+			Theme:        t,
+			Records:      m.records,
+			Player:       m.player,
+			Audio:        m.audio,
+			Clipboard:    m.clipboard,
+			Handling:     m.handling,
+			Keys:         m.keyPreset,
+			IdleTimeout:  m.idleTimeout,
+			SavePath:     m.savePath,
+			AutosavePath: m.autosavePath,
+		}, g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to continue game: %w", err)
+		}
+		// The save is removed once continued, so the game can only be continued from where it was last suspended. An
+		// autosave is taken again once the restored game has been played for a while.
+		err = save.Remove(path)
+		if err != nil {
+			return nil, err
+		}
+		m.refreshModes()
 		m.mode = modeGame
-		m.game = marathon.InitialModel(level)
+		m.game = game
 		return m.game.Init(), nil
 	}
-	return nil, fmt.Errorf("invalid mode: %v", mode)
+
+	gameMode := mode.Get(modeName)
+	if gameMode == nil {
+		return nil, fmt.Errorf("invalid mode: %v", modeName)
+	}
+	game, err := gameMode.New(&mode.Settings{
+		Level:        level,
+		HoldPreview:  holdPreview,
+		Hold:         hold,
+		Matrix:       matrix,
+		Width:        m.width,
+		Height:       m.height,
+		Randomizer:   m.randomizer,
+		Attack:       m.attack,
+		Countdown:    m.countdown,
+		Interludes:   m.interludes,
+		Theme:        t,
+		Speed:        m.speed,
+		Handling:     m.handling,
+		Keys:         m.keyPreset,
+		IdleTimeout:  m.idleTimeout,
+		Records:      m.records,
+		Player:       m.player,
+		Leaderboard:  m.leaderboard,
+		Audio:        m.audio,
+		Clipboard:    m.clipboard,
+		SavePath:     m.savePath,
+		AutosavePath: m.autosavePath,
+		PCSession:    m.pcSession,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.mode = modeGame
+	m.game = game
+	return m.game.Init(), nil
+}
+
+// abandonGame removes the autosave of the game being left before it ended, so that it isn't offered to be restored.
+func (m *Model) abandonGame() {
+	switch game := m.game.(type) {
+	case marathon.Model:
+		game.Abandon()
+	case *marathon.Model:
+		game.Abandon()
+	}
 }
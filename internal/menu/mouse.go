@@ -0,0 +1,79 @@
+package menu
+
+import (
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateMouse handles the mouse in terminals which report it. Clicking an option selects it, and clicking the selected
+// mode again starts the game. Scrolling over a setting moves through its options.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	setting, option, ok := m.optionAt(msg.X, msg.Y)
+	if !ok {
+		return m, nil
+	}
+	m.err = nil
+
+	var index int
+	switch msg.Type {
+	case tea.MouseLeft:
+		if option < 0 {
+			return m, nil
+		}
+		if setting == m.settingIndex && option == m.settings[setting].index && m.settings[setting].name == "Mode" {
+			return m, m.start()
+		}
+		index = option
+	case tea.MouseWheelUp:
+		index = m.settings[setting].index - 1
+	case tea.MouseWheelDown:
+		index = m.settings[setting].index + 1
+	default:
+		return m, nil
+	}
+
+	m.settingIndex = setting
+	if next, cmd, ok := m.selectOption(index); ok {
+		return next, cmd
+	}
+	return m, nil
+}
+
+// optionAt returns the index of the setting drawn at the cell of the menu, and of its option on the line of the cell
+// (-1 if the cell is beside the setting's name or padding). It returns false if the cell is outside every setting.
+func (m *Model) optionAt(x, y int) (setting, option int, ok bool) {
+	sections, row := m.sections()
+	top, width := 0, 0
+	for i, s := range sections {
+		if i < row {
+			top += lipgloss.Height(s)
+		}
+		width = max(width, lipgloss.Width(s))
+	}
+	// The sections are centred as they are joined, with any odd column of space on the left.
+	left := int(math.Round(float64(width-lipgloss.Width(sections[row])) / 2))
+
+	if y < top || y >= top+lipgloss.Height(sections[row]) || x < left {
+		return 0, 0, false
+	}
+	for i, rendered := range m.renderSettings() {
+		w := lipgloss.Width(rendered)
+		if x >= left+w {
+			left += w
+			continue
+		}
+		style := m.styles.settingUnselected
+		if i == m.settingIndex {
+			style = m.styles.settingSelected
+		}
+		// Each setting starts with its name, followed by a line for each option.
+		option = y - top - style.GetBorderTopSize() - style.GetPaddingTop() - 1
+		if option < 0 || option >= len(m.settings[i].options) {
+			option = -1
+		}
+		return i, option, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,86 @@
+package menu
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// barWidth is the width of the longest bar in the charts of the stats screen.
+const barWidth = 30
+
+// statsView shows the player's lifetime stats, with a chart of how the games and time played are split between the
+// modes.
+func (m *Model) statsView() string {
+	title := m.styles.heading.Render(m.styles.text("Lifetime stats"))
+	if m.statsErr != nil {
+		return title + "\n\n" + m.styles.err.Render(m.statsErr.Error())
+	}
+	l := m.lifetime
+	if l.Games == 0 {
+		return title + "\n\n" + m.styles.description.Render(m.styles.text("Finish a game to start keeping stats."))
+	}
+
+	rows := []struct {
+		name, value string
+	}{
+		{"Games", fmt.Sprint(l.Games)},
+		{"Lines", fmt.Sprint(l.Lines)},
+		{"Tetrises", fmt.Sprint(l.Tetrises)},
+		{"Play time", l.Time.Round(time.Second).String()},
+		{"Average PPS", fmt.Sprintf("%.2f", l.PPS())},
+	}
+	var totals strings.Builder
+	for _, r := range rows {
+		totals.WriteString(fmt.Sprintf("%-14s%12s\n", m.styles.text(r.name), r.value))
+	}
+
+	modes := make([]string, 0, len(l.Modes))
+	for name := range l.Modes {
+		modes = append(modes, name)
+	}
+	// The modes played most are charted first.
+	slices.SortFunc(modes, func(a, b string) int {
+		if c := cmp.Compare(l.Modes[b].Games, l.Modes[a].Games); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	games := m.chart("Games by mode", modes, func(t records.ModeTotals) (float64, string) {
+		return float64(t.Games), fmt.Sprint(t.Games)
+	})
+	played := m.chart("Time by mode", modes, func(t records.ModeTotals) (float64, string) {
+		return t.Time.Seconds(), t.Time.Round(time.Second).String()
+	})
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", totals.String(), games, "", played)
+}
+
+// chart draws a bar for each mode, as long as its value relative to the largest, labelled with the value.
+func (m *Model) chart(heading string, modes []string, value func(records.ModeTotals) (float64, string)) string {
+	var largest float64
+	nameWidth := 0
+	for _, name := range modes {
+		v, _ := value(m.lifetime.Modes[name])
+		largest = max(largest, v)
+		nameWidth = max(nameWidth, lipgloss.Width(name))
+	}
+
+	var output strings.Builder
+	output.WriteString(m.styles.text(heading) + "\n")
+	for _, name := range modes {
+		v, label := value(m.lifetime.Modes[name])
+		length := 0
+		if largest > 0 {
+			// Every mode played gets at least a sliver of a bar.
+			length = max(int(v/largest*barWidth), 1)
+		}
+		output.WriteString(fmt.Sprintf("%-*s %s %s\n", nameWidth, name, m.styles.bar.Render(strings.Repeat("█", length)), label))
+	}
+	return strings.TrimSuffix(output.String(), "\n")
+}
@@ -1,16 +1,47 @@
 package menu
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
 
 type Styles struct {
 	settingSelected   lipgloss.Style
 	settingUnselected lipgloss.Style
+	player            lipgloss.Style
+	description       lipgloss.Style
+	err               lipgloss.Style
+	heading           lipgloss.Style
+	bar               lipgloss.Style
+	dimmed            lipgloss.Style
+	locale            *locale.Locale
 }
 
 func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for the menu with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
 	s := Styles{
-		settingSelected: lipgloss.NewStyle().Padding(1, 2),
+		settingSelected: lipgloss.NewStyle().Foreground(t.Text).Padding(1, 2),
 	}
-	s.settingUnselected = s.settingSelected.Copy().Foreground(lipgloss.Color("241"))
+	s.settingUnselected = s.settingSelected.Copy().Foreground(t.Muted)
+	s.player = lipgloss.NewStyle().Foreground(t.Muted)
+	s.description = lipgloss.NewStyle().Foreground(t.Muted).Italic(true)
+	s.err = lipgloss.NewStyle().Foreground(t.Danger)
+	s.heading = lipgloss.NewStyle().Foreground(t.Text).Bold(true)
+	s.bar = lipgloss.NewStyle().Foreground(t.Accent)
+	s.dimmed = lipgloss.NewStyle().Foreground(t.Muted)
+	s.locale = t.Locale
 	return &s
 }
+
+// text returns the English text in the language of the styles' theme.
+func (s *Styles) text(english string) string {
+	return s.locale.T(english)
+}
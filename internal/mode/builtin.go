@@ -0,0 +1,271 @@
+package mode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/dual"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/versus"
+	"github.com/Broderick-Westrope/tetrigo/internal/warmup"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// builtin returns the modes which come with the game, in the order they are listed in the menu.
+func builtin() []GameMode {
+	return []GameMode{
+		&Marathon{
+			Title:   "Marathon",
+			Summary: "Clear lines for as long as you can while the speed increases.",
+		},
+		&Marathon{
+			Title:   "Classic",
+			Summary: "Marathon with the scoring and rules of classic games, without holding.",
+			Input: func(s *Settings) *marathon.Input {
+				return &marathon.Input{
+					Level:       s.Level,
+					Classic:     true,
+					Matrix:      s.Matrix,
+					Countdown:   s.Countdown,
+					Theme:       s.Theme,
+					Speed:       s.Speed,
+					Handling:    s.Handling,
+					Keys:        s.Keys,
+					IdleTimeout: s.IdleTimeout,
+					Records:     s.Records,
+					Player:      s.Player,
+					Leaderboard: s.Leaderboard,
+					Audio:       s.Audio,
+					Clipboard:   s.Clipboard,
+				}
+			},
+		},
+		&Marathon{
+			Title:   "Master",
+			Summary: "Earn a grade playing at 20G, with tetriminos falling instantly.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Master = true
+				in.Randomizer = ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Cheese",
+			Summary: "Dig through 10 rows of garbage as fast as you can.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Cheese = 10
+				in.Matrix, in.Width, in.Height = nil, 0, 0
+				in.Randomizer = ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Dig",
+			Summary: "Survive garbage rising every 4 seconds.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Dig = 4 * time.Second
+				in.GarbageMessiness = 0.3
+				in.Matrix = nil
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Daily",
+			Summary: "The day's cheese race, the same for everyone.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Daily = marathon.DailyDate(time.Now())
+				in.Level = 0
+				in.Matrix, in.Width, in.Height = nil, 0, 0
+				in.Randomizer = ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Invisible",
+			Summary: "Marathon with the stack hidden once tetriminos lock.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Invisible = true
+				in.HoldPreview = false
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Func{
+			Title:   "Dual",
+			Summary: "Play two boards at once, switching between them.",
+			Create: func(s *Settings) (tea.Model, error) {
+				return dual.NewModel(&dual.Input{
+					Level:     s.Level,
+					Countdown: s.Countdown,
+					Theme:     s.Theme,
+					Speed:     s.Speed,
+					Audio:     s.Audio,
+					Keys:      s.Keys,
+					Handling:  s.Handling,
+				}), nil
+			},
+		},
+		&Func{
+			Title:   "Versus",
+			Summary: "Send garbage to the bot until one of you tops out.",
+			Create: func(s *Settings) (tea.Model, error) {
+				return versus.NewModel(&versus.Input{
+					Level:     s.Level,
+					Countdown: s.Countdown,
+					Theme:     s.Theme,
+					Audio:     s.Audio,
+					Keys:      s.Keys,
+					Handling:  s.Handling,
+					Attack:    s.Attack,
+				}), nil
+			},
+		},
+		&Func{
+			Title:   "Warm-up",
+			Summary: "A routine of short exercises played back-to-back.",
+			Create: func(s *Settings) (tea.Model, error) {
+				game, err := warmup.NewModel(&warmup.Input{
+					Routine:   warmup.DefaultRoutine,
+					Countdown: s.Countdown,
+					Theme:     s.Theme,
+					Speed:     s.Speed,
+					Handling:  s.Handling,
+					Records:   s.Records,
+					Player:    s.Player,
+					Audio:     s.Audio,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to create warm-up: %w", err)
+				}
+				return game, nil
+			},
+		},
+		&Marathon{
+			Title:   "Combo",
+			Summary: "Practise long combos in a 4-wide well.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.ComboPractice = true
+				in.Width, in.Height = 0, 0
+				in.Randomizer = ""
+				in.Records, in.Player = nil, ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "T-spin drill",
+			Summary: "Spin Ts into the double and triple slots of 10 generated boards in a row.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.TSpinDrills = marathon.DefaultTSpinDrills
+				in.Matrix, in.Randomizer = nil, ""
+				in.Records, in.Player = nil, ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "PC opener",
+			Summary: "Clear an empty board within 10 tetriminos, 10 times over.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.PCOpeners = marathon.DefaultPCOpeners
+				in.Matrix, in.Width, in.Height = nil, 0, 0
+				in.Randomizer = ""
+				in.Records, in.Player = nil, ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Sandbox",
+			Summary: "Edit the stack and pick the tetriminos dealt to try out setups.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Sandbox = true
+				in.Records, in.Player = nil, ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Zen",
+			Summary: "Play at your own pace, rewinding the last 10 seconds to try again.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Zen = true
+				in.Records, in.Player = nil, ""
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Zone",
+			Summary: "Charge a meter by clearing lines, then stop time to stack them for a bonus.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Zone = true
+				in.Interludes = false
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Big",
+			Summary: "Every tetrimino is twice the size, on a board twice as wide.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Big = true
+				in.Matrix, in.Width, in.Height = nil, 0, 0
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Adaptive",
+			Summary: "Gravity and garbage ease off or press harder to match how you're doing.",
+			Input: func(s *Settings) *marathon.Input {
+				in := s.Input()
+				in.Adaptive = true
+				in.SavePath = ""
+				return in
+			},
+		},
+		&Marathon{
+			Title:   "Demo",
+			Summary: "Watch the built-in bot play.",
+			Input: func(s *Settings) *marathon.Input {
+				return &marathon.Input{
+					Level:     s.Level,
+					Bot:       true,
+					Matrix:    s.Matrix,
+					Countdown: s.Countdown,
+					Theme:     s.Theme,
+					Speed:     s.Speed,
+					Audio:     s.Audio,
+					Clipboard: s.Clipboard,
+				}
+			},
+		},
+	}
+}
@@ -0,0 +1,224 @@
+package mode
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/BurntSushi/toml"
+)
+
+// maxCheese is the most rows of cheese a mode can start with, leaving room in the visible playfield to spawn.
+const maxCheese = 18
+
+// File is a marathon mode defined in a TOML file, so that house rules can be played without changing the game. Fields
+// left out keep the player's settings, or the game's defaults.
+type File struct {
+	Name        string     `toml:"name"`
+	Description string     `toml:"description"`
+	Level       uint       `toml:"level"`      // level to start at (0 for the player's)
+	Curve       string     `toml:"curve"`      // curve the fall speed follows (see tetris.CurveNames)
+	Gravity     []Duration `toml:"gravity"`    // time to fall one row at each level from 1, overriding the curve
+	Randomizer  string     `toml:"randomizer"` // see tetris.RandomizerNames
+	Hold        string     `toml:"hold"`       // once, off or unlimited
+	HoldSlots   int        `toml:"hold_slots"` // tetriminos which can be held at once (0 for 1)
+
+	Goal struct {
+		Lines uint     `toml:"lines"` // won once this many lines are cleared
+		Score uint     `toml:"score"` // won once the score reaches this
+		Level uint     `toml:"level"` // won once this level is reached
+		Time  Duration `toml:"time"`  // finished once this much time has been played, for the best score
+	} `toml:"goal"`
+
+	Garbage struct {
+		Starting  uint     `toml:"starting"`   // rows of garbage the board starts with
+		Cheese    uint     `toml:"cheese"`     // rows of cheese to dig through, finishing once they are cleared
+		Rise      Duration `toml:"rise"`       // time between each line of garbage rising from the bottom
+		Messiness float64  `toml:"messiness"`  // probability of the hole changing column on each line
+		Holes     string   `toml:"holes"`      // messy, clean or random
+		HoleWidth int      `toml:"hole_width"` // columns each hole spans
+	} `toml:"garbage"`
+}
+
+// Duration is a length of time written as in Go, such as "1.5s" or "2m".
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	var err error
+	d.Duration, err = time.ParseDuration(string(text))
+	return err
+}
+
+// LoadFile reads the mode defined in the TOML file at the path.
+func LoadFile(path string) (*Marathon, error) {
+	var f File
+	md, err := toml.DecodeFile(path, &f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mode %s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("invalid mode %s: unknown field %q", path, undecoded[0].String())
+	}
+	err = f.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid mode %s: %w", path, err)
+	}
+	return f.Mode(), nil
+}
+
+// LoadDir registers the modes defined in each .toml file in the directory, in alphabetical order of the files. A
+// missing directory is not an error. Files which can't be loaded or registered are skipped, with an error for each.
+func LoadDir(dir string) []error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return []error{fmt.Errorf("failed to list modes: %w", err)}
+	}
+	var errs []error
+	for _, path := range paths {
+		m, err := LoadFile(path)
+		if err == nil {
+			err = Register(m)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// DefaultDir returns the directory modes are loaded from, beside the config file at the path.
+func DefaultDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "modes")
+}
+
+// Validate reports whether the mode can be played, returning the first problem found.
+func (f *File) Validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return errors.New("mode must have a name")
+	}
+	if f.Curve != "" {
+		if _, err := tetris.CurveByName(f.Curve); err != nil {
+			return err
+		}
+	}
+	for i, d := range f.Gravity {
+		if d.Duration < 0 {
+			return fmt.Errorf("invalid gravity %s at level %d: must not be negative", d, i+1)
+		}
+	}
+	if f.Randomizer != "" && !slices.Contains(tetris.RandomizerNames, f.Randomizer) {
+		return fmt.Errorf("invalid randomizer %q: must be one of %s", f.Randomizer, strings.Join(tetris.RandomizerNames, ", "))
+	}
+	if f.Hold != "" {
+		if _, err := tetris.ParseHoldRule(f.Hold); err != nil {
+			return err
+		}
+	}
+	if f.HoldSlots < 0 || f.HoldSlots > tetris.MaxHoldSlots {
+		return fmt.Errorf("invalid hold slots %d: must be between 1 and %d", f.HoldSlots, tetris.MaxHoldSlots)
+	}
+	if f.Goal.Time.Duration < 0 {
+		return fmt.Errorf("invalid goal time %s: must not be negative", f.Goal.Time)
+	}
+
+	g := f.Garbage
+	if g.Cheese > maxCheese {
+		return fmt.Errorf("invalid cheese %d: must be at most %d", g.Cheese, maxCheese)
+	}
+	if g.Starting > maxCheese {
+		return fmt.Errorf("invalid starting garbage %d: must be at most %d", g.Starting, maxCheese)
+	}
+	if g.Rise.Duration < 0 {
+		return fmt.Errorf("invalid rise %s: must not be negative", g.Rise)
+	}
+	if g.Messiness < 0 || g.Messiness > 1 {
+		return fmt.Errorf("invalid messiness %v: must be between 0 and 1", g.Messiness)
+	}
+	if g.Holes != "" {
+		if _, err := tetris.ParseHolePattern(g.Holes); err != nil {
+			return err
+		}
+	}
+	if g.HoleWidth < 0 || g.HoleWidth >= tetris.DefaultWidth {
+		return fmt.Errorf("invalid hole width %d: must be at most %d", g.HoleWidth, tetris.DefaultWidth-1)
+	}
+	return nil
+}
+
+// Mode returns the mode the file defines, which must be valid.
+func (f *File) Mode() *Marathon {
+	return &Marathon{
+		Title:   f.Name,
+		Summary: f.Description,
+		Input:   f.input,
+		Rules:   f.rules(),
+	}
+}
+
+// input returns the input of a game of the mode played with the settings.
+func (f *File) input(s *Settings) *marathon.Input {
+	in := s.Input()
+	if f.Level > 0 {
+		in.Level = f.Level
+	}
+	in.Curve = f.Curve
+	for _, d := range f.Gravity {
+		in.Gravity = append(in.Gravity, d.Duration)
+	}
+	if f.Randomizer != "" {
+		in.Randomizer = f.Randomizer
+	}
+	if f.Hold != "" {
+		in.Hold, _ = tetris.ParseHoldRule(f.Hold)
+	}
+	if f.HoldSlots > 0 {
+		in.HoldSlots = f.HoldSlots
+	}
+	in.LineGoal = f.Goal.Lines
+	in.TimeLimit = f.Goal.Time.Duration
+
+	holes, _ := tetris.ParseHolePattern(f.Garbage.Holes)
+	if f.Garbage.Holes == "" {
+		holes = tetris.HolesMessy
+	}
+	in.StartingGarbage = f.Garbage.Starting
+	in.Cheese = f.Garbage.Cheese
+	in.CheeseHoles, in.CheeseHoleWidth = holes, f.Garbage.HoleWidth
+	in.Dig = f.Garbage.Rise.Duration
+	in.GarbageMessiness = f.Garbage.Messiness
+	in.GarbageHoles, in.GarbageHoleWidth = holes, f.Garbage.HoleWidth
+
+	// The rules decide when the game is over, so it can't be suspended and continued as a marathon.
+	in.SavePath = ""
+	return in
+}
+
+// rules returns the conditions for winning the mode beyond those the input has, and the progress towards them.
+func (f *File) rules() *marathon.Rules {
+	rules := &marathon.Rules{Name: f.Name}
+	score, level := f.Goal.Score, f.Goal.Level
+	if score == 0 && level == 0 {
+		return rules
+	}
+	rules.Won = func(r marathon.Results) bool {
+		return (score > 0 && r.Score >= score) || (level > 0 && r.Level >= level)
+	}
+	rules.HUD = func(r marathon.Results) string {
+		var goals []string
+		if score > 0 {
+			goals = append(goals, fmt.Sprintf("Goal: %d/%d points", min(r.Score, score), score))
+		}
+		if level > 0 {
+			goals = append(goals, fmt.Sprintf("Goal: level %d/%d", min(r.Level, level), level))
+		}
+		return strings.Join(goals, "\n")
+	}
+	return rules
+}
@@ -0,0 +1,129 @@
+package mode
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// writeMode writes the TOML to a file in a temporary directory, returning its path.
+func writeMode(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(contents), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write mode: %v", err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeMode(t, t.TempDir(), "sprint.toml", `
+name = "Sprint"
+description = "Clear 20 lines"
+level = 3
+gravity = ["1s", "500ms"]
+randomizer = "tgm"
+hold = "once"
+hold_slots = 2
+
+[goal]
+lines = 20
+score = 5000
+
+[garbage]
+cheese = 5
+holes = "clean"
+hole_width = 2
+`)
+
+	m, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if m.Name() != "Sprint" || m.Summary != "Clear 20 lines" {
+		t.Errorf("want Sprint, got %q: %q", m.Name(), m.Summary)
+	}
+
+	in := m.Input(&Settings{Level: 1, Randomizer: "bag", SavePath: "save.json"})
+	switch {
+	case in.Level != 3, in.Randomizer != "tgm", in.Hold != tetris.HoldOnce, in.HoldSlots != 2, in.LineGoal != 20:
+		t.Errorf("want the file's settings, got %+v", in)
+	case len(in.Gravity) != 2 || in.Gravity[1] != 500*time.Millisecond:
+		t.Errorf("want the file's gravity, got %v", in.Gravity)
+	case in.Cheese != 5 || in.CheeseHoles != tetris.HolesClean || in.CheeseHoleWidth != 2:
+		t.Errorf("want 5 rows of clean cheese with wide holes, got %+v", in)
+	case in.SavePath != "":
+		t.Errorf("want games of the mode not saved, got %q", in.SavePath)
+	}
+
+	if m.Rules.Won(marathon.Results{Score: 4000}) || !m.Rules.Won(marathon.Results{Score: 5000}) {
+		t.Errorf("want the game won at 5000 points")
+	}
+	if got := m.Rules.HUD(marathon.Results{Score: 4000}); got != "Goal: 4000/5000 points" {
+		t.Errorf("HUD: want the progress to the goal, got %q", got)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	tt := map[string]struct {
+		contents string
+		wantErr  string
+	}{
+		"no name": {
+			contents: `level = 1`,
+			wantErr:  "must have a name",
+		},
+		"unknown field": {
+			contents: "name = \"Fast\"\nspeed = 2",
+			wantErr:  `unknown field "speed"`,
+		},
+		"unknown randomizer": {
+			contents: "name = \"Fast\"\nrandomizer = \"fair\"",
+			wantErr:  `invalid randomizer "fair"`,
+		},
+		"invalid duration": {
+			contents: "name = \"Fast\"\ngravity = [\"fast\"]",
+			wantErr:  "fast",
+		},
+		"too messy": {
+			contents: "name = \"Fast\"\n[garbage]\nmessiness = 2",
+			wantErr:  "invalid messiness",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			path := writeMode(t, t.TempDir(), "mode.toml", tc.contents)
+			_, err := LoadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("want an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	if errs := LoadDir(filepath.Join(t.TempDir(), "missing")); len(errs) != 0 {
+		t.Errorf("want no errors for a missing directory, got %v", errs)
+	}
+
+	dir := t.TempDir()
+	writeMode(t, dir, "custom.toml", `name = "Custom test mode"`)
+	writeMode(t, dir, "broken.toml", `name = `)
+	writeMode(t, dir, "duplicate.toml", `name = "Marathon"`)
+	writeMode(t, dir, "notes.txt", `not a mode`)
+
+	errs := LoadDir(dir)
+	if len(errs) != 2 {
+		t.Errorf("want errors for the broken and duplicate modes, got %v", errs)
+	}
+	if Get("Custom test mode") == nil {
+		t.Errorf("want the custom mode registered")
+	}
+}
@@ -0,0 +1,179 @@
+// Package mode keeps a registry of the modes games can be played in, so that a mode can be added by registering it
+// rather than changing the menu and the commands which start games.
+package mode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/leaderboard"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// GameMode is a mode games can be played in, such as marathon or a versus match.
+type GameMode interface {
+	// Name is the name the mode is chosen by, such as "Marathon". Names are unique, ignoring case.
+	Name() string
+	// Description briefly describes how the mode is played.
+	Description() string
+	// New creates a game of the mode, played with the settings where they apply to it.
+	New(s *Settings) (tea.Model, error)
+}
+
+// Settings are the choices the player has made for their games, such as in the menu. Modes use the settings which apply
+// to them and ignore the rest.
+type Settings struct {
+	Level       uint
+	HoldPreview bool
+	Hold        tetris.HoldRule
+	Matrix      *tetris.Matrix      // the board to start from, such as a preset (nil for an empty one)
+	Width       int                 // columns of the matrix for games on an empty board (0 for the default)
+	Height      int                 // visible rows of the matrix for games on an empty board (0 for the default)
+	Randomizer  string              // the randomizer dealing tetriminos in modes without one of their own (empty for the default)
+	Attack      *tetris.AttackTable // the lines sent for each kind of line clear in versus (nil for the guideline's table)
+	Countdown   uint
+	Interludes  bool
+	Theme       *theme.Theme
+	Speed       float64
+	Handling    tetris.Handling
+	Keys        string        // the name of the preset of keys gameplay is bound to (empty for the default)
+	IdleTimeout time.Duration // how long games go without input before pausing (0 to never pause)
+	Records     *records.Store
+	Player      string
+	Leaderboard *leaderboard.Client // where the results of recorded games are submitted (nil to not submit them)
+	Audio       *audio.Player
+	Clipboard   io.Writer
+	SavePath    string // where marathon games are saved when suspended (empty to not allow suspending)
+	// AutosavePath is where marathon games are snapshotted while they are played, to be restored after a crash (empty
+	// to not take snapshots).
+	AutosavePath string
+	// PCSession counts the perfect-clear openers attempted over every game played with the settings (nil for each
+	// game to count its own).
+	PCSession *marathon.PCSession
+}
+
+// Input returns the input of a marathon game played with every setting.
+func (s *Settings) Input() *marathon.Input {
+	return &marathon.Input{
+		Level:        s.Level,
+		HoldPreview:  s.HoldPreview,
+		Hold:         s.Hold,
+		Matrix:       s.Matrix,
+		Width:        s.Width,
+		Height:       s.Height,
+		Randomizer:   s.Randomizer,
+		Countdown:    s.Countdown,
+		Interludes:   s.Interludes,
+		Theme:        s.Theme,
+		Speed:        s.Speed,
+		Handling:     s.Handling,
+		Keys:         s.Keys,
+		IdleTimeout:  s.IdleTimeout,
+		Records:      s.Records,
+		Player:       s.Player,
+		Leaderboard:  s.Leaderboard,
+		Audio:        s.Audio,
+		Clipboard:    s.Clipboard,
+		SavePath:     s.SavePath,
+		AutosavePath: s.AutosavePath,
+		PCSession:    s.PCSession,
+	}
+}
+
+// Marathon is a mode played as a marathon game, with the input it returns for the settings and any rules of its own.
+type Marathon struct {
+	Title   string
+	Summary string
+
+	// Input returns the input of a game played with the settings (nil for every setting, as returned by
+	// Settings.Input).
+	Input func(s *Settings) *marathon.Input
+	// Rules add the mode's own win and lose conditions and information to the game (nil for none).
+	Rules *marathon.Rules
+}
+
+func (m *Marathon) Name() string {
+	return m.Title
+}
+
+func (m *Marathon) Description() string {
+	return m.Summary
+}
+
+func (m *Marathon) New(s *Settings) (tea.Model, error) {
+	in := s.Input()
+	if m.Input != nil {
+		in = m.Input(s)
+	}
+	if m.Rules != nil {
+		in.Rules = m.Rules
+	}
+	return marathon.NewModel(in), nil
+}
+
+// Func is a mode whose games are created by a function, such as one played across several boards.
+type Func struct {
+	Title   string
+	Summary string
+	Create  func(s *Settings) (tea.Model, error)
+}
+
+func (f *Func) Name() string {
+	return f.Title
+}
+
+func (f *Func) Description() string {
+	return f.Summary
+}
+
+func (f *Func) New(s *Settings) (tea.Model, error) {
+	return f.Create(s)
+}
+
+// registry holds the modes in the order they were registered, starting with the built-in modes.
+var registry = builtin()
+
+// Register adds the mode to the registry, after the modes registered already. Modes are usually registered from the
+// init function of the package defining them.
+func Register(m GameMode) error {
+	if strings.TrimSpace(m.Name()) == "" {
+		return errors.New("mode must have a name")
+	}
+	if Get(m.Name()) != nil {
+		return fmt.Errorf("mode %q is already registered", m.Name())
+	}
+	registry = append(registry, m)
+	return nil
+}
+
+// All returns every registered mode, in the order they were registered.
+func All() []GameMode {
+	return append([]GameMode(nil), registry...)
+}
+
+// Names returns the names of every registered mode, in the order they were registered.
+func Names() []string {
+	names := make([]string, len(registry))
+	for i, m := range registry {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+// Get returns the mode with the name, ignoring case, or nil if there is none.
+func Get(name string) GameMode {
+	for _, m := range registry {
+		if strings.EqualFold(m.Name(), name) {
+			return m
+		}
+	}
+	return nil
+}
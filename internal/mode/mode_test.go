@@ -0,0 +1,144 @@
+package mode
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNames(t *testing.T) {
+	want := []string{"Marathon", "Classic", "Master", "Cheese", "Dig", "Daily", "Invisible", "Dual", "Versus", "Warm-up", "Combo", "T-spin drill", "PC opener", "Sandbox", "Zen", "Zone", "Big", "Adaptive", "Demo"}
+	got := Names()
+	if !slices.Equal(got[:len(want)], want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestGet(t *testing.T) {
+	tt := map[string]struct {
+		name string
+		want string
+	}{
+		"exact": {
+			name: "Warm-up",
+			want: "Warm-up",
+		},
+		"ignoring case": {
+			name: "marathon",
+			want: "Marathon",
+		},
+		"unknown": {
+			name: "Tetris 99",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Get(tc.name)
+			if tc.want == "" {
+				if got != nil {
+					t.Errorf("want nil, got %v", got.Name())
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("want %v, got nil", tc.want)
+			}
+			if got.Name() != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got.Name())
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func(r []GameMode) { registry = r }(All())
+
+	tt := map[string]struct {
+		mode    GameMode
+		wantErr bool
+	}{
+		"new mode": {
+			mode: &Marathon{Title: "Survival", Rules: &marathon.Rules{Name: "Survival"}},
+		},
+		"already registered": {
+			mode:    &Marathon{Title: "CLASSIC"},
+			wantErr: true,
+		},
+		"no name": {
+			mode:    &Func{Title: " "},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			err := Register(tc.mode)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if got := Get(tc.mode.Name()); got != tc.mode {
+				t.Errorf("want %v, got %v", tc.mode, got)
+			}
+			names := Names()
+			if names[len(names)-1] != tc.mode.Name() {
+				t.Errorf("want %v last, got %v", tc.mode.Name(), names)
+			}
+		})
+	}
+}
+
+func TestMarathon_New(t *testing.T) {
+	survival := &Marathon{
+		Title: "Survival",
+		Rules: &marathon.Rules{
+			Name: "Survival",
+			Lost: func(r marathon.Results) bool { return r.Lines >= 1 },
+			HUD:  func(r marathon.Results) string { return "Survive!" },
+		},
+	}
+
+	game, err := survival.New(&Settings{Level: 3})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	m, ok := game.(*marathon.Model)
+	if !ok {
+		t.Fatalf("want *marathon.Model, got %T", game)
+	}
+	if got := m.Results().Level; got != 3 {
+		t.Errorf("want level 3, got %v", got)
+	}
+	if view := m.View(); !strings.Contains(view, "Survive!") {
+		t.Errorf("want the HUD in the view, got %q", view)
+	}
+}
+
+func TestFunc_New(t *testing.T) {
+	var got *Settings
+	f := &Func{
+		Title: "Custom",
+		Create: func(s *Settings) (tea.Model, error) {
+			got = s
+			return nil, nil
+		},
+	}
+
+	s := &Settings{Level: 5}
+	_, err := f.New(s)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if got != s {
+		t.Errorf("want the settings passed to Create, got %v", got)
+	}
+}
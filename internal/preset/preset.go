@@ -0,0 +1,44 @@
+// Package preset provides predefined boards that games can start from, such as practice scenarios.
+package preset
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+//go:embed boards/*.txt
+var boards embed.FS
+
+// Names returns the names of all presets, sorted alphabetically.
+func Names() []string {
+	entries, err := boards.ReadDir("boards")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns the starting matrix for the named preset.
+func Load(name string) (*tetris.Matrix, error) {
+	data, err := boards.ReadFile(path.Join("boards", name+".txt"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid preset %q: %w", name, err)
+	}
+
+	matrix, err := tetris.ParseMatrix(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse preset %q: %w", name, err)
+	}
+	return &matrix, nil
+}
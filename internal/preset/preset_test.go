@@ -0,0 +1,35 @@
+package preset
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("expected presets, got none")
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			m, err := Load(name)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+
+			// The top of the matrix must be clear for tetriminos to spawn.
+			for row := 0; row < 22; row++ {
+				for col := range (*m)[row] {
+					if !m.IsCellEmpty(row, col) {
+						t.Errorf("cell at row %d, col %d is filled", row, col)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := Load("missing")
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -0,0 +1,58 @@
+// Package profile keeps the files of each player sharing a machine apart, so that each has their own config, personal
+// bests and suspended game.
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+// dirName is the directory beside the default files that each profile's files are kept in, as profiles/<name>.
+const dirName = "profiles"
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Validate checks that the name can be used for a profile: up to 32 letters, digits, dashes and underscores, so that
+// it is safe to use as a directory name.
+func Validate(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid profile %q: must be up to 32 letters, digits, dashes and underscores", name)
+	}
+	return nil
+}
+
+// Path returns where the profile keeps the file found at the given default path, such as the config file: a
+// directory of its own beside it. The default profile (an empty name) keeps the file at the default path.
+func Path(name, defaultPath string) (string, error) {
+	if name == "" {
+		return defaultPath, nil
+	}
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(defaultPath), dirName, name, filepath.Base(defaultPath)), nil
+}
+
+// Names returns the names of the profiles which have been played, in order, given the directory the default files are
+// kept in.
+func Names(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, dirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && Validate(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
@@ -0,0 +1,73 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	defaultPath := filepath.Join("config", "tetrigo", "records.json")
+	tt := []struct {
+		name     string
+		profile  string
+		expected string
+		wantErr  bool
+	}{
+		{"default", "", defaultPath, false},
+		{"named", "alice", filepath.Join("config", "tetrigo", "profiles", "alice", "records.json"), false},
+		{"dashes and underscores", "kid_2-b", filepath.Join("config", "tetrigo", "profiles", "kid_2-b", "records.json"), false},
+		{"path separator", "../alice", "", true},
+		{"space", "alice smith", "", true},
+		{"too long", "abcdefghijklmnopqrstuvwxyz0123456", "", true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Path(tc.profile, defaultPath)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("want %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	dir := t.TempDir()
+	names, err := Names(dir)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("want no profiles, got %v", names)
+	}
+
+	for _, name := range []string{"bob", "alice", "not valid"} {
+		err = os.MkdirAll(filepath.Join(dir, "profiles", name), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = os.WriteFile(filepath.Join(dir, "profiles", "carol"), nil, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, err = Names(dir)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if expected := []string{"alice", "bob"}; !slices.Equal(names, expected) {
+		t.Errorf("want %v, got %v", expected, names)
+	}
+}
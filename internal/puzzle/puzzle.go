@@ -0,0 +1,254 @@
+// Package puzzle loads puzzles: boards to be solved with a fixed queue of tetriminos, such as by clearing every cell.
+//
+// Puzzles are written in TOML. The board uses the text format of tetris.ParseMatrix, aligned to the bottom of the
+// matrix, and the queue lists the values of the tetriminos dealt, in order:
+//
+//	title = "Box out"
+//	description = "Fill the gap with both O tetriminos."
+//	goal = "perfect-clear"
+//	queue = "OO"
+//	board = """
+//	XXX..XXXXX
+//	XXX..XXXXX
+//	"""
+//
+// The goal is "perfect-clear", to leave the board empty, "lines", to clear the number of lines given by lines, or
+// "t-spin", to make a T-spin clearing that many lines, such as 2 for a T-spin double. It must be reached before the
+// queue runs out.
+//
+// Instead of a board, a puzzle can give a fumen, as shared by other tools. If the fumen is a quiz and no queue is
+// given, the quiz's tetriminos are dealt.
+package puzzle
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/fumen"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed puzzles/*.toml
+var puzzles embed.FS
+
+// Goal is what must be done to solve a puzzle.
+type Goal string
+
+const (
+	// GoalPerfectClear is to clear every cell of the board.
+	GoalPerfectClear Goal = "perfect-clear"
+	// GoalLines is to clear a number of lines.
+	GoalLines Goal = "lines"
+	// GoalTSpin is to make a T-spin clearing a number of lines. Mini T-spins don't count.
+	GoalTSpin Goal = "t-spin"
+)
+
+// tSpinNames are the names of the T-spins which clear each number of lines.
+var tSpinNames = []string{1: "T-spin single", 2: "T-spin double", 3: "T-spin triple"}
+
+type Puzzle struct {
+	Name        string // the name of the file the puzzle was loaded from, without its extension
+	Title       string
+	Description string
+	Goal        Goal
+	Lines       uint          // lines to clear, for GoalLines, or for the T-spin to clear, for GoalTSpin
+	Queue       []byte        // values of the tetriminos dealt, in order
+	Matrix      tetris.Matrix // the board the puzzle starts from
+}
+
+// file is the format puzzles are written in.
+type file struct {
+	Title       string `toml:"title"`
+	Description string `toml:"description"`
+	Goal        Goal   `toml:"goal"`
+	Lines       uint   `toml:"lines"`
+	Queue       string `toml:"queue"`
+	Board       string `toml:"board"`
+	Fumen       string `toml:"fumen"`
+}
+
+// Names returns the names of all bundled puzzles, sorted alphabetically.
+func Names() []string {
+	entries, err := puzzles.ReadDir("puzzles")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".toml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns the named bundled puzzle.
+func Load(name string) (*Puzzle, error) {
+	data, err := puzzles.ReadFile(path.Join("puzzles", name+".toml"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid puzzle %q: %w", name, err)
+	}
+	return Parse(name, data)
+}
+
+// LoadFile reads a puzzle from a file, named after the file without its extension.
+func LoadFile(filename string) (*Puzzle, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read puzzle: %w", err)
+	}
+	return Parse(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), data)
+}
+
+// Parse reads a puzzle with the given name from its TOML format.
+func Parse(name string, data []byte) (*Puzzle, error) {
+	var f file
+	md, err := toml.Decode(string(data), &f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse puzzle %q: %w", name, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("invalid puzzle %q: unknown field %q", name, undecoded[0].String())
+	}
+	return parse(name, f)
+}
+
+// FromFumen returns a puzzle to clear the board of the fumen with the tetriminos of its quiz.
+func FromFumen(data string) (*Puzzle, error) {
+	return parse("fumen", file{Title: "Fumen", Goal: GoalPerfectClear, Fumen: data})
+}
+
+func parse(name string, f file) (*Puzzle, error) {
+	var matrix tetris.Matrix
+	var err error
+	if f.Fumen != "" {
+		matrix, err = f.readFumen()
+		if err != nil {
+			return nil, fmt.Errorf("invalid puzzle %q: %w", name, err)
+		}
+	}
+
+	err = f.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid puzzle %q: %w", name, err)
+	}
+
+	if f.Fumen == "" {
+		matrix, err = tetris.ParseMatrix(f.Board)
+		if err != nil {
+			return nil, fmt.Errorf("invalid puzzle %q: failed to parse board: %w", name, err)
+		}
+	}
+	return &Puzzle{
+		Name:        name,
+		Title:       f.Title,
+		Description: f.Description,
+		Goal:        f.Goal,
+		Lines:       f.Lines,
+		Queue:       []byte(f.Queue),
+		Matrix:      matrix,
+	}, nil
+}
+
+// readFumen returns the board of the fumen, taking the queue from its quiz if there is no queue already, and its
+// comment as the description if there is none.
+func (f *file) readFumen() (tetris.Matrix, error) {
+	if f.Board != "" {
+		return tetris.Matrix{}, errors.New("board and fumen can't both be given")
+	}
+	page, err := fumen.Decode(f.Fumen)
+	if err != nil {
+		return tetris.Matrix{}, err
+	}
+
+	quiz, ok, err := fumen.ParseQuiz(page.Comment)
+	switch {
+	case err != nil:
+		return tetris.Matrix{}, err
+	case !ok:
+		if f.Description == "" {
+			f.Description = page.Comment
+		}
+	case f.Queue == "":
+		// Puzzles have no hold, so a quiz that starts with one can't be played as intended.
+		if quiz.Hold != 0 {
+			return tetris.Matrix{}, errors.New("fumen quiz has a held tetrimino, but puzzles have no hold")
+		}
+		if quiz.Current != 0 {
+			f.Queue = string(quiz.Current)
+		}
+		f.Queue += string(quiz.Next)
+	}
+	return page.Matrix, nil
+}
+
+func (f *file) validate() error {
+	if f.Title == "" {
+		return errors.New("title is required")
+	}
+	switch f.Goal {
+	case GoalPerfectClear:
+	case GoalLines:
+		if f.Lines == 0 {
+			return errors.New("lines must be at least 1")
+		}
+	case GoalTSpin:
+		if f.Lines == 0 || f.Lines >= uint(len(tSpinNames)) {
+			return fmt.Errorf("lines must be from 1 to %d for a T-spin", len(tSpinNames)-1)
+		}
+	default:
+		return fmt.Errorf("goal must be %q, %q or %q", GoalPerfectClear, GoalLines, GoalTSpin)
+	}
+	if f.Queue == "" {
+		return errors.New("queue is required")
+	}
+	for _, v := range []byte(f.Queue) {
+		if !slices.ContainsFunc(tetris.Tetriminos, func(t tetris.Tetrimino) bool { return t.Value == v }) {
+			return fmt.Errorf("queue has invalid tetrimino %q", v)
+		}
+	}
+	return nil
+}
+
+// Objective describes the goal of the puzzle, such as "Clear the board with 4 pieces".
+func (p *Puzzle) Objective() string {
+	pieces := plural(uint(len(p.Queue)), "piece")
+	switch p.Goal {
+	case GoalLines:
+		return fmt.Sprintf("Clear %s with %s", plural(p.Lines, "line"), pieces)
+	case GoalTSpin:
+		return fmt.Sprintf("Make a %s with %s", tSpinNames[p.Lines], pieces)
+	default:
+		return fmt.Sprintf("Clear the board with %s", pieces)
+	}
+}
+
+// plural returns the count followed by the noun, which is made plural unless the count is 1.
+func plural(n uint, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Solved reports whether the goal has been reached, given the board once completed lines are removed, the total
+// number of lines cleared, and the T-spin made by the last tetrimino locked with the lines it cleared.
+func (p *Puzzle) Solved(matrix tetris.Matrix, lines uint, spin tetris.Spin, cleared uint) bool {
+	switch p.Goal {
+	case GoalPerfectClear:
+		return matrix.IsEmpty()
+	case GoalLines:
+		return lines >= p.Lines
+	case GoalTSpin:
+		return spin == tetris.SpinFull && cleared == p.Lines
+	}
+	return false
+}
@@ -0,0 +1,363 @@
+package puzzle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/fumen"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestLoad(t *testing.T) {
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("expected puzzles, got none")
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			p, err := Load(name)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if p.Name != name {
+				t.Errorf("Name: want %q, got %q", name, p.Name)
+			}
+
+			// The top of the matrix must be clear for tetriminos to spawn.
+			for row := 0; row < 22; row++ {
+				for col := range p.Matrix[row] {
+					if !p.Matrix.IsCellEmpty(row, col) {
+						t.Errorf("cell at row %d, col %d is filled", row, col)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := Load("missing")
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tetris.toml")
+	data := `
+		title = "Tetris"
+		goal = "lines"
+		lines = 4
+		queue = "I"
+		board = "XXXXXXXXX."
+	`
+	err := os.WriteFile(filename, []byte(data), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write puzzle: %v", err)
+	}
+
+	p, err := LoadFile(filename)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if p.Name != "tetris" {
+		t.Errorf("Name: want %q, got %q", "tetris", p.Name)
+	}
+	if p.Objective() != "Clear 4 lines with 1 piece" {
+		t.Errorf("Objective: got %q", p.Objective())
+	}
+}
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		name       string
+		data       string
+		expectsErr bool
+	}{
+		{
+			name: "perfect clear",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OO"
+				board = "XXX..XXXXX"
+			`,
+		},
+		{
+			name: "lines",
+			data: `
+				title = "Tetris"
+				goal = "lines"
+				lines = 4
+				queue = "I"
+				board = """
+				XXXXXXXXX.
+				XXXXXXXXX.
+				XXXXXXXXX.
+				XXXXXXXXX.
+				"""
+			`,
+		},
+		{
+			name: "t-spin",
+			data: `
+				title = "T-spin double"
+				goal = "t-spin"
+				lines = 2
+				queue = "T"
+				board = "XXXX.XXXXX"
+			`,
+		},
+		{
+			name: "fumen",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OO"
+				fumen = "v115@9gF8DeF8DeF8DeF8NeAgH"
+			`,
+		},
+		{
+			name:       "invalid TOML",
+			data:       `title = `,
+			expectsErr: true,
+		},
+		{
+			name: "unknown field",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OO"
+				pieces = 2
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "no title",
+			data: `
+				goal = "perfect-clear"
+				queue = "OO"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "invalid goal",
+			data: `
+				title = "Box out"
+				goal = "all-spin"
+				queue = "T"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "t-spin, too many lines",
+			data: `
+				title = "T-spin quad"
+				goal = "t-spin"
+				lines = 4
+				queue = "T"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "no lines",
+			data: `
+				title = "Tetris"
+				goal = "lines"
+				queue = "I"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "no queue",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "invalid queue",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OQ"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "board and fumen",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OO"
+				board = "XXX..XXXXX"
+				fumen = "v115@vhAAgH"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "invalid fumen",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OO"
+				fumen = "v115@vh"
+			`,
+			expectsErr: true,
+		},
+		{
+			name: "invalid board",
+			data: `
+				title = "Box out"
+				goal = "perfect-clear"
+				queue = "OO"
+				board = "XXX"
+			`,
+			expectsErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Parse("test", []byte(tc.data))
+
+			if tc.expectsErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if p.Name != "test" || p.Title == "" || len(p.Queue) == 0 {
+				t.Errorf("got incomplete puzzle %+v", p)
+			}
+		})
+	}
+}
+
+func TestFromFumen(t *testing.T) {
+	tt := map[string]struct {
+		fumen      string
+		wantQueue  string
+		expectsErr bool
+	}{
+		"quiz": {
+			fumen:     "v115@vhAAgWaAFLDmClcJSAVDEHBEooRBUoAVBa9aPCM+AAA",
+			wantQueue: "TZSOJLI",
+		},
+		"quiz with hold": {
+			fumen:      mustEncode(t, fumen.Page{Comment: "#Q=[I](T)SZ"}),
+			expectsErr: true,
+		},
+		"not a quiz": {
+			fumen:      "v115@9gF8DeF8DeF8DeF8NeAgH",
+			expectsErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			p, err := FromFumen(tc.fumen)
+			if tc.expectsErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if string(p.Queue) != tc.wantQueue {
+				t.Errorf("Queue: want %q, got %q", tc.wantQueue, p.Queue)
+			}
+			if p.Goal != GoalPerfectClear {
+				t.Errorf("Goal: want %q, got %q", GoalPerfectClear, p.Goal)
+			}
+		})
+	}
+}
+
+func mustEncode(t *testing.T, page fumen.Page) string {
+	t.Helper()
+	data, err := fumen.Encode(page)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	return data
+}
+
+func TestPuzzle_Solved(t *testing.T) {
+	stack := tetris.NewDefaultMatrix()
+	stack[39][0] = 'X'
+
+	tt := []struct {
+		name     string
+		puzzle   Puzzle
+		matrix   tetris.Matrix
+		lines    uint
+		spin     tetris.Spin
+		cleared  uint
+		expected bool
+	}{
+		{
+			name:     "perfect clear",
+			puzzle:   Puzzle{Goal: GoalPerfectClear},
+			lines:    2,
+			expected: true,
+		},
+		{
+			name:   "perfect clear, cells left",
+			puzzle: Puzzle{Goal: GoalPerfectClear},
+			matrix: stack,
+			lines:  2,
+		},
+		{
+			name:     "lines",
+			puzzle:   Puzzle{Goal: GoalLines, Lines: 3},
+			matrix:   stack,
+			lines:    3,
+			expected: true,
+		},
+		{
+			name:   "lines, too few",
+			puzzle: Puzzle{Goal: GoalLines, Lines: 3},
+			lines:  2,
+		},
+		{
+			name:     "t-spin",
+			puzzle:   Puzzle{Goal: GoalTSpin, Lines: 2},
+			matrix:   stack,
+			lines:    2,
+			spin:     tetris.SpinFull,
+			cleared:  2,
+			expected: true,
+		},
+		{
+			name:    "t-spin, mini",
+			puzzle:  Puzzle{Goal: GoalTSpin, Lines: 2},
+			lines:   2,
+			spin:    tetris.SpinMini,
+			cleared: 2,
+		},
+		{
+			name:    "t-spin, other lines",
+			puzzle:  Puzzle{Goal: GoalTSpin, Lines: 2},
+			lines:   3,
+			spin:    tetris.SpinFull,
+			cleared: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.puzzle.Solved(tc.matrix, tc.lines, tc.spin, tc.cleared); actual != tc.expected {
+				t.Errorf("want %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package records
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Lifetime is a player's totals across every game they have finished, in any mode.
+type Lifetime struct {
+	Games    uint          `json:"games"`
+	Lines    uint          `json:"lines"`
+	Tetrises uint          `json:"tetrises"`
+	Pieces   uint          `json:"pieces"`
+	Time     time.Duration `json:"time"`
+
+	// Modes are the totals of the games played in each mode, by the name of the mode.
+	Modes map[string]ModeTotals `json:"modes,omitempty"`
+}
+
+// ModeTotals are a player's totals across the games they have finished in a single mode.
+type ModeTotals struct {
+	Games uint          `json:"games"`
+	Lines uint          `json:"lines"`
+	Time  time.Duration `json:"time"`
+}
+
+// Game is the part of a finished game's results which counts towards the player's lifetime totals.
+type Game struct {
+	Mode     string
+	Lines    uint
+	Tetrises uint
+	Pieces   uint
+	Time     time.Duration
+}
+
+// Add adds the game to the totals.
+func (l *Lifetime) Add(g Game) {
+	l.Games++
+	l.Lines += g.Lines
+	l.Tetrises += g.Tetrises
+	l.Pieces += g.Pieces
+	l.Time += g.Time
+
+	if l.Modes == nil {
+		l.Modes = make(map[string]ModeTotals)
+	}
+	mode := l.Modes[g.Mode]
+	mode.Games++
+	mode.Lines += g.Lines
+	mode.Time += g.Time
+	l.Modes[g.Mode] = mode
+}
+
+// PPS returns the average number of tetriminos placed each second across every game.
+func (l Lifetime) PPS() float64 {
+	if l.Time <= 0 {
+		return 0
+	}
+	return float64(l.Pieces) / l.Time.Seconds()
+}
+
+// Lifetime returns the lifetime totals of the player (empty for local play), which are empty until they have finished
+// a game.
+func (s *Store) Lifetime(player string) (Lifetime, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLifetimes()
+	if err != nil {
+		return Lifetime{}, err
+	}
+	return all[player], nil
+}
+
+// AddGame adds the finished game to the lifetime totals of the player (empty for local play).
+func (s *Store) AddGame(player string, g Game) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLifetimes()
+	if err != nil {
+		return err
+	}
+	l := all[player]
+	l.Add(g)
+	all[player] = l
+	return s.writeLifetimes(all)
+}
+
+// lifetimePath returns the path of the file lifetime totals are kept in, beside the records file.
+func (s *Store) lifetimePath() string {
+	return filepath.Join(filepath.Dir(s.path), "lifetime.json")
+}
+
+func (s *Store) readLifetimes() (map[string]Lifetime, error) {
+	all := make(map[string]Lifetime)
+	data, err := os.ReadFile(s.lifetimePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read lifetime stats: %w", err)
+	}
+
+	err = json.Unmarshal(data, &all)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode lifetime stats: %w", err)
+	}
+	return all, nil
+}
+
+func (s *Store) writeLifetimes(all map[string]Lifetime) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lifetime stats: %w", err)
+	}
+
+	path := s.lifetimePath()
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create records directory: %w", err)
+	}
+	// As with records, write to a temporary file first so that the totals are not lost if writing is interrupted.
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write lifetime stats: %w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace lifetime stats: %w", err)
+	}
+	return nil
+}
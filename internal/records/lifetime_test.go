@@ -0,0 +1,53 @@
+package records
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_AddGame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tetrigo", "records.json")
+	s := NewStore(path)
+	l, err := s.Lifetime("")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if l.Games != 0 || l.PPS() != 0 {
+		t.Errorf("want no games, got %+v", l)
+	}
+
+	games := []Game{
+		{Mode: "Marathon", Lines: 40, Tetrises: 6, Pieces: 100, Time: 50 * time.Second},
+		{Mode: "Marathon", Lines: 20, Tetrises: 2, Pieces: 50, Time: 25 * time.Second},
+		{Mode: "Cheese", Lines: 15, Pieces: 30, Time: 25 * time.Second},
+	}
+	for _, g := range games {
+		err = s.AddGame("", g)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+	}
+	err = s.AddGame("alice", games[0])
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	// Totals are read from the file, so they are shared between stores.
+	l, err = NewStore(path).Lifetime("")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if l.Games != 3 || l.Lines != 75 || l.Tetrises != 8 || l.Pieces != 180 || l.Time != 100*time.Second {
+		t.Errorf("want the totals of all 3 local games, got %+v", l)
+	}
+	if l.PPS() != 1.8 {
+		t.Errorf("PPS: want 1.8, got %v", l.PPS())
+	}
+	if marathon := l.Modes["Marathon"]; marathon.Games != 2 || marathon.Lines != 60 {
+		t.Errorf("want 2 marathon games clearing 60 lines, got %+v", marathon)
+	}
+	if alice, _ := s.Lifetime("alice"); alice.Games != 1 {
+		t.Errorf("want 1 game for alice, got %d", alice.Games)
+	}
+}
@@ -0,0 +1,212 @@
+// Package records keeps each player's best results, so games can tell when a personal best is set.
+package records
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record is the result of a single game.
+type Record struct {
+	Score uint          `json:"score"`
+	Lines uint          `json:"lines"`
+	Time  time.Duration `json:"time"`
+	Date  time.Time     `json:"date"`
+
+	// Race is whether the game was to clear a number of lines, so a faster time is better rather than a higher score.
+	Race bool `json:"race,omitempty"`
+
+	// Checkpoints are the progress of the game each time another CheckpointLines lines were cleared, so that later
+	// games can be compared with it as they are played.
+	Checkpoints []Checkpoint `json:"checkpoints,omitempty"`
+	// BestSplits are the fastest time taken for each section between checkpoints in any game of the mode, whether or
+	// not the game was a personal best.
+	BestSplits []time.Duration `json:"best_splits,omitempty"`
+	// History is the splits of the latest games of the mode which passed a checkpoint, oldest first, whether or not
+	// they were personal bests, so that runs can be compared with each other. At most HistoryRuns are kept.
+	History []Run `json:"history,omitempty"`
+}
+
+// HistoryRuns is the number of the latest games whose splits are kept in a record's history.
+const HistoryRuns = 10
+
+// Run is the splits of a single game, from Splits.
+type Run struct {
+	Date   time.Time       `json:"date"`
+	Splits []time.Duration `json:"splits"`
+}
+
+// CheckpointLines is the number of lines cleared between each checkpoint of a record.
+const CheckpointLines = 10
+
+// Checkpoint is the progress of a game once it had cleared a number of lines.
+type Checkpoint struct {
+	Lines uint          `json:"lines"`
+	Time  time.Duration `json:"time"`
+	Score uint          `json:"score"`
+}
+
+// Splits returns the time taken for each section of a game, from the start or the checkpoint before to each checkpoint.
+func Splits(checkpoints []Checkpoint) []time.Duration {
+	if len(checkpoints) == 0 {
+		return nil
+	}
+	splits := make([]time.Duration, len(checkpoints))
+	var last time.Duration
+	for i, c := range checkpoints {
+		splits[i] = c.Time - last
+		last = c.Time
+	}
+	return splits
+}
+
+// fastestSplits returns the faster of the two times for each section, including the sections only one of them has.
+func fastestSplits(a, b []time.Duration) []time.Duration {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	fastest := slices.Clone(a)
+	for i, split := range b {
+		fastest[i] = min(fastest[i], split)
+	}
+	return fastest
+}
+
+// Beats reports whether the record is better than the other.
+func (r Record) Beats(other Record) bool {
+	if r.Race {
+		return r.Time < other.Time
+	}
+	return r.Score > other.Score
+}
+
+// Store is a file of the best record for each mode. It is safe to use from multiple goroutines, such as for the
+// sessions of an SSH server.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultPath returns the location of the records file in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "records.json"), nil
+}
+
+// NewStore creates a store which keeps records in the file at the given path. The file is created when the first
+// record is submitted.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Key returns the key records for the mode are kept under for the player (empty for local play).
+func Key(player, mode string) string {
+	if player == "" {
+		return mode
+	}
+	return player + "/" + mode
+}
+
+// Best returns the best record kept under the key, or false if there is none.
+func (s *Store) Best(key string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return Record{}, false, err
+	}
+	r, ok := records[key]
+	return r, ok, nil
+}
+
+// All returns every record kept, by key.
+func (s *Store) All() (map[string]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Submit keeps the record under the key if it beats the best record so far, and reports whether it did. The best
+// splits and the history kept under the key are updated with the record's splits either way.
+func (s *Store) Submit(key string, r Record) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Read the file again in case another game has set a record since.
+	records, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	best, ok := records[key]
+	splits := Splits(r.Checkpoints)
+	r.BestSplits = fastestSplits(best.BestSplits, splits)
+	r.History = best.History
+	if len(splits) > 0 {
+		r.History = append(slices.Clone(best.History), Run{Date: r.Date, Splits: splits})
+		r.History = r.History[max(len(r.History)-HistoryRuns, 0):]
+	}
+	if ok && !r.Beats(best) {
+		if len(splits) == 0 {
+			return false, nil
+		}
+		best.BestSplits, best.History = r.BestSplits, r.History
+		records[key] = best
+		return false, s.write(records)
+	}
+
+	records[key] = r
+	err = s.write(records)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) read() (map[string]Record, error) {
+	records := make(map[string]Record)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode records: %w", err)
+	}
+	return records, nil
+}
+
+func (s *Store) write(records map[string]Record) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode records: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create records directory: %w", err)
+	}
+	// Write to a temporary file first so that the records are not lost if writing is interrupted.
+	tmp := s.path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	err = os.Rename(tmp, s.path)
+	if err != nil {
+		return fmt.Errorf("failed to replace records: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package records
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore_Submit(t *testing.T) {
+	tt := []struct {
+		name           string
+		first, second  Record
+		expectsNewBest bool
+	}{
+		{"higher score", Record{Score: 100}, Record{Score: 200}, true},
+		{"lower score", Record{Score: 200}, Record{Score: 100}, false},
+		{"equal score", Record{Score: 100}, Record{Score: 100}, false},
+		{"faster race", Record{Time: time.Minute, Race: true}, Record{Time: 50 * time.Second, Race: true}, true},
+		{"slower race", Record{Time: time.Minute, Race: true}, Record{Time: 70 * time.Second, Race: true}, false},
+		{
+			"checkpoints",
+			Record{Time: time.Minute, Race: true, Checkpoints: []Checkpoint{{Lines: 10, Time: 20 * time.Second, Score: 400}}},
+			Record{
+				Time:        50 * time.Second,
+				Race:        true,
+				Checkpoints: []Checkpoint{{Lines: 10, Time: 15 * time.Second, Score: 500}},
+				BestSplits:  []time.Duration{15 * time.Second},
+				History:     []Run{{Splits: []time.Duration{20 * time.Second}}, {Splits: []time.Duration{15 * time.Second}}},
+			},
+			true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewStore(filepath.Join(t.TempDir(), "tetrigo", "records.json"))
+
+			isBest, err := s.Submit("mode", tc.first)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !isBest {
+				t.Errorf("First: expected a new best")
+			}
+
+			isBest, err = s.Submit("mode", tc.second)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if isBest != tc.expectsNewBest {
+				t.Errorf("Second: want %t, got %t", tc.expectsNewBest, isBest)
+			}
+
+			expected := tc.first
+			if tc.expectsNewBest {
+				expected = tc.second
+			}
+			best, ok, err := s.Best("mode")
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !ok || !reflect.DeepEqual(best, expected) {
+				t.Errorf("Best: want %v, got %v", expected, best)
+			}
+		})
+	}
+}
+
+func TestStore_Submit_BestSplits(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "records.json"))
+	first := Record{Time: time.Minute, Race: true, Checkpoints: []Checkpoint{
+		{Lines: 10, Time: 20 * time.Second},
+		{Lines: 20, Time: 40 * time.Second},
+	}}
+	// The second game is slower overall, but faster through its second section.
+	second := Record{Time: 70 * time.Second, Race: true, Checkpoints: []Checkpoint{
+		{Lines: 10, Time: 25 * time.Second},
+		{Lines: 20, Time: 40 * time.Second},
+		{Lines: 30, Time: 55 * time.Second},
+	}}
+
+	for _, r := range []Record{first, second} {
+		_, err := s.Submit("lines-40", r)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+	}
+
+	best, _, err := s.Best("lines-40")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if best.Time != first.Time {
+		t.Errorf("Time: want %v, got %v", first.Time, best.Time)
+	}
+	want := []time.Duration{20 * time.Second, 15 * time.Second, 15 * time.Second}
+	if !reflect.DeepEqual(best.BestSplits, want) {
+		t.Errorf("BestSplits: want %v, got %v", want, best.BestSplits)
+	}
+}
+
+func TestStore_Submit_History(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "records.json"))
+	for i := 0; i < HistoryRuns+2; i++ {
+		// Each game is slower than the one before, so only the first is a personal best.
+		r := Record{Time: time.Minute + time.Duration(i)*time.Second, Race: true, Checkpoints: []Checkpoint{
+			{Lines: 10, Time: time.Duration(20+i) * time.Second},
+		}}
+		_, err := s.Submit("lines-40", r)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+	}
+	// A game which passed no checkpoints leaves the history alone.
+	_, err := s.Submit("lines-40", Record{Time: 2 * time.Minute, Race: true})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	best, _, err := s.Best("lines-40")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if len(best.History) != HistoryRuns {
+		t.Fatalf("want %d runs, got %d", HistoryRuns, len(best.History))
+	}
+	if first, last := best.History[0].Splits[0], best.History[HistoryRuns-1].Splits[0]; first != 22*time.Second ||
+		last != time.Duration(21+HistoryRuns)*time.Second {
+		t.Errorf("want the latest runs, oldest first, got splits from %v to %v", first, last)
+	}
+}
+
+func TestSplits(t *testing.T) {
+	got := Splits([]Checkpoint{{Lines: 10, Time: 20 * time.Second}, {Lines: 20, Time: 45 * time.Second}})
+	want := []time.Duration{20 * time.Second, 25 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if got := Splits(nil); got != nil {
+		t.Errorf("want nil, got %v", got)
+	}
+}
+
+func TestStore_Keys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	_, err := NewStore(path).Submit(Key("alice", "marathon"), Record{Score: 100})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	// Records are read from the file, so they are shared between stores.
+	s := NewStore(path)
+	if _, ok, _ := s.Best(Key("alice", "marathon")); !ok {
+		t.Errorf("expected a record for alice")
+	}
+	if _, ok, _ := s.Best(Key("", "marathon")); ok {
+		t.Errorf("expected no record for local play")
+	}
+}
+
+func TestStore_All(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "records.json"))
+	all, err := s.All()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("want no records, got %v", all)
+	}
+
+	_, _ = s.Submit("marathon-level-1", Record{Score: 100})
+	_, _ = s.Submit("lines-40", Record{Time: time.Minute, Race: true})
+	all, err = s.All()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if len(all) != 2 || all["marathon-level-1"].Score != 100 || all["lines-40"].Time != time.Minute {
+		t.Errorf("want both records, got %v", all)
+	}
+}
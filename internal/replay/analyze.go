@@ -0,0 +1,130 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is an analysis of a replay, for the whole game and for each section of it.
+type Report struct {
+	Mode string `json:"mode"`
+	Seed int64  `json:"seed"`
+	Stats
+
+	// Sections break the game down by the lines cleared, such as every 10 lines. The last may be cut short by the end
+	// of the game.
+	Sections []Section `json:"sections"`
+}
+
+// Section is the analysis of part of a game.
+type Section struct {
+	Number int           `json:"number"` // from 1
+	Start  time.Duration `json:"start"`  // time played when the section started
+	Stats
+}
+
+// Stats describe how a game, or part of it, was played.
+type Stats struct {
+	Time     time.Duration  `json:"time"`   // time played
+	Pieces   int            `json:"pieces"` // tetriminos placed
+	Lines    uint           `json:"lines"`
+	PPS      float64        `json:"pps"`            // pieces placed per second
+	Faults   int            `json:"finesse_faults"` // placements made with more inputs than needed
+	Clears   map[string]int `json:"clears"`         // number of each action announced, such as "TETRIS"
+	MaxCombo int            `json:"max_combo"`      // longest run of consecutive tetriminos clearing lines
+}
+
+// add counts the placement towards the stats.
+func (s *Stats) add(p Placement, fault bool) {
+	s.Pieces++
+	s.Lines += p.Lines
+	if fault {
+		s.Faults++
+	}
+	if p.Clear != "" {
+		s.Clears[p.Clear]++
+	}
+	s.MaxCombo = max(s.MaxCombo, p.Combo)
+}
+
+// finish sets the time played and the rate of play once every placement has been added.
+func (s *Stats) finish(d time.Duration) {
+	s.Time = d
+	if d > 0 {
+		s.PPS = float64(s.Pieces) / d.Seconds()
+	}
+}
+
+// Analyze breaks the replay down into sections of the given number of lines cleared, and analyses each of them and
+// the game as a whole.
+func Analyze(r *Replay, sectionLines uint) (*Report, error) {
+	if sectionLines == 0 {
+		return nil, errors.New("sections must be at least 1 line")
+	}
+
+	report := &Report{Mode: r.Mode, Seed: r.Seed, Stats: Stats{Clears: make(map[string]int)}}
+	section := Section{Number: 1, Stats: Stats{Clears: make(map[string]int)}}
+	var end time.Duration
+	for _, p := range r.Placements {
+		fault := r.IsFault(p)
+		report.add(p, fault)
+		section.add(p, fault)
+		end = p.Time
+
+		if report.Lines >= uint(section.Number)*sectionLines {
+			section.finish(end - section.Start)
+			report.Sections = append(report.Sections, section)
+			section = Section{Number: section.Number + 1, Start: end, Stats: Stats{Clears: make(map[string]int)}}
+		}
+	}
+	if section.Pieces > 0 {
+		section.finish(end - section.Start)
+		report.Sections = append(report.Sections, section)
+	}
+	report.finish(end)
+	return report, nil
+}
+
+// WriteText writes the report as text: a summary of the game followed by a table of the sections.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Mode:\t%s\n", r.Mode)
+	fmt.Fprintf(tw, "Seed:\t%d\n", r.Seed)
+	fmt.Fprintf(tw, "Time:\t%s\n", r.Time.Round(10*time.Millisecond))
+	fmt.Fprintf(tw, "Pieces:\t%d (%.2f PPS)\n", r.Pieces, r.PPS)
+	fmt.Fprintf(tw, "Lines:\t%d\n", r.Lines)
+	fmt.Fprintf(tw, "Finesse faults:\t%d\n", r.Faults)
+	fmt.Fprintf(tw, "Max combo:\t%d\n", r.MaxCombo)
+	fmt.Fprintf(tw, "Clears:\t%s\n", clearsText(r.Clears))
+	if len(r.Sections) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SECTION\tSTART\tTIME\tPIECES\tPPS\tLINES\tFAULTS\tCOMBO\tCLEARS")
+		for _, s := range r.Sections {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%.2f\t%d\t%d\t%d\t%s\n", s.Number, s.Start.Round(10*time.Millisecond),
+				s.Time.Round(10*time.Millisecond), s.Pieces, s.PPS, s.Lines, s.Faults, s.MaxCombo, clearsText(s.Clears))
+		}
+	}
+	return tw.Flush()
+}
+
+// clearsText lists the number of each action, such as "SINGLE 3, TETRIS 1", in alphabetical order.
+func clearsText(clears map[string]int) string {
+	if len(clears) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(clears))
+	for name := range clears {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, clears[name])
+	}
+	return strings.Join(parts, ", ")
+}
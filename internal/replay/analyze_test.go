@@ -0,0 +1,94 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// spawnCells returns the cells of the tetrimino where it spawns, shifted by the given number of columns.
+func spawnCells(t *testing.T, value byte, cols int) []tetris.Coordinate {
+	t.Helper()
+	for _, tet := range tetris.Tetriminos {
+		if tet.Value != value {
+			continue
+		}
+		c := tet.Copy()
+		offset := tetris.NewMatrix(10, 20).SpawnOffset()
+		c.Pos.X += offset.X + cols
+		c.Pos.Y += offset.Y
+		return cells(c)
+	}
+	t.Fatalf("no tetrimino with value %c", value)
+	return nil
+}
+
+func TestReplay_IsFault(t *testing.T) {
+	tt := []struct {
+		name   string
+		cols   int
+		inputs []string
+		want   bool
+	}{
+		{"straight down", 0, []string{"hard drop"}, false},
+		{"one tap", -1, []string{"left", "hard drop"}, false},
+		{"back and forth", 0, []string{"left", "right", "hard drop"}, true},
+		{"taps to the wall", -4, []string{"left", "left", "left", "left", "hard drop"}, true},
+		{"held to the wall", -4, []string{"left", "hard drop"}, false},
+		{"inputs before hold", -1, []string{"right", "right", "hold", "left", "hard drop"}, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Replay{Width: 10, Height: 20}
+			p := Placement{Piece: "O", Cells: spawnCells(t, 'O', tc.cols), Inputs: tc.inputs}
+
+			got := r.IsFault(p)
+			if got != tc.want {
+				t.Errorf("want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	r := &Replay{Mode: "Marathon", Seed: 7, Width: 10, Height: 20}
+	for i := 1; i <= 5; i++ {
+		p := Placement{Piece: "O", Cells: spawnCells(t, 'O', 0), Time: time.Duration(i) * time.Second, Inputs: []string{"hard drop"}}
+		if i%2 == 0 {
+			p.Clear, p.Lines, p.Combo = "DOUBLE", 2, i/2
+		}
+		r.Placements = append(r.Placements, p)
+	}
+	r.Placements[4].Inputs = []string{"left", "right", "hard drop"}
+
+	report, err := Analyze(r, 2)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	if report.Pieces != 5 || report.Lines != 4 || report.Faults != 1 || report.MaxCombo != 2 || report.Clears["DOUBLE"] != 2 {
+		t.Errorf("want 5 pieces, 4 lines, 1 fault, combo 2 and 2 doubles, got %+v", report.Stats)
+	}
+	if report.PPS != 1 {
+		t.Errorf("want 1 PPS, got %v", report.PPS)
+	}
+	if len(report.Sections) != 3 {
+		t.Fatalf("want 3 sections, got %d", len(report.Sections))
+	}
+	wantPieces := []int{2, 2, 1}
+	wantStarts := []time.Duration{0, 2 * time.Second, 4 * time.Second}
+	for i, s := range report.Sections {
+		if s.Number != i+1 || s.Pieces != wantPieces[i] || s.Start != wantStarts[i] {
+			t.Errorf("Section %d: want %d pieces from %v, got %d from %v", i+1, wantPieces[i], wantStarts[i], s.Pieces, s.Start)
+		}
+	}
+}
+
+func TestAnalyze_ZeroSection(t *testing.T) {
+	_, err := Analyze(&Replay{Width: 10, Height: 20}, 0)
+	if err == nil {
+		t.Errorf("expected an error, got nil")
+	}
+}
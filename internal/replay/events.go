@@ -0,0 +1,131 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// The types of event written to an event log.
+const (
+	EventInput = "input" // a move made by the player
+	EventSpawn = "spawn" // a tetrimino spawning at the top of the matrix
+	EventLock  = "lock"  // a tetrimino locking onto the stack
+	EventClear = "clear" // lines cleared, or an action scored without clearing any, by the tetrimino which just locked
+)
+
+// Event is a line of an event log: something which happened during a game, as it happened.
+type Event struct {
+	Type  string              `json:"type"`            // one of the Event types, such as EventLock
+	Time  time.Duration       `json:"time"`            // time played when it happened, in nanoseconds
+	Input string              `json:"input,omitempty"` // the move made, such as "left", for inputs
+	Piece string              `json:"piece,omitempty"` // value of the tetrimino, such as "T", for spawns and locks
+	Cells []tetris.Coordinate `json:"cells,omitempty"` // cells of the matrix the tetrimino is in, for spawns and locks
+	Clear string              `json:"clear,omitempty"` // the action announced, such as "TETRIS", for clears
+	Lines uint                `json:"lines,omitempty"` // lines cleared, for clears
+	Score uint                `json:"score,omitempty"` // total score afterwards, for clears
+}
+
+// EventLog writes the events of a game as they happen, as JSON lines, for analysis tools and bots to read while it is
+// played or afterwards. Unlike a replay, which is written once the game ends, every input is logged, including those
+// which don't end up moving the tetrimino.
+//
+// Its methods do nothing on a nil log, so games without one needn't check. Writing stops at the first error, which
+// Err returns.
+type EventLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	err    error
+}
+
+// CreateEventLog creates the file at the path, replacing any file there already, to write an event log to.
+func CreateEventLog(path string) (*EventLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event log: %w", err)
+	}
+	log := NewEventLog(f)
+	log.closer = f
+	return log, nil
+}
+
+// NewEventLog creates an event log which writes to w.
+func NewEventLog(w io.Writer) *EventLog {
+	return &EventLog{w: w}
+}
+
+// Input logs a move made at the time.
+func (l *EventLog) Input(at time.Duration, move tetris.Move) {
+	l.write(Event{Type: EventInput, Time: at, Input: move.String()})
+}
+
+// Spawn logs the tetrimino spawning where it is at the time.
+func (l *EventLog) Spawn(at time.Duration, t *tetris.Tetrimino) {
+	l.write(Event{Type: EventSpawn, Time: at, Piece: string(t.Value), Cells: cells(t)})
+}
+
+// Lock logs the tetrimino locking where it is at the time, before any lines it completes are cleared.
+func (l *EventLog) Lock(at time.Duration, t *tetris.Tetrimino) {
+	l.write(Event{Type: EventLock, Time: at, Piece: string(t.Value), Cells: cells(t)})
+}
+
+// Clear logs the lines cleared at the time, the action announced for them and the score once they were.
+func (l *EventLog) Clear(at time.Duration, action string, lines, score uint) {
+	l.write(Event{Type: EventClear, Time: at, Clear: action, Lines: lines, Score: score})
+}
+
+// Err returns the error which stopped the log being written, if any.
+func (l *EventLog) Err() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Close closes the file the log is written to, if it created one, returning the first error writing the log.
+func (l *EventLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closer != nil {
+		err := l.closer.Close()
+		if l.err == nil {
+			l.err = err
+		}
+	}
+	return l.err
+}
+
+// write adds the event to the log as a line of JSON. Each is written straight away, so that tools reading the log as
+// the game is played see events as they happen.
+func (l *EventLog) write(e Event) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		l.err = fmt.Errorf("failed to encode event: %w", err)
+		return
+	}
+	data = append(data, '\n')
+	_, err = l.w.Write(data)
+	if err != nil {
+		l.err = fmt.Errorf("failed to write event log: %w", err)
+	}
+}
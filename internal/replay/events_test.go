@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestEventLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewEventLog(&buf)
+	piece := tetris.Tetriminos[0]
+	log.Spawn(0, &piece)
+	log.Input(time.Second, tetris.MoveHardDrop)
+	log.Lock(time.Second, &piece)
+	log.Clear(time.Second, "SINGLE", 1, 100)
+	err := log.Close()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	var got []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		err := json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		got = append(got, e)
+	}
+	want := []string{EventSpawn, EventInput, EventLock, EventClear}
+	if len(got) != len(want) {
+		t.Fatalf("want %d events, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Type != want[i] {
+			t.Errorf("Event %d: want type %q, got %q", i, want[i], got[i].Type)
+		}
+	}
+	if got[1].Input != tetris.MoveHardDrop.String() || got[1].Time != time.Second {
+		t.Errorf("want a hard drop at 1s, got %+v", got[1])
+	}
+	if got[2].Piece != "I" || len(got[2].Cells) != 4 {
+		t.Errorf("want the four cells of an I locked, got %+v", got[2])
+	}
+	if got[3].Lines != 1 || got[3].Score != 100 {
+		t.Errorf("want 1 line cleared for a score of 100, got %+v", got[3])
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestEventLog_Err(t *testing.T) {
+	log := NewEventLog(failingWriter{})
+	log.Input(0, tetris.MoveLeft)
+	if log.Err() == nil {
+		t.Errorf("expected error, got nil")
+	}
+
+	var none *EventLog
+	none.Input(0, tetris.MoveLeft)
+	if none.Err() != nil {
+		t.Errorf("expected nil from a nil log, got %v", none.Err())
+	}
+}
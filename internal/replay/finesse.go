@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// maxFinesse is the most inputs searched for a placement. Every placement reachable from the top of an empty matrix
+// can be made in fewer.
+const maxFinesse = 8
+
+// move is an input counted towards finesse: a tap of a movement or rotation key, or holding a movement key to the wall.
+type move func(t *tetris.Tetrimino, m *tetris.Matrix) (bool, error)
+
+var finesseMoves = []move{
+	(*tetris.Tetrimino).MoveLeft,
+	(*tetris.Tetrimino).MoveRight,
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (bool, error) { return t.Rotate(m, true) },
+	func(t *tetris.Tetrimino, m *tetris.Matrix) (bool, error) { return t.Rotate(m, false) },
+	toWall((*tetris.Tetrimino).MoveLeft),
+	toWall((*tetris.Tetrimino).MoveRight),
+}
+
+// toWall repeats the move for as long as the tetrimino keeps moving.
+func toWall(step move) move {
+	return func(t *tetris.Tetrimino, m *tetris.Matrix) (bool, error) {
+		var moved bool
+		for {
+			ok, err := step(t, m)
+			if err != nil || !ok {
+				return moved, err
+			}
+			moved = true
+		}
+	}
+}
+
+// minimumInputs returns the fewest moves and rotations which place the tetrimino in the given columns and
+// orientation from where it spawns, on an empty matrix of the given size. Only the shape and columns of the cells are
+// compared, since the tetrimino can be dropped from any height. It returns false if the placement can't be reached
+// that way, such as a tuck or a spin under an overhang.
+func minimumInputs(width, height int, piece byte, target []tetris.Coordinate) (int, bool) {
+	i := slices.IndexFunc(tetris.Tetriminos, func(t tetris.Tetrimino) bool { return t.Value == piece })
+	if i == -1 {
+		return 0, false
+	}
+	empty := tetris.NewMatrix(width, height)
+	start := tetris.Tetriminos[i].Copy()
+	offset := empty.SpawnOffset()
+	start.Pos.X += offset.X
+	start.Pos.Y += offset.Y
+
+	want := footprint(target)
+	type state struct {
+		x, y, rotation int
+	}
+	seen := map[state]bool{{start.Pos.X, start.Pos.Y, start.CurrentRotation}: true}
+	current := []*tetris.Tetrimino{start}
+	for inputs := 0; inputs <= maxFinesse; inputs++ {
+		var next []*tetris.Tetrimino
+		for _, t := range current {
+			if footprint(cells(t)) == want {
+				return inputs, true
+			}
+			for _, mv := range finesseMoves {
+				c := t.Copy()
+				m := tetris.NewMatrix(width, height)
+				if m.AddTetrimino(c) != nil {
+					continue
+				}
+				moved, err := mv(c, &m)
+				if err != nil || !moved {
+					continue
+				}
+				s := state{c.Pos.X, c.Pos.Y, c.CurrentRotation}
+				if !seen[s] {
+					seen[s] = true
+					next = append(next, c)
+				}
+			}
+		}
+		current = next
+	}
+	return 0, false
+}
+
+// footprint describes the shape and columns of the cells, ignoring how high they are.
+func footprint(cells []tetris.Coordinate) string {
+	if len(cells) == 0 {
+		return ""
+	}
+	top := cells[0].Y
+	for _, c := range cells {
+		top = min(top, c.Y)
+	}
+	shape := make([]tetris.Coordinate, len(cells))
+	for i, c := range cells {
+		shape[i] = tetris.Coordinate{X: c.X, Y: c.Y - top}
+	}
+	slices.SortFunc(shape, func(a, b tetris.Coordinate) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return fmt.Sprint(shape)
+}
+
+// finesseInputs returns the number of moves and rotations made to place the tetrimino, after the last hold.
+func finesseInputs(inputs []string) int {
+	var n int
+	for _, name := range inputs {
+		m, err := tetris.ParseMove(name)
+		if err != nil {
+			continue
+		}
+		switch m {
+		case tetris.MoveLeft, tetris.MoveRight, tetris.MoveClockwise, tetris.MoveCounterClockwise:
+			n++
+		case tetris.MoveHold:
+			n = 0
+		}
+	}
+	return n
+}
+
+// IsFault reports whether the placement was made with more moves and rotations than needed. Placements which can't be
+// made from above, such as tucks and spins, are never faults.
+func (r *Replay) IsFault(p Placement) bool {
+	if len(p.Piece) != 1 {
+		return false
+	}
+	least, ok := minimumInputs(r.Width, r.Height, p.Piece[0], p.Cells)
+	return ok && finesseInputs(p.Inputs) > least
+}
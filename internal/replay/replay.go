@@ -0,0 +1,214 @@
+// Package replay records how each tetrimino of a game was placed, so that the game can be analysed afterwards.
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Replay is a record of a finished game: the settings it was played with and every placement made.
+type Replay struct {
+	Mode   string    `json:"mode"` // name of the mode, such as "Sprint (40 lines)"
+	Seed   int64     `json:"seed"`
+	Level  uint      `json:"level"`
+	Width  int       `json:"width"`  // columns of the matrix
+	Height int       `json:"height"` // visible rows of the matrix
+	Date   time.Time `json:"date"`   // when the game ended
+
+	// Board is the matrix the game started from, such as a preset (nil for an empty one).
+	Board tetris.Matrix `json:"board,omitempty"`
+
+	// Randomizer is the name of the randomizer which dealt the tetriminos (see tetris.RandomizerNames), or empty if
+	// they weren't dealt by one, such as in puzzles. Hold is the name of the hold rule (empty for "once"), and Classic
+	// whether the game was scored as in the NES version. Verify needs them to play the game again.
+	Randomizer string `json:"randomizer,omitempty"`
+	Hold       string `json:"hold,omitempty"`
+	Classic    bool   `json:"classic,omitempty"`
+
+	// Mirrored is whether the game has been flipped from left to right (see Mirror), so that each tetrimino placed is
+	// the mirror image of the one dealt.
+	Mirrored bool `json:"mirrored,omitempty"`
+
+	// Score and Lines are the results the game ended with.
+	Score uint `json:"score"`
+	Lines uint `json:"lines"`
+
+	Placements []Placement `json:"placements"`
+
+	// Garbage is the garbage which rose from the bottom of the matrix during play, in the order it rose. Garbage the
+	// game started with is part of Board.
+	Garbage []Garbage `json:"garbage,omitempty"`
+}
+
+// Placement is a tetrimino locking onto the stack.
+type Placement struct {
+	Piece  string              `json:"piece"`           // value of the tetrimino, such as "T"
+	Cells  []tetris.Coordinate `json:"cells"`           // cells of the matrix it locked in, before any lines were cleared
+	Time   time.Duration       `json:"time"`            // time played when it locked
+	Inputs []string            `json:"inputs"`          // moves made from when the last tetrimino locked, such as "left"
+	Clear  string              `json:"clear,omitempty"` // the action announced, such as "TETRIS" (empty if none)
+	Lines  uint                `json:"lines,omitempty"` // lines it cleared
+	Combo  int                 `json:"combo,omitempty"` // consecutive tetriminos which have cleared lines, including it
+	Drop   uint                `json:"drop,omitempty"`  // points scored dropping it, by soft and hard drops
+}
+
+// Garbage is lines of garbage rising from the bottom of the matrix, pushing the stack up.
+type Garbage struct {
+	After     int           `json:"after"`                // placements made before it rose
+	Time      time.Duration `json:"time"`                 // time played when it rose
+	Holes     []int         `json:"holes"`                // column of the hole in each line, from the top one down
+	HoleWidth int           `json:"hole_width,omitempty"` // columns each hole spans from the column given (0 for 1)
+}
+
+// Raise adds the garbage to the bottom of the matrix, as it rose in the game. It returns true if the stack was pushed
+// out of the top of the matrix (top out).
+func (g Garbage) Raise(matrix tetris.Matrix) bool {
+	return matrix.AddWideGarbage(g.Holes, max(g.HoleWidth, 1))
+}
+
+// New starts a replay of a game on the matrix, before the first tetrimino has spawned.
+func New(mode string, seed int64, level uint, matrix tetris.Matrix) *Replay {
+	r := &Replay{
+		Mode:   mode,
+		Seed:   seed,
+		Level:  level,
+		Width:  len(matrix[0]),
+		Height: matrix.VisibleRows(),
+	}
+	if !matrix.IsEmpty() {
+		r.Board = matrix.Clone()
+	}
+	return r
+}
+
+// Add records the tetrimino locking where it is in the matrix, having been placed with the given moves and scored the
+// given points dropping it.
+func (r *Replay) Add(t *tetris.Tetrimino, at time.Duration, moves []tetris.Move, drop uint) {
+	p := Placement{
+		Piece:  string(t.Value),
+		Cells:  cells(t),
+		Time:   at,
+		Inputs: make([]string, len(moves)),
+		Drop:   drop,
+	}
+	for i, m := range moves {
+		p.Inputs[i] = m.String()
+	}
+	r.Placements = append(r.Placements, p)
+}
+
+// cells returns the cells of the matrix the tetrimino occupies.
+func cells(t *tetris.Tetrimino) []tetris.Coordinate {
+	return tetris.NewPiece(t).Cells
+}
+
+// AddGarbage records lines of garbage rising with the given holes, each spanning holeWidth columns, after the
+// placements made so far.
+func (r *Replay) AddGarbage(at time.Duration, holes []int, holeWidth int) {
+	r.Garbage = append(r.Garbage, Garbage{
+		After:     len(r.Placements),
+		Time:      at,
+		Holes:     slices.Clone(holes),
+		HoleWidth: holeWidth,
+	})
+}
+
+// Cleared records the lines cleared by the latest placement, the action announced for it and the combo it continued.
+func (r *Replay) Cleared(action string, lines uint, combo int) {
+	if len(r.Placements) == 0 {
+		return
+	}
+	p := &r.Placements[len(r.Placements)-1]
+	p.Clear, p.Lines, p.Combo = action, lines, combo
+}
+
+// Mirror flips the game from left to right, as if it had been played in a mirror: the board it started from, the cells
+// of each placement and the holes of the garbage are flipped, S and Z and J and L are swapped, and so are moves to the
+// left and right and the two directions of rotation. Mirroring a replay again flips it back.
+func (r *Replay) Mirror() {
+	if r.Board != nil {
+		r.Board.Mirror()
+	}
+	for i := range r.Placements {
+		p := &r.Placements[i]
+		if len(p.Piece) == 1 {
+			p.Piece = string(tetris.MirrorValue(p.Piece[0]))
+		}
+		for j := range p.Cells {
+			p.Cells[j].X = r.Width - 1 - p.Cells[j].X
+		}
+		for j, input := range p.Inputs {
+			m, err := tetris.ParseMove(input)
+			if err == nil {
+				p.Inputs[j] = m.Mirror().String()
+			}
+		}
+	}
+	for _, g := range r.Garbage {
+		for j := range g.Holes {
+			g.Holes[j] = r.Width - g.Holes[j] - max(g.HoleWidth, 1)
+		}
+	}
+	r.Mirrored = !r.Mirrored
+}
+
+// Read returns the replay in the file at the path.
+func Read(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %w", err)
+	}
+
+	var r Replay
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %w", err)
+	}
+	if r.Width < 4 || r.Width > tetris.MaxWidth || r.Height <= 0 || r.Height > tetris.MaxHeight {
+		return nil, errors.New("replay has an invalid matrix size")
+	}
+	if r.Board != nil && (len(r.Board) != r.Height*2 || len(r.Board[0]) != r.Width) {
+		return nil, errors.New("replay has a starting board of the wrong size")
+	}
+	return &r, nil
+}
+
+// Write saves the replay to the file at the path, replacing any file there already.
+func Write(path string, r *Replay) error {
+	data, err := r.encode()
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write replay: %w", err)
+	}
+	return nil
+}
+
+// Hash returns the SHA-256 of the replay as Write saves it, in hex, so that results submitted with the hash can be
+// matched to the replay of the game.
+func (r *Replay) Hash() (string, error) {
+	data, err := r.encode()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (r *Replay) encode() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode replay: %w", err)
+	}
+	return data, nil
+}
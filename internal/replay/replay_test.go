@@ -0,0 +1,131 @@
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.json")
+	r := New("Marathon", 42, 3, tetris.NewMatrix(10, 20))
+	tet := tetris.Tetriminos[1].Copy()
+	r.Add(tet, time.Second, []tetris.Move{tetris.MoveLeft, tetris.MoveHardDrop}, 0)
+	r.Cleared("SINGLE", 1, 1)
+
+	err := Write(path, r)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	if got.Mode != "Marathon" || got.Seed != 42 || got.Level != 3 || got.Width != 10 || got.Height != 20 {
+		t.Errorf("want settings of the game written, got %+v", got)
+	}
+	if len(got.Placements) != 1 {
+		t.Fatalf("want 1 placement, got %d", len(got.Placements))
+	}
+	p := got.Placements[0]
+	if p.Piece != "O" || len(p.Cells) != 4 || p.Time != time.Second || p.Clear != "SINGLE" || p.Lines != 1 || p.Combo != 1 {
+		t.Errorf("want placement written, got %+v", p)
+	}
+	if len(p.Inputs) != 2 || p.Inputs[0] != "left" || p.Inputs[1] != "hard drop" {
+		t.Errorf("want inputs [left hard drop], got %v", p.Inputs)
+	}
+}
+
+func TestReplay_Hash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.json")
+	r := New("Marathon", 42, 3, tetris.NewMatrix(10, 20))
+	r.Add(tetris.Tetriminos[1].Copy(), time.Second, []tetris.Move{tetris.MoveHardDrop}, 0)
+
+	hash, err := r.Hash()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	err = Write(path, r)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if want := hex.EncodeToString(sum[:]); hash != want {
+		t.Errorf("want the hash of the file written, %s, got %s", want, hash)
+	}
+
+	r.Add(tetris.Tetriminos[2].Copy(), 2*time.Second, []tetris.Move{tetris.MoveHardDrop}, 0)
+	changed, err := r.Hash()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if changed == hash {
+		t.Errorf("want the hash to change with the replay, got %s both times", hash)
+	}
+}
+
+func TestRead_InvalidSize(t *testing.T) {
+	tt := map[string]string{
+		"too narrow": `{"mode": "Marathon", "width": 2, "height": 20}`,
+		"too tall":   `{"mode": "Marathon", "width": 10, "height": 1000000000}`,
+	}
+
+	for name, data := range tt {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "replay.json")
+			err := os.WriteFile(path, []byte(data), 0o644)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+
+			_, err = Read(path)
+			if err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestReplay_Mirror(t *testing.T) {
+	r := play(t, []bool{false, true, false, true, false, false, true, false})
+	r.Placements[0].Inputs = []string{"left", "clockwise", "hard drop"}
+	r.AddGarbage(time.Second, []int{1}, 2)
+	original, err := r.Hash()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	r.Mirror()
+	if !r.Mirrored {
+		t.Errorf("want the replay marked as mirrored")
+	}
+	if got := r.Placements[0].Inputs; got[0] != "right" || got[1] != "counter-clockwise" {
+		t.Errorf("want inputs [right counter-clockwise hard drop], got %v", got)
+	}
+	if got, want := r.Garbage[0].Holes[0], r.Width-3; got != want {
+		t.Errorf("want the garbage's hole moved to column %d, got %d", want, got)
+	}
+	err = Verify(r, r.Score, r.Lines)
+	if err != nil {
+		t.Errorf("want the mirrored replay to verify, got error: %v", err)
+	}
+
+	r.Mirror()
+	mirroredTwice, err := r.Hash()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if mirroredTwice != original {
+		t.Errorf("want mirroring twice to restore the replay")
+	}
+}
@@ -0,0 +1,336 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// ErrMismatch is returned, wrapped, when a replay could not have been played as recorded, or doesn't end with the
+// results it claims.
+var ErrMismatch = errors.New("replay does not match")
+
+// kickRows is how many rows a tetrimino can be kicked up by rotating, allowed for when checking the points scored
+// dropping it.
+const kickRows = 2
+
+// Result is the score and lines a replay ends with once played again.
+type Result struct {
+	Score uint
+	Lines uint
+}
+
+// Simulate plays the game again without drawing it, dealing tetriminos from the replay's seed and locking each
+// placement in turn. Each placement must be of the tetrimino dealt, taking holds into account, and lie where it fits
+// on the stack left by the placements before and rests on it, somewhere the tetrimino can be moved to from where it
+// spawned. The points scored by each line clear are worked out again, as in the game, rather than trusted. T-spins
+// are taken as recorded, since the kicks which made them aren't, but only where the T's corners allow them.
+//
+// Garbage which rose during play, such as in dig races and versus, is raised again where it was recorded. Mirrored
+// replays are played again with the mirror image of each tetrimino dealt.
+func Simulate(r *Replay) (*Result, error) {
+	if r.Randomizer == "" {
+		return nil, errors.New("replay does not say how tetriminos were dealt")
+	}
+	randomizer, err := tetris.NewRandomizer(r.Randomizer, r.Seed)
+	if err != nil {
+		return nil, err
+	}
+	hold := tetris.HoldOnce
+	if r.Hold != "" {
+		hold, err = tetris.ParseHoldRule(r.Hold)
+		if err != nil {
+			return nil, err
+		}
+	}
+	matrix := tetris.NewMatrix(r.Width, r.Height)
+	if r.Board != nil {
+		matrix = r.Board.Clone()
+	}
+	scoring := tetris.NewScoring(r.Level)
+	if r.Classic {
+		scoring = tetris.NewClassicScoring(r.Level)
+	}
+
+	game := tetris.NewSimulation(tetris.SimulationOptions{
+		Randomizer: randomizer,
+		Matrix:     matrix,
+		Scoring:    scoring,
+		Hold:       hold,
+	})
+	garbage := r.Garbage
+	for i, p := range r.Placements {
+		for len(garbage) > 0 && garbage[0].After <= i {
+			if game.AddGarbage(garbage[0].Holes, max(garbage[0].HoleWidth, 1)) {
+				return nil, fmt.Errorf("%w: placement %d was made after garbage topped out", ErrMismatch, i+1)
+			}
+			garbage = garbage[1:]
+		}
+		if game.GameOver() {
+			return nil, fmt.Errorf("%w: placement %d was made after the game ended", ErrMismatch, i+1)
+		}
+
+		for _, input := range p.Inputs {
+			if input == tetris.MoveHold.String() {
+				game.Apply(tetris.MoveHold)
+			}
+		}
+		// A mirrored game places the mirror image of each tetrimino dealt, from where it would have spawned.
+		dealt := game.Current()
+		if r.Mirrored {
+			dealt, err = game.Spawned(tetris.MirrorValue(dealt.Value))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if p.Piece != string(dealt.Value) {
+			return nil, fmt.Errorf("%w: placement %d is %s, but %c was dealt", ErrMismatch, i+1, p.Piece, dealt.Value)
+		}
+
+		stack := game.Stack()
+		placed, err := place(stack, dealt, p.Cells)
+		if err != nil {
+			return nil, fmt.Errorf("%w: placement %d %v", ErrMismatch, i+1, err)
+		}
+		if maxDrop := 2 * max(top(p.Cells)-top(tetris.NewPiece(dealt).Cells)+kickRows, 0); p.Drop > uint(maxDrop) {
+			return nil, fmt.Errorf("%w: placement %d scored %d points dropping, more than the %d possible", ErrMismatch,
+				i+1, p.Drop, maxDrop)
+		}
+		scoring.AddSoftDrop(p.Drop)
+		spin, err := claimedSpin(stack, placed, p.Clear)
+		if err != nil {
+			return nil, fmt.Errorf("%w: placement %d %v", ErrMismatch, i+1, err)
+		}
+		lines, _, err := game.Place(placed, spin)
+		if err != nil {
+			return nil, fmt.Errorf("%w: placement %d %v", ErrMismatch, i+1, err)
+		}
+		if uint(lines) != p.Lines {
+			return nil, fmt.Errorf("%w: placement %d cleared %d lines, not %d", ErrMismatch, i+1, lines, p.Lines)
+		}
+	}
+	if report := game.Report(); !report.OK() {
+		return nil, fmt.Errorf("failed to play the replay again: %v", report.Violations[0])
+	}
+	return &Result{Score: scoring.Total(), Lines: scoring.Lines()}, nil
+}
+
+// Verify plays the game again, as Simulate does, and checks that it ends with the given score and lines.
+func Verify(r *Replay, score, lines uint) error {
+	result, err := Simulate(r)
+	if err != nil {
+		return err
+	}
+	if result.Score != score || result.Lines != lines {
+		return fmt.Errorf("%w: it ends with %d points and %d lines, not %d points and %d lines", ErrMismatch,
+			result.Score, result.Lines, score, lines)
+	}
+	return nil
+}
+
+// place locks the tetrimino into the matrix in the cells given, returning it where it was locked. The cells must be
+// the tetrimino's shape in one of its rotations, be empty, rest on the stack or the floor, and be reachable from where
+// the tetrimino spawned.
+func place(matrix tetris.Matrix, t *tetris.Tetrimino, cells []tetris.Coordinate) (*tetris.Tetrimino, error) {
+	placed := &tetris.Tetrimino{Value: t.Value, Pos: tetris.Coordinate{X: left(cells), Y: top(cells)}}
+	for i, rotation := range rotations(t.Cells) {
+		if slices.Equal(shape(rotation), normalize(cells)) {
+			placed.Cells, placed.CurrentRotation = rotation, i
+			break
+		}
+	}
+	if placed.Cells == nil {
+		return nil, fmt.Errorf("is not the shape of %c", t.Value)
+	}
+
+	resting := false
+	for _, c := range cells {
+		if c.Y < 0 || c.Y >= len(matrix) || c.X < 0 || c.X >= len(matrix[0]) || !matrix.IsCellEmpty(c.Y, c.X) {
+			return nil, fmt.Errorf("overlaps the stack at row %d, column %d", c.Y, c.X)
+		}
+		if c.Y+1 == len(matrix) || (!matrix.IsCellEmpty(c.Y+1, c.X) && !slices.Contains(cells, tetris.Coordinate{X: c.X, Y: c.Y + 1})) {
+			resting = true
+		}
+	}
+	if !resting {
+		return nil, errors.New("is not resting on the stack")
+	}
+	ok, err := reachable(matrix, t, cells)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("can't be reached from where %c spawned", t.Value)
+	}
+	err = matrix.AddTetrimino(placed)
+	if err != nil {
+		return nil, err
+	}
+	return placed, nil
+}
+
+// reachable reports whether the tetrimino can be moved from where it spawned into the cells given, over the stack in
+// the matrix, by moving it sideways and down and rotating it as a player can. Every position it can be moved to is
+// searched, so tucks and spins under overhangs are found too.
+func reachable(matrix tetris.Matrix, t *tetris.Tetrimino, cells []tetris.Coordinate) (bool, error) {
+	matrix = matrix.Clone()
+	if !matrix.CanAddTetrimino(t) {
+		return false, nil
+	}
+	moves := []func(*tetris.Tetrimino, *tetris.Matrix) (bool, error){
+		(*tetris.Tetrimino).MoveLeft,
+		(*tetris.Tetrimino).MoveRight,
+		(*tetris.Tetrimino).MoveDown,
+		func(t *tetris.Tetrimino, m *tetris.Matrix) (bool, error) { return t.Rotate(m, true) },
+		func(t *tetris.Tetrimino, m *tetris.Matrix) (bool, error) { return t.Rotate(m, false) },
+	}
+
+	type position struct {
+		pos      tetris.Coordinate
+		rotation int
+	}
+	seen := map[position]bool{{t.Pos, t.CurrentRotation}: true}
+	queue := []*tetris.Tetrimino{t.Copy()}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if sameCells(tetris.NewPiece(current).Cells, cells) {
+			return true, nil
+		}
+		for _, move := range moves {
+			// Each move is made from the current position, with only the tetrimino being moved in the matrix.
+			next := current.Copy()
+			err := matrix.AddTetrimino(next)
+			if err != nil {
+				return false, err
+			}
+			moved, err := move(next, &matrix)
+			if err != nil {
+				return false, err
+			}
+			err = matrix.RemoveTetrimino(next)
+			if err != nil {
+				return false, err
+			}
+			if p := (position{next.Pos, next.CurrentRotation}); moved && !seen[p] {
+				seen[p] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false, nil
+}
+
+// sameCells reports whether the two sets of cells are the same, in any order.
+func sameCells(a, b []tetris.Coordinate) bool {
+	return len(a) == len(b) && left(a) == left(b) && top(a) == top(b) && slices.Equal(normalize(a), normalize(b))
+}
+
+// claimedSpin returns the T-spin the placement's recorded action claims, checking that the placed T has the corners
+// around it filled for one. A mini T-spin can't be told apart from a full one kicked into place, so either is allowed.
+func claimedSpin(matrix tetris.Matrix, placed *tetris.Tetrimino, action string) (tetris.Spin, error) {
+	if !strings.HasPrefix(action, "T-SPIN") {
+		return tetris.SpinNone, nil
+	}
+	if matrix.TSpin(placed, 0) == tetris.SpinNone {
+		return tetris.SpinNone, fmt.Errorf("is recorded as a %s, but is not a T-spin", action)
+	}
+	if strings.HasPrefix(action, "T-SPIN MINI") {
+		return tetris.SpinMini, nil
+	}
+	return tetris.SpinFull, nil
+}
+
+// rotations returns the cells of the shape in each of its four rotations, trimmed to the rows and columns it fills.
+func rotations(cells [][]bool) [][][]bool {
+	all := make([][][]bool, 4)
+	for i := range all {
+		all[i] = trim(cells)
+		cells = rotate(cells)
+	}
+	return all
+}
+
+// rotate turns the cells a quarter turn clockwise.
+func rotate(cells [][]bool) [][]bool {
+	rotated := make([][]bool, len(cells[0]))
+	for row := range rotated {
+		rotated[row] = make([]bool, len(cells))
+		for col := range rotated[row] {
+			rotated[row][col] = cells[len(cells)-1-col][row]
+		}
+	}
+	return rotated
+}
+
+// trim removes the empty rows and columns around the cells.
+func trim(cells [][]bool) [][]bool {
+	top, bottom, left, right := len(cells), -1, len(cells[0]), -1
+	for row := range cells {
+		for col, filled := range cells[row] {
+			if filled {
+				top, bottom = min(top, row), max(bottom, row)
+				left, right = min(left, col), max(right, col)
+			}
+		}
+	}
+	trimmed := make([][]bool, bottom-top+1)
+	for row := range trimmed {
+		trimmed[row] = cells[top+row][left : right+1]
+	}
+	return trimmed
+}
+
+// shape returns the coordinates of the filled cells, from the top left.
+func shape(cells [][]bool) []tetris.Coordinate {
+	var coords []tetris.Coordinate
+	for row := range cells {
+		for col, filled := range cells[row] {
+			if filled {
+				coords = append(coords, tetris.Coordinate{X: col, Y: row})
+			}
+		}
+	}
+	return coords
+}
+
+// normalize moves the cells so that the top and left of them are at 0, in the same order as shape.
+func normalize(cells []tetris.Coordinate) []tetris.Coordinate {
+	x, y := left(cells), top(cells)
+	normalized := make([]tetris.Coordinate, len(cells))
+	for i, c := range cells {
+		normalized[i] = tetris.Coordinate{X: c.X - x, Y: c.Y - y}
+	}
+	slices.SortFunc(normalized, func(a, b tetris.Coordinate) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return normalized
+}
+
+// top returns the highest row of the cells.
+func top(cells []tetris.Coordinate) int {
+	y := 0
+	for i, c := range cells {
+		if i == 0 || c.Y < y {
+			y = c.Y
+		}
+	}
+	return y
+}
+
+// left returns the leftmost column of the cells.
+func left(cells []tetris.Coordinate) int {
+	x := 0
+	for i, c := range cells {
+		if i == 0 || c.X < x {
+			x = c.X
+		}
+	}
+	return x
+}
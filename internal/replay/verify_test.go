@@ -0,0 +1,198 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// play hard drops a tetrimino where it spawns for each step, holding first where the step is true, and returns the
+// replay of the game with the results it ended with.
+func play(t *testing.T, holds []bool) *Replay {
+	t.Helper()
+	matrix := tetris.NewMatrix(10, 20)
+	randomizer, err := tetris.NewRandomizer("bag", 42)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	bag := tetris.NewRandomizedBag(matrix, randomizer)
+	r := New("Marathon", 42, 1, matrix)
+	r.Randomizer = "bag"
+	scoring := tetris.NewScoring(1)
+
+	var held *tetris.Tetrimino
+	for i, hold := range holds {
+		current := bag.Next()
+		moves := []tetris.Move{tetris.MoveHardDrop}
+		if hold {
+			if held == nil {
+				held, current = current, bag.Next()
+			} else {
+				held, current = current, held
+			}
+			moves = append([]tetris.Move{tetris.MoveHold}, moves...)
+		}
+		err := matrix.AddTetrimino(current)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		rows, err := current.Drop(&matrix)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		scoring.AddHardDrop(uint(rows))
+		r.Add(current, time.Duration(i)*time.Second, moves, uint(rows)*2)
+		lines := len(matrix.CompletedLines(current))
+		action := matrix.RemoveCompletedLines(current)
+		scoring.ProcessAction(action)
+		r.Cleared(action.String(), uint(lines), 0)
+	}
+	r.Score, r.Lines = scoring.Total(), scoring.Lines()
+	return r
+}
+
+func TestVerify(t *testing.T) {
+	tt := map[string]struct {
+		tamper  func(r *Replay)
+		wantErr bool
+	}{
+		"as played": {
+			tamper: func(r *Replay) {},
+		},
+		"higher score": {
+			tamper:  func(r *Replay) { r.Score += 100 },
+			wantErr: true,
+		},
+		"more drop points": {
+			tamper: func(r *Replay) {
+				r.Placements[0].Drop += 100
+				r.Score += 100
+			},
+			wantErr: true,
+		},
+		"different piece": {
+			tamper: func(r *Replay) {
+				if r.Placements[1].Piece == "I" {
+					r.Placements[1].Piece = "O"
+				} else {
+					r.Placements[1].Piece = "I"
+				}
+			},
+			wantErr: true,
+		},
+		"floating": {
+			tamper: func(r *Replay) {
+				for i := range r.Placements[2].Cells {
+					r.Placements[2].Cells[i].Y -= 3
+				}
+			},
+			wantErr: true,
+		},
+		"T-spin claimed": {
+			tamper: func(r *Replay) {
+				for i := range r.Placements {
+					if r.Placements[i].Piece == "T" {
+						r.Placements[i].Clear = "T-SPIN"
+						break
+					}
+				}
+			},
+			wantErr: true,
+		},
+		"hold left out": {
+			tamper: func(r *Replay) {
+				r.Placements[3].Inputs = r.Placements[3].Inputs[1:]
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			r := play(t, []bool{false, false, false, true, false, true})
+			tc.tamper(r)
+
+			err := Verify(r, r.Score, r.Lines)
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("expected nil, got error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMismatch) {
+				t.Errorf("want %v, got %v", ErrMismatch, err)
+			}
+		})
+	}
+}
+
+func TestSimulate_NoRandomizer(t *testing.T) {
+	r := New("Puzzle", 42, 1, tetris.NewMatrix(10, 20))
+
+	_, err := Simulate(r)
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestSimulate_Reachable(t *testing.T) {
+	tt := map[string]struct {
+		roofed  bool
+		wantErr bool
+	}{
+		"open":   {},
+		"roofed": {roofed: true, wantErr: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			randomizer, err := tetris.NewRandomizer("bag", 42)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			spawned := tetris.NewRandomizedBag(tetris.NewMatrix(10, 20), randomizer).Next()
+
+			// A pocket at the bottom of the board the shape of the first tetrimino dealt, straight below where it spawns,
+			// with the rest of its rows filled and, if roofed, the row above it filled but for the last column.
+			cells := tetris.NewPiece(spawned).Cells
+			board := tetris.NewMatrix(10, 20)
+			dy := len(board) - 1
+			for _, c := range cells {
+				dy = min(dy, len(board)-1-c.Y)
+			}
+			for i := range cells {
+				cells[i].Y += dy
+			}
+			for row := top(cells); row < len(board); row++ {
+				for col := range board[row] {
+					board[row][col] = tetris.GarbageValue
+				}
+			}
+			for _, c := range cells {
+				board[c.Y][c.X] = 0
+			}
+			if tc.roofed {
+				for col := range board[0][:9] {
+					board[top(cells)-1][col] = tetris.GarbageValue
+				}
+			}
+
+			r := New("Marathon", 42, 1, board)
+			r.Randomizer = "bag"
+			r.Placements = []Placement{{Piece: string(spawned.Value), Cells: cells, Lines: uint(len(board) - top(cells))}}
+
+			_, err = Simulate(r)
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("expected nil, got error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMismatch) {
+				t.Errorf("want %v, got %v", ErrMismatch, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package room
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+// room is a room and the game being played in it. It is only used while holding the server's lock.
+type room struct {
+	code     string
+	settings netplay.Settings
+	players  []*player // in the order they joined, so the first is the owner
+	lastID   int
+	playing  bool
+	rand     *rand.Rand
+}
+
+// outboxSize is how many messages can be waiting to be sent to a player. A player who falls further behind than
+// that is disconnected, rather than holding up the room.
+const outboxSize = 64
+
+// player is a player in a room.
+type player struct {
+	id     int
+	conn   *netplay.Conn
+	outbox chan netplay.Message // messages waiting to be written to conn, closed once they leave
+
+	// The player's part in the game being played.
+	alive    bool
+	board    *netplay.Board // the latest board they sent
+	target   int            // ID of the opponent they last attacked
+	attacker int            // ID of the opponent who last attacked them
+	badges   int            // opponents they knocked out
+	bonus    float64        // fraction of a line of badge bonus not yet sent
+}
+
+// newPlayer returns a player with the ID, and starts writing the messages sent to them to the connection.
+func newPlayer(id int, conn *netplay.Conn) *player {
+	p := &player{id: id, conn: conn, outbox: make(chan netplay.Message, outboxSize)}
+	go p.write()
+	return p
+}
+
+// write writes each message in the outbox to the player's connection until the outbox is closed, disconnecting them
+// if one can't be written.
+func (p *player) write() {
+	for msg := range p.outbox {
+		if err := p.conn.Send(msg); err != nil {
+			p.conn.Close()
+		}
+	}
+}
+
+// handle acts on a message from a player in the room.
+func (r *room) handle(p *player, msg netplay.Message) {
+	switch msg.Type {
+	case netplay.TypeStart:
+		r.start(p)
+	case netplay.TypeState:
+		if r.playing && p.alive {
+			p.board = msg.Board
+			r.relay(p, netplay.Message{Type: netplay.TypeState, Board: msg.Board, Player: p.id})
+		}
+	case netplay.TypeGarbage:
+		if r.playing && p.alive {
+			r.attack(p, msg.Lines)
+		}
+	case netplay.TypeGameOver:
+		r.knockOut(p, msg.Board)
+	case netplay.TypeChat:
+		r.relay(p, netplay.Message{Type: netplay.TypeChat, Chat: msg.Chat, Player: p.id})
+	}
+}
+
+// start starts a game with a fresh seed, if the player is the owner and no game is being played, or tells them why
+// it can't.
+func (r *room) start(p *player) {
+	var reason string
+	switch {
+	case p != r.players[0]:
+		reason = "only the room's owner can start a game"
+	case r.playing:
+		reason = "a game is being played, wait for it to finish"
+	case len(r.players) < 2:
+		reason = "at least 2 players are needed to start"
+	}
+	if reason != "" {
+		r.send(p, netplay.Message{Type: netplay.TypeError, Error: reason})
+		return
+	}
+
+	r.playing = true
+	settings := r.settings
+	settings.Seed = time.Now().UnixNano()
+	for _, q := range r.players {
+		q.alive, q.board, q.target, q.attacker, q.badges, q.bonus = true, nil, 0, 0, 0, 0
+		r.send(q, netplay.Message{Type: netplay.TypeHello, Version: netplay.ProtocolVersion, Settings: &settings})
+	}
+}
+
+// attack sends the lines of garbage from the player on to the opponent the targeting rules choose, adding the bonus
+// of the player's badges. Fractions of a line of bonus are kept until they add up to a whole line.
+func (r *room) attack(p *player, lines uint) {
+	target := r.target(p)
+	if target == nil {
+		return
+	}
+	p.bonus += float64(lines) * netplay.BadgeBonus * float64(min(p.badges, netplay.MaxBadges))
+	whole := math.Floor(p.bonus)
+	lines += uint(whole)
+	p.bonus -= whole
+	p.target, target.attacker = target.id, p.id
+	r.send(target, netplay.Message{Type: netplay.TypeGarbage, Lines: lines, Player: p.id})
+}
+
+// target returns the opponent the player's next attack is sent to, or nil if there are none left.
+func (r *room) target(p *player) *player {
+	var opponents []*player
+	for _, q := range r.players {
+		if q != p && q.alive {
+			opponents = append(opponents, q)
+		}
+	}
+	if len(opponents) == 0 {
+		return nil
+	}
+
+	var chosen []*player
+	switch r.settings.Targeting {
+	case netplay.TargetAttacker:
+		for _, q := range opponents {
+			if q.target == p.id {
+				chosen = append(chosen, q)
+			}
+		}
+	case netplay.TargetBadges:
+		most := -1
+		for _, q := range opponents {
+			if q.badges > most {
+				chosen, most = nil, q.badges
+			}
+			if q.badges == most {
+				chosen = append(chosen, q)
+			}
+		}
+	}
+	if len(chosen) == 0 {
+		chosen = opponents
+	}
+	return chosen[r.rand.Intn(len(chosen))]
+}
+
+// knockOut takes the player out of the game being played, crediting a badge to whoever attacked them last, and ends
+// the game once there is at most one player left. The board is their last, or nil if they disconnected.
+func (r *room) knockOut(p *player, board *netplay.Board) {
+	if !r.playing || !p.alive {
+		return
+	}
+	p.alive = false
+	if board != nil {
+		p.board = board
+	}
+	if p.board == nil {
+		p.board = &netplay.Board{Level: r.settings.Level}
+	}
+	if attacker := r.player(p.attacker); attacker != nil && attacker.alive {
+		attacker.badges++
+	}
+	r.relay(p, netplay.Message{Type: netplay.TypeGameOver, Board: p.board, Player: p.id})
+
+	alive := 0
+	for _, q := range r.players {
+		if q.alive {
+			alive++
+		}
+	}
+	r.playing = alive > 1
+}
+
+// player returns the player with the ID, or nil if they have left.
+func (r *room) player(id int) *player {
+	for _, p := range r.players {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// sendRoom sends everyone the players in the room.
+func (r *room) sendRoom() {
+	ids := make([]int, len(r.players))
+	for i, p := range r.players {
+		ids[i] = p.id
+	}
+	for _, p := range r.players {
+		room := &netplay.Room{Code: r.code, Players: ids, Owner: ids[0], You: p.id}
+		r.send(p, netplay.Message{Type: netplay.TypeRoom, Room: room})
+	}
+}
+
+// relay sends the message from the player to everyone else in the room.
+func (r *room) relay(from *player, msg netplay.Message) {
+	for _, p := range r.players {
+		if p != from {
+			r.send(p, msg)
+		}
+	}
+}
+
+// send queues the message to be sent to the player, without waiting for it to be written. A player who can't be
+// reached, or is too far behind to queue it, is disconnected, so that they leave the room once their connection is
+// read from.
+func (r *room) send(p *player, msg netplay.Message) {
+	select {
+	case p.outbox <- msg:
+	default:
+		p.conn.Close()
+	}
+}
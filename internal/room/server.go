@@ -0,0 +1,180 @@
+// Package room runs a room server, which hosts free-for-alls between any number of tetrigo players. Players create a
+// room and share its code for others to join, and once the room's owner starts a game the server relays each
+// player's board to the others and sends each attack on to a single opponent, chosen by the room's targeting rules.
+package room
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+const (
+	// MaxPlayers is the most players a room can hold.
+	MaxPlayers = 16
+
+	codeLength = 4
+	// codeLetters are the letters room codes are made of, leaving out I and O, which are easily mistaken for 1 and 0.
+	codeLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+// Server hosts rooms for the players connected to its listener.
+type Server struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	rooms map[string]*room
+	rand  *rand.Rand
+}
+
+// Listen starts accepting players on the given TCP address.
+func Listen(addr string) (*Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %q: %w", addr, err)
+	}
+	return NewServer(l), nil
+}
+
+// NewServer starts accepting players on the listener.
+func NewServer(l net.Listener) *Server {
+	s := &Server{
+		listener: l,
+		rooms:    make(map[string]*room),
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	go s.serve()
+	return s
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Rooms returns the number of rooms with players in them.
+func (s *Server) Rooms() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.rooms)
+}
+
+// Close stops accepting players. Those already connected stay in their rooms until they leave.
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			// The listener has been closed.
+			return
+		}
+		go s.handle(netplay.NewConn(conn))
+	}
+}
+
+// handle puts the player in the room they create or join, then passes on their messages until they disconnect.
+func (s *Server) handle(c *netplay.Conn) {
+	defer c.Close()
+
+	msg, err := c.Receive()
+	if err != nil {
+		return
+	}
+	r, p, err := s.enter(c, msg)
+	if err != nil {
+		_ = c.Send(netplay.Message{Type: netplay.TypeError, Error: err.Error()})
+		return
+	}
+	defer s.leave(r, p)
+
+	for {
+		msg, err := c.Receive()
+		if err != nil {
+			return
+		}
+		if msg.Validate() != nil {
+			continue
+		}
+		s.mu.Lock()
+		r.handle(p, msg)
+		s.mu.Unlock()
+	}
+}
+
+// enter adds the player to the room they asked to create or join.
+func (s *Server) enter(c *netplay.Conn, msg netplay.Message) (*room, *player, error) {
+	err := msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var r *room
+	switch msg.Type {
+	case netplay.TypeCreate:
+		r = &room{code: s.newCode(), settings: *msg.Settings, rand: s.rand}
+		s.rooms[r.code] = r
+	case netplay.TypeJoin:
+		r = s.rooms[strings.ToUpper(msg.Code)]
+		switch {
+		case r == nil:
+			return nil, nil, fmt.Errorf("no room with the code %q", msg.Code)
+		case r.playing:
+			return nil, nil, errors.New("the room is playing a game, try again once it is over")
+		case len(r.players) >= MaxPlayers:
+			return nil, nil, fmt.Errorf("the room is full, with %d players", MaxPlayers)
+		}
+	default:
+		return nil, nil, fmt.Errorf("expected %q or %q message, got %q", netplay.TypeCreate, netplay.TypeJoin, msg.Type)
+	}
+
+	r.lastID++
+	p := newPlayer(r.lastID, c)
+	r.players = append(r.players, p)
+	r.sendRoom()
+	return r, p, nil
+}
+
+// leave removes the player from the room, knocking them out of any game being played, and closes the room once it is
+// empty.
+func (s *Server) leave(r *room, p *player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.knockOut(p, nil)
+	for i, q := range r.players {
+		if q == p {
+			r.players = append(r.players[:i], r.players[i+1:]...)
+			break
+		}
+	}
+	// Nothing is sent to the player once they are out of the room.
+	close(p.outbox)
+	if len(r.players) == 0 {
+		delete(s.rooms, r.code)
+		return
+	}
+	r.sendRoom()
+}
+
+// newCode returns a code which no open room has.
+func (s *Server) newCode() string {
+	code := make([]byte, codeLength)
+	for {
+		for i := range code {
+			code[i] = codeLetters[s.rand.Intn(len(codeLetters))]
+		}
+		if _, ok := s.rooms[string(code)]; !ok {
+			return string(code)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package room
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+// newTestServer starts a server on a random local port.
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// receive returns the next message of the type, skipping any others, such as room messages sent as players join.
+func receive(t *testing.T, c *netplay.Conn, msgType netplay.MessageType) netplay.Message {
+	t.Helper()
+	for {
+		msg, err := c.Receive()
+		if err != nil {
+			t.Fatalf("failed to receive %s: %v", msgType, err)
+		}
+		if msg.Type == msgType {
+			return msg
+		}
+	}
+}
+
+// fill creates a room with the settings and has players join it until there are n, returning their connections in
+// the order they joined.
+func fill(t *testing.T, s *Server, settings netplay.Settings, n int) []*netplay.Conn {
+	t.Helper()
+	owner, room, err := netplay.CreateRoom(s.Addr().String(), settings)
+	if err != nil {
+		t.Fatalf("CreateRoom: expected nil, got error: %v", err)
+	}
+	t.Cleanup(func() { owner.Close() })
+	conns := []*netplay.Conn{owner}
+	for len(conns) < n {
+		c, _, err := netplay.JoinRoom(s.Addr().String(), strings.ToLower(room.Code))
+		if err != nil {
+			t.Fatalf("JoinRoom: expected nil, got error: %v", err)
+		}
+		t.Cleanup(func() { c.Close() })
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// start has the owner start a game, returning once every player has been sent its settings.
+func start(t *testing.T, conns []*netplay.Conn) *netplay.Settings {
+	t.Helper()
+	err := conns[0].Send(netplay.Message{Type: netplay.TypeStart})
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	var settings *netplay.Settings
+	for _, c := range conns {
+		settings = receive(t, c, netplay.TypeHello).Settings
+	}
+	return settings
+}
+
+func TestServer_CreateJoin(t *testing.T) {
+	s := newTestServer(t)
+	owner, room, err := netplay.CreateRoom(s.Addr().String(), netplay.Settings{Level: 1})
+	if err != nil {
+		t.Fatalf("CreateRoom: expected nil, got error: %v", err)
+	}
+	defer owner.Close()
+	if len(room.Code) != codeLength || room.Owner != room.You || len(room.Players) != 1 {
+		t.Errorf("want a new room owned by its only player, got %+v", room)
+	}
+
+	guest, joined, err := netplay.JoinRoom(s.Addr().String(), room.Code)
+	if err != nil {
+		t.Fatalf("JoinRoom: expected nil, got error: %v", err)
+	}
+	defer guest.Close()
+	if joined.Code != room.Code || joined.You == room.You || len(joined.Players) != 2 {
+		t.Errorf("want to join room %s as a second player, got %+v", room.Code, joined)
+	}
+	if updated := receive(t, owner, netplay.TypeRoom).Room; len(updated.Players) != 2 {
+		t.Errorf("want the owner told of 2 players, got %+v", updated)
+	}
+
+	_, _, err = netplay.JoinRoom(s.Addr().String(), "ZZZZ")
+	if err == nil {
+		t.Errorf("want an error joining a room which doesn't exist, got nil")
+	}
+	if s.Rooms() != 1 {
+		t.Errorf("Rooms: want 1, got %d", s.Rooms())
+	}
+}
+
+func TestServer_Start(t *testing.T) {
+	s := newTestServer(t)
+	conns := fill(t, s, netplay.Settings{Level: 3}, 1)
+
+	err := conns[0].Send(netplay.Message{Type: netplay.TypeStart})
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	if msg := receive(t, conns[0], netplay.TypeError); msg.Error == "" {
+		t.Errorf("want an error starting alone, got none")
+	}
+
+	conns = fill(t, s, netplay.Settings{Level: 3}, 3)
+	settings := start(t, conns)
+	if settings.Level != 3 || settings.Seed == 0 {
+		t.Errorf("want the room's settings with a seed, got %+v", settings)
+	}
+
+	err = conns[0].Send(netplay.Message{Type: netplay.TypeStart})
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	if msg := receive(t, conns[0], netplay.TypeError); msg.Error == "" {
+		t.Errorf("want an error starting during a game, got none")
+	}
+}
+
+func TestServer_Relay(t *testing.T) {
+	s := newTestServer(t)
+	conns := fill(t, s, netplay.Settings{Level: 1}, 3)
+	start(t, conns)
+
+	board := &netplay.Board{Level: 1, Score: 500}
+	err := conns[0].Send(netplay.Message{Type: netplay.TypeState, Board: board})
+	if err != nil {
+		t.Fatalf("failed to send state: %v", err)
+	}
+	for i, c := range conns[1:] {
+		msg := receive(t, c, netplay.TypeState)
+		if msg.Player != 1 || msg.Board.Score != 500 {
+			t.Errorf("Player %d: want player 1's board, got %+v from %d", i+2, msg.Board, msg.Player)
+		}
+	}
+}
+
+func TestServer_Relay_TooMuchGarbage(t *testing.T) {
+	s := newTestServer(t)
+	conns := fill(t, s, netplay.Settings{Level: 1}, 2)
+	start(t, conns)
+
+	for _, lines := range []uint{1 << 62, 2} {
+		err := conns[0].Send(netplay.Message{Type: netplay.TypeGarbage, Lines: lines})
+		if err != nil {
+			t.Fatalf("failed to send garbage: %v", err)
+		}
+	}
+	if msg := receive(t, conns[1], netplay.TypeGarbage); msg.Lines != 2 {
+		t.Errorf("want the oversized garbage dropped and 2 lines relayed, got %d", msg.Lines)
+	}
+}
+
+func TestServer_KnockOut(t *testing.T) {
+	s := newTestServer(t)
+	conns := fill(t, s, netplay.Settings{Level: 1, Targeting: netplay.TargetAttacker}, 2)
+	start(t, conns)
+
+	// With only one opponent, every attack goes to them.
+	err := conns[0].Send(netplay.Message{Type: netplay.TypeGarbage, Lines: 2})
+	if err != nil {
+		t.Fatalf("failed to send garbage: %v", err)
+	}
+	if msg := receive(t, conns[1], netplay.TypeGarbage); msg.Lines != 2 || msg.Player != 1 {
+		t.Errorf("want 2 lines from player 1, got %d from %d", msg.Lines, msg.Player)
+	}
+
+	err = conns[1].Send(netplay.Message{Type: netplay.TypeGameOver, Board: &netplay.Board{Level: 1}})
+	if err != nil {
+		t.Fatalf("failed to send game over: %v", err)
+	}
+	if msg := receive(t, conns[0], netplay.TypeGameOver); msg.Player != 2 {
+		t.Errorf("want player 2 knocked out, got %d", msg.Player)
+	}
+
+	// The game is over, so the owner can start the next, in which badges are earned again from none.
+	settings := start(t, conns)
+	if settings == nil {
+		t.Fatalf("want the next game started, got nil settings")
+	}
+	err = conns[0].Send(netplay.Message{Type: netplay.TypeGarbage, Lines: 4})
+	if err != nil {
+		t.Fatalf("failed to send garbage: %v", err)
+	}
+	if msg := receive(t, conns[1], netplay.TypeGarbage); msg.Lines != 4 {
+		t.Errorf("want 4 lines without a badge bonus, got %d", msg.Lines)
+	}
+}
+
+func TestRoom_Attack_Badges(t *testing.T) {
+	r := &room{settings: netplay.Settings{Targeting: netplay.TargetBadges}, playing: true, rand: newRand()}
+	sender := &player{id: 1, alive: true, badges: 2}
+	plain := &player{id: 2, alive: true}
+	leader := &player{id: 3, alive: true, badges: 1}
+	r.players = []*player{sender, plain, leader}
+
+	for i := 0; i < 10; i++ {
+		if target := r.target(sender); target != leader {
+			t.Fatalf("want the opponent with the most badges targeted, got player %d", target.id)
+		}
+	}
+	if target := r.target(leader); target != sender {
+		t.Errorf("want the leader to target the sender, who has more badges, got player %d", target.id)
+	}
+
+	r.settings.Targeting = netplay.TargetAttacker
+	plain.target = leader.id
+	if target := r.target(leader); target != plain {
+		t.Errorf("want the leader to target their attacker, got player %d", target.id)
+	}
+}
+
+func TestRoom_Attack_BadgeFraction(t *testing.T) {
+	r := &room{playing: true, rand: newRand()}
+	sender := &player{id: 1, alive: true, badges: 1}
+	target := &player{id: 2, alive: true, outbox: make(chan netplay.Message, outboxSize)}
+	r.players = []*player{sender, target}
+
+	// A badge adds a quarter of the lines sent, so every fourth line sent carries a line of bonus.
+	want := []uint{1, 1, 1, 2, 1}
+	for i, lines := range want {
+		r.attack(sender, 1)
+		if msg := <-target.outbox; msg.Lines != lines {
+			t.Errorf("Attack %d: want %d lines, got %d", i+1, lines, msg.Lines)
+		}
+	}
+}
+
+func TestRoom_Send_Full(t *testing.T) {
+	r := &room{}
+	c := discard(t)
+	p := &player{id: 1, conn: c, outbox: make(chan netplay.Message, outboxSize)}
+
+	// Nothing writes the outbox, so once it is full the player is disconnected rather than waited for.
+	for i := 0; i <= outboxSize; i++ {
+		r.send(p, netplay.Message{Type: netplay.TypeChat, Chat: "hi"})
+	}
+	if err := c.Send(netplay.Message{Type: netplay.TypeChat}); err == nil {
+		t.Errorf("want the connection closed, got nil error sending")
+	}
+}
+
+func TestRoom_KnockOut(t *testing.T) {
+	r := &room{settings: netplay.Settings{Level: 1}, playing: true, rand: newRand()}
+	attacker := newPlayer(1, discard(t))
+	victim := newPlayer(2, discard(t))
+	other := newPlayer(3, discard(t))
+	victim.attacker = attacker.id
+	for _, p := range []*player{attacker, victim, other} {
+		p.alive = true
+	}
+	r.players = []*player{attacker, victim, other}
+
+	r.knockOut(victim, nil)
+	if victim.alive || attacker.badges != 1 || !r.playing {
+		t.Errorf("want the victim out, the attacker with a badge and the game on, got %+v, %+v, playing %t",
+			victim, attacker, r.playing)
+	}
+	r.knockOut(other, nil)
+	if r.playing {
+		t.Errorf("want the game over with one player left")
+	}
+}
+
+// discard returns a connection whose messages are read and thrown away.
+func discard(t *testing.T) *netplay.Conn {
+	t.Helper()
+	a, b := net.Pipe()
+	go io.Copy(io.Discard, b)
+	t.Cleanup(func() { a.Close() })
+	return netplay.NewConn(a)
+}
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
@@ -0,0 +1,115 @@
+// Package save keeps a suspended marathon game on disk, so that it can be continued later.
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Game is everything needed to continue a suspended game where it left off.
+type Game struct {
+	// Settings the game was started with.
+	Level       uint    `json:"level"`
+	HoldPreview bool    `json:"hold_preview,omitempty"`
+	Interludes  bool    `json:"interludes,omitempty"`
+	Speed       float64 `json:"speed"`
+	Seed        int64   `json:"seed"`
+
+	Matrix  tetris.Matrix    `json:"matrix"`         // the stack, without the current tetrimino
+	Current tetris.Tetrimino `json:"current"`        // the tetrimino being placed
+	Hold    tetris.Tetrimino `json:"hold"`           // the held tetrimino (with a value of 0 if there is none)
+	Held    string           `json:"held,omitempty"` // values of the held tetriminos, with the one swapped in next first
+	CanHold bool             `json:"can_hold"`
+
+	Bag        tetris.BagState        `json:"bag"`
+	Scoring    tetris.ScoringState    `json:"scoring"`
+	Attack     tetris.AttackState     `json:"attack"`
+	Statistics tetris.StatisticsState `json:"statistics"`
+	MaxCombo   int                    `json:"max_combo"`
+	Time       time.Duration          `json:"time"` // time spent playing
+
+	// Checkpoints are the progress of the game each time another records.CheckpointLines lines were cleared.
+	Checkpoints []records.Checkpoint `json:"checkpoints,omitempty"`
+
+	Date time.Time `json:"date"` // when the game was suspended
+}
+
+// DefaultPath returns the location of the save file in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "save.json"), nil
+}
+
+// DefaultAutosavePath returns the location of the autosave file in the user's config directory, which the game in
+// progress is snapshotted to. It is kept apart from the save file, so that autosaving doesn't replace a suspended game.
+func DefaultAutosavePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "autosave.json"), nil
+}
+
+// Exists reports whether there is a game saved at the path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Read returns the game saved at the path.
+func Read(path string) (*Game, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved game: %w", err)
+	}
+
+	var g Game
+	err = json.Unmarshal(data, &g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode saved game: %w", err)
+	}
+	return &g, nil
+}
+
+// Write saves the game to the path, replacing any game saved there already.
+func Write(path string, g *Game) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved game: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+	// Write to a temporary file first so that the game is not lost if writing is interrupted.
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write saved game: %w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace saved game: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the game saved at the path, once it has been continued. It does nothing if there is none.
+func Remove(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove saved game: %w", err)
+	}
+	return nil
+}
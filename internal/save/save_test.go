@@ -0,0 +1,86 @@
+package save
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tetrigo", "save.json")
+	bag, err := tetris.NewSeededBag(tetris.NewDefaultMatrix(), 42).State()
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	matrix, err := tetris.ParseMatrix("XXXX..XXXX\nXXXXX.XXXX")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	g := &Game{
+		Level:      5,
+		Speed:      1,
+		Seed:       42,
+		Matrix:     matrix,
+		Current:    tetris.Tetriminos[0],
+		Held:       "IO",
+		CanHold:    true,
+		Bag:        bag,
+		Scoring:    tetris.NewScoring(5).State(),
+		Attack:     tetris.NewAttack().State(),
+		Statistics: tetris.NewStatistics().State(),
+		Time:       90 * time.Second,
+		Date:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	if Exists(path) {
+		t.Errorf("Exists: want false before writing")
+	}
+	err = Write(path, g)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !Exists(path) {
+		t.Errorf("Exists: want true after writing")
+	}
+
+	read, err := Read(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !reflect.DeepEqual(read, g) {
+		t.Errorf("Game: want %+v, got %+v", g, read)
+	}
+
+	err = Remove(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if Exists(path) {
+		t.Errorf("Exists: want false after removing")
+	}
+	err = Remove(path)
+	if err != nil {
+		t.Errorf("Remove again: expected nil, got error: %v", err)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	_, err := Read(path)
+	if err == nil {
+		t.Errorf("Missing: expected error, got nil")
+	}
+
+	err = os.WriteFile(path, []byte("{"), 0o644)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	_, err = Read(path)
+	if err == nil {
+		t.Errorf("Corrupt: expected error, got nil")
+	}
+}
@@ -0,0 +1,39 @@
+package seed
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit  key.Binding
+	Left  key.Binding
+	Right key.Binding
+	Start key.Binding
+}
+
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit:  key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+		Left:  key.NewBinding(key.WithKeys("j", "a", "left"), key.WithHelp("a, j, left", "previous mode")),
+		Right: key.NewBinding(key.WithKeys("l", "d", "right"), key.WithHelp("d, l, right", "next mode")),
+		Start: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "start game")),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Quit,
+		k.Left,
+		k.Right,
+		k.Start,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Quit,
+			k.Left,
+			k.Right,
+			k.Start,
+		},
+	}
+}
@@ -0,0 +1,198 @@
+// Package seed implements a screen for exploring the tetriminos dealt by a seed, and starting games with it.
+package seed
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/teautil"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/internal/versus"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultBags is the number of bags shown when none is specified.
+const DefaultBags = 10
+
+// Modes that can be started with the seed, in the order they are shown.
+var Modes = []string{"Marathon", "Sprint (40 lines)", "Versus", "Demo"}
+
+type Model struct {
+	seed      int64
+	bags      [][]byte
+	modeIndex int
+	game      tea.Model
+	playing   bool
+	input     *Input
+
+	// windowSize is the last size of the terminal, passed on to each game when it starts.
+	windowSize *tea.WindowSizeMsg
+
+	keys   *KeyMap
+	styles *Styles
+	help   help.Model
+}
+
+// Input configures the explorer and the games started from it.
+// Games do not keep personal bests, since the tetriminos are known before they start.
+type Input struct {
+	Seed        int64
+	Bags        int // number of bags to show (DefaultBags if 0)
+	Level       uint
+	HoldPreview bool
+	Countdown   uint          // seconds counted down before each game starts
+	Theme       *theme.Theme  // colour scheme the explorer and games are drawn with (nil for the default)
+	Speed       float64       // gravity multiplier for marathon and sprint games (0 for normal speed)
+	Audio       *audio.Player // plays the sounds of each game (nil for silence)
+
+	// Handling is how held keys move the tetrimino in the games the player plays (see marathon.Input).
+	Handling tetris.Handling
+}
+
+// NewModel creates an explorer which shows the first bags dealt by the seed.
+func NewModel(in *Input) *Model {
+	n := in.Bags
+	if n <= 0 {
+		n = DefaultBags
+	}
+
+	m := &Model{
+		seed:   in.Seed,
+		bags:   tetris.SeededBags(in.Seed, n),
+		input:  in,
+		keys:   DefaultKeyMap(),
+		styles: NewStyles(in.Theme),
+		help:   theme.NewHelp(in.Theme),
+	}
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowSize = &msg
+	}
+
+	if m.playing {
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Quit) {
+			m.playing = false
+			m.game = nil
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.game, cmd = m.game.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Left):
+			m.modeIndex--
+			if m.modeIndex < 0 {
+				m.modeIndex = len(Modes) - 1
+			}
+		case key.Matches(msg, m.keys.Right):
+			m.modeIndex++
+			if m.modeIndex >= len(Modes) {
+				m.modeIndex = 0
+			}
+		case key.Matches(msg, m.keys.Start):
+			m.playing = true
+			m.game = m.newGame(Modes[m.modeIndex])
+			return m, tea.Batch(m.game.Init(), teautil.Resize(m.windowSize))
+		}
+	}
+
+	return m, nil
+}
+
+// newGame creates a game of the given mode which uses the seed.
+func (m *Model) newGame(mode string) tea.Model {
+	in := m.input
+	switch mode {
+	case "Sprint (40 lines)":
+		return marathon.NewModel(&marathon.Input{
+			Level:       in.Level,
+			Seed:        m.seed,
+			LineGoal:    40,
+			HoldPreview: in.HoldPreview,
+			Countdown:   in.Countdown,
+			Theme:       in.Theme,
+			Speed:       in.Speed,
+			Handling:    in.Handling,
+			Audio:       in.Audio,
+		})
+	case "Versus":
+		return versus.NewModel(&versus.Input{
+			Level:     in.Level,
+			Seed:      m.seed,
+			Countdown: in.Countdown,
+			Theme:     in.Theme,
+			Audio:     in.Audio,
+			Handling:  in.Handling,
+		})
+	case "Demo":
+		return marathon.NewModel(&marathon.Input{
+			Level:     in.Level,
+			Seed:      m.seed,
+			Bot:       true,
+			Countdown: in.Countdown,
+			Theme:     in.Theme,
+			Speed:     in.Speed,
+			Audio:     in.Audio,
+		})
+	}
+	return marathon.NewModel(&marathon.Input{
+		Level:       in.Level,
+		Seed:        m.seed,
+		HoldPreview: in.HoldPreview,
+		Countdown:   in.Countdown,
+		Theme:       in.Theme,
+		Speed:       in.Speed,
+		Handling:    in.Handling,
+		Audio:       in.Audio,
+	})
+}
+
+func (m Model) View() string {
+	if m.playing {
+		return m.game.View()
+	}
+
+	var bags strings.Builder
+	width := len(fmt.Sprint(len(m.bags)))
+	for i, bag := range m.bags {
+		bags.WriteString(m.styles.BagNumber.Render(fmt.Sprintf("%*d.", width, i+1)))
+		for _, v := range bag {
+			bags.WriteString(" " + m.styles.TetriminoStyles[v].Render(string(v)))
+		}
+		if i < len(m.bags)-1 {
+			bags.WriteString("\n")
+		}
+	}
+
+	modes := make([]string, len(Modes))
+	for i, mode := range Modes {
+		if i == m.modeIndex {
+			modes[i] = m.styles.ModeSelected.Render("> " + mode)
+		} else {
+			modes[i] = m.styles.ModeUnselected.Render("  " + mode)
+		}
+	}
+
+	return m.styles.Title.Render(fmt.Sprintf("Seed %d", m.seed)) + "\n" +
+		m.styles.Bags.Render(bags.String()) + "\n" +
+		m.styles.Modes.Render(strings.Join(modes, "  ")) + "\n" +
+		m.help.View(m.keys)
+}
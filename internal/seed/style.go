@@ -0,0 +1,42 @@
+package seed
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Title          lipgloss.Style
+	Bags           lipgloss.Style
+	BagNumber      lipgloss.Style
+	Modes          lipgloss.Style
+	ModeSelected   lipgloss.Style
+	ModeUnselected lipgloss.Style
+
+	// TetriminoStyles colour the value of each tetrimino in the bags.
+	TetriminoStyles map[byte]lipgloss.Style
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for the seed explorer with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
+	s := Styles{
+		Title:           lipgloss.NewStyle().Bold(true).Foreground(t.Text).Padding(1, 2, 0),
+		Bags:            lipgloss.NewStyle().Padding(1, 2),
+		BagNumber:       lipgloss.NewStyle().Foreground(t.Muted),
+		Modes:           lipgloss.NewStyle().Padding(0, 2, 1),
+		ModeSelected:    lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		ModeUnselected:  lipgloss.NewStyle().Foreground(t.Muted),
+		TetriminoStyles: make(map[byte]lipgloss.Style, len(t.Tetriminos)),
+	}
+	for value, colour := range t.Tetriminos {
+		s.TetriminoStyles[value] = lipgloss.NewStyle().Bold(true).Foreground(colour)
+	}
+	return &s
+}
@@ -0,0 +1,174 @@
+// Package serve hosts the game over SSH so that it can be played without installing anything.
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/menu"
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/activeterm"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const shutdownTimeout = 30 * time.Second
+
+type Input struct {
+	Addr        string         // address to listen on
+	HostKeyPath string         // path to the server's private key, which is generated if it does not exist
+	Config      *config.Config // config shared by every session
+	Records     *records.Store // records shared by every session, kept under each session's namespace (nil for none)
+	AutosaveDir string         // directory each session's games are snapshotted to, one file per namespace (empty for none)
+}
+
+// DefaultHostKeyPath returns the path of the host key in the user config directory.
+func DefaultHostKeyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "ssh_host_ed25519"), nil
+}
+
+// DefaultAutosaveDir returns the directory sessions' games are snapshotted to in the user config directory.
+func DefaultAutosaveDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "autosaves"), nil
+}
+
+// Run serves the game until the process is interrupted. Each session plays in its own menu model.
+func Run(in *Input) error {
+	s, err := wish.NewServer(
+		wish.WithAddress(in.Addr),
+		wish.WithHostKeyPath(in.HostKeyPath),
+		wish.WithMiddleware(
+			bm.Middleware(handler(in.Config, in.Records, in.AutosaveDir)),
+			activeterm.Middleware(),
+			logging.Middleware(),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-errs:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-done:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
+
+func handler(cfg *config.Config, store *records.Store, autosaveDir string) bm.Handler {
+	autosaves := &claims{}
+	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+		player := Namespace(s.User(), s.PublicKey())
+		// A game left by a dropped connection can be restored by the same player when they reconnect. Only one of their
+		// sessions at a time has the file, so that games played at once don't overwrite each other's snapshots.
+		path := autosavePath(autosaveDir, player)
+		if path != "" && !autosaves.claim(path) {
+			path = ""
+		}
+		if path != "" {
+			go func() {
+				<-s.Context().Done()
+				autosaves.release(path)
+			}()
+		}
+		m := menu.NewModel(&menu.Input{
+			Config:       cfg,
+			Player:       player,
+			Records:      store,
+			AutosavePath: path,
+			// Share text is copied to the player's clipboard rather than the server's.
+			Clipboard: s,
+			// Nobody may be watching a session left open, so its games pause rather than top out.
+			IdleTimeout: cfg.Idle(),
+		})
+		return m, []tea.ProgramOption{tea.WithMouseCellMotion()}
+	}
+}
+
+// autosavePath returns the file the games of the player with the namespace are snapshotted to in the directory, or an
+// empty string if there is no directory.
+func autosavePath(dir, namespace string) string {
+	if dir == "" {
+		return ""
+	}
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@._-", r) {
+			return r
+		}
+		return '_'
+	}, namespace)
+	return filepath.Join(dir, name+".json")
+}
+
+// claims are the autosave files in use by sessions, which no other session may use until they are released.
+type claims struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+// claim reports whether the path was claimed, which it can't be while another session has it.
+func (c *claims) claim(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paths[path] {
+		return false
+	}
+	if c.paths == nil {
+		c.paths = make(map[string]bool)
+	}
+	c.paths[path] = true
+	return true
+}
+
+// release lets the path be claimed again once the session that had it ends.
+func (c *claims) release(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.paths, path)
+}
+
+// Namespace returns the name that a session's records are kept under.
+// Usernames are not authenticated, so the public key's fingerprint is included when one was offered.
+func Namespace(user string, key ssh.PublicKey) string {
+	if key == nil {
+		return user
+	}
+	fingerprint := strings.TrimPrefix(gossh.FingerprintSHA256(key), "SHA256:")
+	return fmt.Sprintf("%s@%s", user, fingerprint[:8])
+}
@@ -0,0 +1,61 @@
+package serve
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestNamespace(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ns := Namespace("alice", nil); ns != "alice" {
+		t.Errorf("No key: want %q, got %q", "alice", ns)
+	}
+
+	ns := Namespace("alice", key)
+	if ns == "alice" || ns[:6] != "alice@" {
+		t.Errorf("Key: want alice@<fingerprint>, got %q", ns)
+	}
+	if again := Namespace("alice", key); again != ns {
+		t.Errorf("Same key: want %q, got %q", ns, again)
+	}
+}
+
+func TestAutosavePath(t *testing.T) {
+	if path := autosavePath("", "alice"); path != "" {
+		t.Errorf("No directory: want none, got %q", path)
+	}
+	if path := autosavePath("saves", "alice@ab+c/d.."); path != filepath.Join("saves", "alice@ab_c_d...json") {
+		t.Errorf("Namespace: want the unsafe characters replaced, got %q", path)
+	}
+	if path := autosavePath("saves", "../../etc"); filepath.Dir(path) != "saves" {
+		t.Errorf("Traversal: want a file in the directory, got %q", path)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	var c claims
+	if !c.claim("alice.json") {
+		t.Fatal("First: want claimed")
+	}
+	if c.claim("alice.json") {
+		t.Error("Claimed: want another session refused")
+	}
+	if !c.claim("bob.json") {
+		t.Error("Other path: want claimed")
+	}
+	c.release("alice.json")
+	if !c.claim("alice.json") {
+		t.Error("Released: want claimed again")
+	}
+}
@@ -0,0 +1,110 @@
+// Package share formats the results of a game as a short summary to paste into chats, like the share cards of word
+// games.
+package share
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// emoji are the squares each cell value is drawn with, using the nearest colour to the guideline theme.
+var emoji = map[byte]string{
+	'I':                 "🟦",
+	'J':                 "🟫",
+	'L':                 "🟧",
+	'O':                 "🟨",
+	'S':                 "🟩",
+	'T':                 "🟪",
+	'Z':                 "🟥",
+	tetris.GarbageValue: "⬜",
+}
+
+// empty is the square empty cells are drawn with.
+const empty = "⬛"
+
+// Card is the summary of a finished game.
+type Card struct {
+	Mode   string // name of the mode played, such as "Sprint (40 lines)"
+	Score  uint
+	Lines  uint
+	Time   time.Duration
+	Pieces uint
+	PPS    float64
+	Seed   int64
+	Matrix tetris.Matrix // the final stack
+
+	// Race is whether the game was to finish as fast as possible, so the time is given before the score.
+	Race bool
+}
+
+// String returns the text of the card: a line naming the mode, a line of results, the seed and the final stack.
+func (c *Card) String() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("tetrigo %s\n", c.Mode))
+
+	results := []string{
+		fmt.Sprintf("%d points", c.Score),
+		fmt.Sprintf("%d lines", c.Lines),
+		formatTime(c.Time),
+	}
+	if c.Race {
+		results[0], results[2] = results[2], results[0]
+	}
+	results = append(results, fmt.Sprintf("%.2f PPS", c.PPS))
+	output.WriteString(strings.Join(results, " · ") + "\n")
+	output.WriteString(fmt.Sprintf("Seed %d\n", c.Seed))
+
+	if board := Board(c.Matrix); board != "" {
+		output.WriteString("\n" + board)
+	}
+	return output.String()
+}
+
+// Board draws the stack in the visible part of the matrix as rows of coloured squares, from its highest row down.
+// It returns an empty string if the matrix is empty. Ghost and hold preview cells are left out.
+func Board(m tetris.Matrix) string {
+	rows := m.Visible()
+	top := len(rows)
+	for row := range rows {
+		if !isRowEmpty(rows[row]) {
+			top = row
+			break
+		}
+	}
+
+	var output strings.Builder
+	for _, row := range rows[top:] {
+		for _, cell := range row {
+			if square, ok := emoji[cell]; ok {
+				output.WriteString(square)
+			} else {
+				output.WriteString(empty)
+			}
+		}
+		output.WriteString("\n")
+	}
+	return output.String()
+}
+
+func isRowEmpty(row []byte) bool {
+	for _, cell := range row {
+		if _, ok := emoji[cell]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// formatTime gives the time to the millisecond, as minutes and seconds once it is a minute or longer.
+func formatTime(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+	minutes := int(d / time.Minute)
+	seconds := (d % time.Minute).Seconds()
+	if minutes > 0 {
+		return fmt.Sprintf("%d:%06.3f", minutes, seconds)
+	}
+	return fmt.Sprintf("%.3fs", seconds)
+}
@@ -0,0 +1,83 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestBoard(t *testing.T) {
+	tt := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			"empty",
+			"",
+			"",
+		},
+		{
+			"stack",
+			`
+				....T.....
+				XXXTTT.XXI
+			`,
+			"⬛⬛⬛⬛🟪⬛⬛⬛⬛⬛\n⬜⬜⬜🟪🟪🟪⬛⬜⬜🟦\n",
+		},
+		{
+			"gap beneath top row",
+			`
+				O.........
+				..........
+				SS........
+			`,
+			"🟨⬛⬛⬛⬛⬛⬛⬛⬛⬛\n⬛⬛⬛⬛⬛⬛⬛⬛⬛⬛\n🟩🟩⬛⬛⬛⬛⬛⬛⬛⬛\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := tetris.ParseMatrix(tc.text)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if actual := Board(m); actual != tc.expected {
+				t.Errorf("want\n%v\ngot\n%v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCard_String(t *testing.T) {
+	m, err := tetris.ParseMatrix("ZZ..IIII..")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		card     Card
+		expected string
+	}{
+		{
+			"score",
+			Card{Mode: "Marathon", Score: 1200, Lines: 12, Time: 95*time.Second + 250*time.Millisecond, PPS: 1.234, Seed: 42},
+			"tetrigo Marathon\n1200 points · 12 lines · 1:35.250 · 1.23 PPS\nSeed 42\n",
+		},
+		{
+			"race",
+			Card{Mode: "Sprint (40 lines)", Score: 5000, Lines: 40, Time: 45 * time.Second, PPS: 2, Seed: 7, Race: true, Matrix: m},
+			"tetrigo Sprint (40 lines)\n45.000s · 40 lines · 5000 points · 2.00 PPS\nSeed 7\n\n🟥🟥⬛⬛🟦🟦🟦🟦⬛⬛\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.card.String(); actual != tc.expected {
+				t.Errorf("want\n%q\ngot\n%q", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package spectate
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+// Broadcaster sends the state of a game to every spectator connected to its listener.
+type Broadcaster struct {
+	listener net.Listener
+	settings netplay.Settings
+
+	mu         sync.Mutex
+	spectators map[*netplay.Conn]struct{}
+}
+
+// Listen starts accepting spectators on the given network address.
+func Listen(network, addr string, settings netplay.Settings) (*Broadcaster, error) {
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %q: %w", addr, err)
+	}
+	return NewBroadcaster(l, settings), nil
+}
+
+// ListenUnix starts accepting spectators on a Unix socket at the given path.
+// A socket left behind by a game that did not shut down cleanly is replaced, but one still in use is not.
+func ListenUnix(path string, settings netplay.Settings) (*Broadcaster, error) {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		if conn, dialErr := net.Dial("unix", path); dialErr == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another game is already broadcasting on %q", path)
+		}
+		if removeErr := os.Remove(path); removeErr != nil {
+			return nil, fmt.Errorf("failed to listen on %q: %w", path, err)
+		}
+		l, err = net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %q: %w", path, err)
+		}
+	}
+	return NewBroadcaster(l, settings), nil
+}
+
+// DefaultSocketPath returns the path of the Unix socket used to watch games on the same machine.
+func DefaultSocketPath() string {
+	return filepath.Join(os.TempDir(), "tetrigo.sock")
+}
+
+// NewBroadcaster starts accepting spectators on the listener. Each is sent the game settings when they connect.
+func NewBroadcaster(l net.Listener, settings netplay.Settings) *Broadcaster {
+	b := &Broadcaster{
+		listener:   l,
+		settings:   settings,
+		spectators: make(map[*netplay.Conn]struct{}),
+	}
+	go b.serve()
+	return b
+}
+
+func (b *Broadcaster) serve() {
+	for {
+		conn, err := netplay.Accept(b.listener, b.settings)
+		if err != nil {
+			// The listener has been closed.
+			return
+		}
+
+		b.mu.Lock()
+		b.spectators[conn] = struct{}{}
+		b.mu.Unlock()
+	}
+}
+
+// Spectators returns the number of connected spectators.
+func (b *Broadcaster) Spectators() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.spectators)
+}
+
+// Publish sends a message to every spectator, disconnecting any that can no longer be reached.
+func (b *Broadcaster) Publish(msg netplay.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.spectators {
+		if err := conn.Send(msg); err != nil {
+			conn.Close()
+			delete(b.spectators, conn)
+		}
+	}
+}
+
+// Close stops accepting spectators and disconnects those already connected.
+func (b *Broadcaster) Close() error {
+	err := b.listener.Close()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.spectators {
+		conn.Close()
+		delete(b.spectators, conn)
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package spectate
+
+import (
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+func TestBroadcaster_Publish(t *testing.T) {
+	settings := netplay.Settings{Seed: 1234, Level: 5}
+	b, err := Listen("tcp", "127.0.0.1:0", settings)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	defer b.Close()
+
+	spectators := make([]*netplay.Conn, 2)
+	for i := range spectators {
+		conn, gotSettings, err := netplay.Join(b.listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Spectator %d: expected nil, got error: %v", i, err)
+		}
+		defer conn.Close()
+		if *gotSettings != settings {
+			t.Errorf("Spectator %d settings: want %+v, got %+v", i, settings, *gotSettings)
+		}
+		spectators[i] = conn
+	}
+
+	// Spectators are added once the broadcaster has sent them the hello, which may be after they receive it.
+	for deadline := time.Now().Add(time.Second); b.Spectators() < len(spectators); {
+		if time.Now().After(deadline) {
+			t.Fatalf("Spectators: want %d, got %d", len(spectators), b.Spectators())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	msg := netplay.Message{Type: netplay.TypeState, Board: &netplay.Board{Score: 100, Hold: "T", Queue: "IOJ"}}
+	b.Publish(msg)
+
+	for i, conn := range spectators {
+		got, err := conn.Receive()
+		if err != nil {
+			t.Fatalf("Spectator %d: expected nil, got error: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, msg) {
+			t.Errorf("Spectator %d message: want %+v, got %+v", i, msg, got)
+		}
+	}
+}
+
+func TestBroadcaster_PublishDisconnected(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	b := NewBroadcaster(l, netplay.Settings{})
+	defer b.Close()
+
+	conn, _, err := netplay.Join(l.Addr().String())
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	for b.Spectators() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	conn.Close()
+
+	// Writes to a closed connection may succeed until the peer's reset arrives.
+	for deadline := time.Now().Add(time.Second); b.Spectators() > 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Spectators: want 0, got %d", b.Spectators())
+		}
+		b.Publish(netplay.Message{Type: netplay.TypeState, Board: &netplay.Board{}})
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBuffer_Pop(t *testing.T) {
+	start := time.Now()
+	b := buffer{delay: 300 * time.Millisecond}
+	for i := 0; i < 3; i++ {
+		b.push(netplay.Message{Type: netplay.TypeState, Board: &netplay.Board{Score: uint(i)}}, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	tt := []struct {
+		name          string
+		after         time.Duration
+		expectsMsg    bool
+		expectedScore uint
+		expectedLeft  int
+	}{
+		{"before delay", 200 * time.Millisecond, false, 0, 3},
+		{"first ready", 300 * time.Millisecond, true, 0, 2},
+		{"skips to latest ready", 550 * time.Millisecond, true, 2, 0},
+		{"empty", time.Second, false, 0, 0},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, ok := b.pop(start.Add(tc.after))
+			if ok != tc.expectsMsg {
+				t.Fatalf("Ready: want %t, got %t", tc.expectsMsg, ok)
+			}
+			if ok && msg.Board.Score != tc.expectedScore {
+				t.Errorf("Score: want %d, got %d", tc.expectedScore, msg.Board.Score)
+			}
+			if len(b.frames) != tc.expectedLeft {
+				t.Errorf("Frames left: want %d, got %d", tc.expectedLeft, len(b.frames))
+			}
+		})
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tetrigo.sock")
+
+	// A socket file left behind by a game that exited without closing it.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	b, err := ListenUnix(path, netplay.Settings{})
+	if err != nil {
+		t.Fatalf("Stale socket: expected nil, got error: %v", err)
+	}
+	defer b.Close()
+
+	conn, _, err := netplay.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial: expected nil, got error: %v", err)
+	}
+	conn.Close()
+
+	_, err = ListenUnix(path, netplay.Settings{})
+	if err == nil {
+		t.Errorf("Socket in use: expected error, got nil")
+	}
+}
@@ -0,0 +1,33 @@
+package spectate
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+type frame struct {
+	at  time.Time
+	msg netplay.Message
+}
+
+// buffer holds back received messages for a short delay, so that network jitter does not make the game stutter.
+type buffer struct {
+	delay  time.Duration
+	frames []frame
+}
+
+func (b *buffer) push(msg netplay.Message, at time.Time) {
+	b.frames = append(b.frames, frame{at: at, msg: msg})
+}
+
+// pop returns the latest message that has been held for at least the delay, discarding any older ones.
+func (b *buffer) pop(now time.Time) (netplay.Message, bool) {
+	var msg netplay.Message
+	var ok bool
+	for len(b.frames) > 0 && now.Sub(b.frames[0].at) >= b.delay {
+		msg, ok = b.frames[0].msg, true
+		b.frames = b.frames[1:]
+	}
+	return msg, ok
+}
@@ -0,0 +1,27 @@
+package spectate
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit key.Binding
+}
+
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit: key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Quit,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Quit,
+		},
+	}
+}
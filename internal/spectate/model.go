@@ -0,0 +1,210 @@
+// Package spectate lets other terminals watch a game live, without being able to affect it.
+package spectate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// frameInterval is the time between each check for a board that is ready to be shown.
+	frameInterval = 50 * time.Millisecond
+
+	// publishInterval is the time between each board sent to spectators.
+	publishInterval = 100 * time.Millisecond
+)
+
+type Input struct {
+	Delay     time.Duration // how long received boards are held back before being shown
+	BoardOnly bool          // whether only the matrix is shown, such as for capturing in a stream
+	Theme     *theme.Theme  // colour scheme the game is drawn with (nil for the default)
+}
+
+// Model watches a game broadcast by another tetrigo instance.
+type Model struct {
+	conn         *netplay.Conn
+	buffer       buffer
+	board        *netplay.Board // the board currently shown
+	gameOver     bool
+	disconnected bool
+	boardOnly    bool
+
+	keys       *KeyMap
+	styles     *Styles
+	gameStyles *marathon.Styles
+	help       help.Model
+}
+
+func NewModel(conn *netplay.Conn, in *Input) *Model {
+	return &Model{
+		conn:       conn,
+		buffer:     buffer{delay: in.Delay},
+		board:      &netplay.Board{},
+		boardOnly:  in.BoardOnly,
+		keys:       DefaultKeyMap(),
+		styles:     NewStyles(in.Theme),
+		gameStyles: marathon.NewStyles(in.Theme),
+		help:       theme.NewHelp(in.Theme),
+	}
+}
+
+// receivedMsg wraps a message received from the broadcaster.
+type receivedMsg struct {
+	msg netplay.Message
+}
+
+type disconnectedMsg struct{}
+
+type frameTickMsg struct{}
+
+func (m Model) receive() tea.Cmd {
+	return func() tea.Msg {
+		msg, err := m.conn.Receive()
+		if err != nil {
+			return disconnectedMsg{}
+		}
+		return receivedMsg{msg: msg}
+	}
+}
+
+func frameTick() tea.Cmd {
+	return tea.Tick(frameInterval, func(_ time.Time) tea.Msg {
+		return frameTickMsg{}
+	})
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.receive(), frameTick())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit) {
+			return m, tea.Quit
+		}
+	case receivedMsg:
+		m.buffer.push(msg.msg, time.Now())
+		return m, m.receive()
+	case disconnectedMsg:
+		m.disconnected = true
+	case frameTickMsg:
+		if msg, ok := m.buffer.pop(time.Now()); ok {
+			if msg.Board != nil {
+				m.board = msg.Board
+			}
+			if msg.Type == netplay.TypeGameOver {
+				m.gameOver = true
+			}
+		}
+		return m, frameTick()
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	if m.boardOnly {
+		return marathon.BoardView(m.gameStyles, &m.board.Matrix)
+	}
+
+	info := fmt.Sprintln(m.styles.text("Score")+": ", m.board.Score) +
+		fmt.Sprintln(m.styles.text("Level")+": ", m.board.Level) +
+		fmt.Sprintln(m.styles.text("Cleared")+": ", m.board.Lines) +
+		fmt.Sprintln(m.styles.text("Time")+": ", m.board.Time.Round(time.Millisecond))
+
+	var queue []tetris.Tetrimino
+	for i := range m.board.Queue {
+		if t, ok := tetrimino(m.board.Queue[i]); ok {
+			queue = append(queue, t)
+		}
+	}
+
+	output := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Right,
+			marathon.HoldView(m.gameStyles, m.board.Hold),
+			m.gameStyles.Information.Render(info),
+		),
+		marathon.BoardView(m.gameStyles, &m.board.Matrix),
+		marathon.QueueView(m.gameStyles, queue),
+	)
+
+	// Boards still in the buffer are shown before reporting the disconnection.
+	if m.gameOver {
+		output += "\n" + m.styles.Status.Render(m.styles.text("GAME OVER"))
+	} else if m.disconnected && len(m.buffer.frames) == 0 {
+		output += "\n" + m.styles.Status.Render(m.styles.text("Broadcast ended"))
+	}
+
+	return output + "\n" + m.help.View(m.keys)
+}
+
+func tetrimino(value byte) (tetris.Tetrimino, bool) {
+	for _, t := range tetris.Tetriminos {
+		if t.Value == value {
+			return t, true
+		}
+	}
+	return tetris.Tetrimino{}, false
+}
+
+// BroadcastModel plays a game while publishing its state to spectators of each broadcaster.
+type BroadcastModel struct {
+	game         tea.Model
+	broadcasters []*Broadcaster
+}
+
+func NewBroadcastModel(game *marathon.Model, broadcasters ...*Broadcaster) *BroadcastModel {
+	return &BroadcastModel{
+		game:         *game,
+		broadcasters: broadcasters,
+	}
+}
+
+type publishTickMsg struct{}
+
+func publishTick() tea.Cmd {
+	return tea.Tick(publishInterval, func(_ time.Time) tea.Msg {
+		return publishTickMsg{}
+	})
+}
+
+// publish sends the current board to spectators without blocking the game.
+func (m BroadcastModel) publish(msgType netplay.MessageType) tea.Cmd {
+	msg := netplay.Message{Type: msgType, Board: m.game.(marathon.Model).Snapshot()}
+	return func() tea.Msg {
+		for _, b := range m.broadcasters {
+			b.Publish(msg)
+		}
+		return nil
+	}
+}
+
+func (m BroadcastModel) Init() tea.Cmd {
+	return tea.Batch(m.game.Init(), publishTick())
+}
+
+func (m BroadcastModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(publishTickMsg); ok {
+		return m, tea.Batch(m.publish(netplay.TypeState), publishTick())
+	}
+
+	var cmd tea.Cmd
+	m.game, cmd = m.game.Update(msg)
+	if _, ok := msg.(marathon.GameOverMsg); ok {
+		return m, tea.Batch(cmd, m.publish(netplay.TypeGameOver))
+	}
+	return m, cmd
+}
+
+func (m BroadcastModel) View() string {
+	return m.game.View()
+}
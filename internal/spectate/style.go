@@ -0,0 +1,34 @@
+package spectate
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Status lipgloss.Style
+
+	locale *locale.Locale
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(nil)
+}
+
+// NewStyles creates the styles for the spectated game's status, showing its text in the language of the given theme
+// (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	s := Styles{
+		Status: lipgloss.NewStyle().Bold(true).Padding(0, 2),
+	}
+	if t != nil {
+		s.locale = t.Locale
+	}
+	return &s
+}
+
+// text returns the English text in the language of the styles' theme.
+func (s *Styles) text(english string) string {
+	return s.locale.T(english)
+}
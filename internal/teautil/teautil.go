@@ -0,0 +1,14 @@
+// Package teautil holds helpers shared by the models which run other models in turn.
+package teautil
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Resize returns a command which sends the size to a newly created model, if it is known. Models only receive the
+// size of the terminal when the program starts and when it changes, so those created later need to be told it.
+func Resize(size *tea.WindowSizeMsg) tea.Cmd {
+	if size == nil {
+		return nil
+	}
+	msg := *size
+	return func() tea.Msg { return msg }
+}
@@ -0,0 +1,99 @@
+package theme
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	// LevelShiftLevels is how many levels the colours of a theme with level colours stay the same for before shifting.
+	LevelShiftLevels = 5
+
+	// levelHueShift is how far around the colour wheel the colours of tetriminos turn at each shift, in degrees, so
+	// that they come back around after 15 shifts.
+	levelHueShift = 24.0
+)
+
+// WithLevelColours returns a copy of the theme whose colours shift as the level increases (see AtLevel).
+func (t *Theme) WithLevelColours() *Theme {
+	shifting := *t
+	shifting.LevelColours = true
+	return &shifting
+}
+
+// LevelShift returns how many times the colours of a theme with level colours have shifted by the level, counting from
+// level 1.
+func LevelShift(level uint) int {
+	return int(max(level, 1)-1) / LevelShiftLevels
+}
+
+// AtLevel returns a copy of the theme with the colours of tetriminos, garbage and the playfield's grid turned around
+// the colour wheel for the level, as classic games change their palette to give a sense of progress. Greys, colours
+// left to the terminal and themes without level colours are unchanged.
+func (t *Theme) AtLevel(level uint) *Theme {
+	shift := LevelShift(level)
+	if !t.LevelColours || shift == 0 {
+		return t
+	}
+	return t.mapColours(func(_ byte, c lipgloss.Color) lipgloss.Color {
+		return turn(c, float64(shift)*levelHueShift)
+	})
+}
+
+// Blend returns a copy of the theme with the colours AtLevel changes moved part of the way towards those of the other
+// theme, by the fraction from 0 (unchanged) to 1 (the other's), so that one palette can fade into the next.
+func (t *Theme) Blend(other *Theme, fraction float64) *Theme {
+	fraction = min(max(fraction, 0), 1)
+	return t.mapColours(func(value byte, c lipgloss.Color) lipgloss.Color {
+		to := other.Grid
+		if value != 0 {
+			to = other.Tetriminos[value]
+		}
+		return blend(c, to, fraction)
+	})
+}
+
+// mapColours returns a copy of the theme with the colour of each tetrimino, and garbage, replaced as given by its value
+// and the grid's by 0.
+func (t *Theme) mapColours(f func(value byte, c lipgloss.Color) lipgloss.Color) *Theme {
+	mapped := *t
+	mapped.Tetriminos = make(map[byte]lipgloss.Color, len(t.Tetriminos))
+	for value, c := range t.Tetriminos {
+		mapped.Tetriminos[value] = f(value, c)
+	}
+	mapped.Grid = f(0, t.Grid)
+	return &mapped
+}
+
+// turn returns the colour turned around the colour wheel by the given number of degrees, keeping its lightness and
+// chroma. Colours which aren't given in hex, such as ANSI colours, are returned unchanged.
+func turn(c lipgloss.Color, degrees float64) lipgloss.Color {
+	col, err := colorful.Hex(string(c))
+	if err != nil {
+		return c
+	}
+	h, chroma, l := col.Hcl()
+	return hex(colorful.Hcl(math.Mod(h+degrees, 360), chroma, l))
+}
+
+// blend returns the colour the fraction of the way from one colour to the other. Unless both are given in hex, it
+// switches from one to the other halfway.
+func blend(from, to lipgloss.Color, fraction float64) lipgloss.Color {
+	a, errFrom := colorful.Hex(string(from))
+	b, errTo := colorful.Hex(string(to))
+	if errFrom != nil || errTo != nil {
+		if fraction < 0.5 {
+			return from
+		}
+		return to
+	}
+	return hex(a.BlendHcl(b, fraction))
+}
+
+// hex returns the colour in hex, in capitals like the themes' colours.
+func hex(c colorful.Color) lipgloss.Color {
+	return lipgloss.Color(strings.ToUpper(c.Clamped().Hex()))
+}
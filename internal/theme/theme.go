@@ -0,0 +1,462 @@
+// Package theme defines the colour schemes and borders used to draw the game.
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a colour scheme shared by every view in the game. An empty colour uses the terminal's default.
+type Theme struct {
+	Name string
+
+	// Tetriminos maps the value of each tetrimino, and garbage, to the colour of its cells.
+	Tetriminos map[byte]lipgloss.Color
+
+	// Patterns maps the value of each tetrimino, and garbage, to the two characters its cells are filled with, so
+	// pieces can be told apart by shape alone (nil to fill every cell alike).
+	Patterns map[byte]string
+
+	Border lipgloss.Border // border around the playfield and hold box
+	Grid   lipgloss.Color  // markers in empty cells of the playfield
+	Ghost  lipgloss.Color  // where the current tetrimino will land
+
+	Text   lipgloss.Color // primary text, such as selected menu options
+	Subtle lipgloss.Color // text that should be readable without drawing attention, such as hints
+	Muted  lipgloss.Color // secondary text, such as row numbers and unselected menu options
+	Accent lipgloss.Color // text that should stand out, such as the countdown
+	Danger lipgloss.Color // game over messages
+
+	// ASCII is whether the game is drawn using only ASCII characters, for terminals and fonts without box drawing
+	// and block characters.
+	ASCII bool
+
+	// Letters is whether each cell is marked with the value of its tetrimino, so pieces can be told apart without
+	// relying on colour.
+	Letters bool
+
+	// LowVision is whether the game is drawn at a larger size, with each cell two rows tall and four columns wide and
+	// text panels spaced out and in bold, for low-vision players on large terminals.
+	LowVision bool
+
+	// ScreenReader is whether games are described in short lines of text as they change, such as each tetrimino
+	// spawning and the lines it clears, for terminal screen readers, rather than drawn.
+	ScreenReader bool
+
+	// DangerRow is the row of the playfield, numbered from the top as beside it, which the stack reaching turns the
+	// border the danger colour, warning that it is close to topping out (0 to never warn).
+	DangerRow int
+
+	// Locale is the language the text of the interface is shown in (nil for English).
+	Locale *locale.Locale
+
+	// Skin is the characters cells are drawn with, chosen independently of the colours (nil for the default).
+	Skin *Skin
+
+	// Frame is what is drawn around the playfield: FrameBox, FrameWell or FrameNone (empty for a box).
+	Frame string
+
+	// Queue is where the next tetriminos are shown: QueueSide, QueueAbove or QueueBelow the playfield (empty for the
+	// side).
+	Queue string
+
+	// GridLines is whether faint lines are drawn between the cells of the playfield, in place of the column markers.
+	GridLines bool
+
+	// HideIndicators is whether the row numbers beside the playfield and the column markers in its empty cells are
+	// left out.
+	HideIndicators bool
+
+	// LevelColours is whether the colours of tetriminos and the grid shift every few levels (see AtLevel).
+	LevelColours bool
+}
+
+// The frames which can be drawn around the playfield.
+const (
+	FrameBox  = "box"  // a border on every side
+	FrameWell = "well" // a border on the sides and bottom, open at the top
+	FrameNone = "none" // no border
+)
+
+// FrameNames are the names of the frames which can be drawn around the playfield, with the default first.
+var FrameNames = []string{FrameBox, FrameWell, FrameNone}
+
+// The places the next tetriminos can be shown.
+const (
+	QueueSide  = "side"  // in a column to the right of the playfield
+	QueueAbove = "above" // in a row above the playfield, for narrow terminals
+	QueueBelow = "below" // in a row below the playfield, for narrow terminals
+)
+
+// QueueNames are the names of the places the next tetriminos can be shown, with the default first.
+var QueueNames = []string{QueueSide, QueueAbove, QueueBelow}
+
+// Skin is a set of characters cells are drawn with. Each is two characters wide.
+type Skin struct {
+	Name   string
+	Filled string // a cell occupied by a tetrimino or garbage
+	Ghost  string // a cell of the ghost or hold preview
+	Empty  string // an empty cell of the playfield
+	Grid   string // an empty cell of the playfield drawn with grid lines, along its bottom and right edges
+}
+
+// ASCII reports whether the skin is drawn using only ASCII characters, so that it can be used in ASCII mode.
+func (s *Skin) ASCII() bool {
+	for _, r := range s.Filled + s.Ghost + s.Empty + s.Grid {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultSkin is the name of the skin used when none is chosen.
+const DefaultSkin = "blocks"
+
+var skins = map[string]*Skin{
+	"blocks":   {Name: "blocks", Filled: "██", Ghost: "░░", Empty: "▕ ", Grid: "▁▕"},
+	"brackets": {Name: "brackets", Filled: "[]", Ghost: "::", Empty: " .", Grid: "_|"},
+	"squares":  {Name: "squares", Filled: "▣▣", Ghost: "▢▢", Empty: " ·", Grid: "▁▕"},
+	"braille":  {Name: "braille", Filled: "⣿⣿", Ghost: "⠶⠶", Empty: "⠀⠂", Grid: "⣀⢸"},
+}
+
+// GetSkin returns the built-in skin with the given name.
+func GetSkin(name string) (*Skin, error) {
+	s, ok := skins[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid skin %q", name)
+	}
+	return s, nil
+}
+
+// SkinNames returns the names of the built-in skins, with the default first and the rest sorted alphabetically.
+func SkinNames() []string {
+	names := make([]string, 0, len(skins))
+	for name := range skins {
+		if name != DefaultSkin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultSkin}, names...)
+}
+
+// ASCIIBorder is the border used in place of the theme's border when drawing with only ASCII characters.
+var ASCIIBorder = lipgloss.Border{
+	Top:         "-",
+	Bottom:      "-",
+	Left:        "|",
+	Right:       "|",
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+}
+
+// ASCIIPatterns are the patterns used in place of the theme's patterns, if it has any, when drawing with only ASCII
+// characters.
+var ASCIIPatterns = map[byte]string{
+	'I': "##",
+	'O': "@@",
+	'T': "%%",
+	'S': "//",
+	'Z': `\\`,
+	'J': "==",
+	'L': "++",
+	'X': "xx",
+}
+
+// DefaultName is the name of the theme used when none is chosen.
+const DefaultName = "guideline"
+
+var themes = map[string]*Theme{
+	"guideline": {
+		Name: "guideline",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "#64C4EB",
+			'O': "#F1D448",
+			'T': "#A15398",
+			'S': "#64B452",
+			'Z': "#DC3A35",
+			'J': "#5C65A8",
+			'L': "#E07F3A",
+			'X': "#7C7C7C",
+		},
+		Border: lipgloss.RoundedBorder(),
+		Grid:   "#303040",
+		Ghost:  "",
+		Text:   "",
+		Subtle: "#A0A0B0",
+		Muted:  "#444049",
+		Accent: "#F1D448",
+		Danger: "#DC3A35",
+	},
+	"monochrome": {
+		Name: "monochrome",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "#F0F0F0",
+			'O': "#D8D8D8",
+			'T': "#C0C0C0",
+			'S': "#A8A8A8",
+			'Z': "#909090",
+			'J': "#787878",
+			'L': "#B4B4B4",
+			'X': "#5A5A5A",
+		},
+		Border: lipgloss.NormalBorder(),
+		Grid:   "#303030",
+		Ghost:  "#808080",
+		Text:   "#FFFFFF",
+		Subtle: "#A0A0A0",
+		Muted:  "#505050",
+		Accent: "#FFFFFF",
+		Danger: "#FFFFFF",
+	},
+	"pastel": {
+		Name: "pastel",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "#A8DEF0",
+			'O': "#FBEBA4",
+			'T': "#D4B0E0",
+			'S': "#B5E2A8",
+			'Z': "#F4A9A8",
+			'J': "#AEB6E6",
+			'L': "#F8C8A0",
+			'X': "#B8B8C0",
+		},
+		Border: lipgloss.RoundedBorder(),
+		Grid:   "#3A3A4A",
+		Ghost:  "#E0E0F0",
+		Text:   "#F8F8FF",
+		Subtle: "#B8B8D0",
+		Muted:  "#6A6A80",
+		Accent: "#FBEBA4",
+		Danger: "#F4A9A8",
+	},
+	// colourblind uses the Okabe-Ito palette, which stays distinguishable with deuteranopia and protanopia.
+	"colourblind": {
+		Name: "colourblind",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "#56B4E9",
+			'O': "#F0E442",
+			'T': "#CC79A7",
+			'S': "#009E73",
+			'Z': "#D55E00",
+			'J': "#0072B2",
+			'L': "#E69F00",
+			'X': "#999999",
+		},
+		Border: lipgloss.RoundedBorder(),
+		Grid:   "#303030",
+		Ghost:  "#BBBBBB",
+		Text:   "#FFFFFF",
+		Subtle: "#BBBBBB",
+		Muted:  "#777777",
+		Accent: "#F0E442",
+		Danger: "#D55E00",
+	},
+	// high-contrast uses fully saturated colours and the brightest text on the terminal's background, with a bold
+	// border, for low-vision players.
+	"high-contrast": {
+		Name: "high-contrast",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "#00FFFF",
+			'O': "#FFFF00",
+			'T': "#FF00FF",
+			'S': "#00FF00",
+			'Z': "#FF0000",
+			'J': "#5F87FF",
+			'L': "#FF8700",
+			'X': "#FFFFFF",
+		},
+		Border: lipgloss.ThickBorder(),
+		Grid:   "#808080",
+		Ghost:  "#FFFFFF",
+		Text:   "#FFFFFF",
+		Subtle: "#FFFFFF",
+		Muted:  "#C0C0C0",
+		Accent: "#FFFF00",
+		Danger: "#FF0000",
+	},
+	// shapes draws everything in the terminal's own colour, telling tetriminos apart by the pattern of their cells
+	// alone.
+	"shapes": {
+		Name: "shapes",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "",
+			'O': "",
+			'T': "",
+			'S': "",
+			'Z': "",
+			'J': "",
+			'L': "",
+			'X': "",
+		},
+		Patterns: map[byte]string{
+			'I': "██",
+			'O': "▓▓",
+			'T': "▒▒",
+			'S': "▞▞",
+			'Z': "▚▚",
+			'J': "▄▄",
+			'L': "▀▀",
+			'X': "╳╳",
+		},
+		Border: lipgloss.ThickBorder(),
+		Grid:   "",
+		Ghost:  "",
+		Text:   "",
+		Subtle: "",
+		Muted:  "",
+		Accent: "",
+		Danger: "",
+	},
+	"nes": {
+		Name: "nes",
+		Tetriminos: map[byte]lipgloss.Color{
+			'I': "#3CBCFC",
+			'O': "#FCFCFC",
+			'T': "#0058F8",
+			'S': "#B8F818",
+			'Z': "#F83800",
+			'J': "#0058F8",
+			'L': "#F83800",
+			'X': "#7C7C7C",
+		},
+		Border: lipgloss.ThickBorder(),
+		Grid:   "#1C1C1C",
+		Ghost:  "#BCBCBC",
+		Text:   "#FCFCFC",
+		Subtle: "#BCBCBC",
+		Muted:  "#7C7C7C",
+		Accent: "#F8B800",
+		Danger: "#F83800",
+	},
+}
+
+// Default returns the theme used when none is chosen.
+func Default() *Theme {
+	return themes[DefaultName]
+}
+
+// Get returns the built-in theme with the given name.
+func Get(name string) (*Theme, error) {
+	t, ok := themes[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid theme %q", name)
+	}
+	return t, nil
+}
+
+// WithASCII returns a copy of the theme which is drawn using only ASCII characters.
+func (t *Theme) WithASCII() *Theme {
+	ascii := *t
+	ascii.Border = ASCIIBorder
+	if t.Patterns != nil {
+		ascii.Patterns = ASCIIPatterns
+	}
+	ascii.ASCII = true
+	return &ascii
+}
+
+// WithLowVision returns a copy of the theme which is drawn at a larger size with more contrast: secondary text is
+// drawn in the colour of hints, and hints in the colour of primary text.
+func (t *Theme) WithLowVision() *Theme {
+	lowVision := *t
+	lowVision.LowVision = true
+	lowVision.Muted = t.Subtle
+	lowVision.Subtle = t.Text
+	return &lowVision
+}
+
+// WithScreenReader returns a copy of the theme which describes games in text for screen readers, rather than drawing
+// them.
+func (t *Theme) WithScreenReader() *Theme {
+	screenReader := *t
+	screenReader.ScreenReader = true
+	return &screenReader
+}
+
+// WithDangerRow returns a copy of the theme which warns once the stack reaches the row, numbered from the top of the
+// playfield (0 to never warn).
+func (t *Theme) WithDangerRow(row int) *Theme {
+	danger := *t
+	danger.DangerRow = max(row, 0)
+	return &danger
+}
+
+// WithLocale returns a copy of the theme which shows the text of the interface in the locale's language.
+func (t *Theme) WithLocale(l *locale.Locale) *Theme {
+	localized := *t
+	localized.Locale = l
+	return &localized
+}
+
+// WithSkin returns a copy of the theme whose cells are drawn with the skin's characters.
+func (t *Theme) WithSkin(s *Skin) *Theme {
+	skinned := *t
+	skinned.Skin = s
+	return &skinned
+}
+
+// WithFrame returns a copy of the theme with the frame drawn around the playfield (see FrameNames).
+func (t *Theme) WithFrame(frame string) *Theme {
+	framed := *t
+	framed.Frame = frame
+	return &framed
+}
+
+// WithQueue returns a copy of the theme with the next tetriminos shown in the place (see QueueNames).
+func (t *Theme) WithQueue(queue string) *Theme {
+	placed := *t
+	placed.Queue = queue
+	return &placed
+}
+
+// WithGridLines returns a copy of the theme with faint lines drawn between the cells of the playfield.
+func (t *Theme) WithGridLines() *Theme {
+	grid := *t
+	grid.GridLines = true
+	return &grid
+}
+
+// WithoutIndicators returns a copy of the theme without row numbers beside the playfield or column markers in it.
+func (t *Theme) WithoutIndicators() *Theme {
+	plain := *t
+	plain.HideIndicators = true
+	return &plain
+}
+
+// NewHelp creates a help view for key bindings which is drawn with the theme's characters (nil for the default).
+func NewHelp(t *Theme) help.Model {
+	h := help.New()
+	if t != nil && t.ASCII {
+		h.ShortSeparator = " | "
+		h.Ellipsis = "..."
+	}
+	return h
+}
+
+// WithLetters returns a copy of the theme which marks each cell with the value of its tetrimino.
+func (t *Theme) WithLetters() *Theme {
+	letters := *t
+	letters.Letters = true
+	return &letters
+}
+
+// Names returns the names of the built-in themes, with the default first and the rest sorted alphabetically.
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		if name != DefaultName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultName}, names...)
+}
@@ -0,0 +1,302 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+)
+
+func TestThemes(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			theme, err := Get(name)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if theme.Name != name {
+				t.Errorf("Name: want %q, got %q", name, theme.Name)
+			}
+
+			for _, value := range []byte("IOTSZJLX") {
+				if _, ok := theme.Tetriminos[value]; !ok {
+					t.Errorf("missing colour for %c", value)
+				}
+			}
+		})
+	}
+}
+
+func TestGet_Invalid(t *testing.T) {
+	_, err := Get("missing")
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestTheme_WithASCII(t *testing.T) {
+	original := Default()
+	ascii := original.WithASCII()
+
+	if !ascii.ASCII {
+		t.Errorf("ASCII: want true, got false")
+	}
+	if ascii.Border != ASCIIBorder {
+		t.Errorf("Border: want %v, got %v", ASCIIBorder, ascii.Border)
+	}
+	if original.ASCII || original.Border == ASCIIBorder {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+	if ascii.Patterns != nil {
+		t.Errorf("Patterns: want nil for a theme without patterns, got %v", ascii.Patterns)
+	}
+}
+
+func TestTheme_Patterns(t *testing.T) {
+	shapes, err := Get("shapes")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	for name, patterns := range map[string]map[byte]string{
+		"unicode": shapes.Patterns,
+		"ascii":   shapes.WithASCII().Patterns,
+	} {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[string]byte)
+			for _, value := range []byte("IOTSZJLX") {
+				pattern, ok := patterns[value]
+				if !ok {
+					t.Fatalf("missing pattern for %c", value)
+				}
+				if width := len([]rune(pattern)); width != 2 {
+					t.Errorf("%c: want 2 characters, got %d", value, width)
+				}
+				if other, ok := seen[pattern]; ok {
+					t.Errorf("%c: want a pattern of its own, got the pattern of %c", value, other)
+				}
+				seen[pattern] = value
+			}
+		})
+	}
+}
+
+func TestTheme_WithLetters(t *testing.T) {
+	original := Default()
+	letters := original.WithLetters()
+
+	if !letters.Letters {
+		t.Errorf("Letters: want true, got false")
+	}
+	if original.Letters {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithLowVision(t *testing.T) {
+	original := Default()
+	lowVision := original.WithLowVision()
+
+	if !lowVision.LowVision {
+		t.Errorf("LowVision: want true, got false")
+	}
+	if lowVision.Muted != original.Subtle || lowVision.Subtle != original.Text {
+		t.Errorf("Contrast: want muted %q and subtle %q, got %q and %q",
+			original.Subtle, original.Text, lowVision.Muted, lowVision.Subtle)
+	}
+	if original.LowVision {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithScreenReader(t *testing.T) {
+	original := Default()
+	screenReader := original.WithScreenReader()
+
+	if !screenReader.ScreenReader {
+		t.Errorf("ScreenReader: want true, got false")
+	}
+	if original.ScreenReader {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithDangerRow(t *testing.T) {
+	original := Default()
+	danger := original.WithDangerRow(4)
+
+	if danger.DangerRow != 4 {
+		t.Errorf("DangerRow: want 4, got %d", danger.DangerRow)
+	}
+	if original.DangerRow != 0 {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+	if negative := original.WithDangerRow(-1); negative.DangerRow != 0 {
+		t.Errorf("Negative: want 0, got %d", negative.DangerRow)
+	}
+}
+
+func TestTheme_WithLocale(t *testing.T) {
+	original := Default()
+	es, err := locale.Get("es")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	localized := original.WithLocale(es)
+
+	if localized.Locale != es {
+		t.Errorf("Locale: want %v, got %v", es.Name(), localized.Locale.Name())
+	}
+	if original.Locale != nil {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestSkins(t *testing.T) {
+	for _, name := range SkinNames() {
+		t.Run(name, func(t *testing.T) {
+			skin, err := GetSkin(name)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if skin.Name != name {
+				t.Errorf("Name: want %q, got %q", name, skin.Name)
+			}
+			for _, glyph := range []string{skin.Filled, skin.Ghost, skin.Empty, skin.Grid} {
+				if width := len([]rune(glyph)); width != 2 {
+					t.Errorf("%q: want 2 characters, got %d", glyph, width)
+				}
+			}
+		})
+	}
+}
+
+func TestSkin_ASCII(t *testing.T) {
+	tt := map[string]bool{
+		"blocks":   false,
+		"brackets": true,
+		"braille":  false,
+	}
+
+	for name, want := range tt {
+		t.Run(name, func(t *testing.T) {
+			skin, err := GetSkin(name)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if got := skin.ASCII(); got != want {
+				t.Errorf("want %t, got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestTheme_WithSkin(t *testing.T) {
+	original := Default()
+	skin, err := GetSkin("brackets")
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	skinned := original.WithSkin(skin)
+
+	if skinned.Skin != skin {
+		t.Errorf("Skin: want %v, got %v", skin.Name, skinned.Skin)
+	}
+	if original.Skin != nil {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithFrame(t *testing.T) {
+	original := Default()
+	framed := original.WithFrame(FrameWell)
+
+	if framed.Frame != FrameWell {
+		t.Errorf("Frame: want %q, got %q", FrameWell, framed.Frame)
+	}
+	if original.Frame != "" {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithQueue(t *testing.T) {
+	original := Default()
+	placed := original.WithQueue(QueueAbove)
+
+	if placed.Queue != QueueAbove {
+		t.Errorf("Queue: want %q, got %q", QueueAbove, placed.Queue)
+	}
+	if original.Queue != "" {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithGridLines(t *testing.T) {
+	original := Default()
+	grid := original.WithGridLines()
+
+	if !grid.GridLines {
+		t.Errorf("GridLines: want true, got false")
+	}
+	if original.GridLines {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_WithoutIndicators(t *testing.T) {
+	original := Default()
+	plain := original.WithoutIndicators()
+
+	if !plain.HideIndicators {
+		t.Errorf("HideIndicators: want true, got false")
+	}
+	if original.HideIndicators {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+}
+
+func TestTheme_AtLevel(t *testing.T) {
+	original := Default()
+	if original.AtLevel(20) != original {
+		t.Errorf("want a theme without level colours unchanged")
+	}
+
+	shifting := original.WithLevelColours()
+	if !shifting.LevelColours || original.LevelColours {
+		t.Fatalf("WithLevelColours: want only the copy to have level colours")
+	}
+	for _, level := range []uint{0, 1, LevelShiftLevels} {
+		if got := shifting.AtLevel(level); got.Tetriminos['T'] != original.Tetriminos['T'] {
+			t.Errorf("level %d: want the colours unchanged until level %d, got %v", level, LevelShiftLevels+1, got.Tetriminos['T'])
+		}
+	}
+
+	shifted := shifting.AtLevel(LevelShiftLevels + 1)
+	if shifted.Tetriminos['T'] == original.Tetriminos['T'] || shifted.Grid == original.Grid {
+		t.Errorf("want the colours shifted, got %v and grid %v", shifted.Tetriminos['T'], shifted.Grid)
+	}
+	if shifted.Tetriminos['X'] != original.Tetriminos['X'] {
+		t.Errorf("want grey garbage unchanged, got %v", shifted.Tetriminos['X'])
+	}
+	if original.Tetriminos['T'] != "#A15398" {
+		t.Errorf("expected the original theme to be unchanged")
+	}
+	// The colours come back around once they have turned all the way.
+	if full := shifting.AtLevel(LevelShiftLevels*15 + 1); full.Tetriminos['T'] != original.Tetriminos['T'] {
+		t.Errorf("want the colours back around after 15 shifts, got %v", full.Tetriminos['T'])
+	}
+}
+
+func TestTheme_Blend(t *testing.T) {
+	from := Default().WithLevelColours()
+	to := from.AtLevel(LevelShiftLevels + 1)
+
+	if got := from.Blend(to, 0); got.Tetriminos['T'] != from.Tetriminos['T'] {
+		t.Errorf("0: want %v, got %v", from.Tetriminos['T'], got.Tetriminos['T'])
+	}
+	if got := from.Blend(to, 1); got.Tetriminos['T'] != to.Tetriminos['T'] {
+		t.Errorf("1: want %v, got %v", to.Tetriminos['T'], got.Tetriminos['T'])
+	}
+	if got := from.Blend(to, 0.5).Tetriminos['T']; got == from.Tetriminos['T'] || got == to.Tetriminos['T'] {
+		t.Errorf("0.5: want a colour between %v and %v, got %v", from.Tetriminos['T'], to.Tetriminos['T'], got)
+	}
+}
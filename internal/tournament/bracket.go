@@ -0,0 +1,285 @@
+package tournament
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Bracket is a single-elimination tournament. Each match is two turns, one for each player, played on the same seed so
+// that both see the same tetriminos, and won by the higher score. Rounds are padded with byes to a power of two, which
+// advance their player without a game.
+type Bracket struct {
+	Players []string  `json:"players"` // in seeded order, the first playing the last in the first round
+	Rounds  [][]Match `json:"rounds"`  // from the first round to the final
+	Seed    int64     `json:"seed"`    // seed of the first match, with each match after it using the next
+	Created time.Time `json:"created"`
+}
+
+// Match is a game between two players. A player is empty when they are yet to be decided, or when the match is a bye.
+type Match struct {
+	A       string  `json:"a"`
+	B       string  `json:"b"`
+	ResultA *Result `json:"result_a,omitempty"` // nil until A has played their turn
+	ResultB *Result `json:"result_b,omitempty"` // nil until B has played their turn
+	Winner  string  `json:"winner,omitempty"`
+}
+
+// Result is the outcome of a player's turn in a match.
+type Result struct {
+	Score uint          `json:"score"`
+	Lines uint          `json:"lines"`
+	Time  time.Duration `json:"time"`
+}
+
+// Turn is a player's turn in a match, identified by the match's round and position in it.
+type Turn struct {
+	Round    int
+	Match    int
+	Player   string
+	Opponent string
+}
+
+// NewBracket creates a bracket for the players, in seeded order, with the first match played on the seed.
+func NewBracket(players []string, seed int64) (*Bracket, error) {
+	if len(players) < 2 {
+		return nil, errors.New("a tournament needs at least 2 players")
+	}
+	seen := make(map[string]bool, len(players))
+	for _, p := range players {
+		if strings.TrimSpace(p) == "" {
+			return nil, errors.New("player names must not be empty")
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("player %q is entered more than once", p)
+		}
+		seen[p] = true
+	}
+
+	size := 2
+	for size < len(players) {
+		size *= 2
+	}
+	b := &Bracket{
+		Players: players,
+		Seed:    seed,
+		Created: time.Now(),
+	}
+	for n := size / 2; n >= 1; n /= 2 {
+		b.Rounds = append(b.Rounds, make([]Match, n))
+	}
+
+	// Seeds are placed in the standard bracket order, so the top seeds are given the byes and meet as late as possible:
+	// the first and second seeds only in the final.
+	slots := make([]string, size)
+	copy(slots, players)
+	order := seedOrder(size)
+	for i := range b.Rounds[0] {
+		first, second := order[2*i], order[2*i+1]
+		b.Rounds[0][i] = Match{A: slots[min(first, second)], B: slots[max(first, second)]}
+	}
+	for i, match := range b.Rounds[0] {
+		if match.B == "" {
+			b.advance(0, i, match.A)
+		}
+	}
+	return b, nil
+}
+
+// seedOrder returns the indexes of the seeds in the order they are placed in a first round of the given size, a power
+// of two, such as 0, 7, 4, 3, 2, 5, 6, 1 for eight: each seed plays the one whose index adds up with theirs to one less
+// than the size, and each half of the order is the order for half as many seeds.
+func seedOrder(size int) []int {
+	order := []int{0}
+	for n := 2; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for i, s := range order {
+			if i%2 == 0 {
+				next = append(next, s, n-1-s)
+			} else {
+				next = append(next, n-1-s, s)
+			}
+		}
+		order = next
+	}
+	return order
+}
+
+// Next returns the next turn to be played, or false if the tournament is over.
+func (b *Bracket) Next() (Turn, bool) {
+	for r, round := range b.Rounds {
+		for i, match := range round {
+			if match.Winner != "" || match.A == "" || match.B == "" {
+				continue
+			}
+			if match.ResultA == nil {
+				return Turn{Round: r, Match: i, Player: match.A, Opponent: match.B}, true
+			}
+			return Turn{Round: r, Match: i, Player: match.B, Opponent: match.A}, true
+		}
+	}
+	return Turn{}, false
+}
+
+// Record records the result of the next turn, deciding the match once both players have played.
+func (b *Bracket) Record(result Result) error {
+	turn, ok := b.Next()
+	if !ok {
+		return errors.New("the tournament is over")
+	}
+	return b.RecordTurn(turn, result)
+}
+
+// RecordTurn records the result of the turn, deciding the match once both players have played. Turns can be recorded
+// in any order, such as when players take their turns on different terminals, but only once.
+func (b *Bracket) RecordTurn(turn Turn, result Result) error {
+	if turn.Round < 0 || turn.Round >= len(b.Rounds) || turn.Match < 0 || turn.Match >= len(b.Rounds[turn.Round]) {
+		return fmt.Errorf("there is no match %d in round %d", turn.Match+1, turn.Round+1)
+	}
+	match := &b.Rounds[turn.Round][turn.Match]
+	var played **Result
+	switch {
+	case match.A == "" || match.B == "":
+		return fmt.Errorf("the players of %s are yet to be decided", b.RoundName(turn.Round))
+	case turn.Player == match.A:
+		played = &match.ResultA
+	case turn.Player == match.B:
+		played = &match.ResultB
+	default:
+		return fmt.Errorf("%s is not playing in this match", turn.Player)
+	}
+	if *played != nil {
+		return fmt.Errorf("%s has already played their turn against %s", turn.Player, turn.Opponent)
+	}
+	*played = &result
+	if match.ResultA == nil || match.ResultB == nil {
+		return nil
+	}
+
+	// Ties are broken by lines cleared, then by the higher seed.
+	ra, rb := match.ResultA, match.ResultB
+	winner := match.B
+	switch {
+	case ra.Score != rb.Score:
+		if ra.Score > rb.Score {
+			winner = match.A
+		}
+	case ra.Lines != rb.Lines:
+		if ra.Lines > rb.Lines {
+			winner = match.A
+		}
+	case b.seed(match.A) < b.seed(match.B):
+		winner = match.A
+	}
+	b.advance(turn.Round, turn.Match, winner)
+	return nil
+}
+
+// seed returns the index of the player in the seeded order, or -1 if they aren't entered.
+func (b *Bracket) seed(player string) int {
+	return slices.Index(b.Players, player)
+}
+
+// advance decides the match in the round, moving the winner into their match in the next round.
+func (b *Bracket) advance(round, match int, winner string) {
+	b.Rounds[round][match].Winner = winner
+	if round+1 >= len(b.Rounds) {
+		return
+	}
+	next := &b.Rounds[round+1][match/2]
+	if match%2 == 0 {
+		next.A = winner
+	} else {
+		next.B = winner
+	}
+}
+
+// Champion returns the winner of the final, or an empty string if it is yet to be played.
+func (b *Bracket) Champion() string {
+	return b.Rounds[len(b.Rounds)-1][0].Winner
+}
+
+// MatchSeed returns the seed the match is played on, shared by both players' turns.
+func (b *Bracket) MatchSeed(round, match int) int64 {
+	offset := 0
+	for _, r := range b.Rounds[:round] {
+		offset += len(r)
+	}
+	return b.Seed + int64(offset+match)
+}
+
+// RoundName returns the name of the round, counting back from the final.
+func (b *Bracket) RoundName(round int) string {
+	switch len(b.Rounds) - round {
+	case 1:
+		return "Final"
+	case 2:
+		return "Semi-finals"
+	case 3:
+		return "Quarter-finals"
+	default:
+		return fmt.Sprintf("Round %d", round+1)
+	}
+}
+
+// DefaultPath returns the location of the tournament file in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "tetrigo", "tournament.json"), nil
+}
+
+// Exists reports whether there is a tournament saved at the path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Read returns the tournament saved at the path.
+func Read(path string) (*Bracket, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tournament: %w", err)
+	}
+
+	var b Bracket
+	err = json.Unmarshal(data, &b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tournament: %w", err)
+	}
+	if len(b.Rounds) == 0 {
+		return nil, errors.New("failed to decode tournament: it has no rounds")
+	}
+	return &b, nil
+}
+
+// Write saves the tournament to the path, replacing any tournament saved there already.
+func Write(path string, b *Bracket) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tournament: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create tournament directory: %w", err)
+	}
+	// Write to a temporary file first so that the results are not lost if writing is interrupted.
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write tournament: %w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace tournament: %w", err)
+	}
+	return nil
+}
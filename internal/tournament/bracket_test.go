@@ -0,0 +1,209 @@
+package tournament
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewBracket(t *testing.T) {
+	tt := map[string]struct {
+		players []string
+		first   []Match
+		wantErr bool
+	}{
+		"two players": {
+			players: []string{"ann", "bob"},
+			first:   []Match{{A: "ann", B: "bob"}},
+		},
+		"byes to the top seeds": {
+			players: []string{"ann", "bob", "cat"},
+			first:   []Match{{A: "ann", Winner: "ann"}, {A: "bob", B: "cat"}},
+		},
+		"standard order": {
+			players: []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8"},
+			first:   []Match{{A: "p1", B: "p8"}, {A: "p4", B: "p5"}, {A: "p3", B: "p6"}, {A: "p2", B: "p7"}},
+		},
+		"byes in standard order": {
+			players: []string{"p1", "p2", "p3", "p4", "p5", "p6"},
+			first: []Match{
+				{A: "p1", Winner: "p1"}, {A: "p4", B: "p5"}, {A: "p3", B: "p6"}, {A: "p2", Winner: "p2"},
+			},
+		},
+		"one player": {
+			players: []string{"ann"},
+			wantErr: true,
+		},
+		"duplicate player": {
+			players: []string{"ann", "bob", "ann"},
+			wantErr: true,
+		},
+		"empty player": {
+			players: []string{"ann", " "},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			b, err := NewBracket(tc.players, 1)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !reflect.DeepEqual(b.Rounds[0], tc.first) {
+				t.Errorf("first round: want %+v, got %+v", tc.first, b.Rounds[0])
+			}
+		})
+	}
+}
+
+func TestBracket_Record(t *testing.T) {
+	b, err := NewBracket([]string{"ann", "bob", "cat"}, 10)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	// ann has a bye, so waits in the final for the winner of bob and cat.
+	want := []Turn{
+		{Round: 0, Match: 1, Player: "bob", Opponent: "cat"},
+		{Round: 0, Match: 1, Player: "cat", Opponent: "bob"},
+		{Round: 1, Match: 0, Player: "ann", Opponent: "cat"},
+		{Round: 1, Match: 0, Player: "cat", Opponent: "ann"},
+	}
+	results := []Result{
+		{Score: 500, Lines: 4},
+		{Score: 500, Lines: 5}, // tied on score, won on lines
+		{Score: 900, Lines: 8},
+		{Score: 900, Lines: 8}, // tied on both, won by the higher seed
+	}
+	for i, turn := range want {
+		got, ok := b.Next()
+		if !ok {
+			t.Fatalf("turn %d: want a turn, got none", i)
+		}
+		if got != turn {
+			t.Errorf("turn %d: want %+v, got %+v", i, turn, got)
+		}
+		err = b.Record(results[i])
+		if err != nil {
+			t.Fatalf("turn %d: expected nil, got error: %v", i, err)
+		}
+	}
+
+	if _, ok := b.Next(); ok {
+		t.Errorf("want no turns after the final")
+	}
+	if b.Champion() != "ann" {
+		t.Errorf("Champion: want ann, got %q", b.Champion())
+	}
+	if err = b.Record(Result{}); err == nil {
+		t.Errorf("Record: want an error after the final, got nil")
+	}
+	if b.MatchSeed(0, 1) != 11 || b.MatchSeed(1, 0) != 12 {
+		t.Errorf("MatchSeed: want 11 and 12, got %d and %d", b.MatchSeed(0, 1), b.MatchSeed(1, 0))
+	}
+}
+
+func TestBracket_EightPlayers(t *testing.T) {
+	players := []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8"}
+	b, err := NewBracket(players, 1)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	// Every turn ties, so every match is won by the higher seed, wherever they are in the match.
+	for i := 0; ; i++ {
+		turn, ok := b.Next()
+		if !ok {
+			break
+		}
+		if turn.Round == len(b.Rounds)-1 && (turn.Player != "p1" && turn.Player != "p2") {
+			t.Errorf("want p1 and p2 in the final, got %s against %s", turn.Player, turn.Opponent)
+		}
+		err = b.Record(Result{Score: 100, Lines: 1})
+		if err != nil {
+			t.Fatalf("turn %d: expected nil, got error: %v", i, err)
+		}
+	}
+
+	semis := b.Rounds[1]
+	if semis[0].Winner != "p1" || semis[1].A != "p3" || semis[1].B != "p2" || semis[1].Winner != "p2" {
+		t.Errorf("semi-finals: want p1 and p2 to win, got %+v", semis)
+	}
+	if b.Champion() != "p1" {
+		t.Errorf("Champion: want p1, got %q", b.Champion())
+	}
+}
+
+func TestBracket_RecordTurn(t *testing.T) {
+	b, err := NewBracket([]string{"ann", "bob", "cat", "dan"}, 1)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	// Turns taken on different terminals can be recorded out of order.
+	turns := []struct {
+		turn   Turn
+		result Result
+	}{
+		{Turn{Round: 0, Match: 1, Player: "cat", Opponent: "bob"}, Result{Score: 300}},
+		{Turn{Round: 0, Match: 0, Player: "dan", Opponent: "ann"}, Result{Score: 400}},
+		{Turn{Round: 0, Match: 1, Player: "bob", Opponent: "cat"}, Result{Score: 200}},
+		{Turn{Round: 0, Match: 0, Player: "ann", Opponent: "dan"}, Result{Score: 100}},
+	}
+	for i, tc := range turns {
+		err = b.RecordTurn(tc.turn, tc.result)
+		if err != nil {
+			t.Fatalf("turn %d: expected nil, got error: %v", i, err)
+		}
+	}
+	if final := b.Rounds[1][0]; final.A != "dan" || final.B != "cat" {
+		t.Errorf("final: want dan against cat, got %+v", final)
+	}
+
+	err = b.RecordTurn(turns[0].turn, Result{Score: 900})
+	if err == nil {
+		t.Errorf("want an error recording a turn twice, got nil")
+	}
+	err = b.RecordTurn(Turn{Round: 1, Match: 0, Player: "ann", Opponent: "dan"}, Result{})
+	if err == nil {
+		t.Errorf("want an error recording a turn of a player who isn't in the match, got nil")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tetrigo", "tournament.json")
+	b, err := NewBracket([]string{"ann", "bob"}, 1)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	err = b.Record(Result{Score: 100, Lines: 1})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	if Exists(path) {
+		t.Errorf("Exists: want false before writing")
+	}
+	err = Write(path, b)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !got.Created.Equal(b.Created) {
+		t.Errorf("Created: want %v, got %v", b.Created, got.Created)
+	}
+	got.Created = b.Created
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("Read: want %+v, got %+v", b, got)
+	}
+}
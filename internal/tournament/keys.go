@@ -0,0 +1,31 @@
+package tournament
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit key.Binding
+	Play key.Binding
+}
+
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit: key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+		Play: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "play turn")),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Quit,
+		k.Play,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Quit,
+			k.Play,
+		},
+	}
+}
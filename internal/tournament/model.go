@@ -0,0 +1,233 @@
+package tournament
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/teautil"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultTimeLimit is the length of each turn when none is specified.
+const DefaultTimeLimit = 2 * time.Minute
+
+type Model struct {
+	bracket *Bracket
+	path    string
+	turn    Turn
+	game    tea.Model
+	playing bool
+
+	// last describes the result of the turn just played, and err any failure to save it.
+	last string
+	err  error
+
+	gameInput marathon.Input
+
+	// windowSize is the last size of the terminal, passed on to each turn when it starts.
+	windowSize *tea.WindowSizeMsg
+
+	keys   *KeyMap
+	styles *Styles
+	help   help.Model
+}
+
+type Input struct {
+	Bracket   *Bracket
+	Path      string        // file the bracket is saved to after each turn (empty to not save it)
+	TimeLimit time.Duration // length of each turn (0 for DefaultTimeLimit)
+	Level     uint          // level each turn starts at
+
+	HoldPreview bool
+	Countdown   uint         // seconds counted down before each turn starts
+	Theme       *theme.Theme // colour scheme the game is drawn with (nil for the default)
+	Speed       float64      // gravity multiplier for every turn (0 for normal speed)
+
+	// Handling is how held keys move the tetrimino in every turn (see marathon.Input).
+	Handling tetris.Handling
+
+	Audio *audio.Player // plays the sounds of each turn (nil for silence)
+}
+
+// NewModel creates a tournament which plays the turns of the bracket one after another, passing the keyboard between
+// players.
+func NewModel(in *Input) *Model {
+	timeLimit := in.TimeLimit
+	if timeLimit == 0 {
+		timeLimit = DefaultTimeLimit
+	}
+	return &Model{
+		bracket: in.Bracket,
+		path:    in.Path,
+		gameInput: marathon.Input{
+			Level:       in.Level,
+			TimeLimit:   timeLimit,
+			HoldPreview: in.HoldPreview,
+			Countdown:   in.Countdown,
+			Theme:       in.Theme,
+			Speed:       in.Speed,
+			Handling:    in.Handling,
+			Audio:       in.Audio,
+		},
+		keys:   DefaultKeyMap(),
+		styles: NewStyles(in.Theme),
+		help:   theme.NewHelp(in.Theme),
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowSize = &msg
+	}
+
+	if m.playing {
+		if msg, ok := msg.(marathon.GameOverMsg); ok {
+			m.record(msg.Results)
+			m.playing = false
+			m.game = nil
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.game, cmd = m.game.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Play):
+			m.err = m.reload()
+			if m.err != nil {
+				return m, nil
+			}
+			turn, ok := m.bracket.Next()
+			if !ok {
+				return m, tea.Quit
+			}
+			// Copy the input so each turn starts from the same settings.
+			in := m.gameInput
+			in.Seed = m.bracket.MatchSeed(turn.Round, turn.Match)
+			m.turn = turn
+			m.playing = true
+			m.game = marathon.NewModel(&in)
+			return m, tea.Batch(m.game.Init(), teautil.Resize(m.windowSize))
+		}
+	}
+
+	return m, nil
+}
+
+// record records the results of the turn just played in the bracket, saving it so the tournament can be continued
+// later.
+func (m *Model) record(r marathon.Results) {
+	m.err = m.reload()
+	if m.err != nil {
+		return
+	}
+	m.err = m.bracket.RecordTurn(m.turn, Result{Score: r.Score, Lines: r.Lines, Time: r.Time})
+	if m.err != nil {
+		return
+	}
+	m.last = fmt.Sprintf("%s scored %d points with %d lines against %s.", m.turn.Player, r.Score, r.Lines, m.turn.Opponent)
+	if m.path != "" {
+		m.err = Write(m.path, m.bracket)
+	}
+}
+
+// reload reads the bracket saved at the path again, so that turns recorded since it was read, by players taking their
+// turns on other terminals, are kept rather than overwritten.
+func (m *Model) reload() error {
+	if m.path == "" || !Exists(m.path) {
+		return nil
+	}
+	b, err := Read(m.path)
+	if err != nil {
+		return err
+	}
+	m.bracket = b
+	return nil
+}
+
+func (m Model) View() string {
+	if m.playing {
+		return m.game.View()
+	}
+
+	var summary strings.Builder
+	if m.last != "" {
+		summary.WriteString(m.last + "\n")
+	}
+	if turn, ok := m.bracket.Next(); ok {
+		summary.WriteString(fmt.Sprintf("%s: %s to play against %s, on the same tetriminos.\n",
+			m.bracket.RoundName(turn.Round), turn.Player, turn.Opponent))
+		summary.WriteString(fmt.Sprintf("Pass the keyboard to %s and press enter to start their turn.", turn.Player))
+	} else {
+		summary.WriteString(fmt.Sprintf("%s is the champion!", m.bracket.Champion()))
+	}
+
+	output := m.styles.Title.Render("Tournament") + "\n" +
+		m.styles.Summary.Render(m.bracketView()) + "\n" +
+		m.styles.Summary.Render(summary.String()) + "\n"
+	if m.err != nil {
+		output += m.styles.Error.Render(m.err.Error()) + "\n"
+	}
+	return output + m.help.View(m.keys)
+}
+
+// bracketView renders the rounds of the bracket side by side, each match showing the scores of its players.
+func (m Model) bracketView() string {
+	width := len("bye")
+	for _, p := range m.bracket.Players {
+		width = max(width, lipgloss.Width(p))
+	}
+
+	columns := make([]string, len(m.bracket.Rounds))
+	for r, round := range m.bracket.Rounds {
+		matches := []string{m.styles.Round.Render(m.bracket.RoundName(r))}
+		for _, match := range round {
+			a := m.playerView(match, match.A, match.ResultA, width, match.B == "" && r == 0)
+			b := m.playerView(match, match.B, match.ResultB, width, match.B == "" && r == 0)
+			matches = append(matches, m.styles.Match.Render(a+"\n"+b))
+		}
+		columns[r] = lipgloss.JoinVertical(lipgloss.Left, matches...)
+		if r < len(columns)-1 {
+			columns[r] = lipgloss.NewStyle().PaddingRight(2).Render(columns[r])
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Center, columns...)
+}
+
+// playerView renders a player in a match with their score, if they have played their turn.
+func (m Model) playerView(match Match, player string, r *Result, width int, bye bool) string {
+	style := m.styles.Player
+	switch {
+	case bye && player == "":
+		return m.styles.Loser.Render(fmt.Sprintf("%-*s", width, "bye") + strings.Repeat(" ", 8))
+	case player == "":
+		return m.styles.Loser.Render(fmt.Sprintf("%-*s", width, "TBD") + strings.Repeat(" ", 8))
+	case match.Winner == player:
+		style = m.styles.Winner
+	case match.Winner != "":
+		style = m.styles.Loser
+	}
+
+	score := "-"
+	if r != nil {
+		score = fmt.Sprint(r.Score)
+	}
+	return style.Render(fmt.Sprintf("%-*s %7s", width, player, score))
+}
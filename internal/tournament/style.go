@@ -0,0 +1,39 @@
+package tournament
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Title   lipgloss.Style
+	Round   lipgloss.Style // heading above each round of the bracket
+	Match   lipgloss.Style
+	Player  lipgloss.Style // a player yet to win or lose their match
+	Winner  lipgloss.Style
+	Loser   lipgloss.Style
+	Summary lipgloss.Style
+	Error   lipgloss.Style
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for the bracket with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
+	s := Styles{
+		Title:   lipgloss.NewStyle().Bold(true).Foreground(t.Text).Padding(1, 2, 0),
+		Round:   lipgloss.NewStyle().Bold(true).Foreground(t.Subtle),
+		Match:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Muted).Padding(0, 1),
+		Player:  lipgloss.NewStyle().Foreground(t.Text),
+		Winner:  lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		Loser:   lipgloss.NewStyle().Foreground(t.Muted),
+		Summary: lipgloss.NewStyle().Foreground(t.Text).Padding(1, 2),
+		Error:   lipgloss.NewStyle().Foreground(t.Danger).Padding(0, 2),
+	}
+	return &s
+}
@@ -0,0 +1,35 @@
+package versus
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit    key.Binding
+	Rematch key.Binding
+	Start   key.Binding
+	Chat    key.Binding
+}
+
+// DefaultKeyMap returns the keys of a match. Rematch, Start and Chat are only enabled against remote opponents,
+// between games.
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit:    key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+		Rematch: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rematch"), key.WithDisabled()),
+		Start:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "start game"), key.WithDisabled()),
+		Chat:    key.NewBinding(key.WithKeys("1", "2", "3", "4"), key.WithHelp("1-4", "chat"), key.WithDisabled()),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Rematch, k.Start, k.Chat, k.Quit,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Rematch, k.Start, k.Chat, k.Quit,
+		},
+	}
+}
@@ -0,0 +1,599 @@
+package versus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// stateInterval is the time between each board snapshot sent to a remote opponent.
+	stateInterval = 100 * time.Millisecond
+
+	// garbageMessiness is the probability of the hole in received garbage changing column on each line.
+	garbageMessiness = 0.3
+
+	// chatLength is the most chat messages shown between games, the oldest being dropped first.
+	chatLength = 4
+)
+
+type Input struct {
+	Level     uint
+	Seed      int64         // seed shared by both sides, so they are dealt the same tetriminos (0 for a random seed each)
+	Countdown uint          // seconds counted down before the match starts
+	Theme     *theme.Theme  // colour scheme the boards are drawn with (nil for the default)
+	Audio     *audio.Player // plays the sounds of the player's game (nil for silence)
+	Keys      string        // the name of the preset of keys the player's game is bound to (empty for the default)
+
+	Attack      *tetris.AttackTable // lines sent for each kind of line clear (nil for the guideline's table)
+	Handicap    netplay.Handicap    // handicap of the player
+	BotHandicap netplay.Handicap    // handicap of the bot
+
+	// Holes is how the hole in garbage received by either side moves from line to line (tetris.HolesMessy, the
+	// default, moves it on some lines), and HoleWidth the columns each hole spans (0 for 1).
+	Holes     tetris.HolePattern
+	HoleWidth int
+
+	// Handling is how held keys move the player's tetrimino (the zero value moves it once for every press the terminal
+	// sends). Like Keys, it only applies to the player's game.
+	Handling tetris.Handling
+}
+
+// Model is a game between the player and an opponent, each on their own matrix.
+// Lines cleared by one side send garbage to the other, and the first to top out loses.
+// The opponent is either the built-in bot or a remote player.
+type Model struct {
+	player     tea.Model
+	opponent   tea.Model // nil when the opponent is remote
+	playerID   int
+	opponentID int
+
+	conn         *netplay.Conn
+	remote       *netplay.Board // the latest board received from the remote opponent
+	remoteStyles *marathon.Styles
+	disconnected bool
+
+	// A remote opponent can be played again and again, the host choosing a fresh seed for each game.
+	settings        *netplay.Settings // of the game being played, nil against the bot
+	host            bool
+	theme           *theme.Theme
+	audio           *audio.Player
+	keyPreset       string
+	handling        tetris.Handling
+	game            int  // games started, so that the state ticks of those before are told apart
+	wins, losses    uint // games of the match won and lost by the player
+	rematch         bool // the player has asked for a rematch
+	opponentRematch bool // the opponent has asked for a rematch
+	chat            []string
+	notice          string // why the last request was refused, by the room server or for settings which are invalid
+
+	// In rooms, the game is a free-for-all against everyone else in the room, whose latest boards are kept by ID.
+	room       *netplay.Room
+	boards     map[int]*netplay.Board
+	knockedOut []int // opponents knocked out of the game, in order
+	place      int   // where the player placed in the game, once it is over for them
+	// Results of each side, set once the game is over.
+	playerResults   *marathon.Results
+	opponentResults *marathon.Results
+	playerWon       bool
+
+	keys   *KeyMap
+	styles *Styles
+	help   help.Model
+}
+
+// NewModel creates a match against the built-in bot.
+func NewModel(in *Input) *Model {
+	player := marathon.NewModel(&marathon.Input{
+		Level:             in.Level,
+		Seed:              in.Seed,
+		Versus:            true,
+		GarbageMessiness:  garbageMessiness,
+		GarbageHoles:      in.Holes,
+		GarbageHoleWidth:  in.HoleWidth,
+		AttackTable:       in.Attack,
+		GarbageMultiplier: in.Handicap.Multiplier,
+		StartingGarbage:   in.Handicap.Garbage,
+		Countdown:         in.Countdown,
+		Theme:             in.Theme,
+		Audio:             in.Audio,
+		Keys:              in.Keys,
+		Handling:          in.Handling,
+	})
+	opponent := marathon.NewModel(&marathon.Input{
+		Level:             in.Level,
+		Seed:              in.Seed,
+		Versus:            true,
+		Bot:               true,
+		GarbageMessiness:  garbageMessiness,
+		GarbageHoles:      in.Holes,
+		GarbageHoleWidth:  in.HoleWidth,
+		AttackTable:       in.Attack,
+		GarbageMultiplier: in.BotHandicap.Multiplier,
+		StartingGarbage:   in.BotHandicap.Garbage,
+		Countdown:         in.Countdown,
+		Theme:             in.Theme,
+	})
+
+	m := &Model{
+		player:     *player,
+		opponent:   *opponent,
+		playerID:   player.ID(),
+		opponentID: opponent.ID(),
+		keys:       DefaultKeyMap(),
+		styles:     NewStyles(in.Theme),
+		help:       theme.NewHelp(in.Theme),
+	}
+	return m
+}
+
+// NewNetworkModel creates a match against a remote opponent, using the settings shared when connecting. The player
+// plays with the host's handicap if they are hosting, and the guest's otherwise.
+// The theme, audio, key preset and handling are chosen locally, since they do not affect the game (nil for the default
+// theme and silence, empty for the default keys).
+func NewNetworkModel(conn *netplay.Conn, settings *netplay.Settings, host bool, t *theme.Theme, a *audio.Player,
+	keys string, handling tetris.Handling) *Model {
+	player := newNetworkPlayer(settings, host, t, a, keys, handling)
+	m := &Model{
+		player:       *player,
+		playerID:     player.ID(),
+		conn:         conn,
+		remote:       &netplay.Board{},
+		remoteStyles: marathon.NewStyles(t),
+		settings:     settings,
+		host:         host,
+		theme:        t,
+		audio:        a,
+		keyPreset:    keys,
+		handling:     handling,
+		keys:         DefaultKeyMap(),
+		styles:       NewStyles(t),
+		help:         theme.NewHelp(t),
+	}
+	return m
+}
+
+// newNetworkPlayer creates the player's game against a remote opponent.
+func newNetworkPlayer(settings *netplay.Settings, host bool, t *theme.Theme, a *audio.Player,
+	keys string, handling tetris.Handling) *marathon.Model {
+	handicap := settings.Guest
+	if host {
+		handicap = settings.Host
+	}
+	return marathon.NewModel(&marathon.Input{
+		Level:             settings.Level,
+		Seed:              settings.Seed,
+		Versus:            true,
+		GarbageMessiness:  garbageMessiness,
+		AttackTable:       settings.Attack,
+		GarbageMultiplier: handicap.Multiplier,
+		StartingGarbage:   handicap.Garbage,
+		Countdown:         settings.Countdown,
+		Theme:             t,
+		Audio:             a,
+		Keys:              keys,
+		Handling:          handling,
+	})
+}
+
+// receivedMsg wraps a message received from the remote opponent.
+type receivedMsg struct {
+	msg netplay.Message
+}
+
+type disconnectedMsg struct {
+	err error
+}
+
+type stateTickMsg struct {
+	game int
+}
+
+func (m Model) receive() tea.Cmd {
+	return func() tea.Msg {
+		msg, err := m.conn.Receive()
+		if err != nil {
+			return disconnectedMsg{err: err}
+		}
+		return receivedMsg{msg: msg}
+	}
+}
+
+func (m Model) send(msg netplay.Message) tea.Cmd {
+	return func() tea.Msg {
+		err := m.conn.Send(msg)
+		if err != nil {
+			return disconnectedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+func (m Model) stateTick() tea.Cmd {
+	game := m.game
+	return tea.Tick(stateInterval, func(_ time.Time) tea.Msg {
+		return stateTickMsg{game: game}
+	})
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.waiting() {
+		return m.receive()
+	}
+	if m.conn != nil {
+		return tea.Batch(m.player.Init(), m.receive(), m.stateTick())
+	}
+	return tea.Batch(m.player.Init(), m.opponent.Init())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.room != nil && (m.waiting() || m.isOver()) {
+		return m.updateRoomLobby(msg)
+	}
+	if m.isOver() {
+		return m.updateLobby(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// The boards are laid out side by side, so neither is centred in the terminal on its own.
+		return m, nil
+	case marathon.AttackMsg:
+		if m.conn != nil {
+			return m, m.send(netplay.Message{Type: netplay.TypeGarbage, Lines: msg.Lines})
+		}
+		target := m.opponentID
+		if msg.ID == m.opponentID {
+			target = m.playerID
+		}
+		return m, func() tea.Msg { return marathon.GarbageMsg{ID: target, Lines: msg.Lines} }
+	case marathon.GameOverMsg:
+		m.playerWon = msg.ID != m.playerID
+		m.endMatch()
+		if m.conn != nil {
+			return m, m.send(netplay.Message{Type: netplay.TypeGameOver, Board: m.playerBoard()})
+		}
+		return m, nil
+	case stateTickMsg:
+		if msg.game != m.game {
+			return m, nil
+		}
+		return m, tea.Batch(m.send(netplay.Message{Type: netplay.TypeState, Board: m.playerBoard()}), m.stateTick())
+	case receivedMsg:
+		return m.handleRemote(msg.msg)
+	case disconnectedMsg:
+		m.disconnected = true
+		// Only an opponent leaves a match between two players, but a room server leaves everyone.
+		m.playerWon = m.room == nil
+		m.endMatch()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	m.player, cmd = m.player.Update(msg)
+	cmds = append(cmds, cmd)
+
+	if m.opponent != nil {
+		m.opponent, cmd = m.opponent.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// handleRemote handles a message from the opponent or room server during a game. Receive doesn't validate messages, so
+// those which aren't well formed, such as garbage too large to add to the matrix, are dropped.
+func (m Model) handleRemote(msg netplay.Message) (tea.Model, tea.Cmd) {
+	if msg.Validate() != nil {
+		return m, m.receive()
+	}
+	if m.room != nil {
+		return m.handleRoom(msg)
+	}
+	switch msg.Type {
+	case netplay.TypeState:
+		if msg.Board != nil {
+			m.remote = msg.Board
+		}
+	case netplay.TypeGarbage:
+		lines := msg.Lines
+		return m, tea.Batch(m.receive(), func() tea.Msg { return marathon.GarbageMsg{ID: m.playerID, Lines: lines} })
+	case netplay.TypeGameOver:
+		if msg.Board != nil {
+			m.remote = msg.Board
+		}
+		m.playerWon = true
+		m.endMatch()
+	}
+	return m, m.receive()
+}
+
+// updateLobby handles messages once the game is over. A remote opponent stays connected, so that the players can chat
+// and agree to a rematch, which the host starts once both have asked for it.
+func (m Model) updateLobby(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case m.disconnected:
+			return m, nil
+		case key.Matches(msg, m.keys.Rematch):
+			if m.rematch {
+				return m, nil
+			}
+			m.rematch = true
+			if m.host && m.opponentRematch {
+				return m.startRematch()
+			}
+			return m, m.send(netplay.Message{Type: netplay.TypeRematch})
+		case key.Matches(msg, m.keys.Chat):
+			return m.chatPreset(msg)
+		}
+	case receivedMsg:
+		var cmd tea.Cmd
+		switch msg.msg.Type {
+		case netplay.TypeRematch:
+			m.opponentRematch = true
+			if !m.host && msg.msg.Settings != nil {
+				if err := msg.msg.Settings.Validate(); err != nil {
+					m.notice = fmt.Sprintf("The host's rematch was refused: %v", err)
+					break
+				}
+				m, cmd = m.startGame(msg.msg.Settings)
+			} else if m.host && m.rematch {
+				var next tea.Model
+				next, cmd = m.startRematch()
+				m = next.(Model)
+			}
+		case netplay.TypeChat:
+			m.addChat("Opponent", msg.msg.Chat)
+		}
+		return m, tea.Batch(cmd, m.receive())
+	case disconnectedMsg:
+		m.disconnected = true
+		m.keys.Rematch.SetEnabled(false)
+		m.keys.Chat.SetEnabled(false)
+	}
+	return m, nil
+}
+
+// startRematch starts the next game as the host, with a fresh seed shared with the opponent.
+func (m Model) startRematch() (tea.Model, tea.Cmd) {
+	settings := *m.settings
+	settings.Seed = time.Now().UnixNano()
+	m, cmd := m.startGame(&settings)
+	return m, tea.Batch(m.send(netplay.Message{Type: netplay.TypeRematch, Settings: &settings}), cmd)
+}
+
+// chatPreset sends the chat preset numbered by the key.
+func (m Model) chatPreset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	preset := netplay.ChatPresets[msg.String()[0]-'1']
+	m.addChat("You", preset.ID)
+	return m, m.send(netplay.Message{Type: netplay.TypeChat, Chat: preset.ID})
+}
+
+// startGame starts the next game against the remote opponents with the host's settings, starting a new match if the
+// last was won.
+func (m Model) startGame(settings *netplay.Settings) (Model, tea.Cmd) {
+	if m.matchOver() {
+		m.wins, m.losses = 0, 0
+	}
+	player := newNetworkPlayer(settings, m.host, m.theme, m.audio, m.keyPreset, m.handling)
+	m.player, m.playerID = *player, player.ID()
+	m.settings = settings
+	m.game++
+	m.remote = &netplay.Board{}
+	m.playerResults, m.opponentResults, m.playerWon = nil, nil, false
+	m.rematch, m.opponentRematch = false, false
+	if m.room != nil {
+		m.boards = make(map[int]*netplay.Board)
+		m.knockedOut, m.place = nil, 0
+	}
+	m.notice = ""
+	m.keys.Rematch.SetEnabled(false)
+	m.keys.Start.SetEnabled(false)
+	m.keys.Chat.SetEnabled(false)
+	return m, tea.Batch(m.player.Init(), m.stateTick())
+}
+
+// addChat adds a chat preset sent by the player or opponent to those shown, ignoring those which are unknown.
+func (m *Model) addChat(from, id string) {
+	text, ok := netplay.ChatText(id)
+	if !ok {
+		return
+	}
+	m.chat = append(m.chat, fmt.Sprintf("%s: %s", from, text))
+	if len(m.chat) > chatLength {
+		m.chat = m.chat[len(m.chat)-chatLength:]
+	}
+}
+
+// matchOver reports whether either player has won enough games to win the match against a remote opponent. Rooms
+// play on without matches.
+func (m Model) matchOver() bool {
+	if m.settings == nil || m.room != nil {
+		return false
+	}
+	return m.wins >= m.settings.WinsNeeded() || m.losses >= m.settings.WinsNeeded()
+}
+
+func (m Model) playerBoard() *netplay.Board {
+	return m.player.(marathon.Model).Snapshot()
+}
+
+// endMatch records the results of both sides, and the game in the match score. Messages are no longer passed to
+// either game, so both stop.
+func (m *Model) endMatch() {
+	playerResults := m.player.(marathon.Model).Results()
+	m.playerResults = &playerResults
+
+	var opponentResults marathon.Results
+	if m.opponent != nil {
+		opponentResults = m.opponent.(marathon.Model).Results()
+	} else if m.room == nil {
+		opponentResults = marathon.Results{
+			Score:    m.remote.Score,
+			Lines:    m.remote.Lines,
+			Level:    m.remote.Level,
+			Time:     m.remote.Time,
+			Attack:   m.remote.Attack,
+			Received: m.remote.Received,
+		}
+	}
+	m.opponentResults = &opponentResults
+
+	if m.conn != nil {
+		if m.playerWon {
+			m.wins++
+		} else {
+			m.losses++
+		}
+		m.keys.Rematch.SetEnabled(!m.disconnected)
+		m.keys.Chat.SetEnabled(!m.disconnected)
+	}
+	if m.room != nil {
+		m.place = m.opponentsLeft() + 1
+		m.keys.Rematch.SetEnabled(false)
+		m.enableLobbyKeys()
+	}
+}
+
+func (m Model) isOver() bool {
+	return m.playerResults != nil
+}
+
+func (m Model) View() string {
+	switch {
+	case m.room != nil && m.waiting():
+		return m.roomView()
+	case m.room != nil && m.isOver():
+		return m.roomResultsView()
+	case m.isOver():
+		return m.resultsView()
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		m.player.View(),
+		m.styles.Gap.Render(""),
+		m.opponentView(),
+	)
+}
+
+func (m Model) opponentView() string {
+	if m.opponent != nil {
+		return m.opponent.View()
+	}
+	if m.room != nil {
+		return m.roomOpponentsView()
+	}
+
+	info := fmt.Sprintln(m.styles.text("Score")+": ", m.remote.Score) +
+		fmt.Sprintln(m.styles.text("Level")+": ", m.remote.Level) +
+		fmt.Sprintln(m.styles.text("Cleared")+": ", m.remote.Lines) +
+		fmt.Sprintln(m.styles.text("Attack")+": ", m.remote.Attack) +
+		fmt.Sprintln(m.styles.text("Received")+": ", m.remote.Received)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		marathon.BoardView(m.remoteStyles, &m.remote.Matrix),
+		m.styles.Results.Render(info),
+	)
+}
+
+func (m Model) resultsView() string {
+	title := m.styles.text("You lose")
+	if m.disconnected {
+		title = m.styles.text("Opponent disconnected")
+	} else if m.playerWon {
+		title = m.styles.text("You win!")
+	}
+	if m.matchOver() && !m.disconnected {
+		title = m.styles.text("You lose the match")
+		if m.wins > m.losses {
+			title = m.styles.text("You win the match!")
+		}
+	}
+
+	opponentName := m.styles.text("Bot")
+	if m.conn != nil {
+		opponentName = m.styles.text("Opponent")
+	}
+
+	rows := []struct {
+		name             string
+		player, opponent string
+	}{
+		{"Score", fmt.Sprint(m.playerResults.Score), fmt.Sprint(m.opponentResults.Score)},
+		{"Lines", fmt.Sprint(m.playerResults.Lines), fmt.Sprint(m.opponentResults.Lines)},
+		{"Attack", fmt.Sprint(m.playerResults.Attack), fmt.Sprint(m.opponentResults.Attack)},
+		{"APM", fmt.Sprintf("%.1f", m.playerResults.APM()), fmt.Sprintf("%.1f", m.opponentResults.APM())},
+		{"Received", fmt.Sprint(m.playerResults.Received), fmt.Sprint(m.opponentResults.Received)},
+		{"Time", m.playerResults.Time.Round(time.Millisecond).String(), m.opponentResults.Time.Round(time.Millisecond).String()},
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%-10s%12s%12s\n", "", m.styles.text("You"), opponentName))
+	for _, r := range rows {
+		output.WriteString(fmt.Sprintf("%-10s%12s%12s\n", m.styles.text(r.name), r.player, r.opponent))
+	}
+
+	if m.conn != nil {
+		output.WriteString(m.lobbyView())
+	}
+
+	return m.styles.Title.Render(title) + "\n" +
+		m.styles.Results.Render(output.String()) + "\n" +
+		m.help.View(m.keys)
+}
+
+// lobbyView shows the match score, whether a rematch has been asked for, and the chat between games.
+func (m Model) lobbyView() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n%-10s%12d%12d\n", m.styles.text("Match"), m.wins, m.losses))
+	if m.settings.BestOf > 1 {
+		output.WriteString(fmt.Sprintf("Best of %d, first to %d wins\n", m.settings.BestOf, m.settings.WinsNeeded()))
+	}
+	if m.disconnected {
+		return output.String()
+	}
+
+	output.WriteString("\n")
+	switch {
+	case m.notice != "":
+		output.WriteString(m.notice + "\n")
+	case m.rematch:
+		output.WriteString("Waiting for your opponent to rematch...\n")
+	case m.opponentRematch:
+		output.WriteString("Your opponent wants a rematch\n")
+	case m.matchOver():
+		output.WriteString("Rematch to start a new match\n")
+	}
+	output.WriteString(m.chatView())
+	return output.String()
+}
+
+// chatView shows the latest chat, and the presets which can be sent.
+func (m Model) chatView() string {
+	var output strings.Builder
+	for _, line := range m.chat {
+		output.WriteString(line + "\n")
+	}
+	var presets []string
+	for i, p := range netplay.ChatPresets {
+		presets = append(presets, fmt.Sprintf("%d %s", i+1, p.Text))
+	}
+	output.WriteString(strings.Join(presets, "  ") + "\n")
+	return output.String()
+}
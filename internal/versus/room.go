@@ -0,0 +1,254 @@
+package versus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NewRoomModel creates a free-for-all in a room on a room server, which the player has just created or joined. No game
+// is played until the room's owner starts one, and the room's settings are only known then.
+// The theme, audio, key preset and handling are chosen locally, as for NewNetworkModel.
+func NewRoomModel(conn *netplay.Conn, room *netplay.Room, t *theme.Theme, a *audio.Player, keys string,
+	handling tetris.Handling) *Model {
+	m := &Model{
+		conn:         conn,
+		room:         room,
+		boards:       make(map[int]*netplay.Board),
+		remoteStyles: marathon.NewStyles(t),
+		theme:        t,
+		audio:        a,
+		keyPreset:    keys,
+		handling:     handling,
+		keys:         DefaultKeyMap(),
+		styles:       NewStyles(t),
+		help:         theme.NewHelp(t),
+	}
+	m.enableLobbyKeys()
+	return m
+}
+
+// waiting reports whether the player is in a room waiting for its first game to start.
+func (m Model) waiting() bool {
+	return m.player == nil
+}
+
+// enableLobbyKeys enables the keys used between games in a room, letting only the owner start the next.
+func (m *Model) enableLobbyKeys() {
+	m.keys.Start.SetEnabled(!m.disconnected && m.room.You == m.room.Owner)
+	m.keys.Chat.SetEnabled(!m.disconnected)
+}
+
+// handleRoom handles a message relayed by the room server during a game.
+func (m Model) handleRoom(msg netplay.Message) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case netplay.TypeGarbage:
+		lines := msg.Lines
+		return m, tea.Batch(m.receive(), func() tea.Msg { return marathon.GarbageMsg{ID: m.playerID, Lines: lines} })
+	case netplay.TypeGameOver:
+		m.knockOut(msg.Player, msg.Board)
+		if m.opponentsLeft() == 0 {
+			m.playerWon = true
+			m.endMatch()
+		}
+	default:
+		m.updateRoom(msg)
+	}
+	return m, m.receive()
+}
+
+// updateRoomLobby handles messages while waiting for the first game in a room, or once the player's game is over. The
+// game may carry on between the others, whose boards are still followed, until the owner can start the next.
+func (m Model) updateRoomLobby(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Start):
+			m.notice = ""
+			return m, m.send(netplay.Message{Type: netplay.TypeStart})
+		case key.Matches(msg, m.keys.Chat):
+			return m.chatPreset(msg)
+		}
+	case receivedMsg:
+		var cmd tea.Cmd
+		switch msg.msg.Type {
+		case netplay.TypeHello:
+			if msg.msg.Settings != nil {
+				m, cmd = m.startGame(msg.msg.Settings)
+			}
+		case netplay.TypeGameOver:
+			m.knockOut(msg.msg.Player, msg.msg.Board)
+		case netplay.TypeError:
+			m.notice = msg.msg.Error
+		default:
+			m.updateRoom(msg.msg)
+		}
+		return m, tea.Batch(cmd, m.receive())
+	case disconnectedMsg:
+		m.disconnected = true
+		m.keys.Start.SetEnabled(false)
+		m.keys.Chat.SetEnabled(false)
+	}
+	return m, nil
+}
+
+// updateRoom handles the messages about the room which can be relayed at any time.
+func (m *Model) updateRoom(msg netplay.Message) {
+	switch msg.Type {
+	case netplay.TypeState:
+		if msg.Board != nil {
+			m.boards[msg.Player] = msg.Board
+		}
+	case netplay.TypeRoom:
+		if msg.Room != nil {
+			m.room = msg.Room
+			if m.waiting() || m.isOver() {
+				m.enableLobbyKeys()
+			}
+		}
+	case netplay.TypeChat:
+		m.addChat(fmt.Sprintf("Player %d", msg.Player), msg.Chat)
+	}
+}
+
+// knockOut records that the opponent has topped out, or left the room, with their final board.
+func (m *Model) knockOut(id int, board *netplay.Board) {
+	if board != nil {
+		m.boards[id] = board
+	}
+	if !slices.Contains(m.knockedOut, id) {
+		m.knockedOut = append(m.knockedOut, id)
+	}
+}
+
+// opponentsLeft returns the opponents in the room who haven't been knocked out of the game.
+func (m Model) opponentsLeft() int {
+	var left int
+	for _, id := range m.room.Opponents() {
+		if !slices.Contains(m.knockedOut, id) {
+			left++
+		}
+	}
+	return left
+}
+
+// roomOpponentsView draws the board of each opponent in the room side by side, marking those knocked out.
+func (m Model) roomOpponentsView() string {
+	var views []string
+	for _, id := range m.room.Opponents() {
+		board, ok := m.boards[id]
+		if !ok {
+			board = &netplay.Board{}
+		}
+		status := fmt.Sprintf("Player %d: %d", id, board.Score)
+		if slices.Contains(m.knockedOut, id) {
+			status = fmt.Sprintf("Player %d: KO", id)
+		}
+		views = append(views, lipgloss.JoinVertical(lipgloss.Left,
+			marathon.BoardView(m.remoteStyles, &board.Matrix),
+			status,
+		), m.styles.Gap.Render(""))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views...)
+}
+
+// roomView shows who is in the room while waiting for its first game to start.
+func (m Model) roomView() string {
+	var output strings.Builder
+	for _, id := range m.room.Players {
+		output.WriteString(fmt.Sprintf("Player %d", id))
+		if id == m.room.Owner {
+			output.WriteString(" (owner)")
+		}
+		if id == m.room.You {
+			output.WriteString(" (you)")
+		}
+		output.WriteString("\n")
+	}
+	output.WriteString(m.roomLobbyView())
+
+	return m.styles.Title.Render(fmt.Sprintf("Room %s", m.room.Code)) + "\n" +
+		m.styles.Results.Render(output.String()) + "\n" +
+		m.help.View(m.keys)
+}
+
+// roomResultsView shows how the player placed once they are out of the game, or have won it.
+func (m Model) roomResultsView() string {
+	title := fmt.Sprintf("Knocked out, %s of %d", ordinal(m.place), len(m.room.Opponents())+1)
+	if m.disconnected {
+		title = "Disconnected from the room server"
+	} else if m.playerWon {
+		title = "You win!"
+	}
+
+	rows := []struct {
+		name, value string
+	}{
+		{"Score", fmt.Sprint(m.playerResults.Score)},
+		{"Lines", fmt.Sprint(m.playerResults.Lines)},
+		{"Attack", fmt.Sprint(m.playerResults.Attack)},
+		{"APM", fmt.Sprintf("%.1f", m.playerResults.APM())},
+		{"Received", fmt.Sprint(m.playerResults.Received)},
+		{"Time", m.playerResults.Time.Round(time.Millisecond).String()},
+		{"Wins", fmt.Sprint(m.wins)},
+	}
+
+	var output strings.Builder
+	for _, r := range rows {
+		output.WriteString(fmt.Sprintf("%-10s%12s\n", r.name, r.value))
+	}
+	output.WriteString(m.roomLobbyView())
+
+	return m.styles.Title.Render(title) + "\n" +
+		m.styles.Results.Render(output.String()) + "\n" +
+		m.help.View(m.keys)
+}
+
+// roomLobbyView shows what the room is waiting for, and the chat between games.
+func (m Model) roomLobbyView() string {
+	var output strings.Builder
+	output.WriteString("\n")
+	switch {
+	case m.disconnected:
+		return output.String()
+	case m.notice != "":
+		output.WriteString(m.notice + "\n")
+	case !m.waiting() && m.opponentsLeft() > 1:
+		output.WriteString(fmt.Sprintf("%d players still playing\n", m.opponentsLeft()))
+	case m.room.You == m.room.Owner && m.waiting():
+		output.WriteString(fmt.Sprintf("Share the code %s, and start once everyone has joined\n", m.room.Code))
+	case m.room.You == m.room.Owner:
+		output.WriteString("Start the next game once everyone is ready\n")
+	default:
+		output.WriteString(fmt.Sprintf("Waiting for player %d to start\n", m.room.Owner))
+	}
+	output.WriteString(m.chatView())
+	return output.String()
+}
+
+// ordinal returns the place written as 1st, 2nd, 3rd and so on.
+func ordinal(place int) string {
+	suffix := "th"
+	switch {
+	case place%100 >= 11 && place%100 <= 13:
+	case place%10 == 1:
+		suffix = "st"
+	case place%10 == 2:
+		suffix = "nd"
+	case place%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", place, suffix)
+}
@@ -0,0 +1,38 @@
+package versus
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/locale"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Gap     lipgloss.Style
+	Title   lipgloss.Style
+	Results lipgloss.Style
+
+	locale *locale.Locale
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(nil)
+}
+
+// NewStyles creates the styles for the results, showing their text in the language of the given theme (nil for the
+// default).
+func NewStyles(t *theme.Theme) *Styles {
+	s := Styles{
+		Gap:     lipgloss.NewStyle().Width(4),
+		Title:   lipgloss.NewStyle().Bold(true).Padding(1, 2, 0),
+		Results: lipgloss.NewStyle().Padding(1, 2),
+	}
+	if t != nil {
+		s.locale = t.Locale
+	}
+	return &s
+}
+
+// text returns the English text in the language of the styles' theme.
+func (s *Styles) text(english string) string {
+	return s.locale.T(english)
+}
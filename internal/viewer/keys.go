@@ -0,0 +1,59 @@
+package viewer
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Quit    key.Binding
+	Help    key.Binding
+	Play    key.Binding
+	Forward key.Binding
+	Back    key.Binding
+	Start   key.Binding
+	Faster  key.Binding
+	Slower  key.Binding
+	End     key.Binding
+}
+
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Quit:    key.NewBinding(key.WithKeys("esc", "q", "ctrl+c"), key.WithHelp("esc", "quit")),
+		Help:    key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Play:    key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "play/pause")),
+		Forward: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→", "next placement")),
+		Back:    key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "previous placement")),
+		Start:   key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("home", "start of game")),
+		End:     key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("end", "end of game")),
+		Faster:  key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑", "faster")),
+		Slower:  key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓", "slower")),
+	}
+}
+
+func (k *KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Quit,
+		k.Help,
+		k.Play,
+		k.Forward,
+		k.Back,
+	}
+}
+
+func (k *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Quit,
+			k.Help,
+			k.Play,
+		},
+		{
+			k.Forward,
+			k.Back,
+			k.Start,
+			k.End,
+		},
+		{
+			k.Faster,
+			k.Slower,
+		},
+	}
+}
@@ -0,0 +1,154 @@
+// Package viewer plays replays back in the terminal, one placement at a time, so that games recorded in tetrigo or
+// imported from other clients can be watched without exporting them first.
+package viewer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/export"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// minFrameDelay is the shortest time a frame is shown while playing, so that placements made in quick succession
+	// can still be followed.
+	minFrameDelay = 50 * time.Millisecond
+	// maxFrameDelay is the longest time a frame is shown while playing, skipping over long pauses in the game.
+	maxFrameDelay = 2 * time.Second
+
+	minSpeed = 0.25
+	maxSpeed = 8
+)
+
+type Input struct {
+	Theme *theme.Theme // colour scheme the board is drawn with (nil for the default)
+}
+
+// Model plays back a replay, frame by frame, as exported recordings do (see export.Frames).
+type Model struct {
+	replay  *replay.Replay
+	frames  []export.Frame
+	frame   int     // index of the frame shown
+	playing bool    // whether frames advance by themselves, at the pace they were played
+	speed   float64 // multiplies the pace of playback
+	tick    int     // playbacks started, so that the ticks of those before are told apart
+
+	keys       *KeyMap
+	styles     *Styles
+	gameStyles *marathon.Styles
+	help       help.Model
+}
+
+// NewModel creates a viewer of the replay, starting from the first frame and playing.
+func NewModel(r *replay.Replay, in *Input) *Model {
+	return &Model{
+		replay:     r,
+		frames:     export.Frames(r),
+		playing:    true,
+		speed:      1,
+		keys:       DefaultKeyMap(),
+		styles:     DefaultStyles(),
+		gameStyles: marathon.NewStyles(in.Theme),
+		help:       theme.NewHelp(in.Theme),
+	}
+}
+
+// frameTickMsg moves playback on to the next frame, unless it is from a playback which has since been paused or
+// moved.
+type frameTickMsg struct {
+	tick int
+}
+
+// next schedules the move to the next frame after the time between the frames in the game, or nothing if the last
+// frame is shown or playback is paused.
+func (m *Model) next() tea.Cmd {
+	if !m.playing || m.frame+1 >= len(m.frames) {
+		return nil
+	}
+	m.tick++
+	tick := m.tick
+	delay := time.Duration(float64(m.frames[m.frame+1].Time-m.frames[m.frame].Time) / m.speed)
+	delay = min(max(delay, minFrameDelay), maxFrameDelay)
+	return tea.Tick(delay, func(_ time.Time) tea.Msg {
+		return frameTickMsg{tick: tick}
+	})
+}
+
+// seek shows the frame, keeping it within the replay, and restarts the wait for the next if playing.
+func (m *Model) seek(frame int) tea.Cmd {
+	m.frame = min(max(frame, 0), len(m.frames)-1)
+	return m.next()
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.next()
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		case key.Matches(msg, m.keys.Play):
+			m.playing = !m.playing
+			if m.playing && m.frame+1 >= len(m.frames) {
+				// Playing again from the end starts the game over.
+				return m, m.seek(0)
+			}
+			return m, m.seek(m.frame)
+		case key.Matches(msg, m.keys.Forward):
+			return m, m.seek(m.frame + 1)
+		case key.Matches(msg, m.keys.Back):
+			return m, m.seek(m.frame - 1)
+		case key.Matches(msg, m.keys.Start):
+			return m, m.seek(0)
+		case key.Matches(msg, m.keys.End):
+			return m, m.seek(len(m.frames) - 1)
+		case key.Matches(msg, m.keys.Faster):
+			m.speed = min(m.speed*2, maxSpeed)
+			return m, m.seek(m.frame)
+		case key.Matches(msg, m.keys.Slower):
+			m.speed = max(m.speed/2, minSpeed)
+			return m, m.seek(m.frame)
+		}
+	case frameTickMsg:
+		if msg.tick != m.tick || !m.playing {
+			return m, nil
+		}
+		return m, m.seek(m.frame + 1)
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	f := m.frames[m.frame]
+	info := fmt.Sprintln(m.replay.Mode) +
+		fmt.Sprintln("Pieces: ", f.Pieces, "/", len(m.replay.Placements)) +
+		fmt.Sprintln("Cleared:", f.Lines) +
+		fmt.Sprintln("Time:   ", f.Time.Round(10*time.Millisecond)) +
+		fmt.Sprintf("Speed:   %gx\n", m.speed)
+
+	output := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.gameStyles.Information.Render(info),
+		marathon.BoardView(m.gameStyles, &f.Matrix),
+	)
+
+	switch {
+	case m.frame+1 >= len(m.frames):
+		output += "\n" + m.styles.Status.Render(fmt.Sprintf("Finished with %d points and %d lines",
+			m.replay.Score, m.replay.Lines))
+	case !m.playing:
+		output += "\n" + m.styles.Status.Render("Paused")
+	}
+	return output + "\n" + m.help.View(m.keys)
+}
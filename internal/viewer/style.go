@@ -0,0 +1,14 @@
+package viewer
+
+import "github.com/charmbracelet/lipgloss"
+
+type Styles struct {
+	Status lipgloss.Style
+}
+
+func DefaultStyles() *Styles {
+	s := Styles{
+		Status: lipgloss.NewStyle().Bold(true).Padding(0, 2),
+	}
+	return &s
+}
@@ -0,0 +1,190 @@
+package warmup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/internal/teautil"
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Exercise is a single short game played as part of a warm-up routine.
+type Exercise struct {
+	Name  string
+	Input *marathon.Input
+}
+
+// Exercises that can be included in a warm-up routine, keyed by the name used to select them.
+var Exercises = map[string]Exercise{
+	"drill": {
+		Name:  "Hard drop drill (30s)",
+		Input: &marathon.Input{Level: 1, TimeLimit: 30 * time.Second, HardDropOnly: true},
+	},
+	"sprint": {
+		Name:  "Sprint (40 lines)",
+		Input: &marathon.Input{Level: 1, LineGoal: 40},
+	},
+	"cheese": {
+		Name:  "Cheese race (10 rows)",
+		Input: &marathon.Input{Level: 1, Cheese: 10},
+	},
+}
+
+// DefaultRoutine is the sequence of exercises used when none is specified.
+var DefaultRoutine = []string{"drill", "cheese", "sprint"}
+
+type Model struct {
+	exercises []Exercise
+	results   []marathon.Results
+	index     int
+	game      tea.Model
+	playing   bool
+
+	// windowSize is the last size of the terminal, passed on to each exercise when it starts.
+	windowSize *tea.WindowSizeMsg
+
+	keys   *KeyMap
+	styles *Styles
+	help   help.Model
+}
+
+type Input struct {
+	Routine   []string     // names of the exercises to play, in order (the default routine if empty)
+	Countdown uint         // seconds counted down before each exercise starts
+	Theme     *theme.Theme // colour scheme the game is drawn with (nil for the default)
+	Speed     float64      // gravity multiplier for every exercise (0 for normal speed)
+
+	// Handling is how held keys move the tetrimino in every exercise (see marathon.Input).
+	Handling tetris.Handling
+
+	Records *records.Store // the player's best results for each exercise (nil to not keep records)
+	Player  string         // name the player's records are kept under (empty for local play)
+
+	Audio *audio.Player // plays the sounds of each exercise (nil for silence)
+}
+
+// NewModel creates a warm-up routine which plays the named exercises back-to-back.
+func NewModel(in *Input) (*Model, error) {
+	routine := in.Routine
+	if len(routine) == 0 {
+		routine = DefaultRoutine
+	}
+
+	exercises := make([]Exercise, len(routine))
+	for i, name := range routine {
+		e, ok := Exercises[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid exercise: %v", name)
+		}
+		// Copy the input so the exercises shared between routines are not modified.
+		exerciseInput := *e.Input
+		exerciseInput.Countdown = in.Countdown
+		exerciseInput.Theme = in.Theme
+		exerciseInput.Speed = in.Speed
+		exerciseInput.Handling = in.Handling
+		exerciseInput.Records = in.Records
+		exerciseInput.Player = in.Player
+		exerciseInput.Audio = in.Audio
+		e.Input = &exerciseInput
+		exercises[i] = e
+	}
+
+	m := &Model{
+		exercises: exercises,
+		results:   make([]marathon.Results, 0, len(exercises)),
+		keys:      DefaultKeyMap(),
+		styles:    NewStyles(in.Theme),
+		help:      theme.NewHelp(in.Theme),
+	}
+	return m, nil
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowSize = &msg
+	}
+
+	if m.playing {
+		if msg, ok := msg.(marathon.GameOverMsg); ok {
+			m.results = append(m.results, msg.Results)
+			m.index++
+			m.playing = false
+			m.game = nil
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.game, cmd = m.game.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Continue):
+			if m.index >= len(m.exercises) {
+				return m, tea.Quit
+			}
+			m.playing = true
+			m.game = marathon.NewModel(m.exercises[m.index].Input)
+			return m, tea.Batch(m.game.Init(), teautil.Resize(m.windowSize))
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) View() string {
+	if m.playing {
+		return m.game.View()
+	}
+
+	title := "Warm-up"
+	if m.index >= len(m.exercises) {
+		title += " complete"
+	}
+
+	var output strings.Builder
+	var upcoming strings.Builder
+	var totalTime time.Duration
+	var totalLines uint
+	for i, e := range m.exercises {
+		if i >= len(m.results) {
+			upcoming.WriteString(fmt.Sprintf("%d. %s\n", i+1, e.Name))
+			continue
+		}
+		r := m.results[i]
+		status := "done"
+		if !r.Completed {
+			status = "topped out"
+		}
+		if r.SpeedAdjusted() {
+			status += fmt.Sprintf(", at %vx speed", r.Speed)
+		}
+		output.WriteString(fmt.Sprintf("%d. %s: %d lines, %d points in %s (%s)\n",
+			i+1, e.Name, r.Lines, r.Score, r.Time.Round(time.Millisecond), status))
+		totalTime += r.Time
+		totalLines += r.Lines
+	}
+	if len(m.results) > 0 {
+		output.WriteString(fmt.Sprintf("\nTotal: %d lines in %s\n", totalLines, totalTime.Round(time.Millisecond)))
+	}
+
+	return m.styles.Title.Render(title) + "\n" +
+		m.styles.Summary.Render(output.String()) +
+		m.styles.Upcoming.Render(upcoming.String()) + "\n" +
+		m.help.View(m.keys)
+}
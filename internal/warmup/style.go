@@ -0,0 +1,29 @@
+package warmup
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Title    lipgloss.Style
+	Summary  lipgloss.Style
+	Upcoming lipgloss.Style
+}
+
+func DefaultStyles() *Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles creates the styles for the intermissions with the given theme (nil for the default).
+func NewStyles(t *theme.Theme) *Styles {
+	if t == nil {
+		t = theme.Default()
+	}
+	s := Styles{
+		Title:   lipgloss.NewStyle().Bold(true).Foreground(t.Text).Padding(1, 2, 0),
+		Summary: lipgloss.NewStyle().Foreground(t.Text).Padding(1, 2),
+	}
+	s.Upcoming = s.Summary.Copy().Foreground(t.Muted)
+	return &s
+}
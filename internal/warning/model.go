@@ -0,0 +1,56 @@
+// Package warning displays a dismissible warning above another model without interrupting it.
+package warning
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Model shows a warning above the wrapped model until the first key is pressed.
+// All messages are passed through to the wrapped model.
+type Model struct {
+	model   tea.Model
+	message string
+	style   lipgloss.Style
+}
+
+// New wraps the model with the warning message. An empty message is never shown.
+func New(model tea.Model, message string) *Model {
+	return &Model{
+		model:   model,
+		message: message,
+		style:   lipgloss.NewStyle().Foreground(lipgloss.Color("#F1D448")),
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.model.Init()
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch mouse := msg.(type) {
+	case tea.KeyMsg:
+		m.message = ""
+	case tea.MouseMsg:
+		// The wrapped model is drawn below the warning, so the mouse is passed on relative to where it is drawn.
+		if m.message != "" {
+			mouse.Y -= lipgloss.Height(m.warningView())
+			msg = mouse
+		}
+	}
+
+	var cmd tea.Cmd
+	m.model, cmd = m.model.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	if m.message == "" {
+		return m.model.View()
+	}
+	return m.warningView() + "\n" + m.model.View()
+}
+
+func (m Model) warningView() string {
+	return m.style.Render("Warning: " + m.message + " (using defaults)")
+}
@@ -1,38 +1,1313 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"runtime/pprof"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
+	"github.com/Broderick-Westrope/tetrigo/internal/api"
+	"github.com/Broderick-Westrope/tetrigo/internal/audio"
+	"github.com/Broderick-Westrope/tetrigo/internal/bench"
+	"github.com/Broderick-Westrope/tetrigo/internal/calibrate"
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/dual"
+	"github.com/Broderick-Westrope/tetrigo/internal/export"
+	"github.com/Broderick-Westrope/tetrigo/internal/fumen"
+	"github.com/Broderick-Westrope/tetrigo/internal/importer"
+	"github.com/Broderick-Westrope/tetrigo/internal/input"
+	"github.com/Broderick-Westrope/tetrigo/internal/leaderboard"
 	"github.com/Broderick-Westrope/tetrigo/internal/marathon"
 	"github.com/Broderick-Westrope/tetrigo/internal/menu"
+	"github.com/Broderick-Westrope/tetrigo/internal/mode"
+	"github.com/Broderick-Westrope/tetrigo/internal/preset"
+	"github.com/Broderick-Westrope/tetrigo/internal/profile"
+	"github.com/Broderick-Westrope/tetrigo/internal/puzzle"
+	"github.com/Broderick-Westrope/tetrigo/internal/records"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/room"
+	"github.com/Broderick-Westrope/tetrigo/internal/save"
+	"github.com/Broderick-Westrope/tetrigo/internal/seed"
+	"github.com/Broderick-Westrope/tetrigo/internal/serve"
+	"github.com/Broderick-Westrope/tetrigo/internal/spectate"
+	"github.com/Broderick-Westrope/tetrigo/internal/tournament"
+	"github.com/Broderick-Westrope/tetrigo/internal/versus"
+	"github.com/Broderick-Westrope/tetrigo/internal/viewer"
+	"github.com/Broderick-Westrope/tetrigo/internal/warmup"
+	"github.com/Broderick-Westrope/tetrigo/internal/warning"
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+	"github.com/Broderick-Westrope/tetrigo/tetris"
 	"github.com/alecthomas/kong"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 var cli struct {
+	Config  string `help:"Path to the config file (defaults to the user config directory)" type:"path"`
+	Profile string `help:"Name of the profile to play as, with its own config, personal bests and suspended game" env:"TETRIGO_PROFILE"`
+
 	Menu     struct{} `cmd:"" help:"Play the game" default:"1"`
 	Marathon struct {
-		Level uint `help:"Level to start at" short:"l" default:"1"`
+		Level          uint          `help:"Level to start at (defaults to the config)" short:"l"`
+		HoldPreview    bool          `help:"Show where the held tetrimino would land if swapped in"`
+		Hold           string        `help:"How often each tetrimino can be held (once, off or unlimited)" enum:"once,off,unlimited" default:"once"`
+		HoldSlots      int           `help:"How many tetriminos can be held at once, cycled through by holding again" default:"1"`
+		Seed           int64         `help:"Seed for the order of tetriminos, to play the same game again (defaults to a random seed)"`
+		Strict         bool          `help:"Reject physically impossible inputs and flag the game"`
+		Interludes     bool          `help:"Pause briefly to show the new level and speed after each level up"`
+		Preset         string        `help:"Board preset to start from (four-wide, late-game, sz-mess, tspin-slot)" short:"p"`
+		Fumen          string        `help:"Fumen to start from, as shared by other tools, instead of a preset"`
+		Mirror         bool          `help:"Flip the preset or fumen from left to right"`
+		Speed          float64       `help:"Speed of the whole game from 0.5 to 2, for practising slowly or quickly (defaults to the config)"`
+		Curve          string        `help:"How the fall speed increases with the level (guideline, nes or tgm)" enum:"guideline,nes,tgm" default:"guideline"`
+		EntryDelay     time.Duration `help:"Time the next tetrimino takes to spawn after one locks (ARE)"`
+		LineClearDelay time.Duration `help:"Extra time the next tetrimino takes to spawn when lines are cleared"`
+		History        bool          `help:"Show a log of recent events beside the board"`
+		Stream         bool          `help:"Start in the stream layout, with large readouts for viewers (toggled with f2)"`
+		Webcam         string        `help:"Size of a region to leave blank in the stream layout for a webcam overlay, such as 40x12"`
+		Replay         string        `help:"File to write a replay of the game to, for tetrigo analyze" type:"path"`
+		LogEvents      string        `help:"File to log every input, spawn, lock and clear to as they happen, as JSON lines" type:"path"`
+		Input          string        `help:"File or named pipe to read moves from as well as the keyboard, one per line, or - for standard input"`
+		Chat           string        `help:"IRC server and channel to take votes on moves from, such as irc.chat.twitch.tv:6667/#channel"`
+		VoteWindow     time.Duration `help:"How long each round of chat votes lasts" default:"2s"`
+		Record         string        `help:"File to export a recording of the game to, as an asciinema cast (.cast) or GIF (.gif)" type:"path"`
+		Broadcast      string        `help:"Address to let spectators watch the game on"`
+		Local          bool          `help:"Let tetrigo watch mirror the game from another terminal on this machine"`
+		Socket         string        `help:"Path of the socket used by --local (defaults to the temp directory)" type:"path"`
 	} `cmd:"" help:"Play marathon mode"`
+	Classic struct {
+		Level uint `help:"Level to start at, where 1 is the NES's level 0 (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Play by the rules of the NES version"`
+	Master struct {
+		Level   uint   `help:"Level to start at (defaults to the config)" short:"l"`
+		Grading string `help:"How the game is graded: by lines cleared, or by score and time (lines or score)" enum:"lines,score" default:"lines"`
+	} `cmd:"" help:"Play with instant gravity, beating the lock delay"`
+	Cheese struct {
+		Rows      uint   `help:"Rows of cheese on the board, from 1 to 18" short:"r" default:"10"`
+		Total     uint   `help:"Rows of cheese to dig in total, adding new rows as they are cleared (defaults to --rows)" short:"t"`
+		Holes     string `help:"How the hole moves from row to row (messy, clean or random)" enum:"messy,clean,random" default:"messy"`
+		HoleWidth int    `help:"Columns each hole spans" default:"1"`
+		Level     uint   `help:"Level to start at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Dig through rows of garbage as fast as possible"`
+	Dig struct {
+		Interval  time.Duration `help:"Time between each line of garbage rising" short:"i" default:"4s"`
+		Messiness float64       `help:"Probability (0 to 1) that the hole moves to a different column on each line" default:"0.3"`
+		Holes     string        `help:"How the hole moves from line to line (messy, clean or random)" enum:"messy,clean,random" default:"messy"`
+		HoleWidth int           `help:"Columns each hole spans" default:"1"`
+		Level     uint          `help:"Level to start at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Survive garbage rising from the bottom for as long as possible"`
+	Daily     struct{} `cmd:"" help:"Play today's daily challenge, the same cheese race for everyone"`
+	Invisible struct {
+		Fade  time.Duration `help:"How long locked tetriminos stay visible (0 to hide them as soon as they lock)" short:"f"`
+		Level uint          `help:"Level to start at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Play with the stack hidden from view, from memory"`
+	Puzzle struct {
+		Name  string `arg:"" optional:"" help:"Bundled puzzle to play, the path of a puzzle file, or a fumen quiz (lists the bundled puzzles if not given)"`
+		Level uint   `help:"Level to start at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Solve a puzzle by clearing a board with a fixed queue of tetriminos"`
+	Dual struct {
+		Level uint `help:"Level to start at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Play on two boards at once, switching between them with tab"`
+	Combo struct {
+		Level uint   `help:"Level to start at (defaults to the config)" short:"l"`
+		Hold  string `help:"How often each tetrimino can be held (once, off or unlimited)" enum:"once,off,unlimited" default:"once"`
+	} `cmd:"" help:"Practice combos in a 4-wide well"`
+	Drill struct {
+		Level uint `help:"Level to start at (defaults to the config)" short:"l"`
+		Count uint `help:"Number of drills to play" short:"n" default:"10"`
+	} `cmd:"" help:"Practice T-spin doubles and triples on generated boards"`
+	PC struct {
+		Level uint   `help:"Level to start at (defaults to the config)" short:"l"`
+		Count uint   `help:"Number of openers to attempt" short:"n" default:"10"`
+		Hold  string `help:"How often each tetrimino can be held (once, off or unlimited)" enum:"once,off,unlimited" default:"once"`
+	} `cmd:"" help:"Practice perfect-clear openers, clearing an empty board within 10 tetriminos"`
+	Sandbox struct {
+		Level  uint   `help:"Level to start at (defaults to the config)" short:"l"`
+		Preset string `help:"Board preset to start from (four-wide, late-game, sz-mess, tspin-slot)" short:"p"`
+	} `cmd:"" help:"Try out setups, pausing gravity, editing the board and picking the next tetrimino"`
+	Versus struct {
+		Level         uint    `help:"Level to start at (defaults to the config)" short:"l"`
+		Multiplier    float64 `help:"Multiply the garbage you send, such as 0.5 to handicap yourself" default:"1"`
+		Garbage       uint    `help:"Rows of garbage you start with"`
+		BotMultiplier float64 `help:"Multiply the garbage the bot sends" default:"1"`
+		BotGarbage    uint    `help:"Rows of garbage the bot starts with"`
+		Holes         string  `help:"How the hole in garbage moves from line to line (messy, clean or random)" enum:"messy,clean,random" default:"messy"`
+		HoleWidth     int     `help:"Columns each hole in garbage spans" default:"1"`
+	} `cmd:"" help:"Play versus mode against the bot"`
+	Host struct {
+		Addr            string  `help:"Address to listen on" default:":7070"`
+		Level           uint    `help:"Level to start at (defaults to the config)" short:"l"`
+		Multiplier      float64 `help:"Multiply the garbage you send, such as 0.5 to handicap yourself" default:"1"`
+		Garbage         uint    `help:"Rows of garbage you start with"`
+		GuestMultiplier float64 `help:"Multiply the garbage your opponent sends" default:"1"`
+		GuestGarbage    uint    `help:"Rows of garbage your opponent starts with"`
+		BestOf          uint    `help:"Games in the match, won by the first to win more than half of them" default:"1"`
+	} `cmd:"" help:"Host a networked versus game"`
+	Join struct {
+		Addr string `arg:"" help:"Address of the host"`
+	} `cmd:"" help:"Join a networked versus game"`
+	Rooms struct {
+		Addr string `help:"Address to listen on" default:":7072"`
+	} `cmd:"" help:"Run a room server for free-for-alls between any number of players"`
+	CreateRoom struct {
+		Server    string `arg:"" help:"Address of the room server"`
+		Level     uint   `help:"Level to start at (defaults to the config)" short:"l"`
+		Targeting string `help:"Who each attack is sent to (random, attacker or badges)" enum:"random,attacker,badges" default:"random"`
+	} `cmd:"" help:"Create a room on a room server for others to join with its code"`
+	JoinRoom struct {
+		Server string `arg:"" help:"Address of the room server"`
+		Code   string `arg:"" help:"Code of the room, as shared by the player who created it"`
+	} `cmd:"" help:"Join a room on a room server by its code"`
+	Serve struct {
+		SSH     string `name:"ssh" help:"Address to serve the game over SSH on" default:":2222"`
+		HostKey string `help:"Path to the server's host key (defaults to the user config directory)" type:"path"`
+		HTTP    string `name:"http" help:"Play marathon here instead, sharing it over HTTP and WebSocket on the address"`
+		Control bool   `help:"Let HTTP clients make moves in the game shared with --http"`
+	} `cmd:"" help:"Host the game for anyone to play over SSH, or share it over HTTP"`
+	Spectate struct {
+		Addr  string        `arg:"" help:"Address of the broadcasting game"`
+		Delay time.Duration `help:"How long to buffer the game for, smoothing out network jitter" default:"300ms"`
+	} `cmd:"" help:"Watch a game broadcast with marathon --broadcast"`
+	Watch struct {
+		Socket string `help:"Path of the socket the game is broadcasting on (defaults to the temp directory)" type:"path"`
+	} `cmd:"" help:"Mirror the board of a game started with marathon --local"`
+	Warmup struct {
+		Exercises []string `help:"Exercises to play, in order (drill, cheese, sprint)" short:"e"`
+	} `cmd:"" help:"Play a warm-up routine of short exercises"`
+	Tournament struct {
+		Players []string      `arg:"" optional:"" help:"Players to enter, in seeded order (continues the saved tournament if none)"`
+		File    string        `help:"File the tournament is saved to after each turn (defaults to the config directory)" type:"path"`
+		Time    time.Duration `help:"Length of each turn" default:"2m"`
+		Level   uint          `help:"Level each turn starts at (defaults to the config)" short:"l"`
+		New     bool          `help:"Start a new tournament even if the saved one is unfinished"`
+	} `cmd:"" help:"Run a bracket of players taking turns on this machine, saved between sessions"`
+	Seed struct {
+		Value int64 `arg:"" help:"Seed to explore"`
+		Bags  int   `help:"Number of bags to show" short:"n" default:"10"`
+		Level uint  `help:"Level to start games at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Show the tetriminos dealt by a seed and play games with it"`
+	Analyze struct {
+		File    string `arg:"" help:"Replay written with marathon --replay" type:"existingfile"`
+		JSON    bool   `help:"Print the analysis as JSON, for other tools"`
+		Section uint   `help:"Lines cleared in each section" default:"10"`
+	} `cmd:"" help:"Analyse a replay, section by section"`
+	Verify struct {
+		File string `arg:"" help:"Replay written with marathon --replay" type:"existingfile"`
+	} `cmd:"" help:"Play a replay again to check it ends with the score and lines it claims"`
+	Export struct {
+		File   string `arg:"" help:"Replay written with marathon --replay" type:"existingfile"`
+		Output string `arg:"" help:"File to export to, as an asciinema cast (.cast) or GIF (.gif)" type:"path"`
+		Mirror bool   `help:"Flip the game from left to right"`
+	} `cmd:"" help:"Export a replay as an asciinema cast or animated GIF to share"`
+	View struct {
+		File string `arg:"" help:"Replay written with marathon --replay, or converted with tetrigo import" type:"existingfile"`
+	} `cmd:"" help:"Watch a replay in the terminal, placement by placement"`
+	Import struct {
+		File   string `arg:"" help:"Replay saved by another client, such as a TETR.IO .ttr or .ttrm" type:"existingfile"`
+		Output string `arg:"" help:"File to write the tetrigo replay to" type:"path"`
+		Round  int    `help:"Round of a multiplayer replay to import" default:"1"`
+		Player int    `help:"Player of the round whose game is imported, in the order the replay lists them" default:"1"`
+		Mirror bool   `help:"Flip the game from left to right"`
+	} `cmd:"" help:"Convert a replay from another client into a tetrigo replay, to analyse or export"`
+	Play struct {
+		Mode  string `arg:"" help:"Name of the mode to play, as listed in the menu"`
+		Level uint   `help:"Level to start at (defaults to the config)" short:"l"`
+	} `cmd:"" help:"Play any mode by name, including those registered by other packages"`
+	Bench struct {
+		Games      int    `help:"Games for the bot to play" short:"n" default:"10"`
+		Pieces     int    `help:"Most tetriminos each game is played for" default:"1000"`
+		Seed       int64  `help:"Seed of the first game, with each game after it using the next" default:"1"`
+		CPUProfile string `help:"File to write a CPU profile of the games to, for go tool pprof" type:"path"`
+	} `cmd:"" help:"Measure the speed of the engine with the bot playing games without drawing them"`
+	Calibrate struct{} `cmd:"" help:"Measure your key repeat and reactions to tune the handling of held keys"`
+	Records   struct{} `cmd:"" help:"List your personal bests"`
+	Profiles  struct{} `cmd:"" help:"List the profiles which have been played"`
 }
 
+// maxCheese is the most rows of cheese a cheese race can start with, leaving room in the visible playfield to spawn.
+const maxCheese = 18
+
 func main() {
 	ctx := kong.Parse(&cli)
+	if cli.Profile != "" {
+		err := profile.Validate(cli.Profile)
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+
+	cfg, cfgWarning := loadConfig()
+	cfgWarning = strings.TrimSpace(cfgWarning + "\n" + loadModes())
+	store := openRecords()
+	online, onlineWarning := openLeaderboard(cfg)
+	cfgWarning = strings.TrimSpace(cfgWarning + "\n" + onlineWarning)
+
+	// Sounds are played on this machine, so not while serving other players, watching someone else's game or listing
+	// records. A game shared over HTTP is still played here.
+	var sound *audio.Player
+	switch ctx.Command() {
+	case "spectate <addr>", "watch", "records", "profiles", "analyze <file>", "verify <file>", "export <file> <output>",
+		"import <file> <output>", "bench", "rooms":
+	default:
+		if ctx.Command() == "serve" && cli.Serve.HTTP == "" {
+			break
+		}
+		sound = audio.Open(cfg.Sound, cfg.Music)
+		defer sound.Close()
+	}
+
+	var m tea.Model
+	var opts []tea.ProgramOption
 	switch ctx.Command() {
 	case "menu":
-		startTeaModel(menu.InitialModel())
+		var newMenu func(cfg *config.Config) tea.Model
+		newMenu = func(cfg *config.Config) tea.Model {
+			in := &menu.Input{Config: cfg, Records: store, Leaderboard: online, Audio: sound, SavePath: savePath(), AutosavePath: autosavePath(), Profile: cli.Profile}
+			// Profiles are kept beside the default config file, so can't be switched between with --config.
+			if cli.Config == "" {
+				in.Profiles = profileNames()
+				in.SwitchProfile = func(name string) tea.Model {
+					cli.Profile = name
+					cfg, cfgWarning := loadConfig()
+					store = openRecords()
+					var onlineWarning string
+					online, onlineWarning = openLeaderboard(cfg)
+					return warning.New(newMenu(cfg), strings.TrimSpace(cfgWarning+"\n"+onlineWarning))
+				}
+			}
+			return menu.NewModel(in)
+		}
+		m = newMenu(cfg)
+		if path, ok := firstRun(); ok {
+			m = calibrate.NewModel(&calibrate.Input{Config: cfg, Path: path, Theme: cfg.Theme(), FirstRun: true, Then: newMenu})
+		}
+		// Settings can be clicked and scrolled through in terminals which report the mouse.
+		opts = append(opts, tea.WithMouseCellMotion())
+	case "calibrate":
+		path, err := configPath()
+		if err != nil {
+			exitWithError(err)
+		}
+		m = calibrate.NewModel(&calibrate.Input{Config: cfg, Path: path, Theme: cfg.Theme()})
 	case "marathon":
-		startTeaModel(marathon.InitialModel(cli.Marathon.Level))
+		in := &marathon.Input{
+			Level:          levelOrDefault(cli.Marathon.Level, cfg),
+			HoldPreview:    cli.Marathon.HoldPreview || cfg.HoldPreview,
+			Hold:           holdRule(cli.Marathon.Hold),
+			HoldSlots:      cli.Marathon.HoldSlots,
+			Seed:           cli.Marathon.Seed,
+			Strict:         cli.Marathon.Strict,
+			Countdown:      cfg.Countdown,
+			Interludes:     cli.Marathon.Interludes || cfg.Interludes,
+			Theme:          cfg.Theme(),
+			Speed:          cfg.Speed,
+			Curve:          cli.Marathon.Curve,
+			EntryDelay:     cli.Marathon.EntryDelay,
+			LineClearDelay: cli.Marathon.LineClearDelay,
+			History:        cli.Marathon.History,
+			Stream:         cli.Marathon.Stream,
+			ReplayPath:     cli.Marathon.Replay,
+			RecordingPath:  cli.Marathon.Record,
+			Width:          int(cfg.Width),
+			Height:         int(cfg.Height),
+			Randomizer:     cfg.Randomizer,
+			Handling:       cfg.Handling(),
+			Keys:           cfg.Keys,
+			Records:        store,
+			Leaderboard:    online,
+			Audio:          sound,
+		}
+		if cli.Marathon.HoldSlots < 1 || cli.Marathon.HoldSlots > tetris.MaxHoldSlots {
+			exitWithError(fmt.Errorf("invalid hold slots %d: must be between 1 and %d", cli.Marathon.HoldSlots, tetris.MaxHoldSlots))
+		}
+		if cli.Marathon.Speed != 0 {
+			if cli.Marathon.Speed < 0.5 || cli.Marathon.Speed > 2 {
+				exitWithError(fmt.Errorf("invalid speed %v: must be between 0.5 and 2", cli.Marathon.Speed))
+			}
+			in.Speed = cli.Marathon.Speed
+		}
+		if cli.Marathon.Webcam != "" {
+			err := in.Webcam.UnmarshalText([]byte(cli.Marathon.Webcam))
+			if err != nil {
+				exitWithError(err)
+			}
+		}
+		if cli.Marathon.Seed != 0 {
+			// As in the seed explorer, a game which can be played again knowing what comes next isn't recorded.
+			in.Records = nil
+		}
+		switch {
+		case cli.Marathon.Preset != "" && cli.Marathon.Fumen != "":
+			exitWithError(errors.New("--preset and --fumen can't both be given"))
+		case cli.Marathon.Preset != "":
+			var err error
+			in.Matrix, err = preset.Load(cli.Marathon.Preset)
+			if err != nil {
+				exitWithError(err)
+			}
+		case cli.Marathon.Fumen != "":
+			page, err := fumen.Decode(cli.Marathon.Fumen)
+			if err != nil {
+				exitWithError(err)
+			}
+			in.Matrix = &page.Matrix
+		}
+		if cli.Marathon.Mirror && in.Matrix != nil {
+			in.Matrix.Mirror()
+		}
+		if cli.Marathon.LogEvents != "" {
+			events, err := replay.CreateEventLog(cli.Marathon.LogEvents)
+			if err != nil {
+				exitWithError(err)
+			}
+			defer events.Close()
+			in.Events = events
+		}
+		if cli.Marathon.Input != "" {
+			source, closer, err := openInput(cli.Marathon.Input)
+			if err != nil {
+				exitWithError(err)
+			}
+			defer closer.Close()
+			in.Sources = append(in.Sources, source)
+			if cli.Marathon.Input == "-" {
+				// Standard input carries the moves, so keys can't be read from it as well.
+				opts = append(opts, tea.WithInput(nil))
+			}
+		}
+		if cli.Marathon.Chat != "" {
+			addr, channel, ok := strings.Cut(cli.Marathon.Chat, "/")
+			if !ok || channel == "" {
+				exitWithError(fmt.Errorf("invalid chat %q: expected <server>/<channel>", cli.Marathon.Chat))
+			}
+			votes := input.NewVotes(cli.Marathon.VoteWindow)
+			chat, err := input.DialChat(addr, channel, votes)
+			if err != nil {
+				exitWithError(err)
+			}
+			defer chat.Close()
+			in.Sources = append(in.Sources, votes)
+			// The chat may wander off, so the game pauses rather than topping out while nobody is voting.
+			in.IdleTimeout = cfg.Idle()
+		}
+		if len(in.Sources) > 0 {
+			// As with the bot, a game the player didn't make every move of isn't recorded.
+			in.Records = nil
+		}
+		game := marathon.NewModel(in)
+		m = game
+
+		settings := netplay.Settings{Level: in.Level}
+		var broadcasters []*spectate.Broadcaster
+		if cli.Marathon.Broadcast != "" {
+			b, err := spectate.Listen("tcp", cli.Marathon.Broadcast, settings)
+			if err != nil {
+				exitWithError(err)
+			}
+			defer b.Close()
+			broadcasters = append(broadcasters, b)
+		}
+		if cli.Marathon.Local {
+			b, err := spectate.ListenUnix(socketOrDefault(cli.Marathon.Socket), settings)
+			if err != nil {
+				exitWithError(err)
+			}
+			defer b.Close()
+			broadcasters = append(broadcasters, b)
+		}
+		if len(broadcasters) > 0 {
+			m = spectate.NewBroadcastModel(game, broadcasters...)
+		}
+	case "classic":
+		m = marathon.NewModel(&marathon.Input{
+			Level:       levelOrDefault(cli.Classic.Level, cfg),
+			Classic:     true,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		})
+	case "master":
+		m = marathon.NewModel(&marathon.Input{
+			Level:       levelOrDefault(cli.Master.Level, cfg),
+			Master:      true,
+			Grading:     cli.Master.Grading,
+			Width:       int(cfg.Width),
+			Height:      int(cfg.Height),
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		})
+	case "cheese":
+		if cli.Cheese.Rows < 1 || cli.Cheese.Rows > maxCheese {
+			exitWithError(fmt.Errorf("invalid rows %v: must be between 1 and %d", cli.Cheese.Rows, maxCheese))
+		}
+		validateHoleWidth(cli.Cheese.HoleWidth, tetris.DefaultWidth)
+		m = marathon.NewModel(&marathon.Input{
+			Level:           levelOrDefault(cli.Cheese.Level, cfg),
+			HoldPreview:     cfg.HoldPreview,
+			Cheese:          cli.Cheese.Rows,
+			CheeseTotal:     cli.Cheese.Total,
+			CheeseHoles:     holePattern(cli.Cheese.Holes),
+			CheeseHoleWidth: cli.Cheese.HoleWidth,
+			Countdown:       cfg.Countdown,
+			Theme:           cfg.Theme(),
+			Speed:           cfg.Speed,
+			Handling:        cfg.Handling(),
+			Keys:            cfg.Keys,
+			Records:         store,
+			Leaderboard:     online,
+			Audio:           sound,
+		})
+	case "daily":
+		m = marathon.NewModel(&marathon.Input{
+			Daily:       marathon.DailyDate(time.Now()),
+			HoldPreview: cfg.HoldPreview,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		})
+	case "dig":
+		if cli.Dig.Interval < time.Second {
+			exitWithError(fmt.Errorf("invalid interval %v: must be at least 1s", cli.Dig.Interval))
+		}
+		if cli.Dig.Messiness < 0 || cli.Dig.Messiness > 1 {
+			exitWithError(fmt.Errorf("invalid messiness %v: must be between 0 and 1", cli.Dig.Messiness))
+		}
+		validateHoleWidth(cli.Dig.HoleWidth, int(cfg.Width))
+		m = marathon.NewModel(&marathon.Input{
+			Level:            levelOrDefault(cli.Dig.Level, cfg),
+			HoldPreview:      cfg.HoldPreview,
+			Dig:              cli.Dig.Interval,
+			GarbageMessiness: cli.Dig.Messiness,
+			GarbageHoles:     holePattern(cli.Dig.Holes),
+			GarbageHoleWidth: cli.Dig.HoleWidth,
+			Width:            int(cfg.Width),
+			Height:           int(cfg.Height),
+			Randomizer:       cfg.Randomizer,
+			Countdown:        cfg.Countdown,
+			Theme:            cfg.Theme(),
+			Speed:            cfg.Speed,
+			Handling:         cfg.Handling(),
+			Keys:             cfg.Keys,
+			Records:          store,
+			Leaderboard:      online,
+			Audio:            sound,
+		})
+	case "invisible":
+		m = marathon.NewModel(&marathon.Input{
+			Level:       levelOrDefault(cli.Invisible.Level, cfg),
+			Invisible:   true,
+			FadeDelay:   cli.Invisible.Fade,
+			Width:       int(cfg.Width),
+			Height:      int(cfg.Height),
+			Randomizer:  cfg.Randomizer,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		})
+	case "puzzle":
+		listPuzzles()
+		return
+	case "puzzle <name>":
+		p, err := loadPuzzle(cli.Puzzle.Name)
+		if err != nil {
+			exitWithError(err)
+		}
+		m = marathon.NewModel(&marathon.Input{
+			Level:       levelOrDefault(cli.Puzzle.Level, cfg),
+			Puzzle:      p,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		})
+	case "dual":
+		m = dual.NewModel(&dual.Input{
+			Level:     levelOrDefault(cli.Dual.Level, cfg),
+			Countdown: cfg.Countdown,
+			Theme:     cfg.Theme(),
+			Speed:     cfg.Speed,
+			Audio:     sound,
+			Keys:      cfg.Keys,
+			Handling:  cfg.Handling(),
+		})
+	case "combo":
+		m = marathon.NewModel(&marathon.Input{
+			Level:         levelOrDefault(cli.Combo.Level, cfg),
+			HoldPreview:   cfg.HoldPreview,
+			ComboPractice: true,
+			Hold:          holdRule(cli.Combo.Hold),
+			Countdown:     cfg.Countdown,
+			Theme:         cfg.Theme(),
+			Speed:         cfg.Speed,
+			Handling:      cfg.Handling(),
+			Keys:          cfg.Keys,
+			Audio:         sound,
+		})
+	case "drill":
+		m = marathon.NewModel(&marathon.Input{
+			Level:       levelOrDefault(cli.Drill.Level, cfg),
+			HoldPreview: cfg.HoldPreview,
+			TSpinDrills: max(cli.Drill.Count, 1),
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Audio:       sound,
+		})
+	case "pc":
+		m = marathon.NewModel(&marathon.Input{
+			Level:       levelOrDefault(cli.PC.Level, cfg),
+			HoldPreview: cfg.HoldPreview,
+			PCOpeners:   max(cli.PC.Count, 1),
+			Hold:        holdRule(cli.PC.Hold),
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Audio:       sound,
+		})
+	case "sandbox":
+		in := &marathon.Input{
+			Level:       levelOrDefault(cli.Sandbox.Level, cfg),
+			HoldPreview: cfg.HoldPreview,
+			Sandbox:     true,
+			Width:       int(cfg.Width),
+			Height:      int(cfg.Height),
+			Randomizer:  cfg.Randomizer,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Audio:       sound,
+		}
+		if cli.Sandbox.Preset != "" {
+			var err error
+			in.Matrix, err = preset.Load(cli.Sandbox.Preset)
+			if err != nil {
+				exitWithError(err)
+			}
+		}
+		m = marathon.NewModel(in)
+	case "versus":
+		in := &versus.Input{
+			Level:       levelOrDefault(cli.Versus.Level, cfg),
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Audio:       sound,
+			Keys:        cfg.Keys,
+			Handling:    cfg.Handling(),
+			Attack:      cfg.AttackTable(),
+			Handicap:    netplay.Handicap{Multiplier: cli.Versus.Multiplier, Garbage: cli.Versus.Garbage},
+			BotHandicap: netplay.Handicap{Multiplier: cli.Versus.BotMultiplier, Garbage: cli.Versus.BotGarbage},
+			Holes:       holePattern(cli.Versus.Holes),
+			HoleWidth:   cli.Versus.HoleWidth,
+		}
+		validateHoleWidth(cli.Versus.HoleWidth, tetris.DefaultWidth)
+		for _, h := range []netplay.Handicap{in.Handicap, in.BotHandicap} {
+			if err := h.Validate(); err != nil {
+				exitWithError(err)
+			}
+		}
+		m = versus.NewModel(in)
+	case "host":
+		fmt.Printf("Waiting for an opponent on %s...\n", cli.Host.Addr)
+		settings := &netplay.Settings{
+			Seed:      time.Now().UnixNano(),
+			Level:     levelOrDefault(cli.Host.Level, cfg),
+			Countdown: cfg.Countdown,
+			BestOf:    cli.Host.BestOf,
+			Attack:    cfg.AttackTable(),
+			Host:      netplay.Handicap{Multiplier: cli.Host.Multiplier, Garbage: cli.Host.Garbage},
+			Guest:     netplay.Handicap{Multiplier: cli.Host.GuestMultiplier, Garbage: cli.Host.GuestGarbage},
+		}
+		if err := settings.Validate(); err != nil {
+			exitWithError(err)
+		}
+		conn, err := netplay.Host(cli.Host.Addr, *settings)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer conn.Close()
+		m = versus.NewNetworkModel(conn, settings, true, cfg.Theme(), sound, cfg.Keys, cfg.Handling())
+	case "join <addr>":
+		conn, settings, err := netplay.Join(cli.Join.Addr)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer conn.Close()
+		m = versus.NewNetworkModel(conn, settings, false, cfg.Theme(), sound, cfg.Keys, cfg.Handling())
+	case "rooms":
+		server, err := room.Listen(cli.Rooms.Addr)
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("Hosting rooms on %s...\n", cli.Rooms.Addr)
+		done := make(chan os.Signal, 1)
+		signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+		<-done
+		server.Close()
+		return
+	case "create-room <server>":
+		settings := netplay.Settings{
+			Level:     levelOrDefault(cli.CreateRoom.Level, cfg),
+			Countdown: cfg.Countdown,
+			Attack:    cfg.AttackTable(),
+			Targeting: cli.CreateRoom.Targeting,
+		}
+		conn, r, err := netplay.CreateRoom(cli.CreateRoom.Server, settings)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer conn.Close()
+		m = versus.NewRoomModel(conn, r, cfg.Theme(), sound, cfg.Keys, cfg.Handling())
+	case "join-room <server> <code>":
+		conn, r, err := netplay.JoinRoom(cli.JoinRoom.Server, cli.JoinRoom.Code)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer conn.Close()
+		m = versus.NewRoomModel(conn, r, cfg.Theme(), sound, cfg.Keys, cfg.Handling())
+	case "serve":
+		if cli.Serve.HTTP == "" {
+			serveSSH(cfg, cfgWarning, store)
+			return
+		}
+		server, err := api.Listen(cli.Serve.HTTP, cli.Serve.Control)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer server.Close()
+		in := &marathon.Input{
+			Level:       cfg.Level,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Width:       int(cfg.Width),
+			Height:      int(cfg.Height),
+			Randomizer:  cfg.Randomizer,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		}
+		if cli.Serve.Control {
+			// Moves made by other programs can't be told apart from the player's, so the game isn't recorded.
+			in.Sources = []input.Source{server}
+			in.Records = nil
+		}
+		m = api.NewModel(marathon.NewModel(in), server)
+	case "records":
+		err := printRecords(store)
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "analyze <file>":
+		err := printAnalysis()
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "verify <file>":
+		err := verifyReplay()
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "export <file> <output>":
+		r, err := replay.Read(cli.Export.File)
+		if err != nil {
+			exitWithError(err)
+		}
+		if cli.Export.Mirror {
+			r.Mirror()
+		}
+		err = export.Write(cli.Export.Output, r, cfg.Theme())
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "view <file>":
+		r, err := replay.Read(cli.View.File)
+		if err != nil {
+			exitWithError(err)
+		}
+		m = viewer.NewModel(r, &viewer.Input{Theme: cfg.Theme()})
+	case "bench":
+		err := runBenchmark(cfg)
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "import <file> <output>":
+		err := importReplay()
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "profiles":
+		err := printProfiles()
+		if err != nil {
+			exitWithError(err)
+		}
+		return
+	case "spectate <addr>":
+		conn, _, err := netplay.Join(cli.Spectate.Addr)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer conn.Close()
+		m = spectate.NewModel(conn, &spectate.Input{Delay: cli.Spectate.Delay, Theme: cfg.Theme()})
+	case "watch":
+		conn, _, err := netplay.Dial("unix", socketOrDefault(cli.Watch.Socket))
+		if err != nil {
+			exitWithError(err)
+		}
+		defer conn.Close()
+		m = spectate.NewModel(conn, &spectate.Input{BoardOnly: true, Theme: cfg.Theme()})
+	case "warmup":
+		var err error
+		m, err = warmup.NewModel(&warmup.Input{
+			Routine:   cli.Warmup.Exercises,
+			Countdown: cfg.Countdown,
+			Theme:     cfg.Theme(),
+			Speed:     cfg.Speed,
+			Handling:  cfg.Handling(),
+			Records:   store,
+			Audio:     sound,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+	case "tournament", "tournament <players>":
+		path, bracket, err := loadTournament()
+		if err != nil {
+			exitWithError(err)
+		}
+		m = tournament.NewModel(&tournament.Input{
+			Bracket:     bracket,
+			Path:        path,
+			TimeLimit:   cli.Tournament.Time,
+			Level:       levelOrDefault(cli.Tournament.Level, cfg),
+			HoldPreview: cfg.HoldPreview,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Audio:       sound,
+		})
+	case "play <mode>":
+		gameMode := mode.Get(cli.Play.Mode)
+		if gameMode == nil {
+			exitWithError(fmt.Errorf("unknown mode %q (choose from %s)", cli.Play.Mode, strings.Join(mode.Names(), ", ")))
+		}
+		var err error
+		m, err = gameMode.New(&mode.Settings{
+			Level:       levelOrDefault(cli.Play.Level, cfg),
+			HoldPreview: cfg.HoldPreview,
+			Hold:        tetris.HoldOnce,
+			Width:       int(cfg.Width),
+			Height:      int(cfg.Height),
+			Randomizer:  cfg.Randomizer,
+			Attack:      cfg.AttackTable(),
+			Countdown:   cfg.Countdown,
+			Interludes:  cfg.Interludes,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Keys:        cfg.Keys,
+			Records:     store,
+			Leaderboard: online,
+			Audio:       sound,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+	case "seed <value>":
+		m = seed.NewModel(&seed.Input{
+			Seed:        cli.Seed.Value,
+			Bags:        cli.Seed.Bags,
+			Level:       levelOrDefault(cli.Seed.Level, cfg),
+			HoldPreview: cfg.HoldPreview,
+			Countdown:   cfg.Countdown,
+			Theme:       cfg.Theme(),
+			Speed:       cfg.Speed,
+			Handling:    cfg.Handling(),
+			Audio:       sound,
+		})
 	default:
-		panic(ctx.Command())
+		exitWithError(fmt.Errorf("unknown command %q", ctx.Command()))
+	}
+
+	startTeaModel(warning.New(m, cfgWarning), opts...)
+}
+
+// loadConfig loads the config file, returning a warning to show in place of any error.
+// The game is always playable, falling back to the default config if the file is invalid.
+func loadConfig() (*config.Config, string) {
+	path, err := configPath()
+	if err != nil {
+		return config.Default(), err.Error()
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return cfg, err.Error()
+	}
+	return cfg, ""
+}
+
+// loadModes registers the custom modes in the modes directory beside the config file, returning a warning to show
+// for any which couldn't be loaded. The built-in modes are always playable.
+func loadModes() string {
+	path, err := configPath()
+	if err != nil {
+		return ""
+	}
+	var warnings []string
+	for _, err := range mode.LoadDir(mode.DefaultDir(path)) {
+		warnings = append(warnings, err.Error())
+	}
+	return strings.Join(warnings, "\n")
+}
+
+// configPath returns the path of the config file given on the command line, or in the user's config directory.
+func configPath() (string, error) {
+	if cli.Config != "" {
+		return cli.Config, nil
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return profile.Path(cli.Profile, path)
+}
+
+// firstRun reports whether the game is being run for the first time, since there is no config file yet, and returns
+// the path of the file.
+func firstRun() (string, bool) {
+	path, err := configPath()
+	if err != nil {
+		return "", false
+	}
+	_, err = os.Stat(path)
+	return path, errors.Is(err, fs.ErrNotExist)
+}
+
+// savePath returns the path of the file games are suspended to in the user's config directory, or an empty string if
+// it cannot be found, so that games cannot be suspended.
+func savePath() string {
+	path, err := save.DefaultPath()
+	if err != nil {
+		return ""
+	}
+	path, err = profile.Path(cli.Profile, path)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadTournament returns the path of the tournament file and the bracket to play: a new one if players were entered,
+// or else the one saved at the path. An unfinished tournament is only replaced when asked to with --new.
+func loadTournament() (string, *tournament.Bracket, error) {
+	path := cli.Tournament.File
+	if path == "" {
+		var err error
+		path, err = tournament.DefaultPath()
+		if err != nil {
+			return "", nil, err
+		}
+		path, err = profile.Path(cli.Profile, path)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if len(cli.Tournament.Players) == 0 {
+		if !tournament.Exists(path) {
+			return "", nil, errors.New("no tournament to continue: enter players to start one")
+		}
+		b, err := tournament.Read(path)
+		return path, b, err
+	}
+
+	if tournament.Exists(path) && !cli.Tournament.New {
+		b, err := tournament.Read(path)
+		if err == nil && b.Champion() == "" {
+			return "", nil, fmt.Errorf("the tournament at %s is unfinished: continue it without players, or start again with --new", path)
+		}
+	}
+	b, err := tournament.NewBracket(cli.Tournament.Players, time.Now().UnixNano())
+	if err != nil {
+		return "", nil, err
+	}
+	return path, b, tournament.Write(path, b)
+}
+
+// autosavePath returns the path of the file games in progress are snapshotted to in the user's config directory, or an
+// empty string if it cannot be found, so that games are not autosaved.
+func autosavePath() string {
+	path, err := save.DefaultAutosavePath()
+	if err != nil {
+		return ""
+	}
+	path, err = profile.Path(cli.Profile, path)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// openRecords returns the store of personal bests in the user's config directory, or nil if it cannot be found.
+func openRecords() *records.Store {
+	path, err := records.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	path, err = profile.Path(cli.Profile, path)
+	if err != nil {
+		return nil
+	}
+	return records.NewStore(path)
+}
+
+// openLeaderboard returns the client submitting the results of games to the leaderboard, or nil unless submitting them
+// was opted in to, returning a warning to show if they can't be. Results are submitted under the name logged in with
+// unless another is set.
+func openLeaderboard(cfg *config.Config) (*leaderboard.Client, string) {
+	if !cfg.SubmitScores || cfg.LeaderboardURL == "" {
+		return nil, ""
+	}
+	path, err := leaderboard.DefaultQueuePath()
+	if err == nil {
+		path, err = profile.Path(cli.Profile, path)
+	}
+	if err != nil {
+		return nil, fmt.Sprintf("scores won't be submitted to the leaderboard: %v", err)
+	}
+	name := cfg.LeaderboardName
+	if u, err := user.Current(); name == "" && err == nil {
+		name = u.Username
+	}
+	return leaderboard.NewClient(cfg.LeaderboardURL, name, path), ""
+}
+
+// profileNames returns the profiles which have been played, or none if they can't be found.
+func profileNames() []string {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	names, _ := profile.Names(filepath.Dir(path))
+	return names
+}
+
+// printProfiles lists the profiles which have been played, beside the files of the default profile.
+func printProfiles() error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	names, err := profile.Names(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No profiles yet. Play with --profile <name> to create one.")
+		return nil
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// printAnalysis analyses the replay given on the command line, printing it as text or JSON.
+func printAnalysis() error {
+	r, err := replay.Read(cli.Analyze.File)
+	if err != nil {
+		return err
 	}
+	report, err := replay.Analyze(r, cli.Analyze.Section)
+	if err != nil {
+		return err
+	}
+	if !cli.Analyze.JSON {
+		return report.WriteText(os.Stdout)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// verifyReplay plays the replay given on the command line again, printing the results it claims if they match.
+func verifyReplay() error {
+	r, err := replay.Read(cli.Verify.File)
+	if err != nil {
+		return err
+	}
+	err = replay.Verify(r, r.Score, r.Lines)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Verified: %d points and %d lines\n", r.Score, r.Lines)
+	return nil
+}
+
+// importReplay converts the replay saved by another client into a tetrigo replay.
+func importReplay() error {
+	r, err := importer.Read(cli.Import.File, importer.Options{Round: cli.Import.Round, Player: cli.Import.Player})
+	if err != nil {
+		return err
+	}
+	if cli.Import.Mirror {
+		r.Mirror()
+	}
+	err = replay.Write(cli.Import.Output, r)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %s: %d tetriminos placed and %d lines\n", r.Mode, len(r.Placements), r.Lines)
+	return nil
+}
+
+// runBenchmark has the bot play games without drawing them, printing how quickly the engine played them and where
+// the time went.
+func runBenchmark(cfg *config.Config) error {
+	if cli.Bench.CPUProfile != "" {
+		f, err := os.Create(cli.Bench.CPUProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		defer f.Close()
+		err = pprof.StartCPUProfile(f)
+		if err != nil {
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	r, err := bench.Run(bench.Options{
+		Games:      cli.Bench.Games,
+		Pieces:     cli.Bench.Pieces,
+		Seed:       cli.Bench.Seed,
+		Randomizer: cfg.Randomizer,
+		Width:      int(cfg.Width),
+		Height:     int(cfg.Height),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d games, %d tetriminos and %d lines in %s\n", r.Games, r.Pieces, r.Lines, r.Elapsed.Round(time.Millisecond))
+	fmt.Printf("Pieces per second: %.0f\n", r.PiecesPerSecond())
+	fmt.Printf("Allocations: %.1f per tetrimino, %d bytes in all\n", r.AllocsPerPiece(), r.Bytes)
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOT SPOT\tTIME\tSHARE\tCALLS")
+	for _, p := range r.Phases {
+		fmt.Fprintf(w, "%s\t%s\t%.1f%%\t%d\n", p.Name, p.Time.Round(time.Microsecond),
+			100*p.Time.Seconds()/r.Elapsed.Seconds(), p.Calls)
+	}
+	return w.Flush()
 }
 
-func startTeaModel(m tea.Model) {
-	p := tea.NewProgram(m)
+// printRecords lists the personal bests kept for each mode, by key, with the time set. Daily challenges are listed
+// separately, most recent first.
+func printRecords(store *records.Store) error {
+	if store == nil {
+		return errors.New("failed to find records")
+	}
+	all, err := store.All()
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		fmt.Println("No personal bests yet.")
+		return nil
+	}
+
+	var keys, daily []string
+	for k := range all {
+		// Records are kept under keys such as "daily-2026-10-14", prefixed with the player's name over SSH.
+		mode := k[strings.LastIndex(k, "/")+1:]
+		if strings.HasPrefix(mode, "daily-") {
+			daily = append(daily, k)
+		} else {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	slices.Sort(daily)
+	slices.Reverse(daily)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printRecordTable(w, "MODE", keys, all)
+	if len(daily) > 0 {
+		if len(keys) > 0 {
+			fmt.Fprintln(w)
+		}
+		printRecordTable(w, "DAILY CHALLENGE", daily, all)
+	}
+	return w.Flush()
+}
+
+func printRecordTable(w io.Writer, heading string, keys []string, all map[string]records.Record) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s\tSCORE\tLINES\tTIME\tDATE\n", heading)
+	for _, k := range keys {
+		r := all[k]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", k, r.Score, r.Lines, r.Time.Round(10*time.Millisecond), r.Date.Format(time.DateOnly))
+	}
+}
+
+// serveSSH runs the SSH server until it is interrupted. Config warnings are shown to whoever runs the server, not to players.
+func serveSSH(cfg *config.Config, cfgWarning string, store *records.Store) {
+	if cfgWarning != "" {
+		fmt.Printf("Warning: %s (using defaults)\n", cfgWarning)
+	}
+
+	hostKey := cli.Serve.HostKey
+	if hostKey == "" {
+		var err error
+		hostKey, err = serve.DefaultHostKeyPath()
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+
+	// Games are still played without autosaves if there is nowhere to keep them.
+	autosaveDir, _ := serve.DefaultAutosaveDir()
+
+	fmt.Printf("Serving on %s...\n", cli.Serve.SSH)
+	err := serve.Run(&serve.Input{Addr: cli.Serve.SSH, HostKeyPath: hostKey, Config: cfg, Records: store, AutosaveDir: autosaveDir})
+	if err != nil {
+		exitWithError(err)
+	}
+}
+
+// openInput opens the file or named pipe at the path to read moves from, or standard input for "-".
+func openInput(path string) (input.Source, io.Closer, error) {
+	if path == "-" {
+		return input.NewReader(os.Stdin), os.Stdin, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open input: %w", err)
+	}
+	return input.NewReader(f), f, nil
+}
+
+// loadPuzzle loads the named bundled puzzle, the puzzle file at the given path if there is one, or the puzzle given
+// by a fumen.
+func loadPuzzle(name string) (*puzzle.Puzzle, error) {
+	if strings.Contains(name, "115@") {
+		return puzzle.FromFumen(name)
+	}
+	if _, err := os.Stat(name); err == nil {
+		return puzzle.LoadFile(name)
+	}
+	return puzzle.Load(name)
+}
+
+// listPuzzles prints the name, title and objective of each bundled puzzle.
+func listPuzzles() {
+	fmt.Println("Puzzles (play one with tetrigo puzzle <name>):")
+	for _, name := range puzzle.Names() {
+		p, err := puzzle.Load(name)
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("  %-14s%s: %s\n", name, p.Title, p.Objective())
+	}
+}
+
+func levelOrDefault(level uint, cfg *config.Config) uint {
+	if level == 0 {
+		return cfg.Level
+	}
+	return level
+}
+
+// holdRule returns the hold rule with the given name, which kong has already checked is valid.
+func holdRule(name string) tetris.HoldRule {
+	rule, _ := tetris.ParseHoldRule(name)
+	return rule
+}
+
+// holePattern returns the hole pattern with the given name, which kong has already checked is valid.
+func holePattern(name string) tetris.HolePattern {
+	pattern, _ := tetris.ParseHolePattern(name)
+	return pattern
+}
+
+// validateHoleWidth exits if holes of the width would leave no filled cell in a line of garbage on a board of the
+// given width (0 for the default).
+func validateHoleWidth(holeWidth, width int) {
+	if width == 0 {
+		width = tetris.DefaultWidth
+	}
+	if holeWidth < 1 || holeWidth >= width {
+		exitWithError(fmt.Errorf("invalid hole width %d: must be between 1 and %d", holeWidth, width-1))
+	}
+}
+
+func socketOrDefault(path string) string {
+	if path == "" {
+		return spectate.DefaultSocketPath()
+	}
+	return path
+}
+
+func startTeaModel(m tea.Model, opts ...tea.ProgramOption) {
+	p := tea.NewProgram(m, opts...)
 	if _, err := p.Run(); err != nil {
-		fmt.Printf("Alas, there's been an error: %v", err)
-		os.Exit(1)
+		exitWithError(err)
 	}
 }
+
+func exitWithError(err error) {
+	fmt.Printf("Alas, there's been an error: %v", err)
+	os.Exit(1)
+}
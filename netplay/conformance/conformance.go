@@ -0,0 +1,290 @@
+// Package conformance checks that an implementation of the netplay protocol, such as a third-party client or bot,
+// speaks it correctly. It plays the other side of a game against the implementation: it exchanges the hello message
+// and states with it, sends it garbage which it must report having received, and then tops out so it wins.
+//
+// The checks can be run from a Go test of the implementation:
+//
+//	l, err := net.Listen("tcp", "127.0.0.1:0")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer l.Close()
+//	go myclient.Join(l.Addr().String())
+//	if err := conformance.TestClient(l, nil); err != nil {
+//		t.Fatal(err)
+//	}
+package conformance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+const (
+	DefaultTimeout = 5 * time.Second
+	DefaultStates  = 3
+	DefaultGarbage = 2
+)
+
+// stateInterval is how often the tester sends its own state to the implementation.
+const stateInterval = 100 * time.Millisecond
+
+type Options struct {
+	// Settings are sent to clients in the hello message (level 1 if not set). Hosts choose their own.
+	Settings netplay.Settings
+
+	Timeout time.Duration // how long to wait for each message (DefaultTimeout if 0)
+	States  int           // state messages to receive before garbage is sent (DefaultStates if 0)
+	Garbage uint          // lines of garbage sent, which must be reported as received (DefaultGarbage if 0)
+}
+
+func (o *Options) withDefaults() Options {
+	var opts Options
+	if o != nil {
+		opts = *o
+	}
+	if opts.Settings.Level == 0 {
+		opts.Settings.Level = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.States <= 0 {
+		opts.States = DefaultStates
+	}
+	if opts.Garbage == 0 {
+		opts.Garbage = DefaultGarbage
+	}
+	return opts
+}
+
+// TestClient waits for a client to connect to the listener, acting as its host, and checks that it follows the
+// protocol. It returns an error describing the first problem found, or nil if the client conforms. The listener
+// is not closed, so a client which never connects leaves an Accept running until the caller closes it.
+func TestClient(l net.Listener, opts *Options) error {
+	o := opts.withDefaults()
+
+	conn, err := accept(l, o.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	p := newPeer(conn, o)
+	p.level = o.Settings.Level
+	err = p.send(netplay.Message{Type: netplay.TypeHello, Version: netplay.ProtocolVersion, Settings: &o.Settings})
+	if err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+	return p.play()
+}
+
+// TestHost connects to a host on the given network address, acting as its opponent, and checks that it follows the
+// protocol. It returns an error describing the first problem found, or nil if the host conforms.
+func TestHost(network, addr string, opts *Options) error {
+	o := opts.withDefaults()
+
+	conn, err := net.DialTimeout(network, addr, o.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %q: %w", addr, err)
+	}
+	defer conn.Close()
+
+	p := newPeer(conn, o)
+	msg, err := p.receive()
+	if err != nil {
+		return fmt.Errorf("failed to receive hello: %w", err)
+	}
+	if msg.Type != netplay.TypeHello {
+		return fmt.Errorf("expected %q message first, got %q", netplay.TypeHello, msg.Type)
+	}
+	if msg.Version != netplay.ProtocolVersion {
+		return fmt.Errorf("host uses protocol version %d, expected %d", msg.Version, netplay.ProtocolVersion)
+	}
+	p.level = msg.Settings.Level
+	return p.play()
+}
+
+func accept(l net.Listener, timeout time.Duration) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		conn, err := l.Accept()
+		results <- result{conn, err}
+	}()
+
+	select {
+	case r := <-results:
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to accept connection: %w", r.err)
+		}
+		return r.conn, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("no client connected within %v", timeout)
+	}
+}
+
+// peer is the tester's side of the game.
+type peer struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	opts   Options
+	level  uint // the starting level, which states may not be below
+
+	mu       sync.Mutex // guards writes and received
+	received uint       // lines of garbage received from the implementation
+}
+
+func newPeer(conn net.Conn, opts Options) *peer {
+	return &peer{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		opts:   opts,
+	}
+}
+
+func (p *peer) send(msg netplay.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.conn.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// receive reads the next message, checking it is a JSON object on its own line and a valid message.
+func (p *peer) receive() (netplay.Message, error) {
+	var msg netplay.Message
+	err := p.conn.SetReadDeadline(time.Now().Add(p.opts.Timeout))
+	if err != nil {
+		return msg, fmt.Errorf("failed to set read deadline: %w", err)
+	}
+	line, err := p.reader.ReadBytes('\n')
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return msg, fmt.Errorf("no message received within %v", p.opts.Timeout)
+	} else if err != nil {
+		return msg, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(line, &fields)
+	if err != nil {
+		return msg, fmt.Errorf("message %q is not a JSON object: %w", line, err)
+	}
+	if _, ok := fields["type"]; !ok {
+		return msg, fmt.Errorf("message %q has no type", line)
+	}
+	err = json.Unmarshal(line, &msg)
+	if err != nil {
+		return msg, fmt.Errorf("failed to decode message %q: %w", line, err)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return msg, fmt.Errorf("invalid message %q: %w", line, err)
+	}
+	return msg, nil
+}
+
+// play exchanges states with the implementation for the rest of the game. Once enough states have been received,
+// garbage is sent which the implementation must report in the received lines of a later state. The tester then tops
+// out, ending the game.
+func (p *peer) play() error {
+	stop := make(chan struct{})
+	defer close(stop)
+	sendErrs := make(chan error, 1)
+	go p.sendStates(stop, sendErrs)
+
+	var states int
+	var expected uint
+	var deadline time.Time
+	for {
+		select {
+		case err := <-sendErrs:
+			return err
+		default:
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("%d lines of garbage were not reported as received within %v", p.opts.Garbage, p.opts.Timeout)
+		}
+
+		msg, err := p.receive()
+		if err != nil {
+			return err
+		}
+		switch msg.Type {
+		case netplay.TypeHello:
+			return errors.New("unexpected hello message after the game started")
+		case netplay.TypeGameOver:
+			return errors.New("game over before the checks finished")
+		case netplay.TypeGarbage:
+			p.mu.Lock()
+			p.received += msg.Lines
+			p.mu.Unlock()
+			continue
+		}
+
+		if msg.Board.Level < p.level {
+			return fmt.Errorf("state has level %d, below the starting level %d", msg.Board.Level, p.level)
+		}
+		if deadline.IsZero() {
+			states++
+			if states < p.opts.States {
+				continue
+			}
+			expected = msg.Board.Received + p.opts.Garbage
+			err = p.send(netplay.Message{Type: netplay.TypeGarbage, Lines: p.opts.Garbage})
+			if err != nil {
+				return fmt.Errorf("failed to send garbage: %w", err)
+			}
+			deadline = time.Now().Add(p.opts.Timeout)
+		} else if msg.Board.Received >= expected {
+			err = p.send(netplay.Message{Type: netplay.TypeGameOver, Board: p.board()})
+			if err != nil {
+				return fmt.Errorf("failed to send game over: %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+// sendStates sends the tester's state every interval until stopped, or until sending fails.
+func (p *peer) sendStates(stop <-chan struct{}, errs chan<- error) {
+	ticker := time.NewTicker(stateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			err := p.send(netplay.Message{Type: netplay.TypeState, Board: p.board()})
+			if err != nil {
+				errs <- fmt.Errorf("failed to send state: %w", err)
+				return
+			}
+		}
+	}
+}
+
+// board is the tester's state: an empty matrix, at the starting level, with the garbage it has received.
+func (p *peer) board() *netplay.Board {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &netplay.Board{Level: max(p.level, 1), Received: p.received}
+}
@@ -0,0 +1,173 @@
+package conformance
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/netplay"
+)
+
+// implementation plays one side of a game on the connection until it is closed, like a client or host would.
+type implementation struct {
+	level         uint
+	reportGarbage bool   // whether received garbage is reported in states
+	invalid       string // a line sent before the first state, if not empty
+}
+
+// play takes the raw connection as well as the netplay connection wrapping it, so invalid lines can be written.
+func (impl implementation) play(conn net.Conn, c *netplay.Conn) {
+	defer c.Close()
+	if impl.invalid != "" {
+		_, _ = conn.Write([]byte(impl.invalid + "\n"))
+	}
+
+	var mu sync.Mutex
+	var received uint
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			msg, err := c.Receive()
+			if err != nil || msg.Type == netplay.TypeGameOver {
+				return
+			}
+			if msg.Type == netplay.TypeGarbage && impl.reportGarbage {
+				mu.Lock()
+				received += msg.Lines
+				mu.Unlock()
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+		mu.Lock()
+		board := &netplay.Board{Level: impl.level, Received: received, Queue: "IOT"}
+		mu.Unlock()
+		if c.Send(netplay.Message{Type: netplay.TypeState, Board: board}) != nil {
+			return
+		}
+	}
+}
+
+func TestTestClient(t *testing.T) {
+	tt := []struct {
+		name        string
+		impl        implementation
+		expectedErr string
+	}{
+		{"conforming", implementation{level: 3, reportGarbage: true}, ""},
+		{"garbage not reported", implementation{level: 3}, "not reported"},
+		{"below starting level", implementation{level: 1, reportGarbage: true}, "below the starting level"},
+		{"unknown type", implementation{level: 3, reportGarbage: true, invalid: `{"type":"emote"}`}, "unknown message type"},
+		{"not JSON", implementation{level: 3, reportGarbage: true, invalid: "hello"}, "not a JSON object"},
+		{"no type", implementation{level: 3, reportGarbage: true, invalid: `{"lines":2}`}, "has no type"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			defer l.Close()
+
+			go func() {
+				conn, err := net.Dial("tcp", l.Addr().String())
+				if err != nil {
+					return
+				}
+				c := netplay.NewConn(conn)
+				hello, err := c.Receive()
+				if err != nil || hello.Settings == nil || hello.Settings.Level != 3 {
+					c.Close()
+					return
+				}
+				tc.impl.play(conn, c)
+			}()
+
+			err = TestClient(l, &Options{Settings: netplay.Settings{Level: 3}, Timeout: 500 * time.Millisecond})
+			checkErr(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestTestHost(t *testing.T) {
+	tt := []struct {
+		name        string
+		impl        implementation
+		expectedErr string
+	}{
+		{"conforming", implementation{level: 2, reportGarbage: true}, ""},
+		{"garbage not reported", implementation{level: 2}, "not reported"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			defer l.Close()
+
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				c := netplay.NewConn(conn)
+				settings := netplay.Settings{Seed: 1, Level: 2}
+				if c.Send(netplay.Message{Type: netplay.TypeHello, Version: netplay.ProtocolVersion, Settings: &settings}) != nil {
+					c.Close()
+					return
+				}
+				tc.impl.play(conn, c)
+			}()
+
+			err = TestHost("tcp", l.Addr().String(), &Options{Timeout: 500 * time.Millisecond})
+			checkErr(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestTestHost_NoHello(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		implementation{level: 1, reportGarbage: true}.play(conn, netplay.NewConn(conn))
+	}()
+
+	err = TestHost("tcp", l.Addr().String(), &Options{Timeout: 500 * time.Millisecond})
+	checkErr(t, err, "expected \"hello\" message first")
+}
+
+func checkErr(t *testing.T, err error, expected string) {
+	t.Helper()
+	if expected == "" {
+		if err != nil {
+			t.Errorf("expected nil, got error: %v", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("expected an error containing %q, got nil", expected)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected an error containing %q, got %v", expected, err)
+	}
+}
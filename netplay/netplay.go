@@ -0,0 +1,235 @@
+// Package netplay implements the protocol used by two tetrigo instances to play versus over a network.
+//
+// Messages are JSON objects, one per line. The host sends a hello message containing the game settings as soon as
+// the opponent connects, after which both sides send state, garbage and game over messages as they play. Once the
+// game is over, both sides stay connected to chat with preset messages and send rematch messages to play again: the
+// host starts the next game by sending the settings for it, with a fresh seed, once both have asked for a rematch.
+// Spectators use the same protocol, but only receive the hello, state and game over messages.
+//
+// Three or more players play free-for-alls through a room server instead, which relays their messages as described
+// with TypeCreate.
+//
+// The package is public so that other clients and bots can play against tetrigo. They can check that they speak
+// the protocol correctly with the conformance package.
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// ProtocolVersion is incremented whenever a change would stop older versions from understanding messages.
+const ProtocolVersion = 3
+
+type MessageType string
+
+const (
+	TypeHello    MessageType = "hello"     // sent by the host with the game settings
+	TypeState    MessageType = "state"     // the sender's current board
+	TypeGarbage  MessageType = "garbage"   // lines of garbage sent to the receiver
+	TypeGameOver MessageType = "game_over" // the sender topped out, with their final board
+	TypeRematch  MessageType = "rematch"   // the sender wants to play again, with the next game's settings from the host
+	TypeChat     MessageType = "chat"      // one of the ChatPresets, sent between games
+)
+
+type Message struct {
+	Type     MessageType `json:"type"`
+	Version  int         `json:"version,omitempty"`
+	Settings *Settings   `json:"settings,omitempty"`
+	Board    *Board      `json:"board,omitempty"`
+	Lines    uint        `json:"lines,omitempty"`
+	Chat     string      `json:"chat,omitempty"` // ID of the chat preset
+
+	// Rooms use these too. Player is the ID of the player a message relayed by the room server is from.
+	Code   string `json:"code,omitempty"`
+	Room   *Room  `json:"room,omitempty"`
+	Player int    `json:"player,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Settings are chosen by the host and shared so both players get the same game.
+type Settings struct {
+	Seed      int64 `json:"seed"`
+	Level     uint  `json:"level"`
+	Countdown uint  `json:"countdown,omitempty"`
+	BestOf    uint  `json:"best_of,omitempty"` // games in the match, won by the first to win more than half (0 for 1)
+
+	// Targeting is who a room server sends each attack to in free-for-alls (TargetRandom if empty).
+	Targeting string `json:"targeting,omitempty"`
+
+	// Attack is the lines sent for each kind of line clear (nil for the guideline's table).
+	Attack *tetris.AttackTable `json:"attack,omitempty"`
+	// Host and Guest are the handicaps of the host and of the player who joins.
+	Host  Handicap `json:"host_handicap"`
+	Guest Handicap `json:"guest_handicap"`
+}
+
+// WinsNeeded returns the games a player has to win to win the match.
+func (s Settings) WinsNeeded() uint {
+	return s.BestOf/2 + 1
+}
+
+// ChatPreset is a message players can send each other between games. Only the ID is sent, so that clients can show
+// the text in their own language, and players can't send anything that would need moderating.
+type ChatPreset struct {
+	ID   string
+	Text string
+}
+
+// ChatPresets are the messages which can be chatted, in the order they are offered.
+var ChatPresets = []ChatPreset{
+	{ID: "gg", Text: "Good game!"},
+	{ID: "wp", Text: "Well played!"},
+	{ID: "again", Text: "One more?"},
+	{ID: "close", Text: "That was close!"},
+}
+
+// ChatText returns the text of the chat preset with the ID, or false if there is none. Newer versions may add
+// presets, so chat messages with those which are unknown are valid, and should be ignored.
+func ChatText(id string) (string, bool) {
+	for _, p := range ChatPresets {
+		if p.ID == id {
+			return p.Text, true
+		}
+	}
+	return "", false
+}
+
+// Handicap evens out a match between players of different skill, by weakening a player's attack or having them start
+// with garbage.
+type Handicap struct {
+	Multiplier float64 `json:"multiplier,omitempty"` // multiplies the lines of garbage the player sends (0 for 1)
+	Garbage    uint    `json:"garbage,omitempty"`    // rows of garbage the player starts with
+}
+
+// Board is a snapshot of a player's game.
+type Board struct {
+	Matrix   tetris.Matrix `json:"matrix"`
+	Score    uint          `json:"score"`
+	Lines    uint          `json:"lines"`
+	Level    uint          `json:"level"`
+	Attack   uint          `json:"attack"`
+	Received uint          `json:"received"`
+	Time     time.Duration `json:"time"`
+	Hold     string        `json:"hold,omitempty"`  // values of the held tetriminos, with the one swapped in next first
+	Queue    string        `json:"queue,omitempty"` // values of the upcoming tetriminos, in order
+}
+
+// Conn is a connection to an opponent. It is safe to send from multiple goroutines.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+// Send writes a message to the opponent.
+func (c *Conn) Send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.conn.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// Receive blocks until the next message from the opponent is read.
+func (c *Conn) Receive() (Message, error) {
+	var msg Message
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return msg, fmt.Errorf("failed to read message: %w", err)
+	}
+	err = json.Unmarshal(line, &msg)
+	if err != nil {
+		return msg, fmt.Errorf("failed to decode message: %w", err)
+	}
+	return msg, nil
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Host waits for an opponent to connect on the given address and sends them the game settings.
+func Host(addr string, settings Settings) (*Conn, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %q: %w", addr, err)
+	}
+	defer l.Close()
+	return Accept(l, settings)
+}
+
+// Accept waits for an opponent to connect to the listener and sends them the game settings.
+func Accept(l net.Listener, settings Settings) (*Conn, error) {
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept connection: %w", err)
+	}
+
+	c := NewConn(conn)
+	err = c.Send(Message{Type: TypeHello, Version: ProtocolVersion, Settings: &settings})
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to send hello: %w", err)
+	}
+	return c, nil
+}
+
+// Join connects to a host on the given TCP address and returns the game settings it sent.
+func Join(addr string) (*Conn, *Settings, error) {
+	return Dial("tcp", addr)
+}
+
+// Dial connects to a host on the given network address and returns the game settings it sent.
+func Dial(network, addr string) (*Conn, *Settings, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %q: %w", addr, err)
+	}
+
+	c := NewConn(conn)
+	settings, err := c.receiveHello()
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	return c, settings, nil
+}
+
+func (c *Conn) receiveHello() (*Settings, error) {
+	msg, err := c.Receive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive hello: %w", err)
+	}
+	if msg.Type != TypeHello {
+		return nil, fmt.Errorf("expected %q message, got %q", TypeHello, msg.Type)
+	}
+	if msg.Version != ProtocolVersion {
+		return nil, fmt.Errorf("host uses protocol version %d, expected %d", msg.Version, ProtocolVersion)
+	}
+	if msg.Settings == nil {
+		return nil, errors.New("hello message is missing settings")
+	}
+	return msg.Settings, nil
+}
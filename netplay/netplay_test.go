@@ -0,0 +1,121 @@
+package netplay
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestConn_SendReceive(t *testing.T) {
+	tt := []struct {
+		name string
+		msg  Message
+	}{
+		{
+			"garbage",
+			Message{Type: TypeGarbage, Lines: 4},
+		},
+		{
+			"state",
+			Message{Type: TypeState, Board: &Board{Score: 1200, Lines: 8, Level: 2}},
+		},
+		{
+			"rematch",
+			Message{Type: TypeRematch, Settings: &Settings{Seed: 99, Level: 3, BestOf: 5}},
+		},
+		{
+			"chat",
+			Message{Type: TypeChat, Chat: "gg"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := net.Pipe()
+			sender, receiver := NewConn(a), NewConn(b)
+			defer sender.Close()
+			defer receiver.Close()
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- sender.Send(tc.msg)
+			}()
+
+			msg, err := receiver.Receive()
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if err = <-errs; err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !reflect.DeepEqual(msg, tc.msg) {
+				t.Errorf("Message: want %+v, got %+v", tc.msg, msg)
+			}
+		})
+	}
+}
+
+func TestHostJoin(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	settings := Settings{Seed: 1234, Level: 5}
+	hosts := make(chan *Conn, 1)
+	go func() {
+		conn, err := Accept(l, settings)
+		if err != nil {
+			t.Errorf("Accept: expected nil, got error: %v", err)
+		}
+		hosts <- conn
+	}()
+
+	conn, received, err := Join(l.Addr().String())
+	if err != nil {
+		t.Fatalf("Join: expected nil, got error: %v", err)
+	}
+	defer conn.Close()
+	if host := <-hosts; host != nil {
+		defer host.Close()
+	}
+
+	if *received != settings {
+		t.Errorf("Settings: want %+v, got %+v", settings, *received)
+	}
+}
+
+func TestJoin_VersionMismatch(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		c := NewConn(conn)
+		defer c.Close()
+		_ = c.Send(Message{Type: TypeHello, Version: ProtocolVersion + 1, Settings: &Settings{}})
+	}()
+
+	conn, _, err := Join(l.Addr().String())
+	if err == nil {
+		conn.Close()
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestSettings_WinsNeeded(t *testing.T) {
+	tt := map[uint]uint{0: 1, 1: 1, 3: 2, 5: 3, 7: 4}
+
+	for bestOf, want := range tt {
+		if got := (Settings{BestOf: bestOf}).WinsNeeded(); got != want {
+			t.Errorf("best of %d: want %d wins, got %d", bestOf, want, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package netplay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// A room server hosts free-for-alls between any number of players. A player creates a room with the settings of its
+// games, and the server replies with a room message giving its code, which others join it with. Room messages are
+// sent to everyone in the room whenever someone joins or leaves. Once the room's owner sends a start message, the
+// server sends everyone a hello with a fresh seed, as a host would.
+//
+// During a game, each player sends state, garbage and game over messages to the server as they would to an opponent.
+// States and game overs are relayed to everyone else, with the player they are from, and garbage is sent on to a
+// single opponent, chosen by the room's targeting rules. The last player left wins, after which the owner can start
+// the next game.
+const (
+	TypeCreate MessageType = "create" // asks a room server for a new room, with the settings of its games
+	TypeJoin   MessageType = "join"   // asks a room server to join the room with the code
+	TypeRoom   MessageType = "room"   // the players in the room, sent by the server whenever they change
+	TypeStart  MessageType = "start"  // asks the server to start a game, sent by the room's owner
+	TypeError  MessageType = "error"  // the server refused a request, with the reason
+)
+
+// The rules a room server can choose the target of each attack by.
+const (
+	TargetRandom   = "random"   // a random opponent
+	TargetAttacker = "attacker" // an opponent attacking the sender, or a random one if none are
+	TargetBadges   = "badges"   // the opponent with the most badges, or a random one of them if they are tied
+)
+
+// Targetings are the names of the targeting rules.
+var Targetings = []string{TargetRandom, TargetAttacker, TargetBadges}
+
+// Knocking out an opponent, by being the last to attack them before they top out, earns a badge. Each badge increases
+// the garbage sent by BadgeBonus, up to MaxBadges.
+const (
+	BadgeBonus = 0.25
+	MaxBadges  = 4
+)
+
+// Room is a room on a room server.
+type Room struct {
+	Code    string `json:"code"`
+	Players []int  `json:"players"` // IDs of the players in the room, in the order they joined
+	Owner   int    `json:"owner"`   // ID of the player who starts games, the first of those still in the room
+	You     int    `json:"you"`     // ID of the player the message is sent to
+}
+
+// Opponents returns the IDs of everyone in the room other than the player the room was sent to.
+func (r *Room) Opponents() []int {
+	var opponents []int
+	for _, id := range r.Players {
+		if id != r.You {
+			opponents = append(opponents, id)
+		}
+	}
+	return opponents
+}
+
+// CreateRoom connects to a room server on the given TCP address and creates a room whose games are played with the
+// settings, returning the room with its code.
+func CreateRoom(addr string, settings Settings) (*Conn, *Room, error) {
+	return enterRoom(addr, Message{Type: TypeCreate, Settings: &settings})
+}
+
+// JoinRoom connects to a room server on the given TCP address and joins the room with the code.
+func JoinRoom(addr, code string) (*Conn, *Room, error) {
+	return enterRoom(addr, Message{Type: TypeJoin, Code: strings.ToUpper(code)})
+}
+
+func enterRoom(addr string, request Message) (*Conn, *Room, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %q: %w", addr, err)
+	}
+
+	c := NewConn(conn)
+	room, err := c.requestRoom(request)
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	return c, room, nil
+}
+
+func (c *Conn) requestRoom(request Message) (*Room, error) {
+	err := c.Send(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", request.Type, err)
+	}
+	msg, err := c.Receive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive room: %w", err)
+	}
+	switch {
+	case msg.Type == TypeError:
+		return nil, fmt.Errorf("room server refused: %s", msg.Error)
+	case msg.Type != TypeRoom:
+		return nil, fmt.Errorf("expected %q message, got %q", TypeRoom, msg.Type)
+	case msg.Room == nil:
+		return nil, errors.New("room message is missing the room")
+	}
+	return msg.Room, nil
+}
@@ -0,0 +1,183 @@
+package netplay
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+// Validate reports whether the message is well formed: its type is known and it has the fields its type requires.
+// Receive does not validate messages, so that newer versions can add to them, but implementations should only send
+// messages which pass.
+func (m Message) Validate() error {
+	switch m.Type {
+	case TypeHello:
+		if m.Version <= 0 {
+			return errors.New("hello message is missing the protocol version")
+		}
+		if m.Settings == nil {
+			return errors.New("hello message is missing settings")
+		}
+		return m.Settings.Validate()
+	case TypeState, TypeGameOver:
+		if m.Board == nil {
+			return fmt.Errorf("%s message is missing the board", m.Type)
+		}
+		return m.Board.Validate()
+	case TypeGarbage:
+		if m.Lines == 0 {
+			return errors.New("garbage message has no lines")
+		}
+		if m.Lines > MaxGarbage {
+			return fmt.Errorf("invalid garbage %d: must be at most %d", m.Lines, MaxGarbage)
+		}
+		return nil
+	case TypeRematch:
+		if m.Settings == nil {
+			return nil
+		}
+		return m.Settings.Validate()
+	case TypeChat:
+		// Presets this version doesn't know are allowed, since newer versions may add them.
+		if m.Chat == "" {
+			return errors.New("chat message is missing the preset")
+		}
+		return nil
+	case TypeCreate:
+		if m.Settings == nil {
+			return errors.New("create message is missing settings")
+		}
+		return m.Settings.Validate()
+	case TypeJoin:
+		if m.Code == "" {
+			return errors.New("join message is missing the room code")
+		}
+		return nil
+	case TypeRoom:
+		if m.Room == nil {
+			return errors.New("room message is missing the room")
+		}
+		return nil
+	case TypeStart:
+		return nil
+	case TypeError:
+		if m.Error == "" {
+			return errors.New("error message is missing the reason")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown message type %q", m.Type)
+}
+
+func (s Settings) Validate() error {
+	if s.Level < 1 {
+		return fmt.Errorf("invalid level %d: must be at least 1", s.Level)
+	}
+	if s.BestOf%2 == 0 && s.BestOf != 0 {
+		return fmt.Errorf("invalid best of %d: must be odd, so that the match can't be drawn", s.BestOf)
+	}
+	if s.Targeting != "" && !slices.Contains(Targetings, s.Targeting) {
+		return fmt.Errorf("invalid targeting %q: must be one of %s", s.Targeting, strings.Join(Targetings, ", "))
+	}
+	if s.Attack != nil {
+		err := validateAttack(*s.Attack)
+		if err != nil {
+			return fmt.Errorf("invalid attack table: %w", err)
+		}
+	}
+	err := s.Host.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid host handicap: %w", err)
+	}
+	err = s.Guest.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid guest handicap: %w", err)
+	}
+	return nil
+}
+
+// The limits of a handicap. A player starting with more garbage would have too little room to place tetriminos.
+const (
+	MaxMultiplier      = 4
+	MaxStartingGarbage = 15
+)
+
+// MaxAttack is the most lines the attack table can have a single line clear send, which is enough to fill the tallest
+// matrix.
+const MaxAttack = 40
+
+// MaxGarbage is the most lines a garbage message can carry: a clear sending MaxAttack lines, as many again for
+// continuing a back-to-back chain and a few for the combo, at MaxMultiplier and with a room's most badges.
+const MaxGarbage uint = (2*MaxAttack + 6) * MaxMultiplier * (1 + BadgeBonus*MaxBadges)
+
+func validateAttack(t tetris.AttackTable) error {
+	entries := []struct {
+		name  string
+		lines uint
+	}{
+		{"single", t.Single},
+		{"double", t.Double},
+		{"triple", t.Triple},
+		{"tetris", t.Tetris},
+		{"mini T-spin single", t.MiniTSpinSingle},
+		{"T-spin single", t.TSpinSingle},
+		{"T-spin double", t.TSpinDouble},
+		{"T-spin triple", t.TSpinTriple},
+		{"back-to-back", t.BackToBack},
+	}
+	for _, e := range entries {
+		if e.lines > MaxAttack {
+			return fmt.Errorf("invalid %s %d: must be at most %d", e.name, e.lines, MaxAttack)
+		}
+	}
+	return nil
+}
+
+func (h Handicap) Validate() error {
+	if h.Multiplier < 0 || h.Multiplier > MaxMultiplier {
+		return fmt.Errorf("invalid multiplier %v: must be between 0 and %d", h.Multiplier, MaxMultiplier)
+	}
+	if h.Garbage > MaxStartingGarbage {
+		return fmt.Errorf("invalid garbage %d: must be at most %d", h.Garbage, MaxStartingGarbage)
+	}
+	return nil
+}
+
+func (b Board) Validate() error {
+	if b.Level < 1 {
+		return fmt.Errorf("invalid level %d: must be at least 1", b.Level)
+	}
+	for row := range b.Matrix {
+		for col, cell := range b.Matrix[row] {
+			if cell != 0 && cell != tetris.GarbageValue && !isTetrimino(cell) {
+				return fmt.Errorf("invalid cell %q at row %d, col %d", cell, row, col)
+			}
+		}
+	}
+	if len(b.Hold) > tetris.MaxHoldSlots {
+		return fmt.Errorf("invalid hold %q: must be at most %d tetriminos", b.Hold, tetris.MaxHoldSlots)
+	}
+	for _, value := range []byte(b.Hold) {
+		if !isTetrimino(value) {
+			return fmt.Errorf("invalid hold %q: must be the values of tetriminos", b.Hold)
+		}
+	}
+	for _, value := range []byte(b.Queue) {
+		if !isTetrimino(value) {
+			return fmt.Errorf("invalid queue %q: must be the values of tetriminos", b.Queue)
+		}
+	}
+	return nil
+}
+
+func isTetrimino(value byte) bool {
+	for _, t := range tetris.Tetriminos {
+		if t.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package netplay
+
+import (
+	"testing"
+
+	"github.com/Broderick-Westrope/tetrigo/tetris"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	matrix := tetris.NewDefaultMatrix()
+	copy(matrix[39], []byte{'X', 'X', 'T', 'T', 'T', 0, 'X'})
+	badMatrix := tetris.NewDefaultMatrix()
+	badMatrix[39][0] = 'G'
+
+	tt := []struct {
+		name       string
+		msg        Message
+		expectsErr bool
+	}{
+		{"hello", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1}}, false},
+		{"hello without version", Message{Type: TypeHello, Settings: &Settings{Level: 1}}, true},
+		{"hello without settings", Message{Type: TypeHello, Version: ProtocolVersion}, true},
+		{"hello at level 0", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{}}, true},
+		{"hello with handicaps", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, Host: Handicap{Multiplier: 0.5, Garbage: 4}, Guest: Handicap{Multiplier: 1.5}}}, false},
+		{"hello with invalid multiplier", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, Host: Handicap{Multiplier: -1}}}, true},
+		{"hello with an attack table", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, Attack: &tetris.AttackTable{Tetris: 6, BackToBack: 2}}}, false},
+		{"hello with too much attack", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, Attack: &tetris.AttackTable{TSpinTriple: 41}}}, true},
+		{"hello with too much garbage", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, Guest: Handicap{Garbage: 16}}}, true},
+		{"state", Message{Type: TypeState, Board: &Board{Matrix: matrix, Level: 3, Hold: "I", Queue: "OJLSTZ"}}, false},
+		{"state without board", Message{Type: TypeState}, true},
+		{"state with invalid cell", Message{Type: TypeState, Board: &Board{Matrix: badMatrix, Level: 1}}, true},
+		{"state with several held", Message{Type: TypeState, Board: &Board{Level: 1, Hold: "IO"}}, false},
+		{"state with invalid hold", Message{Type: TypeState, Board: &Board{Level: 1, Hold: "IX"}}, true},
+		{"state with too many held", Message{Type: TypeState, Board: &Board{Level: 1, Hold: "IOTSZ"}}, true},
+		{"state with invalid queue", Message{Type: TypeState, Board: &Board{Level: 1, Queue: "IOX"}}, true},
+		{"game over", Message{Type: TypeGameOver, Board: &Board{Level: 1}}, false},
+		{"game over without board", Message{Type: TypeGameOver}, true},
+		{"garbage", Message{Type: TypeGarbage, Lines: 2}, false},
+		{"garbage without lines", Message{Type: TypeGarbage}, true},
+		{"garbage at the limit", Message{Type: TypeGarbage, Lines: MaxGarbage}, false},
+		{"too much garbage", Message{Type: TypeGarbage, Lines: 1 << 62}, true},
+		{"hello best of 3", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, BestOf: 3}}, false},
+		{"hello best of 2", Message{Type: TypeHello, Version: ProtocolVersion, Settings: &Settings{Level: 1, BestOf: 2}}, true},
+		{"rematch", Message{Type: TypeRematch}, false},
+		{"rematch with settings", Message{Type: TypeRematch, Settings: &Settings{Seed: 5, Level: 1}}, false},
+		{"rematch with invalid settings", Message{Type: TypeRematch, Settings: &Settings{}}, true},
+		{"chat", Message{Type: TypeChat, Chat: "gg"}, false},
+		{"chat with unknown preset", Message{Type: TypeChat, Chat: "brb"}, false},
+		{"chat without preset", Message{Type: TypeChat}, true},
+		{"create", Message{Type: TypeCreate, Settings: &Settings{Level: 1, Targeting: TargetBadges}}, false},
+		{"create without settings", Message{Type: TypeCreate}, true},
+		{"create with unknown targeting", Message{Type: TypeCreate, Settings: &Settings{Level: 1, Targeting: "nearest"}}, true},
+		{"join", Message{Type: TypeJoin, Code: "ABCD"}, false},
+		{"join without code", Message{Type: TypeJoin}, true},
+		{"room", Message{Type: TypeRoom, Room: &Room{Code: "ABCD", Players: []int{1, 2}, Owner: 1, You: 2}}, false},
+		{"room without room", Message{Type: TypeRoom}, true},
+		{"start", Message{Type: TypeStart}, false},
+		{"error", Message{Type: TypeError, Error: "room is full"}, false},
+		{"error without reason", Message{Type: TypeError}, true},
+		{"unknown type", Message{Type: "emote"}, true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.msg.Validate()
+			if tc.expectsErr && err == nil {
+				t.Error("expected an error, got nil")
+			} else if !tc.expectsErr && err != nil {
+				t.Errorf("expected nil, got error: %v", err)
+			}
+		})
+	}
+}
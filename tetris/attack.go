@@ -0,0 +1,199 @@
+package tetris
+
+// Attack tracks the garbage lines sent to and received from opponents in versus games.
+type Attack struct {
+	sent       uint
+	received   uint
+	combo      int
+	backToBack bool
+	chain      int // number of back-to-back clears in the current chain
+
+	table      AttackTable
+	multiplier float64
+	remainder  float64 // the fraction of a line left over once the multiplier has been applied
+}
+
+// AttackTable is the number of lines sent for each kind of line clear before the combo bonus, which is the same for
+// every table. It is shared with a remote opponent, so that both sides play by the same table.
+type AttackTable struct {
+	Single          uint `json:"single"`
+	Double          uint `json:"double"`
+	Triple          uint `json:"triple"`
+	Tetris          uint `json:"tetris"`
+	MiniTSpinSingle uint `json:"mini_tspin_single"`
+	TSpinSingle     uint `json:"tspin_single"`
+	TSpinDouble     uint `json:"tspin_double"`
+	TSpinTriple     uint `json:"tspin_triple"`
+	BackToBack      uint `json:"back_to_back"` // extra lines for each difficult clear continuing a back-to-back chain
+}
+
+// DefaultAttackTable returns the table given by the guideline.
+func DefaultAttackTable() AttackTable {
+	return AttackTable{
+		Double:      1,
+		Triple:      2,
+		Tetris:      4,
+		TSpinSingle: 2,
+		TSpinDouble: 4,
+		TSpinTriple: 6,
+		BackToBack:  1,
+	}
+}
+
+// lines returns the lines sent for the action before any bonuses.
+func (t AttackTable) lines(act action) uint {
+	switch act {
+	case actionSingle:
+		return t.Single
+	case actionDouble:
+		return t.Double
+	case actionTriple:
+		return t.Triple
+	case actionTetris:
+		return t.Tetris
+	case actionMiniTSpinSingle:
+		return t.MiniTSpinSingle
+	case actionTSpinSingle:
+		return t.TSpinSingle
+	case actionTSpinDouble:
+		return t.TSpinDouble
+	case actionTSpinTriple:
+		return t.TSpinTriple
+	}
+	return 0
+}
+
+// lineEpsilon allows for rounding errors when fractions of a line add up to a whole one.
+const lineEpsilon = 1e-9
+
+// Extra lines sent for consecutive line clears, indexed by the number of clears since the combo started.
+var comboTable = []uint{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 4, 5}
+
+// NewAttack creates an attack sending lines by the guideline's table.
+func NewAttack() *Attack {
+	return NewCustomAttack(DefaultAttackTable(), 1)
+}
+
+// NewCustomAttack creates an attack sending lines by the table, with the lines sent by each clear (bonuses included)
+// multiplied by the multiplier, such as 0.5 to handicap a stronger player. Fractions of a line are carried over to the
+// next clear, so that with a multiplier of 0.5 two doubles send one line between them.
+func NewCustomAttack(table AttackTable, multiplier float64) *Attack {
+	return &Attack{
+		table:      table,
+		multiplier: multiplier,
+	}
+}
+
+// AttackState is the state of an attack, from which it can be restored.
+type AttackState struct {
+	Sent       uint `json:"sent"`
+	Received   uint `json:"received"`
+	Combo      int  `json:"combo"`
+	BackToBack bool `json:"back_to_back,omitempty"`
+	Chain      int  `json:"chain,omitempty"`
+
+	Remainder float64 `json:"remainder,omitempty"`
+}
+
+// State returns the state of the attack.
+func (a *Attack) State() AttackState {
+	return AttackState{
+		Sent:       a.sent,
+		Received:   a.received,
+		Combo:      a.combo,
+		BackToBack: a.backToBack,
+		Chain:      a.chain,
+		Remainder:  a.remainder,
+	}
+}
+
+// RestoreAttack creates an attack in the given state, sending lines by the guideline's table.
+func RestoreAttack(s AttackState) *Attack {
+	a := NewAttack()
+	a.sent = s.Sent
+	a.received = s.Received
+	a.combo = s.Combo
+	a.backToBack = s.BackToBack
+	a.chain = s.Chain
+	a.remainder = s.Remainder
+	return a
+}
+
+// Sent returns the total number of lines sent.
+func (a *Attack) Sent() uint {
+	return a.sent
+}
+
+// Received returns the total number of lines received.
+func (a *Attack) Received() uint {
+	return a.received
+}
+
+// Combo returns the number of consecutive tetriminos that have cleared lines.
+func (a *Attack) Combo() int {
+	return a.combo
+}
+
+// BackToBack returns the number of consecutive back-to-back clears, or 0 if there is no chain.
+func (a *Attack) BackToBack() int {
+	return a.chain
+}
+
+// Receive records lines of garbage received from an opponent.
+func (a *Attack) Receive(lines uint) {
+	a.received += lines
+}
+
+// ProcessAction records the result of a tetrimino locking down and returns the number of lines sent.
+func (a *Attack) ProcessAction(act action) uint {
+	if !clearsLines(act) {
+		a.combo = 0
+		return 0
+	}
+
+	lines := a.table.lines(act)
+
+	if isDifficult(act) {
+		if a.backToBack {
+			lines += a.table.BackToBack
+			a.chain++
+		}
+		a.backToBack = true
+	} else {
+		a.backToBack = false
+		a.chain = 0
+	}
+
+	a.combo++
+	if a.combo < len(comboTable) {
+		lines += comboTable[a.combo]
+	} else {
+		lines += comboTable[len(comboTable)-1]
+	}
+
+	if a.multiplier != 1 {
+		scaled := float64(lines)*a.multiplier + a.remainder
+		lines = uint(scaled + lineEpsilon)
+		a.remainder = max(scaled-float64(lines), 0)
+	}
+
+	a.sent += lines
+	return lines
+}
+
+func clearsLines(act action) bool {
+	switch act {
+	case actionNone, actionMiniTSpin, actionTSpin:
+		return false
+	}
+	return true
+}
+
+// isDifficult reports whether the action is a difficult line clear, which continues a back-to-back chain.
+func isDifficult(act action) bool {
+	switch act {
+	case actionTetris, actionMiniTSpinSingle, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package tetris
+
+import (
+	"testing"
+)
+
+func TestAttack_ProcessAction(t *testing.T) {
+	tt := []struct {
+		name             string
+		actions          []action
+		expectedLines    uint
+		expectedSent     uint
+		expectedCombo    int
+		expectBackToBack bool
+	}{
+		{
+			name:          "single",
+			actions:       []action{actionSingle},
+			expectedLines: 0,
+			expectedSent:  0,
+			expectedCombo: 1,
+		},
+		{
+			name:             "tetris",
+			actions:          []action{actionTetris},
+			expectedLines:    4,
+			expectedSent:     4,
+			expectedCombo:    1,
+			expectBackToBack: true,
+		},
+		{
+			name:             "back-to-back tetris",
+			actions:          []action{actionTetris, actionNone, actionTetris},
+			expectedLines:    5,
+			expectedSent:     9,
+			expectedCombo:    1,
+			expectBackToBack: true,
+		},
+		{
+			name:          "back-to-back broken by double",
+			actions:       []action{actionTetris, actionNone, actionDouble},
+			expectedLines: 1,
+			expectedSent:  5,
+			expectedCombo: 1,
+		},
+		{
+			name:          "combo of doubles",
+			actions:       []action{actionDouble, actionDouble, actionDouble},
+			expectedLines: 2,
+			expectedSent:  5,
+			expectedCombo: 3,
+		},
+		{
+			name:          "combo reset",
+			actions:       []action{actionDouble, actionDouble, actionNone},
+			expectedLines: 0,
+			expectedSent:  3,
+			expectedCombo: 0,
+		},
+		{
+			name:             "T-spin without lines keeps back-to-back",
+			actions:          []action{actionTSpinDouble, actionTSpin, actionTSpinSingle},
+			expectedLines:    3,
+			expectedSent:     7,
+			expectedCombo:    1,
+			expectBackToBack: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewAttack()
+
+			var lines uint
+			for _, act := range tc.actions {
+				lines = a.ProcessAction(act)
+			}
+
+			if lines != tc.expectedLines {
+				t.Errorf("Lines: expected %d, got %d", tc.expectedLines, lines)
+			}
+			if a.Sent() != tc.expectedSent {
+				t.Errorf("Sent: expected %d, got %d", tc.expectedSent, a.Sent())
+			}
+			if a.combo != tc.expectedCombo {
+				t.Errorf("Combo: expected %d, got %d", tc.expectedCombo, a.combo)
+			}
+			if a.backToBack != tc.expectBackToBack {
+				t.Errorf("BackToBack: expected %v, got %v", tc.expectBackToBack, a.backToBack)
+			}
+		})
+	}
+}
+
+func TestAttack_BackToBack(t *testing.T) {
+	tt := []struct {
+		name     string
+		actions  []action
+		expected int
+	}{
+		{"single tetris", []action{actionTetris}, 0},
+		{"two tetrises", []action{actionTetris, actionTetris}, 1},
+		{"broken by single", []action{actionTetris, actionTetris, actionSingle}, 0},
+		{"kept between locks", []action{actionTetris, actionNone, actionTSpinDouble, actionTetris}, 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewAttack()
+			for _, act := range tc.actions {
+				a.ProcessAction(act)
+			}
+
+			if a.BackToBack() != tc.expected {
+				t.Errorf("BackToBack: expected %d, got %d", tc.expected, a.BackToBack())
+			}
+		})
+	}
+}
+
+func TestAttack_Receive(t *testing.T) {
+	a := NewAttack()
+	a.Receive(3)
+	a.Receive(2)
+
+	if a.Received() != 5 {
+		t.Errorf("Received: expected 5, got %d", a.Received())
+	}
+}
+
+func TestRestoreAttack(t *testing.T) {
+	a := NewAttack()
+	for _, act := range []action{actionTetris, actionTSpinDouble, actionSingle} {
+		a.ProcessAction(act)
+	}
+	a.Receive(3)
+
+	restored := RestoreAttack(a.State())
+	if *restored != *a {
+		t.Errorf("Attack: want %+v, got %+v", *a, *restored)
+	}
+}
+
+func TestNewCustomAttack(t *testing.T) {
+	tetrisOnly := AttackTable{Tetris: 4, BackToBack: 2}
+
+	tt := []struct {
+		name         string
+		table        AttackTable
+		multiplier   float64
+		actions      []action
+		expectedSent uint
+	}{
+		{
+			name:         "custom table",
+			table:        tetrisOnly,
+			multiplier:   1,
+			actions:      []action{actionDouble, actionNone, actionTetris, actionNone, actionTetris},
+			expectedSent: 10,
+		},
+		{
+			name:         "halved",
+			table:        DefaultAttackTable(),
+			multiplier:   0.5,
+			actions:      []action{actionTetris},
+			expectedSent: 2,
+		},
+		{
+			name:         "fractions carried over",
+			table:        DefaultAttackTable(),
+			multiplier:   0.5,
+			actions:      []action{actionDouble, actionNone, actionDouble},
+			expectedSent: 1,
+		},
+		{
+			name:         "increased",
+			table:        DefaultAttackTable(),
+			multiplier:   1.5,
+			actions:      []action{actionTetris, actionNone, actionTriple},
+			expectedSent: 9,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewCustomAttack(tc.table, tc.multiplier)
+			for _, act := range tc.actions {
+				a.ProcessAction(act)
+			}
+
+			if a.Sent() != tc.expectedSent {
+				t.Errorf("Sent: expected %d, got %d", tc.expectedSent, a.Sent())
+			}
+		})
+	}
+}
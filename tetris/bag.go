@@ -1,24 +1,97 @@
 package tetris
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
+	"slices"
 )
 
 type Bag struct {
-	Elements     []Tetrimino
-	matrixHeight int
+	Elements   []Tetrimino
+	spawn      Coordinate  // how far tetriminos are moved from their starting positions as they are dealt
+	randomizer Randomizer  // decides the order tetriminos are dealt in (nil for unseeded bags of seven)
+	tetriminos []Tetrimino // the tetriminos to draw from (nil for all of them)
+	fixed      bool        // whether the bag is never refilled, so it runs out once every tetrimino is dealt
+	scale      int         // cells each mino of the tetriminos dealt spans (0 for 1)
 }
 
-func NewBag(matrixHeight int) *Bag {
+// NewBag creates a bag of seven which deals tetriminos in a random order. Like every bag, it deals them where they
+// spawn in the given matrix (see Matrix.SpawnOffset).
+func NewBag(matrix Matrix) *Bag {
+	return NewSeededBag(matrix, rand.Int63())
+}
+
+// NewSeededBag creates a bag which always produces the same sequence of tetriminos for a given seed.
+func NewSeededBag(matrix Matrix, seed int64) *Bag {
+	return NewRandomizedBag(matrix, NewBagRandomizer(seed))
+}
+
+// NewRandomizedBag creates a bag which deals the tetriminos in the order chosen by the randomizer.
+func NewRandomizedBag(matrix Matrix, r Randomizer) *Bag {
 	b := Bag{
-		Elements:     make([]Tetrimino, 0, 14),
-		matrixHeight: matrixHeight,
+		Elements:   make([]Tetrimino, 0, 14),
+		spawn:      matrix.SpawnOffset(),
+		randomizer: r,
 	}
 	b.fill()
 	b.fill()
 	return &b
 }
 
+// NewSeededBagOf creates a seeded bag which only produces the tetriminos with the given values.
+func NewSeededBagOf(matrix Matrix, seed int64, values []byte) (*Bag, error) {
+	tetriminos, err := tetriminosOf(values)
+	if err != nil {
+		return nil, err
+	}
+
+	b := Bag{
+		Elements:   make([]Tetrimino, 0, 14),
+		spawn:      matrix.SpawnOffset(),
+		randomizer: NewBagRandomizer(seed),
+		tetriminos: tetriminos,
+	}
+	b.fill()
+	b.fill()
+	return &b, nil
+}
+
+// NewFixedBag creates a bag which deals the tetriminos with the given values in order, and then runs out.
+// Next must not be called once the bag is empty.
+func NewFixedBag(matrix Matrix, values []byte) (*Bag, error) {
+	tetriminos, err := tetriminosOf(values)
+	if err != nil {
+		return nil, err
+	}
+	return &Bag{
+		Elements: tetriminos,
+		spawn:    matrix.SpawnOffset(),
+		fixed:    true,
+	}, nil
+}
+
+// tetriminosOf returns the tetriminos with the given values, in the same order.
+func tetriminosOf(values []byte) ([]Tetrimino, error) {
+	var tetriminos []Tetrimino
+	for _, v := range values {
+		i := slices.IndexFunc(Tetriminos, func(t Tetrimino) bool { return t.Value == v })
+		if i == -1 {
+			return nil, fmt.Errorf("invalid tetrimino value %q", v)
+		}
+		tetriminos = append(tetriminos, Tetriminos[i])
+	}
+	if len(tetriminos) == 0 {
+		return nil, errors.New("no tetriminos given")
+	}
+	return tetriminos, nil
+}
+
+// Empty reports whether every tetrimino has been dealt from a fixed bag. Other bags are never empty.
+func (b *Bag) Empty() bool {
+	return len(b.Elements) == 0
+}
+
 func (b *Bag) Next() *Tetrimino {
 	tet := b.Elements[0]
 	b.Elements = b.Elements[1:]
@@ -26,21 +99,167 @@ func (b *Bag) Next() *Tetrimino {
 	if len(b.Elements) <= 7 {
 		b.fill()
 	}
+	return b.place(tet)
+}
 
-	tet.Pos.Y += b.matrixHeight - 20
-	return &tet
+// Peek returns the tetrimino Next would deal, where it would be dealt, without dealing it. It returns false if the
+// bag is empty.
+func (b *Bag) Peek() (*Tetrimino, bool) {
+	if b.Empty() {
+		return nil, false
+	}
+	return b.place(b.Elements[0]), true
+}
+
+// place returns the tetrimino scaled and moved to where the bag deals it.
+func (b *Bag) place(t Tetrimino) *Tetrimino {
+	t = t.Scaled(b.scale)
+	t.Pos.X += b.spawn.X
+	t.Pos.Y += b.spawn.Y
+	return &t
+}
+
+// Scale makes the bag deal tetriminos with each mino spanning scale cells across and down (see Tetrimino.Scaled),
+// spawning in the given matrix, which should be at least scale times the default width.
+func (b *Bag) Scale(matrix Matrix, scale int) {
+	b.scale = scale
+	b.spawn = matrix.ScaledSpawnOffset(scale)
+}
+
+// Spawned returns the tetrimino with the given value as the bag deals it, facing the way it spawns, where it spawns.
+func (b *Bag) Spawned(value byte) (*Tetrimino, error) {
+	t, err := tetriminosOf([]byte{value})
+	if err != nil {
+		return nil, err
+	}
+	return b.place(t[0]), nil
+}
+
+// SpawnPosition returns the position the tetrimino with the given value is dealt at.
+func (b *Bag) SpawnPosition(value byte) (Coordinate, error) {
+	t, err := b.Spawned(value)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	return t.Pos, nil
+}
+
+// DealNext puts the tetrimino with the given value at the front of the queue, so that it is dealt next. The rest of
+// the queue is dealt after it in the same order.
+func (b *Bag) DealNext(value byte) error {
+	t, err := tetriminosOf([]byte{value})
+	if err != nil {
+		return err
+	}
+	b.Elements = append(t, b.Elements...)
+	return nil
 }
 
 func (b *Bag) fill() {
-	if cap(b.Elements)-len(b.Elements) < 7 {
+	// Check against the intended size rather than the capacity, since Next reslices the elements and reduces the capacity.
+	if b.fixed || 14-len(b.Elements) < 7 {
 		return
 	}
 
-	perm := rand.Perm(len(Tetriminos))
-	for _, i := range perm {
-		if len(b.Elements) == 14 {
-			return
+	tetriminos := b.tetriminos
+	if tetriminos == nil {
+		tetriminos = Tetriminos
+	}
+
+	randomizer := b.randomizer
+	if randomizer == nil {
+		randomizer = &bagRandomizer{}
+	}
+	for n := len(b.Elements) + 7; len(b.Elements) < n; {
+		b.Elements = append(b.Elements, randomizer.Deal(tetriminos)...)
+	}
+}
+
+// BagState is the state of a bag, from which it can be restored to deal the same tetriminos.
+type BagState struct {
+	Queue      string           `json:"queue"`                // values of the tetriminos waiting to be dealt, in order
+	Choices    string           `json:"choices,omitempty"`    // values of the tetriminos drawn from (empty for all of them)
+	Fixed      bool             `json:"fixed,omitempty"`      // whether the bag is never refilled
+	Randomizer *RandomizerState `json:"randomizer,omitempty"` // the randomizer refilling the bag (nil for fixed bags)
+}
+
+// State returns the state of the bag. Only fixed and seeded bags can be saved, since the tetriminos dealt by other
+// bags depend on the global source of random numbers.
+func (b *Bag) State() (BagState, error) {
+	s := BagState{
+		Queue:   values(b.Elements),
+		Choices: values(b.tetriminos),
+		Fixed:   b.fixed,
+	}
+	if b.fixed {
+		return s, nil
+	}
+
+	r, ok := b.randomizer.(statefulRandomizer)
+	if !ok {
+		return BagState{}, errors.New("bag is not seeded")
+	}
+	rs := r.state()
+	s.Randomizer = &rs
+	return s, nil
+}
+
+// RestoreBag creates a bag in the given state.
+func RestoreBag(matrix Matrix, s BagState) (*Bag, error) {
+	b := Bag{
+		spawn: matrix.SpawnOffset(),
+		fixed: s.Fixed,
+	}
+
+	var err error
+	if s.Queue != "" {
+		b.Elements, err = tetriminosOf([]byte(s.Queue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid queue: %w", err)
+		}
+	}
+	if s.Choices != "" {
+		b.tetriminos, err = tetriminosOf([]byte(s.Choices))
+		if err != nil {
+			return nil, fmt.Errorf("invalid choices: %w", err)
+		}
+	}
+	if s.Fixed {
+		return &b, nil
+	}
+
+	if s.Randomizer == nil {
+		return nil, errors.New("randomizer is required")
+	}
+	b.randomizer, err = RestoreRandomizer(*s.Randomizer)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Elements) <= 7 {
+		return nil, errors.New("queue must have more than 7 tetriminos")
+	}
+	return &b, nil
+}
+
+// values returns the value of each tetrimino, in order.
+func values(tetriminos []Tetrimino) string {
+	v := make([]byte, len(tetriminos))
+	for i, t := range tetriminos {
+		v[i] = t.Value
+	}
+	return string(v)
+}
+
+// SeededBags returns the values of the first n bags of seven tetriminos drawn by a bag with the given seed,
+// which are the tetriminos a game using the seed is dealt, in order.
+func SeededBags(seed int64, n int) [][]byte {
+	b := NewSeededBag(NewDefaultMatrix(), seed)
+	bags := make([][]byte, n)
+	for i := range bags {
+		bags[i] = make([]byte, len(Tetriminos))
+		for j := range bags[i] {
+			bags[i][j] = b.Next().Value
 		}
-		b.Elements = append(b.Elements, Tetriminos[i])
 	}
+	return bags
 }
@@ -1,28 +1,34 @@
 package tetris
 
 import (
+	"encoding/json"
 	"reflect"
+	"slices"
 	"testing"
 )
 
 func TestNewBag(t *testing.T) {
 	tt := []struct {
-		name         string
-		matrixHeight int
+		name   string
+		matrix Matrix
 	}{
 		{
 			"matrix height 20",
-			20,
+			NewMatrix(DefaultWidth, 10),
 		},
 		{
 			"matrix height 40",
-			40,
+			NewDefaultMatrix(),
+		},
+		{
+			"matrix width 14",
+			NewMatrix(14, DefaultHeight),
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			b := NewBag(tc.matrixHeight)
+			b := NewBag(tc.matrix)
 
 			if len(b.Elements) != 14 {
 				t.Errorf("Length: want 14, got %d", len(b.Elements))
@@ -43,6 +49,135 @@ func TestNewBag(t *testing.T) {
 	}
 }
 
+func TestNewSeededBag(t *testing.T) {
+	a := NewSeededBag(NewDefaultMatrix(), 42)
+	b := NewSeededBag(NewDefaultMatrix(), 42)
+
+	for i := 0; i < 28; i++ {
+		if x, y := a.Next().Value, b.Next().Value; x != y {
+			t.Fatalf("Tetrimino %d: want %c, got %c", i, x, y)
+		}
+	}
+}
+
+func TestSeededBags(t *testing.T) {
+	bags := SeededBags(42, 4)
+	if len(bags) != 4 {
+		t.Fatalf("want 4 bags, got %d", len(bags))
+	}
+
+	b := NewSeededBag(NewDefaultMatrix(), 42)
+	for i, bag := range bags {
+		seen := make(map[byte]bool)
+		for j, v := range bag {
+			if seen[v] {
+				t.Errorf("Bag %d: %c appears more than once", i, v)
+			}
+			seen[v] = true
+			if want := b.Next().Value; v != want {
+				t.Errorf("Bag %d, tetrimino %d: want %c, got %c", i, j, want, v)
+			}
+		}
+		if len(seen) != len(Tetriminos) {
+			t.Errorf("Bag %d: want %d tetriminos, got %d", i, len(Tetriminos), len(seen))
+		}
+	}
+}
+
+func TestNewSeededBagOf(t *testing.T) {
+	tt := []struct {
+		name       string
+		values     []byte
+		expectsErr bool
+	}{
+		{
+			name:   "without O",
+			values: []byte{'I', 'J', 'L', 'S', 'T', 'Z'},
+		},
+		{
+			name:   "only I",
+			values: []byte{'I'},
+		},
+		{
+			name:       "invalid value",
+			values:     []byte{'Q'},
+			expectsErr: true,
+		},
+		{
+			name:       "no values",
+			expectsErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewSeededBagOf(NewDefaultMatrix(), 42, tc.values)
+			if tc.expectsErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+
+			for i := 0; i < 28; i++ {
+				if v := b.Next().Value; !slices.Contains(tc.values, v) {
+					t.Fatalf("Tetrimino %d: got %c, want one of %c", i, v, tc.values)
+				}
+			}
+		})
+	}
+}
+
+func TestNewFixedBag(t *testing.T) {
+	values := []byte{'T', 'I', 'T', 'O'}
+	b, err := NewFixedBag(NewDefaultMatrix(), values)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	for i, want := range values {
+		if b.Empty() {
+			t.Fatalf("Tetrimino %d: want %c, got empty bag", i, want)
+		}
+		if v := b.Next().Value; v != want {
+			t.Errorf("Tetrimino %d: want %c, got %c", i, want, v)
+		}
+	}
+	if !b.Empty() {
+		t.Errorf("want empty bag, got %d tetriminos left", len(b.Elements))
+	}
+
+	_, err = NewFixedBag(NewDefaultMatrix(), []byte{'T', 'Q'})
+	if err == nil {
+		t.Errorf("Invalid value: expected error, got nil")
+	}
+}
+
+func TestBag_DealNext(t *testing.T) {
+	b, err := NewFixedBag(NewDefaultMatrix(), []byte{'T', 'I'})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	err = b.DealNext('O')
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	for i, want := range []byte{'O', 'T', 'I'} {
+		if v := b.Next().Value; v != want {
+			t.Errorf("Tetrimino %d: want %c, got %c", i, want, v)
+		}
+	}
+
+	err = b.DealNext('Q')
+	if err == nil {
+		t.Errorf("Invalid value: expected error, got nil")
+	}
+}
+
 // Checks:
 //   - that the tetrimino returned is the first element of the bag.
 //   - that the first element of the bag is removed.
@@ -73,16 +208,18 @@ func TestBag_Next(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			b := Bag{
-				Elements:     tc.elements,
-				matrixHeight: 40,
+				Elements: tc.elements,
+				spawn:    Coordinate{X: 2, Y: 20},
 			}
 			expected := tc.elements[0].Copy()
-			expected.Pos.Y += b.matrixHeight - 20
+			expected.Pos.X += b.spawn.X
+			expected.Pos.Y += b.spawn.Y
 
 			var expectedElements []Tetrimino
 			for _, e := range tc.elements[1:] {
 				temp := e.Copy()
-				temp.Pos.Y += b.matrixHeight - 20
+				temp.Pos.X += b.spawn.X
+				temp.Pos.Y += b.spawn.Y
 				expectedElements = append(expectedElements, *temp)
 			}
 
@@ -92,7 +229,8 @@ func TestBag_Next(t *testing.T) {
 			}
 
 			for i := range b.Elements {
-				b.Elements[i].Pos.Y += b.matrixHeight - 20
+				b.Elements[i].Pos.X += b.spawn.X
+				b.Elements[i].Pos.Y += b.spawn.Y
 			}
 
 			if v := b.Elements[:len(expectedElements)]; !reflect.DeepEqual(v, expectedElements) {
@@ -151,8 +289,8 @@ func TestBag_Fill(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			b := Bag{
-				Elements:     tc.elements,
-				matrixHeight: 40,
+				Elements: tc.elements,
+				spawn:    Coordinate{Y: 20},
 			}
 
 			for i := 0; i < tc.timesToFill; i++ {
@@ -180,3 +318,80 @@ func TestBag_Fill(t *testing.T) {
 		})
 	}
 }
+
+func TestRestoreBag(t *testing.T) {
+	combo, err := NewSeededBagOf(NewDefaultMatrix(), 7, []byte("IJLT"))
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	fixed, err := NewFixedBag(NewDefaultMatrix(), []byte("OOTI"))
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	tt := []struct {
+		name string
+		bag  *Bag
+	}{
+		{"seeded", NewSeededBag(NewDefaultMatrix(), 42)},
+		{"NES", NewRandomizedBag(NewDefaultMatrix(), NewNESRandomizer(42))},
+		{"14-bag", randomizedBag(t, "bag14")},
+		{"random", randomizedBag(t, "random")},
+		{"TGM", randomizedBag(t, "tgm")},
+		{"subset", combo},
+		{"fixed", fixed},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				tc.bag.Next()
+			}
+			state, err := tc.bag.State()
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			// Round trip through JSON, as the state is when a game is saved.
+			data, err := json.Marshal(state)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			var decoded BagState
+			err = json.Unmarshal(data, &decoded)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			restored, err := RestoreBag(NewDefaultMatrix(), decoded)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+
+			for i := 0; !tc.bag.Empty() && i < 50; i++ {
+				want, got := tc.bag.Next(), restored.Next()
+				if !reflect.DeepEqual(want, got) {
+					t.Fatalf("Tetrimino %d: want %c, got %c", i, want.Value, got.Value)
+				}
+			}
+			if tc.bag.Empty() != restored.Empty() {
+				t.Errorf("Empty: want %t, got %t", tc.bag.Empty(), restored.Empty())
+			}
+		})
+	}
+}
+
+func TestBag_State_Unseeded(t *testing.T) {
+	_, err := NewRandomizedBag(NewDefaultMatrix(), nil).State()
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+// randomizedBag returns a bag dealt by the named randomizer, seeded with 42.
+func randomizedBag(t *testing.T, name string) *Bag {
+	t.Helper()
+	r, err := NewRandomizer(name, 42)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	return NewRandomizedBag(NewDefaultMatrix(), r)
+}
@@ -0,0 +1,89 @@
+package tetris
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the engine's source of time. Games played in a terminal use RealClock, while servers, simulations and
+// tests can use a ManualClock so that time only passes when they say so.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is a Clock which reads the system time.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a Clock which only moves forward when advanced. It is safe to use from multiple goroutines.
+type ManualClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by the given duration.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Stopwatch measures how long a game has been running, excluding any time it was stopped.
+type Stopwatch struct {
+	clock   Clock
+	elapsed time.Duration // time measured before the stopwatch was last started
+	started time.Time
+	running bool
+}
+
+func NewStopwatch(clock Clock) *Stopwatch {
+	return &Stopwatch{clock: clock}
+}
+
+// Start resumes measuring time. It does nothing if the stopwatch is already running.
+func (s *Stopwatch) Start() {
+	if s.running {
+		return
+	}
+	s.started = s.clock.Now()
+	s.running = true
+}
+
+// Stop pauses measuring time. It does nothing if the stopwatch is already stopped.
+func (s *Stopwatch) Stop() {
+	if !s.running {
+		return
+	}
+	s.elapsed += s.clock.Now().Sub(s.started)
+	s.running = false
+}
+
+// Restore sets the time measured so far, such as when a saved game is resumed. The stopwatch must be stopped.
+func (s *Stopwatch) Restore(elapsed time.Duration) {
+	s.elapsed = elapsed
+}
+
+func (s *Stopwatch) Running() bool {
+	return s.running
+}
+
+// Elapsed returns the total time the stopwatch has been running.
+func (s *Stopwatch) Elapsed() time.Duration {
+	if !s.running {
+		return s.elapsed
+	}
+	return s.elapsed + s.clock.Now().Sub(s.started)
+}
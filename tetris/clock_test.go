@@ -0,0 +1,50 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopwatch_Elapsed(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+
+	clock.Advance(time.Second)
+	if elapsed := s.Elapsed(); elapsed != 0 {
+		t.Errorf("Before start: want 0, got %v", elapsed)
+	}
+
+	s.Start()
+	clock.Advance(2 * time.Second)
+	if elapsed := s.Elapsed(); elapsed != 2*time.Second {
+		t.Errorf("Running: want %v, got %v", 2*time.Second, elapsed)
+	}
+
+	s.Stop()
+	clock.Advance(5 * time.Second)
+	if elapsed := s.Elapsed(); elapsed != 2*time.Second {
+		t.Errorf("Stopped: want %v, got %v", 2*time.Second, elapsed)
+	}
+
+	s.Start()
+	s.Start()
+	clock.Advance(time.Second)
+	if elapsed := s.Elapsed(); elapsed != 3*time.Second {
+		t.Errorf("Restarted: want %v, got %v", 3*time.Second, elapsed)
+	}
+}
+
+func TestStopwatch_Restore(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	s.Restore(90 * time.Second)
+	if elapsed := s.Elapsed(); elapsed != 90*time.Second {
+		t.Errorf("Restored: want %v, got %v", 90*time.Second, elapsed)
+	}
+
+	s.Start()
+	clock.Advance(time.Second)
+	if elapsed := s.Elapsed(); elapsed != 91*time.Second {
+		t.Errorf("Running: want %v, got %v", 91*time.Second, elapsed)
+	}
+}
@@ -0,0 +1,134 @@
+package tetris
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Curve gives the time for a tetrimino to fall one row at each level.
+type Curve func(level uint) time.Duration
+
+// Table is a curve given as the time to fall one row at each level, starting from level 1. Levels after the end of
+// the table keep the speed of its last level.
+type Table []time.Duration
+
+// Curve returns the time to fall one row at the level. Its signature matches Curve, so a table's method value can be
+// given wherever a curve is expected.
+func (t Table) Curve(level uint) time.Duration {
+	if len(t) == 0 {
+		return 0
+	}
+	i := min(int(level), len(t)) - 1
+	return t[max(i, 0)]
+}
+
+// guidelineLevels are the levels the guideline formula is followed to. The guideline only gives it up to level 15, and
+// past level 20 it no longer makes sense (eventually giving negative times), but by then tetriminos are already
+// falling more than 20 rows a frame (20G).
+const guidelineLevels = 20
+
+// guidelineTable is the guideline formula, (0.8 - (level-1) * 0.007) ^ (level-1) seconds for each row, worked out
+// for each level.
+var guidelineTable = func() Table {
+	t := make(Table, guidelineLevels)
+	for i := range t {
+		seconds := math.Pow(0.8-float64(i)*0.007, float64(i))
+		t[i] = time.Duration(seconds * float64(time.Second))
+	}
+	return t
+}()
+
+// GuidelineCurve is the fall speed described by the guideline.
+func GuidelineCurve(level uint) time.Duration {
+	return guidelineTable.Curve(level)
+}
+
+// frames returns the table for speeds measured in frames of the given length.
+func frames(frame time.Duration, counts ...int) Table {
+	t := make(Table, len(counts))
+	for i, n := range counts {
+		t[i] = time.Duration(n) * frame
+	}
+	return t
+}
+
+// nesFrame is the length of a frame on the NTSC NES, which the NES fall speeds are measured in.
+const nesFrame = time.Second * 1000 / 60099
+
+// nesTable is the frames taken to fall one row at each NES level, up to level 29, after which it stays at one.
+var nesTable = frames(nesFrame,
+	48, 43, 38, 33, 28, 23, 18, 13, 8, 6, 5, 5, 5, 4, 4, 4, 3, 3, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1)
+
+// NESCurve is the fall speed of the NES version. Levels are numbered from 1 here, so level 1 is the NES's level 0.
+func NESCurve(level uint) time.Duration {
+	return nesTable.Curve(level)
+}
+
+// tgmFrame is the length of a frame in The Grandmaster, which its fall speeds are measured in.
+const tgmFrame = time.Second / 60
+
+// tgmLevelsPerLevel is how many of The Grandmaster's levels are taken to make up each level here. Its level goes up
+// with every tetrimino dealt and line cleared, so clearing the 10 lines of a level here takes about 35 of them.
+const tgmLevelsPerLevel = 35
+
+// tgmGravity is the gravity of The Grandmaster from each of its levels, in 1/256ths of a row per frame, up to 20G
+// from level 500.
+var tgmGravity = []struct {
+	level   uint
+	gravity int
+}{
+	{0, 4}, {30, 6}, {35, 8}, {40, 10}, {50, 12}, {60, 16}, {70, 32}, {80, 48}, {90, 64}, {100, 80}, {120, 96},
+	{140, 112}, {160, 128}, {170, 144}, {200, 4}, {220, 32}, {230, 64}, {233, 96}, {236, 128}, {239, 160},
+	{243, 192}, {247, 224}, {251, 256}, {300, 512}, {330, 768}, {360, 1024}, {400, 1280}, {420, 1024}, {450, 768},
+	{500, 5120},
+}
+
+// TGMCurve is the fall speed of The Grandmaster, which starts slowly, drops back down at its level 200 and reaches
+// 20G by its level 500, around level 15 here. See tgmLevelsPerLevel for how the levels compare.
+//
+// At 20G tetriminos land almost as soon as they spawn, leaving only the lock delay to move them into place.
+func TGMCurve(level uint) time.Duration {
+	tgmLevel := (max(level, 1) - 1) * tgmLevelsPerLevel
+	gravity := tgmGravity[0].gravity
+	for _, g := range tgmGravity {
+		if g.level > tgmLevel {
+			break
+		}
+		gravity = g.gravity
+	}
+	return tgmFrame * 256 / time.Duration(gravity)
+}
+
+// MasterCurve is the fall speed of master mode, where tetriminos fall instantly (20G) at every level.
+func MasterCurve(uint) time.Duration {
+	return 0
+}
+
+// curves are the curves which can be chosen by name. MasterCurve is left out, since instant gravity needs the lock
+// delay of master mode.
+var curves = map[string]Curve{
+	"guideline": GuidelineCurve,
+	"nes":       NESCurve,
+	"tgm":       TGMCurve,
+}
+
+// CurveNames returns the names of the curves which can be chosen, in alphabetical order.
+func CurveNames() []string {
+	names := make([]string, 0, len(curves))
+	for name := range curves {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// CurveByName returns the curve with the given name, such as "nes".
+func CurveByName(name string) (Curve, error) {
+	c, ok := curves[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid curve %q", name)
+	}
+	return c, nil
+}
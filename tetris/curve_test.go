@@ -0,0 +1,121 @@
+package tetris
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTable_Curve(t *testing.T) {
+	table := Table{3 * time.Second, 2 * time.Second, time.Second}
+	tt := []struct {
+		level    uint
+		expected time.Duration
+	}{
+		{0, 3 * time.Second},
+		{1, 3 * time.Second},
+		{2, 2 * time.Second},
+		{3, time.Second},
+		{4, time.Second},
+		{99, time.Second},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprint("level ", tc.level), func(t *testing.T) {
+			if d := table.Curve(tc.level); d != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, d)
+			}
+		})
+	}
+}
+
+func TestGuidelineCurve(t *testing.T) {
+	tt := []struct {
+		level    uint
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, 793 * time.Millisecond},
+		{15, 7059 * time.Microsecond},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprint("level ", tc.level), func(t *testing.T) {
+			if d := GuidelineCurve(tc.level).Round(time.Microsecond); d != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, d)
+			}
+		})
+	}
+
+	// Past level 20 the formula goes wrong, so the speed stays at that of level 20.
+	for _, level := range []uint{21, 116, 500} {
+		if d := GuidelineCurve(level); d != GuidelineCurve(20) || d <= 0 {
+			t.Errorf("level %d: want %v, got %v", level, GuidelineCurve(20), d)
+		}
+	}
+}
+
+func TestNESCurve(t *testing.T) {
+	tt := []struct {
+		level          uint
+		expectedFrames int
+	}{
+		{1, 48},
+		{10, 6},
+		{11, 5},
+		{19, 3},
+		{20, 2},
+		{30, 1},
+		{99, 1},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprint("level ", tc.level), func(t *testing.T) {
+			expected := time.Duration(tc.expectedFrames) * nesFrame
+			if d := NESCurve(tc.level); d != expected {
+				t.Errorf("want %v, got %v", expected, d)
+			}
+		})
+	}
+
+	clock := NewManualClock(time.Unix(0, 0))
+	g := NewGravity(NewStopwatch(clock), 1)
+	g.SetCurve(NESCurve)
+	if g.Interval() != NESCurve(1) {
+		t.Errorf("Interval: want %v, got %v", NESCurve(1), g.Interval())
+	}
+}
+
+func TestTGMCurve(t *testing.T) {
+	tt := []struct {
+		level    uint
+		expected time.Duration
+	}{
+		{1, tgmFrame * 64},  // level 0, 4/256G
+		{2, tgmFrame * 32},  // level 35, 8/256G
+		{7, tgmFrame * 64},  // level 210, back down to 4/256G
+		{13, tgmFrame / 4},  // level 420, 4G
+		{15, tgmFrame / 3},  // level 490, 3G
+		{16, tgmFrame / 20}, // level 525, 20G
+		{99, tgmFrame / 20},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprint("level ", tc.level), func(t *testing.T) {
+			if d := TGMCurve(tc.level); d != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, d)
+			}
+		})
+	}
+}
+
+func TestCurveByName(t *testing.T) {
+	for _, name := range CurveNames() {
+		if _, err := CurveByName(name); err != nil {
+			t.Errorf("%s: expected nil, got error: %v", name, err)
+		}
+	}
+	if _, err := CurveByName("master"); err == nil {
+		t.Errorf("master: expected error, got nil")
+	}
+}
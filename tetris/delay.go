@@ -0,0 +1,98 @@
+package tetris
+
+import "time"
+
+// Delay measures a fixed length of time on a stopwatch, such as the lock delay after a tetrimino lands or the entry
+// delay (ARE) before the next one spawns. Since it only reads the stopwatch, it is paused whenever the game is.
+type Delay struct {
+	stopwatch *Stopwatch
+	length    time.Duration
+	start     time.Duration // the stopwatch reading when the delay was started
+	running   bool
+}
+
+func NewDelay(stopwatch *Stopwatch, length time.Duration) *Delay {
+	return &Delay{stopwatch: stopwatch, length: length}
+}
+
+// SetLength changes the length of the delay, taking effect immediately if it is running.
+func (d *Delay) SetLength(length time.Duration) {
+	d.length = length
+}
+
+// Length returns the length of the delay.
+func (d *Delay) Length() time.Duration {
+	return d.length
+}
+
+// Start begins the delay from the current stopwatch reading, restarting it if it was already running.
+func (d *Delay) Start() {
+	d.start = d.stopwatch.Elapsed()
+	d.running = true
+}
+
+func (d *Delay) Stop() {
+	d.running = false
+}
+
+func (d *Delay) Running() bool {
+	return d.running
+}
+
+// Remaining returns the time left before the delay expires, or 0 if it is not running or has expired.
+func (d *Delay) Remaining() time.Duration {
+	if !d.running {
+		return 0
+	}
+	return max(d.length-(d.stopwatch.Elapsed()-d.start), 0)
+}
+
+// Expired reports whether the delay is running and its length has passed since it started.
+func (d *Delay) Expired() bool {
+	return d.running && d.stopwatch.Elapsed()-d.start >= d.length
+}
+
+// masterFrame is the length of a frame in the arcade games master mode is based on, which its delays are measured in.
+const masterFrame = time.Second / 60
+
+// masterTiming is the number of frames of each delay in master mode, from the given level onwards.
+type masterTiming struct {
+	level     uint
+	lock      int
+	entry     int
+	lineClear int
+}
+
+// masterTimings loosely follow the arcade games, where the delays shorten as the level increases.
+var masterTimings = []masterTiming{
+	{level: 1, lock: 30, entry: 25, lineClear: 40},
+	{level: 5, lock: 30, entry: 16, lineClear: 25},
+	{level: 9, lock: 24, entry: 12, lineClear: 16},
+	{level: 13, lock: 18, entry: 6, lineClear: 12},
+	{level: 17, lock: 15, entry: 6, lineClear: 6},
+}
+
+func masterTimingAt(level uint) masterTiming {
+	timing := masterTimings[0]
+	for _, t := range masterTimings {
+		if level >= t.level {
+			timing = t
+		}
+	}
+	return timing
+}
+
+// MasterLockDelay is how long a tetrimino can be moved after landing in master mode, before it locks.
+func MasterLockDelay(level uint) time.Duration {
+	return time.Duration(masterTimingAt(level).lock) * masterFrame
+}
+
+// MasterEntryDelay is how long the next tetrimino takes to spawn after one locks in master mode.
+func MasterEntryDelay(level uint) time.Duration {
+	return time.Duration(masterTimingAt(level).entry) * masterFrame
+}
+
+// MasterLineClearDelay is how much longer the next tetrimino takes to spawn in master mode when lines are cleared.
+func MasterLineClearDelay(level uint) time.Duration {
+	return time.Duration(masterTimingAt(level).lineClear) * masterFrame
+}
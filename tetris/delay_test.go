@@ -0,0 +1,95 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	d := NewDelay(s, 500*time.Millisecond)
+	s.Start()
+
+	if d.Running() || d.Expired() {
+		t.Fatalf("Before start: want stopped, got running %t, expired %t", d.Running(), d.Expired())
+	}
+
+	d.Start()
+	clock.Advance(300 * time.Millisecond)
+	if d.Expired() {
+		t.Errorf("After 300ms: want not expired")
+	}
+	if r := d.Remaining(); r != 200*time.Millisecond {
+		t.Errorf("After 300ms: want 200ms remaining, got %v", r)
+	}
+
+	// Time does not pass for the delay while the stopwatch is stopped.
+	s.Stop()
+	clock.Advance(time.Second)
+	if d.Expired() {
+		t.Errorf("While paused: want not expired")
+	}
+	s.Start()
+
+	d.Start()
+	clock.Advance(300 * time.Millisecond)
+	if d.Expired() {
+		t.Errorf("After restart: want not expired")
+	}
+	clock.Advance(200 * time.Millisecond)
+	if !d.Expired() {
+		t.Errorf("After 500ms: want expired")
+	}
+	if r := d.Remaining(); r != 0 {
+		t.Errorf("After 500ms: want none remaining, got %v", r)
+	}
+
+	d.SetLength(time.Second)
+	if d.Expired() {
+		t.Errorf("After lengthening: want not expired")
+	}
+
+	d.Stop()
+	clock.Advance(time.Second)
+	if d.Running() || d.Expired() {
+		t.Errorf("After stop: want stopped, got running %t, expired %t", d.Running(), d.Expired())
+	}
+}
+
+func TestMasterDelays(t *testing.T) {
+	tt := []struct {
+		level             uint
+		expectedLock      int
+		expectedEntry     int
+		expectedLineClear int
+	}{
+		{1, 30, 25, 40},
+		{4, 30, 25, 40},
+		{5, 30, 16, 25},
+		{12, 24, 12, 16},
+		{13, 18, 6, 12},
+		{30, 15, 6, 6},
+	}
+
+	for _, tc := range tt {
+		if d := MasterLockDelay(tc.level); d != time.Duration(tc.expectedLock)*masterFrame {
+			t.Errorf("Level %d lock delay: want %d frames, got %v", tc.level, tc.expectedLock, d)
+		}
+		if d := MasterEntryDelay(tc.level); d != time.Duration(tc.expectedEntry)*masterFrame {
+			t.Errorf("Level %d entry delay: want %d frames, got %v", tc.level, tc.expectedEntry, d)
+		}
+		if d := MasterLineClearDelay(tc.level); d != time.Duration(tc.expectedLineClear)*masterFrame {
+			t.Errorf("Level %d line clear delay: want %d frames, got %v", tc.level, tc.expectedLineClear, d)
+		}
+	}
+
+	g := NewGravity(NewStopwatch(NewManualClock(time.Unix(0, 0))), 1)
+	if g.Instant() {
+		t.Errorf("Guideline gravity: want not instant")
+	}
+	g.SetCurve(MasterCurve)
+	if !g.Instant() || g.Rows() != 0 {
+		t.Errorf("Master gravity: want instant with no rows, got instant %t", g.Instant())
+	}
+}
@@ -0,0 +1,89 @@
+package tetris
+
+import "math"
+
+// Difficulty is a rubber-band controller for how hard a game is, adjusting it to how the player has been doing so that
+// they are neither bored nor overwhelmed. After each placement it eases off while the stack is high or lines aren't
+// being cleared, and pushes harder while the stack is low and lines are cleared quickly. The difficulty is given as a
+// multiplier for the speed of gravity and a rate of garbage to send.
+type Difficulty struct {
+	level float64 // from 0 for the easiest to 1 for the hardest
+
+	// recent are the lines cleared by each of the most recent placements, oldest first.
+	recent []int
+	// owed is the garbage sent so far which has yet to make up a whole line.
+	owed float64
+}
+
+const (
+	// FlowHeight is the height of the stack, as a fraction of the visible rows, which the difficulty is adjusted to
+	// keep it around.
+	FlowHeight = 0.35
+	// difficultyWindow is the number of placements the clear rate is measured over.
+	difficultyWindow = 10
+	// difficultyGain is how far a single placement can move the difficulty, as a fraction of its range.
+	difficultyGain = 0.05
+	// steadyClearRate is the lines each placement must clear on average to keep the height of the stack the same,
+	// since each adds four cells to a ten cell wide matrix.
+	steadyClearRate = 0.4
+	// garbageThreshold is the difficulty above which garbage is sent, and maxGarbageRate the lines sent for each
+	// placement at the highest difficulty.
+	garbageThreshold = 0.5
+	maxGarbageRate   = 0.2
+)
+
+// NewDifficulty creates a controller starting at the difficulty, from 0 for the easiest to 1 for the hardest.
+func NewDifficulty(level float64) *Difficulty {
+	return &Difficulty{level: clampDifficulty(level)}
+}
+
+// Level returns the difficulty, from 0 for the easiest to 1 for the hardest.
+func (d *Difficulty) Level() float64 {
+	return d.level
+}
+
+// Place adjusts the difficulty after a tetrimino is placed in the matrix, clearing the lines. The stack is measured
+// after the lines are removed.
+func (d *Difficulty) Place(matrix Matrix, lines int) {
+	d.recent = append(d.recent, lines)
+	if len(d.recent) > difficultyWindow {
+		d.recent = d.recent[len(d.recent)-difficultyWindow:]
+	}
+	var cleared int
+	for _, l := range d.recent {
+		cleared += l
+	}
+	// Clearing at the steady rate leaves the stack where it is, so the height alone decides the change. Clearing
+	// faster or slower than that shows where the stack is heading, and is weighted less than where it is.
+	rate := float64(cleared) / float64(len(d.recent)) / steadyClearRate
+	height := float64(matrix.Height()) / float64(matrix.VisibleRows())
+	d.level = clampDifficulty(d.level + difficultyGain*(2*(FlowHeight-height)+(rate-1)/2))
+
+	d.owed += d.GarbageRate()
+}
+
+// Speed returns the multiplier for how quickly tetriminos fall at the difficulty, from half speed at the easiest to
+// double speed at the hardest.
+func (d *Difficulty) Speed() float64 {
+	return 0.5 * math.Pow(4, d.level)
+}
+
+// GarbageRate returns the lines of garbage sent for each placement at the difficulty, which is none until it passes
+// the halfway mark.
+func (d *Difficulty) GarbageRate() float64 {
+	if d.level <= garbageThreshold {
+		return 0
+	}
+	return maxGarbageRate * (d.level - garbageThreshold) / (1 - garbageThreshold)
+}
+
+// Garbage returns the whole lines of garbage sent since it was last called, keeping any fraction of a line for later.
+func (d *Difficulty) Garbage() uint {
+	lines := math.Floor(d.owed)
+	d.owed -= lines
+	return uint(lines)
+}
+
+func clampDifficulty(level float64) float64 {
+	return min(max(level, 0), 1)
+}
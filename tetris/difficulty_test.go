@@ -0,0 +1,81 @@
+package tetris
+
+import (
+	"math"
+	"testing"
+)
+
+// stackOf returns a default matrix with a stack the given number of rows high.
+func stackOf(height int) Matrix {
+	m := NewDefaultMatrix()
+	for row := len(m) - height; row < len(m); row++ {
+		m[row][0] = 'X'
+	}
+	return m
+}
+
+func TestDifficulty_Place(t *testing.T) {
+	tt := map[string]struct {
+		height int
+		lines  int
+		harder bool
+	}{
+		"low stack clearing steadily":  {height: 2, lines: 1, harder: true},
+		"high stack clearing steadily": {height: 15, lines: 1, harder: false},
+		"low stack not clearing":       {height: 4, lines: 0, harder: false},
+		"flow height clearing quickly": {height: 7, lines: 4, harder: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			d := NewDifficulty(0.5)
+			for i := 0; i < difficultyWindow; i++ {
+				d.Place(stackOf(tc.height), tc.lines)
+			}
+			if harder := d.Level() > 0.5; harder != tc.harder {
+				t.Errorf("want harder %t, got level %v", tc.harder, d.Level())
+			}
+		})
+	}
+}
+
+func TestDifficulty_Bounds(t *testing.T) {
+	d := NewDifficulty(2)
+	if d.Level() != 1 {
+		t.Errorf("NewDifficulty: want 1, got %v", d.Level())
+	}
+	for i := 0; i < 100; i++ {
+		d.Place(stackOf(18), 0)
+	}
+	if d.Level() != 0 {
+		t.Errorf("Overwhelmed: want 0, got %v", d.Level())
+	}
+	if math.Abs(d.Speed()-0.5) > 1e-9 {
+		t.Errorf("Speed: want 0.5 at the easiest, got %v", d.Speed())
+	}
+	if d.GarbageRate() != 0 {
+		t.Errorf("GarbageRate: want none at the easiest, got %v", d.GarbageRate())
+	}
+}
+
+func TestDifficulty_Garbage(t *testing.T) {
+	d := NewDifficulty(1)
+	if math.Abs(d.Speed()-2) > 1e-9 {
+		t.Errorf("Speed: want 2 at the hardest, got %v", d.Speed())
+	}
+
+	// An empty board keeps the difficulty at its highest, sending a line every 5 placements.
+	var lines uint
+	for i := 0; i < 5; i++ {
+		if lines = d.Garbage(); lines != 0 {
+			t.Fatalf("placement %d: want no garbage, got %d", i, lines)
+		}
+		d.Place(NewDefaultMatrix(), 1)
+	}
+	if lines = d.Garbage(); lines != 1 {
+		t.Errorf("want 1 line after 5 placements, got %d", lines)
+	}
+	if lines = d.Garbage(); lines != 0 {
+		t.Errorf("want the line to be sent once, got %d", lines)
+	}
+}
@@ -0,0 +1,86 @@
+package tetris
+
+import (
+	"slices"
+	"time"
+)
+
+// Fade tracks when each cell of the matrix was filled, so cells can be hidden once they have been filled for long
+// enough, as in invisible play. It does not see the matrix itself, so it is kept in step by repeating each change
+// made to the matrix. Since it only reads the stopwatch, cells stop fading whenever the game is paused.
+type Fade struct {
+	stopwatch *Stopwatch
+	delay     time.Duration
+	filled    [][]time.Duration // the stopwatch reading when each cell was filled
+}
+
+// NewFade creates a fade for the cells of a matrix the size of the given one, where cells are hidden once they have
+// been filled for the delay (0 to hide them at once). Cells already in the matrix are treated as filled now.
+func NewFade(stopwatch *Stopwatch, matrix Matrix, delay time.Duration) *Fade {
+	f := &Fade{stopwatch: stopwatch, delay: delay, filled: make([][]time.Duration, len(matrix))}
+	for row := range f.filled {
+		f.filled[row] = make([]time.Duration, len(matrix[row]))
+	}
+	f.Reset()
+	return f
+}
+
+// Reset treats every cell as filled now, such as after the matrix is replaced.
+func (f *Fade) Reset() {
+	now := f.stopwatch.Elapsed()
+	for row := range f.filled {
+		for col := range f.filled[row] {
+			f.filled[row][col] = now
+		}
+	}
+}
+
+// Lock records the cells of the tetrimino as filled now. Cells outside the matrix are ignored.
+func (f *Fade) Lock(t *Tetrimino) {
+	now := f.stopwatch.Elapsed()
+	for row := range t.Cells {
+		for col := range t.Cells[row] {
+			y, x := t.Pos.Y+row, t.Pos.X+col
+			if !t.Cells[row][col] || y < 0 || y >= len(f.filled) || x < 0 || x >= len(f.filled[y]) {
+				continue
+			}
+			f.filled[y][x] = now
+		}
+	}
+}
+
+// RemoveLines removes the given rows, from top to bottom, moving the rows above them down as Matrix.RemoveCompletedLines
+// does.
+func (f *Fade) RemoveLines(rows []int) {
+	now := f.stopwatch.Elapsed()
+	for _, row := range rows {
+		// The removed row is reused for the new row at the top, as the matrix does.
+		removed := f.filled[row]
+		copy(f.filled[1:row+1], f.filled[:row])
+		for col := range removed {
+			removed[col] = now
+		}
+		f.filled[0] = removed
+	}
+}
+
+// Raise moves every row up by the given number of lines, as Matrix.AddGarbage does, with the new lines at the
+// bottom filled now.
+func (f *Fade) Raise(lines int) {
+	lines = min(lines, len(f.filled))
+	pushed := slices.Clone(f.filled[:lines])
+	copy(f.filled, f.filled[lines:])
+	copy(f.filled[len(f.filled)-lines:], pushed)
+	now := f.stopwatch.Elapsed()
+	for row := len(f.filled) - lines; row < len(f.filled); row++ {
+		for col := range f.filled[row] {
+			f.filled[row][col] = now
+		}
+	}
+}
+
+// Hidden reports whether the cell has been filled for at least the delay. It says nothing of whether the cell is
+// filled, which is for the matrix to answer.
+func (f *Fade) Hidden(row, col int) bool {
+	return f.stopwatch.Elapsed()-f.filled[row][col] >= f.delay
+}
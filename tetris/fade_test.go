@@ -0,0 +1,86 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFade(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	f := NewFade(s, NewDefaultMatrix(), time.Second)
+	s.Start()
+
+	tet := Tetriminos[1].Copy() // O, filling columns 3 and 4 of rows 38 and 39
+	tet.Pos = Coordinate{X: 3, Y: 38}
+
+	clock.Advance(time.Second)
+	if !f.Hidden(39, 0) {
+		t.Errorf("Starting cell after 1s: want hidden")
+	}
+
+	f.Lock(tet)
+	clock.Advance(500 * time.Millisecond)
+	if f.Hidden(38, 4) || f.Hidden(39, 3) {
+		t.Errorf("Locked cells after 500ms: want visible")
+	}
+
+	// Clearing the bottom row moves the top of the tetrimino down with the rows above.
+	f.RemoveLines([]int{39})
+	if f.Hidden(39, 4) {
+		t.Errorf("Moved cell after clear: want visible")
+	}
+	if !f.Hidden(38, 4) {
+		t.Errorf("Cell above after clear: want hidden")
+	}
+
+	// Raising garbage moves it back up, with a new garbage line at the bottom.
+	f.Raise(1)
+	if f.Hidden(38, 4) || f.Hidden(39, 0) {
+		t.Errorf("After raise: want moved cell and garbage visible")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if !f.Hidden(38, 4) {
+		t.Errorf("Moved cell after 1s: want hidden")
+	}
+
+	// Time does not pass while the stopwatch is stopped.
+	s.Stop()
+	clock.Advance(time.Second)
+	if f.Hidden(39, 0) {
+		t.Errorf("Garbage while paused: want visible")
+	}
+}
+
+func TestFade_Immediate(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	f := NewFade(s, NewDefaultMatrix(), 0)
+	s.Start()
+
+	tet := Tetriminos[0].Copy()
+	tet.Pos = Coordinate{X: 0, Y: 38}
+	f.Lock(tet)
+	if !f.Hidden(39, 0) {
+		t.Errorf("Want hidden as soon as locked")
+	}
+}
+
+func TestMatrix_CompletedLines(t *testing.T) {
+	m, err := ParseMatrix(`
+		XXXXXXXXX.
+		XXXXXXXXXX
+		XXXX.XXXXX
+		XXXXXXXXXX
+	`)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	tet := &Tetrimino{Pos: Coordinate{X: 0, Y: 36}, Cells: [][]bool{{}, {}, {}, {}}}
+
+	rows := m.CompletedLines(tet)
+	if len(rows) != 2 || rows[0] != 37 || rows[1] != 39 {
+		t.Errorf("want [37 39], got %v", rows)
+	}
+}
@@ -0,0 +1,168 @@
+package tetris
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// GarbageValue is the value of cells in garbage lines.
+const GarbageValue byte = 'X'
+
+// HolePattern is how the hole in garbage moves from one line to the next.
+type HolePattern int8
+
+const (
+	// HolesMessy moves the hole to a different column on each line with the generator's messiness: never with 0, for
+	// a single clean column, and on every line with 1.
+	HolesMessy HolePattern = iota
+	// HolesClean keeps the hole in the same column on every line, whatever the messiness.
+	HolesClean
+	// HolesRandom puts the hole of each line in a random column, which may be the same as the line before.
+	HolesRandom
+)
+
+// holePatternNames are the names of each pattern, as used on the command line.
+var holePatternNames = []string{"messy", "clean", "random"}
+
+func (h HolePattern) String() string {
+	if int(h) >= 0 && int(h) < len(holePatternNames) {
+		return holePatternNames[h]
+	}
+	return fmt.Sprintf("HolePattern(%d)", int8(h))
+}
+
+// ParseHolePattern returns the pattern with the given name, such as "clean".
+func ParseHolePattern(name string) (HolePattern, error) {
+	for i, n := range holePatternNames {
+		if n == name {
+			return HolePattern(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid hole pattern %q", name)
+}
+
+// GarbagePattern is how the holes in lines of garbage are placed.
+type GarbagePattern struct {
+	Holes     HolePattern
+	Messiness float64 // probability (0 to 1) that the hole moves to a different column on each line, for HolesMessy
+	HoleWidth int     // columns each hole spans, such as 2 for a wide well (0 for 1)
+}
+
+// GarbageGenerator chooses the hole column for each line of garbage.
+type GarbageGenerator struct {
+	pattern   HolePattern
+	messiness float64
+	holeWidth int
+	rand      *rand.Rand
+	hole      int
+}
+
+// NewGarbageGenerator creates a generator for a matrix of the given width, with holes a single column wide.
+// Messiness is the probability (0 to 1) that the hole moves to a different column on each new line.
+func NewGarbageGenerator(width int, messiness float64, seed int64) *GarbageGenerator {
+	return NewPatternedGarbageGenerator(width, GarbagePattern{Messiness: messiness}, seed)
+}
+
+// NewPatternedGarbageGenerator creates a generator for a matrix of the given width, placing holes by the pattern.
+// Holes are made narrower than the matrix if they need to be, so that every line of garbage has a filled cell.
+func NewPatternedGarbageGenerator(width int, pattern GarbagePattern, seed int64) *GarbageGenerator {
+	holeWidth := min(max(pattern.HoleWidth, 1), max(width-1, 1))
+	r := rand.New(rand.NewSource(seed))
+	return &GarbageGenerator{
+		pattern:   pattern.Holes,
+		messiness: pattern.Messiness,
+		holeWidth: holeWidth,
+		rand:      r,
+		hole:      r.Intn(max(width-holeWidth+1, 1)),
+	}
+}
+
+// HoleWidth returns the columns each hole spans, starting from the column returned by Holes.
+func (g *GarbageGenerator) HoleWidth() int {
+	return g.holeWidth
+}
+
+// Holes returns the leftmost hole column for each of the given number of lines, from top to bottom.
+func (g *GarbageGenerator) Holes(lines, width int) []int {
+	// Wide holes start far enough from the right wall to fit.
+	columns := max(width-g.holeWidth+1, 1)
+	holes := make([]int, lines)
+	for i := range holes {
+		switch g.pattern {
+		case HolesMessy:
+			if columns > 1 && g.rand.Float64() < g.messiness {
+				// Choose from all other columns so the hole is guaranteed to move.
+				next := g.rand.Intn(columns - 1)
+				if next >= g.hole {
+					next++
+				}
+				g.hole = next
+			}
+		case HolesRandom:
+			g.hole = g.rand.Intn(columns)
+		}
+		holes[i] = g.hole
+	}
+	return holes
+}
+
+// AddGarbage pushes the stack up and fills the bottom of the matrix with a line of garbage for each hole column given.
+// The stack may be pushed into the buffer zone, but it returns true if any filled cells were pushed out of the top of
+// the matrix (top out).
+func (p Matrix) AddGarbage(holes []int) bool {
+	return p.AddWideGarbage(holes, 1)
+}
+
+// AddWideGarbage adds garbage as AddGarbage does, with each hole spanning holeWidth columns from the column given.
+func (p Matrix) AddWideGarbage(holes []int, holeWidth int) bool {
+	lines := len(holes)
+	if lines == 0 {
+		return false
+	}
+	if lines > len(p) {
+		lines = len(p)
+		holes = holes[len(holes)-lines:]
+	}
+
+	toppedOut := false
+	for row := 0; row < lines; row++ {
+		for col := range p[row] {
+			if !isCellEmpty(p[row][col]) {
+				toppedOut = true
+			}
+		}
+	}
+
+	// The rows pushed out of the top are reused for the garbage, so that no two rows share their cells.
+	pushed := slices.Clone(p[:lines])
+	copy(p, p[lines:])
+	copy(p[len(p)-lines:], pushed)
+
+	for i, hole := range holes {
+		row := len(p) - lines + i
+		for col := range p[row] {
+			if col >= hole && col < hole+holeWidth {
+				p[row][col] = 0
+			} else {
+				p[row][col] = GarbageValue
+			}
+		}
+	}
+
+	return toppedOut
+}
+
+// GarbageLines returns the number of rows of the matrix which contain garbage.
+func (p Matrix) GarbageLines() int {
+	var lines int
+	for row := range p {
+		for _, cell := range p[row] {
+			if cell == GarbageValue {
+				lines++
+				break
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,223 @@
+package tetris
+
+import (
+	"testing"
+)
+
+func TestGarbageGenerator_Holes(t *testing.T) {
+	tt := []struct {
+		name       string
+		messiness  float64
+		expectSame bool
+	}{
+		{
+			"clean",
+			0,
+			true,
+		},
+		{
+			"messy",
+			1,
+			false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGarbageGenerator(10, tc.messiness, 1)
+			holes := g.Holes(20, 10)
+
+			if len(holes) != 20 {
+				t.Fatalf("Length: want 20, got %d", len(holes))
+			}
+			for i, hole := range holes {
+				if hole < 0 || hole >= 10 {
+					t.Errorf("Hole %d: %d is out of bounds", i, hole)
+				}
+				if i == 0 {
+					continue
+				}
+				if same := hole == holes[i-1]; same != tc.expectSame {
+					t.Errorf("Hole %d: expected same as previous to be %v, holes %v", i, tc.expectSame, holes)
+				}
+			}
+		})
+	}
+}
+
+func TestGarbageGenerator_Holes_Pattern(t *testing.T) {
+	tt := map[string]struct {
+		pattern   GarbagePattern
+		wantWidth int
+		wantMoves bool
+	}{
+		"clean ignores messiness": {GarbagePattern{Holes: HolesClean, Messiness: 1}, 1, false},
+		"random":                  {GarbagePattern{Holes: HolesRandom}, 1, true},
+		"wide":                    {GarbagePattern{Messiness: 1, HoleWidth: 2}, 2, true},
+		"wider than the matrix":   {GarbagePattern{HoleWidth: 12}, 9, false},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			g := NewPatternedGarbageGenerator(10, tc.pattern, 1)
+			if g.HoleWidth() != tc.wantWidth {
+				t.Fatalf("Hole width: want %d, got %d", tc.wantWidth, g.HoleWidth())
+			}
+			holes := g.Holes(20, 10)
+
+			var moves bool
+			for i, hole := range holes {
+				if hole < 0 || hole+tc.wantWidth > 10 {
+					t.Errorf("Hole %d: %d is out of bounds", i, hole)
+				}
+				moves = moves || hole != holes[0]
+			}
+			if moves != tc.wantMoves {
+				t.Errorf("want the hole to move to be %v, holes %v", tc.wantMoves, holes)
+			}
+		})
+	}
+}
+
+func TestParseHolePattern(t *testing.T) {
+	for _, pattern := range []HolePattern{HolesMessy, HolesClean, HolesRandom} {
+		got, err := ParseHolePattern(pattern.String())
+		if err != nil {
+			t.Fatalf("%v: expected nil, got error: %v", pattern, err)
+		}
+		if got != pattern {
+			t.Errorf("want %v, got %v", pattern, got)
+		}
+	}
+	if _, err := ParseHolePattern("swiss"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestMatrix_AddWideGarbage(t *testing.T) {
+	m := NewDefaultMatrix()
+	toppedOut := m.AddWideGarbage([]int{3, 8}, 2)
+
+	want := NewDefaultMatrix()
+	copy(want[38], []byte{'X', 'X', 'X', 0, 0, 'X', 'X', 'X', 'X', 'X'})
+	copy(want[39], []byte{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 0, 0})
+	if toppedOut {
+		t.Errorf("Topped out: want false, got true")
+	}
+	if !m.Equal(want) {
+		t.Errorf("Matrix: want %v, got %v", want, m)
+	}
+}
+
+func TestMatrix_AddGarbage(t *testing.T) {
+	tt := []struct {
+		name              string
+		matrix            Matrix
+		holes             []int
+		expectedMatrix    Matrix
+		expectedToppedOut bool
+	}{
+		{
+			name: "no garbage",
+			matrix: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[39], []byte{'T', 'T', 'T'})
+				return m
+			}(),
+			holes: []int{},
+			expectedMatrix: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[39], []byte{'T', 'T', 'T'})
+				return m
+			}(),
+		},
+		{
+			name: "pushes stack up",
+			matrix: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[39], []byte{'T', 'T', 'T'})
+				return m
+			}(),
+			holes: []int{0, 9},
+			expectedMatrix: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[37], []byte{'T', 'T', 'T'})
+				copy(m[38], []byte{0, 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'})
+				copy(m[39], []byte{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 0})
+				return m
+			}(),
+		},
+		{
+			name: "top out",
+			matrix: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[0], []byte{'T'})
+				return m
+			}(),
+			holes: []int{5},
+			expectedMatrix: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[39], []byte{'X', 'X', 'X', 'X', 'X', 0, 'X', 'X', 'X', 'X'})
+				return m
+			}(),
+			expectedToppedOut: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			toppedOut := tc.matrix.AddGarbage(tc.holes)
+
+			if toppedOut != tc.expectedToppedOut {
+				t.Errorf("Topped out: want %v, got %v", tc.expectedToppedOut, toppedOut)
+			}
+			if !tc.matrix.Equal(tc.expectedMatrix) {
+				t.Errorf("Matrix: want %v, got %v", tc.expectedMatrix, tc.matrix)
+			}
+		})
+	}
+}
+
+func TestMatrix_GarbageLines(t *testing.T) {
+	tt := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{
+			"empty",
+			"",
+			0,
+		},
+		{
+			"no garbage",
+			`
+				....T.....
+				...TTT....
+			`,
+			0,
+		},
+		{
+			"partly dug",
+			`
+				....T.....
+				XXXTTT.XXX
+				XXXXX.XXXX
+				.X........
+			`,
+			3,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMatrix(tc.text)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if actual := m.GarbageLines(); actual != tc.expected {
+				t.Errorf("want %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
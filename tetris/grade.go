@@ -0,0 +1,171 @@
+package tetris
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Grader grades a player's performance as they play, such as in master mode. Attached to scoring with
+// Scoring.SetGrader, it is updated each time the scoring processes an action.
+type Grader interface {
+	// Update grades a tetrimino locking down and clearing the lines, given the scoring once it has been awarded.
+	Update(s *Scoring, lines uint)
+	// StartRoll records the credit roll at the end of the game starting, and CompleteRoll the player surviving it.
+	StartRoll()
+	CompleteRoll()
+	// String returns the name of the grade earned so far, such as "S4".
+	String() string
+}
+
+// GraderNames are the names of each grader, as used on the command line:
+//   - "lines" grades by the lines cleared, with those cleared during the credit roll worth more (see Grade).
+//   - "score" grades by the score reached, and how quickly, as the first arcade game does (see ScoreGrade).
+var GraderNames = []string{"lines", "score"}
+
+// NewGrader creates the grader with the given name (see GraderNames) for a game whose final level is the one given,
+// timing the player with the stopwatch.
+func NewGrader(name string, stopwatch *Stopwatch, final uint) (Grader, error) {
+	switch name {
+	case "lines":
+		return NewGrade(), nil
+	case "score":
+		return NewScoreGrade(stopwatch, final), nil
+	}
+	return nil, fmt.Errorf("invalid grader %q", name)
+}
+
+// Grade measures a player's performance in master mode, loosely following the arcade games. Points are earned for
+// every line cleared, and are worth more when cleared during the credit roll at the end of the game, where the stack
+// is invisible.
+type Grade struct {
+	points  uint
+	rolling bool
+}
+
+// gradePoints are the points for clearing each number of lines at once, before and during the credit roll.
+var (
+	gradePoints     = []uint{0, 1, 2, 3, 4}
+	rollGradePoints = []uint{0, 2, 5, 9, 16}
+)
+
+// rollBonus is the points for surviving the credit roll without topping out.
+const rollBonus = 20
+
+// gradeStep is the points needed to move up from one grade to the next.
+const gradeStep = 10
+
+// grades are the names of each grade, from lowest to highest.
+var grades = []string{
+	"9", "8", "7", "6", "5", "4", "3", "2", "1",
+	"S1", "S2", "S3", "S4", "S5", "S6", "S7", "S8", "S9",
+	"M", "GM",
+}
+
+func NewGrade() *Grade {
+	return &Grade{}
+}
+
+// ProcessAction records the result of a tetrimino locking down, during the credit roll if roll is set.
+func (g *Grade) ProcessAction(a action, roll bool) {
+	g.award(linesCleared(a), roll)
+}
+
+// Update records a tetrimino locking down and clearing the lines, worth more once the credit roll has started.
+func (g *Grade) Update(_ *Scoring, lines uint) {
+	g.award(lines, g.rolling)
+}
+
+func (g *Grade) award(lines uint, roll bool) {
+	if roll {
+		g.points += rollGradePoints[lines]
+		return
+	}
+	g.points += gradePoints[lines]
+}
+
+// StartRoll records the credit roll starting, so that lines cleared from now on are worth more.
+func (g *Grade) StartRoll() {
+	g.rolling = true
+}
+
+// CompleteRoll records the player surviving the credit roll.
+func (g *Grade) CompleteRoll() {
+	g.points += rollBonus
+}
+
+// Points returns the total points earned.
+func (g *Grade) Points() uint {
+	return g.points
+}
+
+// String returns the name of the grade earned, such as "S4".
+func (g *Grade) String() string {
+	return grades[min(g.points/gradeStep, uint(len(grades)-1))]
+}
+
+// scoreGrades are the scores needed for each grade from 9 to S9, in the same order as grades. They are those of the
+// first arcade game, scaled to the guideline scoring of a game 15 levels long.
+var scoreGrades = []uint{
+	0, 250, 500, 800, 1200, 2000, 3000, 4500, 6500,
+	9000, 12000, 16500, 22000, 28500, 36000, 45000, 55000, 66000,
+}
+
+// gmCheckpoint is a point in the game which must be passed in time, and with a high enough grade, to earn GM.
+type gmCheckpoint struct {
+	level uint          // level reached
+	time  time.Duration // most time it can take to reach it
+	grade string        // lowest grade it can be reached with
+}
+
+// ScoreGrade grades a player by their score, as the first arcade game does. Each grade from 9 to S9 is earned by
+// reaching its score, and GM by reaching a third of the way through the game within 4:15 at grade 1 or higher, half
+// way within 7:30 at S4 or higher, and clearing the final level within 13:30 at S9.
+type ScoreGrade struct {
+	stopwatch   *Stopwatch
+	grade       int
+	checkpoints []gmCheckpoint // the checkpoints still to pass for GM (nil once one has been missed)
+	gm          bool
+}
+
+// NewScoreGrade creates a grader for a game whose final level is the one given, timing the player with the stopwatch.
+func NewScoreGrade(stopwatch *Stopwatch, final uint) *ScoreGrade {
+	return &ScoreGrade{
+		stopwatch: stopwatch,
+		checkpoints: []gmCheckpoint{
+			{level: max(final/3, 1), time: 4*time.Minute + 15*time.Second, grade: "1"},
+			{level: final/2 + 1, time: 7*time.Minute + 30*time.Second, grade: "S4"},
+			{level: final + 1, time: 13*time.Minute + 30*time.Second, grade: "S9"},
+		},
+	}
+}
+
+// Update grades the score reached, and checks any checkpoint for GM reached by it.
+func (g *ScoreGrade) Update(s *Scoring, _ uint) {
+	for g.grade+1 < len(scoreGrades) && s.Total() >= scoreGrades[g.grade+1] {
+		g.grade++
+	}
+	for len(g.checkpoints) > 0 && s.Level() >= g.checkpoints[0].level {
+		c := g.checkpoints[0]
+		if g.stopwatch.Elapsed() > c.time || g.grade < slices.Index(grades, c.grade) {
+			g.checkpoints = nil
+			break
+		}
+		g.checkpoints = g.checkpoints[1:]
+		g.gm = len(g.checkpoints) == 0
+	}
+}
+
+// StartRoll does nothing, since the credit roll doesn't change the score needed for each grade.
+func (g *ScoreGrade) StartRoll() {}
+
+// CompleteRoll does nothing, since the credit roll doesn't change the score needed for each grade.
+func (g *ScoreGrade) CompleteRoll() {}
+
+// String returns the name of the grade earned, such as "S4", or "GM".
+func (g *ScoreGrade) String() string {
+	if g.gm {
+		return "GM"
+	}
+	return grades[g.grade]
+}
@@ -0,0 +1,165 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGrade(t *testing.T) {
+	repeat := func(a action, n int) []action {
+		actions := make([]action, n)
+		for i := range actions {
+			actions[i] = a
+		}
+		return actions
+	}
+
+	tt := []struct {
+		name           string
+		actions        []action
+		rollActions    []action
+		completeRoll   bool
+		expectedPoints uint
+		expectedGrade  string
+	}{
+		{
+			name:          "nothing cleared",
+			actions:       []action{actionNone, actionTSpin},
+			expectedGrade: "9",
+		},
+		{
+			name:           "lines before the roll",
+			actions:        []action{actionSingle, actionTSpinDouble, actionTetris, actionTriple},
+			expectedPoints: 10,
+			expectedGrade:  "8",
+		},
+		{
+			name:           "lines during the roll",
+			rollActions:    []action{actionSingle, actionTSpinDouble, actionTetris, actionTriple},
+			expectedPoints: 32,
+			expectedGrade:  "6",
+		},
+		{
+			name:           "roll completed",
+			actions:        repeat(actionTetris, 40),
+			completeRoll:   true,
+			expectedPoints: 180,
+			expectedGrade:  "M",
+		},
+		{
+			name:           "highest grade",
+			actions:        repeat(actionTetris, 45),
+			rollActions:    repeat(actionTetris, 10),
+			completeRoll:   true,
+			expectedPoints: 360,
+			expectedGrade:  "GM",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGrade()
+			for _, a := range tc.actions {
+				g.ProcessAction(a, false)
+			}
+			for _, a := range tc.rollActions {
+				g.ProcessAction(a, true)
+			}
+			if tc.completeRoll {
+				g.CompleteRoll()
+			}
+
+			if g.Points() != tc.expectedPoints {
+				t.Errorf("Points: want %d, got %d", tc.expectedPoints, g.Points())
+			}
+			if g.String() != tc.expectedGrade {
+				t.Errorf("Grade: want %q, got %q", tc.expectedGrade, g.String())
+			}
+		})
+	}
+}
+
+func TestScoreGrade(t *testing.T) {
+	type lock struct {
+		level   uint // level reached
+		total   uint // score reached
+		elapsed time.Duration
+	}
+	tt := []struct {
+		name  string
+		locks []lock
+		want  string
+	}{
+		{
+			name:  "by score",
+			locks: []lock{{1, 800, time.Minute}, {2, 2400, 2 * time.Minute}},
+			want:  "4",
+		},
+		{
+			name:  "grand master",
+			locks: []lock{{5, 7000, 4 * time.Minute}, {8, 25000, 7 * time.Minute}, {16, 70000, 13 * time.Minute}},
+			want:  "GM",
+		},
+		{
+			name:  "checkpoint too slow",
+			locks: []lock{{5, 7000, 5 * time.Minute}, {8, 25000, 7 * time.Minute}, {16, 70000, 13 * time.Minute}},
+			want:  "S9",
+		},
+		{
+			name:  "checkpoint grade too low",
+			locks: []lock{{5, 7000, 4 * time.Minute}, {8, 20000, 7 * time.Minute}, {16, 70000, 13 * time.Minute}},
+			want:  "S9",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Time{})
+			stopwatch := NewStopwatch(clock)
+			stopwatch.Start()
+			s := NewScoring(1)
+			s.SetGrader(NewScoreGrade(stopwatch, 15))
+
+			for _, l := range tc.locks {
+				clock.Advance(l.elapsed - stopwatch.Elapsed())
+				s.level, s.total = l.level, l.total
+				s.ProcessAction(actionNone)
+			}
+
+			if got := s.Grade(); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNewGrader(t *testing.T) {
+	for _, name := range GraderNames {
+		g, err := NewGrader(name, NewStopwatch(RealClock{}), 15)
+		if err != nil {
+			t.Fatalf("%s: expected nil, got error: %v", name, err)
+		}
+		if g.String() != "9" {
+			t.Errorf("%s: want grade 9 before anything is scored, got %q", name, g.String())
+		}
+	}
+	if _, err := NewGrader("lap", nil, 15); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestScoring_Grade(t *testing.T) {
+	s := NewScoring(1)
+	if s.Grade() != "" {
+		t.Errorf("want no grade without a grader, got %q", s.Grade())
+	}
+
+	g := NewGrade()
+	s.SetGrader(g)
+	s.ProcessAction(actionTetris)
+	g.StartRoll()
+	s.ProcessAction(actionTetris)
+	if g.Points() != gradePoints[4]+rollGradePoints[4] {
+		t.Errorf("want %d points, got %d", gradePoints[4]+rollGradePoints[4], g.Points())
+	}
+}
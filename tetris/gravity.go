@@ -0,0 +1,186 @@
+package tetris
+
+import "time"
+
+// Gravity decides when the falling tetrimino is lowered, based on the level and the time measured by a stopwatch.
+// Since it only reads the stopwatch, time passes for gravity exactly when it does for the rest of the game.
+//
+// The rows fallen are accumulated from the time elapsed at the speed in effect, including fractions of a row, so the
+// same number of rows fall however often or late they are asked for, such as when frames are dropped, and speeds of
+// any number of rows a frame are kept to exactly.
+//
+// A tetrimino which has landed locks when gravity next lowers it, so the speed also scales how long it can be moved
+// after landing.
+type Gravity struct {
+	stopwatch    *Stopwatch
+	level        uint
+	curve        Curve
+	speed        float64 // multiplier for how quickly tetriminos fall, where 1 is the speed given by the curve
+	defaultTime  time.Duration
+	softDropTime time.Duration
+	isSoftDrop   bool
+
+	// heldUntil is when a held soft drop ends, unless the key is pressed again first (0 if soft drop is toggled).
+	heldUntil time.Duration
+
+	// softDropFactor is how many times faster tetriminos fall during a soft drop.
+	softDropFactor uint
+	// sonic is set when soft drops lower tetriminos all the way at once, so softDropFactor is unused.
+	sonic bool
+
+	// progress is how many rows the tetrimino has fallen that it hasn't been lowered by yet, including the fraction of
+	// a row towards the next, as of the stopwatch reading at.
+	progress float64
+	at       time.Duration
+}
+
+// rowEpsilon allows for rounding errors in the progress accumulated, so that falling exactly one row's time counts as
+// a whole row.
+const rowEpsilon = 1e-9
+
+func NewGravity(stopwatch *Stopwatch, level uint) *Gravity {
+	g := &Gravity{stopwatch: stopwatch, curve: GuidelineCurve, speed: 1, softDropFactor: DefaultSoftDropFactor}
+	g.SetLevel(level)
+	return g
+}
+
+// SetLevel changes the fall speeds to match the level, keeping soft drop active if it was.
+func (g *Gravity) SetLevel(level uint) {
+	g.change(func() {
+		g.level = level
+		g.defaultTime = time.Duration(float64(g.curve(level)) / g.speed)
+		g.softDropTime = g.defaultTime / time.Duration(g.softDropFactor)
+	})
+}
+
+// SetCurve changes how the fall speed increases with the level, such as to NESCurve for classic rules.
+func (g *Gravity) SetCurve(c Curve) {
+	g.curve = c
+	g.SetLevel(g.level)
+}
+
+// SetSpeed scales how quickly tetriminos fall at every level, such as 0.5 for half the guideline speed.
+func (g *Gravity) SetSpeed(speed float64) {
+	if speed <= 0 {
+		speed = 1
+	}
+	g.speed = speed
+	g.SetLevel(g.level)
+}
+
+// DefaultSoftDropFactor is how many times faster tetriminos fall during a soft drop, unless set otherwise.
+const DefaultSoftDropFactor = 10
+
+// SetSoftDropFactor changes how many times faster tetriminos fall during a soft drop (0 for the default).
+func (g *Gravity) SetSoftDropFactor(factor uint) {
+	if factor == 0 {
+		factor = DefaultSoftDropFactor
+	}
+	g.softDropFactor = factor
+	g.SetLevel(g.level)
+}
+
+// SetSonicDrop makes soft drops lower tetriminos all the way onto the stack at once without locking them (a sonic
+// drop), rather than making them fall faster. A landed tetrimino then locks when it would without a soft drop.
+func (g *Gravity) SetSonicDrop(sonic bool) {
+	g.change(func() { g.sonic = sonic })
+}
+
+// IsSonicDrop reports whether a sonic drop is active. Rows does not count it, so the tetrimino must be dropped by the
+// caller.
+func (g *Gravity) IsSonicDrop() bool {
+	return g.sonic && g.IsSoftDrop()
+}
+
+func (g *Gravity) ToggleSoftDrop() {
+	g.change(func() {
+		g.isSoftDrop = !g.isSoftDrop
+		g.heldUntil = 0
+	})
+}
+
+// SoftDropHoldDelay is how long a held soft drop lasts after the key is first pressed, waiting for the terminal to
+// start repeating it. It covers the repeat delay of most terminals, which is usually between 250 and 660ms.
+const SoftDropHoldDelay = 700 * time.Millisecond
+
+// HoldSoftDrop soft drops for a press of the key, when soft drop lasts only while the key is held rather than being
+// toggled. Terminals don't report keys being let go, so the soft drop ends once the terminal stops repeating the key:
+// SoftDropHoldDelay after the first press, or the repeat window after each repeat (0 for DefaultRepeatWindow).
+func (g *Gravity) HoldSoftDrop(window time.Duration) {
+	if window <= 0 {
+		window = DefaultRepeatWindow
+	}
+	wait := SoftDropHoldDelay
+	if g.heldUntil > 0 && g.IsSoftDrop() {
+		wait = window
+	}
+	g.heldUntil = g.stopwatch.Elapsed() + wait
+	if !g.isSoftDrop {
+		g.change(func() { g.isSoftDrop = true })
+	}
+}
+
+func (g *Gravity) IsSoftDrop() bool {
+	return g.isSoftDrop && (g.heldUntil == 0 || g.stopwatch.Elapsed() < g.heldUntil)
+}
+
+// Instant reports whether tetriminos fall all the way as soon as they spawn or move (20G), rather than row by row.
+// Rows always returns 0 in this case, so the tetrimino must be dropped by the caller.
+func (g *Gravity) Instant() bool {
+	return g.defaultTime <= 0
+}
+
+// Interval returns the time for a tetrimino to fall one row, ignoring soft drop.
+func (g *Gravity) Interval() time.Duration {
+	return g.defaultTime
+}
+
+func (g *Gravity) interval() time.Duration {
+	if g.isSoftDrop && !g.sonic {
+		return g.softDropTime
+	}
+	return g.defaultTime
+}
+
+// advance accumulates the rows fallen at the current speed from when progress was last measured until the given
+// stopwatch reading.
+func (g *Gravity) advance(to time.Duration) {
+	if interval := g.interval(); interval > 0 && to > g.at {
+		g.progress += float64(to-g.at) / float64(interval)
+	}
+	g.at = to
+}
+
+// change changes the fall speed with apply, counting the time until now at the old speed. Progress towards the next
+// row carries over, except that once the speed increases the time already spent falling towards it counts at the new
+// speed, up to a whole row, so starting a soft drop lowers the tetrimino by at most one row straight away.
+func (g *Gravity) change(apply func()) {
+	g.advance(g.stopwatch.Elapsed())
+	before := g.interval()
+	apply()
+	if after := g.interval(); after > 0 && after < before {
+		g.progress = min(g.progress*float64(before)/float64(after), max(g.progress, 1))
+	}
+}
+
+// Reset starts the fall towards the next row from now, such as when a saved game is resumed.
+func (g *Gravity) Reset() {
+	g.progress = 0
+	g.at = g.stopwatch.Elapsed()
+}
+
+// Rows returns the number of rows the tetrimino should be lowered by since the last call. Any fraction of a row is
+// kept towards the next call.
+func (g *Gravity) Rows() int {
+	elapsed := g.stopwatch.Elapsed()
+	if g.isSoftDrop && g.heldUntil > 0 && elapsed >= g.heldUntil {
+		// The held soft drop ended since the last call, so it only counts until then.
+		g.advance(g.heldUntil)
+		g.isSoftDrop, g.heldUntil = false, 0
+	}
+	g.advance(elapsed)
+
+	rows := int(g.progress + rowEpsilon)
+	g.progress = max(g.progress-float64(rows), 0)
+	return rows
+}
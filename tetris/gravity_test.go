@@ -0,0 +1,290 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGravity_Rows(t *testing.T) {
+	tt := []struct {
+		name         string
+		level        uint
+		advance      time.Duration
+		softDrop     bool
+		expectedRows int
+	}{
+		{"none elapsed", 1, 0, false, 0},
+		{"less than a row", 1, 999 * time.Millisecond, false, 0},
+		{"one row", 1, time.Second, false, 1},
+		{"several rows", 1, 3500 * time.Millisecond, false, 3},
+		{"faster level", 10, time.Second, false, 15},
+		{"soft drop", 1, time.Second, true, 10},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Unix(0, 0))
+			s := NewStopwatch(clock)
+			g := NewGravity(s, tc.level)
+			if tc.softDrop {
+				g.ToggleSoftDrop()
+			}
+			s.Start()
+
+			clock.Advance(tc.advance)
+			if rows := g.Rows(); rows != tc.expectedRows {
+				t.Errorf("want %d, got %d", tc.expectedRows, rows)
+			}
+			if rows := g.Rows(); rows != 0 {
+				t.Errorf("Repeated: want 0, got %d", rows)
+			}
+		})
+	}
+}
+
+func TestGravity_Rows_Frames(t *testing.T) {
+	tt := []struct {
+		name         string
+		speed        float64
+		frame        time.Duration
+		frames       int
+		expectedRows int
+	}{
+		{"every frame", 1, time.Second / 60, 150, 2},
+		{"dropped frames", 1, time.Second / 6, 15, 2},
+		{"fraction of a row each frame", 1.5, 16 * time.Millisecond, 100, 2},
+		{"several rows each frame", 1.5, 2 * time.Second, 3, 9},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Unix(0, 0))
+			s := NewStopwatch(clock)
+			g := NewGravity(s, 1)
+			g.SetSpeed(tc.speed)
+			s.Start()
+
+			var rows int
+			for i := 0; i < tc.frames; i++ {
+				clock.Advance(tc.frame)
+				rows += g.Rows()
+			}
+			if rows != tc.expectedRows {
+				t.Errorf("want %d, got %d", tc.expectedRows, rows)
+			}
+		})
+	}
+}
+
+func TestGravity_SlowerKeepsProgress(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+	g.ToggleSoftDrop()
+	s.Start()
+
+	// Half a row is fallen during the soft drop, and the other half at the normal speed once it ends.
+	clock.Advance(50 * time.Millisecond)
+	g.ToggleSoftDrop()
+	clock.Advance(499 * time.Millisecond)
+	if rows := g.Rows(); rows != 0 {
+		t.Errorf("Before a row: want 0, got %d", rows)
+	}
+	clock.Advance(time.Millisecond)
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("After a row: want 1, got %d", rows)
+	}
+}
+
+func TestGravity_Stopped(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+
+	s.Start()
+	clock.Advance(500 * time.Millisecond)
+	s.Stop()
+	clock.Advance(10 * time.Second)
+	if rows := g.Rows(); rows != 0 {
+		t.Errorf("Stopped: want 0, got %d", rows)
+	}
+
+	s.Start()
+	clock.Advance(500 * time.Millisecond)
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("Resumed: want 1, got %d", rows)
+	}
+}
+
+func TestGravity_Reset(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+
+	s.Restore(time.Minute)
+	g.Reset()
+	if rows := g.Rows(); rows != 0 {
+		t.Errorf("Reset: want 0, got %d", rows)
+	}
+
+	s.Start()
+	clock.Advance(time.Second)
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("Resumed: want 1, got %d", rows)
+	}
+}
+
+func TestGravity_ToggleSoftDrop(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+
+	s.Start()
+	clock.Advance(900 * time.Millisecond)
+	g.ToggleSoftDrop()
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("Soft drop started: want 1, got %d", rows)
+	}
+
+	clock.Advance(50 * time.Millisecond)
+	g.ToggleSoftDrop()
+	clock.Advance(950 * time.Millisecond)
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("Soft drop ended: want 1, got %d", rows)
+	}
+}
+
+func TestGravity_HoldSoftDrop(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+	s.Start()
+
+	g.HoldSoftDrop(0)
+	if !g.IsSoftDrop() {
+		t.Fatalf("Pressed: want soft drop, got none")
+	}
+	// The terminal starts repeating the key after 500ms, then every 50ms until it is let go after a second.
+	clock.Advance(500 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		g.HoldSoftDrop(0)
+		clock.Advance(50 * time.Millisecond)
+	}
+	if rows := g.Rows(); rows != 10 {
+		t.Errorf("Held: want 10, got %d", rows)
+	}
+
+	// The soft drop ends the repeat window after the last repeat, at 1030ms, and gravity falls at the normal speed
+	// from then on.
+	clock.Advance(time.Second)
+	if g.IsSoftDrop() {
+		t.Errorf("Released: want no soft drop")
+	}
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("Released: want 1, got %d", rows)
+	}
+}
+
+func TestGravity_HoldSoftDrop_Tap(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+	s.Start()
+
+	g.HoldSoftDrop(0)
+	clock.Advance(SoftDropHoldDelay - time.Millisecond)
+	if !g.IsSoftDrop() {
+		t.Errorf("Before delay: want soft drop, got none")
+	}
+	clock.Advance(time.Millisecond)
+	if g.IsSoftDrop() {
+		t.Errorf("After delay: want no soft drop")
+	}
+	if rows := g.Rows(); rows != 7 {
+		t.Errorf("want 7, got %d", rows)
+	}
+}
+
+func TestGravity_SetSoftDropFactor(t *testing.T) {
+	tt := []struct {
+		name         string
+		factor       uint
+		expectedRows int
+	}{
+		{"default", 0, 10},
+		{"slower", 5, 5},
+		{"faster", 20, 20},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Unix(0, 0))
+			s := NewStopwatch(clock)
+			g := NewGravity(s, 1)
+			g.SetSoftDropFactor(tc.factor)
+			g.ToggleSoftDrop()
+			s.Start()
+
+			clock.Advance(time.Second)
+			if rows := g.Rows(); rows != tc.expectedRows {
+				t.Errorf("want %d, got %d", tc.expectedRows, rows)
+			}
+		})
+	}
+}
+
+func TestGravity_SetSonicDrop(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	g := NewGravity(s, 1)
+	g.SetSonicDrop(true)
+	s.Start()
+
+	if g.IsSonicDrop() {
+		t.Errorf("Before soft drop: want no sonic drop")
+	}
+	g.ToggleSoftDrop()
+	if !g.IsSonicDrop() {
+		t.Errorf("Soft drop: want sonic drop, got none")
+	}
+	// The tetrimino is dropped by the caller, so gravity keeps to the normal speed.
+	clock.Advance(time.Second)
+	if rows := g.Rows(); rows != 1 {
+		t.Errorf("want 1, got %d", rows)
+	}
+}
+
+func TestGravity_SetSpeed(t *testing.T) {
+	tt := []struct {
+		name         string
+		speed        float64
+		expectedRows int
+	}{
+		{"half speed", 0.5, 2},
+		{"normal speed", 1, 4},
+		{"double speed", 2, 8},
+		{"invalid speed", 0, 4},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Unix(0, 0))
+			s := NewStopwatch(clock)
+			g := NewGravity(s, 1)
+			g.SetSpeed(tc.speed)
+			s.Start()
+
+			clock.Advance(4 * time.Second)
+			if rows := g.Rows(); rows != tc.expectedRows {
+				t.Errorf("want %d, got %d", tc.expectedRows, rows)
+			}
+
+			g.SetLevel(2)
+			expected := NewGravity(s, 2)
+			expected.SetSpeed(tc.speed)
+			if g.Interval() != expected.Interval() {
+				t.Errorf("Interval after level up: want %v, got %v", expected.Interval(), g.Interval())
+			}
+		})
+	}
+}
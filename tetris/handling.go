@@ -0,0 +1,92 @@
+package tetris
+
+import "time"
+
+// Handling is how the game responds to held keys: how soon and how quickly a held key moves the tetrimino sideways,
+// and how fast it falls during a soft drop. It also holds the lock-down rule, for how moves keep a landed tetrimino
+// from locking.
+type Handling struct {
+	// DAS (delayed auto shift) is how long a key is held before the tetrimino starts moving repeatedly. Until then,
+	// the presses the terminal repeats are ignored. 0 turns handling off, moving once for every press the terminal sends.
+	DAS time.Duration
+	// ARR (auto repeat rate) is the time between each move once DAS has passed (0 to move all the way to the wall).
+	ARR time.Duration
+	// Window is the longest gap between the presses a terminal repeats while a key is held, so that a press of the same
+	// key within it is taken to mean the key is still held (0 for DefaultRepeatWindow).
+	Window time.Duration
+	// SoftDrop is how many times faster tetriminos fall during a soft drop (0 for DefaultSoftDropFactor).
+	SoftDrop uint
+	// SonicDrop makes soft drops lower the tetrimino all the way onto the stack at once without locking it, in place
+	// of SoftDrop.
+	SonicDrop bool
+	// ToggleSoftDrop keeps soft drop on from one press of the key until the next, rather than only while it is held.
+	ToggleSoftDrop bool
+	// LockDown is the rule for what restarts the lock delay of a landed tetrimino (LockDownExtended by default).
+	LockDown LockDown
+}
+
+// Scaled returns the handling for a game played at the given speed, such as 0.5 for half speed, with DAS and ARR
+// lasting as much shorter or longer as the other timers of the game. The repeat window is kept, since it depends on the
+// terminal rather than the game.
+func (h Handling) Scaled(speed float64) Handling {
+	if speed <= 0 || speed == 1 {
+		return h
+	}
+	h.DAS = time.Duration(float64(h.DAS) / speed)
+	h.ARR = time.Duration(float64(h.ARR) / speed)
+	return h
+}
+
+// DefaultRepeatWindow suits the key repeat rate of most terminals, which repeat held keys around 30 times a second.
+const DefaultRepeatWindow = 80 * time.Millisecond
+
+// Shift applies the handling to the presses of the keys which move the tetrimino sideways. Terminals only report key
+// presses, repeating them at their own rate while a key is held, so a press of the same key soon after the last is
+// taken to mean that the key is still held.
+type Shift struct {
+	handling    Handling
+	move        Move
+	start, last time.Duration // when the key was first pressed, and when the terminal last sent it
+	moved       int           // cells moved since the key was first pressed
+	pressed     bool          // whether any key has been pressed yet
+}
+
+func NewShift(h Handling) *Shift {
+	if h.Window <= 0 {
+		h.Window = DefaultRepeatWindow
+	}
+	return &Shift{handling: h}
+}
+
+// Press records a press of the key for the move at the given time since the start of the game, and returns the
+// number of cells to move the tetrimino by. The width of the matrix is returned to move it as far as it can go.
+func (s *Shift) Press(move Move, at time.Duration) int {
+	h := s.handling
+	if h.DAS <= 0 {
+		return 1
+	}
+
+	held := s.pressed && move == s.move && at-s.last <= h.Window
+	s.pressed = true
+	s.last = at
+	if !held {
+		s.move = move
+		s.start = at
+		s.moved = 1
+		return 1
+	}
+
+	elapsed := at - s.start
+	if elapsed < h.DAS {
+		return 0
+	}
+	if h.ARR <= 0 {
+		return MaxWidth
+	}
+
+	// The tetrimino moves once when the key is first pressed, again once DAS has passed, and then every ARR.
+	due := 2 + int((elapsed-h.DAS)/h.ARR)
+	cells := max(due-s.moved, 0)
+	s.moved = max(due, s.moved)
+	return cells
+}
@@ -0,0 +1,102 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShift_Press(t *testing.T) {
+	type press struct {
+		move Move
+		at   time.Duration
+	}
+	ms := time.Millisecond
+
+	tt := []struct {
+		name     string
+		handling Handling
+		presses  []press
+		expected []int
+	}{
+		{
+			name:     "handling off",
+			presses:  []press{{MoveLeft, 0}, {MoveLeft, 30 * ms}, {MoveLeft, 60 * ms}},
+			expected: []int{1, 1, 1},
+		},
+		{
+			name:     "taps",
+			handling: Handling{DAS: 150 * ms, ARR: 30 * ms},
+			presses:  []press{{MoveLeft, 0}, {MoveLeft, 200 * ms}, {MoveRight, 220 * ms}, {MoveLeft, 240 * ms}},
+			expected: []int{1, 1, 1, 1},
+		},
+		{
+			name:     "held before DAS",
+			handling: Handling{DAS: 150 * ms, ARR: 30 * ms},
+			presses:  []press{{MoveLeft, 0}, {MoveLeft, 50 * ms}, {MoveLeft, 100 * ms}},
+			expected: []int{1, 0, 0},
+		},
+		{
+			name:     "held past DAS",
+			handling: Handling{DAS: 100 * ms, ARR: 20 * ms},
+			presses:  []press{{MoveLeft, 0}, {MoveLeft, 60 * ms}, {MoveLeft, 120 * ms}, {MoveLeft, 180 * ms}},
+			expected: []int{1, 0, 2, 3},
+		},
+		{
+			name:     "instant ARR",
+			handling: Handling{DAS: 100 * ms},
+			presses:  []press{{MoveRight, 0}, {MoveRight, 60 * ms}, {MoveRight, 120 * ms}},
+			expected: []int{1, 0, MaxWidth},
+		},
+		{
+			name:     "released",
+			handling: Handling{DAS: 100 * ms, Window: 50 * ms},
+			presses:  []press{{MoveRight, 0}, {MoveRight, 40 * ms}, {MoveRight, 80 * ms}, {MoveRight, 140 * ms}},
+			expected: []int{1, 0, 0, 1},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewShift(tc.handling)
+			for i, p := range tc.presses {
+				if cells := s.Press(p.move, p.at); cells != tc.expected[i] {
+					t.Errorf("Press %d (%v at %v): want %d, got %d", i, p.move, p.at, tc.expected[i], cells)
+				}
+			}
+		})
+	}
+}
+
+func TestHandling_Scaled(t *testing.T) {
+	h := Handling{DAS: 160 * time.Millisecond, ARR: 30 * time.Millisecond, Window: 80 * time.Millisecond, SoftDrop: 20}
+
+	tt := map[string]struct {
+		speed float64
+		want  Handling
+	}{
+		"normal speed": {
+			speed: 1,
+			want:  h,
+		},
+		"half speed": {
+			speed: 0.5,
+			want:  Handling{DAS: 320 * time.Millisecond, ARR: 60 * time.Millisecond, Window: 80 * time.Millisecond, SoftDrop: 20},
+		},
+		"double speed": {
+			speed: 2,
+			want:  Handling{DAS: 80 * time.Millisecond, ARR: 15 * time.Millisecond, Window: 80 * time.Millisecond, SoftDrop: 20},
+		},
+		"unset": {
+			speed: 0,
+			want:  h,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			if got := h.Scaled(tc.speed); got != tc.want {
+				t.Errorf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
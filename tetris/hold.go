@@ -0,0 +1,144 @@
+package tetris
+
+import (
+	"errors"
+	"fmt"
+)
+
+// HoldRule is how often the current tetrimino can be swapped with the held one.
+type HoldRule int8
+
+const (
+	// HoldOnce allows one hold for each tetrimino dealt, until it locks. This is the guideline's rule.
+	HoldOnce HoldRule = iota
+	// HoldOff turns hold off entirely, as in the NES version.
+	HoldOff
+	// HoldUnlimited allows the tetriminos to be swapped back and forth as often as the player likes, for practice.
+	HoldUnlimited
+)
+
+// holdRuleNames are the names of each rule, as used on the command line.
+var holdRuleNames = []string{"once", "off", "unlimited"}
+
+func (h HoldRule) String() string {
+	if int(h) >= 0 && int(h) < len(holdRuleNames) {
+		return holdRuleNames[h]
+	}
+	return fmt.Sprintf("HoldRule(%d)", int8(h))
+}
+
+// ParseHoldRule returns the rule with the given name, such as "unlimited".
+func ParseHoldRule(name string) (HoldRule, error) {
+	for i, n := range holdRuleNames {
+		if n == name {
+			return HoldRule(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid hold rule %q", name)
+}
+
+var (
+	// ErrBlockOut is returned when a tetrimino has no room to spawn, which tops out (block out).
+	ErrBlockOut = errors.New("no room for tetrimino to spawn (block out)")
+	// ErrEmptyBag is returned when a tetrimino is to be dealt from a fixed bag which has run out.
+	ErrEmptyBag = errors.New("no tetriminos left to deal")
+)
+
+// MaxHoldSlots is the most tetriminos a hold queue can be given slots for.
+const MaxHoldSlots = 4
+
+// HoldQueue is the tetriminos put on hold, in as many slots as the game allows. Holding puts the current tetrimino at
+// the back of the queue, and the next tetrimino is dealt in its place until every slot is filled. After that, each
+// hold swaps in the tetrimino at the front, so holding again and again cycles through them.
+type HoldQueue struct {
+	Slots int         // how many tetriminos can be held at once (0 for 1)
+	Held  []Tetrimino // the held tetriminos, with the one swapped in next first
+}
+
+// NewHoldQueue creates an empty queue with the given number of slots.
+func NewHoldQueue(slots int) HoldQueue {
+	return HoldQueue{Slots: max(slots, 1)}
+}
+
+// Swap puts the tetrimino on hold, and returns the one to play in its place: the one at the front of the queue if
+// every slot was filled, or false if there was a slot free and the next tetrimino should be dealt instead.
+func (q *HoldQueue) Swap(t Tetrimino) (Tetrimino, bool) {
+	q.Held = append(q.Held, t)
+	if len(q.Held) <= max(q.Slots, 1) {
+		return Tetrimino{}, false
+	}
+	next := q.Held[0]
+	q.Held = append(q.Held[:0:0], q.Held[1:]...)
+	return next, true
+}
+
+// Hold swaps the current tetrimino in the matrix for the one to play in its place, which it returns: the one at the
+// front of the queue if every slot is filled, or otherwise the next from the bag. The current tetrimino goes on hold
+// facing the way it spawns, so that it spawns as it was dealt when it is swapped back in.
+//
+// The swap is made all at once. If it can't be made, because the tetrimino swapped in has no room to spawn
+// (ErrBlockOut), the bag has run out (ErrEmptyBag) or the current tetrimino isn't in the matrix, the matrix, queue and
+// bag are left as they were.
+func (q *HoldQueue) Hold(matrix Matrix, current *Tetrimino, bag *Bag) (*Tetrimino, error) {
+	held, err := bag.Spawned(current.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tetrimino with value '%v': %w", current.Value, err)
+	}
+	next, swapped := q.Next()
+	in := &next
+	if !swapped {
+		var ok bool
+		if in, ok = bag.Peek(); !ok {
+			return nil, ErrEmptyBag
+		}
+	}
+
+	// The swap is tried on a copy of the matrix, so that it is left as it was if the swap can't be made.
+	after := matrix.Clone()
+	err = after.RemoveTetrimino(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove tetrimino: %w", err)
+	}
+	if after.AddTetrimino(in) != nil {
+		return nil, ErrBlockOut
+	}
+
+	for row := range matrix {
+		copy(matrix[row], after[row])
+	}
+	if _, ok := q.Swap(*held); !ok {
+		in = bag.Next()
+	}
+	return in, nil
+}
+
+// Next returns the tetrimino which the next hold would swap in, or false if there is a slot free, so it would deal the
+// next tetrimino instead.
+func (q HoldQueue) Next() (Tetrimino, bool) {
+	if len(q.Held) < max(q.Slots, 1) {
+		return Tetrimino{}, false
+	}
+	return q.Held[0], true
+}
+
+// Front returns the value of the tetrimino which has been held longest, or 0 if none is held.
+func (q HoldQueue) Front() byte {
+	if len(q.Held) == 0 {
+		return 0
+	}
+	return q.Held[0].Value
+}
+
+// Values returns the values of the held tetriminos, with the one swapped in next first.
+func (q HoldQueue) Values() string {
+	return values(q.Held)
+}
+
+// Copy returns a copy of the queue which shares none of its tetriminos.
+func (q HoldQueue) Copy() HoldQueue {
+	held := make([]Tetrimino, len(q.Held))
+	for i := range q.Held {
+		held[i] = *q.Held[i].Copy()
+	}
+	return HoldQueue{Slots: q.Slots, Held: held}
+}
@@ -0,0 +1,129 @@
+package tetris
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseHoldRule(t *testing.T) {
+	for _, rule := range []HoldRule{HoldOnce, HoldOff, HoldUnlimited} {
+		got, err := ParseHoldRule(rule.String())
+		if err != nil {
+			t.Fatalf("%v: expected nil, got error: %v", rule, err)
+		}
+		if got != rule {
+			t.Errorf("want %v, got %v", rule, got)
+		}
+	}
+	if _, err := ParseHoldRule("twice"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestHoldQueue_Swap(t *testing.T) {
+	q := NewHoldQueue(2)
+	tetrimino := func(v byte) Tetrimino { return Tetrimino{Value: v} }
+
+	for _, v := range []byte{'T', 'I'} {
+		if _, ok := q.Swap(tetrimino(v)); ok {
+			t.Fatalf("Hold %c: want the next tetrimino dealt while a slot is free, got a swap", v)
+		}
+	}
+	if _, ok := q.Next(); !ok || q.Values() != "TI" {
+		t.Fatalf("want TI held with T swapped in next, got %q", q.Values())
+	}
+
+	// Holding again and again cycles through the held tetriminos.
+	current := tetrimino('O')
+	for _, want := range []byte{'T', 'I', 'O', 'T'} {
+		next, ok := q.Swap(current)
+		if !ok || next.Value != want {
+			t.Fatalf("want %c swapped in, got %c (swapped %t)", want, next.Value, ok)
+		}
+		current = next
+	}
+
+	c := q.Copy()
+	c.Held[0].Value = 'Z'
+	if q.Front() == 'Z' {
+		t.Errorf("Copy: want the copy not to share tetriminos")
+	}
+	if NewHoldQueue(0).Slots != 1 {
+		t.Errorf("NewHoldQueue: want at least 1 slot")
+	}
+}
+
+func TestHoldQueue_Hold(t *testing.T) {
+	matrix := NewDefaultMatrix()
+	bag, err := NewFixedBag(matrix, []byte("TIO"))
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	current := bag.Next()
+	if err := matrix.AddTetrimino(current); err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	_, err = current.MoveDown(&matrix)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	q := NewHoldQueue(1)
+
+	// With the hold empty, the next tetrimino is dealt in place of the one held, which goes back to where it spawns.
+	next, err := q.Hold(matrix, current, bag)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if next.Value != 'I' || q.Values() != "T" || bag.Elements[0].Value != 'O' {
+		t.Fatalf("want I swapped in for T with O left in the bag, got %c for %q", next.Value, q.Values())
+	}
+	if want, _ := bag.SpawnPosition('T'); q.Held[0].Pos != want {
+		t.Errorf("want T held at its spawn position %v, got %v", want, q.Held[0].Pos)
+	}
+	expected := NewDefaultMatrix()
+	if err := expected.AddTetrimino(next); err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !matrix.Equal(expected) {
+		t.Fatalf("want only I in the matrix, got\n%v", matrix)
+	}
+
+	// A failed swap leaves the matrix, queue and bag as they were.
+	for _, tc := range []struct {
+		name    string
+		prepare func(m Matrix, q *HoldQueue, b *Bag, current *Tetrimino)
+		want    error
+	}{
+		{"block out", func(m Matrix, _ *HoldQueue, _ *Bag, _ *Tetrimino) {
+			// Fill the top row of where T spawns, above I, so that T has no room to be swapped back in.
+			row := m.BufferRows() - 2
+			for col := range m[row] {
+				m[row][col] = GarbageValue
+			}
+		}, ErrBlockOut},
+		{"empty bag", func(_ Matrix, q *HoldQueue, b *Bag, _ *Tetrimino) {
+			q.Slots = 2
+			b.Next()
+		}, ErrEmptyBag},
+		{"not in matrix", func(m Matrix, _ *HoldQueue, _ *Bag, current *Tetrimino) {
+			if err := m.RemoveTetrimino(current); err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+		}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, q, current := matrix.Clone(), q.Copy(), next.Copy()
+			b := *bag
+			tc.prepare(m, &q, &b, current)
+			before, queue, dealt := m.Clone(), q.Values(), len(b.Elements)
+
+			_, err := q.Hold(m, current, &b)
+			if err == nil || (tc.want != nil && !errors.Is(err, tc.want)) {
+				t.Fatalf("want error %v, got %v", tc.want, err)
+			}
+			if !m.Equal(before) || q.Values() != queue || len(b.Elements) != dealt {
+				t.Errorf("want the matrix, queue and bag unchanged, got\n%v\nholding %q with %d to deal", m, q.Values(), len(b.Elements))
+			}
+		})
+	}
+}
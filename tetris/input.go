@@ -0,0 +1,189 @@
+package tetris
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Move is a single player input.
+type Move int8
+
+const (
+	MoveLeft Move = iota
+	MoveRight
+	MoveClockwise
+	MoveCounterClockwise
+	MoveSoftDrop
+	MoveHardDrop
+	MoveHold
+)
+
+func (m Move) String() string {
+	switch m {
+	case MoveLeft:
+		return "left"
+	case MoveRight:
+		return "right"
+	case MoveClockwise:
+		return "clockwise"
+	case MoveCounterClockwise:
+		return "counter-clockwise"
+	case MoveSoftDrop:
+		return "soft drop"
+	case MoveHardDrop:
+		return "hard drop"
+	case MoveHold:
+		return "hold"
+	}
+	return fmt.Sprintf("Move(%d)", int8(m))
+}
+
+// ParseMove returns the move with the given name, as returned by String, such as "hard drop".
+func ParseMove(name string) (Move, error) {
+	for m := MoveLeft; m <= MoveHold; m++ {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid move %q", name)
+}
+
+// Mirror returns the move which does the same thing in a game mirrored from left to right.
+func (m Move) Mirror() Move {
+	switch m {
+	case MoveLeft:
+		return MoveRight
+	case MoveRight:
+		return MoveLeft
+	case MoveClockwise:
+		return MoveCounterClockwise
+	case MoveCounterClockwise:
+		return MoveClockwise
+	}
+	return m
+}
+
+// FrameDuration is the length of one frame of gameplay. The guideline targets 60 frames per second.
+const FrameDuration = time.Second / 60
+
+// ErrIllegalInput is returned when an input could not physically have been made by a player.
+var ErrIllegalInput = errors.New("illegal input")
+
+// InputChecker rejects input sequences that are impossible for a player using a single keyboard.
+// It is used to detect tool-assisted or corrupted input, where a strict set of rules is wanted.
+type InputChecker struct {
+	last     time.Duration
+	frame    int64
+	inFrame  []Move
+	rejected uint
+}
+
+func NewInputChecker() *InputChecker {
+	return &InputChecker{frame: -1}
+}
+
+// Check validates a move made at the given time since the start of the game.
+// An error wrapping ErrIllegalInput is returned if the move is not legal, in which case it should not be applied.
+func (c *InputChecker) Check(move Move, at time.Duration) error {
+	err := c.check(move, at)
+	if err != nil {
+		c.rejected++
+		return err
+	}
+
+	frame := int64(at / FrameDuration)
+	if frame != c.frame {
+		c.frame = frame
+		c.inFrame = c.inFrame[:0]
+	}
+	c.inFrame = append(c.inFrame, move)
+	c.last = at
+	return nil
+}
+
+func (c *InputChecker) check(move Move, at time.Duration) error {
+	if at < c.last {
+		return fmt.Errorf("%w: %v at %v is before the previous input at %v", ErrIllegalInput, move, at, c.last)
+	}
+	if int64(at/FrameDuration) != c.frame {
+		return nil
+	}
+
+	for _, prev := range c.inFrame {
+		switch {
+		case prev == move:
+			return fmt.Errorf("%w: %v pressed twice in one frame", ErrIllegalInput, move)
+		case isOpposite(prev, move):
+			return fmt.Errorf("%w: %v and %v pressed in one frame", ErrIllegalInput, prev, move)
+		case prev == MoveHardDrop:
+			// After a hard drop there is no tetrimino to move until the next one spawns on a later frame.
+			return fmt.Errorf("%w: %v in the same frame as a hard drop", ErrIllegalInput, move)
+		}
+	}
+	return nil
+}
+
+// Rejected returns the number of inputs that have been rejected.
+func (c *InputChecker) Rejected() uint {
+	return c.rejected
+}
+
+func isOpposite(a, b Move) bool {
+	switch {
+	case a == MoveLeft && b == MoveRight, a == MoveRight && b == MoveLeft:
+		return true
+	case a == MoveClockwise && b == MoveCounterClockwise, a == MoveCounterClockwise && b == MoveClockwise:
+		return true
+	}
+	return false
+}
+
+// InitialInputs buffers the hold, rotation and sideways inputs pressed while there is no tetrimino to control, such as
+// during the entry delay or a line clear, so that they are applied the instant the next one spawns rather than being
+// lost. Hold and rotation are the initial hold and rotation systems (IHS and IRS), which let a tetrimino be swapped or
+// turned before gravity can take it anywhere. Drops are never buffered, so that a drop pressed twice by mistake
+// doesn't also drop the next tetrimino.
+//
+// The zero value is an empty buffer.
+type InitialInputs struct {
+	hold     bool
+	rotation Move // the last rotation pressed, if rotate is set
+	rotate   bool
+	shifts   []Move // the sideways moves pressed, in order
+}
+
+// Buffer keeps the move to apply once the next tetrimino spawns, and reports whether it was kept. Only hold, rotations
+// and sideways moves are kept. A rotation replaces any pressed before it, and sideways moves are kept in order, up to
+// the MaxWidth it would take to cross the widest matrix.
+func (b *InitialInputs) Buffer(move Move) bool {
+	switch move {
+	case MoveHold:
+		b.hold = true
+	case MoveClockwise, MoveCounterClockwise:
+		b.rotation, b.rotate = move, true
+	case MoveLeft, MoveRight:
+		if len(b.shifts) >= MaxWidth {
+			return false
+		}
+		b.shifts = append(b.shifts, move)
+	default:
+		return false
+	}
+	return true
+}
+
+// Take empties the buffer, returning the moves to apply to the new tetrimino in order: hold first, so that the
+// rotation turns the tetrimino swapped in, and then the sideways moves, so that it is shifted as it will be placed.
+func (b *InitialInputs) Take() []Move {
+	var moves []Move
+	if b.hold {
+		moves = append(moves, MoveHold)
+	}
+	if b.rotate {
+		moves = append(moves, b.rotation)
+	}
+	moves = append(moves, b.shifts...)
+	*b = InitialInputs{}
+	return moves
+}
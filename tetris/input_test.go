@@ -0,0 +1,196 @@
+package tetris
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInputChecker_Check(t *testing.T) {
+	type input struct {
+		move Move
+		at   time.Duration
+	}
+
+	tt := []struct {
+		name             string
+		inputs           []input
+		expectsErr       bool
+		expectedRejected uint
+	}{
+		{
+			name: "separate frames",
+			inputs: []input{
+				{MoveLeft, 0},
+				{MoveRight, FrameDuration},
+				{MoveRight, 2 * FrameDuration},
+			},
+			expectsErr: false,
+		},
+		{
+			name: "different moves in one frame",
+			inputs: []input{
+				{MoveLeft, 0},
+				{MoveClockwise, time.Millisecond},
+			},
+			expectsErr: false,
+		},
+		{
+			name: "same move twice in one frame",
+			inputs: []input{
+				{MoveLeft, 0},
+				{MoveLeft, time.Millisecond},
+			},
+			expectsErr:       true,
+			expectedRejected: 1,
+		},
+		{
+			name: "left and right in one frame",
+			inputs: []input{
+				{MoveLeft, 0},
+				{MoveRight, time.Millisecond},
+			},
+			expectsErr:       true,
+			expectedRejected: 1,
+		},
+		{
+			name: "both rotations in one frame",
+			inputs: []input{
+				{MoveCounterClockwise, 0},
+				{MoveClockwise, 0},
+			},
+			expectsErr:       true,
+			expectedRejected: 1,
+		},
+		{
+			name: "rotation after hard drop in one frame",
+			inputs: []input{
+				{MoveHardDrop, 0},
+				{MoveClockwise, time.Millisecond},
+			},
+			expectsErr:       true,
+			expectedRejected: 1,
+		},
+		{
+			name: "out of order",
+			inputs: []input{
+				{MoveLeft, time.Second},
+				{MoveRight, 0},
+			},
+			expectsErr:       true,
+			expectedRejected: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewInputChecker()
+
+			var err error
+			for _, in := range tc.inputs {
+				err = c.Check(in.move, in.at)
+			}
+
+			if tc.expectsErr && !errors.Is(err, ErrIllegalInput) {
+				t.Errorf("expected ErrIllegalInput, got %v", err)
+			} else if !tc.expectsErr && err != nil {
+				t.Errorf("expected nil, got error: %v", err)
+			}
+			if c.Rejected() != tc.expectedRejected {
+				t.Errorf("Rejected: expected %d, got %d", tc.expectedRejected, c.Rejected())
+			}
+		})
+	}
+}
+
+func TestMove_Mirror(t *testing.T) {
+	tt := []struct {
+		move     Move
+		expected Move
+	}{
+		{MoveLeft, MoveRight},
+		{MoveRight, MoveLeft},
+		{MoveClockwise, MoveCounterClockwise},
+		{MoveCounterClockwise, MoveClockwise},
+		{MoveSoftDrop, MoveSoftDrop},
+		{MoveHardDrop, MoveHardDrop},
+		{MoveHold, MoveHold},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.move.String(), func(t *testing.T) {
+			if actual := tc.move.Mirror(); actual != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	for m := MoveLeft; m <= MoveHold; m++ {
+		got, err := ParseMove(m.String())
+		if err != nil {
+			t.Fatalf("%v: expected nil, got error: %v", m, err)
+		}
+		if got != m {
+			t.Errorf("want %v, got %v", m, got)
+		}
+	}
+	if _, err := ParseMove("spin"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestInitialInputs(t *testing.T) {
+	tt := []struct {
+		name     string
+		moves    []Move
+		expected []Move
+	}{
+		{"none", nil, nil},
+		{"hold", []Move{MoveHold}, []Move{MoveHold}},
+		{"rotation", []Move{MoveCounterClockwise}, []Move{MoveCounterClockwise}},
+		{"last rotation", []Move{MoveClockwise, MoveCounterClockwise}, []Move{MoveCounterClockwise}},
+		{"hold before rotation", []Move{MoveClockwise, MoveHold}, []Move{MoveHold, MoveClockwise}},
+		{"sideways moves", []Move{MoveLeft, MoveLeft, MoveRight}, []Move{MoveLeft, MoveLeft, MoveRight}},
+		{"sideways after rotation", []Move{MoveRight, MoveClockwise, MoveHold}, []Move{MoveHold, MoveClockwise, MoveRight}},
+		{"drops", []Move{MoveHardDrop, MoveSoftDrop}, nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var b InitialInputs
+			for _, move := range tc.moves {
+				buffered := b.Buffer(move)
+				if want := move != MoveHardDrop && move != MoveSoftDrop; buffered != want {
+					t.Errorf("Buffer(%v): want %t, got %t", move, want, buffered)
+				}
+			}
+			if actual := b.Take(); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("want %v, got %v", tc.expected, actual)
+			}
+			if actual := b.Take(); actual != nil {
+				t.Errorf("Take again: want none, got %v", actual)
+			}
+		})
+	}
+}
+
+func TestInitialInputs_ShiftLimit(t *testing.T) {
+	var b InitialInputs
+	for i := 0; i < MaxWidth; i++ {
+		if !b.Buffer(MoveRight) {
+			t.Fatalf("Buffer %d: want kept, got dropped", i)
+		}
+	}
+	if b.Buffer(MoveLeft) {
+		t.Errorf("Over the limit: want dropped, got kept")
+	}
+	if !b.Buffer(MoveClockwise) {
+		t.Errorf("Rotation over the limit: want kept, got dropped")
+	}
+	if actual := len(b.Take()); actual != MaxWidth+1 {
+		t.Errorf("want %d moves, got %d", MaxWidth+1, actual)
+	}
+}
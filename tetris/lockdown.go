@@ -0,0 +1,132 @@
+package tetris
+
+import (
+	"fmt"
+	"time"
+)
+
+// LockDown is a rule from the guideline for how long a tetrimino which has landed can be moved before it locks. Under
+// each, it locks once the lock delay has passed, and they differ only in what restarts the delay.
+type LockDown int8
+
+const (
+	// LockDownExtended restarts the delay each time the tetrimino is moved or rotated, but only ExtendedMoves times,
+	// after which it locks as soon as it is on the stack. The count starts again whenever the tetrimino falls below
+	// the lowest row it has reached. This is Extended Placement, the guideline's default.
+	LockDownExtended LockDown = iota
+	// LockDownInfinite restarts the delay each time the tetrimino is moved or rotated, without limit (Infinite
+	// Placement).
+	LockDownInfinite
+	// LockDownClassic only restarts the delay when the tetrimino falls below the lowest row it has reached, however it
+	// is moved.
+	LockDownClassic
+)
+
+// lockDownNames are the names of each rule, as used in the config file.
+var lockDownNames = []string{"extended", "infinite", "classic"}
+
+func (l LockDown) String() string {
+	if int(l) >= 0 && int(l) < len(lockDownNames) {
+		return lockDownNames[l]
+	}
+	return fmt.Sprintf("LockDown(%d)", int8(l))
+}
+
+// ParseLockDown returns the rule with the given name, such as "infinite".
+func ParseLockDown(name string) (LockDown, error) {
+	for i, n := range lockDownNames {
+		if n == name {
+			return LockDown(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid lock down %q", name)
+}
+
+// DefaultLockDelay is how long a landed tetrimino can be moved before it locks, as given by the guideline.
+const DefaultLockDelay = 500 * time.Millisecond
+
+// ExtendedMoves is the number of moves and rotations which restart the delay under LockDownExtended.
+const ExtendedMoves = 15
+
+// LockDelay decides when the current tetrimino locks after landing, under one of the lock-down rules. It must be told
+// where the tetrimino is whenever it moves or falls, and reset for each new tetrimino.
+type LockDelay struct {
+	rule   LockDown
+	delay  *Delay
+	moves  int  // moves and rotations which restarted the delay since the lowest row was reached
+	lowest int  // the lowest row the tetrimino has reached, where larger rows are lower
+	landed bool // whether the tetrimino was on the stack when last updated
+}
+
+func NewLockDelay(stopwatch *Stopwatch, rule LockDown, length time.Duration) *LockDelay {
+	return &LockDelay{rule: rule, delay: NewDelay(stopwatch, length)}
+}
+
+// SetLength changes how long the tetrimino can be moved after landing, such as when the level increases.
+func (l *LockDelay) SetLength(length time.Duration) {
+	l.delay.SetLength(length)
+}
+
+// Reset starts afresh for a tetrimino at the given row, such as one which has just spawned or been swapped in from
+// hold.
+func (l *LockDelay) Reset(row int) {
+	l.delay.Stop()
+	l.moves = 0
+	l.lowest = row
+	l.landed = false
+}
+
+// Update records the row the tetrimino is at and whether it is on the stack, after it has moved or fallen. The delay
+// starts when it lands, and starts again from the beginning once it lands below the lowest row it had reached.
+// Under LockDownClassic the delay keeps running if the tetrimino is moved off the stack without falling any lower.
+func (l *LockDelay) Update(row int, landed bool) {
+	l.landed = landed
+	if row > l.lowest {
+		l.lowest = row
+		l.moves = 0
+		l.delay.Stop()
+	}
+	if !landed {
+		if l.rule != LockDownClassic {
+			l.delay.Stop()
+		}
+		return
+	}
+	if !l.delay.Running() {
+		l.delay.Start()
+	}
+}
+
+// Moved records that the player moved or rotated the tetrimino, restarting the delay if it has landed and the rule
+// allows it. Update should be called afterwards, since the move may have taken the tetrimino off the stack.
+func (l *LockDelay) Moved() {
+	if !l.landed || !l.delay.Running() {
+		return
+	}
+	switch l.rule {
+	case LockDownInfinite:
+		l.delay.Start()
+	case LockDownExtended:
+		l.moves++
+		if l.moves < ExtendedMoves {
+			l.delay.Start()
+		}
+	}
+}
+
+// Remaining returns the time left before the tetrimino locks, or 0 if it is not on the stack.
+func (l *LockDelay) Remaining() time.Duration {
+	if !l.landed {
+		return 0
+	}
+	return l.delay.Remaining()
+}
+
+// Expired reports whether the tetrimino should lock now: it is on the stack, and either the delay has passed or it
+// has no moves left to restart it under LockDownExtended.
+func (l *LockDelay) Expired() bool {
+	if !l.landed || !l.delay.Running() {
+		return false
+	}
+	return l.delay.Expired() || l.rule == LockDownExtended && l.moves >= ExtendedMoves
+}
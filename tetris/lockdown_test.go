@@ -0,0 +1,144 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockDelay(t *testing.T) {
+	// The tetrimino lands on the stack at row 10, then is moved along it every 100ms.
+	tt := []struct {
+		name          string
+		rule          LockDown
+		moves         int
+		expectedAfter time.Duration // how long after the last move the tetrimino locks (0 for straight away)
+	}{
+		{"extended, no moves", LockDownExtended, 0, 500 * time.Millisecond},
+		{"extended, some moves", LockDownExtended, 10, 500 * time.Millisecond},
+		{"extended, out of moves", LockDownExtended, ExtendedMoves, 0},
+		{"infinite", LockDownInfinite, 30, 500 * time.Millisecond},
+		{"classic, no moves", LockDownClassic, 0, 500 * time.Millisecond},
+		{"classic, some moves", LockDownClassic, 3, 300 * time.Millisecond},
+		{"classic, many moves", LockDownClassic, 5, 100 * time.Millisecond},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Unix(0, 0))
+			s := NewStopwatch(clock)
+			l := NewLockDelay(s, tc.rule, DefaultLockDelay)
+			s.Start()
+
+			l.Reset(0)
+			l.Update(10, true)
+			for i := 0; i < tc.moves; i++ {
+				if i > 0 {
+					clock.Advance(100 * time.Millisecond)
+				}
+				if l.Expired() {
+					t.Fatalf("Move %d: want not expired", i+1)
+				}
+				l.Moved()
+				l.Update(10, true)
+			}
+
+			if tc.expectedAfter == 0 {
+				if !l.Expired() {
+					t.Errorf("want expired straight away")
+				}
+				return
+			}
+			clock.Advance(tc.expectedAfter - time.Millisecond)
+			if l.Expired() {
+				t.Errorf("Before delay: want not expired")
+			}
+			clock.Advance(time.Millisecond)
+			if !l.Expired() {
+				t.Errorf("After delay: want expired")
+			}
+		})
+	}
+}
+
+func TestLockDelay_FallLower(t *testing.T) {
+	for _, rule := range []LockDown{LockDownExtended, LockDownClassic} {
+		t.Run(rule.String(), func(t *testing.T) {
+			clock := NewManualClock(time.Unix(0, 0))
+			s := NewStopwatch(clock)
+			l := NewLockDelay(s, rule, DefaultLockDelay)
+			s.Start()
+
+			l.Reset(0)
+			l.Update(10, true)
+			for i := 0; i < ExtendedMoves-1; i++ {
+				l.Moved()
+			}
+			clock.Advance(400 * time.Millisecond)
+
+			// Moving off the ledge lets the tetrimino fall to a lower row, where it has the whole delay again.
+			l.Moved()
+			l.Update(10, false)
+			if l.Expired() {
+				t.Fatalf("Off the stack: want not expired")
+			}
+			l.Update(12, true)
+			clock.Advance(DefaultLockDelay - time.Millisecond)
+			l.Moved()
+			if l.Expired() {
+				t.Errorf("Lower: want not expired")
+			}
+		})
+	}
+}
+
+func TestLockDelay_Airborne(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	l := NewLockDelay(s, LockDownInfinite, DefaultLockDelay)
+	s.Start()
+
+	l.Reset(0)
+	l.Update(5, false)
+	clock.Advance(time.Second)
+	if l.Expired() {
+		t.Errorf("Falling: want not expired")
+	}
+	l.Update(10, true)
+	if l.Expired() {
+		t.Errorf("Just landed: want not expired")
+	}
+}
+
+func TestParseLockDown(t *testing.T) {
+	for _, rule := range []LockDown{LockDownExtended, LockDownInfinite, LockDownClassic} {
+		got, err := ParseLockDown(rule.String())
+		if err != nil {
+			t.Fatalf("%v: expected nil, got error: %v", rule, err)
+		}
+		if got != rule {
+			t.Errorf("want %v, got %v", rule, got)
+		}
+	}
+	if _, err := ParseLockDown("step"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestLockDelay_Remaining(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewStopwatch(clock)
+	l := NewLockDelay(s, LockDownExtended, DefaultLockDelay)
+	s.Start()
+	l.Reset(0)
+
+	l.Update(5, false)
+	if got := l.Remaining(); got != 0 {
+		t.Errorf("Airborne: want 0, got %v", got)
+	}
+
+	l.Update(10, true)
+	clock.Advance(200 * time.Millisecond)
+	if got := l.Remaining(); got != 300*time.Millisecond {
+		t.Errorf("Landed: want %v, got %v", 300*time.Millisecond, got)
+	}
+}
@@ -1,10 +1,202 @@
 package tetris
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
 
-type Matrix [40][10]byte
+// Matrix is the playfield, as rows of cells from the top down. Only the bottom half of the rows are visible to the
+// player. The rest (the buffer zone) are where tetriminos spawn, in the two rows just above the visible ones, and
+// where the stack can grow into before topping out. The game is topped out when a tetrimino can't spawn (block out),
+// when one locks entirely within the buffer zone (lock out), or when garbage pushes the stack out of the top of the
+// buffer zone.
+//
+// Rows are slices, so assigning a matrix shares its cells. Use Clone for a copy which can be changed separately.
+type Matrix [][]byte
 
-func (p *Matrix) isLineComplete(row int) bool {
+const (
+	// DefaultWidth and DefaultHeight are the size of the matrix given by the guideline: 10 columns and 20 visible rows.
+	DefaultWidth  = 10
+	DefaultHeight = 20
+
+	// MaxWidth is the widest a matrix can be made. It is also the furthest a tetrimino can move in one go.
+	MaxWidth = 20
+	// MaxHeight is the most visible rows a matrix can be made with.
+	MaxHeight = 40
+)
+
+// NewMatrix creates an empty matrix which is width columns wide, with height rows visible and as many again hidden
+// above them.
+func NewMatrix(width, height int) Matrix {
+	p := make(Matrix, height*2)
+	for row := range p {
+		p[row] = make([]byte, width)
+	}
+	return p
+}
+
+// NewDefaultMatrix creates an empty matrix of the size given by the guideline.
+func NewDefaultMatrix() Matrix {
+	return NewMatrix(DefaultWidth, DefaultHeight)
+}
+
+// Clone returns a copy of the matrix which shares none of its cells.
+func (p Matrix) Clone() Matrix {
+	c := make(Matrix, len(p))
+	for row := range p {
+		c[row] = slices.Clone(p[row])
+	}
+	return c
+}
+
+// Equal reports whether the matrices are the same size and have the same cells.
+func (p Matrix) Equal(other Matrix) bool {
+	return slices.EqualFunc(p, other, slices.Equal[[]byte])
+}
+
+// IsEmpty reports whether every cell of the matrix is empty.
+func (p Matrix) IsEmpty() bool {
+	for row := range p {
+		for _, cell := range p[row] {
+			if !isCellEmpty(cell) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// VisibleRows returns the number of rows at the bottom of the matrix which are visible to the player.
+func (p Matrix) VisibleRows() int {
+	return len(p) / 2
+}
+
+// BufferRows returns the number of rows at the top of the matrix which make up the buffer zone, hidden from the
+// player. It is also the index of the first visible row.
+func (p Matrix) BufferRows() int {
+	return len(p) - p.VisibleRows()
+}
+
+// Visible returns the rows of the matrix which are visible to the player, sharing their cells.
+func (p Matrix) Visible() Matrix {
+	return p[p.BufferRows():]
+}
+
+// IsLockOut reports whether every mino of the tetrimino is within the buffer zone, so that locking it where it is
+// tops out (lock out).
+func (p Matrix) IsLockOut(t *Tetrimino) bool {
+	for row := len(t.Cells) - 1; row >= 0; row-- {
+		if slices.Contains(t.Cells[row], true) {
+			return t.Pos.Y+row < p.BufferRows()
+		}
+	}
+	return false
+}
+
+// Height returns the number of rows from the bottom of the matrix up to and including its highest filled cell.
+func (p Matrix) Height() int {
+	for row := range p {
+		for _, cell := range p[row] {
+			if !isCellEmpty(cell) {
+				return len(p) - row
+			}
+		}
+	}
+	return 0
+}
+
+// SpawnOffset returns how far tetriminos spawn from their starting positions, which are given for the top left of
+// the visible rows of a matrix of the default width. They are moved along to stay centred, and down past the buffer
+// zone, so that they spawn just above the visible rows.
+func (p Matrix) SpawnOffset() Coordinate {
+	var width int
+	if len(p) > 0 {
+		width = len(p[0])
+	}
+	return Coordinate{X: (width - DefaultWidth) / 2, Y: p.BufferRows()}
+}
+
+// ScaledSpawnOffset returns how far tetriminos scaled by the given amount (see Tetrimino.Scaled) spawn from their
+// starting positions, as SpawnOffset does for those which aren't.
+func (p Matrix) ScaledSpawnOffset(scale int) Coordinate {
+	offset := p.SpawnOffset()
+	if scale > 1 {
+		offset.X -= DefaultWidth * (scale - 1) / 2
+	}
+	return offset
+}
+
+// MarshalJSON encodes the matrix as an array of rows, each an array of cell values.
+func (p Matrix) MarshalJSON() ([]byte, error) {
+	rows := make([][]int, len(p))
+	for row := range p {
+		rows[row] = make([]int, len(p[row]))
+		for col, cell := range p[row] {
+			rows[row][col] = int(cell)
+		}
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON decodes a matrix encoded by MarshalJSON. Every row must be the same width, and an empty array is
+// decoded as a nil matrix.
+func (p *Matrix) UnmarshalJSON(data []byte) error {
+	var rows [][]int
+	err := json.Unmarshal(data, &rows)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		*p = nil
+		return nil
+	}
+
+	m := make(Matrix, len(rows))
+	for row := range rows {
+		if len(rows[row]) != len(rows[0]) {
+			return fmt.Errorf("row %d has %d cells, expected %d", row+1, len(rows[row]), len(rows[0]))
+		}
+		m[row] = make([]byte, len(rows[row]))
+		for col, cell := range rows[row] {
+			if cell < 0 || cell > 255 {
+				return fmt.Errorf("row %d has invalid cell %d", row+1, cell)
+			}
+			m[row][col] = byte(cell)
+		}
+	}
+	*p = m
+	return nil
+}
+
+// IsCellEmpty reports whether the cell at the given row and column is empty (or only contains a ghost piece).
+func (p Matrix) IsCellEmpty(row, col int) bool {
+	return isCellEmpty(p[row][col])
+}
+
+// SetCell fills the cell at the given row and column with the value of a tetrimino or garbage, or empties it with
+// 0, as when editing a board by hand.
+func (p Matrix) SetCell(row, col int, value byte) error {
+	if row < 0 || row >= len(p) {
+		return fmt.Errorf("row %d is out of bounds", row)
+	}
+	if col < 0 || col >= len(p[row]) {
+		return fmt.Errorf("col %d is out of bounds", col)
+	}
+	if value != 0 && value != GarbageValue && !isTetriminoValue(value) {
+		return fmt.Errorf("invalid cell value %q", value)
+	}
+	p[row][col] = value
+	return nil
+}
+
+// CanAddTetrimino reports whether the tetrimino fits within the matrix at its current position without overlapping any filled cells.
+func (p Matrix) CanAddTetrimino(tetrimino *Tetrimino) bool {
+	return tetrimino.canRotate(p)
+}
+
+func (p Matrix) isLineComplete(row int) bool {
 	for _, cell := range p[row] {
 		if isCellEmpty(cell) {
 			return false
@@ -13,14 +205,16 @@ func (p *Matrix) isLineComplete(row int) bool {
 	return true
 }
 
-func (p *Matrix) removeLine(row int) {
-	p[0] = [10]byte{}
-	for i := row; i > 0; i-- {
-		p[i] = p[i-1]
-	}
+// removeLine removes the row, moving the rows above it down and adding an empty row at the top. The removed row is
+// reused for the new one, since the rows above can't be copied down without sharing their cells.
+func (p Matrix) removeLine(row int) {
+	removed := p[row]
+	copy(p[1:row+1], p[:row])
+	clear(removed)
+	p[0] = removed
 }
 
-func (p *Matrix) RemoveTetrimino(tetrimino *Tetrimino) error {
+func (p Matrix) RemoveTetrimino(tetrimino *Tetrimino) error {
 	for row := range tetrimino.Cells {
 		for col := range tetrimino.Cells[row] {
 			if tetrimino.Cells[row][col] {
@@ -42,7 +236,7 @@ func (p *Matrix) RemoveTetrimino(tetrimino *Tetrimino) error {
 	return nil
 }
 
-func (p *Matrix) AddTetrimino(tetrimino *Tetrimino) error {
+func (p Matrix) AddTetrimino(tetrimino *Tetrimino) error {
 	for row := range tetrimino.Cells {
 		for col := range tetrimino.Cells[row] {
 			if tetrimino.Cells[row][col] {
@@ -64,26 +258,137 @@ func (p *Matrix) AddTetrimino(tetrimino *Tetrimino) error {
 	return nil
 }
 
-func (p *Matrix) RemoveCompletedLines(tet *Tetrimino) action {
-	lines := 0
+// CompletedLines returns the rows spanned by the tetrimino which are complete, from top to bottom.
+func (p Matrix) CompletedLines(tet *Tetrimino) []int {
+	var rows []int
 	for row := range tet.Cells {
 		if p.isLineComplete(tet.Pos.Y + row) {
-			p.removeLine(tet.Pos.Y + row)
-			lines++
+			rows = append(rows, tet.Pos.Y+row)
+		}
+	}
+	return rows
+}
+
+// RemoveCompletedLines removes the lines completed by the tetrimino, returning the action it scores, as ClearLines
+// does for a lock which isn't a T-spin.
+func (p Matrix) RemoveCompletedLines(tet *Tetrimino) action {
+	return p.ClearLines(tet, SpinNone)
+}
+
+// ClearLines removes the lines completed by the tetrimino, returning the action it scores for clearing them with the
+// T-spin it made (see TSpin).
+func (p Matrix) ClearLines(tet *Tetrimino, spin Spin) action {
+	// Rows are removed from the top down, so removing one does not move those below it that are still to be removed.
+	rows := p.CompletedLines(tet)
+	for _, row := range rows {
+		p.removeLine(row)
+	}
+
+	// A scaled tetrimino clears a line for each row of its minos, however many rows of cells that is.
+	return clearAction((len(rows)+tet.step()-1)/tet.step(), spin)
+}
+
+// ParseMatrix reads a matrix of the default size from its text format, as produced by String.
+// Each line is a row of the matrix, using '.' for empty cells and the tetrimino value (or 'X' for garbage) of filled cells.
+// Rows are aligned to the bottom of the matrix, so only the filled part of the stack needs to be given.
+// Blank lines and lines starting with '#' are ignored.
+func ParseMatrix(text string) (Matrix, error) {
+	var rows []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rows = append(rows, line)
+	}
+
+	m := NewDefaultMatrix()
+	if len(rows) > len(m) {
+		return m, fmt.Errorf("matrix has %d rows, the maximum is %d", len(rows), len(m))
+	}
+
+	offset := len(m) - len(rows)
+	for i, line := range rows {
+		if len(line) != len(m[0]) {
+			return m, fmt.Errorf("row %d has %d cells, expected %d", i+1, len(line), len(m[0]))
+		}
+		for col := range line {
+			value := line[col]
+			switch {
+			case value == '.':
+				value = 0
+			case value == GarbageValue:
+			case isTetriminoValue(value):
+			default:
+				return m, fmt.Errorf("row %d has invalid cell '%c'", i+1, value)
+			}
+			m[offset+i][col] = value
 		}
 	}
+	return m, nil
+}
 
-	switch lines {
-	case 0:
-		return actionNone
-	case 1:
-		return actionSingle
-	case 2:
-		return actionDouble
-	case 3:
-		return actionTriple
-	case 4:
-		return actionTetris
+// String returns the text format of the matrix, from the highest row containing a filled cell down to the bottom.
+func (p Matrix) String() string {
+	top := len(p)
+	for row := range p {
+		for col := range p[row] {
+			if !isCellEmpty(p[row][col]) {
+				top = row
+				break
+			}
+		}
+		if top != len(p) {
+			break
+		}
+	}
+
+	var b strings.Builder
+	for row := top; row < len(p); row++ {
+		for col := range p[row] {
+			if isCellEmpty(p[row][col]) {
+				b.WriteByte('.')
+			} else {
+				b.WriteByte(p[row][col])
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Mirror flips the matrix from left to right, swapping the tetriminos which are mirror images of each other (S and Z,
+// J and L) so that the mirrored stack could have been built from the mirrored moves.
+func (p Matrix) Mirror() {
+	for row := range p {
+		cells := p[row]
+		for i, j := 0, len(cells)-1; i <= j; i, j = i+1, j-1 {
+			cells[i], cells[j] = MirrorValue(cells[j]), MirrorValue(cells[i])
+		}
+	}
+}
+
+// MirrorValue returns the value of the tetrimino which is the mirror image of the tetrimino with the given value.
+// Other values, such as garbage and the symmetrical tetriminos, are returned unchanged.
+func MirrorValue(value byte) byte {
+	switch value {
+	case 'S':
+		return 'Z'
+	case 'Z':
+		return 'S'
+	case 'J':
+		return 'L'
+	case 'L':
+		return 'J'
+	}
+	return value
+}
+
+func isTetriminoValue(value byte) bool {
+	for _, t := range Tetriminos {
+		if t.Value == value {
+			return true
+		}
 	}
-	return actionNone
+	return false
 }
@@ -1,20 +1,33 @@
 package tetris
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
-func TestMatrix_IsLineComplete(t *testing.T) {
-	m := &Matrix{
-		[10]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		[10]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		[10]byte{0, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		[10]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
-		[10]byte{1, 1, 1, 1, 0, 1, 1, 0, 0, 1},
-		[10]byte{1, 1, 0, 1, 1, 1, 1, 1, 1, 1},
+// padded returns a matrix of the default size with the rows of m at the top, filling out short rows and the rows
+// after them with empty cells.
+func padded(m Matrix) Matrix {
+	p := NewDefaultMatrix()
+	for row := range m {
+		copy(p[row], m[row])
 	}
+	return p
+}
+
+func TestMatrix_IsLineComplete(t *testing.T) {
+	m := padded(Matrix{
+		[]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		[]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
+		[]byte{1, 1, 1, 1, 0, 1, 1, 0, 0, 1},
+		[]byte{1, 1, 0, 1, 1, 1, 1, 1, 1, 1},
+	})
 
 	// these test cases correspond to the rows in the matrix above
 	tt := []struct {
@@ -39,21 +52,21 @@ func TestMatrix_IsLineComplete(t *testing.T) {
 }
 
 func TestMatrix_RemoveLine(t *testing.T) {
-	testRows := Matrix{
-		[10]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		[10]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		[10]byte{0, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		[10]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
-		[10]byte{1, 1, 1, 1, 0, 1, 1, 0, 0, 1},
-		[10]byte{1, 1, 0, 1, 1, 1, 1, 1, 1, 1},
-	}
+	testRows := padded(Matrix{
+		[]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		[]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
+		[]byte{1, 1, 1, 1, 0, 1, 1, 0, 0, 1},
+		[]byte{1, 1, 0, 1, 1, 1, 1, 1, 1, 1},
+	})
 
 	for rowIndex := range testRows {
 		t.Run(fmt.Sprint("row ", rowIndex), func(t *testing.T) {
-			m := Matrix{}
-			m[rowIndex] = testRows[rowIndex]
+			m := NewDefaultMatrix()
+			copy(m[rowIndex], testRows[rowIndex])
 
-			if m[rowIndex] != testRows[rowIndex] {
+			if !slices.Equal(m[rowIndex], testRows[rowIndex]) {
 				t.Errorf("Before: expected %v, got %v", testRows[rowIndex], m[rowIndex])
 			}
 
@@ -76,7 +89,7 @@ func TestMatrix_RemoveTetrimino(t *testing.T) {
 		for _, mRow := range matrixRows {
 			for _, mCol := range matrixCols {
 				t.Run(fmt.Sprintf("Tetrimino %s, row %d, col %d", string(tet.Value), mRow, mCol), func(t *testing.T) {
-					m := Matrix{}
+					m := NewDefaultMatrix()
 					tet.Pos = Coordinate{mCol, mRow}
 
 					err := m.AddTetrimino(&tet)
@@ -110,9 +123,9 @@ func TestMatrix_AddTetrimino(t *testing.T) {
 			t.Run(fmt.Sprintf("Tetrimino %s, error %t", string(tet.Value), expectsErr), func(t *testing.T) {
 				tet.Pos = Coordinate{0, 0}
 
-				m := Matrix{}
+				m := NewDefaultMatrix()
 				if expectsErr {
-					m[0] = [10]byte{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'}
+					copy(m[0], []byte{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'})
 				}
 
 				err := m.AddTetrimino(&tet)
@@ -139,111 +152,111 @@ func TestMatrix_AddTetrimino(t *testing.T) {
 func TestMatrix_RemoveCompletedLines(t *testing.T) {
 	tt := []struct {
 		name           string
-		matrix         *Matrix
+		matrix         Matrix
 		posY           int
 		cells          [][]bool
 		expectedAction action
-		expectedMatrix *Matrix
+		expectedMatrix Matrix
 	}{
 		{
 			"0 lines, height 1",
-			&Matrix{
+			padded(Matrix{
 				{},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			0,
 			[][]bool{{}},
 			actionNone,
-			&Matrix{
+			padded(Matrix{
 				{},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 		},
 		{
 			"1 line, height 1",
-			&Matrix{
+			padded(Matrix{
 				{},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			1,
 			[][]bool{{}},
 			actionSingle,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 		{
 			"1 line, height 1",
-			&Matrix{
+			padded(Matrix{
 				{},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			0,
 			[][]bool{{}, {}},
 			actionSingle,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 		{
 			"2 lines, height 2",
-			&Matrix{
+			padded(Matrix{
 				{},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			1,
 			[][]bool{{}, {}},
 			actionDouble,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 		{
 			"2 lines, height 4",
-			&Matrix{
+			padded(Matrix{
 				{},
 				{},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			0,
 			[][]bool{{}, {}, {}, {}},
 			actionDouble,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 		{
 			"3 lines",
-			&Matrix{
+			padded(Matrix{
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			0,
 			[][]bool{{}, {}, {}},
 			actionTriple,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 		{
 			"4 lines",
-			&Matrix{
+			padded(Matrix{
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			0,
 			[][]bool{{}, {}, {}, {}},
 			actionTetris,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 		{
 			"5 lines (unknown action)",
-			&Matrix{
+			padded(Matrix{
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
 				{'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X', 'X'},
-			},
+			}),
 			0,
 			[][]bool{{}, {}, {}, {}, {}},
 			actionNone,
-			&Matrix{},
+			NewDefaultMatrix(),
 		},
 	}
 
@@ -266,3 +279,304 @@ func TestMatrix_RemoveCompletedLines(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMatrix(t *testing.T) {
+	tt := []struct {
+		name       string
+		text       string
+		expected   Matrix
+		expectsErr bool
+	}{
+		{
+			name:     "empty",
+			text:     "",
+			expected: NewDefaultMatrix(),
+		},
+		{
+			name: "bottom aligned",
+			text: `
+				# a comment
+				....T.....
+				XXXTTT.XXX
+			`,
+			expected: func() Matrix {
+				m := NewDefaultMatrix()
+				copy(m[38], []byte{0, 0, 0, 0, 'T'})
+				copy(m[39], []byte{'X', 'X', 'X', 'T', 'T', 'T', 0, 'X', 'X', 'X'})
+				return m
+			}(),
+		},
+		{
+			name:       "wrong width",
+			text:       "XXXX",
+			expectsErr: true,
+		},
+		{
+			name:       "invalid cell",
+			text:       "XXXX?XXXXX",
+			expectsErr: true,
+		},
+		{
+			name:       "too many rows",
+			text:       strings.Repeat("..........\n", 41),
+			expectsErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMatrix(tc.text)
+
+			if tc.expectsErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if !m.Equal(tc.expected) {
+				t.Errorf("Matrix: want %v, got %v", tc.expected, m)
+			}
+		})
+	}
+}
+
+func TestMatrix_String(t *testing.T) {
+	m := NewDefaultMatrix()
+	copy(m[38], []byte{0, 0, 0, 0, 'T', 'G'})
+	copy(m[39], []byte{'X', 'X', 'X', 'T', 'T', 'T', 0, 'X', 'X', 'X'})
+
+	expected := "....T.....\nXXXTTT.XXX\n"
+	if s := m.String(); s != expected {
+		t.Errorf("want %q, got %q", expected, s)
+	}
+
+	parsed, err := ParseMatrix(m.String())
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	m[38][5] = 0
+	if !parsed.Equal(m) {
+		t.Errorf("Round trip: want %v, got %v", m, parsed)
+	}
+}
+
+func TestMatrix_Mirror(t *testing.T) {
+	m, err := ParseMatrix(`
+		S.........
+		SS...J....
+		.S...JJJ.X
+	`)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	expected, err := ParseMatrix(`
+		.........Z
+		....L...ZZ
+		X.LLL...Z.
+	`)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	m.Mirror()
+	if !m.Equal(expected) {
+		t.Errorf("want\n%v\ngot\n%v", expected.String(), m.String())
+	}
+
+	m.Mirror()
+	m.Mirror()
+	if !m.Equal(expected) {
+		t.Errorf("Double mirror: want\n%v\ngot\n%v", expected.String(), m.String())
+	}
+}
+
+func TestNewMatrix(t *testing.T) {
+	tt := []struct {
+		name          string
+		width, height int
+		expectedSpawn Coordinate
+	}{
+		{"default", DefaultWidth, DefaultHeight, Coordinate{X: 0, Y: 20}},
+		{"narrow and short", 8, 16, Coordinate{X: -1, Y: 16}},
+		{"wide and tall", 14, 24, Coordinate{X: 2, Y: 24}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMatrix(tc.width, tc.height)
+
+			if len(m) != tc.height*2 {
+				t.Errorf("Rows: want %d, got %d", tc.height*2, len(m))
+			}
+			for row := range m {
+				if len(m[row]) != tc.width {
+					t.Fatalf("Row %d: want %d cells, got %d", row, tc.width, len(m[row]))
+				}
+			}
+			if v := m.VisibleRows(); v != tc.height {
+				t.Errorf("Visible rows: want %d, got %d", tc.height, v)
+			}
+			if b := m.BufferRows(); b != tc.height {
+				t.Errorf("Buffer rows: want %d, got %d", tc.height, b)
+			}
+			if v := m.Visible(); len(v) != tc.height || &v[0][0] != &m[tc.height][0] {
+				t.Errorf("Visible: want the bottom %d rows, got %d rows", tc.height, len(v))
+			}
+			if s := m.SpawnOffset(); s != tc.expectedSpawn {
+				t.Errorf("Spawn offset: want %v, got %v", tc.expectedSpawn, s)
+			}
+		})
+	}
+}
+
+func TestMatrix_SpawnRows(t *testing.T) {
+	m := NewDefaultMatrix()
+	offset := m.SpawnOffset()
+	for _, tet := range Tetriminos {
+		t.Run(string(tet.Value), func(t *testing.T) {
+			tet := tet.Copy()
+			tet.Pos.X += offset.X
+			tet.Pos.Y += offset.Y
+
+			// Rows are counted from 1 at the bottom, so the buffer zone starts at row 21.
+			for row := range tet.Cells {
+				if !slices.Contains(tet.Cells[row], true) {
+					continue
+				}
+				if fromBottom := len(m) - (tet.Pos.Y + row); fromBottom < 21 || fromBottom > 22 {
+					t.Errorf("want minos in rows 21 to 22, got row %d", fromBottom)
+				}
+			}
+			if !m.CanAddTetrimino(tet) {
+				t.Errorf("want room to spawn in an empty matrix")
+			}
+		})
+	}
+}
+
+func TestMatrix_IsLockOut(t *testing.T) {
+	m := NewDefaultMatrix()
+	tt := []struct {
+		name     string
+		y        int
+		expected bool
+	}{
+		{"spawn rows", 18, true},
+		{"top of buffer zone", 0, true},
+		{"partly visible", 19, false},
+		{"fully visible", 20, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tet := Tetriminos[2].Copy() // T, two rows tall
+			tet.Pos.Y = tc.y
+			if got := m.IsLockOut(tet); got != tc.expected {
+				t.Errorf("want %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatrix_Height(t *testing.T) {
+	m := NewDefaultMatrix()
+	if got := m.Height(); got != 0 {
+		t.Errorf("Empty: want 0, got %d", got)
+	}
+	m[39][0] = 'X'
+	m[36][9] = 'T'
+	if got := m.Height(); got != 4 {
+		t.Errorf("Stack: want 4, got %d", got)
+	}
+}
+
+func TestMatrix_Clone(t *testing.T) {
+	m := NewMatrix(8, 16)
+	m[31][0] = 'X'
+
+	c := m.Clone()
+	if !c.Equal(m) {
+		t.Fatalf("want %v, got %v", m, c)
+	}
+	c[31][1] = 'T'
+	if m[31][1] != 0 {
+		t.Errorf("Original: expected to be unchanged by the clone")
+	}
+}
+
+func TestMatrix_JSON(t *testing.T) {
+	m := NewMatrix(8, 16)
+	m[31] = []byte{'X', 'X', 'T', 'T', 'T', 0, 'X', 'X'}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	var decoded Matrix
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !decoded.Equal(m) {
+		t.Errorf("Round trip: want %v, got %v", m, decoded)
+	}
+
+	err = json.Unmarshal([]byte("[[0, 0], [0]]"), &decoded)
+	if err == nil {
+		t.Errorf("Uneven rows: expected error, got nil")
+	}
+}
+
+func TestMatrix_SetCell(t *testing.T) {
+	tt := []struct {
+		name    string
+		row     int
+		col     int
+		value   byte
+		wantErr bool
+	}{
+		{"garbage", 39, 0, GarbageValue, false},
+		{"tetrimino", 20, 9, 'T', false},
+		{"empty", 39, 1, 0, false},
+		{"row out of bounds", 40, 0, GarbageValue, true},
+		{"col out of bounds", 39, -1, GarbageValue, true},
+		{"invalid value", 39, 0, 'Q', true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewDefaultMatrix()
+			m[39][1] = 'X'
+			err := m.SetCell(tc.row, tc.col, tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if m[tc.row][tc.col] != tc.value {
+				t.Errorf("want %v, got %v", tc.value, m[tc.row][tc.col])
+			}
+		})
+	}
+}
+
+func TestMatrix_RemoveCompletedLines_Scaled(t *testing.T) {
+	matrix := NewMatrix(4, 4)
+	for row := range matrix {
+		for col := range matrix[row] {
+			matrix[row][col] = 'X'
+		}
+	}
+	tet := &Tetrimino{Cells: [][]bool{{}, {}, {}, {}, {}, {}}, Pos: Coordinate{Y: 2}, Scale: 2}
+
+	if got := matrix.RemoveCompletedLines(tet); got != actionTriple {
+		t.Errorf("want 6 rows of big minos cleared as a triple, got %v", got)
+	}
+}
@@ -0,0 +1,234 @@
+package tetris
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Randomizer decides the order tetriminos are dealt in. Each call to Deal returns one or more tetriminos, chosen from
+// the given choices, to be added to the end of the queue.
+type Randomizer interface {
+	Deal(choices []Tetrimino) []Tetrimino
+}
+
+// RandomizerNames are the names of each randomizer, as used in the config file:
+//   - "bag" deals bags of seven, as described by the guideline.
+//   - "bag14" deals bags of fourteen, with two of each tetrimino.
+//   - "random" picks every tetrimino at random, regardless of those dealt before.
+//   - "nes" deals tetriminos like the NES version.
+//   - "tgm" deals tetriminos like Tetris The Grand Master 2, avoiding the last four dealt.
+var RandomizerNames = []string{"bag", "bag14", "random", "nes", "tgm"}
+
+// NewRandomizer creates the randomizer with the given name (see RandomizerNames), always dealing the same tetriminos
+// for a given seed.
+func NewRandomizer(name string, seed int64) (Randomizer, error) {
+	if !slices.Contains(RandomizerNames, name) {
+		return nil, fmt.Errorf("invalid randomizer %q", name)
+	}
+	return RestoreRandomizer(RandomizerState{Kind: name, Seed: seed, Previous: -1})
+}
+
+// RandomizerState is the state of a seeded randomizer, from which it can be restored to deal the same tetriminos.
+type RandomizerState struct {
+	Kind  string `json:"kind"` // the name of the randomizer, such as "bag" (see RandomizerNames)
+	Seed  int64  `json:"seed"`
+	Draws uint64 `json:"draws"` // number of values drawn from the source of random numbers since it was seeded
+
+	// Previous is the index of the previous tetrimino dealt by an NES randomizer (-1 for none).
+	Previous int `json:"previous"`
+	// History is the values of the last tetriminos dealt by a TGM randomizer, oldest first (empty before the first).
+	History string `json:"history,omitempty"`
+}
+
+// statefulRandomizer is a randomizer whose state can be saved, so that it can be restored with RestoreRandomizer.
+type statefulRandomizer interface {
+	Randomizer
+	state() RandomizerState
+}
+
+// RestoreRandomizer creates a randomizer in the given state.
+func RestoreRandomizer(s RandomizerState) (Randomizer, error) {
+	src := newCountingSource(s.Seed)
+	src.advance(s.Draws)
+
+	switch s.Kind {
+	case "bag":
+		return &bagRandomizer{rand: rand.New(src), src: src}, nil
+	case "bag14":
+		return &bagRandomizer{rand: rand.New(src), src: src, copies: 2}, nil
+	case "random":
+		return &randomRandomizer{rand: rand.New(src), src: src}, nil
+	case "nes":
+		return &nesRandomizer{rand: rand.New(src), src: src, previous: s.Previous}, nil
+	case "tgm":
+		return &tgmRandomizer{rand: rand.New(src), src: src, history: []byte(s.History)}, nil
+	}
+	return nil, fmt.Errorf("invalid randomizer kind %q", s.Kind)
+}
+
+// countingSource is a source of random numbers which counts the values drawn from it. Only the seed and the count
+// need to be saved, since a new source with the same seed reaches the same state once as many values are drawn.
+type countingSource struct {
+	src   rand.Source64
+	seed  int64
+	draws uint64
+}
+
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64), seed: seed}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.seed = seed
+	s.draws = 0
+}
+
+// advance draws and discards n values.
+func (s *countingSource) advance(n uint64) {
+	for i := uint64(0); i < n; i++ {
+		s.Uint64()
+	}
+}
+
+// bagRandomizer deals every choice once per bag, in a random order, as described by the guideline. Bigger bags deal
+// each choice more than once, so the same tetrimino can come up more often in a row, but never goes missing for long.
+type bagRandomizer struct {
+	rand   *rand.Rand      // nil to use the global source
+	src    *countingSource // the source of rand (nil if it is nil)
+	copies int             // how many of each choice are in a bag (0 for 1)
+}
+
+// NewBagRandomizer creates a randomizer which deals bags of seven, always in the same order for a given seed.
+func NewBagRandomizer(seed int64) Randomizer {
+	src := newCountingSource(seed)
+	return &bagRandomizer{rand: rand.New(src), src: src}
+}
+
+func (r *bagRandomizer) state() RandomizerState {
+	kind := "bag"
+	if r.copies == 2 {
+		kind = "bag14"
+	}
+	return RandomizerState{Kind: kind, Seed: r.src.seed, Draws: r.src.draws, Previous: -1}
+}
+
+func (r *bagRandomizer) Deal(choices []Tetrimino) []Tetrimino {
+	bag := choices
+	for i := 1; i < r.copies; i++ {
+		bag = append(slices.Clip(bag), choices...)
+	}
+
+	var perm []int
+	if r.rand != nil {
+		perm = r.rand.Perm(len(bag))
+	} else {
+		perm = rand.Perm(len(bag))
+	}
+	dealt := make([]Tetrimino, len(perm))
+	for i, j := range perm {
+		dealt[i] = bag[j]
+	}
+	return dealt
+}
+
+// randomRandomizer deals tetriminos one at a time, each picked at random without regard for those dealt before, as
+// in the earliest versions of the game. Long droughts and floods of the same tetrimino are possible.
+type randomRandomizer struct {
+	rand *rand.Rand
+	src  *countingSource
+}
+
+func (r *randomRandomizer) state() RandomizerState {
+	return RandomizerState{Kind: "random", Seed: r.src.seed, Draws: r.src.draws, Previous: -1}
+}
+
+func (r *randomRandomizer) Deal(choices []Tetrimino) []Tetrimino {
+	return []Tetrimino{choices[r.rand.Intn(len(choices))]}
+}
+
+// nesRandomizer deals tetriminos one at a time like the NES version, which picks one at random and picks again,
+// only once, if it is the same as the previous tetrimino. Droughts of a tetrimino are possible, unlike with bags.
+type nesRandomizer struct {
+	rand     *rand.Rand
+	src      *countingSource
+	previous int // index of the previous tetrimino dealt (-1 for none)
+}
+
+// NewNESRandomizer creates a randomizer which deals tetriminos like the NES version, always in the same order for a
+// given seed.
+func NewNESRandomizer(seed int64) Randomizer {
+	src := newCountingSource(seed)
+	return &nesRandomizer{rand: rand.New(src), src: src, previous: -1}
+}
+
+func (r *nesRandomizer) state() RandomizerState {
+	return RandomizerState{Kind: "nes", Seed: r.src.seed, Draws: r.src.draws, Previous: r.previous}
+}
+
+func (r *nesRandomizer) Deal(choices []Tetrimino) []Tetrimino {
+	// One more than the number of choices is rolled, as on the NES, where the extra value also causes a re-roll.
+	i := r.rand.Intn(len(choices) + 1)
+	if i == len(choices) || i == r.previous {
+		i = r.rand.Intn(len(choices))
+	}
+	r.previous = i
+	return []Tetrimino{choices[i]}
+}
+
+const (
+	// tgmRolls is the most times a TGM randomizer rolls for a tetrimino which isn't in its history.
+	tgmRolls = 6
+	// tgmHistory is the history a TGM randomizer starts with, making the first few tetriminos unlikely to be S or Z.
+	tgmHistory = "ZSSZ"
+	// tgmUnfair are the values of the tetriminos a TGM randomizer never deals first, since they leave an overhang on
+	// an empty matrix.
+	tgmUnfair = "SZO"
+)
+
+// tgmRandomizer deals tetriminos one at a time like Tetris The Grand Master 2. It remembers the last four tetriminos
+// dealt, and rolls up to tgmRolls times for one which isn't among them, so repeats and droughts are rare but possible.
+type tgmRandomizer struct {
+	rand    *rand.Rand
+	src     *countingSource
+	history []byte // values of the last tetriminos dealt, oldest first (empty before the first)
+}
+
+func (r *tgmRandomizer) state() RandomizerState {
+	return RandomizerState{Kind: "tgm", Seed: r.src.seed, Draws: r.src.draws, Previous: -1, History: string(r.history)}
+}
+
+func (r *tgmRandomizer) Deal(choices []Tetrimino) []Tetrimino {
+	var dealt Tetrimino
+	if len(r.history) == 0 {
+		fair := slices.DeleteFunc(slices.Clone(choices), func(t Tetrimino) bool {
+			return strings.IndexByte(tgmUnfair, t.Value) >= 0
+		})
+		if len(fair) == 0 {
+			fair = choices
+		}
+		dealt = fair[r.rand.Intn(len(fair))]
+		r.history = []byte(tgmHistory)
+	} else {
+		for i := 0; i < tgmRolls; i++ {
+			dealt = choices[r.rand.Intn(len(choices))]
+			if !slices.Contains(r.history, dealt.Value) {
+				break
+			}
+		}
+	}
+	r.history = append(r.history[1:], dealt.Value)
+	return []Tetrimino{dealt}
+}
@@ -0,0 +1,123 @@
+package tetris
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNESRandomizer(t *testing.T) {
+	a := NewRandomizedBag(NewDefaultMatrix(), NewNESRandomizer(42))
+	b := NewRandomizedBag(NewDefaultMatrix(), NewNESRandomizer(42))
+
+	counts := make(map[byte]int)
+	repeats := 0
+	var previous byte
+	for i := 0; i < 7000; i++ {
+		x, y := a.Next().Value, b.Next().Value
+		if x != y {
+			t.Fatalf("Tetrimino %d: want %c, got %c", i, x, y)
+		}
+		if x == previous {
+			repeats++
+		}
+		previous = x
+		counts[x]++
+	}
+
+	for _, tet := range Tetriminos {
+		if counts[tet.Value] == 0 {
+			t.Errorf("%c was never dealt", tet.Value)
+		}
+	}
+	// Picking again after a repeat makes them much less likely than the 1 in 7 of a uniform pick.
+	if repeats == 0 || repeats > 7000/14 {
+		t.Errorf("Repeats: want between 1 and %d, got %d", 7000/14, repeats)
+	}
+	if len(a.Elements) < 7 {
+		t.Errorf("Queue: want at least 7 tetriminos, got %d", len(a.Elements))
+	}
+}
+
+func TestBagRandomizer(t *testing.T) {
+	r := NewBagRandomizer(42)
+	for i := 0; i < 4; i++ {
+		dealt := r.Deal(Tetriminos)
+		if len(dealt) != len(Tetriminos) {
+			t.Fatalf("Bag %d: want %d tetriminos, got %d", i, len(Tetriminos), len(dealt))
+		}
+		for _, tet := range Tetriminos {
+			if !slices.ContainsFunc(dealt, func(d Tetrimino) bool { return d.Value == tet.Value }) {
+				t.Errorf("Bag %d: %c is missing", i, tet.Value)
+			}
+		}
+	}
+}
+
+func TestBag14Randomizer(t *testing.T) {
+	r, err := NewRandomizer("bag14", 42)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		counts := make(map[byte]int)
+		for _, d := range r.Deal(Tetriminos) {
+			counts[d.Value]++
+		}
+		for _, tet := range Tetriminos {
+			if counts[tet.Value] != 2 {
+				t.Errorf("Bag %d: want 2 of %c, got %d", i, tet.Value, counts[tet.Value])
+			}
+		}
+	}
+}
+
+func TestTGMRandomizer(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		r, err := NewRandomizer("tgm", seed)
+		if err != nil {
+			t.Fatalf("expected nil, got error: %v", err)
+		}
+		if first := r.Deal(Tetriminos)[0].Value; first == 'S' || first == 'Z' || first == 'O' {
+			t.Errorf("Seed %d: first tetrimino is %c", seed, first)
+		}
+	}
+
+	r, err := NewRandomizer("tgm", 42)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	repeats := 0
+	var previous byte
+	for i := 0; i < 7000; i++ {
+		v := r.Deal(Tetriminos)[0].Value
+		if v == previous {
+			repeats++
+		}
+		previous = v
+	}
+	// Rolling again while the tetrimino is in the history makes repeats far rarer than with the NES randomizer.
+	if repeats > 7000/100 {
+		t.Errorf("Repeats: want at most %d, got %d", 7000/100, repeats)
+	}
+}
+
+func TestNewRandomizer(t *testing.T) {
+	for _, name := range RandomizerNames {
+		a, err := NewRandomizer(name, 42)
+		if err != nil {
+			t.Fatalf("%s: expected nil, got error: %v", name, err)
+		}
+		b, _ := NewRandomizer(name, 42)
+		for i := 0; i < 28; i++ {
+			if x, y := a.Deal(Tetriminos)[0].Value, b.Deal(Tetriminos)[0].Value; x != y {
+				t.Fatalf("%s, tetrimino %d: want %c, got %c", name, i, x, y)
+			}
+		}
+		if s := a.(statefulRandomizer).state(); s.Kind != name {
+			t.Errorf("%s: want kind %q, got %q", name, name, s.Kind)
+		}
+	}
+	if _, err := NewRandomizer("bag7", 42); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -5,6 +5,22 @@ type Scoring struct {
 	total      uint
 	lines      uint
 	backToBack bool
+
+	// classic is whether the NES scoring is used instead of the guideline scoring.
+	classic    bool
+	startLevel uint
+
+	// previous is the level before the last action was processed (0 if none has been).
+	previous uint
+
+	// grader grades the player as each action is processed (nil for none).
+	grader Grader
+}
+
+// LevelChange is the level increasing as lines are cleared, such as to change the speed tetriminos fall at.
+type LevelChange struct {
+	From uint
+	To   uint
 }
 
 // Actions that score points. Defined in chapter 8 of the 2009 Guideline
@@ -24,12 +40,89 @@ const (
 	actionTSpinTriple
 )
 
+// ClearsLines reports whether the action cleared any lines.
+func (a action) ClearsLines() bool {
+	return clearsLines(a)
+}
+
+// actionNames are the names of the actions as announced to the player.
+var actionNames = map[action]string{
+	actionSingle:          "SINGLE",
+	actionDouble:          "DOUBLE",
+	actionTriple:          "TRIPLE",
+	actionTetris:          "TETRIS",
+	actionMiniTSpin:       "T-SPIN MINI",
+	actionMiniTSpinSingle: "T-SPIN MINI SINGLE",
+	actionTSpin:           "T-SPIN",
+	actionTSpinSingle:     "T-SPIN SINGLE",
+	actionTSpinDouble:     "T-SPIN DOUBLE",
+	actionTSpinTriple:     "T-SPIN TRIPLE",
+}
+
+// String returns the name of the action as announced to the player, such as "T-SPIN DOUBLE", or an empty string
+// if the tetrimino locked without clearing lines or making a T-spin.
+func (a action) String() string {
+	return actionNames[a]
+}
+
 func NewScoring(level uint) *Scoring {
 	return &Scoring{
 		level: level,
 	}
 }
 
+// NewClassicScoring creates scoring which follows the NES version. Lines are worth 40, 100, 300 or 1200 points for
+// one to four cleared at once, multiplied by the level, with no bonus for T-spins or back-to-back clears.
+// The level increases every 10 lines, and only soft drops earn points for the rows dropped.
+func NewClassicScoring(level uint) *Scoring {
+	return &Scoring{
+		level:      level,
+		classic:    true,
+		startLevel: level,
+	}
+}
+
+// ScoringState is the state of the scoring, from which it can be restored.
+type ScoringState struct {
+	Level      uint `json:"level"`
+	Total      uint `json:"total"`
+	Lines      uint `json:"lines"`
+	BackToBack bool `json:"back_to_back,omitempty"`
+	Classic    bool `json:"classic,omitempty"`
+	StartLevel uint `json:"start_level,omitempty"`
+
+	// PreviousLevel is the level before the last tetrimino locked (0 if none has). It is only kept for undoing a lock,
+	// so isn't saved: a restored game announces no level change until the next lock.
+	PreviousLevel uint `json:"-"`
+}
+
+// State returns the state of the scoring.
+func (s *Scoring) State() ScoringState {
+	return ScoringState{
+		Level:      s.level,
+		Total:      s.total,
+		Lines:      s.lines,
+		BackToBack: s.backToBack,
+		Classic:    s.classic,
+		StartLevel: s.startLevel,
+
+		PreviousLevel: s.previous,
+	}
+}
+
+// RestoreScoring creates scoring in the given state.
+func RestoreScoring(s ScoringState) *Scoring {
+	return &Scoring{
+		level:      s.Level,
+		total:      s.Total,
+		lines:      s.Lines,
+		backToBack: s.BackToBack,
+		classic:    s.Classic,
+		startLevel: s.StartLevel,
+		previous:   s.PreviousLevel,
+	}
+}
+
 func (s *Scoring) Level() uint {
 	return s.level
 }
@@ -47,12 +140,67 @@ func (s *Scoring) AddSoftDrop(lines uint) {
 }
 
 func (s *Scoring) AddHardDrop(lines uint) {
+	if s.classic {
+		return
+	}
 	s.total += lines * 2
 }
 
-func (s *Scoring) ProcessAction(a action) {
+// ProcessAction records the result of a tetrimino locking down and returns the points awarded for it. Any change in
+// level it makes is reported by LevelChanged until the next action is processed.
+func (s *Scoring) ProcessAction(a action) uint {
+	s.previous = s.level
+	points := s.award(a)
+	if s.grader != nil {
+		s.grader.Update(s, linesCleared(a))
+	}
+	return points
+}
+
+// AddZone awards the points for the lines stacked during a zone, cleared all at once as it ends, and returns them.
+// The points grow with the square of the lines, 50 for each before the level is applied, so four are worth a tetris
+// and sixteen are worth sixteen tetrises. The lines count towards the level as singles do, and any change in level is
+// reported by LevelChanged.
+func (s *Scoring) AddZone(lines uint) uint {
+	s.previous = s.level
+	awarded := 50 * lines * lines * s.level
+	s.total += awarded
+	s.lines += lines
+	for s.lines >= s.level*5 {
+		s.level++
+	}
+	return awarded
+}
+
+// SetGrader attaches the grader, to grade the player each time an action is processed from now on.
+func (s *Scoring) SetGrader(g Grader) {
+	s.grader = g
+}
+
+// Grade returns the name of the grade earned so far, or an empty string if no grader is attached.
+func (s *Scoring) Grade() string {
+	if s.grader == nil {
+		return ""
+	}
+	return s.grader.String()
+}
+
+// LevelChanged returns the change in level made by the last action processed, and whether it changed the level.
+func (s *Scoring) LevelChanged() (LevelChange, bool) {
+	if s.previous == 0 {
+		return LevelChange{From: s.level, To: s.level}, false
+	}
+	return LevelChange{From: s.previous, To: s.level}, s.level != s.previous
+}
+
+// award adds the points for the action to the total, returning them, and raises the level once enough lines have been
+// cleared.
+func (s *Scoring) award(a action) uint {
 	if a == actionNone {
-		return
+		return 0
+	}
+	if s.classic {
+		return s.processClassicAction(a)
 	}
 
 	points := 0.0
@@ -114,10 +262,27 @@ func (s *Scoring) ProcessAction(a action) {
 		s.backToBack = true
 	}
 
-	s.total += uint(points+backToBack) * s.level
+	awarded := uint(points+backToBack) * s.level
+	s.total += awarded
 	s.lines += uint((points + backToBack) / 100)
 
 	for s.lines >= s.level*5 {
 		s.level++
 	}
+	return awarded
+}
+
+// classicPoints are the points for clearing each number of lines at once in the NES version, before the level is applied.
+var classicPoints = []uint{0, 40, 100, 300, 1200}
+
+func (s *Scoring) processClassicAction(a action) uint {
+	lines := linesCleared(a)
+	awarded := classicPoints[lines] * s.level
+	s.total += awarded
+	s.lines += lines
+
+	for s.lines >= (s.level-s.startLevel+1)*10 {
+		s.level++
+	}
+	return awarded
 }
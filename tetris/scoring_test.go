@@ -1,6 +1,7 @@
 package tetris
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -367,12 +368,16 @@ func TestScoring_ProcessAction(t *testing.T) {
 				level:      1,
 			}
 
-			s.ProcessAction(tc.a)
+			awarded := s.ProcessAction(tc.a)
 
 			if s.total != tc.expectedTotal {
 				t.Errorf("Total: expected %d, got %d", tc.expectedTotal, s.total)
 			}
 
+			if awarded != tc.expectedTotal {
+				t.Errorf("Awarded: expected %d, got %d", tc.expectedTotal, awarded)
+			}
+
 			if s.backToBack != tc.expectedBackToBack {
 				t.Errorf("BackToBack: expected %t, got %t", tc.expectedBackToBack, s.backToBack)
 			}
@@ -389,3 +394,144 @@ func TestScoring_ProcessAction(t *testing.T) {
 		})
 	}
 }
+
+func TestAction_String(t *testing.T) {
+	tt := []struct {
+		action   action
+		expected string
+	}{
+		{actionNone, ""},
+		{actionDouble, "DOUBLE"},
+		{actionTetris, "TETRIS"},
+		{actionMiniTSpin, "T-SPIN MINI"},
+		{actionTSpinTriple, "T-SPIN TRIPLE"},
+	}
+
+	for _, tc := range tt {
+		if got := tc.action.String(); got != tc.expected {
+			t.Errorf("action %d: want %q, got %q", tc.action, tc.expected, got)
+		}
+	}
+}
+
+func TestScoring_Classic(t *testing.T) {
+	tt := []struct {
+		name          string
+		level         uint
+		actions       []action
+		expectedTotal uint
+		expectedLines uint
+		expectedLevel uint
+	}{
+		{"single", 1, []action{actionSingle}, 40, 1, 1},
+		{"double", 1, []action{actionDouble}, 100, 2, 1},
+		{"triple", 1, []action{actionTriple}, 300, 3, 1},
+		{"tetris", 3, []action{actionTetris}, 3600, 4, 3},
+		{"T-spin counts as lines only", 1, []action{actionTSpinDouble}, 100, 2, 1},
+		{"no back to back bonus", 1, []action{actionTetris, actionTetris}, 2400, 8, 1},
+		{
+			"level up every 10 lines",
+			5,
+			[]action{actionTetris, actionTetris, actionDouble, actionTetris, actionTetris, actionDouble},
+			2*1200*5 + 100*5 + 2*1200*6 + 100*6,
+			20,
+			7,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewClassicScoring(tc.level)
+			for _, a := range tc.actions {
+				s.ProcessAction(a)
+			}
+
+			if s.Total() != tc.expectedTotal {
+				t.Errorf("Total: want %d, got %d", tc.expectedTotal, s.Total())
+			}
+			if s.Lines() != tc.expectedLines {
+				t.Errorf("Lines: want %d, got %d", tc.expectedLines, s.Lines())
+			}
+			if s.Level() != tc.expectedLevel {
+				t.Errorf("Level: want %d, got %d", tc.expectedLevel, s.Level())
+			}
+		})
+	}
+
+	s := NewClassicScoring(1)
+	s.AddSoftDrop(5)
+	s.AddHardDrop(10)
+	if s.Total() != 5 {
+		t.Errorf("Drops: want 5, got %d", s.Total())
+	}
+}
+
+func TestScoring_LevelChanged(t *testing.T) {
+	s := NewScoring(1)
+	if _, ok := s.LevelChanged(); ok {
+		t.Errorf("New: want no change")
+	}
+
+	tt := []struct {
+		action   action
+		expected LevelChange
+		changed  bool
+	}{
+		{actionTetris, LevelChange{From: 1, To: 2}, true},
+		{actionSingle, LevelChange{From: 2, To: 2}, false},
+		{actionTetris, LevelChange{From: 2, To: 4}, true},
+		{actionNone, LevelChange{From: 4, To: 4}, false},
+	}
+	for i, tc := range tt {
+		s.ProcessAction(tc.action)
+		change, ok := s.LevelChanged()
+		if ok != tc.changed || change != tc.expected {
+			t.Errorf("Action %d: want %v (%t), got %v (%t)", i, tc.expected, tc.changed, change, ok)
+		}
+	}
+}
+
+func TestScoring_AddZone(t *testing.T) {
+	s := NewScoring(2)
+	if got := s.AddZone(12); got != 14400 {
+		t.Errorf("Points: want 14400, got %d", got)
+	}
+	if s.Lines() != 12 || s.Total() != 14400 {
+		t.Errorf("want 12 lines and 14400 points, got %d and %d", s.Lines(), s.Total())
+	}
+	if change, ok := s.LevelChanged(); !ok || change != (LevelChange{From: 2, To: 3}) {
+		t.Errorf("want the level raised from 2 to 3, got %v (%t)", change, ok)
+	}
+}
+
+func TestRestoreScoring(t *testing.T) {
+	for _, s := range []*Scoring{NewScoring(3), NewClassicScoring(5)} {
+		for _, a := range []action{actionTetris, actionTetris, actionDouble, actionTetris} {
+			s.ProcessAction(a)
+		}
+		s.AddHardDrop(10)
+
+		restored := RestoreScoring(s.State())
+		if *restored != *s {
+			t.Errorf("Scoring: want %+v, got %+v", *s, *restored)
+		}
+	}
+}
+
+func TestScoringState_JSON(t *testing.T) {
+	s := NewScoring(1)
+	s.ProcessAction(actionTetris)
+
+	data, err := json.Marshal(s.State())
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	var state ScoringState
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if _, ok := RestoreScoring(state).LevelChanged(); ok {
+		t.Errorf("Decoded: want no level change, got the one before it was saved")
+	}
+}
@@ -0,0 +1,421 @@
+package tetris
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// The invariants checked by Simulate.
+const (
+	InvariantCells = "cells" // the stack and the current tetrimino never overlap, and no cell is gained or lost
+	InvariantScore = "score" // the score never decreases
+	InvariantBag   = "bag"   // each seven tetriminos dealt are one of each
+	InvariantError = "error" // the engine never fails to move a tetrimino it reported could move
+)
+
+// simulatedQueue is how many of the next tetriminos are given in the state returned by Simulate, as the guideline
+// shows.
+const simulatedQueue = 5
+
+// Violation is an invariant broken during a simulation.
+type Violation struct {
+	Input     int    // index of the input being applied when it was broken (-1 while spawning the first tetrimino)
+	Invariant string // such as InvariantCells
+	Detail    string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("input %d: %s: %s", v.Input, v.Invariant, v.Detail)
+}
+
+// Report is what was checked during a simulation.
+type Report struct {
+	Inputs     int // inputs applied, which is fewer than given if the game ended first
+	Violations []Violation
+}
+
+// OK reports whether every invariant held.
+func (r *Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// Simulate plays a game in a matrix of the default size, dealt by a bag of seven with the seed, applying the inputs in
+// order, and returns the state it ended in along with every invariant which was broken. It is deterministic, so that
+// any input sequence, such as one generated by a fuzzer, can be checked against the rules and replayed exactly.
+//
+// No time passes, so tetriminos only fall when soft dropped and only lock when hard dropped. Inputs which aren't
+// moves are ignored, as are those given once the game is over.
+func Simulate(seed int64, inputs []Move) (State, Report) {
+	s := NewSimulation(SimulationOptions{Seed: seed})
+	for _, move := range inputs {
+		if s.gameOver {
+			break
+		}
+		s.Apply(move)
+	}
+	return s.State(), s.report
+}
+
+// SimulationOptions are the rules a Simulation is played by. The zero value plays as Simulate does.
+type SimulationOptions struct {
+	Seed       int64      // seed of the bag of seven the tetriminos are dealt from
+	Randomizer Randomizer // deals the tetriminos in place of the bag of seven (nil for the bag)
+	Matrix     Matrix     // matrix to play in, which is changed as the game is played (nil for an empty default one)
+	Scoring    *Scoring   // scores the game (nil for the guideline's scoring from level 1)
+	Hold       HoldRule   // how often the current tetrimino can be held
+	Unchecked  bool       // whether to skip checking the invariants after each move, such as while timing the engine
+}
+
+// Simulation is a game played one move at a time without time passing, as Simulate plays one, for callers which
+// choose each move as they go, such as the bot, or which move tetriminos by rules of their own and only place them,
+// such as replays and other clients.
+type Simulation struct {
+	matrix  Matrix
+	bag     *Bag
+	scoring *Scoring
+	attack  *Attack
+	stats   *Statistics
+	hold    HoldRule
+
+	current  *Tetrimino
+	kick     int // kick used by the current tetrimino's last rotation, or -1 if it has moved since (see TSpin)
+	held     HoldQueue
+	canHold  bool
+	gameOver bool
+
+	unchecked  bool
+	bagOfSeven bool   // whether the tetriminos are dealt by the bag of seven, so each seven dealt are one of each
+	dealt      []byte // values of the tetriminos dealt by the bag, in order
+	stack      int    // filled cells of the matrix, not counting the current tetrimino
+	score      uint   // score after the last input
+
+	input  int
+	report Report
+}
+
+// NewSimulation starts a game with the options, spawning the first tetrimino.
+func NewSimulation(opts SimulationOptions) *Simulation {
+	s := &Simulation{
+		matrix:     opts.Matrix,
+		scoring:    opts.Scoring,
+		attack:     NewAttack(),
+		stats:      NewStatistics(),
+		hold:       opts.Hold,
+		unchecked:  opts.Unchecked,
+		bagOfSeven: opts.Randomizer == nil,
+		input:      -1,
+	}
+	if s.matrix == nil {
+		s.matrix = NewDefaultMatrix()
+	}
+	if s.scoring == nil {
+		s.scoring = NewScoring(1)
+	}
+	if opts.Randomizer == nil {
+		s.bag = NewSeededBag(s.matrix, opts.Seed)
+	} else {
+		s.bag = NewRandomizedBag(s.matrix, opts.Randomizer)
+	}
+	s.stack = filledCells(s.matrix)
+	s.score = s.scoring.Total()
+	s.spawn(nil)
+	s.check()
+	return s
+}
+
+// Apply makes the move, if it is one, unless the game is over.
+func (s *Simulation) Apply(move Move) {
+	if s.gameOver {
+		return
+	}
+	s.input = s.report.Inputs
+	s.apply(move)
+	s.check()
+	s.report.Inputs++
+}
+
+// Place locks the tetrimino where it is in place of the current one, for callers which move tetriminos by rules of
+// their own, and returns the lines it cleared and the name of the action they make, such as "T-SPIN DOUBLE" (empty if
+// it makes none). It must fit on the stack, and it is given the T-spin it makes, since how it was moved there isn't
+// known.
+func (s *Simulation) Place(t *Tetrimino, spin Spin) (int, string, error) {
+	if s.gameOver {
+		return 0, "", errors.New("the game is over")
+	}
+	err := s.matrix.RemoveTetrimino(s.current)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to remove tetrimino: %w", err)
+	}
+	err = s.matrix.AddTetrimino(t)
+	if err != nil {
+		// The current tetrimino was just removed, so it fits where it was.
+		_ = s.matrix.AddTetrimino(s.current)
+		return 0, "", fmt.Errorf("failed to place %c: %w", t.Value, err)
+	}
+	s.current = t
+	lines, act := s.lock(spin)
+	s.check()
+	return lines, act.String(), nil
+}
+
+// AddGarbage raises lines of garbage beneath the stack, as Matrix.AddWideGarbage does, leaving the current tetrimino
+// where it is. It reports whether the game ended, because the garbage pushed the stack out of the top of the matrix or
+// into the current tetrimino.
+func (s *Simulation) AddGarbage(holes []int, holeWidth int) bool {
+	if s.gameOver {
+		return true
+	}
+	err := s.matrix.RemoveTetrimino(s.current)
+	if err != nil {
+		s.violate(InvariantError, "failed to remove %c: %v", s.current.Value, err)
+		return false
+	}
+	toppedOut := s.matrix.AddWideGarbage(holes, holeWidth)
+	s.stack = filledCells(s.matrix)
+	if toppedOut || !s.matrix.CanAddTetrimino(s.current) {
+		s.gameOver = true
+		return true
+	}
+	err = s.matrix.AddTetrimino(s.current)
+	if err != nil {
+		s.violate(InvariantError, "failed to add %c: %v", s.current.Value, err)
+	}
+	return false
+}
+
+// Current returns the tetrimino being placed, which is moved as moves are applied.
+func (s *Simulation) Current() *Tetrimino {
+	return s.current
+}
+
+// Matrix returns the matrix the game is played in, with the current tetrimino in it.
+func (s *Simulation) Matrix() Matrix {
+	return s.matrix
+}
+
+// Stack returns a copy of the matrix without the current tetrimino.
+func (s *Simulation) Stack() Matrix {
+	stack := s.matrix.Clone()
+	if !s.gameOver {
+		_ = stack.RemoveTetrimino(s.current)
+	}
+	return stack
+}
+
+// Spawned returns the tetrimino with the value as it would spawn, such as to place in place of the one dealt.
+func (s *Simulation) Spawned(value byte) (*Tetrimino, error) {
+	return s.bag.Spawned(value)
+}
+
+// GameOver reports whether the game has ended.
+func (s *Simulation) GameOver() bool {
+	return s.gameOver
+}
+
+// Report returns what has been checked so far.
+func (s *Simulation) Report() Report {
+	return s.report
+}
+
+// violate records the invariant as broken.
+func (s *Simulation) violate(invariant, format string, a ...any) {
+	s.report.Violations = append(s.report.Violations, Violation{
+		Input:     s.input,
+		Invariant: invariant,
+		Detail:    fmt.Sprintf(format, a...),
+	})
+}
+
+// apply makes the move, if it is one.
+func (s *Simulation) apply(move Move) {
+	var err error
+	var moved bool
+	switch move {
+	case MoveLeft:
+		moved, err = s.current.MoveLeft(&s.matrix)
+	case MoveRight:
+		moved, err = s.current.MoveRight(&s.matrix)
+	case MoveClockwise, MoveCounterClockwise:
+		var kick int
+		kick, err = s.current.RotateWithKicks(&s.matrix, move == MoveClockwise)
+		if kick >= 0 {
+			s.kick = kick
+		}
+	case MoveSoftDrop:
+		moved, err = s.current.MoveDown(&s.matrix)
+		if moved {
+			s.scoring.AddSoftDrop(1)
+		}
+	case MoveHardDrop:
+		var rows int
+		rows, err = s.current.Drop(&s.matrix)
+		s.scoring.AddHardDrop(uint(rows))
+		if rows > 0 {
+			s.kick = -1
+		}
+		if err == nil {
+			s.lock(s.matrix.TSpin(s.current, s.kick))
+		}
+	case MoveHold:
+		err = s.holdTetrimino()
+	default:
+		return
+	}
+	if moved {
+		s.kick = -1
+	}
+	s.stats.ProcessInput(move)
+	if err != nil {
+		s.violate(InvariantError, "failed to apply %s: %v", move, err)
+	}
+}
+
+// lock locks the current tetrimino with the T-spin it makes, clearing the lines it completed, and spawns the next,
+// returning the lines cleared and the action they make. Locking it entirely within the buffer zone ends the game
+// instead.
+func (s *Simulation) lock(spin Spin) (int, action) {
+	if s.matrix.IsLockOut(s.current) {
+		s.stack += 4
+		s.gameOver = true
+		return 0, actionNone
+	}
+	lines := len(s.matrix.CompletedLines(s.current))
+	act := s.matrix.ClearLines(s.current, spin)
+	points := s.scoring.ProcessAction(act)
+	s.attack.ProcessAction(act)
+	s.stats.ProcessLock(s.current.Value, act, points)
+	s.stack += 4 - lines*len(s.matrix[0])
+	s.spawn(nil)
+	return lines, act
+}
+
+// holdTetrimino swaps the current tetrimino with the held one, or the next if none is held, as often as the hold rule
+// allows. The game ends if the tetrimino swapped in has no room to spawn, leaving the current one in the stack.
+func (s *Simulation) holdTetrimino() error {
+	if !s.canHold {
+		return nil
+	}
+	_, swapped := s.held.Next()
+	next, err := s.held.Hold(s.matrix, s.current, s.bag)
+	if errors.Is(err, ErrBlockOut) {
+		s.stack += 4
+		s.gameOver = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !swapped {
+		s.deal(next.Value)
+	}
+	s.current, s.kick = next, -1
+	s.canHold = s.hold == HoldUnlimited
+	return nil
+}
+
+// spawn adds the tetrimino to the matrix, or the next from the bag if it is nil, ending the game if there is no room
+// for it.
+func (s *Simulation) spawn(t *Tetrimino) {
+	if t == nil {
+		t = s.bag.Next()
+		s.deal(t.Value)
+	}
+	s.current = t
+	s.kick = -1
+	s.canHold = s.hold != HoldOff
+	if !s.matrix.CanAddTetrimino(t) {
+		s.gameOver = true
+		return
+	}
+	err := s.matrix.AddTetrimino(t)
+	if err != nil {
+		s.violate(InvariantError, "failed to spawn %c: %v", t.Value, err)
+		s.gameOver = true
+	}
+}
+
+// deal notes the tetrimino with the value as dealt by the bag, checking that the last seven dealt were one of each
+// once seven more have been dealt.
+func (s *Simulation) deal(value byte) {
+	if s.unchecked || !s.bagOfSeven {
+		return
+	}
+	s.dealt = append(s.dealt, value)
+	if len(s.dealt)%len(Tetriminos) != 0 {
+		return
+	}
+	bag := slices.Clone(s.dealt[len(s.dealt)-len(Tetriminos):])
+	slices.Sort(bag)
+	if string(bag) != "IJLOSTZ" {
+		s.violate(InvariantBag, "bag %d dealt %s", len(s.dealt)/len(Tetriminos), bag)
+	}
+}
+
+// check checks the matrix and the score after an input.
+func (s *Simulation) check() {
+	if s.unchecked {
+		return
+	}
+	if s.scoring.Total() < s.score {
+		s.violate(InvariantScore, "score decreased from %d to %d", s.score, s.scoring.Total())
+	}
+	s.score = s.scoring.Total()
+
+	stack := s.matrix.Clone()
+	if !s.gameOver {
+		err := stack.RemoveTetrimino(s.current)
+		if err != nil {
+			s.violate(InvariantCells, "current %c is not where it is expected: %v", s.current.Value, err)
+			return
+		}
+	}
+	var filled int
+	for row := range stack {
+		for col, cell := range stack[row] {
+			if cell == 0 {
+				continue
+			}
+			if cell != GarbageValue && !isTetriminoValue(cell) {
+				s.violate(InvariantCells, "cell at row %d, col %d is %q", row, col, cell)
+			}
+			filled++
+		}
+	}
+	if filled != s.stack {
+		s.violate(InvariantCells, "stack has %d cells, want %d", filled, s.stack)
+		s.stack = filled
+	}
+}
+
+// State returns the state the game is in.
+func (s *Simulation) State() State {
+	state := State{
+		Version:    StateVersion,
+		Matrix:     s.matrix.Clone(),
+		CanHold:    s.canHold,
+		Scoring:    s.scoring.State(),
+		Attack:     s.attack.State(),
+		Statistics: s.stats.State(),
+		GameOver:   s.gameOver,
+	}
+	if !s.gameOver && state.Matrix.RemoveTetrimino(s.current) == nil {
+		state.Current = NewPiece(s.current)
+	}
+	state.Hold = s.held.Values()
+	state.Queue = values(s.bag.Elements[:min(simulatedQueue, len(s.bag.Elements))])
+	return state
+}
+
+// filledCells returns the number of cells of the matrix which aren't empty.
+func filledCells(m Matrix) int {
+	var filled int
+	for row := range m {
+		for _, cell := range m[row] {
+			if cell != 0 {
+				filled++
+			}
+		}
+	}
+	return filled
+}
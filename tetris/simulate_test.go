@@ -0,0 +1,138 @@
+package tetris
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	inputs := []Move{MoveLeft, MoveLeft, MoveHardDrop, MoveClockwise, MoveRight, MoveSoftDrop, MoveHardDrop, MoveHold}
+	state, report := Simulate(1, inputs)
+
+	if !report.OK() {
+		t.Errorf("want no violations, got %v", report.Violations)
+	}
+	if report.Inputs != len(inputs) {
+		t.Errorf("want %d inputs applied, got %d", len(inputs), report.Inputs)
+	}
+	if state.Statistics.Placed != 2 {
+		t.Errorf("want 2 tetriminos placed, got %d", state.Statistics.Placed)
+	}
+	dealt := SeededBags(1, 1)[0]
+	if state.Hold != string(dealt[2]) || state.CanHold {
+		t.Errorf("want %c held until the next lock, got %q (can hold: %t)", dealt[2], state.Hold, state.CanHold)
+	}
+	if state.Current == nil || state.Current.Value != string(dealt[3]) {
+		t.Errorf("want %c current, got %+v", dealt[3], state.Current)
+	}
+	if state.Scoring.Total == 0 {
+		t.Errorf("want points for dropping, got 0")
+	}
+
+	again, _ := Simulate(1, inputs)
+	if !reflect.DeepEqual(state, again) {
+		t.Errorf("want the same state from the same seed and inputs, got %+v and %+v", state, again)
+	}
+}
+
+func TestSimulate_GameOver(t *testing.T) {
+	inputs := make([]Move, 100)
+	for i := range inputs {
+		inputs[i] = MoveHardDrop
+	}
+	state, report := Simulate(1, inputs)
+
+	if !report.OK() {
+		t.Errorf("want no violations, got %v", report.Violations)
+	}
+	if !state.GameOver || state.Current != nil {
+		t.Errorf("want the game over with no current tetrimino, got %+v", state)
+	}
+	if report.Inputs >= len(inputs) {
+		t.Errorf("want the inputs after topping out ignored, got %d applied", report.Inputs)
+	}
+}
+
+func TestSimulate_IgnoresUnknownMoves(t *testing.T) {
+	state, report := Simulate(1, []Move{-1, MoveHold + 1, 100})
+
+	if !report.OK() {
+		t.Errorf("want no violations, got %v", report.Violations)
+	}
+	if len(state.Statistics.Inputs) != 0 {
+		t.Errorf("want no inputs counted, got %v", state.Statistics.Inputs)
+	}
+}
+
+func TestSimulation_Place(t *testing.T) {
+	s := NewSimulation(SimulationOptions{Seed: 1})
+	dealt := SeededBags(1, 1)[0]
+
+	// The tetrimino is dropped onto a copy of the stack, then placed there in place of the current one.
+	placed := s.Current().Copy()
+	stack := s.Stack()
+	err := stack.AddTetrimino(placed)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	_, err = placed.Drop(&stack)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	lines, name, err := s.Place(placed, SpinNone)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if lines != 0 || name != "" || s.Current().Value != dealt[1] {
+		t.Errorf("want no lines or action and %c dealt next, got %d lines, %q and %c", dealt[1], lines, name,
+			s.Current().Value)
+	}
+
+	// The next tetrimino can't be placed over the first.
+	_, _, err = s.Place(placed.Copy(), SpinNone)
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	if report := s.Report(); !report.OK() {
+		t.Errorf("want no violations, got %v", report.Violations)
+	}
+}
+
+func TestSimulation_AddGarbage(t *testing.T) {
+	s := NewSimulation(SimulationOptions{Seed: 1})
+	if s.AddGarbage([]int{0, 1}, 1) {
+		t.Fatalf("want the game to carry on, got game over")
+	}
+	if got, want := filledCells(s.Stack()), 2*(DefaultWidth-1); got != want {
+		t.Errorf("want %d cells of garbage, got %d", want, got)
+	}
+	s.Apply(MoveHardDrop)
+	if report := s.Report(); !report.OK() {
+		t.Errorf("want no violations, got %v", report.Violations)
+	}
+}
+
+func TestSimulation_HoldOff(t *testing.T) {
+	s := NewSimulation(SimulationOptions{Seed: 1, Hold: HoldOff})
+	current := s.Current().Value
+	s.Apply(MoveHold)
+	if state := s.State(); state.Hold != "" || s.Current().Value != current {
+		t.Errorf("want nothing held and %c current, got %q held and %c current", current, state.Hold,
+			s.Current().Value)
+	}
+}
+
+func FuzzSimulate(f *testing.F) {
+	f.Add(int64(1), []byte{0, 0, 5, 2, 1, 4, 5, 6})
+	f.Add(int64(42), []byte{6, 6, 5, 3, 3, 3, 3, 5, 1, 1, 1, 1, 1, 5})
+	f.Fuzz(func(t *testing.T, seed int64, data []byte) {
+		inputs := make([]Move, len(data))
+		for i, b := range data {
+			inputs[i] = Move(b % byte(MoveHold+1))
+		}
+		_, report := Simulate(seed, inputs)
+		for _, v := range report.Violations {
+			t.Error(v)
+		}
+	})
+}
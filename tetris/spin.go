@@ -0,0 +1,126 @@
+package tetris
+
+// The rotation states of a tetrimino, as counted by CurrentRotation: as it spawns, turned clockwise (R), turned twice,
+// and turned counter-clockwise (L).
+const (
+	stateSpawn = iota
+	stateRight
+	stateTwice
+	stateLeft
+)
+
+// kicks are the offsets tried, in order, when rotating from one state to another, keyed by the two states. They are
+// the guideline's SRS tables with rows counted down, so that a kick up has a negative Y.
+var kicks = map[[2]int][]Coordinate{
+	{stateSpawn, stateRight}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: 2}, {X: -1, Y: 2}},
+	{stateRight, stateSpawn}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: -2}, {X: 1, Y: -2}},
+	{stateRight, stateTwice}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: -2}, {X: 1, Y: -2}},
+	{stateTwice, stateRight}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: 2}, {X: -1, Y: 2}},
+	{stateTwice, stateLeft}:  {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: -1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+	{stateLeft, stateTwice}:  {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: -2}, {X: -1, Y: -2}},
+	{stateLeft, stateSpawn}:  {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: -2}, {X: -1, Y: -2}},
+	{stateSpawn, stateLeft}:  {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: -1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+}
+
+// iKicks are the offsets tried when rotating the I, which has kicks of its own.
+var iKicks = map[[2]int][]Coordinate{
+	{stateSpawn, stateRight}: {{X: 0, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 1}, {X: 1, Y: -2}},
+	{stateRight, stateSpawn}: {{X: 0, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: -1}, {X: -1, Y: 2}},
+	{stateRight, stateTwice}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: -2}, {X: 2, Y: 1}},
+	{stateTwice, stateRight}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 2}, {X: -2, Y: -1}},
+	{stateTwice, stateLeft}:  {{X: 0, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: -1}, {X: -1, Y: 2}},
+	{stateLeft, stateTwice}:  {{X: 0, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 1}, {X: 1, Y: -2}},
+	{stateLeft, stateSpawn}:  {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 2}, {X: -2, Y: -1}},
+	{stateSpawn, stateLeft}:  {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: -2}, {X: 2, Y: 1}},
+}
+
+// Kicks returns the offsets tried, in order, when the tetrimino with the value is rotated from one state to another
+// (see Tetrimino.CurrentRotation), as in the guideline's Super Rotation System: it turns to the first at which it
+// fits. Rows are counted down, so a kick up has a negative Y. There are none for half turns, which SRS doesn't have.
+func Kicks(value byte, from, to int) []Coordinate {
+	if value == 'I' {
+		return iKicks[[2]int{from, to}]
+	}
+	return kicks[[2]int{from, to}]
+}
+
+// Spin is the kind of T-spin a tetrimino locked with.
+type Spin int8
+
+const (
+	// SpinNone is a lock which isn't a T-spin.
+	SpinNone Spin = iota
+	// SpinMini is a mini T-spin, with only one of the corners the T points towards filled.
+	SpinMini
+	// SpinFull is a T-spin.
+	SpinFull
+)
+
+// tOffsets are where the cells of the T are in the box it rotates in, in each rotation state, in minos from the top
+// left of the box.
+var tOffsets = []Coordinate{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 0}}
+
+// tCorners are the corners of the box the T rotates in, clockwise from the top left. The corners the T points
+// towards in each rotation state are the pair starting from that state.
+var tCorners = []Coordinate{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}
+
+// tstKick is the index of the last kick of each of the T's rotations, which moves it far enough, as into the slot of
+// a T-spin triple, to count as a full T-spin whatever the corners.
+const tstKick = 4
+
+// TSpin returns the kind of T-spin the tetrimino makes where it is, having been turned there by the kick its last
+// rotation used (see Tetrimino.RotateWithKicks). The kick is -1 if it has moved since it last rotated, or hasn't
+// rotated, since only a T rotated into place makes a T-spin. Lines it completes must not have been cleared yet.
+//
+// It is a T-spin when three of the four corners of the box the T rotates in are filled or outside the matrix, and a
+// mini T-spin when only one of those is a corner it points towards.
+func (p Matrix) TSpin(t *Tetrimino, kick int) Spin {
+	if t.Value != 'T' || kick < 0 || t.CurrentRotation < 0 || t.CurrentRotation >= len(tOffsets) {
+		return SpinNone
+	}
+	step := t.step()
+	offset := tOffsets[t.CurrentRotation]
+	box := Coordinate{X: t.Pos.X - offset.X*step, Y: t.Pos.Y - offset.Y*step}
+
+	var filled, front int
+	for i, c := range tCorners {
+		row, col := box.Y+c.Y*step, box.X+c.X*step
+		if row >= 0 && row < len(p) && col >= 0 && col < len(p[row]) && p.IsCellEmpty(row, col) {
+			continue
+		}
+		filled++
+		if i == t.CurrentRotation || i == (t.CurrentRotation+1)%len(tCorners) {
+			front++
+		}
+	}
+	switch {
+	case filled < 3:
+		return SpinNone
+	case front < 2 && kick != tstKick:
+		return SpinMini
+	}
+	return SpinFull
+}
+
+// clearAction returns the action of a lock which clears the lines, with the T-spin it makes.
+func clearAction(lines int, spin Spin) action {
+	switch {
+	case spin == SpinMini && lines == 0:
+		return actionMiniTSpin
+	case spin == SpinMini && lines == 1:
+		return actionMiniTSpinSingle
+	case spin != SpinNone && lines <= 3:
+		return []action{actionTSpin, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple}[lines]
+	}
+	switch lines {
+	case 1:
+		return actionSingle
+	case 2:
+		return actionDouble
+	case 3:
+		return actionTriple
+	case 4:
+		return actionTetris
+	}
+	return actionNone
+}
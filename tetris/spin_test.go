@@ -0,0 +1,131 @@
+package tetris
+
+import (
+	"testing"
+)
+
+func TestKicks(t *testing.T) {
+	if got := Kicks('T', stateSpawn, stateRight); len(got) != 5 || got[0] != (Coordinate{}) {
+		t.Errorf("T: want 5 kicks starting in place, got %v", got)
+	}
+	if got, want := Kicks('I', stateSpawn, stateRight)[1], (Coordinate{X: -2, Y: 0}); got != want {
+		t.Errorf("I: want the second kick %v, got %v", want, got)
+	}
+	if got := Kicks('T', stateSpawn, stateTwice); got != nil {
+		t.Errorf("half turn: want no kicks, got %v", got)
+	}
+}
+
+func TestMatrix_TSpin(t *testing.T) {
+	// A T pointing up at the floor, with the corner above its left and both below it (outside the matrix) filled.
+	m, err := ParseMatrix(`
+X.........
+...XXXXXX.
+`)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	tet := &Tetrimino{
+		Value: 'T',
+		Cells: [][]bool{
+			{false, true, false},
+			{true, true, true},
+		},
+		Pos:            Coordinate{X: 0, Y: len(m) - 2},
+		RotationCoords: RotationCoords['6'],
+	}
+	err = m.AddTetrimino(tet)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	tt := map[string]struct {
+		tet  *Tetrimino
+		kick int
+		want Spin
+	}{
+		"rotated into place": {tet, 0, SpinMini},
+		"last kick":          {tet, tstKick, SpinFull},
+		"moved since":        {tet, -1, SpinNone},
+		"not a T":            {&Tetrimino{Value: 'L', Pos: tet.Pos}, 0, SpinNone},
+		"two corners": {
+			&Tetrimino{Value: 'T', Cells: tet.Cells, Pos: Coordinate{X: 4, Y: len(m) - 3}}, 0, SpinNone,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			if got := m.TSpin(tc.tet, tc.kick); got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMatrix_ClearLines(t *testing.T) {
+	// A T-spin double slot under an overhang on its left, which a T pointing right spins into.
+	m, err := ParseMatrix(`
+XXXX......
+XXX...XXXX
+XXXX.XXXXX
+`)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	tet := &Tetrimino{
+		Value: 'T',
+		Cells: [][]bool{
+			{true, false},
+			{true, true},
+			{true, false},
+		},
+		Pos:             Coordinate{X: 4, Y: len(m) - 3},
+		CurrentRotation: stateRight,
+		RotationCoords:  RotationCoords['6'],
+	}
+	err = m.AddTetrimino(tet)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	kick, err := tet.RotateWithKicks(&m, true)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if kick != 0 || tet.CurrentRotation != stateTwice || tet.Pos != (Coordinate{X: 3, Y: len(m) - 2}) {
+		t.Fatalf("want the T turned in place to point down at (3, %d), got kick %d, rotation %d at %v",
+			len(m)-2, kick, tet.CurrentRotation, tet.Pos)
+	}
+	spin := m.TSpin(tet, kick)
+	if spin != SpinFull {
+		t.Errorf("TSpin: want %v, got %v", SpinFull, spin)
+	}
+	if got := m.ClearLines(tet, spin); got != actionTSpinDouble {
+		t.Errorf("ClearLines: want %v, got %v", actionTSpinDouble, got)
+	}
+}
+
+func TestClearAction(t *testing.T) {
+	tt := map[string]struct {
+		lines int
+		spin  Spin
+		want  action
+	}{
+		"none":             {0, SpinNone, actionNone},
+		"tetris":           {4, SpinNone, actionTetris},
+		"mini":             {0, SpinMini, actionMiniTSpin},
+		"mini single":      {1, SpinMini, actionMiniTSpinSingle},
+		"mini double":      {2, SpinMini, actionTSpinDouble},
+		"T-spin":           {0, SpinFull, actionTSpin},
+		"T-spin triple":    {3, SpinFull, actionTSpinTriple},
+		"too many to spin": {4, SpinFull, actionTetris},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			if got := clearAction(tc.lines, tc.spin); got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
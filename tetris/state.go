@@ -0,0 +1,55 @@
+package tetris
+
+import "time"
+
+// StateVersion is incremented whenever a change to State would stop tools written for an older version from
+// understanding it. Fields may be added without changing it.
+const StateVersion = 1
+
+// State is everything about a game at a moment in time, in a stable JSON schema for tools outside the game, such as
+// overlays, bots and tests.
+type State struct {
+	Version int `json:"version"` // StateVersion when the state was taken
+
+	Matrix  Matrix `json:"matrix"`            // the stack, without the current tetrimino
+	Current *Piece `json:"current,omitempty"` // the tetrimino being placed (nil between tetriminos or once the game is over)
+	Hold    string `json:"hold,omitempty"`    // values of the held tetriminos, with the one swapped in next first
+	CanHold bool   `json:"can_hold"`
+	Queue   string `json:"queue"` // values of the next tetriminos, as far ahead as the player can see, in order
+
+	Scoring    ScoringState    `json:"scoring"`
+	Attack     AttackState     `json:"attack"`
+	Statistics StatisticsState `json:"statistics"`
+	Timers     Timers          `json:"timers"`
+	GameOver   bool            `json:"game_over"`
+}
+
+// Piece is a tetrimino in the matrix.
+type Piece struct {
+	Value    string       `json:"value"`    // such as "T"
+	X        int          `json:"x"`        // column of the top left cell of its bounding box
+	Y        int          `json:"y"`        // row of the top left cell of its bounding box, counting the buffer zone
+	Rotation int          `json:"rotation"` // 0 as it spawns, then 1 to 3 clockwise from there
+	Cells    []Coordinate `json:"cells"`    // cells of the matrix it occupies
+}
+
+// Timers are the time played and the delays running, all in nanoseconds.
+type Timers struct {
+	Time       time.Duration `json:"time"`        // time played
+	Gravity    time.Duration `json:"gravity"`     // how long the current tetrimino takes to fall a row
+	LockDelay  time.Duration `json:"lock_delay"`  // time left before the current tetrimino locks (0 unless it has landed)
+	EntryDelay time.Duration `json:"entry_delay"` // time left before the next tetrimino spawns (0 unless waiting)
+}
+
+// NewPiece describes the tetrimino where it is in the matrix.
+func NewPiece(t *Tetrimino) *Piece {
+	p := &Piece{Value: string(t.Value), X: t.Pos.X, Y: t.Pos.Y, Rotation: t.CurrentRotation}
+	for row := range t.Cells {
+		for col := range t.Cells[row] {
+			if t.Cells[row][col] {
+				p.Cells = append(p.Cells, Coordinate{X: t.Pos.X + col, Y: t.Pos.Y + row})
+			}
+		}
+	}
+	return p
+}
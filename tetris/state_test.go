@@ -0,0 +1,55 @@
+package tetris
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewPiece(t *testing.T) {
+	tet := Tetriminos[2].Copy() // T
+	tet.Pos = Coordinate{X: 3, Y: 18}
+
+	got := NewPiece(tet)
+
+	want := &Piece{
+		Value: "T",
+		X:     3,
+		Y:     18,
+		Cells: []Coordinate{{X: 4, Y: 18}, {X: 3, Y: 19}, {X: 4, Y: 19}, {X: 5, Y: 19}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestState_JSON(t *testing.T) {
+	matrix := NewMatrix(4, 4)
+	matrix[7][0] = GarbageValue
+	state := State{
+		Version: StateVersion,
+		Matrix:  matrix,
+		Current: NewPiece(Tetriminos[1].Copy()),
+		Hold:    "I",
+		CanHold: true,
+		Queue:   "TSZ",
+		Scoring: ScoringState{Level: 2, Total: 1200, Lines: 14},
+		Attack:  AttackState{Sent: 3, Combo: 1},
+		Timers:  Timers{Time: time.Minute, Gravity: 800 * time.Millisecond, LockDelay: 100 * time.Millisecond},
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	var got State
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, state) {
+		t.Errorf("want %+v, got %+v", state, got)
+	}
+}
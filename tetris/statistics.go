@@ -0,0 +1,210 @@
+package tetris
+
+import (
+	"fmt"
+	"maps"
+)
+
+// ClearKind groups the line clears shown separately when breaking down a game's score.
+type ClearKind int8
+
+const (
+	ClearSingle ClearKind = iota
+	ClearDouble
+	ClearTriple
+	ClearTetris
+	ClearTSpin // any T-spin, including minis and those which clear no lines
+)
+
+// ClearKinds lists every kind of clear, in the order they are shown.
+var ClearKinds = []ClearKind{ClearSingle, ClearDouble, ClearTriple, ClearTetris, ClearTSpin}
+
+func (k ClearKind) String() string {
+	switch k {
+	case ClearSingle:
+		return "Singles"
+	case ClearDouble:
+		return "Doubles"
+	case ClearTriple:
+		return "Triples"
+	case ClearTetris:
+		return "Tetrises"
+	case ClearTSpin:
+		return "T-spins"
+	}
+	return "Unknown"
+}
+
+// ClearCount is the number of clears of one kind made during a game and the points they were awarded.
+type ClearCount struct {
+	Kind   ClearKind
+	Count  uint
+	Points uint
+}
+
+// Statistics counts the tetriminos placed during a game, the lines they cleared and the moves made to place them.
+type Statistics struct {
+	pieces      map[byte]uint
+	placed      uint
+	lines       uint
+	tetrisLines uint
+	clears      map[ClearKind]*ClearCount
+	inputs      map[Move]uint
+}
+
+func NewStatistics() *Statistics {
+	s := &Statistics{
+		pieces: make(map[byte]uint),
+		clears: make(map[ClearKind]*ClearCount, len(ClearKinds)),
+		inputs: make(map[Move]uint),
+	}
+	for _, kind := range ClearKinds {
+		s.clears[kind] = &ClearCount{Kind: kind}
+	}
+	return s
+}
+
+// StatisticsState is the state of the statistics, from which they can be restored.
+type StatisticsState struct {
+	Pieces      map[string]uint `json:"pieces"` // tetriminos placed, keyed by their value
+	Placed      uint            `json:"placed"`
+	Lines       uint            `json:"lines"`
+	TetrisLines uint            `json:"tetris_lines"`
+	Clears      []ClearCount    `json:"clears"`
+	Inputs      map[Move]uint   `json:"inputs"`
+}
+
+// State returns the state of the statistics.
+func (s *Statistics) State() StatisticsState {
+	pieces := make(map[string]uint, len(s.pieces))
+	for v, n := range s.pieces {
+		pieces[string(v)] = n
+	}
+	return StatisticsState{
+		Pieces:      pieces,
+		Placed:      s.placed,
+		Lines:       s.lines,
+		TetrisLines: s.tetrisLines,
+		Clears:      s.Breakdown(),
+		Inputs:      s.Inputs(),
+	}
+}
+
+// RestoreStatistics creates statistics in the given state.
+func RestoreStatistics(state StatisticsState) (*Statistics, error) {
+	s := NewStatistics()
+	for v, n := range state.Pieces {
+		if len(v) != 1 {
+			return nil, fmt.Errorf("invalid tetrimino value %q", v)
+		}
+		s.pieces[v[0]] = n
+	}
+	s.placed = state.Placed
+	s.lines = state.Lines
+	s.tetrisLines = state.TetrisLines
+	for _, c := range state.Clears {
+		if _, ok := s.clears[c.Kind]; !ok {
+			return nil, fmt.Errorf("invalid clear kind %d", c.Kind)
+		}
+		count := c
+		s.clears[c.Kind] = &count
+	}
+	maps.Copy(s.inputs, state.Inputs)
+	return s, nil
+}
+
+// ProcessLock records a tetrimino with the given value locking, the action it made and the points it was awarded.
+func (s *Statistics) ProcessLock(value byte, act action, points uint) {
+	s.pieces[value]++
+	s.placed++
+
+	lines := linesCleared(act)
+	s.lines += lines
+	if act == actionTetris {
+		s.tetrisLines += lines
+	}
+
+	if kind, ok := clearKind(act); ok {
+		s.clears[kind].Count++
+		s.clears[kind].Points += points
+	}
+}
+
+// ProcessInput records a move made by the player.
+func (s *Statistics) ProcessInput(move Move) {
+	s.inputs[move]++
+}
+
+// Inputs returns the number of times each move was made.
+func (s *Statistics) Inputs() map[Move]uint {
+	return maps.Clone(s.inputs)
+}
+
+// Pieces returns the number of tetriminos placed.
+func (s *Statistics) Pieces() uint {
+	return s.placed
+}
+
+// Lines returns the number of lines cleared.
+func (s *Statistics) Lines() uint {
+	return s.lines
+}
+
+// PieceCount returns the number of tetriminos with the given value placed.
+func (s *Statistics) PieceCount(value byte) uint {
+	return s.pieces[value]
+}
+
+// Tetrises returns the number of tetrises cleared.
+func (s *Statistics) Tetrises() uint {
+	return s.clears[ClearTetris].Count
+}
+
+// TetrisRate returns the fraction of cleared lines that were cleared by tetrises, from 0 to 1.
+func (s *Statistics) TetrisRate() float64 {
+	if s.lines == 0 {
+		return 0
+	}
+	return float64(s.tetrisLines) / float64(s.lines)
+}
+
+// Breakdown returns the number of clears of each kind and the points they were awarded, in the order of ClearKinds.
+func (s *Statistics) Breakdown() []ClearCount {
+	breakdown := make([]ClearCount, len(ClearKinds))
+	for i, kind := range ClearKinds {
+		breakdown[i] = *s.clears[kind]
+	}
+	return breakdown
+}
+
+// linesCleared returns the number of lines removed from the matrix by the action.
+func linesCleared(act action) uint {
+	switch act {
+	case actionSingle, actionMiniTSpinSingle, actionTSpinSingle:
+		return 1
+	case actionDouble, actionTSpinDouble:
+		return 2
+	case actionTriple, actionTSpinTriple:
+		return 3
+	case actionTetris:
+		return 4
+	}
+	return 0
+}
+
+// clearKind returns the kind of clear the action is counted as, or false if it is not counted.
+func clearKind(act action) (ClearKind, bool) {
+	switch act {
+	case actionSingle:
+		return ClearSingle, true
+	case actionDouble:
+		return ClearDouble, true
+	case actionTriple:
+		return ClearTriple, true
+	case actionTetris:
+		return ClearTetris, true
+	case actionMiniTSpin, actionMiniTSpinSingle, actionTSpin, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple:
+		return ClearTSpin, true
+	}
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package tetris
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatistics_ProcessLock(t *testing.T) {
+	type lock struct {
+		value  byte
+		act    action
+		points uint
+	}
+
+	tt := []struct {
+		name               string
+		locks              []lock
+		expectedPieces     uint
+		expectedLines      uint
+		expectedTetrises   uint
+		expectedTetrisRate float64
+		expectedCounts     map[byte]uint
+	}{
+		{
+			name:           "none",
+			expectedCounts: map[byte]uint{'I': 0},
+		},
+		{
+			name:               "no lines",
+			locks:              []lock{{'T', actionNone, 0}, {'T', actionTSpin, 0}, {'O', actionNone, 0}},
+			expectedPieces:     3,
+			expectedTetrisRate: 0,
+			expectedCounts:     map[byte]uint{'T': 2, 'O': 1, 'I': 0},
+		},
+		{
+			name:               "tetris rate",
+			locks:              []lock{{'L', actionDouble, 0}, {'J', actionTSpinDouble, 0}, {'I', actionTetris, 0}},
+			expectedPieces:     3,
+			expectedLines:      8,
+			expectedTetrises:   1,
+			expectedTetrisRate: 0.5,
+			expectedCounts:     map[byte]uint{'L': 1, 'J': 1, 'I': 1},
+		},
+		{
+			name:               "only tetrises",
+			locks:              []lock{{'I', actionTetris, 0}, {'I', actionTetris, 0}},
+			expectedPieces:     2,
+			expectedLines:      8,
+			expectedTetrises:   2,
+			expectedTetrisRate: 1,
+			expectedCounts:     map[byte]uint{'I': 2},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewStatistics()
+			for _, l := range tc.locks {
+				s.ProcessLock(l.value, l.act, l.points)
+			}
+
+			if s.Pieces() != tc.expectedPieces {
+				t.Errorf("Pieces: want %d, got %d", tc.expectedPieces, s.Pieces())
+			}
+			if s.Lines() != tc.expectedLines {
+				t.Errorf("Lines: want %d, got %d", tc.expectedLines, s.Lines())
+			}
+			if s.Tetrises() != tc.expectedTetrises {
+				t.Errorf("Tetrises: want %d, got %d", tc.expectedTetrises, s.Tetrises())
+			}
+			if s.TetrisRate() != tc.expectedTetrisRate {
+				t.Errorf("TetrisRate: want %v, got %v", tc.expectedTetrisRate, s.TetrisRate())
+			}
+			for value, count := range tc.expectedCounts {
+				if s.PieceCount(value) != count {
+					t.Errorf("PieceCount(%c): want %d, got %d", value, count, s.PieceCount(value))
+				}
+			}
+		})
+	}
+}
+
+func TestStatistics_Inputs(t *testing.T) {
+	s := NewStatistics()
+	for _, move := range []Move{MoveLeft, MoveLeft, MoveClockwise, MoveHardDrop, MoveRight, MoveHardDrop} {
+		s.ProcessInput(move)
+	}
+
+	expected := map[Move]uint{MoveLeft: 2, MoveRight: 1, MoveClockwise: 1, MoveHardDrop: 2}
+	inputs := s.Inputs()
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("want %v, got %v", expected, inputs)
+	}
+
+	// The counts returned are a copy, so changing them does not affect the statistics.
+	inputs[MoveHold] = 10
+	if n := s.Inputs()[MoveHold]; n != 0 {
+		t.Errorf("After changing the copy: want 0 holds, got %d", n)
+	}
+}
+
+func TestStatistics_Breakdown(t *testing.T) {
+	s := NewStatistics()
+	s.ProcessLock('I', actionSingle, 100)
+	s.ProcessLock('I', actionTetris, 800)
+	s.ProcessLock('T', actionTSpin, 400)
+	s.ProcessLock('T', actionTSpinDouble, 1200)
+	s.ProcessLock('O', actionNone, 0)
+
+	expected := []ClearCount{
+		{Kind: ClearSingle, Count: 1, Points: 100},
+		{Kind: ClearDouble},
+		{Kind: ClearTriple},
+		{Kind: ClearTetris, Count: 1, Points: 800},
+		{Kind: ClearTSpin, Count: 2, Points: 1600},
+	}
+	breakdown := s.Breakdown()
+	if !reflect.DeepEqual(breakdown, expected) {
+		t.Errorf("want %v, got %v", expected, breakdown)
+	}
+}
+
+func TestRestoreStatistics(t *testing.T) {
+	s := NewStatistics()
+	s.ProcessLock('I', actionTetris, 800)
+	s.ProcessLock('T', actionTSpinDouble, 1200)
+	s.ProcessLock('O', actionNone, 0)
+	s.ProcessInput(MoveLeft)
+	s.ProcessInput(MoveHardDrop)
+
+	restored, err := RestoreStatistics(s.State())
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	if !reflect.DeepEqual(restored, s) {
+		t.Errorf("Statistics: want %+v, got %+v", s, restored)
+	}
+
+	_, err = RestoreStatistics(StatisticsState{Pieces: map[string]uint{"IJ": 1}})
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -8,12 +8,15 @@ type Coordinate struct {
 	X, Y int
 }
 
+// RotationCoords are how far the top left cell of each tetrimino moves as it is rotated clockwise into each rotation
+// state, turning about the centre of the box it rotates in as in SRS. Rotating counter-clockwise out of a state moves
+// it back by the same amount.
 var RotationCoords = map[byte][]Coordinate{
 	'I': {
-		{X: -1, Y: -1},
-		{X: 2, Y: 1},
-		{X: -2, Y: -2},
-		{X: 1, Y: 2},
+		{X: -1, Y: 1},
+		{X: 2, Y: -1},
+		{X: -2, Y: 2},
+		{X: 1, Y: -2},
 	},
 	'O': {
 		{X: 0, Y: 0},
@@ -24,8 +27,8 @@ var RotationCoords = map[byte][]Coordinate{
 	'6': { // All tetriminos with 6 cells (T, S, Z, J, L)
 		{X: 0, Y: 0},
 		{X: 1, Y: 0},
-		{X: -1, Y: -1},
-		{X: 0, Y: 1},
+		{X: -1, Y: 1},
+		{X: 0, Y: -1},
 	},
 }
 
@@ -106,97 +109,130 @@ type Tetrimino struct {
 	Pos             Coordinate // the top left cell of the tetrimino
 	CurrentRotation int
 	RotationCoords  []Coordinate
+	Scale           int // cells each mino spans across and down, so that it moves in steps of that many (0 for 1)
 }
 
-// MoveDown moves the tetrimino down one row.
-// If the tetrimino cannot move down, it will be added to the matrix and a new tetrimino will be returned.
-func (t *Tetrimino) MoveDown(matrix *Matrix) error {
-	err := matrix.RemoveTetrimino(t)
-	if err != nil {
-		return fmt.Errorf("failed to remove cells: %w", err)
-	}
-	t.Pos.Y++
-	err = matrix.AddTetrimino(t)
-	if err != nil {
-		return fmt.Errorf("failed to add cells: %w", err)
-	}
-	return nil
+// MoveDown moves the tetrimino down one row, and reports whether it moved.
+// If the tetrimino cannot move down, it will not move.
+// An error is only returned if the tetrimino is not in the matrix where it is expected to be.
+func (t *Tetrimino) MoveDown(matrix *Matrix) (bool, error) {
+	return t.moveBy(matrix, 0, 1)
 }
 
-// MoveLeft moves the tetrimino left one column.
+// MoveLeft moves the tetrimino left one column, and reports whether it moved.
 // If the tetrimino cannot move left, it will not move.
-func (t *Tetrimino) MoveLeft(matrix *Matrix) error {
-	if !t.canMoveLeft(*matrix) {
-		return nil
-	}
-	err := matrix.RemoveTetrimino(t)
-	if err != nil {
-		return fmt.Errorf("failed to remove cells: %w", err)
-	}
-	t.Pos.X--
-	err = matrix.AddTetrimino(t)
-	if err != nil {
-		return fmt.Errorf("failed to add cells: %w", err)
-	}
-	return nil
+func (t *Tetrimino) MoveLeft(matrix *Matrix) (bool, error) {
+	return t.moveBy(matrix, -1, 0)
 }
 
-// MoveRight moves the tetrimino right one column.
+// MoveRight moves the tetrimino right one column, and reports whether it moved.
 // If the tetrimino cannot move right, it will not move.
-func (t *Tetrimino) MoveRight(matrix *Matrix) error {
-	if !t.canMoveRight(*matrix) {
-		return nil
+func (t *Tetrimino) MoveRight(matrix *Matrix) (bool, error) {
+	return t.moveBy(matrix, 1, 0)
+}
+
+// moveBy offsets the tetrimino by the given number of steps if it can be, and reports whether it moved.
+func (t *Tetrimino) moveBy(matrix *Matrix, dx, dy int) (bool, error) {
+	dx, dy = dx*t.step(), dy*t.step()
+	if !t.canMoveBy(*matrix, dx, dy) {
+		return false, nil
 	}
 	err := matrix.RemoveTetrimino(t)
 	if err != nil {
-		return fmt.Errorf("failed to remove cells: %w", err)
+		return false, fmt.Errorf("failed to remove cells: %w", err)
 	}
-	t.Pos.X++
+	t.Pos.X += dx
+	t.Pos.Y += dy
 	err = matrix.AddTetrimino(t)
 	if err != nil {
-		return fmt.Errorf("failed to add cells: %w", err)
+		return false, fmt.Errorf("failed to add cells: %w", err)
 	}
-	return nil
+	return true, nil
 }
 
 func (t *Tetrimino) CanMoveDown(matrix Matrix) bool {
-	bottomRow := len(t.Cells) - 1
-	for col := range t.Cells[bottomRow] {
-		if t.Cells[bottomRow][col] {
-			if bottomRow+t.Pos.Y+1 >= len(matrix) {
-				return false
-			}
-			if !isCellEmpty(matrix[bottomRow+t.Pos.Y+1][col+t.Pos.X]) {
-				return false
-			}
+	return t.canMoveBy(matrix, 0, t.step())
+}
+
+// Drop moves the tetrimino straight down as far as it can go, and returns the number of rows it moved.
+func (t *Tetrimino) Drop(matrix *Matrix) (int, error) {
+	rows := 0
+	for {
+		moved, err := t.MoveDown(matrix)
+		if err != nil {
+			return rows, err
+		}
+		if !moved {
+			return rows, nil
 		}
+		rows++
 	}
-	return true
+}
+
+// DropPosition returns the position the tetrimino would land at if it was dropped straight down from its current position.
+// The tetrimino does not need to be in the matrix, but its current position must be a valid one.
+func (t *Tetrimino) DropPosition(matrix Matrix) Coordinate {
+	dy := 0
+	for t.canMoveBy(matrix, 0, dy+t.step()) {
+		dy += t.step()
+	}
+	return Coordinate{X: t.Pos.X, Y: t.Pos.Y + dy}
 }
 
 func (t *Tetrimino) canMoveLeft(matrix Matrix) bool {
-	leftCol := 0
-	for row := range t.Cells {
-		if t.Cells[row][leftCol] {
-			if leftCol+t.Pos.X-1 < 0 {
-				return false
-			}
-			if !isCellEmpty(matrix[row+t.Pos.Y][leftCol+t.Pos.X-1]) {
-				return false
-			}
+	return t.canMoveBy(matrix, -t.step(), 0)
+}
+
+func (t *Tetrimino) canMoveRight(matrix Matrix) bool {
+	return t.canMoveBy(matrix, t.step(), 0)
+}
+
+// step returns the number of cells the tetrimino moves at a time, which is the number each of its minos spans.
+func (t *Tetrimino) step() int {
+	return max(t.Scale, 1)
+}
+
+// Scaled returns the tetrimino with each mino spanning scale cells across and down, as in big mode, at its starting
+// position in a matrix scale times the default width.
+func (t Tetrimino) Scaled(scale int) Tetrimino {
+	if scale <= 1 {
+		return t
+	}
+	cells := make([][]bool, len(t.Cells)*scale)
+	for row := range cells {
+		cells[row] = make([]bool, len(t.Cells[row/scale])*scale)
+		for col := range cells[row] {
+			cells[row][col] = t.Cells[row/scale][col/scale]
 		}
 	}
-	return true
+	coords := make([]Coordinate, len(t.RotationCoords))
+	for i, c := range t.RotationCoords {
+		coords[i] = Coordinate{X: c.X * scale, Y: c.Y * scale}
+	}
+	t.Cells, t.RotationCoords = cells, coords
+	t.Pos = Coordinate{X: t.Pos.X * scale, Y: t.Pos.Y * scale}
+	t.Scale = scale
+	return t
 }
 
-func (t *Tetrimino) canMoveRight(matrix Matrix) bool {
-	rightCol := len(t.Cells[0]) - 1
+// canMoveBy reports whether every cell of the tetrimino can be offset by the given amount.
+// Destination cells that are occupied by the tetrimino itself are ignored, since they will be vacated by the move.
+func (t *Tetrimino) canMoveBy(matrix Matrix, dx, dy int) bool {
 	for row := range t.Cells {
-		if t.Cells[row][rightCol] {
-			if rightCol+t.Pos.X+1 >= len(matrix[0]) {
+		for col := range t.Cells[row] {
+			if !t.Cells[row][col] {
+				continue
+			}
+			if t.isOwnCell(row+dy, col+dx) {
+				continue
+			}
+			if isOutOfBoundsHorizontally(t.Pos.X+dx, col, matrix) {
+				return false
+			}
+			if isOutOfBoundsVertically(t.Pos.Y+dy, row, matrix) {
 				return false
 			}
-			if !isCellEmpty(matrix[row+t.Pos.Y][rightCol+t.Pos.X+1]) {
+			if !isCellEmpty(matrix[t.Pos.Y+dy+row][t.Pos.X+dx+col]) {
 				return false
 			}
 		}
@@ -204,9 +240,27 @@ func (t *Tetrimino) canMoveRight(matrix Matrix) bool {
 	return true
 }
 
-func (t *Tetrimino) Rotate(matrix *Matrix, clockwise bool) error {
+// isOwnCell reports whether the given row and column (relative to the tetrimino position) is a filled cell of the tetrimino.
+func (t *Tetrimino) isOwnCell(row, col int) bool {
+	if row < 0 || row >= len(t.Cells) || col < 0 || col >= len(t.Cells[row]) {
+		return false
+	}
+	return t.Cells[row][col]
+}
+
+// Rotate rotates the tetrimino clockwise or counter-clockwise, and reports whether it rotated.
+// If the rotated tetrimino would not fit, even once kicked, it will not rotate.
+func (t *Tetrimino) Rotate(matrix *Matrix, clockwise bool) (bool, error) {
+	kick, err := t.RotateWithKicks(matrix, clockwise)
+	return kick >= 0, err
+}
+
+// RotateWithKicks rotates the tetrimino clockwise or counter-clockwise as in SRS, turning it about the centre of the
+// box it rotates in and then moving it by the first of the rotation's kicks (see Kicks) at which it fits. It returns
+// the index of the kick used, or -1 if it fit at none of them and so did not rotate.
+func (t *Tetrimino) RotateWithKicks(matrix *Matrix, clockwise bool) (int, error) {
 	if t.Value == 'O' {
-		return nil
+		return -1, nil
 	}
 
 	var rotated *Tetrimino
@@ -217,23 +271,31 @@ func (t *Tetrimino) Rotate(matrix *Matrix, clockwise bool) error {
 		rotated, err = t.rotateCounterClockwise()
 	}
 	if err != nil {
-		return fmt.Errorf("failed to rotate tetrimino: %w", err)
+		return -1, fmt.Errorf("failed to rotate tetrimino: %w", err)
 	}
 
 	err = matrix.RemoveTetrimino(t)
 	if err != nil {
-		return fmt.Errorf("failed to remove cells: %w", err)
+		return -1, fmt.Errorf("failed to remove cells: %w", err)
 	}
 
-	if rotated.canRotate(matrix) {
-		t.Cells = rotated.Cells
+	used := -1
+	for i, kick := range Kicks(t.Value, t.CurrentRotation, rotated.CurrentRotation) {
+		placed := *rotated
+		placed.Pos.X += kick.X * t.step()
+		placed.Pos.Y += kick.Y * t.step()
+		if placed.canRotate(*matrix) {
+			t.Cells, t.Pos, t.CurrentRotation = placed.Cells, placed.Pos, placed.CurrentRotation
+			used = i
+			break
+		}
 	}
 
 	err = matrix.AddTetrimino(t)
 	if err != nil {
-		return fmt.Errorf("failed to add cells: %w", err)
+		return -1, fmt.Errorf("failed to add cells: %w", err)
 	}
-	return nil
+	return used, nil
 }
 
 func (t Tetrimino) rotateClockwise() (*Tetrimino, error) {
@@ -271,13 +333,15 @@ func (t Tetrimino) rotateCounterClockwise() (*Tetrimino, error) {
 	t.transpose()
 
 	var err error
+	from := t.CurrentRotation
 	t.CurrentRotation, err = positiveMod(t.CurrentRotation-1, len(t.RotationCoords))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positive mod: %w", err)
 	}
 
-	t.Pos.X -= t.RotationCoords[t.CurrentRotation].X
-	t.Pos.Y -= t.RotationCoords[t.CurrentRotation].Y
+	// Turning back out of a state undoes the move made turning clockwise into it.
+	t.Pos.X -= t.RotationCoords[from].X
+	t.Pos.Y -= t.RotationCoords[from].Y
 
 	return &t, nil
 }
@@ -297,7 +361,7 @@ func (t *Tetrimino) transpose() {
 	t.Cells = result
 }
 
-func (t *Tetrimino) canRotate(matrix *Matrix) bool {
+func (t *Tetrimino) canRotate(matrix Matrix) bool {
 	for cellRow := range t.Cells {
 		for cellCol := range t.Cells[cellRow] {
 			if t.Cells[cellRow][cellCol] {
@@ -316,11 +380,11 @@ func (t *Tetrimino) canRotate(matrix *Matrix) bool {
 	return true
 }
 
-func isOutOfBoundsHorizontally(tetPosX, cellCol int, matrix *Matrix) bool {
+func isOutOfBoundsHorizontally(tetPosX, cellCol int, matrix Matrix) bool {
 	tetPosX += cellCol
 	return tetPosX < 0 || tetPosX >= len(matrix[0])
 }
-func isOutOfBoundsVertically(tetPosY, cellRow int, matrix *Matrix) bool {
+func isOutOfBoundsVertically(tetPosY, cellRow int, matrix Matrix) bool {
 	tetPosY += cellRow
 	return tetPosY < 0 || tetPosY >= len(matrix)
 }
@@ -354,7 +418,7 @@ func (t *Tetrimino) Copy() *Tetrimino {
 	if t.Cells == nil {
 		cells = nil
 	} else {
-		cells := make([][]bool, len(t.Cells))
+		cells = make([][]bool, len(t.Cells))
 		for i := range t.Cells {
 			cells[i] = make([]bool, len(t.Cells[i]))
 			copy(cells[i], t.Cells[i])
@@ -365,7 +429,7 @@ func (t *Tetrimino) Copy() *Tetrimino {
 	if t.RotationCoords == nil {
 		rotationCoords = nil
 	} else {
-		rotationCoords := make([]Coordinate, len(t.RotationCoords))
+		rotationCoords = make([]Coordinate, len(t.RotationCoords))
 		copy(rotationCoords, t.RotationCoords)
 	}
 
@@ -375,5 +439,6 @@ func (t *Tetrimino) Copy() *Tetrimino {
 		Pos:             t.Pos,
 		CurrentRotation: t.CurrentRotation,
 		RotationCoords:  rotationCoords,
+		Scale:           t.Scale,
 	}
 }
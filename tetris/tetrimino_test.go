@@ -11,14 +11,15 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 		startingPlayfield Matrix
 		startingTet       Tetrimino
 		expectedPlayfield Matrix
+		expectsMoved      bool
 		expectsErr        bool
 	}{
 		{
 			name: "can, empty matrix",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{0, 'T', 'T', 'T'},
 				{0, 0, 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -27,18 +28,19 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				},
 				Pos: Coordinate{X: 1, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{'T', 'T', 'T'},
 				{0, 'T', 0},
-			},
-			expectsErr: false,
+			}),
+			expectsMoved: true,
+			expectsErr:   false,
 		},
 		{
 			name: "can, perfect fit",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'#', 0, 'T', 'T', 'T'},
 				{'#', '#', 0, 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -47,18 +49,19 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				},
 				Pos: Coordinate{X: 2, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{'#', 'T', 'T', 'T'},
 				{'#', '#', 'T', 0},
-			},
-			expectsErr: false,
+			}),
+			expectsMoved: true,
+			expectsErr:   false,
 		},
 		{
 			name: "can, ghost cells",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'#', 'G', 'T', 'T', 'T'},
 				{'#', '#', 'G', 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -67,18 +70,19 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				},
 				Pos: Coordinate{X: 2, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{'#', 'T', 'T', 'T'},
 				{'#', '#', 'T', 0},
-			},
-			expectsErr: false,
+			}),
+			expectsMoved: true,
+			expectsErr:   false,
 		},
 		{
 			name: "cannot, blocking tetrimino",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'#', 'T', 'T', 'T'},
 				{'#', 0, 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -87,18 +91,18 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				},
 				Pos: Coordinate{X: 1, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{'#', 'T', 'T', 'T'},
 				{'#', 0, 'T', 0},
-			},
+			}),
 			expectsErr: false,
 		},
 		{
 			name: "cannot, end of matrix",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'T', 'T', 'T'},
 				{0, 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -107,18 +111,18 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				},
 				Pos: Coordinate{X: 0, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{'T', 'T', 'T'},
 				{0, 'T', 0},
-			},
+			}),
 			expectsErr: false,
 		},
 		{
 			name: "error, wrong value",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{0, 'X', 'X', 'X'},
 				{0, 0, 'X', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -127,14 +131,14 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				},
 				Pos: Coordinate{X: 1, Y: 0},
 			},
-			expectedPlayfield: Matrix{},
+			expectedPlayfield: NewDefaultMatrix(),
 			expectsErr:        true,
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			err := tc.startingTet.MoveLeft(&tc.startingPlayfield)
+			moved, err := tc.startingTet.MoveLeft(&tc.startingPlayfield)
 
 			if tc.expectsErr && err == nil {
 				t.Errorf("expected error, got nil")
@@ -142,7 +146,10 @@ func TestTetrimino_MoveLeft(t *testing.T) {
 				t.Errorf("expected nil, got error")
 			}
 
-			if err == nil && tc.startingPlayfield != tc.expectedPlayfield {
+			if err == nil && moved != tc.expectsMoved {
+				t.Errorf("expected moved %t, got %t", tc.expectsMoved, moved)
+			}
+			if err == nil && !tc.startingPlayfield.Equal(tc.expectedPlayfield) {
 				t.Errorf("expected matrix %v, got %v", tc.expectedPlayfield, tc.startingPlayfield)
 			}
 		})
@@ -155,14 +162,15 @@ func TestTetrimino_MoveRight(t *testing.T) {
 		startingPlayfield Matrix
 		startingTet       Tetrimino
 		expectedPlayfield Matrix
+		expectsMoved      bool
 		expectsErr        bool
 	}{
 		{
 			name: "can, empty matrix",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'T', 'T', 'T'},
 				{0, 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -171,18 +179,19 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				},
 				Pos: Coordinate{X: 0, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{0, 'T', 'T', 'T'},
 				{0, 0, 'T', 0},
-			},
-			expectsErr: false,
+			}),
+			expectsMoved: true,
+			expectsErr:   false,
 		},
 		{
 			name: "can, perfect fit",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'T', 'T', 'T', 0, '#'},
 				{0, 'T', 0, '#'},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -191,18 +200,19 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				},
 				Pos: Coordinate{X: 0, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{0, 'T', 'T', 'T', '#'},
 				{0, 0, 'T', '#'},
-			},
-			expectsErr: false,
+			}),
+			expectsMoved: true,
+			expectsErr:   false,
 		},
 		{
 			name: "can, ghost cells",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'T', 'T', 'T', 'G', '#'},
 				{0, 'T', 'G', '#'},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -211,18 +221,19 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				},
 				Pos: Coordinate{X: 0, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{0, 'T', 'T', 'T', '#'},
 				{0, 0, 'T', '#'},
-			},
-			expectsErr: false,
+			}),
+			expectsMoved: true,
+			expectsErr:   false,
 		},
 		{
 			name: "cannot, blocking tetrimino",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'T', 'T', 'T', '#'},
 				{0, 'T', '#'},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -231,18 +242,18 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				},
 				Pos: Coordinate{X: 0, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{'T', 'T', 'T', '#'},
 				{0, 'T', '#'},
-			},
+			}),
 			expectsErr: false,
 		},
 		{
 			name: "cannot, end of matrix",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{0, 0, 0, 0, 0, 0, 0, 'T', 'T', 'T'},
 				{0, 0, 0, 0, 0, 0, 0, 0, 'T', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -251,18 +262,18 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				},
 				Pos: Coordinate{X: 7, Y: 0},
 			},
-			expectedPlayfield: Matrix{
+			expectedPlayfield: padded(Matrix{
 				{0, 0, 0, 0, 0, 0, 0, 'T', 'T', 'T'},
 				{0, 0, 0, 0, 0, 0, 0, 0, 'T', 0},
-			},
+			}),
 			expectsErr: false,
 		},
 		{
 			name: "error, wrong value",
-			startingPlayfield: Matrix{
+			startingPlayfield: padded(Matrix{
 				{'X', 'X', 'X'},
 				{0, 'X', 0},
-			},
+			}),
 			startingTet: Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -271,14 +282,14 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				},
 				Pos: Coordinate{X: 0, Y: 0},
 			},
-			expectedPlayfield: Matrix{},
+			expectedPlayfield: NewDefaultMatrix(),
 			expectsErr:        true,
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			err := tc.startingTet.MoveRight(&tc.startingPlayfield)
+			moved, err := tc.startingTet.MoveRight(&tc.startingPlayfield)
 
 			if tc.expectsErr && err == nil {
 				t.Errorf("expected error, got nil")
@@ -286,7 +297,323 @@ func TestTetrimino_MoveRight(t *testing.T) {
 				t.Errorf("expected nil, got error")
 			}
 
-			if err == nil && tc.startingPlayfield != tc.expectedPlayfield {
+			if err == nil && moved != tc.expectsMoved {
+				t.Errorf("expected moved %t, got %t", tc.expectsMoved, moved)
+			}
+			if err == nil && !tc.startingPlayfield.Equal(tc.expectedPlayfield) {
+				t.Errorf("expected matrix %v, got %v", tc.expectedPlayfield, tc.startingPlayfield)
+			}
+		})
+	}
+}
+
+func TestTetrimino_CanMoveDown(t *testing.T) {
+	tt := []struct {
+		name     string
+		matrix   Matrix
+		tet      Tetrimino
+		expected bool
+	}{
+		{
+			name: "can, empty matrix",
+			matrix: padded(Matrix{
+				{0, 'S', 'S'},
+				{'S', 'S', 0},
+			}),
+			tet: Tetrimino{
+				Value: 'S',
+				Cells: [][]bool{
+					{false, true, true},
+					{true, true, false},
+				},
+				Pos: Coordinate{X: 0, Y: 0},
+			},
+			expected: true,
+		},
+		{
+			name: "cannot, overhang blocked",
+			matrix: padded(Matrix{
+				{0, 'S', 'S'},
+				{'S', 'S', '#'},
+			}),
+			tet: Tetrimino{
+				Value: 'S',
+				Cells: [][]bool{
+					{false, true, true},
+					{true, true, false},
+				},
+				Pos: Coordinate{X: 0, Y: 0},
+			},
+			expected: false,
+		},
+		{
+			name: "cannot, bottom of matrix",
+			matrix: func() Matrix {
+				m := NewDefaultMatrix()
+				m[len(m)-1] = []byte{'I', 'I', 'I', 'I'}
+				return m
+			}(),
+			tet: Tetrimino{
+				Value: 'I',
+				Cells: [][]bool{
+					{true, true, true, true},
+				},
+				Pos: Coordinate{X: 0, Y: len(NewDefaultMatrix()) - 1},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.tet.CanMoveDown(tc.matrix); result != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestTetrimino_Drop(t *testing.T) {
+	tt := []struct {
+		name           string
+		matrix         Matrix
+		tet            Tetrimino
+		expectedMatrix Matrix
+		expectedRows   int
+	}{
+		{
+			name: "lands on stack",
+			matrix: padded(Matrix{
+				{'T', 'T', 'T'},
+				{0, 'T', 0},
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 0, '#'},
+			}),
+			tet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{true, true, true},
+					{false, true, false},
+				},
+			},
+			expectedMatrix: padded(Matrix{
+				3: {'T', 'T', 'T'},
+				4: {0, 'T', '#'},
+			}),
+			expectedRows: 3,
+		},
+		{
+			name: "already landed",
+			matrix: padded(Matrix{
+				{'T', 'T', 'T'},
+				{0, 'T', 0},
+				{0, '#', 0},
+			}),
+			tet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{true, true, true},
+					{false, true, false},
+				},
+			},
+			expectedMatrix: padded(Matrix{
+				{'T', 'T', 'T'},
+				{0, 'T', 0},
+				{0, '#', 0},
+			}),
+			expectedRows: 0,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := tc.tet.Drop(&tc.matrix)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if rows != tc.expectedRows {
+				t.Errorf("expected rows %d, got %d", tc.expectedRows, rows)
+			}
+			if !tc.matrix.Equal(tc.expectedMatrix) {
+				t.Errorf("expected matrix %v, got %v", tc.expectedMatrix, tc.matrix)
+			}
+		})
+	}
+}
+
+func TestTetrimino_DropPosition(t *testing.T) {
+	tt := []struct {
+		name     string
+		matrix   Matrix
+		tet      Tetrimino
+		expected Coordinate
+	}{
+		{
+			name:   "empty matrix",
+			matrix: NewDefaultMatrix(),
+			tet: Tetrimino{
+				Value: 'O',
+				Cells: [][]bool{
+					{true, true},
+					{true, true},
+				},
+				Pos: Coordinate{X: 4, Y: 0},
+			},
+			expected: Coordinate{X: 4, Y: len(NewDefaultMatrix()) - 2},
+		},
+		{
+			name: "lands on stack",
+			matrix: padded(Matrix{
+				{'T', 'T', 'T'},
+				{0, 'T', 0},
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 0, '#'},
+			}),
+			tet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{true, true, true},
+					{false, true, false},
+				},
+				Pos: Coordinate{X: 0, Y: 0},
+			},
+			expected: Coordinate{X: 0, Y: 3},
+		},
+		{
+			name: "hypothetical tetrimino not in matrix",
+			matrix: padded(Matrix{
+				{0, 0, 0},
+				{0, 0, 0},
+				{'#', 0, '#'},
+			}),
+			tet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{true, true, true},
+					{false, true, false},
+				},
+				Pos: Coordinate{X: 0, Y: 0},
+			},
+			expected: Coordinate{X: 0, Y: 1},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.tet.DropPosition(tc.matrix); result != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestTetrimino_Rotate(t *testing.T) {
+	tt := []struct {
+		name              string
+		startingPlayfield Matrix
+		startingTet       Tetrimino
+		expectedPlayfield Matrix
+		expectsMoved      bool
+	}{
+		{
+			name: "can, empty matrix",
+			startingPlayfield: padded(Matrix{
+				{0, 'T', 0},
+				{'T', 'T', 'T'},
+			}),
+			startingTet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{false, true, false},
+					{true, true, true},
+				},
+				RotationCoords: RotationCoords['6'],
+			},
+			expectedPlayfield: padded(Matrix{
+				{0, 'T', 0},
+				{0, 'T', 'T'},
+				{0, 'T', 0},
+			}),
+			expectsMoved: true,
+		},
+		{
+			name: "can, kicked left",
+			startingPlayfield: padded(Matrix{
+				{0, 'T', 0},
+				{'T', 'T', 'T'},
+				{0, 'X', 0},
+			}),
+			startingTet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{false, true, false},
+					{true, true, true},
+				},
+				RotationCoords: RotationCoords['6'],
+			},
+			expectedPlayfield: padded(Matrix{
+				{'T', 0, 0},
+				{'T', 'T', 0},
+				{'T', 'X', 0},
+			}),
+			expectsMoved: true,
+		},
+		{
+			name: "cannot, blocking cell",
+			startingPlayfield: padded(Matrix{
+				{0, 'T', 0},
+				{'T', 'T', 'T'},
+				{'X', 'X', 0},
+			}),
+			startingTet: Tetrimino{
+				Value: 'T',
+				Cells: [][]bool{
+					{false, true, false},
+					{true, true, true},
+				},
+				RotationCoords: RotationCoords['6'],
+			},
+			expectedPlayfield: padded(Matrix{
+				{0, 'T', 0},
+				{'T', 'T', 'T'},
+				{'X', 'X', 0},
+			}),
+			expectsMoved: false,
+		},
+		{
+			name: "cannot, O",
+			startingPlayfield: padded(Matrix{
+				{'O', 'O'},
+				{'O', 'O'},
+			}),
+			startingTet: Tetrimino{
+				Value: 'O',
+				Cells: [][]bool{
+					{true, true},
+					{true, true},
+				},
+				RotationCoords: RotationCoords['O'],
+			},
+			expectedPlayfield: padded(Matrix{
+				{'O', 'O'},
+				{'O', 'O'},
+			}),
+			expectsMoved: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			moved, err := tc.startingTet.Rotate(&tc.startingPlayfield, true)
+			if err != nil {
+				t.Fatalf("expected nil, got error: %v", err)
+			}
+			if moved != tc.expectsMoved {
+				t.Errorf("expected moved %t, got %t", tc.expectsMoved, moved)
+			}
+			if !tc.startingPlayfield.Equal(tc.expectedPlayfield) {
 				t.Errorf("expected matrix %v, got %v", tc.expectedPlayfield, tc.startingPlayfield)
 			}
 		})
@@ -313,6 +640,7 @@ func TestRotateClockwise(t *testing.T) {
 				Coordinate{X: startingPositions['6'].X, Y: startingPositions['6'].Y + 20},
 				0,
 				RotationCoords['6'],
+				0,
 			},
 			false,
 			[][]bool{
@@ -335,6 +663,7 @@ func TestRotateClockwise(t *testing.T) {
 				Coordinate{X: 0, Y: 0},
 				1,
 				RotationCoords['6'],
+				0,
 			},
 			true,
 			[][]bool{},
@@ -444,11 +773,11 @@ func TestTranspose(t *testing.T) {
 }
 
 func TestCanRotate(t *testing.T) {
-	matrix := &Matrix{}
+	matrix := NewDefaultMatrix()
 
 	tt := []struct {
 		name    string
-		matrix  *Matrix
+		matrix  Matrix
 		rotated *Tetrimino
 		expects bool
 	}{
@@ -472,11 +801,11 @@ func TestCanRotate(t *testing.T) {
 		},
 		{
 			"can rotate, perfect fit",
-			&Matrix{
+			padded(Matrix{
 				{0, 0, 0, 'X'},
 				{'X', 0, 'X'},
 				{'X', 'X', 'X'},
-			},
+			}),
 			&Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -494,9 +823,9 @@ func TestCanRotate(t *testing.T) {
 		},
 		{
 			"cannot rotate, blocking cell",
-			&Matrix{
+			padded(Matrix{
 				{'X'},
-			},
+			}),
 			&Tetrimino{
 				Value: 'T',
 				Cells: [][]bool{
@@ -598,7 +927,7 @@ func TestCanRotate(t *testing.T) {
 }
 
 func TestIsOutOfBoundsHorizontally(t *testing.T) {
-	matrix := &Matrix{}
+	matrix := NewDefaultMatrix()
 
 	tt := []struct {
 		name    string
@@ -632,7 +961,7 @@ func TestIsOutOfBoundsHorizontally(t *testing.T) {
 }
 
 func TestIsOutOfBoundsVertically(t *testing.T) {
-	matrix := &Matrix{}
+	matrix := NewDefaultMatrix()
 
 	tt := []struct {
 		name    string
@@ -808,3 +1137,52 @@ func TestIsCellEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestTetrimino_Scaled(t *testing.T) {
+	matrix := NewMatrix(DefaultWidth*2, DefaultHeight)
+	b, err := NewFixedBag(matrix, []byte{'T', 'I'})
+	if err != nil {
+		t.Fatalf("expected nil, got error: %v", err)
+	}
+	b.Scale(matrix, 2)
+
+	tet := b.Next()
+	wantCells := [][]bool{
+		{false, false, true, true, false, false},
+		{false, false, true, true, false, false},
+		{true, true, true, true, true, true},
+		{true, true, true, true, true, true},
+	}
+	if !reflect.DeepEqual(tet.Cells, wantCells) {
+		t.Errorf("Cells: want %v, got %v", wantCells, tet.Cells)
+	}
+	if want := (Coordinate{X: 6, Y: DefaultHeight - 4}); tet.Pos != want {
+		t.Errorf("Pos: want %v, got %v", want, tet.Pos)
+	}
+	err = matrix.AddTetrimino(tet)
+	if err != nil {
+		t.Fatalf("failed to add tetrimino: %v", err)
+	}
+
+	// Moves and rotations keep the minos aligned to the big grid, moving two cells at a time.
+	moved, err := tet.MoveLeft(&matrix)
+	if err != nil || !moved || tet.Pos.X != 4 {
+		t.Errorf("MoveLeft: want to move to column 4, got %d (moved %t, error %v)", tet.Pos.X, moved, err)
+	}
+	rotated, err := tet.Rotate(&matrix, true)
+	if err != nil || !rotated || tet.Pos.X%2 != 0 || tet.Pos.Y%2 != 0 {
+		t.Errorf("Rotate: want to rotate onto the big grid, got %v (rotated %t, error %v)", tet.Pos, rotated, err)
+	}
+	rows, err := tet.Drop(&matrix)
+	if err != nil || tet.Pos.Y+len(tet.Cells) != len(matrix) {
+		t.Errorf("Drop: want to land on the floor, got %v after %d steps (error %v)", tet.Pos, rows, err)
+	}
+	if tet.Copy().Scale != 2 {
+		t.Errorf("Copy: want the scale kept")
+	}
+
+	pos, err := b.SpawnPosition('I')
+	if err != nil || pos != (Coordinate{X: 6, Y: DefaultHeight - 2}) {
+		t.Errorf("SpawnPosition: want the big I centred, got %v (error %v)", pos, err)
+	}
+}
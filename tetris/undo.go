@@ -0,0 +1,70 @@
+package tetris
+
+import "time"
+
+// Snapshot is the state of a game as a tetrimino spawns, from which its placement can be undone.
+type Snapshot struct {
+	Matrix     Matrix    // the stack, without the current tetrimino
+	Current    Tetrimino // the tetrimino which has just spawned
+	Hold       HoldQueue // the held tetriminos
+	CanHold    bool
+	Bag        BagState
+	Scoring    ScoringState
+	Attack     AttackState
+	Statistics StatisticsState
+	Time       time.Duration // the time played when the tetrimino spawned
+}
+
+// Undo keeps snapshots of the game from before the most recent placements, so that they can be undone one at a time
+// in practice. Only the latest are kept, up to its limit, dropping the oldest as more are taken.
+type Undo struct {
+	snapshots []Snapshot
+	limit     int
+}
+
+// NewUndo creates an undo history which keeps up to limit snapshots.
+func NewUndo(limit int) *Undo {
+	return &Undo{limit: limit}
+}
+
+// Push keeps the snapshot taken before a placement, dropping the oldest if the limit has been reached.
+func (u *Undo) Push(s Snapshot) {
+	if u.limit <= 0 {
+		return
+	}
+	if len(u.snapshots) == u.limit {
+		u.snapshots = append(u.snapshots[:0], u.snapshots[1:]...)
+	}
+	u.snapshots = append(u.snapshots, s)
+}
+
+// Pop returns the snapshot taken before the latest placement and forgets it, or false if there is none left.
+func (u *Undo) Pop() (Snapshot, bool) {
+	if len(u.snapshots) == 0 {
+		return Snapshot{}, false
+	}
+	s := u.snapshots[len(u.snapshots)-1]
+	u.snapshots = u.snapshots[:len(u.snapshots)-1]
+	return s, true
+}
+
+// Rewind returns the snapshot of the game as it was at the given time played, which is the last taken at or before it,
+// and forgets it along with every snapshot taken since. If every snapshot was taken since, the oldest is returned. It
+// returns false if there is no snapshot left.
+func (u *Undo) Rewind(to time.Duration) (Snapshot, bool) {
+	if len(u.snapshots) == 0 {
+		return Snapshot{}, false
+	}
+	i := len(u.snapshots) - 1
+	for i > 0 && u.snapshots[i].Time > to {
+		i--
+	}
+	s := u.snapshots[i]
+	u.snapshots = u.snapshots[:i]
+	return s, true
+}
+
+// Len returns the number of placements which can be undone.
+func (u *Undo) Len() int {
+	return len(u.snapshots)
+}
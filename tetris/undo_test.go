@@ -0,0 +1,80 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUndo(t *testing.T) {
+	u := NewUndo(3)
+	if _, ok := u.Pop(); ok {
+		t.Fatalf("Empty: want no snapshot, got one")
+	}
+
+	for i := 1; i <= 5; i++ {
+		u.Push(Snapshot{Scoring: ScoringState{Total: uint(i)}})
+	}
+	if u.Len() != 3 {
+		t.Fatalf("Len: want 3, got %d", u.Len())
+	}
+
+	// The oldest snapshots are dropped once the limit is reached, and the latest are popped first.
+	for _, want := range []uint{5, 4, 3} {
+		s, ok := u.Pop()
+		if !ok {
+			t.Fatalf("want snapshot %d, got none", want)
+		}
+		if s.Scoring.Total != want {
+			t.Errorf("want snapshot %d, got %d", want, s.Scoring.Total)
+		}
+	}
+	if _, ok := u.Pop(); ok {
+		t.Errorf("Exhausted: want no snapshot, got one")
+	}
+}
+
+func TestUndo_ZeroLimit(t *testing.T) {
+	u := NewUndo(0)
+	u.Push(Snapshot{})
+	if u.Len() != 0 {
+		t.Errorf("want nothing kept, got %d", u.Len())
+	}
+}
+
+func TestUndo_Rewind(t *testing.T) {
+	tt := []struct {
+		name     string
+		to       time.Duration
+		want     uint
+		wantLeft int
+	}{
+		{"to a placement", 5 * time.Second, 3, 2},
+		{"between placements", 7 * time.Second, 4, 3},
+		{"before every placement", 0, 1, 0},
+		{"after every placement", time.Minute, 5, 4},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			u := NewUndo(10)
+			for i := 1; i <= 5; i++ {
+				u.Push(Snapshot{Scoring: ScoringState{Total: uint(i)}, Time: time.Duration(2*i-1) * time.Second})
+			}
+
+			s, ok := u.Rewind(tc.to)
+			if !ok {
+				t.Fatalf("want snapshot %d, got none", tc.want)
+			}
+			if s.Scoring.Total != tc.want {
+				t.Errorf("want snapshot %d, got %d", tc.want, s.Scoring.Total)
+			}
+			if u.Len() != tc.wantLeft {
+				t.Errorf("Len: want %d, got %d", tc.wantLeft, u.Len())
+			}
+		})
+	}
+
+	if _, ok := NewUndo(10).Rewind(0); ok {
+		t.Errorf("Empty: want no snapshot, got one")
+	}
+}